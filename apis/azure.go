@@ -27,7 +27,9 @@ import (
 	dnsv1alpha1 "github.com/crossplane-contrib/provider-azure/apis/dns/v1alpha1"
 	keyvaultv1alpha1 "github.com/crossplane-contrib/provider-azure/apis/keyvault/v1alpha1"
 	networkv1alpha3 "github.com/crossplane-contrib/provider-azure/apis/network/v1alpha3"
+	storagev1alpha1 "github.com/crossplane-contrib/provider-azure/apis/storage/v1alpha1"
 	storagev1alpha3 "github.com/crossplane-contrib/provider-azure/apis/storage/v1alpha3"
+	storagev1beta1 "github.com/crossplane-contrib/provider-azure/apis/storage/v1beta1"
 	azurev1alpha1 "github.com/crossplane-contrib/provider-azure/apis/v1alpha1"
 	azurev1alpha3 "github.com/crossplane-contrib/provider-azure/apis/v1alpha3"
 	azurev1beta1 "github.com/crossplane-contrib/provider-azure/apis/v1beta1"
@@ -45,7 +47,9 @@ func init() {
 		databasev1beta1.SchemeBuilder.AddToScheme,
 		keyvaultv1alpha1.SchemeBuilder.AddToScheme,
 		networkv1alpha3.SchemeBuilder.AddToScheme,
+		storagev1alpha1.SchemeBuilder.AddToScheme,
 		storagev1alpha3.SchemeBuilder.AddToScheme,
+		storagev1beta1.SchemeBuilder.AddToScheme,
 		dnsv1alpha1.SchemeBuilder.AddToScheme,
 	)
 }
@@ -20,6 +20,8 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+
+	apisv1alpha3 "github.com/crossplane-contrib/provider-azure/apis/v1alpha3"
 )
 
 const (
@@ -167,12 +169,21 @@ type RedisObservation struct {
 
 	// Name - Resource name.
 	Name string `json:"name,omitempty"`
+
+	// LastOperation represents the state of the last operation started by the
+	// controller.
+	LastOperation apisv1alpha3.AsyncOperation `json:"lastOperation,omitempty"`
 }
 
 // A RedisStatus represents the observed state of a Redis.
 type RedisStatus struct {
 	xpv1.ResourceStatus `json:",inline"`
 	AtProvider          RedisObservation `json:"atProvider,omitempty"`
+
+	// LastFailedRequest identifies the most recent request to Azure that
+	// failed.
+	// +optional
+	LastFailedRequest *apisv1alpha3.FailedRequest `json:"lastFailedRequest,omitempty"`
 }
 
 // +kubebuilder:object:root=true
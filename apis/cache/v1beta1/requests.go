@@ -0,0 +1,31 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	apisv1alpha3 "github.com/crossplane-contrib/provider-azure/apis/v1alpha3"
+)
+
+// GetLastFailedRequest of this Redis.
+func (mg *Redis) GetLastFailedRequest() *apisv1alpha3.FailedRequest {
+	return mg.Status.LastFailedRequest
+}
+
+// SetLastFailedRequest of this Redis.
+func (mg *Redis) SetLastFailedRequest(r *apisv1alpha3.FailedRequest) {
+	mg.Status.LastFailedRequest = r
+}
@@ -22,6 +22,7 @@ limitations under the License.
 package v1beta1
 
 import (
+	"github.com/crossplane-contrib/provider-azure/apis/v1alpha3"
 	"github.com/crossplane/crossplane-runtime/apis/common/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
@@ -93,6 +94,7 @@ func (in *RedisObservation) DeepCopyInto(out *RedisObservation) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	in.LastOperation.DeepCopyInto(&out.LastOperation)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RedisObservation.
@@ -204,6 +206,11 @@ func (in *RedisStatus) DeepCopyInto(out *RedisStatus) {
 	*out = *in
 	in.ResourceStatus.DeepCopyInto(&out.ResourceStatus)
 	in.AtProvider.DeepCopyInto(&out.AtProvider)
+	if in.LastFailedRequest != nil {
+		in, out := &in.LastFailedRequest, &out.LastFailedRequest
+		*out = new(v1alpha3.FailedRequest)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RedisStatus.
@@ -20,6 +20,8 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+
+	apisv1alpha3 "github.com/crossplane-contrib/provider-azure/apis/v1alpha3"
 )
 
 const (
@@ -81,6 +83,10 @@ type AKSClusterParameters struct {
 	// cluster.
 	// +optional
 	DisableRBAC bool `json:"disableRBAC,omitempty"`
+
+	// Tags - Resource tags.
+	// +optional
+	Tags map[string]string `json:"tags,omitempty"`
 }
 
 // An AKSClusterSpec defines the desired state of a AKSCluster.
@@ -89,6 +95,14 @@ type AKSClusterSpec struct {
 	AKSClusterParameters `json:",inline"`
 }
 
+// An AKSClusterObservation represents the observed state of an AKSCluster
+// that is not reported by Azure as part of the cluster itself.
+type AKSClusterObservation struct {
+	// LastOperation represents the state of the last operation started by the
+	// controller.
+	LastOperation apisv1alpha3.AsyncOperation `json:"lastOperation,omitempty"`
+}
+
 // An AKSClusterStatus represents the observed state of an AKSCluster.
 type AKSClusterStatus struct {
 	xpv1.ResourceStatus `json:",inline"`
@@ -102,6 +116,15 @@ type AKSClusterStatus struct {
 
 	// Endpoint is the endpoint where the cluster can be reached
 	Endpoint string `json:"endpoint,omitempty"`
+
+	// AtProvider reports the observed state of the AKSCluster that is not
+	// reported by Azure as part of the cluster itself.
+	AtProvider AKSClusterObservation `json:"atProvider,omitempty"`
+
+	// LastFailedRequest identifies the most recent request to Azure that
+	// failed.
+	// +optional
+	LastFailedRequest *apisv1alpha3.FailedRequest `json:"lastFailedRequest,omitempty"`
 }
 
 // +kubebuilder:object:root=true
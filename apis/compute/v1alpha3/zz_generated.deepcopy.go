@@ -22,6 +22,7 @@ limitations under the License.
 package v1alpha3
 
 import (
+	apisv1alpha3 "github.com/crossplane-contrib/provider-azure/apis/v1alpha3"
 	"github.com/crossplane/crossplane-runtime/apis/common/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
@@ -85,6 +86,22 @@ func (in *AKSClusterList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AKSClusterObservation) DeepCopyInto(out *AKSClusterObservation) {
+	*out = *in
+	in.LastOperation.DeepCopyInto(&out.LastOperation)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AKSClusterObservation.
+func (in *AKSClusterObservation) DeepCopy() *AKSClusterObservation {
+	if in == nil {
+		return nil
+	}
+	out := new(AKSClusterObservation)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AKSClusterParameters) DeepCopyInto(out *AKSClusterParameters) {
 	*out = *in
@@ -113,6 +130,13 @@ func (in *AKSClusterParameters) DeepCopyInto(out *AKSClusterParameters) {
 		*out = new(int)
 		**out = **in
 	}
+	if in.Tags != nil {
+		in, out := &in.Tags, &out.Tags
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AKSClusterParameters.
@@ -146,6 +170,12 @@ func (in *AKSClusterSpec) DeepCopy() *AKSClusterSpec {
 func (in *AKSClusterStatus) DeepCopyInto(out *AKSClusterStatus) {
 	*out = *in
 	in.ResourceStatus.DeepCopyInto(&out.ResourceStatus)
+	in.AtProvider.DeepCopyInto(&out.AtProvider)
+	if in.LastFailedRequest != nil {
+		in, out := &in.LastFailedRequest, &out.LastFailedRequest
+		*out = new(apisv1alpha3.FailedRequest)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AKSClusterStatus.
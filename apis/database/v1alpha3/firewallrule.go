@@ -20,6 +20,8 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+
+	apisv1alpha3 "github.com/crossplane-contrib/provider-azure/apis/v1alpha3"
 )
 
 // FirewallRuleProperties defines the properties of an Azure SQL firewall rule.
@@ -45,6 +47,11 @@ type FirewallRuleObservation struct {
 type FirewallRuleStatus struct {
 	xpv1.ResourceStatus `json:",inline"`
 	AtProvider          FirewallRuleObservation `json:"atProvider,omitempty"`
+
+	// LastFailedRequest identifies the most recent request to Azure that
+	// failed.
+	// +optional
+	LastFailedRequest *apisv1alpha3.FailedRequest `json:"lastFailedRequest,omitempty"`
 }
 
 // FirewallRuleParameters define the desired state of an Azure SQL firewall
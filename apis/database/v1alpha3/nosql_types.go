@@ -21,6 +21,8 @@ import (
 
 	"github.com/Azure/azure-sdk-for-go/services/cosmos-db/mgmt/2015-04-08/documentdb"
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+
+	apisv1alpha3 "github.com/crossplane-contrib/provider-azure/apis/v1alpha3"
 )
 
 // +kubebuilder:object:root=true
@@ -98,6 +100,10 @@ type CosmosDBAccountObservation struct {
 
 	// State - current state of the account in Azure.
 	State string `json:"state"`
+
+	// LastOperation represents the state of the last operation started by the
+	// controller.
+	LastOperation apisv1alpha3.AsyncOperation `json:"lastOperation,omitempty"`
 }
 
 // CosmosDBAccountProperties define the desired properties of an Azure CosmosDB account.
@@ -179,4 +185,9 @@ type CosmosDBAccountStatus struct {
 	xpv1.ResourceStatus `json:",inline"`
 	// + optional
 	AtProvider *CosmosDBAccountObservation `json:"atProvider,omitempty"`
+
+	// LastFailedRequest identifies the most recent request to Azure that
+	// failed.
+	// +optional
+	LastFailedRequest *apisv1alpha3.FailedRequest `json:"lastFailedRequest,omitempty"`
 }
@@ -20,6 +20,8 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+
+	apisv1alpha3 "github.com/crossplane-contrib/provider-azure/apis/v1alpha3"
 )
 
 const (
@@ -66,6 +68,11 @@ type VirtualNetworkRuleStatus struct {
 
 	// Type - Resource type.
 	Type string `json:"type,omitempty"`
+
+	// LastFailedRequest identifies the most recent request to Azure that
+	// failed.
+	// +optional
+	LastFailedRequest *apisv1alpha3.FailedRequest `json:"lastFailedRequest,omitempty"`
 }
 
 // A PostgreSQLVirtualNetworkRuleSpec defines the desired state of a PostgreSQLVirtualNetworkRule.
@@ -22,6 +22,7 @@ limitations under the License.
 package v1alpha3
 
 import (
+	apisv1alpha3 "github.com/crossplane-contrib/provider-azure/apis/v1alpha3"
 	"github.com/crossplane/crossplane-runtime/apis/common/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
@@ -128,6 +129,7 @@ func (in *CosmosDBAccountLocation) DeepCopy() *CosmosDBAccountLocation {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CosmosDBAccountObservation) DeepCopyInto(out *CosmosDBAccountObservation) {
 	*out = *in
+	in.LastOperation.DeepCopyInto(&out.LastOperation)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CosmosDBAccountObservation.
@@ -242,7 +244,12 @@ func (in *CosmosDBAccountStatus) DeepCopyInto(out *CosmosDBAccountStatus) {
 	if in.AtProvider != nil {
 		in, out := &in.AtProvider, &out.AtProvider
 		*out = new(CosmosDBAccountObservation)
-		**out = **in
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LastFailedRequest != nil {
+		in, out := &in.LastFailedRequest, &out.LastFailedRequest
+		*out = new(apisv1alpha3.FailedRequest)
+		(*in).DeepCopyInto(*out)
 	}
 }
 
@@ -344,6 +351,11 @@ func (in *FirewallRuleStatus) DeepCopyInto(out *FirewallRuleStatus) {
 	*out = *in
 	in.ResourceStatus.DeepCopyInto(&out.ResourceStatus)
 	out.AtProvider = in.AtProvider
+	if in.LastFailedRequest != nil {
+		in, out := &in.LastFailedRequest, &out.LastFailedRequest
+		*out = new(apisv1alpha3.FailedRequest)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FirewallRuleStatus.
@@ -695,6 +707,11 @@ func (in *VirtualNetworkRuleProperties) DeepCopy() *VirtualNetworkRuleProperties
 func (in *VirtualNetworkRuleStatus) DeepCopyInto(out *VirtualNetworkRuleStatus) {
 	*out = *in
 	in.ResourceStatus.DeepCopyInto(&out.ResourceStatus)
+	if in.LastFailedRequest != nil {
+		in, out := &in.LastFailedRequest, &out.LastFailedRequest
+		*out = new(apisv1alpha3.FailedRequest)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualNetworkRuleStatus.
@@ -166,4 +166,9 @@ type SQLServerConfigurationObservation struct {
 type SQLServerConfigurationStatus struct {
 	xpv1.ResourceStatus `json:",inline"`
 	AtProvider          SQLServerConfigurationObservation `json:"atProvider,omitempty"`
+
+	// LastFailedRequest identifies the most recent request to Azure that
+	// failed.
+	// +optional
+	LastFailedRequest *apisv1alpha3.FailedRequest `json:"lastFailedRequest,omitempty"`
 }
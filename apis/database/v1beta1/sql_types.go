@@ -246,4 +246,9 @@ type SQLServerObservation struct {
 type SQLServerStatus struct {
 	xpv1.ResourceStatus `json:",inline"`
 	AtProvider          SQLServerObservation `json:"atProvider,omitempty"`
+
+	// LastFailedRequest identifies the most recent request to Azure that
+	// failed.
+	// +optional
+	LastFailedRequest *apisv1alpha3.FailedRequest `json:"lastFailedRequest,omitempty"`
 }
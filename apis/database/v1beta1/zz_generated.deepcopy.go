@@ -22,6 +22,7 @@ limitations under the License.
 package v1beta1
 
 import (
+	"github.com/crossplane-contrib/provider-azure/apis/v1alpha3"
 	"github.com/crossplane/crossplane-runtime/apis/common/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
@@ -285,7 +286,7 @@ func (in *SKU) DeepCopy() *SKU {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SQLServerConfigurationObservation) DeepCopyInto(out *SQLServerConfigurationObservation) {
 	*out = *in
-	out.LastOperation = in.LastOperation
+	in.LastOperation.DeepCopyInto(&out.LastOperation)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SQLServerConfigurationObservation.
@@ -359,7 +360,12 @@ func (in *SQLServerConfigurationSpec) DeepCopy() *SQLServerConfigurationSpec {
 func (in *SQLServerConfigurationStatus) DeepCopyInto(out *SQLServerConfigurationStatus) {
 	*out = *in
 	in.ResourceStatus.DeepCopyInto(&out.ResourceStatus)
-	out.AtProvider = in.AtProvider
+	in.AtProvider.DeepCopyInto(&out.AtProvider)
+	if in.LastFailedRequest != nil {
+		in, out := &in.LastFailedRequest, &out.LastFailedRequest
+		*out = new(v1alpha3.FailedRequest)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SQLServerConfigurationStatus.
@@ -375,7 +381,7 @@ func (in *SQLServerConfigurationStatus) DeepCopy() *SQLServerConfigurationStatus
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SQLServerObservation) DeepCopyInto(out *SQLServerObservation) {
 	*out = *in
-	out.LastOperation = in.LastOperation
+	in.LastOperation.DeepCopyInto(&out.LastOperation)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SQLServerObservation.
@@ -462,7 +468,12 @@ func (in *SQLServerSpec) DeepCopy() *SQLServerSpec {
 func (in *SQLServerStatus) DeepCopyInto(out *SQLServerStatus) {
 	*out = *in
 	in.ResourceStatus.DeepCopyInto(&out.ResourceStatus)
-	out.AtProvider = in.AtProvider
+	in.AtProvider.DeepCopyInto(&out.AtProvider)
+	if in.LastFailedRequest != nil {
+		in, out := &in.LastFailedRequest, &out.LastFailedRequest
+		*out = new(v1alpha3.FailedRequest)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SQLServerStatus.
@@ -20,6 +20,8 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+
+	apisv1alpha3 "github.com/crossplane-contrib/provider-azure/apis/v1alpha3"
 )
 
 // ZoneParameters define the desired state of an Azure DNS Zone.
@@ -111,6 +113,11 @@ type ZoneSpec struct {
 type ZoneStatus struct {
 	xpv1.ResourceStatus `json:",inline"`
 	AtProvider          ZoneObservation `json:"atProvider,omitempty"`
+
+	// LastFailedRequest identifies the most recent request to Azure that
+	// failed.
+	// +optional
+	LastFailedRequest *apisv1alpha3.FailedRequest `json:"lastFailedRequest,omitempty"`
 }
 
 // RecordType enumerates the values for record type.
@@ -367,6 +374,11 @@ type RecordSetSpec struct {
 type RecordSetStatus struct {
 	xpv1.ResourceStatus `json:",inline"`
 	AtProvider          RecordSetObservation `json:"atProvider,omitempty"`
+
+	// LastFailedRequest identifies the most recent request to Azure that
+	// failed.
+	// +optional
+	LastFailedRequest *apisv1alpha3.FailedRequest `json:"lastFailedRequest,omitempty"`
 }
 
 // +kubebuilder:object:root=true
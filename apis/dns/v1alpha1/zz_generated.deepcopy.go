@@ -22,6 +22,7 @@ limitations under the License.
 package v1alpha1
 
 import (
+	"github.com/crossplane-contrib/provider-azure/apis/v1alpha3"
 	"github.com/crossplane/crossplane-runtime/apis/common/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
@@ -382,6 +383,11 @@ func (in *RecordSetStatus) DeepCopyInto(out *RecordSetStatus) {
 	*out = *in
 	in.ResourceStatus.DeepCopyInto(&out.ResourceStatus)
 	out.AtProvider = in.AtProvider
+	if in.LastFailedRequest != nil {
+		in, out := &in.LastFailedRequest, &out.LastFailedRequest
+		*out = new(v1alpha3.FailedRequest)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RecordSetStatus.
@@ -679,6 +685,11 @@ func (in *ZoneStatus) DeepCopyInto(out *ZoneStatus) {
 	*out = *in
 	in.ResourceStatus.DeepCopyInto(&out.ResourceStatus)
 	in.AtProvider.DeepCopyInto(&out.AtProvider)
+	if in.LastFailedRequest != nil {
+		in, out := &in.LastFailedRequest, &out.LastFailedRequest
+		*out = new(v1alpha3.FailedRequest)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ZoneStatus.
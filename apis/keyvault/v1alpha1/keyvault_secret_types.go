@@ -20,6 +20,8 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+
+	apisv1alpha3 "github.com/crossplane-contrib/provider-azure/apis/v1alpha3"
 )
 
 // KeyVaultSecretAttributesParameters defines the desired state of an Azure Key Vault Secret Attributes.
@@ -39,6 +41,8 @@ type KeyVaultSecretAttributesParameters struct {
 // https://docs.microsoft.com/en-us/rest/api/keyvault/#secret-operations
 type KeyVaultSecretParameters struct {
 	// VaultBaseURL - The vault name, for example https://myvault.vault.azure.net.
+	// A bare vault name, for example myvault, is resolved using the Key Vault
+	// DNS suffix of the cloud selected by the ProviderConfig.
 	VaultBaseURL string `json:"vaultBaseUrl"`
 
 	// Name - The name of the secret
@@ -112,6 +116,11 @@ type KeyVaultSecretObservation struct {
 type KeyVaultSecretStatus struct {
 	xpv1.ResourceStatus `json:",inline"`
 	AtProvider          KeyVaultSecretObservation `json:"atProvider,omitempty"`
+
+	// LastFailedRequest identifies the most recent request to Azure that
+	// failed.
+	// +optional
+	LastFailedRequest *apisv1alpha3.FailedRequest `json:"lastFailedRequest,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -20,6 +20,8 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+
+	apisv1alpha3 "github.com/crossplane-contrib/provider-azure/apis/v1alpha3"
 )
 
 // AddressSpace contains an array of IP address ranges that can be used by
@@ -75,6 +77,15 @@ type VirtualNetworkSpec struct {
 	Tags map[string]string `json:"tags,omitempty"`
 }
 
+// A VirtualNetworkObservation represents the observed state of a
+// VirtualNetwork that is not reported by Azure as part of the VirtualNetwork
+// itself.
+type VirtualNetworkObservation struct {
+	// LastOperation represents the state of the last operation started by the
+	// controller.
+	LastOperation apisv1alpha3.AsyncOperation `json:"lastOperation,omitempty"`
+}
+
 // A VirtualNetworkStatus represents the observed state of a VirtualNetwork.
 type VirtualNetworkStatus struct {
 	xpv1.ResourceStatus `json:",inline"`
@@ -98,6 +109,15 @@ type VirtualNetworkStatus struct {
 
 	// Type of this VirtualNetwork.
 	Type string `json:"type,omitempty"`
+
+	// AtProvider reports the observed state of the VirtualNetwork that is not
+	// reported by Azure as part of the VirtualNetwork itself.
+	AtProvider VirtualNetworkObservation `json:"atProvider,omitempty"`
+
+	// LastFailedRequest identifies the most recent request to Azure that
+	// failed.
+	// +optional
+	LastFailedRequest *apisv1alpha3.FailedRequest `json:"lastFailedRequest,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -180,6 +200,14 @@ type SubnetSpec struct {
 	SubnetPropertiesFormat `json:"properties"`
 }
 
+// A SubnetObservation represents the observed state of a Subnet that is not
+// reported by Azure as part of the Subnet itself.
+type SubnetObservation struct {
+	// LastOperation represents the state of the last operation started by the
+	// controller.
+	LastOperation apisv1alpha3.AsyncOperation `json:"lastOperation,omitempty"`
+}
+
 // A SubnetStatus represents the observed state of a Subnet.
 type SubnetStatus struct {
 	xpv1.ResourceStatus `json:",inline"`
@@ -199,6 +227,15 @@ type SubnetStatus struct {
 	// Purpose - A string identifying the intention of use for this subnet based
 	// on delegations and other user-defined properties.
 	Purpose string `json:"purpose,omitempty"`
+
+	// AtProvider reports the observed state of the Subnet that is not
+	// reported by Azure as part of the Subnet itself.
+	AtProvider SubnetObservation `json:"atProvider,omitempty"`
+
+	// LastFailedRequest identifies the most recent request to Azure that
+	// failed.
+	// +optional
+	LastFailedRequest *apisv1alpha3.FailedRequest `json:"lastFailedRequest,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -390,12 +427,21 @@ type PublicIPAddressObservation struct {
 
 	// IPConfiguration - The IP configuration associated with the public IP address
 	IPConfiguration *IPConfiguration `json:"ipConfiguration,omitempty"`
+
+	// LastOperation represents the state of the last operation started by the
+	// controller.
+	LastOperation apisv1alpha3.AsyncOperation `json:"lastOperation,omitempty"`
 }
 
 // A PublicIPAddressStatus represents the observed state of a SQLServer.
 type PublicIPAddressStatus struct {
 	xpv1.ResourceStatus `json:",inline"`
 	AtProvider          PublicIPAddressObservation `json:"atProvider,omitempty"`
+
+	// LastFailedRequest identifies the most recent request to Azure that
+	// failed.
+	// +optional
+	LastFailedRequest *apisv1alpha3.FailedRequest `json:"lastFailedRequest,omitempty"`
 }
 
 // +kubebuilder:object:root=true
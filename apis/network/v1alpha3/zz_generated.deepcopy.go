@@ -22,6 +22,7 @@ limitations under the License.
 package v1alpha3
 
 import (
+	apisv1alpha3 "github.com/crossplane-contrib/provider-azure/apis/v1alpha3"
 	"github.com/crossplane/crossplane-runtime/apis/common/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
@@ -203,6 +204,7 @@ func (in *PublicIPAddressObservation) DeepCopyInto(out *PublicIPAddressObservati
 		*out = new(IPConfiguration)
 		(*in).DeepCopyInto(*out)
 	}
+	in.LastOperation.DeepCopyInto(&out.LastOperation)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PublicIPAddressObservation.
@@ -294,6 +296,11 @@ func (in *PublicIPAddressStatus) DeepCopyInto(out *PublicIPAddressStatus) {
 	*out = *in
 	in.ResourceStatus.DeepCopyInto(&out.ResourceStatus)
 	in.AtProvider.DeepCopyInto(&out.AtProvider)
+	if in.LastFailedRequest != nil {
+		in, out := &in.LastFailedRequest, &out.LastFailedRequest
+		*out = new(apisv1alpha3.FailedRequest)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PublicIPAddressStatus.
@@ -400,6 +407,22 @@ func (in *SubnetList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SubnetObservation) DeepCopyInto(out *SubnetObservation) {
+	*out = *in
+	in.LastOperation.DeepCopyInto(&out.LastOperation)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SubnetObservation.
+func (in *SubnetObservation) DeepCopy() *SubnetObservation {
+	if in == nil {
+		return nil
+	}
+	out := new(SubnetObservation)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SubnetPropertiesFormat) DeepCopyInto(out *SubnetPropertiesFormat) {
 	*out = *in
@@ -463,6 +486,12 @@ func (in *SubnetSpec) DeepCopy() *SubnetSpec {
 func (in *SubnetStatus) DeepCopyInto(out *SubnetStatus) {
 	*out = *in
 	in.ResourceStatus.DeepCopyInto(&out.ResourceStatus)
+	in.AtProvider.DeepCopyInto(&out.AtProvider)
+	if in.LastFailedRequest != nil {
+		in, out := &in.LastFailedRequest, &out.LastFailedRequest
+		*out = new(apisv1alpha3.FailedRequest)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SubnetStatus.
@@ -534,6 +563,22 @@ func (in *VirtualNetworkList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VirtualNetworkObservation) DeepCopyInto(out *VirtualNetworkObservation) {
+	*out = *in
+	in.LastOperation.DeepCopyInto(&out.LastOperation)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualNetworkObservation.
+func (in *VirtualNetworkObservation) DeepCopy() *VirtualNetworkObservation {
+	if in == nil {
+		return nil
+	}
+	out := new(VirtualNetworkObservation)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VirtualNetworkPropertiesFormat) DeepCopyInto(out *VirtualNetworkPropertiesFormat) {
 	*out = *in
@@ -588,6 +633,12 @@ func (in *VirtualNetworkSpec) DeepCopy() *VirtualNetworkSpec {
 func (in *VirtualNetworkStatus) DeepCopyInto(out *VirtualNetworkStatus) {
 	*out = *in
 	in.ResourceStatus.DeepCopyInto(&out.ResourceStatus)
+	in.AtProvider.DeepCopyInto(&out.AtProvider)
+	if in.LastFailedRequest != nil {
+		in, out := &in.LastFailedRequest, &out.LastFailedRequest
+		*out = new(apisv1alpha3.FailedRequest)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualNetworkStatus.
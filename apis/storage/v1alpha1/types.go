@@ -0,0 +1,283 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+
+	apisv1alpha3 "github.com/crossplane-contrib/provider-azure/apis/v1alpha3"
+)
+
+// ManagementPolicyParameters define the desired state of the lifecycle
+// management policy of an Azure storage account.
+// https://docs.microsoft.com/en-us/azure/storage/blobs/lifecycle-management-overview
+type ManagementPolicyParameters struct {
+	// ResourceGroupName of the storage account the policy applies to. It is
+	// the resource group of the Account referenced by accountNameRef or
+	// accountNameSelector if it is unspecified.
+	// +immutable
+	// +optional
+	ResourceGroupName string `json:"resourceGroupName,omitempty"`
+
+	// ResourceGroupNameRef to fetch resource group name.
+	// +immutable
+	// +optional
+	ResourceGroupNameRef *xpv1.Reference `json:"resourceGroupNameRef,omitempty"`
+
+	// ResourceGroupNameSelector to select a reference to a resource group.
+	// +immutable
+	// +optional
+	ResourceGroupNameSelector *xpv1.Selector `json:"resourceGroupNameSelector,omitempty"`
+
+	// AccountName of the storage account the policy applies to. A storage
+	// account has at most one lifecycle management policy.
+	// +immutable
+	// +optional
+	AccountName string `json:"accountName,omitempty"`
+
+	// AccountNameRef to fetch the storage account name.
+	// +immutable
+	// +optional
+	AccountNameRef *xpv1.Reference `json:"accountNameRef,omitempty"`
+
+	// AccountNameSelector to select a reference to a storage account.
+	// +immutable
+	// +optional
+	AccountNameSelector *xpv1.Selector `json:"accountNameSelector,omitempty"`
+
+	// Rules of the policy.
+	// +kubebuilder:validation:MinItems=1
+	Rules []ManagementPolicyRule `json:"rules"`
+}
+
+// A ManagementPolicyRule applies actions to the blobs that match its filters.
+type ManagementPolicyRule struct {
+	// Name of the rule. It must be unique within the policy.
+	Name string `json:"name"`
+
+	// Enabled is true if the rule is applied. Rules are enabled unless
+	// specified otherwise.
+	// +optional
+	Enabled *bool `json:"enabled,omitempty"`
+
+	// Definition of the rule.
+	Definition ManagementPolicyDefinition `json:"definition"`
+}
+
+// A ManagementPolicyDefinition defines the actions of a rule, and the blobs
+// they apply to.
+type ManagementPolicyDefinition struct {
+	// Actions of the rule.
+	Actions ManagementPolicyActions `json:"actions"`
+
+	// Filters limit the actions of the rule to the blobs that match them.
+	// +optional
+	Filters *ManagementPolicyFilter `json:"filters,omitempty"`
+}
+
+// ManagementPolicyActions apply to base blobs, snapshots and versions.
+type ManagementPolicyActions struct {
+	// BaseBlob actions of the rule.
+	// +optional
+	BaseBlob *ManagementPolicyBaseBlob `json:"baseBlob,omitempty"`
+
+	// Snapshot actions of the rule.
+	// +optional
+	Snapshot *ManagementPolicySnapshot `json:"snapshot,omitempty"`
+
+	// Version actions of the rule.
+	// +optional
+	Version *ManagementPolicyVersion `json:"version,omitempty"`
+}
+
+// ManagementPolicyBaseBlob actions apply to base blobs once they have not
+// been modified or accessed for a number of days.
+type ManagementPolicyBaseBlob struct {
+	// TierToCool moves blobs at the hot tier to cool storage.
+	// +optional
+	TierToCool *DateAfterModification `json:"tierToCool,omitempty"`
+
+	// TierToArchive moves blobs at the hot or cool tier to archive storage.
+	// +optional
+	TierToArchive *DateAfterModification `json:"tierToArchive,omitempty"`
+
+	// Delete deletes blobs.
+	// +optional
+	Delete *DateAfterModification `json:"delete,omitempty"`
+
+	// EnableAutoTierToHotFromCool moves blobs back from cool to hot storage
+	// when they are accessed. It requires tierToCool with
+	// daysAfterLastAccessTimeGreaterThan.
+	// +optional
+	EnableAutoTierToHotFromCool *bool `json:"enableAutoTierToHotFromCool,omitempty"`
+}
+
+// ManagementPolicySnapshot actions apply to blob snapshots once they reach a
+// number of days of age.
+type ManagementPolicySnapshot struct {
+	// TierToCool moves snapshots at the hot tier to cool storage.
+	// +optional
+	TierToCool *DateAfterCreation `json:"tierToCool,omitempty"`
+
+	// TierToArchive moves snapshots at the hot or cool tier to archive
+	// storage.
+	// +optional
+	TierToArchive *DateAfterCreation `json:"tierToArchive,omitempty"`
+
+	// Delete deletes snapshots.
+	// +optional
+	Delete *DateAfterCreation `json:"delete,omitempty"`
+}
+
+// ManagementPolicyVersion actions apply to previous blob versions once they
+// reach a number of days of age.
+type ManagementPolicyVersion struct {
+	// TierToCool moves versions at the hot tier to cool storage.
+	// +optional
+	TierToCool *DateAfterCreation `json:"tierToCool,omitempty"`
+
+	// TierToArchive moves versions at the hot or cool tier to archive
+	// storage.
+	// +optional
+	TierToArchive *DateAfterCreation `json:"tierToArchive,omitempty"`
+
+	// Delete deletes versions.
+	// +optional
+	Delete *DateAfterCreation `json:"delete,omitempty"`
+}
+
+// DateAfterModification is the age of a base blob that triggers an action.
+// Exactly one of its fields must be specified.
+type DateAfterModification struct {
+	// DaysAfterModificationGreaterThan is the number of days since the blob
+	// was last modified.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	DaysAfterModificationGreaterThan *int `json:"daysAfterModificationGreaterThan,omitempty"`
+
+	// DaysAfterLastAccessTimeGreaterThan is the number of days since the blob
+	// was last accessed. It requires last access time tracking to be enabled
+	// for the storage account.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	DaysAfterLastAccessTimeGreaterThan *int `json:"daysAfterLastAccessTimeGreaterThan,omitempty"`
+}
+
+// DateAfterCreation is the age of a snapshot or version that triggers an
+// action.
+type DateAfterCreation struct {
+	// DaysAfterCreationGreaterThan is the number of days since the snapshot
+	// or version was created.
+	// +kubebuilder:validation:Minimum=0
+	DaysAfterCreationGreaterThan int `json:"daysAfterCreationGreaterThan"`
+}
+
+// A ManagementPolicyFilter limits the actions of a rule to the blobs that
+// match it.
+type ManagementPolicyFilter struct {
+	// PrefixMatch is a list of prefixes of the names of the blobs the rule
+	// applies to, starting with a container name.
+	// +optional
+	PrefixMatch []string `json:"prefixMatch,omitempty"`
+
+	// BlobTypes the rule applies to. Block blobs support all actions, while
+	// append blobs only support delete actions. Possible values include:
+	// 'blockBlob', 'appendBlob'
+	// +kubebuilder:validation:MinItems=1
+	BlobTypes []string `json:"blobTypes"`
+
+	// BlobIndexMatch is a list of blob index tag filters. The rule applies to
+	// the blobs that match all of them.
+	// +kubebuilder:validation:MaxItems=10
+	// +optional
+	BlobIndexMatch []TagFilter `json:"blobIndexMatch,omitempty"`
+}
+
+// A TagFilter matches blobs by a blob index tag.
+type TagFilter struct {
+	// Name of the blob index tag.
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=128
+	Name string `json:"name"`
+
+	// Op is the operator used to compare the blob index tag to the value.
+	// Only equality (==) is currently supported, and is used if the operator
+	// is unspecified.
+	// +optional
+	Op string `json:"op,omitempty"`
+
+	// Value the blob index tag is compared to.
+	// +kubebuilder:validation:MaxLength=256
+	Value string `json:"value"`
+}
+
+// A ManagementPolicySpec defines the desired state of a ManagementPolicy.
+type ManagementPolicySpec struct {
+	xpv1.ResourceSpec `json:",inline"`
+	ForProvider       ManagementPolicyParameters `json:"forProvider"`
+}
+
+// ManagementPolicyObservation represents the observed state of the
+// ManagementPolicy object in Azure.
+type ManagementPolicyObservation struct {
+	// ID of the policy.
+	ID string `json:"id,omitempty"`
+
+	// LastModifiedTime is the time the policy was last modified.
+	LastModifiedTime *metav1.Time `json:"lastModifiedTime,omitempty"`
+}
+
+// A ManagementPolicyStatus represents the observed status of a
+// ManagementPolicy.
+type ManagementPolicyStatus struct {
+	xpv1.ResourceStatus `json:",inline"`
+	AtProvider          ManagementPolicyObservation `json:"atProvider,omitempty"`
+
+	// LastFailedRequest identifies the most recent request to Azure that
+	// failed.
+	// +optional
+	LastFailedRequest *apisv1alpha3.FailedRequest `json:"lastFailedRequest,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// A ManagementPolicy is a managed resource that represents the lifecycle
+// management policy of an Azure storage account.
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+// +kubebuilder:printcolumn:name="STORAGE_ACCOUNT",type="string",JSONPath=".spec.forProvider.accountName"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster,categories={crossplane,managed,azure}
+type ManagementPolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ManagementPolicySpec   `json:"spec"`
+	Status ManagementPolicyStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ManagementPolicyList contains a list of ManagementPolicy.
+type ManagementPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ManagementPolicy `json:"items"`
+}
@@ -0,0 +1,438 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha3
+
+import (
+	"encoding/json"
+	"reflect"
+
+	"github.com/Azure/azure-sdk-for-go/services/storage/mgmt/2017-06-01/storage"
+	"github.com/Azure/azure-storage-blob-go/azblob"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+
+	"github.com/crossplane-contrib/provider-azure/apis/storage/v1beta1"
+	apisv1alpha3 "github.com/crossplane-contrib/provider-azure/apis/v1alpha3"
+)
+
+// AnnotationKeyConversionData is the annotation that preserves the fields of
+// an object that the version it was converted to can't represent, so that they
+// are restored when the object is converted back. A v1alpha3 object holds the
+// fields of the v1beta1 object it was converted from, and vice versa.
+const AnnotationKeyConversionData = "storage.azure.crossplane.io/conversion-data"
+
+const (
+	errGetConversionData = "cannot get conversion data"
+	errSetConversionData = "cannot set conversion data"
+)
+
+// accountData are the fields of an Account that only one of its versions can
+// represent.
+type accountData struct {
+	// Fields of a v1beta1 Account.
+	ResourceGroupNameRef      *xpv1.Reference              `json:"resourceGroupNameRef,omitempty"`
+	ResourceGroupNameSelector *xpv1.Selector               `json:"resourceGroupNameSelector,omitempty"`
+	LastOperation             *apisv1alpha3.AsyncOperation `json:"lastOperation,omitempty"`
+	LastFailedRequest         *apisv1alpha3.FailedRequest  `json:"lastFailedRequest,omitempty"`
+
+	// Fields of a v1alpha3 Account.
+	IdentityPrincipalID string          `json:"identityPrincipalId,omitempty"`
+	IdentityTenantID    string          `json:"identityTenantId,omitempty"`
+	SkuCapabilities     []skuCapability `json:"skuCapabilities,omitempty"`
+	SkuKind             storage.Kind    `json:"skuKind,omitempty"`
+	SkuLocations        []string        `json:"skuLocations,omitempty"`
+	SkuResourceType     string          `json:"skuResourceType,omitempty"`
+}
+
+// defaultProviderConfig is the ProviderConfig of a v1beta1 Container that is
+// converted from a v1alpha3 Container. The ProviderConfig of a v1alpha3
+// Container is the Account that contains it, which a v1beta1 Container
+// references by its accountNameRef instead. Such a Container is marked to
+// inherit the ProviderConfig of that Account, which replaces the default once
+// the Container controller resolves it.
+const defaultProviderConfig = "default"
+
+// containerData are the fields of a Container that only one of its versions
+// can represent.
+type containerData struct {
+	// Fields of a v1beta1 Container.
+	ProviderConfigReference   *xpv1.Reference               `json:"providerConfigRef,omitempty"`
+	ResourceGroupName         string                        `json:"resourceGroupName,omitempty"`
+	ResourceGroupNameRef      *xpv1.Reference               `json:"resourceGroupNameRef,omitempty"`
+	ResourceGroupNameSelector *xpv1.Selector                `json:"resourceGroupNameSelector,omitempty"`
+	AccountName               string                        `json:"accountName,omitempty"`
+	AccountNameSelector       *xpv1.Selector                `json:"accountNameSelector,omitempty"`
+	ImmutabilityPolicy        *v1beta1.ImmutabilityPolicy   `json:"immutabilityPolicy,omitempty"`
+	LegalHold                 *v1beta1.LegalHold            `json:"legalHold,omitempty"`
+	AtProvider                *v1beta1.ContainerObservation `json:"atProvider,omitempty"`
+	LastFailedRequest         *apisv1alpha3.FailedRequest   `json:"lastFailedRequest,omitempty"`
+}
+
+// ConvertTo converts this Account to the hub version.
+func (a *Account) ConvertTo(hub conversion.Hub) error {
+	dst := hub.(*v1beta1.Account)
+	dst.ObjectMeta = *a.ObjectMeta.DeepCopy()
+	d := &accountData{}
+	if err := getConversionData(dst, d); err != nil {
+		return errors.Wrap(err, errGetConversionData)
+	}
+
+	dst.Spec.ResourceSpec = *a.Spec.ResourceSpec.DeepCopy()
+	dst.Spec.ForProvider = v1beta1.AccountParameters{
+		ResourceGroupName:         a.Spec.ResourceGroupName,
+		ResourceGroupNameRef:      d.ResourceGroupNameRef,
+		ResourceGroupNameSelector: d.ResourceGroupNameSelector,
+	}
+	stored := accountData{}
+	if s := a.Spec.StorageAccountSpec.DeepCopy(); s != nil {
+		p := &dst.Spec.ForProvider
+		p.Location = s.Location
+		p.Kind = string(s.Kind)
+		p.Tags = s.Tags
+		if s.Identity != nil {
+			p.Identity = &v1beta1.Identity{Type: s.Identity.Type}
+			stored.IdentityPrincipalID = s.Identity.PrincipalID
+			stored.IdentityTenantID = s.Identity.TenantID
+		}
+		if s.Sku != nil {
+			p.SKU = v1beta1.SKU{Name: string(s.Sku.Name), Tier: string(s.Sku.Tier)}
+			stored.SkuCapabilities = s.Sku.Capabilities
+			stored.SkuKind = s.Sku.Kind
+			stored.SkuLocations = s.Sku.Locations
+			stored.SkuResourceType = s.Sku.ResourceType
+		}
+		if sp := s.StorageAccountSpecProperties; sp != nil {
+			if sp.AccessTier != "" {
+				p.AccessTier = toStringPtr(string(sp.AccessTier))
+			}
+			if sp.EnableHTTPSTrafficOnly {
+				p.EnableHTTPSTrafficOnly = &sp.EnableHTTPSTrafficOnly
+			}
+			if sp.CustomDomain != nil {
+				p.CustomDomain = &v1beta1.CustomDomain{Name: sp.CustomDomain.Name, UseSubDomainName: sp.CustomDomain.UseSubDomainName}
+			}
+			p.Encryption = encryptionToHub(sp.Encryption)
+			p.NetworkRuleSet = networkRuleSetToHub(sp.NetworkRuleSet)
+		}
+	}
+
+	dst.Status.ResourceStatus = *a.Status.ResourceStatus.DeepCopy()
+	dst.Status.AtProvider = v1beta1.AccountObservation{}
+	if s := a.Status.StorageAccountStatus.DeepCopy(); s != nil {
+		o := &dst.Status.AtProvider
+		o.ID = s.ID
+		o.Name = s.Name
+		o.Type = s.Type
+		if sp := s.StorageAccountStatusProperties; sp != nil {
+			o.CreationTime = sp.CreationTime
+			o.LastGeoFailoverTime = sp.LastGeoFailoverTime
+			o.PrimaryEndpoints = endpointsToHub(sp.PrimaryEndpoints)
+			o.PrimaryLocation = sp.PrimaryLocation
+			o.ProvisioningState = string(sp.ProvisioningState)
+			o.SecondaryEndpoints = endpointsToHub(sp.SecondaryEndpoints)
+			o.SecondaryLocation = sp.SecondaryLocation
+			o.StatusOfPrimary = string(sp.StatusOfPrimary)
+			o.StatusOfSecondary = string(sp.StatusOfSecondary)
+		}
+	}
+	if d.LastOperation != nil {
+		dst.Status.AtProvider.LastOperation = *d.LastOperation
+	}
+	dst.Status.LastFailedRequest = d.LastFailedRequest
+
+	return errors.Wrap(setConversionData(dst, stored), errSetConversionData)
+}
+
+// ConvertFrom converts the hub version to this Account.
+func (a *Account) ConvertFrom(hub conversion.Hub) error {
+	src := hub.(*v1beta1.Account)
+	a.ObjectMeta = *src.ObjectMeta.DeepCopy()
+	d := &accountData{}
+	if err := getConversionData(a, d); err != nil {
+		return errors.Wrap(err, errGetConversionData)
+	}
+
+	p := src.Spec.ForProvider.DeepCopy()
+	a.Spec.ResourceSpec = *src.Spec.ResourceSpec.DeepCopy()
+	a.Spec.ResourceGroupName = p.ResourceGroupName
+	s := &StorageAccountSpec{
+		Kind:     storage.Kind(p.Kind),
+		Location: p.Location,
+		Sku: &Sku{
+			Capabilities: d.SkuCapabilities,
+			Kind:         d.SkuKind,
+			Locations:    d.SkuLocations,
+			Name:         storage.SkuName(p.SKU.Name),
+			ResourceType: d.SkuResourceType,
+			Tier:         storage.SkuTier(p.SKU.Tier),
+		},
+		Tags: p.Tags,
+	}
+	if p.Identity != nil {
+		s.Identity = &Identity{PrincipalID: d.IdentityPrincipalID, TenantID: d.IdentityTenantID, Type: p.Identity.Type}
+	}
+	sp := &StorageAccountSpecProperties{
+		Encryption:     encryptionFromHub(p.Encryption),
+		NetworkRuleSet: networkRuleSetFromHub(p.NetworkRuleSet),
+	}
+	if p.AccessTier != nil {
+		sp.AccessTier = storage.AccessTier(*p.AccessTier)
+	}
+	if p.EnableHTTPSTrafficOnly != nil {
+		sp.EnableHTTPSTrafficOnly = *p.EnableHTTPSTrafficOnly
+	}
+	if p.CustomDomain != nil {
+		sp.CustomDomain = &CustomDomain{Name: p.CustomDomain.Name, UseSubDomainName: p.CustomDomain.UseSubDomainName}
+	}
+	if *sp != (StorageAccountSpecProperties{}) {
+		s.StorageAccountSpecProperties = sp
+	}
+	a.Spec.StorageAccountSpec = s
+
+	o := src.Status.AtProvider.DeepCopy()
+	a.Status.ResourceStatus = *src.Status.ResourceStatus.DeepCopy()
+	a.Status.StorageAccountStatus = nil
+	props := &StorageAccountStatusProperties{
+		CreationTime:        o.CreationTime,
+		LastGeoFailoverTime: o.LastGeoFailoverTime,
+		PrimaryEndpoints:    endpointsFromHub(o.PrimaryEndpoints),
+		PrimaryLocation:     o.PrimaryLocation,
+		ProvisioningState:   storage.ProvisioningState(o.ProvisioningState),
+		SecondaryEndpoints:  endpointsFromHub(o.SecondaryEndpoints),
+		SecondaryLocation:   o.SecondaryLocation,
+		StatusOfPrimary:     storage.AccountStatus(o.StatusOfPrimary),
+		StatusOfSecondary:   storage.AccountStatus(o.StatusOfSecondary),
+	}
+	if *props == (StorageAccountStatusProperties{}) {
+		props = nil
+	}
+	if o.ID != "" || o.Name != "" || o.Type != "" || props != nil {
+		a.Status.StorageAccountStatus = &StorageAccountStatus{ID: o.ID, Name: o.Name, Type: o.Type, StorageAccountStatusProperties: props}
+	}
+
+	stored := accountData{
+		ResourceGroupNameRef:      p.ResourceGroupNameRef,
+		ResourceGroupNameSelector: p.ResourceGroupNameSelector,
+		LastFailedRequest:         src.Status.LastFailedRequest.DeepCopy(),
+	}
+	if op := o.LastOperation; !reflect.DeepEqual(op, apisv1alpha3.AsyncOperation{}) {
+		stored.LastOperation = op.DeepCopy()
+	}
+	return errors.Wrap(setConversionData(a, stored), errSetConversionData)
+}
+
+// ConvertTo converts this Container to the hub version.
+func (c *Container) ConvertTo(hub conversion.Hub) error {
+	dst := hub.(*v1beta1.Container)
+	dst.ObjectMeta = *c.ObjectMeta.DeepCopy()
+	d := &containerData{}
+	if err := getConversionData(dst, d); err != nil {
+		return errors.Wrap(err, errGetConversionData)
+	}
+
+	dst.Spec.ResourceSpec = *c.Spec.ResourceSpec.DeepCopy()
+	dst.Spec.ProviderConfigReference = d.ProviderConfigReference
+	dst.Spec.ProviderReference = nil
+	dst.Spec.ForProvider = v1beta1.ContainerParameters{
+		ResourceGroupName:         d.ResourceGroupName,
+		ResourceGroupNameRef:      d.ResourceGroupNameRef,
+		ResourceGroupNameSelector: d.ResourceGroupNameSelector,
+		AccountName:               d.AccountName,
+		AccountNameSelector:       d.AccountNameSelector,
+		PublicAccessType:          string(c.Spec.PublicAccessType),
+		ImmutabilityPolicy:        d.ImmutabilityPolicy,
+		LegalHold:                 d.LegalHold,
+	}
+	switch {
+	case c.Spec.ProviderConfigReference != nil:
+		dst.Spec.ForProvider.AccountNameRef = &xpv1.Reference{Name: c.Spec.ProviderConfigReference.Name}
+	case c.Spec.ProviderReference != nil:
+		dst.Spec.ForProvider.AccountNameRef = &xpv1.Reference{Name: c.Spec.ProviderReference.Name}
+	}
+	if dst.Spec.ProviderConfigReference == nil {
+		dst.Spec.ProviderConfigReference = &xpv1.Reference{Name: defaultProviderConfig}
+		if dst.Spec.ForProvider.AccountNameRef != nil {
+			meta.AddAnnotations(dst, map[string]string{v1beta1.AnnotationKeyInheritProviderConfig: "true"})
+		}
+	}
+	if m := c.Spec.Metadata; m != nil {
+		dst.Spec.ForProvider.Metadata = make(map[string]string, len(m))
+		for k, v := range m {
+			dst.Spec.ForProvider.Metadata[k] = v
+		}
+	}
+
+	dst.Status.ResourceStatus = *c.Status.ResourceStatus.DeepCopy()
+	dst.Status.AtProvider = v1beta1.ContainerObservation{}
+	if d.AtProvider != nil {
+		dst.Status.AtProvider = *d.AtProvider
+	}
+	dst.Status.LastFailedRequest = d.LastFailedRequest
+	return nil
+}
+
+// ConvertFrom converts the hub version to this Container.
+func (c *Container) ConvertFrom(hub conversion.Hub) error {
+	src := hub.(*v1beta1.Container)
+	c.ObjectMeta = *src.ObjectMeta.DeepCopy()
+	if err := getConversionData(c, &containerData{}); err != nil {
+		return errors.Wrap(err, errGetConversionData)
+	}
+	_, inherit := c.GetAnnotations()[v1beta1.AnnotationKeyInheritProviderConfig]
+	meta.RemoveAnnotations(c, v1beta1.AnnotationKeyInheritProviderConfig)
+
+	c.Spec.ResourceSpec = *src.Spec.ResourceSpec.DeepCopy()
+	c.Spec.ProviderConfigReference = nil
+	if ref := src.Spec.ForProvider.AccountNameRef; ref != nil {
+		c.Spec.ProviderConfigReference = &xpv1.Reference{Name: ref.Name}
+	}
+	c.Spec.ContainerParameters = ContainerParameters{
+		PublicAccessType: azblob.PublicAccessType(src.Spec.ForProvider.PublicAccessType),
+	}
+	if m := src.Spec.ForProvider.Metadata; m != nil {
+		c.Spec.Metadata = make(map[string]string, len(m))
+		for k, v := range m {
+			c.Spec.Metadata[k] = v
+		}
+	}
+	c.Status.ResourceStatus = *src.Status.ResourceStatus.DeepCopy()
+
+	p := src.Spec.ForProvider
+	stored := containerData{
+		ResourceGroupName:         p.ResourceGroupName,
+		ResourceGroupNameRef:      p.ResourceGroupNameRef,
+		ResourceGroupNameSelector: p.ResourceGroupNameSelector,
+		AccountName:               p.AccountName,
+		AccountNameSelector:       p.AccountNameSelector,
+		ImmutabilityPolicy:        p.ImmutabilityPolicy,
+		LegalHold:                 p.LegalHold,
+		LastFailedRequest:         src.Status.LastFailedRequest.DeepCopy(),
+	}
+	if !inherit {
+		stored.ProviderConfigReference = src.Spec.ProviderConfigReference
+	}
+	if o := src.Status.AtProvider; !reflect.DeepEqual(o, v1beta1.ContainerObservation{}) {
+		stored.AtProvider = o.DeepCopy()
+	}
+	return errors.Wrap(setConversionData(c, stored), errSetConversionData)
+}
+
+// getConversionData reads the conversion data annotation of the supplied
+// object into the supplied data, and removes the annotation.
+func getConversionData(o metav1.Object, data interface{}) error {
+	a := o.GetAnnotations()
+	v, ok := a[AnnotationKeyConversionData]
+	if !ok {
+		return nil
+	}
+	delete(a, AnnotationKeyConversionData)
+	if len(a) == 0 {
+		a = nil
+	}
+	o.SetAnnotations(a)
+	return json.Unmarshal([]byte(v), data)
+}
+
+// setConversionData writes the supplied data to the conversion data
+// annotation of the supplied object, unless the data is empty.
+func setConversionData(o metav1.Object, data interface{}) error {
+	b, err := json.Marshal(data)
+	if err != nil || string(b) == "{}" {
+		return err
+	}
+	a := o.GetAnnotations()
+	if a == nil {
+		a = map[string]string{}
+	}
+	a[AnnotationKeyConversionData] = string(b)
+	o.SetAnnotations(a)
+	return nil
+}
+
+func encryptionToHub(e *Encryption) *v1beta1.Encryption {
+	if e == nil {
+		return nil
+	}
+	out := &v1beta1.Encryption{KeySource: string(e.KeySource)}
+	if e.Services != nil {
+		out.Services = &v1beta1.EnabledEncryptionServices{Blob: e.Services.Blob, File: e.Services.File}
+	}
+	if kv := e.KeyVaultProperties; kv != nil {
+		out.KeyVaultProperties = &v1beta1.KeyVaultProperties{KeyName: kv.KeyName, KeyVersion: kv.KeyVersion, KeyVaultURI: kv.KeyVaultURI}
+	}
+	return out
+}
+
+func encryptionFromHub(e *v1beta1.Encryption) *Encryption {
+	if e == nil {
+		return nil
+	}
+	out := &Encryption{KeySource: storage.KeySource(e.KeySource)}
+	if e.Services != nil {
+		out.Services = &EnabledEncryptionServices{Blob: e.Services.Blob, File: e.Services.File}
+	}
+	if kv := e.KeyVaultProperties; kv != nil {
+		out.KeyVaultProperties = &KeyVaultProperties{KeyName: kv.KeyName, KeyVersion: kv.KeyVersion, KeyVaultURI: kv.KeyVaultURI}
+	}
+	return out
+}
+
+func networkRuleSetToHub(n *NetworkRuleSet) *v1beta1.NetworkRuleSet {
+	if n == nil {
+		return nil
+	}
+	out := &v1beta1.NetworkRuleSet{Bypass: string(n.Bypass), DefaultAction: string(n.DefaultAction)}
+	for _, r := range n.VirtualNetworkRules {
+		out.VirtualNetworkRules = append(out.VirtualNetworkRules, v1beta1.VirtualNetworkRule{VirtualNetworkResourceID: r.VirtualNetworkResourceID, Action: string(r.Action)})
+	}
+	for _, r := range n.IPRules {
+		out.IPRules = append(out.IPRules, v1beta1.IPRule{IPAddressOrRange: r.IPAddressOrRange, Action: string(r.Action)})
+	}
+	return out
+}
+
+func networkRuleSetFromHub(n *v1beta1.NetworkRuleSet) *NetworkRuleSet {
+	if n == nil {
+		return nil
+	}
+	out := &NetworkRuleSet{Bypass: storage.Bypass(n.Bypass), DefaultAction: storage.DefaultAction(n.DefaultAction)}
+	for _, r := range n.VirtualNetworkRules {
+		out.VirtualNetworkRules = append(out.VirtualNetworkRules, VirtualNetworkRule{VirtualNetworkResourceID: r.VirtualNetworkResourceID, Action: storage.Action(r.Action)})
+	}
+	for _, r := range n.IPRules {
+		out.IPRules = append(out.IPRules, IPRule{IPAddressOrRange: r.IPAddressOrRange, Action: storage.Action(r.Action)})
+	}
+	return out
+}
+
+func endpointsToHub(e *Endpoints) *v1beta1.Endpoints {
+	if e == nil {
+		return nil
+	}
+	return &v1beta1.Endpoints{Blob: e.Blob, Queue: e.Queue, Table: e.Table, File: e.File}
+}
+
+func endpointsFromHub(e *v1beta1.Endpoints) *Endpoints {
+	if e == nil {
+		return nil
+	}
+	return &Endpoints{Blob: e.Blob, Queue: e.Queue, Table: e.Table, File: e.File}
+}
@@ -0,0 +1,261 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha3
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/services/storage/mgmt/2017-06-01/storage"
+	"github.com/Azure/azure-storage-blob-go/azblob"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+
+	"github.com/crossplane-contrib/provider-azure/apis/storage/v1beta1"
+	apisv1alpha3 "github.com/crossplane-contrib/provider-azure/apis/v1alpha3"
+)
+
+var created = metav1.NewTime(time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC))
+
+func account() *Account {
+	return &Account{
+		ObjectMeta: metav1.ObjectMeta{Name: "coolaccount", Annotations: map[string]string{"cool": "very"}},
+		Spec: AccountSpec{
+			ResourceSpec: xpv1.ResourceSpec{ProviderConfigReference: &xpv1.Reference{Name: "default"}},
+			AccountParameters: AccountParameters{
+				ResourceGroupName: "cool-rg",
+				StorageAccountSpec: &StorageAccountSpec{
+					Identity: &Identity{PrincipalID: "cool-principal", TenantID: "cool-tenant", Type: "SystemAssigned"},
+					Kind:     storage.Storage,
+					Location: "westus",
+					Sku: &Sku{
+						Capabilities: []skuCapability{{Name: "supportsfileencryption", Value: "true"}},
+						Name:         storage.StandardLRS,
+						Tier:         storage.Standard,
+					},
+					StorageAccountSpecProperties: &StorageAccountSpecProperties{
+						AccessTier:             storage.Hot,
+						CustomDomain:           &CustomDomain{Name: "cool.example.org"},
+						EnableHTTPSTrafficOnly: true,
+						Encryption: &Encryption{
+							Services:  &EnabledEncryptionServices{Blob: true},
+							KeySource: storage.MicrosoftStorage,
+						},
+						NetworkRuleSet: &NetworkRuleSet{
+							Bypass:              storage.AzureServices,
+							VirtualNetworkRules: []VirtualNetworkRule{{VirtualNetworkResourceID: "cool-subnet", Action: storage.Allow}},
+							IPRules:             []IPRule{{IPAddressOrRange: "10.0.0.0/8", Action: storage.Allow}},
+							DefaultAction:       storage.DefaultActionDeny,
+						},
+					},
+					Tags: map[string]string{"env": "prod"},
+				},
+			},
+		},
+		Status: AccountStatus{
+			ResourceStatus: xpv1.ResourceStatus{ConditionedStatus: xpv1.ConditionedStatus{Conditions: []xpv1.Condition{xpv1.Available()}}},
+			StorageAccountStatus: &StorageAccountStatus{
+				ID:   "cool-id",
+				Name: "coolaccount",
+				StorageAccountStatusProperties: &StorageAccountStatusProperties{
+					CreationTime:      &created,
+					PrimaryEndpoints:  &Endpoints{Blob: "https://coolaccount.blob.core.windows.net/"},
+					ProvisioningState: storage.Succeeded,
+					StatusOfPrimary:   storage.Available,
+				},
+			},
+		},
+	}
+}
+
+func hubAccount() *v1beta1.Account {
+	return &v1beta1.Account{
+		ObjectMeta: metav1.ObjectMeta{Name: "coolaccount"},
+		Spec: v1beta1.AccountSpec{
+			ResourceSpec: xpv1.ResourceSpec{ProviderConfigReference: &xpv1.Reference{Name: "default"}},
+			ForProvider: v1beta1.AccountParameters{
+				ResourceGroupName:         "cool-rg",
+				ResourceGroupNameRef:      &xpv1.Reference{Name: "cool-rg"},
+				ResourceGroupNameSelector: &xpv1.Selector{MatchLabels: map[string]string{"cool": "very"}},
+				Location:                  "westus",
+				Kind:                      "Storage",
+				SKU:                       v1beta1.SKU{Name: "Standard_LRS"},
+				AccessTier:                to.StringPtr("Cool"),
+				EnableHTTPSTrafficOnly:    to.BoolPtr(true),
+			},
+		},
+		Status: v1beta1.AccountStatus{
+			AtProvider: v1beta1.AccountObservation{
+				ID:                "cool-id",
+				ProvisioningState: "Creating",
+				LastOperation:     apisv1alpha3.AsyncOperation{Method: "PUT", PollingURL: "https://example.org/op", StartTime: &created, Status: "InProgress"},
+			},
+			LastFailedRequest: &apisv1alpha3.FailedRequest{RequestID: "cool-request", CorrelationID: "cool-correlation", Time: created},
+		},
+	}
+}
+
+func TestAccountConvertTo(t *testing.T) {
+	hub := &v1beta1.Account{}
+	if err := account().ConvertTo(hub); err != nil {
+		t.Fatalf("ConvertTo(...): %s", err)
+	}
+
+	want := v1beta1.AccountParameters{
+		ResourceGroupName:      "cool-rg",
+		Location:               "westus",
+		Kind:                   "Storage",
+		SKU:                    v1beta1.SKU{Name: "Standard_LRS", Tier: "Standard"},
+		Identity:               &v1beta1.Identity{Type: "SystemAssigned"},
+		AccessTier:             to.StringPtr("Hot"),
+		CustomDomain:           &v1beta1.CustomDomain{Name: "cool.example.org"},
+		EnableHTTPSTrafficOnly: to.BoolPtr(true),
+		Encryption: &v1beta1.Encryption{
+			Services:  &v1beta1.EnabledEncryptionServices{Blob: true},
+			KeySource: "Microsoft.Storage",
+		},
+		NetworkRuleSet: &v1beta1.NetworkRuleSet{
+			Bypass:              "AzureServices",
+			VirtualNetworkRules: []v1beta1.VirtualNetworkRule{{VirtualNetworkResourceID: "cool-subnet", Action: "Allow"}},
+			IPRules:             []v1beta1.IPRule{{IPAddressOrRange: "10.0.0.0/8", Action: "Allow"}},
+			DefaultAction:       "Deny",
+		},
+		Tags: map[string]string{"env": "prod"},
+	}
+	if diff := cmp.Diff(want, hub.Spec.ForProvider); diff != "" {
+		t.Errorf("\nConvertTo(...): -want forProvider, +got forProvider:\n%s", diff)
+	}
+	if _, ok := hub.GetAnnotations()[AnnotationKeyConversionData]; !ok {
+		t.Errorf("\nConvertTo(...): fields that v1beta1 can't represent should be preserved in the %s annotation", AnnotationKeyConversionData)
+	}
+}
+
+func TestAccountRoundTrip(t *testing.T) {
+	t.Run("FromSpoke", func(t *testing.T) {
+		want := account()
+		hub := &v1beta1.Account{}
+		if err := want.DeepCopy().ConvertTo(hub); err != nil {
+			t.Fatalf("ConvertTo(...): %s", err)
+		}
+		got := &Account{}
+		if err := got.ConvertFrom(hub); err != nil {
+			t.Fatalf("ConvertFrom(...): %s", err)
+		}
+		if diff := cmp.Diff(want, got, cmpopts.EquateEmpty()); diff != "" {
+			t.Errorf("\nA v1alpha3 Account should survive a round trip through v1beta1: -want, +got:\n%s", diff)
+		}
+	})
+	t.Run("FromHub", func(t *testing.T) {
+		want := hubAccount()
+		spoke := &Account{}
+		if err := spoke.ConvertFrom(want.DeepCopy()); err != nil {
+			t.Fatalf("ConvertFrom(...): %s", err)
+		}
+		got := &v1beta1.Account{}
+		if err := spoke.ConvertTo(got); err != nil {
+			t.Fatalf("ConvertTo(...): %s", err)
+		}
+		if diff := cmp.Diff(want, got, cmpopts.EquateEmpty()); diff != "" {
+			t.Errorf("\nA v1beta1 Account should survive a round trip through v1alpha3: -want, +got:\n%s", diff)
+		}
+	})
+}
+
+func TestContainerRoundTrip(t *testing.T) {
+	t.Run("FromSpoke", func(t *testing.T) {
+		want := &Container{
+			ObjectMeta: metav1.ObjectMeta{Name: "cool-container"},
+			Spec: ContainerSpec{
+				ResourceSpec: xpv1.ResourceSpec{ProviderConfigReference: &xpv1.Reference{Name: "coolaccount"}},
+				ContainerParameters: ContainerParameters{
+					Metadata:         azblob.Metadata{"cool": "very"},
+					PublicAccessType: azblob.PublicAccessBlob,
+				},
+			},
+		}
+		hub := &v1beta1.Container{}
+		if err := want.DeepCopy().ConvertTo(hub); err != nil {
+			t.Fatalf("ConvertTo(...): %s", err)
+		}
+		wantSpec := v1beta1.ContainerSpec{
+			ResourceSpec: xpv1.ResourceSpec{ProviderConfigReference: &xpv1.Reference{Name: "default"}},
+			ForProvider: v1beta1.ContainerParameters{
+				AccountNameRef:   &xpv1.Reference{Name: "coolaccount"},
+				Metadata:         map[string]string{"cool": "very"},
+				PublicAccessType: "blob",
+			},
+		}
+		if diff := cmp.Diff(wantSpec, hub.Spec); diff != "" {
+			t.Errorf("\nThe Account of a v1alpha3 Container should be the accountNameRef of a v1beta1 Container: -want spec, +got spec:\n%s", diff)
+		}
+		if _, ok := hub.GetAnnotations()[v1beta1.AnnotationKeyInheritProviderConfig]; !ok {
+			t.Errorf("\nA v1beta1 Container should inherit the ProviderConfig of the Account of the v1alpha3 Container it was converted from")
+		}
+		got := &Container{}
+		if err := got.ConvertFrom(hub); err != nil {
+			t.Fatalf("ConvertFrom(...): %s", err)
+		}
+		if diff := cmp.Diff(want, got, cmpopts.EquateEmpty()); diff != "" {
+			t.Errorf("\nA v1alpha3 Container should survive a round trip through v1beta1: -want, +got:\n%s", diff)
+		}
+	})
+	t.Run("FromHub", func(t *testing.T) {
+		locked := true
+		want := &v1beta1.Container{
+			ObjectMeta: metav1.ObjectMeta{Name: "cool-container"},
+			Spec: v1beta1.ContainerSpec{
+				ResourceSpec: xpv1.ResourceSpec{ProviderConfigReference: &xpv1.Reference{Name: "cool-pc"}},
+				ForProvider: v1beta1.ContainerParameters{
+					ResourceGroupName: "cool-rg",
+					AccountName:       "coolaccount",
+					AccountNameRef:    &xpv1.Reference{Name: "cool-account"},
+					PublicAccessType:  "container",
+					ImmutabilityPolicy: &v1beta1.ImmutabilityPolicy{
+						ImmutabilityPeriodSinceCreationInDays: 7,
+						Locked:                                &locked,
+					},
+					LegalHold: &v1beta1.LegalHold{Tags: []string{"audit"}},
+				},
+			},
+			Status: v1beta1.ContainerStatus{
+				AtProvider: v1beta1.ContainerObservation{
+					ID:            "cool-id",
+					LastModified:  &created,
+					LeaseState:    "available",
+					HasLegalHold:  true,
+					LegalHoldTags: []string{"audit"},
+				},
+				LastFailedRequest: &apisv1alpha3.FailedRequest{RequestID: "cool-request", CorrelationID: "cool-correlation", Time: created},
+			},
+		}
+		spoke := &Container{}
+		if err := spoke.ConvertFrom(want.DeepCopy()); err != nil {
+			t.Fatalf("ConvertFrom(...): %s", err)
+		}
+		got := &v1beta1.Container{}
+		if err := spoke.ConvertTo(got); err != nil {
+			t.Fatalf("ConvertTo(...): %s", err)
+		}
+		if diff := cmp.Diff(want, got, cmpopts.EquateEmpty()); diff != "" {
+			t.Errorf("\nA v1beta1 Container should survive a round trip through v1alpha3: -want, +got:\n%s", diff)
+		}
+	})
+}
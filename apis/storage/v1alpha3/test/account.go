@@ -65,6 +65,12 @@ func (ta *MockAccount) WithDeleteTimestamp(t metav1.Time) *MockAccount {
 	return ta
 }
 
+// WithAnnotations adds annotations
+func (ta *MockAccount) WithAnnotations(a map[string]string) *MockAccount {
+	meta.AddAnnotations(ta.Account, a)
+	return ta
+}
+
 // WithFinalizer sets finalizer
 func (ta *MockAccount) WithFinalizer(f string) *MockAccount {
 	ta.Account.ObjectMeta.Finalizers = append(ta.Account.ObjectMeta.Finalizers, f)
@@ -96,7 +96,9 @@ type ContainerStatus struct {
 // +kubebuilder:object:root=true
 
 // A Container is a managed resource that represents an Azure Blob Storage
-// Container.
+// Container. A v1alpha3 Container uses its Account as its ProviderConfig. When
+// it is converted to v1beta1 the Account becomes its accountNameRef, and the
+// Container inherits the ProviderConfig of that Account.
 // +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
 // +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
 // +kubebuilder:printcolumn:name="STORAGE_ACCOUNT",type="string",JSONPath=".spec.accountRef.name"
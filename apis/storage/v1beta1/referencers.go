@@ -0,0 +1,112 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/reference"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	"github.com/crossplane-contrib/provider-azure/apis/v1alpha3"
+)
+
+// ResolveReferences of this Account.
+func (mg *Account) ResolveReferences(ctx context.Context, c client.Reader) error {
+	r := reference.NewAPIResolver(c, mg)
+
+	// Resolve spec.forProvider.resourceGroupName
+	rsp, err := r.Resolve(ctx, reference.ResolutionRequest{
+		CurrentValue: mg.Spec.ForProvider.ResourceGroupName,
+		Reference:    mg.Spec.ForProvider.ResourceGroupNameRef,
+		Selector:     mg.Spec.ForProvider.ResourceGroupNameSelector,
+		To:           reference.To{Managed: &v1alpha3.ResourceGroup{}, List: &v1alpha3.ResourceGroupList{}},
+		Extract:      reference.ExternalName(),
+	})
+	if err != nil {
+		return errors.Wrap(err, "spec.forProvider.resourceGroupName")
+	}
+	mg.Spec.ForProvider.ResourceGroupName = rsp.ResolvedValue
+	mg.Spec.ForProvider.ResourceGroupNameRef = rsp.ResolvedReference
+
+	return nil
+}
+
+// AccountResourceGroupName extracts the resource group name of an Account.
+func AccountResourceGroupName() reference.ExtractValueFn {
+	return func(mg resource.Managed) string {
+		a, ok := mg.(*Account)
+		if !ok {
+			return ""
+		}
+		return a.Spec.ForProvider.ResourceGroupName
+	}
+}
+
+// ResolveReferences of this Container.
+func (mg *Container) ResolveReferences(ctx context.Context, c client.Reader) error {
+	r := reference.NewAPIResolver(c, mg)
+
+	// Resolve spec.forProvider.resourceGroupName
+	rsp, err := r.Resolve(ctx, reference.ResolutionRequest{
+		CurrentValue: mg.Spec.ForProvider.ResourceGroupName,
+		Reference:    mg.Spec.ForProvider.ResourceGroupNameRef,
+		Selector:     mg.Spec.ForProvider.ResourceGroupNameSelector,
+		To:           reference.To{Managed: &v1alpha3.ResourceGroup{}, List: &v1alpha3.ResourceGroupList{}},
+		Extract:      reference.ExternalName(),
+	})
+	if err != nil {
+		return errors.Wrap(err, "spec.forProvider.resourceGroupName")
+	}
+	mg.Spec.ForProvider.ResourceGroupName = rsp.ResolvedValue
+	mg.Spec.ForProvider.ResourceGroupNameRef = rsp.ResolvedReference
+
+	// Resolve spec.forProvider.accountName
+	rsp, err = r.Resolve(ctx, reference.ResolutionRequest{
+		CurrentValue: mg.Spec.ForProvider.AccountName,
+		Reference:    mg.Spec.ForProvider.AccountNameRef,
+		Selector:     mg.Spec.ForProvider.AccountNameSelector,
+		To:           reference.To{Managed: &Account{}, List: &AccountList{}},
+		Extract:      reference.ExternalName(),
+	})
+	if err != nil {
+		return errors.Wrap(err, "spec.forProvider.accountName")
+	}
+	mg.Spec.ForProvider.AccountName = rsp.ResolvedValue
+	mg.Spec.ForProvider.AccountNameRef = rsp.ResolvedReference
+
+	// Resolve spec.forProvider.resourceGroupName from the referenced Account
+	// if the resource group is not specified otherwise.
+	if mg.Spec.ForProvider.ResourceGroupNameRef != nil || mg.Spec.ForProvider.ResourceGroupNameSelector != nil {
+		return nil
+	}
+	rsp, err = r.Resolve(ctx, reference.ResolutionRequest{
+		CurrentValue: mg.Spec.ForProvider.ResourceGroupName,
+		Reference:    mg.Spec.ForProvider.AccountNameRef,
+		To:           reference.To{Managed: &Account{}, List: &AccountList{}},
+		Extract:      AccountResourceGroupName(),
+	})
+	if err != nil {
+		return errors.Wrap(err, "spec.forProvider.resourceGroupName")
+	}
+	mg.Spec.ForProvider.ResourceGroupName = rsp.ResolvedValue
+
+	return nil
+}
@@ -0,0 +1,513 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+
+	apisv1alpha3 "github.com/crossplane-contrib/provider-azure/apis/v1alpha3"
+)
+
+// A SKU of a storage account.
+type SKU struct {
+	// Name of the SKU. Note that in older versions, the SKU name was called
+	// accountType.
+	// +kubebuilder:validation:Enum=Standard_LRS;Standard_GRS;Standard_RAGRS;Standard_ZRS;Premium_LRS
+	Name string `json:"name"`
+
+	// Tier of the SKU. It is derived from the SKU name.
+	// +kubebuilder:validation:Enum=Standard;Premium
+	// +optional
+	Tier string `json:"tier,omitempty"`
+}
+
+// An Identity of a storage account.
+type Identity struct {
+	// Type of the identity.
+	// +kubebuilder:validation:Enum=SystemAssigned
+	Type string `json:"type"`
+}
+
+// A CustomDomain assigned to a storage account.
+type CustomDomain struct {
+	// Name of the custom domain. It is the CNAME source.
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// UseSubDomainName indicates whether indirect CNAME validation is
+	// enabled.
+	// +optional
+	UseSubDomainName bool `json:"useSubDomainName,omitempty"`
+}
+
+// EnabledEncryptionServices are the services that encrypt their data.
+type EnabledEncryptionServices struct {
+	// Blob specifies whether the blob service encrypts its data.
+	// +optional
+	Blob bool `json:"blob,omitempty"`
+
+	// File specifies whether the file service encrypts its data.
+	// +optional
+	File bool `json:"file,omitempty"`
+}
+
+// KeyVaultProperties identify the key vault key that encrypts a storage
+// account.
+type KeyVaultProperties struct {
+	// KeyName - The name of KeyVault key.
+	// +optional
+	KeyName string `json:"keyname,omitempty"`
+
+	// KeyVersion - The version of KeyVault key.
+	// +optional
+	KeyVersion string `json:"keyversion,omitempty"`
+
+	// KeyVaultURI - The Uri of KeyVault.
+	// +optional
+	KeyVaultURI string `json:"keyvaulturi,omitempty"`
+}
+
+// Encryption settings of a storage account.
+type Encryption struct {
+	// Services that encrypt their data.
+	// +optional
+	Services *EnabledEncryptionServices `json:"services,omitempty"`
+
+	// KeySource - The encryption keySource (provider).
+	// +kubebuilder:validation:Enum=Microsoft.Storage;Microsoft.Keyvault
+	// +optional
+	KeySource string `json:"keySource,omitempty"`
+
+	// KeyVaultProperties - Properties provided by key vault.
+	// +optional
+	KeyVaultProperties *KeyVaultProperties `json:"keyvaultproperties,omitempty"`
+}
+
+// A VirtualNetworkRule allows traffic from a subnet.
+type VirtualNetworkRule struct {
+	// VirtualNetworkResourceID - Resource ID of a subnet,
+	// for example: /subscriptions/{subscriptionId}/resourceGroups/{groupName}/providers/Microsoft.Network/virtualNetworks/{vnetName}/subnets/{subnetName}.
+	VirtualNetworkResourceID string `json:"id"`
+
+	// Action of the virtual network rule.
+	// +kubebuilder:validation:Enum=Allow
+	// +optional
+	Action string `json:"action,omitempty"`
+}
+
+// An IPRule allows traffic from an IP address or range.
+type IPRule struct {
+	// IPAddressOrRange - Specifies the IP or IP range in CIDR format.
+	// Only IPV4 address is allowed.
+	IPAddressOrRange string `json:"value"`
+
+	// Action of the IP rule.
+	// +kubebuilder:validation:Enum=Allow
+	// +optional
+	Action string `json:"action,omitempty"`
+}
+
+// A NetworkRuleSet restricts the network traffic to a storage account.
+type NetworkRuleSet struct {
+	// Bypass - Specifies whether traffic is bypassed for Logging/Metrics/AzureServices.
+	// Possible values are any combination of Logging|Metrics|AzureServices
+	// (For example, "Logging, Metrics"), or None to bypass none of those traffics.
+	// +optional
+	Bypass string `json:"bypass,omitempty"`
+
+	// VirtualNetworkRules - Sets the virtual network rules
+	// +optional
+	VirtualNetworkRules []VirtualNetworkRule `json:"virtualNetworkRules,omitempty"`
+
+	// IPRules - Sets the IP ACL rules
+	// +optional
+	IPRules []IPRule `json:"ipRules,omitempty"`
+
+	// DefaultAction - Specifies the default action of allow or deny when no
+	// other rules match.
+	// +kubebuilder:validation:Enum=Allow;Deny
+	DefaultAction string `json:"defaultAction"`
+}
+
+// AccountParameters define the desired state of an Azure storage account.
+// https://docs.microsoft.com/en-us/rest/api/storagerp/storage-accounts/create
+type AccountParameters struct {
+	// ResourceGroupName in which to create this resource.
+	// +immutable
+	ResourceGroupName string `json:"resourceGroupName,omitempty"`
+
+	// ResourceGroupNameRef to fetch resource group name.
+	// +immutable
+	ResourceGroupNameRef *xpv1.Reference `json:"resourceGroupNameRef,omitempty"`
+
+	// ResourceGroupNameSelector to select a reference to a resource group.
+	// +immutable
+	ResourceGroupNameSelector *xpv1.Selector `json:"resourceGroupNameSelector,omitempty"`
+
+	// Location in which to create this resource.
+	// +immutable
+	Location string `json:"location"`
+
+	// Kind of storage account.
+	// +kubebuilder:validation:Enum=Storage;BlobStorage
+	Kind string `json:"kind"`
+
+	// SKU of the storage account.
+	SKU SKU `json:"sku"`
+
+	// Identity of the storage account.
+	// +optional
+	Identity *Identity `json:"identity,omitempty"`
+
+	// AccessTier used for billing. Required for storage accounts of kind
+	// BlobStorage.
+	// +kubebuilder:validation:Enum=Hot;Cool
+	// +optional
+	AccessTier *string `json:"accessTier,omitempty"`
+
+	// CustomDomain assigned to the storage account. Only one custom domain is
+	// supported per storage account at this time. To clear the existing
+	// custom domain, use an empty string for the custom domain name.
+	// +optional
+	CustomDomain *CustomDomain `json:"customDomain,omitempty"`
+
+	// EnableHTTPSTrafficOnly allows only HTTPS traffic to the storage account.
+	// +optional
+	EnableHTTPSTrafficOnly *bool `json:"supportsHttpsTrafficOnly,omitempty"`
+
+	// Encryption settings of the storage account. The encryption settings
+	// of the account remain the same if they are unspecified.
+	// +optional
+	Encryption *Encryption `json:"encryption,omitempty"`
+
+	// NetworkRuleSet restricts the network traffic to the storage account.
+	// +optional
+	NetworkRuleSet *NetworkRuleSet `json:"networkAcls,omitempty"`
+
+	// Tags - Resource tags.
+	// +optional
+	Tags map[string]string `json:"tags,omitempty"`
+}
+
+// An AccountSpec defines the desired state of an Account.
+type AccountSpec struct {
+	xpv1.ResourceSpec `json:",inline"`
+	ForProvider       AccountParameters `json:"forProvider"`
+}
+
+// Endpoints of the storage services of an account.
+type Endpoints struct {
+	// Blob - the blob endpoint.
+	Blob string `json:"blob,omitempty"`
+	// Queue - the queue endpoint.
+	Queue string `json:"queue,omitempty"`
+	// Table - the table endpoint.
+	Table string `json:"table,omitempty"`
+	// File - the file endpoint.
+	File string `json:"file,omitempty"`
+}
+
+// AccountObservation represents the observed state of the Account object in
+// Azure.
+type AccountObservation struct {
+	// ID - Resource ID.
+	ID string `json:"id,omitempty"`
+
+	// Name - Resource name.
+	Name string `json:"name,omitempty"`
+
+	// Type - Resource type.
+	Type string `json:"type,omitempty"`
+
+	// CreationTime - the creation date and time of the storage account in UTC.
+	CreationTime *metav1.Time `json:"creationTime,omitempty"`
+
+	// LastGeoFailoverTime - the timestamp of the most recent instance of a
+	// failover to the secondary location. Only the most recent timestamp is retained.
+	// This element is not returned if there has never been a failover instance.
+	// Only available if the accountType is Standard_GRS or Standard_RAGRS.
+	LastGeoFailoverTime *metav1.Time `json:"lastGeoFailoverTime,omitempty"`
+
+	// PrimaryEndpoints - the URLs that are used to perform a retrieval of a public blob, queue, or table object.
+	// Note that Standard_ZRS and Premium_LRS accounts only return the blob endpoint.
+	PrimaryEndpoints *Endpoints `json:"primaryEndpoints,omitempty"`
+
+	// PrimaryLocation - the location of the primary data center for the storage account.
+	PrimaryLocation string `json:"primaryLocation,omitempty"`
+
+	// ProvisioningState - the status of the storage account at the time the
+	// operation was called. Possible values include: 'Creating',
+	// 'ResolvingDNS', 'Succeeded'
+	ProvisioningState string `json:"provisioningState,omitempty"`
+
+	// SecondaryEndpoints - the URLs that are used to perform a retrieval of a
+	// public blob, queue, or table object from the secondary location of the
+	// storage account. Only available if the Sku name is Standard_RAGRS.
+	SecondaryEndpoints *Endpoints `json:"secondaryEndpoints,omitempty"`
+
+	// SecondaryLocation - the location of the geo-replicated secondary for the
+	// storage account. Only available if the accountType is Standard_GRS or Standard_RAGRS.
+	SecondaryLocation string `json:"secondaryLocation,omitempty"`
+
+	// StatusOfPrimary - the status indicating whether the primary location
+	// of the storage account is available or unavailable.
+	// Possible values include: 'Available', 'Unavailable'
+	StatusOfPrimary string `json:"statusOfPrimary,omitempty"`
+
+	// StatusOfSecondary - the status indicating whether the secondary location
+	// of the storage account is available or unavailable.
+	// Only available if the Sku name is Standard_GRS or Standard_RAGRS.
+	// Possible values include: 'Available', 'Unavailable'
+	StatusOfSecondary string `json:"statusOfSecondary,omitempty"`
+
+	// LastOperation represents the state of the last operation started by the
+	// controller.
+	LastOperation apisv1alpha3.AsyncOperation `json:"lastOperation,omitempty"`
+}
+
+// An AccountStatus represents the observed state of an Account.
+type AccountStatus struct {
+	xpv1.ResourceStatus `json:",inline"`
+	AtProvider          AccountObservation `json:"atProvider,omitempty"`
+
+	// LastFailedRequest identifies the most recent request to Azure that
+	// failed.
+	// +optional
+	LastFailedRequest *apisv1alpha3.FailedRequest `json:"lastFailedRequest,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// An Account is a managed resource that represents an Azure storage account.
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+// +kubebuilder:printcolumn:name="STATE",type="string",JSONPath=".status.atProvider.provisioningState"
+// +kubebuilder:printcolumn:name="LOCATION",type="string",JSONPath=".spec.forProvider.location"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:subresource:status
+// +kubebuilder:storageversion
+// +kubebuilder:resource:scope=Cluster,categories={crossplane,managed,azure}
+type Account struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   AccountSpec   `json:"spec"`
+	Status AccountStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// AccountList contains a list of Account.
+type AccountList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Account `json:"items"`
+}
+
+// ContainerParameters define the desired state of an Azure blob container.
+type ContainerParameters struct {
+	// ResourceGroupName of the storage account that contains the container.
+	// It is the resource group of the Account referenced by accountNameRef
+	// or accountNameSelector if it is unspecified.
+	// +immutable
+	// +optional
+	ResourceGroupName string `json:"resourceGroupName,omitempty"`
+
+	// ResourceGroupNameRef to fetch resource group name.
+	// +immutable
+	// +optional
+	ResourceGroupNameRef *xpv1.Reference `json:"resourceGroupNameRef,omitempty"`
+
+	// ResourceGroupNameSelector to select a reference to a resource group.
+	// +immutable
+	// +optional
+	ResourceGroupNameSelector *xpv1.Selector `json:"resourceGroupNameSelector,omitempty"`
+
+	// AccountName of the storage account that contains the container.
+	// +immutable
+	// +optional
+	AccountName string `json:"accountName,omitempty"`
+
+	// AccountNameRef to fetch the storage account name.
+	// +immutable
+	// +optional
+	AccountNameRef *xpv1.Reference `json:"accountNameRef,omitempty"`
+
+	// AccountNameSelector to select a reference to a storage account.
+	// +immutable
+	// +optional
+	AccountNameSelector *xpv1.Selector `json:"accountNameSelector,omitempty"`
+
+	// Metadata of the container.
+	// +optional
+	Metadata map[string]string `json:"metadata,omitempty"`
+
+	// PublicAccessType of the container. Data in the container may only be
+	// accessed by authorized requests if it is unspecified.
+	// +kubebuilder:validation:Enum=blob;container
+	// +optional
+	PublicAccessType string `json:"publicAccessType,omitempty"`
+
+	// ImmutabilityPolicy is the time-based retention policy of the container.
+	// It is late-initialized from the container if unspecified. An unlocked
+	// policy is deleted if this is set to an empty policy, or removed once the
+	// policy has been specified or late-initialized.
+	// +optional
+	ImmutabilityPolicy *ImmutabilityPolicy `json:"immutabilityPolicy,omitempty"`
+
+	// LegalHold of the container. It is late-initialized from the container
+	// if unspecified.
+	// +optional
+	LegalHold *LegalHold `json:"legalHold,omitempty"`
+}
+
+// An ImmutabilityPolicy prevents blobs in a container from being modified or
+// deleted until their retention period has elapsed.
+type ImmutabilityPolicy struct {
+	// ImmutabilityPeriodSinceCreationInDays is the retention period of blobs
+	// in the container. The period of a locked policy may only be extended.
+	// A policy without a period is empty.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=146000
+	// +optional
+	ImmutabilityPeriodSinceCreationInDays int32 `json:"immutabilityPeriodSinceCreationInDays,omitempty"`
+
+	// AllowProtectedAppendWrites allows new blocks to be written to append
+	// blobs while maintaining immutability protection. It may only be changed
+	// while the policy is unlocked.
+	// +optional
+	AllowProtectedAppendWrites *bool `json:"allowProtectedAppendWrites,omitempty"`
+
+	// Locked policies can not be unlocked or deleted. Locking a policy is
+	// required for compliance with SEC 17a-4(f) and similar regulations.
+	// +optional
+	Locked *bool `json:"locked,omitempty"`
+}
+
+// A LegalHold prevents blobs in a container from being modified or deleted
+// while it has at least one tag.
+type LegalHold struct {
+	// Tags of the legal hold. Each tag must be 3 to 23 alphanumeric
+	// characters, and is normalized to lower case. The legal hold is cleared
+	// if no tags are specified.
+	// +optional
+	Tags []string `json:"tags,omitempty"`
+}
+
+// AnnotationKeyInheritProviderConfig marks a Container that uses the
+// ProviderConfig of the Account its accountNameRef refers to. A v1alpha3
+// Container has no ProviderConfig of its own, so it is marked when it is
+// converted to v1beta1, and the Container controller replaces its
+// providerConfigRef with that of the Account before it connects to Azure.
+const AnnotationKeyInheritProviderConfig = "storage.azure.crossplane.io/inherit-provider-config"
+
+// A ContainerSpec defines the desired state of a Container.
+type ContainerSpec struct {
+	xpv1.ResourceSpec `json:",inline"`
+	ForProvider       ContainerParameters `json:"forProvider"`
+}
+
+// ContainerObservation represents the observed state of the Container object
+// in Azure.
+type ContainerObservation struct {
+	// ID of the container.
+	ID string `json:"id,omitempty"`
+
+	// LastModified is the time the container or its properties were last
+	// modified.
+	LastModified *metav1.Time `json:"lastModified,omitempty"`
+
+	// LeaseState of the container. Possible values include: 'available',
+	// 'leased', 'expired', 'breaking', 'broken'
+	LeaseState string `json:"leaseState,omitempty"`
+
+	// LeaseStatus of the container. Possible values include: 'locked',
+	// 'unlocked'
+	LeaseStatus string `json:"leaseStatus,omitempty"`
+
+	// HasImmutabilityPolicy is true if the container has an immutability
+	// policy.
+	HasImmutabilityPolicy bool `json:"hasImmutabilityPolicy,omitempty"`
+
+	// HasLegalHold is true if the container has at least one legal hold tag.
+	HasLegalHold bool `json:"hasLegalHold,omitempty"`
+
+	// ImmutabilityPolicy of the container.
+	ImmutabilityPolicy *ImmutabilityPolicyObservation `json:"immutabilityPolicy,omitempty"`
+
+	// LegalHoldTags of the container.
+	LegalHoldTags []string `json:"legalHoldTags,omitempty"`
+}
+
+// ImmutabilityPolicyObservation represents the observed state of the
+// immutability policy of a container.
+type ImmutabilityPolicyObservation struct {
+	// ImmutabilityPeriodSinceCreationInDays is the retention period of blobs
+	// in the container.
+	ImmutabilityPeriodSinceCreationInDays int32 `json:"immutabilityPeriodSinceCreationInDays,omitempty"`
+
+	// AllowProtectedAppendWrites is true if new blocks may be written to
+	// append blobs.
+	AllowProtectedAppendWrites bool `json:"allowProtectedAppendWrites,omitempty"`
+
+	// State of the policy. Possible values include: 'Locked', 'Unlocked'
+	State string `json:"state,omitempty"`
+
+	// Etag of the policy.
+	Etag string `json:"etag,omitempty"`
+}
+
+// A ContainerStatus represents the observed status of a Container.
+type ContainerStatus struct {
+	xpv1.ResourceStatus `json:",inline"`
+	AtProvider          ContainerObservation `json:"atProvider,omitempty"`
+
+	// LastFailedRequest identifies the most recent request to Azure that
+	// failed.
+	// +optional
+	LastFailedRequest *apisv1alpha3.FailedRequest `json:"lastFailedRequest,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// A Container is a managed resource that represents an Azure blob container.
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+// +kubebuilder:printcolumn:name="STORAGE_ACCOUNT",type="string",JSONPath=".spec.forProvider.accountName"
+// +kubebuilder:printcolumn:name="PUBLIC_ACCESS_TYPE",type="string",JSONPath=".spec.forProvider.publicAccessType"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:subresource:status
+// +kubebuilder:storageversion
+// +kubebuilder:resource:scope=Cluster,categories={crossplane,managed,azure}
+type Container struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ContainerSpec   `json:"spec"`
+	Status ContainerStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ContainerList contains a list of Container.
+type ContainerList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Container `json:"items"`
+}
@@ -67,6 +67,19 @@ type ResourceGroupSpec struct {
 	// Location of the resource group. See the  official list of valid regions -
 	// https://azure.microsoft.com/en-us/global-infrastructure/regions/
 	Location string `json:"location"`
+
+	// Tags - Resource tags.
+	// +optional
+	Tags map[string]string `json:"tags,omitempty"`
+}
+
+// A ResourceGroupObservation represents the observed state of a
+// ResourceGroup that is not reported by Azure as part of the resource group
+// itself.
+type ResourceGroupObservation struct {
+	// LastOperation represents the state of the last operation started by the
+	// controller.
+	LastOperation AsyncOperation `json:"lastOperation,omitempty"`
 }
 
 // A ResourceGroupStatus represents the observed status of a ResourceGroup.
@@ -75,6 +88,15 @@ type ResourceGroupStatus struct {
 
 	// ProvisioningState - The provisioning state of the resource group.
 	ProvisioningState ProvisioningState `json:"provisioningState,omitempty"`
+
+	// AtProvider reports the observed state of the resource group that is not
+	// reported by Azure as part of the resource group itself.
+	AtProvider ResourceGroupObservation `json:"atProvider,omitempty"`
+
+	// LastFailedRequest identifies the most recent request to Azure that
+	// failed.
+	// +optional
+	LastFailedRequest *FailedRequest `json:"lastFailedRequest,omitempty"`
 }
 
 // A ResourceGroup is a managed resource that represents an Azure Resource
@@ -114,4 +136,24 @@ type AsyncOperation struct {
 
 	// ErrorMessage represents the error that occurred during the operation.
 	ErrorMessage string `json:"errorMessage,omitempty"`
+
+	// StartTime is the time at which the controller started the operation.
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+}
+
+// A FailedRequest identifies a request to Azure that failed, for example so
+// that it can be referred to in an Azure support request.
+type FailedRequest struct {
+	// RequestID is the ID Azure assigned to the request, as returned in its
+	// x-ms-request-id header.
+	// +optional
+	RequestID string `json:"requestId,omitempty"`
+
+	// CorrelationID is the ID Azure assigned to the operation the request
+	// was part of, as returned in its x-ms-correlation-request-id header.
+	// +optional
+	CorrelationID string `json:"correlationId,omitempty"`
+
+	// Time at which the request failed.
+	Time metav1.Time `json:"time"`
 }
@@ -28,6 +28,10 @@ import (
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AsyncOperation) DeepCopyInto(out *AsyncOperation) {
 	*out = *in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AsyncOperation.
@@ -40,6 +44,22 @@ func (in *AsyncOperation) DeepCopy() *AsyncOperation {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FailedRequest) DeepCopyInto(out *FailedRequest) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FailedRequest.
+func (in *FailedRequest) DeepCopy() *FailedRequest {
+	if in == nil {
+		return nil
+	}
+	out := new(FailedRequest)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Provider) DeepCopyInto(out *Provider) {
 	*out = *in
@@ -173,10 +193,33 @@ func (in *ResourceGroupList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceGroupObservation) DeepCopyInto(out *ResourceGroupObservation) {
+	*out = *in
+	in.LastOperation.DeepCopyInto(&out.LastOperation)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceGroupObservation.
+func (in *ResourceGroupObservation) DeepCopy() *ResourceGroupObservation {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceGroupObservation)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ResourceGroupSpec) DeepCopyInto(out *ResourceGroupSpec) {
 	*out = *in
 	in.ResourceSpec.DeepCopyInto(&out.ResourceSpec)
+	if in.Tags != nil {
+		in, out := &in.Tags, &out.Tags
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceGroupSpec.
@@ -193,6 +236,12 @@ func (in *ResourceGroupSpec) DeepCopy() *ResourceGroupSpec {
 func (in *ResourceGroupStatus) DeepCopyInto(out *ResourceGroupStatus) {
 	*out = *in
 	in.ResourceStatus.DeepCopyInto(&out.ResourceStatus)
+	in.AtProvider.DeepCopyInto(&out.AtProvider)
+	if in.LastFailedRequest != nil {
+		in, out := &in.LastFailedRequest, &out.LastFailedRequest
+		*out = new(FailedRequest)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceGroupStatus.
@@ -17,35 +17,206 @@ limitations under the License.
 package v1beta1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 )
 
+// CredentialsSourceOIDCTokenFile indicates that the credentials are a
+// projected service account token that is exchanged for an Azure AD token
+// using a federated identity credential.
+const CredentialsSourceOIDCTokenFile xpv1.CredentialsSource = "OIDCTokenFile"
+
+// CredentialsSourceManagedIdentity indicates that the credentials are
+// obtained from the Azure instance metadata service using the system-assigned
+// or a user-assigned managed identity of the host the provider runs on.
+const CredentialsSourceManagedIdentity xpv1.CredentialsSource = "ManagedIdentity"
+
+// DefaultOIDCTokenFilePath is the path at which the Azure AD workload identity
+// webhook projects the service account token.
+const DefaultOIDCTokenFilePath = "/var/run/secrets/azure/tokens/azure-identity-token"
+
+// A CloudName identifies an Azure cloud.
+type CloudName string
+
+// Azure clouds.
+const (
+	CloudAzurePublic       CloudName = "AzurePublic"
+	CloudAzureChina        CloudName = "AzureChina"
+	CloudAzureUSGovernment CloudName = "AzureUSGovernment"
+	CloudCustom            CloudName = "Custom"
+)
+
+// A Cloud selects the Azure cloud that the provider connects to.
+type Cloud struct {
+	// Name of the Azure cloud. The endpoints of a Custom cloud must be
+	// supplied explicitly.
+	// +kubebuilder:validation:Enum=AzurePublic;AzureChina;AzureUSGovernment;Custom
+	// +kubebuilder:default=AzurePublic
+	Name CloudName `json:"name"`
+
+	// Endpoints override the well-known endpoints of the named cloud. The
+	// activeDirectory and resourceManager endpoints are required when the
+	// cloud is Custom.
+	// +optional
+	Endpoints *CloudEndpoints `json:"endpoints,omitempty"`
+}
+
+// CloudEndpoints of an Azure cloud.
+type CloudEndpoints struct {
+	// ActiveDirectory is the Azure AD authority host, for example
+	// https://login.microsoftonline.com/.
+	// +optional
+	ActiveDirectory *string `json:"activeDirectory,omitempty"`
+
+	// ResourceManager is the Azure Resource Manager endpoint, for example
+	// https://management.azure.com/.
+	// +optional
+	ResourceManager *string `json:"resourceManager,omitempty"`
+
+	// Graph is the Azure AD Graph endpoint, for example
+	// https://graph.windows.net/.
+	// +optional
+	Graph *string `json:"graph,omitempty"`
+
+	// KeyVaultResource is the resource that Key Vault tokens are requested
+	// for, for example https://vault.azure.net.
+	// +optional
+	KeyVaultResource *string `json:"keyVaultResource,omitempty"`
+
+	// KeyVaultDNSSuffix is the DNS suffix of Key Vault vaults, for example
+	// vault.azure.net.
+	// +optional
+	KeyVaultDNSSuffix *string `json:"keyVaultDNSSuffix,omitempty"`
+
+	// StorageEndpointSuffix is the DNS suffix of storage account endpoints,
+	// for example core.windows.net.
+	// +optional
+	StorageEndpointSuffix *string `json:"storageEndpointSuffix,omitempty"`
+}
+
 // A ProviderConfigSpec defines the desired state of a ProviderConfig.
 type ProviderConfigSpec struct {
 	// Credentials required to authenticate to this provider.
 	Credentials ProviderCredentials `json:"credentials"`
+
+	// ClientID is the client ID of the Azure AD application or managed
+	// identity used to authenticate. Required when the credentials source is
+	// OIDCTokenFile. When the credentials source is ManagedIdentity it selects
+	// a user-assigned identity; the system-assigned identity is used if neither
+	// clientID nor managedIdentityResourceID is set.
+	// +optional
+	ClientID *string `json:"clientID,omitempty"`
+
+	// ManagedIdentityResourceID is the resource ID of the user-assigned
+	// managed identity used to authenticate when the credentials source is
+	// ManagedIdentity. It is mutually exclusive with clientID.
+	// +optional
+	ManagedIdentityResourceID *string `json:"managedIdentityResourceID,omitempty"`
+
+	// TenantID is the ID of the Azure AD tenant that the identity belongs to.
+	// Required when the credentials source is OIDCTokenFile.
+	// +optional
+	TenantID *string `json:"tenantID,omitempty"`
+
+	// SubscriptionID is the ID of the default Azure subscription that
+	// resources using this ProviderConfig are managed in. It takes precedence
+	// over the subscription ID found in the credentials, and may be overridden
+	// by a managed resource using the azure.crossplane.io/subscription-id
+	// annotation. Required when the credentials do not contain a subscription
+	// ID, e.g. when the credentials source is OIDCTokenFile or ManagedIdentity.
+	// +optional
+	SubscriptionID *string `json:"subscriptionID,omitempty"`
+
+	// Cloud is the Azure cloud that resources using this ProviderConfig are
+	// managed in. Endpoints found in the credentials are used if it is not
+	// set, falling back to the Azure public cloud.
+	// +optional
+	Cloud *Cloud `json:"cloud,omitempty"`
+
+	// DefaultTags are added to the tags of every taggable external resource
+	// managed using this ProviderConfig. Tags of a managed resource take
+	// precedence over default tags with the same key. The crossplane-kind,
+	// crossplane-name and crossplane-providerconfig tags are reserved; the
+	// provider adds them to every external resource to identify its managed
+	// resource.
+	// +optional
+	DefaultTags map[string]string `json:"defaultTags,omitempty"`
 }
 
 // ProviderCredentials required to authenticate.
 type ProviderCredentials struct {
 	// Source of the provider credentials.
-	// +kubebuilder:validation:Enum=None;Secret;Environment;Filesystem
+	// +kubebuilder:validation:Enum=None;Secret;Environment;Filesystem;OIDCTokenFile;ManagedIdentity
 	Source xpv1.CredentialsSource `json:"source"`
 
 	xpv1.CommonCredentialSelectors `json:",inline"`
+
+	// OIDCTokenFilePath is the path of the projected service account token
+	// that is exchanged for an Azure AD token when the credentials source is
+	// OIDCTokenFile. The file is read again every time the token is refreshed.
+	// +optional
+	OIDCTokenFilePath *string `json:"oidcTokenFilePath,omitempty"`
 }
 
 // A ProviderConfigStatus represents the status of a ProviderConfig.
 type ProviderConfigStatus struct {
 	xpv1.ProviderConfigStatus `json:",inline"`
+
+	// TenantID is the ID of the Azure AD tenant that issued the most recently
+	// acquired token.
+	// +optional
+	TenantID string `json:"tenantID,omitempty"`
+
+	// SubscriptionID is the ID of the Azure subscription that the credentials
+	// were most recently validated against.
+	// +optional
+	SubscriptionID string `json:"subscriptionID,omitempty"`
+
+	// TokenExpiry is the time at which the token acquired by the most recent
+	// validation that updated this status expires. Validations that change
+	// nothing else don't update it until the recorded token has expired.
+	// +optional
+	TokenExpiry *metav1.Time `json:"tokenExpiry,omitempty"`
+}
+
+// Reasons a ProviderConfig is or is not ready.
+const (
+	ReasonHealthy   xpv1.ConditionReason = "Healthy"
+	ReasonUnhealthy xpv1.ConditionReason = "Unhealthy"
+)
+
+// Healthy returns a condition that indicates the credentials of a
+// ProviderConfig were validated successfully.
+func Healthy() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               xpv1.TypeReady,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonHealthy,
+	}
+}
+
+// Unhealthy returns a condition that indicates the credentials of a
+// ProviderConfig could not be validated.
+func Unhealthy(err error) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               xpv1.TypeReady,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonUnhealthy,
+		Message:            err.Error(),
+	}
 }
 
 // +kubebuilder:object:root=true
 
 // A ProviderConfig configures an Azure 'provider', i.e. a connection to a particular
 // Azure account using a particular Azure Service Principal.
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="SUBSCRIPTION",type="string",JSONPath=".status.subscriptionID"
+// +kubebuilder:printcolumn:name="TOKEN-EXPIRY",type="date",JSONPath=".status.tokenExpiry",priority=1
 // +kubebuilder:printcolumn:name="SECRET-NAME",type="string",JSONPath=".spec.credentialsSecretRef.name",priority=1
 // +kubebuilder:resource:scope=Cluster,categories={crossplane,provider,azure}
 // +kubebuilder:subresource:status
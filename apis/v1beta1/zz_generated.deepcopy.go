@@ -25,6 +25,71 @@ import (
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Cloud) DeepCopyInto(out *Cloud) {
+	*out = *in
+	if in.Endpoints != nil {
+		in, out := &in.Endpoints, &out.Endpoints
+		*out = new(CloudEndpoints)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Cloud.
+func (in *Cloud) DeepCopy() *Cloud {
+	if in == nil {
+		return nil
+	}
+	out := new(Cloud)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CloudEndpoints) DeepCopyInto(out *CloudEndpoints) {
+	*out = *in
+	if in.ActiveDirectory != nil {
+		in, out := &in.ActiveDirectory, &out.ActiveDirectory
+		*out = new(string)
+		**out = **in
+	}
+	if in.ResourceManager != nil {
+		in, out := &in.ResourceManager, &out.ResourceManager
+		*out = new(string)
+		**out = **in
+	}
+	if in.Graph != nil {
+		in, out := &in.Graph, &out.Graph
+		*out = new(string)
+		**out = **in
+	}
+	if in.KeyVaultResource != nil {
+		in, out := &in.KeyVaultResource, &out.KeyVaultResource
+		*out = new(string)
+		**out = **in
+	}
+	if in.KeyVaultDNSSuffix != nil {
+		in, out := &in.KeyVaultDNSSuffix, &out.KeyVaultDNSSuffix
+		*out = new(string)
+		**out = **in
+	}
+	if in.StorageEndpointSuffix != nil {
+		in, out := &in.StorageEndpointSuffix, &out.StorageEndpointSuffix
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloudEndpoints.
+func (in *CloudEndpoints) DeepCopy() *CloudEndpoints {
+	if in == nil {
+		return nil
+	}
+	out := new(CloudEndpoints)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ProviderConfig) DeepCopyInto(out *ProviderConfig) {
 	*out = *in
@@ -88,6 +153,38 @@ func (in *ProviderConfigList) DeepCopyObject() runtime.Object {
 func (in *ProviderConfigSpec) DeepCopyInto(out *ProviderConfigSpec) {
 	*out = *in
 	in.Credentials.DeepCopyInto(&out.Credentials)
+	if in.ClientID != nil {
+		in, out := &in.ClientID, &out.ClientID
+		*out = new(string)
+		**out = **in
+	}
+	if in.ManagedIdentityResourceID != nil {
+		in, out := &in.ManagedIdentityResourceID, &out.ManagedIdentityResourceID
+		*out = new(string)
+		**out = **in
+	}
+	if in.TenantID != nil {
+		in, out := &in.TenantID, &out.TenantID
+		*out = new(string)
+		**out = **in
+	}
+	if in.SubscriptionID != nil {
+		in, out := &in.SubscriptionID, &out.SubscriptionID
+		*out = new(string)
+		**out = **in
+	}
+	if in.Cloud != nil {
+		in, out := &in.Cloud, &out.Cloud
+		*out = new(Cloud)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DefaultTags != nil {
+		in, out := &in.DefaultTags, &out.DefaultTags
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProviderConfigSpec.
@@ -104,6 +201,10 @@ func (in *ProviderConfigSpec) DeepCopy() *ProviderConfigSpec {
 func (in *ProviderConfigStatus) DeepCopyInto(out *ProviderConfigStatus) {
 	*out = *in
 	in.ProviderConfigStatus.DeepCopyInto(&out.ProviderConfigStatus)
+	if in.TokenExpiry != nil {
+		in, out := &in.TokenExpiry, &out.TokenExpiry
+		*out = (*in).DeepCopy()
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProviderConfigStatus.
@@ -178,6 +279,11 @@ func (in *ProviderConfigUsageList) DeepCopyObject() runtime.Object {
 func (in *ProviderCredentials) DeepCopyInto(out *ProviderCredentials) {
 	*out = *in
 	in.CommonCredentialSelectors.DeepCopyInto(&out.CommonCredentialSelectors)
+	if in.OIDCTokenFilePath != nil {
+		in, out := &in.OIDCTokenFilePath, &out.OIDCTokenFilePath
+		*out = new(string)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProviderCredentials.
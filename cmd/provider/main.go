@@ -40,6 +40,8 @@ import (
 	"github.com/crossplane-contrib/provider-azure/apis/v1alpha1"
 	"github.com/crossplane-contrib/provider-azure/pkg/controller"
 	"github.com/crossplane-contrib/provider-azure/pkg/features"
+	"github.com/crossplane-contrib/provider-azure/pkg/tracing"
+	"github.com/crossplane-contrib/provider-azure/pkg/webhook"
 )
 
 func main() {
@@ -53,6 +55,13 @@ func main() {
 
 		namespace                  = app.Flag("namespace", "Namespace used to set as default scope in default secret store config.").Default("crossplane-system").Envar("POD_NAMESPACE").String()
 		enableExternalSecretStores = app.Flag("enable-external-secret-stores", "Enable support for ExternalSecretStores.").Default("false").Envar("ENABLE_EXTERNAL_SECRET_STORES").Bool()
+
+		enableTracing      = app.Flag("enable-tracing", "Export OpenTelemetry traces of reconciles and Azure requests to an OTLP/HTTP collector.").Default("false").Envar("ENABLE_TRACING").Bool()
+		tracingEndpoint    = app.Flag("tracing-endpoint", "Host and port of the OTLP/HTTP collector traces are exported to.").Default("localhost:4318").Envar("TRACING_ENDPOINT").String()
+		tracingInsecure    = app.Flag("tracing-insecure", "Export traces over plain HTTP rather than HTTPS.").Default("false").Envar("TRACING_INSECURE").Bool()
+		tracingSampleRatio = app.Flag("tracing-sample-ratio", "Fraction of reconciles that are traced.").Default("1").Envar("TRACING_SAMPLE_RATIO").Float64()
+
+		webhookTLSCertDir = app.Flag("webhook-tls-cert-dir", "Directory containing the tls.crt and tls.key files of the webhook server. Validating webhooks are served only if it is set.").Envar("WEBHOOK_TLS_CERT_DIR").String()
 	)
 	kingpin.MustParse(app.Parse(os.Args[1:]))
 
@@ -67,8 +76,34 @@ func main() {
 
 	log.Debug("Starting", "sync-period", syncInterval.String())
 
+	// kingpin.FatalIfError exits without running deferred functions, so
+	// buffered traces are flushed before any fatal error too.
+	flushTraces := func() {}
+	defer func() { flushTraces() }()
+	fatalIfError := func(err error, format string, args ...interface{}) {
+		if err != nil {
+			flushTraces()
+		}
+		kingpin.FatalIfError(err, format, args...)
+	}
+
+	if *enableTracing {
+		shutdown, err := tracing.Setup(context.Background(), tracing.Options{
+			Endpoint:    *tracingEndpoint,
+			Insecure:    *tracingInsecure,
+			SampleRatio: *tracingSampleRatio,
+		})
+		kingpin.FatalIfError(err, "Cannot set up tracing")
+		flushTraces = func() {
+			if err := shutdown(context.Background()); err != nil {
+				log.Info("Cannot flush traces", "error", err)
+			}
+		}
+		log.Info("Tracing enabled", "endpoint", *tracingEndpoint)
+	}
+
 	cfg, err := ctrl.GetConfig()
-	kingpin.FatalIfError(err, "Cannot get API server rest config")
+	fatalIfError(err, "Cannot get API server rest config")
 
 	mgr, err := ctrl.NewManager(cfg, ctrl.Options{
 		SyncPeriod: syncInterval,
@@ -85,9 +120,11 @@ func main() {
 		LeaderElectionResourceLock: resourcelock.LeasesResourceLock,
 		LeaseDuration:              func() *time.Duration { d := 60 * time.Second; return &d }(),
 		RenewDeadline:              func() *time.Duration { d := 50 * time.Second; return &d }(),
+
+		CertDir: *webhookTLSCertDir,
 	})
-	kingpin.FatalIfError(err, "Cannot create controller manager")
-	kingpin.FatalIfError(apis.AddToScheme(mgr.GetScheme()), "Cannot add Azure APIs to scheme")
+	fatalIfError(err, "Cannot create controller manager")
+	fatalIfError(apis.AddToScheme(mgr.GetScheme()), "Cannot add Azure APIs to scheme")
 
 	o := xpcontroller.Options{
 		Logger:                  log,
@@ -102,7 +139,7 @@ func main() {
 		log.Info("Alpha feature enabled", "flag", features.EnableAlphaExternalSecretStores)
 
 		// Ensure default store config exists.
-		kingpin.FatalIfError(resource.Ignore(kerrors.IsAlreadyExists, mgr.GetClient().Create(context.Background(), &v1alpha1.StoreConfig{
+		fatalIfError(resource.Ignore(kerrors.IsAlreadyExists, mgr.GetClient().Create(context.Background(), &v1alpha1.StoreConfig{
 			ObjectMeta: metav1.ObjectMeta{
 				Name: "default",
 			},
@@ -116,6 +153,10 @@ func main() {
 		})), "cannot create default store config")
 	}
 
-	kingpin.FatalIfError(controller.Setup(mgr, o), "Cannot setup Azure controllers")
-	kingpin.FatalIfError(mgr.Start(ctrl.SetupSignalHandler()), "Cannot start controller manager")
+	fatalIfError(controller.Setup(mgr, o), "Cannot setup Azure controllers")
+	if *webhookTLSCertDir != "" {
+		fatalIfError(webhook.Setup(mgr), "Cannot setup Azure webhooks")
+		log.Info("Webhooks enabled", "tls-cert-dir", *webhookTLSCertDir)
+	}
+	fatalIfError(mgr.Start(ctrl.SetupSignalHandler()), "Cannot start controller manager")
 }
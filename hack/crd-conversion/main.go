@@ -0,0 +1,65 @@
+//go:build generate
+// +build generate
+
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// crd-conversion configures the supplied CustomResourceDefinitions to convert
+// their custom resources between versions using the conversion webhook of the
+// provider. controller-gen can't generate the conversion strategy of a
+// CustomResourceDefinition, so it is patched once they are generated. The
+// package manager of Crossplane configures the client of the webhook.
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/pkg/errors"
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"sigs.k8s.io/yaml"
+)
+
+func main() {
+	for _, path := range os.Args[1:] {
+		if err := patch(path); err != nil {
+			fmt.Fprintf(os.Stderr, "crd-conversion: %s\n", err)
+			os.Exit(1)
+		}
+	}
+}
+
+func patch(path string) error {
+	b, err := os.ReadFile(path) // nolint:gosec
+	if err != nil {
+		return errors.Wrapf(err, "cannot read %s", path)
+	}
+	crd := &extv1.CustomResourceDefinition{}
+	if err := yaml.Unmarshal(b, crd); err != nil {
+		return errors.Wrapf(err, "cannot unmarshal %s", path)
+	}
+	crd.Spec.Conversion = &extv1.CustomResourceConversion{
+		Strategy: extv1.WebhookConverter,
+		Webhook: &extv1.WebhookConversion{
+			ConversionReviewVersions: []string{"v1"},
+		},
+	}
+	out, err := yaml.Marshal(crd)
+	if err != nil {
+		return errors.Wrapf(err, "cannot marshal %s", path)
+	}
+	return errors.Wrapf(os.WriteFile(path, append([]byte("---\n"), out...), 0600), "cannot write %s", path)
+}
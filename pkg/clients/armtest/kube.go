@@ -0,0 +1,117 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package armtest
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane-contrib/provider-azure/apis/v1beta1"
+	azure "github.com/crossplane-contrib/provider-azure/pkg/clients"
+)
+
+// ProviderConfigName is the name of the ProviderConfig that managed resources
+// reference to use the credentials of a Server.
+const ProviderConfigName = "armtest"
+
+// SecretKey is the key of the credentials in the Secrets got by the client
+// Kube returns.
+const SecretKey = "creds"
+
+// Kube returns a Kubernetes client whose ProviderConfigs use the credentials
+// of the Server, and the supplied default tags. Other objects are got empty,
+// and writes succeed.
+func (s *Server) Kube(defaultTags map[string]string) client.Client {
+	creds, _ := json.Marshal(s.Credentials())
+	return &test.MockClient{
+		MockGet: func(_ context.Context, _ client.ObjectKey, obj client.Object) error {
+			switch o := obj.(type) {
+			case *v1beta1.ProviderConfig:
+				// Clients are cached by ProviderConfig, so each Server
+				// needs its own.
+				o.SetUID(types.UID(s.URL))
+				o.Spec.Credentials.Source = xpv1.CredentialsSourceSecret
+				o.Spec.Credentials.SecretRef = &xpv1.SecretKeySelector{SecretReference: xpv1.SecretReference{Name: ProviderConfigName}, Key: SecretKey}
+				o.Spec.DefaultTags = defaultTags
+			case *corev1.Secret:
+				o.Data = map[string][]byte{SecretKey: creds}
+			}
+			return nil
+		},
+		MockCreate:       test.NewMockCreateFn(nil),
+		MockUpdate:       test.NewMockUpdateFn(nil),
+		MockPatch:        test.NewMockPatchFn(nil),
+		MockStatusUpdate: test.NewMockStatusUpdateFn(nil),
+	}
+}
+
+// DryRun makes the supplied managed resource a dry-run managed resource that
+// uses the credentials of a Server.
+func DryRun(mg resource.Managed) {
+	mg.SetProviderConfigReference(&xpv1.Reference{Name: ProviderConfigName})
+	meta.AddAnnotations(mg, map[string]string{azure.AnnotationKeyManagementMode: string(azure.ManagementModeDryRun)})
+}
+
+// PlanDryRun makes the supplied managed resource a dry-run managed resource
+// that uses the credentials of the Server, and observes it using the supplied
+// ExternalConnecter wrapped with azure.NewConnecter. It fails the supplied test
+// if the managed resource cannot be observed, or if the Server received any
+// request that may have changed a resource. It returns the managed resource's
+// Planned condition, whose message lists the planned requests.
+func (s *Server) PlanDryRun(t *testing.T, c managed.ExternalConnecter, mg resource.Managed) xpv1.Condition {
+	t.Helper()
+	ctx := context.Background()
+	DryRun(mg)
+	e, err := azure.NewConnecter(c, event.NewNopRecorder()).Connect(ctx, mg)
+	if err != nil {
+		t.Fatalf("Connect(...): %s", err)
+	}
+	if _, err := e.Observe(ctx, mg); err != nil {
+		t.Fatalf("Observe(...): %s", err)
+	}
+	if diff := cmp.Diff([]Request(nil), s.Changes()); diff != "" {
+		t.Errorf("Observe(...): -want no requests that change resources, +got:\n%s", diff)
+	}
+	return mg.GetCondition(azure.TypePlanned)
+}
+
+// Changes returns the requests the Server received that may have changed a
+// resource. Requests for Azure AD tokens are omitted.
+func (s *Server) Changes() []Request {
+	var c []Request
+	for _, r := range s.Requests() {
+		if azure.IsReadOnly(r.Method, r.Path) || strings.HasSuffix(strings.ToLower(r.Path), "/oauth2/token") {
+			continue
+		}
+		c = append(c, r)
+	}
+	return c
+}
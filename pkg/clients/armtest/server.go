@@ -0,0 +1,583 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package armtest provides an in-memory stand-in for Azure Resource Manager
+// and Azure AD, served over HTTP, for testing controllers without network
+// access.
+package armtest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DefaultSubscriptionID is the subscription of the credentials returned by
+// Server.Credentials.
+const DefaultSubscriptionID = "00000000-0000-0000-0000-000000000000"
+
+// Provisioning states.
+const (
+	ProvisioningStateSucceeded = "Succeeded"
+	ProvisioningStateCreating  = "Creating"
+	ProvisioningStateUpdating  = "Updating"
+	ProvisioningStateDeleting  = "Deleting"
+)
+
+// Operation statuses.
+const (
+	OperationStatusInProgress = "InProgress"
+	OperationStatusSucceeded  = "Succeeded"
+	OperationStatusFailed     = "Failed"
+)
+
+// Error codes returned by the Server.
+const (
+	CodeResourceNotFound           = "ResourceNotFound"
+	CodeResourceGroupNotFound      = "ResourceGroupNotFound"
+	CodeParentResourceNotFound     = "ParentResourceNotFound"
+	CodeAnotherOperationInProgress = "AnotherOperationInProgress"
+	CodeInvalidRequestContent      = "InvalidRequestContent"
+	CodeInvalidResourceType        = "InvalidResourceType"
+)
+
+const (
+	// operationsNamespace is the resource provider namespace under which
+	// the Server serves the status of long-running operations.
+	operationsNamespace = "Microsoft.Emulator"
+
+	headerRequestID            = "x-ms-request-id"
+	headerCorrelationRequestID = "x-ms-correlation-request-id"
+	headerAsyncOperation       = "Azure-AsyncOperation"
+)
+
+// A Request recorded by the Server.
+type Request struct {
+	Method string
+	Path   string
+}
+
+// A Fault makes the Server fail matching requests.
+type Fault struct {
+	// Method of the requests to fail. Requests with any method match if it
+	// is empty.
+	Method string
+
+	// PathContains is a case-insensitive substring of the paths of the
+	// requests to fail. Requests with any path match if it is empty.
+	PathContains string
+
+	// StatusCode the failing requests are answered with. Defaults to 500.
+	StatusCode int
+
+	// Code and Message of the error the failing requests are answered with.
+	Code    string
+	Message string
+
+	// Async makes the long-running operations started by matching requests
+	// fail, rather than the requests themselves.
+	Async bool
+
+	// Count is how many matching requests fail, after which the fault is
+	// removed. Matching requests fail indefinitely if it is zero.
+	Count int
+}
+
+func (f *Fault) matches(r *http.Request) bool {
+	if f.Method != "" && !strings.EqualFold(f.Method, r.Method) {
+		return false
+	}
+	return f.PathContains == "" || strings.Contains(strings.ToLower(r.URL.Path), strings.ToLower(f.PathContains))
+}
+
+type operation struct {
+	id     string
+	key    string
+	method string
+	polls  int
+	status string
+	fault  *Fault
+}
+
+// A Server is an in-memory stand-in for Azure Resource Manager. It supports
+// resource groups, PUT, PATCH, GET, HEAD and DELETE of generic resources
+// addressed by their ARM IDs, listing the resources of a collection, POST of
+// actions such as listKeys and checkNameAvailability, long-running operations
+// polled via the Azure-AsyncOperation header, and injected faults. It also
+// issues Azure AD tokens, so that clients configured with the credentials it
+// returns may authenticate against it.
+type Server struct {
+	*httptest.Server
+
+	mu         sync.Mutex
+	polls      int
+	resources  map[string]map[string]interface{}
+	operations map[string]*operation
+	faults     []*Fault
+	requests   []Request
+}
+
+// An Option configures a Server.
+type Option func(*Server)
+
+// WithPolls configures how many times a long-running operation reports that
+// it is in progress before it completes. Operations complete synchronously if
+// it is zero. Defaults to one.
+func WithPolls(n int) Option {
+	return func(s *Server) {
+		s.polls = n
+	}
+}
+
+// NewServer starts and returns a new Server. The caller should Close it when
+// finished.
+func NewServer(o ...Option) *Server {
+	s := &Server{
+		polls:      1,
+		resources:  map[string]map[string]interface{}{},
+		operations: map[string]*operation{},
+	}
+	for _, fn := range o {
+		fn(s)
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serve))
+	return s
+}
+
+// Credentials returns service principal credentials, as found in the Secret
+// of a ProviderConfig, that authenticate against the Server and use it as the
+// Azure Resource Manager endpoint.
+func (s *Server) Credentials() map[string]string {
+	return map[string]string{
+		"clientId":                       "client",
+		"clientSecret":                   "secret",
+		"tenantId":                       "tenant",
+		"subscriptionId":                 DefaultSubscriptionID,
+		"activeDirectoryEndpointUrl":     s.URL + "/",
+		"resourceManagerEndpointUrl":     s.URL + "/",
+		"activeDirectoryGraphResourceId": s.URL + "/",
+	}
+}
+
+// Inject a fault into the Server.
+func (s *Server) Inject(f Fault) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if f.StatusCode == 0 {
+		f.StatusCode = http.StatusInternalServerError
+	}
+	s.faults = append(s.faults, &f)
+}
+
+// Requests returns the requests the Server received, in order.
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Request(nil), s.requests...)
+}
+
+// Resource returns the resource with the supplied ID, if it exists.
+func (s *Server) Resource(id string) (map[string]interface{}, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r, ok := s.resources[key(id)]
+	return copyObject(r), ok
+}
+
+// SetResource creates or replaces the resource with the supplied ID. The
+// resource's provisioning state is Succeeded. Neither its resource group nor
+// its parent need exist.
+func (s *Server) SetResource(id string, body map[string]interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.resources[key(id)] = newResource(id, copyObject(body), ProvisioningStateSucceeded)
+}
+
+func (s *Server) serve(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.requests = append(s.requests, Request{Method: r.Method, Path: r.URL.Path})
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set(headerRequestID, uuid.New().String())
+	cid := r.Header.Get(headerCorrelationRequestID)
+	if cid == "" {
+		cid = uuid.New().String()
+	}
+	w.Header().Set(headerCorrelationRequestID, cid)
+
+	f := s.fault(r)
+	if f != nil && !f.Async {
+		writeError(w, f.StatusCode, f.Code, f.Message)
+		return
+	}
+
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	switch {
+	case len(parts) == 3 && strings.EqualFold(parts[1], "oauth2") && strings.EqualFold(parts[2], "token"):
+		writeToken(w)
+	case len(parts) == 6 && strings.EqualFold(parts[0], "subscriptions") && strings.EqualFold(parts[3], operationsNamespace):
+		s.serveOperation(w, parts[5])
+	case r.Method == http.MethodPost && strings.EqualFold(parts[len(parts)-1], "checkNameAvailability"):
+		// Names are always available, since they are not global.
+		writeJSON(w, http.StatusOK, map[string]interface{}{"nameAvailable": true})
+	case len(parts) == 4 && strings.EqualFold(parts[0], "subscriptions") && strings.EqualFold(parts[2], "resourcegroups"):
+		s.serveResource(w, r, parts, f)
+	case len(parts) >= 6 && strings.EqualFold(parts[0], "subscriptions") && providersIndex(parts) > 0:
+		s.serveResource(w, r, parts, f)
+	default:
+		writeError(w, http.StatusNotFound, CodeInvalidResourceType, fmt.Sprintf("The resource type of %q is not supported.", r.URL.Path))
+	}
+}
+
+// fault returns the first fault that matches the supplied request, if any,
+// and removes faults that have failed as many requests as they should.
+func (s *Server) fault(r *http.Request) *Fault {
+	for i, f := range s.faults {
+		if !f.matches(r) {
+			continue
+		}
+		if f.Count > 0 {
+			f.Count--
+			if f.Count == 0 {
+				s.faults = append(s.faults[:i], s.faults[i+1:]...)
+			}
+		}
+		return f
+	}
+	return nil
+}
+
+func (s *Server) serveOperation(w http.ResponseWriter, id string) {
+	op, ok := s.operations[id]
+	if !ok {
+		writeError(w, http.StatusNotFound, CodeResourceNotFound, fmt.Sprintf("Operation %s was not found.", id))
+		return
+	}
+	if op.status == OperationStatusInProgress {
+		if op.polls > 0 {
+			op.polls--
+		} else {
+			s.complete(op)
+		}
+	}
+	body := map[string]interface{}{"id": op.id, "name": op.id, "status": op.status}
+	if op.status == OperationStatusFailed {
+		body["error"] = map[string]interface{}{"code": op.fault.Code, "message": op.fault.Message}
+	}
+	writeJSON(w, http.StatusOK, body)
+}
+
+func (s *Server) serveResource(w http.ResponseWriter, r *http.Request, parts []string, f *Fault) {
+	id := "/" + strings.Join(parts, "/")
+	k := key(id)
+
+	// Collections have an odd number of segments following the resource
+	// provider namespace, e.g. .../providers/Microsoft.Cache/redis.
+	p := providersIndex(parts)
+	if p > 0 && (len(parts)-p)%2 == 1 {
+		s.serveCollection(w, r, parts)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet, http.MethodHead:
+		res, ok := s.resources[k]
+		if !ok {
+			writeNotFound(w, parts)
+			return
+		}
+		writeJSON(w, http.StatusOK, res)
+		return
+	}
+
+	if s.inProgress(k) {
+		writeError(w, http.StatusConflict, CodeAnotherOperationInProgress, fmt.Sprintf("Another operation is in progress on %s.", id))
+		return
+	}
+
+	switch r.Method {
+	case http.MethodPut:
+		if code, msg := s.checkParents(parts); code != "" {
+			writeError(w, http.StatusNotFound, code, msg)
+			return
+		}
+		body, err := readObject(r)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, CodeInvalidRequestContent, err.Error())
+			return
+		}
+		_, exists := s.resources[k]
+		status, state := http.StatusCreated, ProvisioningStateCreating
+		if exists {
+			status, state = http.StatusOK, ProvisioningStateUpdating
+		}
+		if s.polls == 0 && f == nil || isResourceGroup(parts) {
+			state = ProvisioningStateSucceeded
+		}
+		s.resources[k] = newResource(id, body, state)
+		if state != ProvisioningStateSucceeded {
+			s.start(w, parts, k, r.Method, f)
+		}
+		writeJSON(w, status, s.resources[k])
+	case http.MethodPatch:
+		res, ok := s.resources[k]
+		if !ok {
+			writeNotFound(w, parts)
+			return
+		}
+		body, err := readObject(r)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, CodeInvalidRequestContent, err.Error())
+			return
+		}
+		merge(res, body)
+		writeJSON(w, http.StatusOK, res)
+	case http.MethodDelete:
+		res, ok := s.resources[k]
+		if !ok {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		if s.polls == 0 && f == nil {
+			s.delete(k)
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		setProvisioningState(res, ProvisioningStateDeleting)
+		s.start(w, parts, k, r.Method, f)
+		w.WriteHeader(http.StatusAccepted)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, CodeInvalidRequestContent, fmt.Sprintf("Method %s is not supported.", r.Method))
+	}
+}
+
+// serveCollection lists the resources of a collection, and answers actions
+// such as listKeys with an empty object if the resource they act upon exists.
+func (s *Server) serveCollection(w http.ResponseWriter, r *http.Request, parts []string) {
+	if r.Method == http.MethodPost {
+		if _, ok := s.resources[key("/"+strings.Join(parts[:len(parts)-1], "/"))]; !ok {
+			writeNotFound(w, parts[:len(parts)-1])
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{})
+		return
+	}
+	prefix := key("/"+strings.Join(parts, "/")) + "/"
+	keys := make([]string, 0)
+	for k := range s.resources {
+		if strings.HasPrefix(k, prefix) && !strings.Contains(strings.TrimPrefix(k, prefix), "/") {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	value := make([]interface{}, len(keys))
+	for i, k := range keys {
+		value[i] = s.resources[k]
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"value": value})
+}
+
+// checkParents returns the error code and message with which a PUT of the
+// resource with the supplied ID segments should fail because its resource
+// group or parent resource does not exist.
+func (s *Server) checkParents(parts []string) (string, string) {
+	if isResourceGroup(parts) {
+		return "", ""
+	}
+	if len(parts) > 4 && strings.EqualFold(parts[2], "resourcegroups") {
+		if _, ok := s.resources[key("/"+strings.Join(parts[:4], "/"))]; !ok {
+			return CodeResourceGroupNotFound, fmt.Sprintf("Resource group '%s' could not be found.", parts[3])
+		}
+	}
+	if p := providersIndex(parts); len(parts)-p > 4 {
+		parent := "/" + strings.Join(parts[:len(parts)-2], "/")
+		if _, ok := s.resources[key(parent)]; !ok {
+			return CodeParentResourceNotFound, fmt.Sprintf("Parent resource '%s' could not be found.", parent)
+		}
+	}
+	return "", ""
+}
+
+func (s *Server) inProgress(k string) bool {
+	for _, op := range s.operations {
+		if op.key == k && op.status == OperationStatusInProgress {
+			return true
+		}
+	}
+	return false
+}
+
+// start a long-running operation on the resource with the supplied key, and
+// point the client at it.
+func (s *Server) start(w http.ResponseWriter, parts []string, k, method string, f *Fault) {
+	op := &operation{id: uuid.New().String(), key: k, method: method, polls: s.polls, status: OperationStatusInProgress, fault: f}
+	s.operations[op.id] = op
+	w.Header().Set(headerAsyncOperation, fmt.Sprintf("%s/subscriptions/%s/providers/%s/operations/%s?api-version=2020-01-01", s.URL, parts[1], operationsNamespace, op.id))
+	w.Header().Set("Retry-After", "0")
+}
+
+// complete the supplied long-running operation.
+func (s *Server) complete(op *operation) {
+	if op.fault != nil {
+		op.status = OperationStatusFailed
+		if res, ok := s.resources[op.key]; ok {
+			setProvisioningState(res, OperationStatusFailed)
+		}
+		return
+	}
+	op.status = OperationStatusSucceeded
+	if op.method == http.MethodDelete {
+		s.delete(op.key)
+		return
+	}
+	if res, ok := s.resources[op.key]; ok {
+		setProvisioningState(res, ProvisioningStateSucceeded)
+	}
+}
+
+// delete the resource with the supplied key, and any resources it contains.
+func (s *Server) delete(k string) {
+	delete(s.resources, k)
+	for rk := range s.resources {
+		if strings.HasPrefix(rk, k+"/") {
+			delete(s.resources, rk)
+		}
+	}
+}
+
+func providersIndex(parts []string) int {
+	for i := 0; i < len(parts)-1; i++ {
+		if strings.EqualFold(parts[i], "providers") {
+			return i
+		}
+	}
+	return -1
+}
+
+func isResourceGroup(parts []string) bool {
+	return len(parts) == 4 && strings.EqualFold(parts[2], "resourcegroups")
+}
+
+func key(id string) string {
+	return strings.ToLower(strings.TrimSuffix(id, "/"))
+}
+
+// newResource returns the supplied body, with the properties ARM adds to the
+// resources it returns.
+func newResource(id string, body map[string]interface{}, state string) map[string]interface{} {
+	if body == nil {
+		body = map[string]interface{}{}
+	}
+	parts := strings.Split(strings.Trim(id, "/"), "/")
+	body["id"] = id
+	body["name"] = parts[len(parts)-1]
+	if p := providersIndex(parts); p > 0 {
+		types := []string{parts[p+1]}
+		for i := p + 2; i < len(parts); i += 2 {
+			types = append(types, parts[i])
+		}
+		body["type"] = strings.Join(types, "/")
+	} else {
+		body["type"] = "Microsoft.Resources/resourceGroups"
+	}
+	setProvisioningState(body, state)
+	return body
+}
+
+func setProvisioningState(res map[string]interface{}, state string) {
+	props, ok := res["properties"].(map[string]interface{})
+	if !ok {
+		props = map[string]interface{}{}
+		res["properties"] = props
+	}
+	props["provisioningState"] = state
+}
+
+// merge the supplied patch into the supplied resource. Its tags and
+// properties are merged, and any other fields replaced.
+func merge(res, patch map[string]interface{}) {
+	for k, v := range patch {
+		pv, pok := v.(map[string]interface{})
+		rv, rok := res[k].(map[string]interface{})
+		if (k == "properties" || k == "tags") && pok && rok {
+			for pk, pvv := range pv {
+				rv[pk] = pvv
+			}
+			continue
+		}
+		res[k] = v
+	}
+}
+
+func readObject(r *http.Request) (map[string]interface{}, error) {
+	b, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, err
+	}
+	body := map[string]interface{}{}
+	if len(b) == 0 {
+		return body, nil
+	}
+	return body, json.Unmarshal(b, &body)
+}
+
+func copyObject(o map[string]interface{}) map[string]interface{} {
+	if o == nil {
+		return nil
+	}
+	b, _ := json.Marshal(o)
+	c := map[string]interface{}{}
+	_ = json.Unmarshal(b, &c)
+	return c
+}
+
+func writeNotFound(w http.ResponseWriter, parts []string) {
+	if isResourceGroup(parts) {
+		writeError(w, http.StatusNotFound, CodeResourceGroupNotFound, fmt.Sprintf("Resource group '%s' could not be found.", parts[3]))
+		return
+	}
+	writeError(w, http.StatusNotFound, CodeResourceNotFound, fmt.Sprintf("The Resource '%s' was not found.", "/"+strings.Join(parts, "/")))
+}
+
+func writeError(w http.ResponseWriter, status int, code, message string) {
+	writeJSON(w, status, map[string]interface{}{"error": map[string]interface{}{"code": code, "message": message}})
+}
+
+func writeToken(w http.ResponseWriter) {
+	now := time.Now()
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"access_token": "armtest",
+		"token_type":   "Bearer",
+		"expires_in":   "3600",
+		"expires_on":   strconv.FormatInt(now.Add(time.Hour).Unix(), 10),
+		"not_before":   strconv.FormatInt(now.Unix(), 10),
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,304 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package armtest
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/services/redis/mgmt/2018-03-01/redis"
+	"github.com/Azure/azure-sdk-for-go/services/resources/mgmt/2018-05-01/resources"
+	azureautorest "github.com/Azure/go-autorest/autorest/azure"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+
+	azure "github.com/crossplane-contrib/provider-azure/pkg/clients"
+)
+
+const (
+	rg    = "cool-rg"
+	cache = "cool-cache"
+)
+
+var params = redis.CreateParameters{
+	Location: to.StringPtr("westus"),
+	CreateProperties: &redis.CreateProperties{
+		Sku: &redis.Sku{Name: redis.Basic, Family: redis.C, Capacity: to.Int32Ptr(0)},
+	},
+}
+
+func clients(t *testing.T, s *Server) (resources.GroupsClient, redis.Client) {
+	t.Helper()
+	creds := s.Credentials()
+	auth, err := azure.NewAuthorizer(creds)
+	if err != nil {
+		t.Fatalf("NewAuthorizer(...): %s", err)
+	}
+	gc := resources.NewGroupsClientWithBaseURI(creds[azure.CredentialsKeyResourceManagerEndpointURL], creds[azure.CredentialsKeySubscriptionID])
+	azure.ConfigureClient(&gc.Client, auth)
+	rc := redis.NewClientWithBaseURI(creds[azure.CredentialsKeyResourceManagerEndpointURL], creds[azure.CredentialsKeySubscriptionID])
+	azure.ConfigureClient(&rc.Client, auth)
+	return gc, rc
+}
+
+func TestResourceGroups(t *testing.T) {
+	s := NewServer(WithPolls(0))
+	defer s.Close()
+	ctx := context.Background()
+	gc, _ := clients(t, s)
+
+	if _, err := gc.Get(ctx, rg); !azure.IsNotFound(err) {
+		t.Fatalf("Get(...): want not found error, got %v", err)
+	}
+	if _, err := gc.CreateOrUpdate(ctx, rg, resources.Group{Location: to.StringPtr("westus")}); err != nil {
+		t.Fatalf("CreateOrUpdate(...): %s", err)
+	}
+	g, err := gc.Get(ctx, rg)
+	if err != nil {
+		t.Fatalf("Get(...): %s", err)
+	}
+	if diff := cmp.Diff(ProvisioningStateSucceeded, to.String(g.Properties.ProvisioningState)); diff != "" {
+		t.Errorf("Get(...): -want provisioning state, +got:\n%s", diff)
+	}
+	if _, err := gc.Delete(ctx, rg); err != nil {
+		t.Fatalf("Delete(...): %s", err)
+	}
+	if _, err := gc.Get(ctx, rg); !azure.IsNotFound(err) {
+		t.Errorf("Get(...): want not found error after deletion, got %v", err)
+	}
+}
+
+func TestLongRunningOperation(t *testing.T) {
+	s := NewServer(WithPolls(1))
+	defer s.Close()
+	ctx := context.Background()
+	gc, rc := clients(t, s)
+
+	if _, err := rc.Create(ctx, rg, cache, params); azure.Classify(err) != azure.ErrorNotFound {
+		t.Fatalf("Create(...): want not found error in a missing resource group, got %v", err)
+	}
+
+	if _, err := gc.CreateOrUpdate(ctx, rg, resources.Group{Location: to.StringPtr("westus")}); err != nil {
+		t.Fatalf("CreateOrUpdate(...): %s", err)
+	}
+	f, err := rc.Create(ctx, rg, cache, params)
+	if err != nil {
+		t.Fatalf("Create(...): %s", err)
+	}
+	op := azure.NewAsyncOperation(http.MethodPut, f.FutureAPI, nil)
+	if diff := cmp.Diff(azure.AsyncOperationStatusInProgress, op.Status); diff != "" {
+		t.Fatalf("Create(...): -want status, +got:\n%s", diff)
+	}
+
+	if _, err := rc.Create(ctx, rg, cache, params); azure.Classify(err) != azure.ErrorConflict {
+		t.Errorf("Create(...): want conflict while another operation is in progress, got %v", err)
+	}
+
+	for _, want := range []string{azure.AsyncOperationStatusInProgress, azure.AsyncOperationStatusSucceeded} {
+		if err := azure.FetchAsyncOperation(ctx, rc.Client, &op); err != nil {
+			t.Fatalf("FetchAsyncOperation(...): %s", err)
+		}
+		if diff := cmp.Diff(want, op.Status); diff != "" {
+			t.Errorf("FetchAsyncOperation(...): -want status, +got:\n%s", diff)
+		}
+	}
+
+	r, err := rc.Get(ctx, rg, cache)
+	if err != nil {
+		t.Fatalf("Get(...): %s", err)
+	}
+	if diff := cmp.Diff(redis.Succeeded, r.ProvisioningState); diff != "" {
+		t.Errorf("Get(...): -want provisioning state, +got:\n%s", diff)
+	}
+}
+
+func TestFaults(t *testing.T) {
+	s := NewServer(WithPolls(0))
+	defer s.Close()
+	ctx := context.Background()
+	gc, rc := clients(t, s)
+
+	if _, err := gc.CreateOrUpdate(ctx, rg, resources.Group{Location: to.StringPtr("westus")}); err != nil {
+		t.Fatalf("CreateOrUpdate(...): %s", err)
+	}
+
+	s.Inject(Fault{Method: http.MethodGet, PathContains: "Microsoft.Cache", StatusCode: http.StatusForbidden, Code: "AuthorizationFailed", Count: 1})
+	if _, err := rc.Get(ctx, rg, cache); azure.Classify(err) != azure.ErrorAuthorizationFailed {
+		t.Errorf("Get(...): want injected authorization error, got %v", err)
+	}
+	if _, err := rc.Get(ctx, rg, cache); !azure.IsNotFound(err) {
+		t.Errorf("Get(...): want not found error once the fault was removed, got %v", err)
+	}
+
+	s.Inject(Fault{Method: http.MethodPut, PathContains: cache, Code: "QuotaExceeded", Message: "boom", Async: true})
+	f, err := rc.Create(ctx, rg, cache, params)
+	if err != nil {
+		t.Fatalf("Create(...): %s", err)
+	}
+	op := azure.NewAsyncOperation(http.MethodPut, f.FutureAPI, nil)
+	if err := azure.FetchAsyncOperation(ctx, rc.Client, &op); err != nil {
+		t.Fatalf("FetchAsyncOperation(...): %s", err)
+	}
+	if diff := cmp.Diff(azure.AsyncOperationStatusFailed, op.Status); diff != "" {
+		t.Errorf("FetchAsyncOperation(...): -want status of an operation with an injected fault, +got:\n%s", diff)
+	}
+}
+
+func TestSetResource(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+
+	id := "/subscriptions/" + DefaultSubscriptionID + "/resourceGroups/" + rg + "/providers/Microsoft.Cache/Redis/" + cache
+	s.SetResource(id, map[string]interface{}{"location": "westus"})
+
+	_, rc := clients(t, s)
+	r, err := rc.Get(context.Background(), rg, cache)
+	if err != nil {
+		t.Fatalf("Get(...): %s", err)
+	}
+	if diff := cmp.Diff("westus", to.String(r.Location)); diff != "" {
+		t.Errorf("Get(...): -want location, +got:\n%s", diff)
+	}
+
+	want := []Request{
+		{Method: http.MethodPost, Path: "/tenant/oauth2/token"},
+		{Method: http.MethodGet, Path: id},
+	}
+	if diff := cmp.Diff(want, s.Requests()); diff != "" {
+		t.Errorf("Requests(): -want, +got:\n%s", diff)
+	}
+}
+
+func TestChanges(t *testing.T) {
+	s := NewServer(WithPolls(0))
+	defer s.Close()
+	ctx := context.Background()
+	gc, rc := clients(t, s)
+
+	if _, err := gc.CreateOrUpdate(ctx, rg, resources.Group{Location: to.StringPtr("westus")}); err != nil {
+		t.Fatalf("CreateOrUpdate(...): %s", err)
+	}
+	id := "/subscriptions/" + DefaultSubscriptionID + "/resourceGroups/" + rg + "/providers/Microsoft.Cache/Redis/" + cache
+	s.SetResource(id, map[string]interface{}{"location": "westus"})
+	if _, err := rc.ListKeys(ctx, rg, cache); err != nil {
+		t.Fatalf("ListKeys(...): %s", err)
+	}
+
+	want := []Request{
+		{Method: http.MethodPut, Path: "/subscriptions/" + DefaultSubscriptionID + "/resourcegroups/" + rg},
+	}
+	if diff := cmp.Diff(want, s.Changes()); diff != "" {
+		t.Errorf("Changes(): -want, +got:\n%s", diff)
+	}
+}
+
+// code returns the error code of the supplied error returned by the Server.
+func code(err error) string {
+	var rErr *azureautorest.RequestError
+	if errors.As(err, &rErr) && rErr.ServiceError != nil {
+		return rErr.ServiceError.Code
+	}
+	var sErr *azureautorest.ServiceError
+	if errors.As(err, &sErr) {
+		return sErr.Code
+	}
+	return ""
+}
+
+func TestCollections(t *testing.T) {
+	s := NewServer(WithPolls(0))
+	defer s.Close()
+	ctx := context.Background()
+	gc, rc := clients(t, s)
+
+	if _, err := gc.CreateOrUpdate(ctx, rg, resources.Group{Location: to.StringPtr("westus")}); err != nil {
+		t.Fatalf("CreateOrUpdate(...): %s", err)
+	}
+	ids := make([]string, 0, 2)
+	for _, name := range []string{"cool-cache-b", "cool-cache-a"} {
+		id := "/subscriptions/" + DefaultSubscriptionID + "/resourceGroups/" + rg + "/providers/Microsoft.Cache/Redis/" + name
+		s.SetResource(id, map[string]interface{}{"location": "westus"})
+		ids = append([]string{id}, ids...)
+	}
+	// Resources contained by the listed resources are not listed.
+	s.SetResource(ids[0]+"/firewallRules/cool-rule", map[string]interface{}{})
+
+	page, err := rc.ListByResourceGroup(ctx, rg)
+	if err != nil {
+		t.Fatalf("ListByResourceGroup(...): %s", err)
+	}
+	got := make([]string, 0, len(page.Values()))
+	for _, r := range page.Values() {
+		got = append(got, to.String(r.ID))
+	}
+	if diff := cmp.Diff(ids, got); diff != "" {
+		t.Errorf("ListByResourceGroup(...): -want IDs, +got:\n%s", diff)
+	}
+}
+
+func TestParents(t *testing.T) {
+	s := NewServer(WithPolls(0))
+	defer s.Close()
+	ctx := context.Background()
+	gc, rc := clients(t, s)
+	fc := redis.NewFirewallRulesClientWithBaseURI(rc.BaseURI, rc.SubscriptionID)
+	fc.Client = rc.Client
+	rule := redis.FirewallRuleCreateParameters{
+		FirewallRuleProperties: &redis.FirewallRuleProperties{StartIP: to.StringPtr("10.0.0.1"), EndIP: to.StringPtr("10.0.0.1")},
+	}
+
+	if _, err := rc.Create(ctx, rg, cache, params); code(err) != CodeResourceGroupNotFound {
+		t.Errorf("Create(...): want %s error, got %v", CodeResourceGroupNotFound, err)
+	}
+	if _, err := gc.CreateOrUpdate(ctx, rg, resources.Group{Location: to.StringPtr("westus")}); err != nil {
+		t.Fatalf("CreateOrUpdate(...): %s", err)
+	}
+	if _, err := fc.CreateOrUpdate(ctx, rg, cache, "cool-rule", rule); code(err) != CodeParentResourceNotFound {
+		t.Errorf("CreateOrUpdate(...): want %s error, got %v", CodeParentResourceNotFound, err)
+	}
+	if _, err := rc.Create(ctx, rg, cache, params); err != nil {
+		t.Fatalf("Create(...): %s", err)
+	}
+	if _, err := fc.CreateOrUpdate(ctx, rg, cache, "cool-rule", rule); err != nil {
+		t.Errorf("CreateOrUpdate(...): %s", err)
+	}
+}
+
+func TestRequestIDs(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+
+	req, err := http.NewRequest(http.MethodGet, s.URL+"/subscriptions/"+DefaultSubscriptionID+"/resourcegroups/"+rg, nil)
+	if err != nil {
+		t.Fatalf("NewRequest(...): %s", err)
+	}
+	req.Header.Set(azure.HeaderCorrelationRequestID, "cool-correlation")
+	rsp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Do(...): %s", err)
+	}
+	defer rsp.Body.Close() // nolint:errcheck
+
+	if diff := cmp.Diff("cool-correlation", rsp.Header.Get(azure.HeaderCorrelationRequestID)); diff != "" {
+		t.Errorf("Do(...): the correlation ID of a request should be echoed: -want, +got:\n%s", diff)
+	}
+	if rsp.Header.Get(azure.HeaderRequestID) == "" {
+		t.Errorf("Do(...): every response should have a request ID")
+	}
+}
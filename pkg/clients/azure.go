@@ -20,17 +20,19 @@ import (
 	"context"
 	"encoding/json"
 	"net/http"
+	"strings"
 
 	"github.com/Azure/azure-sdk-for-go/services/resources/mgmt/2018-05-01/resources"
 	"github.com/Azure/go-autorest/autorest"
 	"github.com/Azure/go-autorest/autorest/azure"
-	"github.com/Azure/go-autorest/autorest/azure/auth"
 	"github.com/Azure/go-autorest/autorest/to"
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
 
 	"github.com/crossplane-contrib/provider-azure/apis/v1alpha3"
@@ -43,7 +45,14 @@ const (
 	// AsyncOperationStatusInProgress is the status value for AsyncOperation type
 	// that indicates the operation is still ongoing.
 	AsyncOperationStatusInProgress = "InProgress"
-	asyncOperationPollingMethod    = "AsyncOperation"
+	// AsyncOperationStatusSucceeded is the status value for AsyncOperation
+	// type that indicates the operation completed successfully.
+	AsyncOperationStatusSucceeded = "Succeeded"
+	// AsyncOperationStatusFailed is the status value for AsyncOperation type
+	// that indicates the operation failed.
+	AsyncOperationStatusFailed   = "Failed"
+	asyncOperationStatusCanceled = "Canceled"
+	asyncOperationPollingMethod  = "AsyncOperation"
 )
 
 // Error strings.
@@ -81,19 +90,58 @@ const (
 	CredentialsKeySQLManagementEndpointURL       = "sqlManagementEndpointUrl"
 	CredentialsKeyGalleryEndpointURL             = "galleryEndpointUrl"
 	CredentialsManagementEndpointURL             = "managementEndpointUrl"
+	CredentialsKeyFederatedTokenFile             = "federatedTokenFile"
+	CredentialsKeyUseManagedIdentity             = "useManagedIdentity"
+	CredentialsKeyManagedIdentityResourceID      = "managedIdentityResourceId"
+	CredentialsKeyClientCertificate              = "clientCertificateData"
+	CredentialsKeyClientCertificatePassword      = "clientCertificatePassword"
+	CredentialsKeyKeyVaultResourceID             = "keyVaultResourceId"
+	CredentialsKeyKeyVaultDNSSuffix              = "keyVaultDnsSuffix"
+	CredentialsKeyStorageEndpointSuffix          = "storageEndpointSuffix"
 )
 
+// AnnotationKeySubscriptionID is the annotation of a managed resource that
+// selects the Azure subscription it is managed in, overriding the subscription
+// of its ProviderConfig. Changing it after the external resource was created
+// causes the provider to look for the resource in the new subscription.
+const AnnotationKeySubscriptionID = "azure.crossplane.io/subscription-id"
+
 // GetAuthInfo figures out how to connect to Azure API and returns the necessary
 // information to be used for controllers to construct their specific clients.
 func GetAuthInfo(ctx context.Context, c client.Client, mg resource.Managed) (content map[string]string, authorizer autorest.Authorizer, err error) {
+	return GetResourceAuthInfo(ctx, c, mg, CredentialsKeyResourceManagerEndpointURL)
+}
+
+// GetResourceAuthInfo is like GetAuthInfo, but returns an authorizer for the
+// resource whose URL the credentials store under the supplied key, e.g.
+// CredentialsKeyActiveDirectoryGraphResourceID, rather than for Azure Resource
+// Manager.
+func GetResourceAuthInfo(ctx context.Context, c client.Client, mg resource.Managed, resourceKey string) (content map[string]string, authorizer autorest.Authorizer, err error) {
 	switch {
 	case mg.GetProviderConfigReference() != nil:
-		return UseProviderConfig(ctx, c, mg)
+		content, authorizer, err = useProviderConfig(ctx, c, mg, resourceKey)
 	case mg.GetProviderReference() != nil:
-		return UseProvider(ctx, c, mg)
+		content, _, err = UseProvider(ctx, c, mg)
+		if err == nil {
+			authorizer, err = NewResourceAuthorizer(content, content[resourceKey])
+			err = errors.Wrap(err, errGetAuthorizer)
+		}
 	default:
 		return nil, nil, errors.New(errNeitherPCNorPGiven)
 	}
+	if err != nil {
+		return nil, nil, err
+	}
+	SetSubscriptionOverride(mg, content)
+	return content, authorizer, nil
+}
+
+// SetSubscriptionOverride replaces the subscription in the supplied
+// credentials with the one selected by the supplied managed resource, if any.
+func SetSubscriptionOverride(mg resource.Managed, creds map[string]string) {
+	if id := mg.GetAnnotations()[AnnotationKeySubscriptionID]; id != "" {
+		creds[CredentialsKeySubscriptionID] = id
+	}
 }
 
 // UseProvider to return the necessary information to construct an Azure client.
@@ -113,17 +161,18 @@ func UseProvider(ctx context.Context, c client.Client, mg resource.Managed) (con
 	if err := json.Unmarshal(s.Data[ref.Key], &m); err != nil {
 		return nil, nil, errors.Wrap(err, errUnmarshalCredentialSecret)
 	}
-	cfg := auth.NewClientCredentialsConfig(m[CredentialsKeyClientID], m[CredentialsKeyClientSecret], m[CredentialsKeyTenantID])
-	cfg.AADEndpoint = m[CredentialsKeyActiveDirectoryEndpointURL]
-	cfg.Resource = m[CredentialsKeyResourceManagerEndpointURL]
-
-	a, err := cfg.Authorizer()
+	setCloudEndpoints(m, credentialsEnvironment(m), false)
+	a, err := NewAuthorizer(m)
 	return m, a, errors.Wrap(err, errGetAuthorizer)
 }
 
 // UseProviderConfig to return the necessary information to construct an Azure
 // client.
 func UseProviderConfig(ctx context.Context, c client.Client, mg resource.Managed) (content map[string]string, authorizer autorest.Authorizer, err error) {
+	return useProviderConfig(ctx, c, mg, CredentialsKeyResourceManagerEndpointURL)
+}
+
+func useProviderConfig(ctx context.Context, c client.Client, mg resource.Managed, resourceKey string) (map[string]string, autorest.Authorizer, error) {
 	pc := &v1beta1.ProviderConfig{}
 	t := resource.NewProviderConfigUsageTracker(c, &v1beta1.ProviderConfigUsage{})
 	if err := t.Track(ctx, mg); err != nil {
@@ -133,20 +182,64 @@ func UseProviderConfig(ctx context.Context, c client.Client, mg resource.Managed
 		return nil, nil, errors.Wrap(err, errGetProviderConfig)
 	}
 
-	data, err := resource.CommonCredentialExtractor(ctx, pc.Spec.Credentials.Source, c, pc.Spec.Credentials.CommonCredentialSelectors)
-	if err != nil {
-		return nil, nil, errors.Wrap(err, "cannot get credentials")
-	}
+	return DefaultAuthorizerCache.GetForResource(ctx, c, pc, resourceKey)
+}
+
+// ProviderConfigCredentials returns the credentials content described by the
+// supplied ProviderConfig, using the same keys as the JSON encoded credentials
+// Secret. Identity fields and the cloud set on the ProviderConfig take
+// precedence over the ones found in the credentials.
+func ProviderConfigCredentials(ctx context.Context, c client.Client, pc *v1beta1.ProviderConfig) (map[string]string, error) {
 	m := map[string]string{}
-	if err := json.Unmarshal(data, &m); err != nil {
-		return nil, nil, errors.Wrap(err, errUnmarshalCredentialSecret)
+	switch pc.Spec.Credentials.Source { //nolint:exhaustive
+	case v1beta1.CredentialsSourceOIDCTokenFile:
+		if pc.Spec.ClientID == nil || pc.Spec.TenantID == nil {
+			return nil, errors.New(errMissingOIDCIdentity)
+		}
+		path := v1beta1.DefaultOIDCTokenFilePath
+		if pc.Spec.Credentials.OIDCTokenFilePath != nil {
+			path = *pc.Spec.Credentials.OIDCTokenFilePath
+		}
+		m[CredentialsKeyFederatedTokenFile] = path
+	case v1beta1.CredentialsSourceManagedIdentity:
+		if pc.Spec.SubscriptionID == nil {
+			return nil, errors.New(errMissingMISubscription)
+		}
+		if pc.Spec.ClientID != nil && pc.Spec.ManagedIdentityResourceID != nil {
+			return nil, errors.New(errAmbiguousManagedIdentity)
+		}
+		m[CredentialsKeyUseManagedIdentity] = "true"
+		if pc.Spec.ManagedIdentityResourceID != nil {
+			m[CredentialsKeyManagedIdentityResourceID] = *pc.Spec.ManagedIdentityResourceID
+		}
+	default:
+		data, err := resource.CommonCredentialExtractor(ctx, pc.Spec.Credentials.Source, c, pc.Spec.Credentials.CommonCredentialSelectors)
+		if err != nil {
+			return nil, errors.Wrap(err, "cannot get credentials")
+		}
+		if err := json.Unmarshal(data, &m); err != nil {
+			return nil, errors.Wrap(err, errUnmarshalCredentialSecret)
+		}
 	}
-	cfg := auth.NewClientCredentialsConfig(m[CredentialsKeyClientID], m[CredentialsKeyClientSecret], m[CredentialsKeyTenantID])
-	cfg.AADEndpoint = m[CredentialsKeyActiveDirectoryEndpointURL]
-	cfg.Resource = m[CredentialsKeyResourceManagerEndpointURL]
-
-	a, err := cfg.Authorizer()
-	return m, a, errors.Wrap(err, errGetAuthorizer)
+	if pc.Spec.ClientID != nil {
+		m[CredentialsKeyClientID] = *pc.Spec.ClientID
+	}
+	if pc.Spec.TenantID != nil {
+		m[CredentialsKeyTenantID] = *pc.Spec.TenantID
+	}
+	if pc.Spec.SubscriptionID != nil {
+		m[CredentialsKeySubscriptionID] = *pc.Spec.SubscriptionID
+	}
+	if pc.Spec.Cloud == nil {
+		setCloudEndpoints(m, credentialsEnvironment(m), false)
+		return m, nil
+	}
+	env, err := CloudEnvironment(pc.Spec.Cloud)
+	if err != nil {
+		return nil, err
+	}
+	setCloudEndpoints(m, env, true)
+	return m, nil
 }
 
 // Client struct that represents the information needed to connect to the Azure services as a client
@@ -166,6 +259,12 @@ type Credentials struct {
 	ActiveDirectoryEndpointURL     string `json:"activeDirectoryEndpointUrl"`
 	ResourceManagerEndpointURL     string `json:"resourceManagerEndpointUrl"`
 	ActiveDirectoryGraphResourceID string `json:"activeDirectoryGraphResourceId"`
+
+	// ClientCertificateData is either a PEM encoded certificate and private
+	// key, or a base64 encoded PKCS#12 (PFX) archive. It is used in place of
+	// the client secret when set.
+	ClientCertificateData     string `json:"clientCertificateData,omitempty"`
+	ClientCertificatePassword string `json:"clientCertificatePassword,omitempty"`
 }
 
 // NewClient returns a client that can be used to connect to Azure services
@@ -175,13 +274,13 @@ func NewClient(credentials []byte) (*Client, error) {
 	if err := json.Unmarshal(credentials, &creds); err != nil {
 		return nil, errors.Wrap(err, "failed to unmarshal azure client secret data")
 	}
+	m := map[string]string{}
+	if err := json.Unmarshal(credentials, &m); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal azure client secret data")
+	}
+	setCloudEndpoints(m, credentialsEnvironment(m), false)
 
-	// create a config object from the loaded credentials data
-	config := auth.NewClientCredentialsConfig(creds.ClientID, creds.ClientSecret, creds.TenantID)
-	config.AADEndpoint = creds.ActiveDirectoryEndpointURL
-	config.Resource = creds.ResourceManagerEndpointURL
-
-	authorizer, err := config.Authorizer()
+	authorizer, err := NewAuthorizer(m)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to get authorizer from config")
 	}
@@ -193,29 +292,41 @@ func NewClient(credentials []byte) (*Client, error) {
 			ClientID:                       creds.ClientID,
 			ClientSecret:                   creds.ClientSecret,
 			TenantID:                       creds.TenantID,
-			ActiveDirectoryEndpointURL:     creds.ActiveDirectoryEndpointURL,
-			ActiveDirectoryGraphResourceID: creds.ActiveDirectoryGraphResourceID,
+			ActiveDirectoryEndpointURL:     m[CredentialsKeyActiveDirectoryEndpointURL],
+			ResourceManagerEndpointURL:     m[CredentialsKeyResourceManagerEndpointURL],
+			ActiveDirectoryGraphResourceID: m[CredentialsKeyActiveDirectoryGraphResourceID],
+			ClientCertificateData:          creds.ClientCertificateData,
+			ClientCertificatePassword:      creds.ClientCertificatePassword,
 		},
 	}, nil
 }
 
 // ValidateClient verifies if the given client is valid by testing if it can make an Azure service API call
 // TODO: is there a better way to validate the Azure client?
-func ValidateClient(client *Client) error {
-	groupsClient := resources.NewGroupsClient(client.SubscriptionID)
-	groupsClient.Authorizer = client.Authorizer
+func ValidateClient(ctx context.Context, client *Client) error {
+	baseURI := client.ResourceManagerEndpointURL
+	if baseURI == "" {
+		baseURI = resources.DefaultBaseURI
+	}
+	groupsClient := resources.NewGroupsClientWithBaseURI(baseURI, client.SubscriptionID)
+	ConfigureClient(&groupsClient.Client, client.Authorizer)
 	groupsClient.AddToUserAgent(UserAgent)
 
-	_, err := groupsClient.ListComplete(context.TODO(), "", nil)
+	_, err := groupsClient.ListComplete(ctx, "", nil)
 	return err
 }
 
 // FetchAsyncOperation updates the given operation object with the most up-to-date
-// status retrieved from Azure API.
+// status retrieved from Azure API. Operations that are known to have completed
+// are not fetched again.
 func FetchAsyncOperation(ctx context.Context, client autorest.Sender, as *v1alpha3.AsyncOperation) error {
 	if as == nil || as.PollingURL == "" || as.Method == "" {
 		return nil
 	}
+	switch as.Status {
+	case AsyncOperationStatusSucceeded, AsyncOperationStatusFailed, asyncOperationStatusCanceled:
+		return nil
+	}
 	// NOTE(muvaf):There is NewFutureFromResponse method to construct Future
 	// object but that requires http.Request object. Even though we construct a
 	// fake http.Request object, the poll operation makes decisions based on the
@@ -241,12 +352,118 @@ func FetchAsyncOperation(ctx context.Context, client autorest.Sender, as *v1alph
 	// related to fetch call.
 	_, err = op.DoneWithContext(ctx, client)
 	as.Status = op.Status()
+	DefaultRequestMetrics.TrackOperation(as.Method, as.PollingURL, as.Status == AsyncOperationStatusInProgress)
 	if err != nil {
-		as.ErrorMessage = err.Error()
+		as.ErrorMessage = WithRequestIDs(err).Error()
 	}
 	return nil
 }
 
+// NewAsyncOperation returns an AsyncOperation that records an operation the
+// controller just started with the supplied HTTP method. The supplied future
+// is used to track operations that Azure completes asynchronously; operations
+// that complete synchronously have none and are recorded as succeeded, or as
+// failed if the supplied error is not nil.
+func NewAsyncOperation(method string, f azure.FutureAPI, err error) v1alpha3.AsyncOperation {
+	now := metav1.Now()
+	op := v1alpha3.AsyncOperation{Method: method, StartTime: &now}
+	switch {
+	case err != nil:
+		op.Status = AsyncOperationStatusFailed
+		op.ErrorMessage = WithRequestIDs(err).Error()
+	case f != nil:
+		op.PollingURL = f.PollingURL()
+		op.Status = operationStatus(f.Status())
+		DefaultRequestMetrics.TrackOperation(method, op.PollingURL, op.Status == AsyncOperationStatusInProgress)
+	default:
+		op.Status = AsyncOperationStatusSucceeded
+	}
+	return op
+}
+
+// operationStatus returns the AsyncOperation status corresponding to the
+// status of a future. Futures report the provisioning state of the resource
+// they act upon, e.g. Creating, until they are polled, so any status that is
+// not terminal means the operation is in progress.
+func operationStatus(s string) string {
+	switch {
+	case strings.EqualFold(s, AsyncOperationStatusSucceeded):
+		return AsyncOperationStatusSucceeded
+	case strings.EqualFold(s, AsyncOperationStatusFailed):
+		return AsyncOperationStatusFailed
+	case strings.EqualFold(s, asyncOperationStatusCanceled):
+		return asyncOperationStatusCanceled
+	default:
+		return AsyncOperationStatusInProgress
+	}
+}
+
+// IsOperationInProgress returns true if the supplied operation is still
+// ongoing and was started with one of the supplied HTTP methods. Operations
+// started with any method are considered if no methods are supplied.
+func IsOperationInProgress(op v1alpha3.AsyncOperation, methods ...string) bool {
+	if op.Status != AsyncOperationStatusInProgress {
+		return false
+	}
+	if len(methods) == 0 {
+		return true
+	}
+	for _, m := range methods {
+		if op.Method == m {
+			return true
+		}
+	}
+	return false
+}
+
+// AnnotationKeyCreateOperation is the annotation of a managed resource that
+// records the operation its Create method started. The managed reconciler
+// resets the status of a managed resource to its persisted state after calling
+// Create, but persists its annotations, so the operation is recorded here too
+// until it has been persisted in the status.
+const AnnotationKeyCreateOperation = "azure.crossplane.io/create-operation"
+
+// RecordCreateOperation records the supplied operation, which the Create method
+// of the supplied object started, such that RestoreCreateOperation can restore
+// it once it has been removed from the object's status. Operations that have
+// already finished are not recorded.
+func RecordCreateOperation(o metav1.Object, op v1alpha3.AsyncOperation) {
+	if op.Status != AsyncOperationStatusInProgress {
+		meta.RemoveAnnotations(o, AnnotationKeyCreateOperation)
+		return
+	}
+	b, err := json.Marshal(op)
+	if err != nil {
+		return
+	}
+	meta.AddAnnotations(o, map[string]string{AnnotationKeyCreateOperation: string(b)})
+}
+
+// RestoreCreateOperation restores the operation recorded by
+// RecordCreateOperation into the supplied operation, unless the latter was
+// started more recently. Once the supplied operation, which must be read from
+// the persisted status, is the recorded one or a more recent one the recorded
+// operation is removed from the object. It returns true if it was removed, in
+// which case the object must be updated, e.g. by reporting it as late
+// initialized, for its removal to be persisted.
+func RestoreCreateOperation(o metav1.Object, op *v1alpha3.AsyncOperation) bool {
+	v, ok := o.GetAnnotations()[AnnotationKeyCreateOperation]
+	if !ok {
+		return false
+	}
+	created := v1alpha3.AsyncOperation{}
+	if err := json.Unmarshal([]byte(v), &created); err != nil || created.StartTime == nil {
+		meta.RemoveAnnotations(o, AnnotationKeyCreateOperation)
+		return true
+	}
+	if op.StartTime != nil && !op.StartTime.Before(created.StartTime) {
+		meta.RemoveAnnotations(o, AnnotationKeyCreateOperation)
+		return true
+	}
+	*op = created
+	return false
+}
+
 // IsNotFound returns a value indicating whether the given error represents that the resource was not found.
 func IsNotFound(err error) bool {
 	var aErr autorest.DetailedError
@@ -26,9 +26,15 @@ import (
 	"testing"
 
 	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/azure"
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/onsi/gomega"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
 	"github.com/crossplane/crossplane-runtime/pkg/test"
 
 	"github.com/crossplane-contrib/provider-azure/apis/v1alpha3"
@@ -80,6 +86,25 @@ func TestFetchAsyncOperation(t *testing.T) {
 		want want
 	}{
 		"NoOperation": {},
+		"Completed": {
+			args: args{
+				as: &v1alpha3.AsyncOperation{
+					Method:     http.MethodPut,
+					PollingURL: pollingURL,
+					Status:     AsyncOperationStatusSucceeded,
+				},
+				sender: autorest.SenderFunc(func(req *http.Request) (*http.Response, error) {
+					return nil, errors.New("completed operations should not be fetched")
+				}),
+			},
+			want: want{
+				op: &v1alpha3.AsyncOperation{
+					Method:     http.MethodPut,
+					PollingURL: pollingURL,
+					Status:     AsyncOperationStatusSucceeded,
+				},
+			},
+		},
 		"InProgress": {
 			args: args{
 				as: &v1alpha3.AsyncOperation{
@@ -145,6 +170,200 @@ func TestFetchAsyncOperation(t *testing.T) {
 
 }
 
+func TestNewAsyncOperation(t *testing.T) {
+	inProgress := &azure.Future{}
+	if err := inProgress.UnmarshalJSON([]byte(`{"method":"PUT","pollingMethod":"AsyncOperation","pollingURI":"crossplane.io","lroState":"InProgress"}`)); err != nil {
+		t.Fatal(err)
+	}
+	creating := &azure.Future{}
+	if err := creating.UnmarshalJSON([]byte(`{"method":"PUT","pollingMethod":"AsyncOperation","pollingURI":"crossplane.io","lroState":"Creating"}`)); err != nil {
+		t.Fatal(err)
+	}
+
+	type args struct {
+		method string
+		f      azure.FutureAPI
+		err    error
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   v1alpha3.AsyncOperation
+	}{
+		"Async": {
+			reason: "Operations with a future should be tracked using its polling URL",
+			args:   args{method: http.MethodPut, f: inProgress},
+			want:   v1alpha3.AsyncOperation{Method: http.MethodPut, PollingURL: "crossplane.io", Status: AsyncOperationStatusInProgress},
+		},
+		"ProvisioningState": {
+			reason: "Futures that report the provisioning state of their resource should be recorded as in progress",
+			args:   args{method: http.MethodPut, f: creating},
+			want:   v1alpha3.AsyncOperation{Method: http.MethodPut, PollingURL: "crossplane.io", Status: AsyncOperationStatusInProgress},
+		},
+		"Sync": {
+			reason: "Operations without a future should be recorded as succeeded",
+			args:   args{method: http.MethodPatch},
+			want:   v1alpha3.AsyncOperation{Method: http.MethodPatch, Status: AsyncOperationStatusSucceeded},
+		},
+		"Failed": {
+			reason: "Operations that could not be started should be recorded as failed",
+			args:   args{method: http.MethodDelete, err: errors.New("boom")},
+			want:   v1alpha3.AsyncOperation{Method: http.MethodDelete, Status: AsyncOperationStatusFailed, ErrorMessage: "boom"},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := NewAsyncOperation(tc.args.method, tc.args.f, tc.args.err)
+			if diff := cmp.Diff(tc.want, got, cmpopts.IgnoreFields(v1alpha3.AsyncOperation{}, "StartTime")); diff != "" {
+				t.Errorf("\n%s\nNewAsyncOperation(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			if got.StartTime == nil {
+				t.Errorf("\n%s\nNewAsyncOperation(...): StartTime was not set", tc.reason)
+			}
+		})
+	}
+}
+
+func TestIsOperationInProgress(t *testing.T) {
+	type args struct {
+		op      v1alpha3.AsyncOperation
+		methods []string
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   bool
+	}{
+		"NoOperation": {
+			reason: "Resources that never started an operation have none in progress",
+			want:   false,
+		},
+		"AnyMethod": {
+			reason: "Operations with any method should be considered if no methods are supplied",
+			args:   args{op: v1alpha3.AsyncOperation{Method: http.MethodDelete, Status: AsyncOperationStatusInProgress}},
+			want:   true,
+		},
+		"OtherMethod": {
+			reason: "Operations with other methods than the supplied ones should not be considered",
+			args: args{
+				op:      v1alpha3.AsyncOperation{Method: http.MethodDelete, Status: AsyncOperationStatusInProgress},
+				methods: []string{http.MethodPut},
+			},
+			want: false,
+		},
+		"Completed": {
+			reason: "Completed operations are not in progress",
+			args: args{
+				op:      v1alpha3.AsyncOperation{Method: http.MethodPut, Status: AsyncOperationStatusSucceeded},
+				methods: []string{http.MethodPut},
+			},
+			want: false,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := IsOperationInProgress(tc.args.op, tc.args.methods...)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nIsOperationInProgress(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestRecordCreateOperation(t *testing.T) {
+	earlier := metav1.Unix(1600000000, 0)
+
+	cases := map[string]struct {
+		reason string
+		op     v1alpha3.AsyncOperation
+		want   bool
+	}{
+		"InProgress": {
+			reason: "An operation that is in progress should be recorded",
+			op:     v1alpha3.AsyncOperation{Method: http.MethodPut, Status: AsyncOperationStatusInProgress, StartTime: &earlier},
+			want:   true,
+		},
+		"Finished": {
+			reason: "An operation that has already finished should leave no annotation",
+			op:     v1alpha3.AsyncOperation{Method: http.MethodPut, Status: AsyncOperationStatusSucceeded, StartTime: &earlier},
+			want:   false,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			mg := &fake.Managed{}
+			meta.AddAnnotations(mg, map[string]string{AnnotationKeyCreateOperation: "{}"})
+			RecordCreateOperation(mg, tc.op)
+			_, got := mg.GetAnnotations()[AnnotationKeyCreateOperation]
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nRecordCreateOperation(...): -want annotated, +got annotated:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestRestoreCreateOperation(t *testing.T) {
+	earlier := metav1.Unix(1600000000, 0)
+	later := metav1.Unix(1600000060, 0)
+	created := v1alpha3.AsyncOperation{Method: http.MethodPut, PollingURL: "crossplane.io", Status: AsyncOperationStatusInProgress, StartTime: &earlier}
+
+	type want struct {
+		op        v1alpha3.AsyncOperation
+		forgotten bool
+		annotated bool
+	}
+	cases := map[string]struct {
+		reason  string
+		created *v1alpha3.AsyncOperation
+		op      v1alpha3.AsyncOperation
+		want    want
+	}{
+		"NotRecorded": {
+			reason: "The operation should not change if Create recorded none",
+			op:     v1alpha3.AsyncOperation{Method: http.MethodDelete, Status: AsyncOperationStatusSucceeded, StartTime: &later},
+			want:   want{op: v1alpha3.AsyncOperation{Method: http.MethodDelete, Status: AsyncOperationStatusSucceeded, StartTime: &later}},
+		},
+		"Lost": {
+			reason:  "The operation recorded by Create should be restored and kept recorded if it is missing from the status",
+			created: &created,
+			want:    want{op: created, annotated: true},
+		},
+		"Newer": {
+			reason:  "An operation started after the one recorded by Create should be kept, and the recorded one forgotten",
+			created: &created,
+			op:      v1alpha3.AsyncOperation{Method: http.MethodDelete, Status: AsyncOperationStatusInProgress, StartTime: &later},
+			want: want{
+				op:        v1alpha3.AsyncOperation{Method: http.MethodDelete, Status: AsyncOperationStatusInProgress, StartTime: &later},
+				forgotten: true,
+			},
+		},
+		"Persisted": {
+			reason:  "The operation recorded by Create should not overwrite its own progress, and should be forgotten, once persisted in the status",
+			created: &created,
+			op:      v1alpha3.AsyncOperation{Method: http.MethodPut, PollingURL: "crossplane.io", Status: AsyncOperationStatusSucceeded, StartTime: &earlier},
+			want: want{
+				op:        v1alpha3.AsyncOperation{Method: http.MethodPut, PollingURL: "crossplane.io", Status: AsyncOperationStatusSucceeded, StartTime: &earlier},
+				forgotten: true,
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			mg := &fake.Managed{}
+			if tc.created != nil {
+				RecordCreateOperation(mg, *tc.created)
+			}
+			op := tc.op
+			forgotten := RestoreCreateOperation(mg, &op)
+			_, annotated := mg.GetAnnotations()[AnnotationKeyCreateOperation]
+			got := want{op: op, forgotten: forgotten, annotated: annotated}
+			if diff := cmp.Diff(tc.want, got, cmp.AllowUnexported(want{})); diff != "" {
+				t.Errorf("\n%s\nRestoreCreateOperation(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
 func TestIsNotFound(t *testing.T) {
 	g := gomega.NewGomegaWithT(t)
 
@@ -163,6 +382,35 @@ func TestIsNotFound(t *testing.T) {
 	}
 }
 
+func TestSetSubscriptionOverride(t *testing.T) {
+	cases := map[string]struct {
+		reason      string
+		annotations map[string]string
+		want        string
+	}{
+		"NoOverride": {
+			reason: "The subscription of the ProviderConfig should be used if the resource does not select one",
+			want:   "default",
+		},
+		"Override": {
+			reason:      "The subscription selected by the resource should take precedence",
+			annotations: map[string]string{AnnotationKeySubscriptionID: "override"},
+			want:        "override",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			mg := &fake.Managed{ObjectMeta: metav1.ObjectMeta{Annotations: tc.annotations}}
+			creds := map[string]string{CredentialsKeySubscriptionID: "default"}
+			SetSubscriptionOverride(mg, creds)
+			if diff := cmp.Diff(tc.want, creds[CredentialsKeySubscriptionID]); diff != "" {
+				t.Errorf("\n%s\nSetSubscriptionOverride(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
 func TestStringHelpers(t *testing.T) {
 	t.Run("ToStringMap", func(t *testing.T) {
 		original := make(map[string]*string)
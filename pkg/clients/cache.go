@@ -0,0 +1,194 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azure
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"sync"
+
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	"github.com/crossplane-contrib/provider-azure/apis/v1beta1"
+)
+
+// Error strings.
+const (
+	errGetCredentialsSecret = "cannot get credentials secret"
+	errExtractCredentials   = "cannot extract credentials"
+)
+
+var (
+	authorizerCacheHits = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "crossplane_azure_authorizer_cache_hits_total",
+		Help: "Number of times the credentials and authorizer of a ProviderConfig were served from the cache.",
+	})
+	authorizerCacheMisses = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "crossplane_azure_authorizer_cache_misses_total",
+		Help: "Number of times the credentials and authorizer of a ProviderConfig had to be built.",
+	})
+)
+
+func init() {
+	metrics.Registry.MustRegister(authorizerCacheHits, authorizerCacheMisses)
+}
+
+// DefaultAuthorizerCache is the AuthorizerCache used by UseProviderConfig.
+var DefaultAuthorizerCache = NewAuthorizerCache(authorizerCacheHits, authorizerCacheMisses)
+
+type authorizerCacheEntry struct {
+	name        string
+	version     string
+	creds       map[string]string
+	authorizers map[string]autorest.Authorizer
+}
+
+// An AuthorizerCache caches the credentials and authorizers of each
+// ProviderConfig, so that managed resources sharing a ProviderConfig share its
+// Azure AD tokens. Authorizers are cached per resource, e.g. Azure Resource
+// Manager or the Azure AD Graph, since each needs its own token. Tokens are
+// refreshed when they are about to expire. An entry is replaced when the
+// ProviderConfig's spec or its credentials change.
+type AuthorizerCache struct {
+	mu      sync.Mutex
+	entries map[types.UID]authorizerCacheEntry
+
+	hits   prometheus.Counter
+	misses prometheus.Counter
+}
+
+// NewAuthorizerCache returns an empty AuthorizerCache that counts its hits
+// and misses using the supplied counters.
+func NewAuthorizerCache(hits, misses prometheus.Counter) *AuthorizerCache {
+	return &AuthorizerCache{
+		entries: map[types.UID]authorizerCacheEntry{},
+		hits:    hits,
+		misses:  misses,
+	}
+}
+
+// Get returns the credentials of the supplied ProviderConfig and an authorizer
+// for its Azure Resource Manager endpoint. The returned credentials may be
+// modified by the caller.
+func (ac *AuthorizerCache) Get(ctx context.Context, c client.Client, pc *v1beta1.ProviderConfig) (map[string]string, autorest.Authorizer, error) {
+	return ac.GetForResource(ctx, c, pc, CredentialsKeyResourceManagerEndpointURL)
+}
+
+// GetForResource returns the credentials of the supplied ProviderConfig and an
+// authorizer for the resource whose URL its credentials store under the
+// supplied key, e.g. CredentialsKeyActiveDirectoryGraphResourceID. The
+// returned credentials may be modified by the caller.
+func (ac *AuthorizerCache) GetForResource(ctx context.Context, c client.Client, pc *v1beta1.ProviderConfig, resourceKey string) (map[string]string, autorest.Authorizer, error) {
+	v, err := credentialsVersion(ctx, c, pc)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ac.mu.Lock()
+	e, ok := ac.entries[pc.GetUID()]
+	current := ok && e.version == v
+	var a autorest.Authorizer
+	if current {
+		a = e.authorizers[e.creds[resourceKey]]
+	}
+	ac.mu.Unlock()
+	if a != nil {
+		ac.hits.Inc()
+		return copyCredentials(e.creds), a, nil
+	}
+	ac.misses.Inc()
+
+	if !current {
+		m, err := ProviderConfigCredentials(ctx, c, pc)
+		if err != nil {
+			return nil, nil, err
+		}
+		e = authorizerCacheEntry{name: pc.GetName(), version: v, creds: m, authorizers: map[string]autorest.Authorizer{}}
+	}
+
+	resource := e.creds[resourceKey]
+	a, err = NewResourceAuthorizer(e.creds, resource)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, errGetAuthorizer)
+	}
+
+	ac.mu.Lock()
+	e.authorizers[resource] = a
+	ac.entries[pc.GetUID()] = e
+	ac.mu.Unlock()
+	return copyCredentials(e.creds), a, nil
+}
+
+// Delete the cached credentials and authorizer of the ProviderConfig with the
+// supplied name. Entries are keyed by UID, but the UID of a ProviderConfig is
+// no longer known once it has been deleted.
+func (ac *AuthorizerCache) Delete(name string) {
+	ac.mu.Lock()
+	for uid, e := range ac.entries {
+		if e.name == name {
+			delete(ac.entries, uid)
+		}
+	}
+	ac.mu.Unlock()
+}
+
+// credentialsVersion returns a string that changes whenever the credentials
+// described by the supplied ProviderConfig may have changed. Credentials read
+// from anywhere but the ProviderConfig's spec are hashed, since nothing else
+// tells when they were rotated.
+func credentialsVersion(ctx context.Context, c client.Client, pc *v1beta1.ProviderConfig) (string, error) {
+	switch pc.Spec.Credentials.Source { //nolint:exhaustive
+	case v1beta1.CredentialsSourceOIDCTokenFile, v1beta1.CredentialsSourceManagedIdentity:
+		// These credentials are built from the ProviderConfig's spec. The
+		// token file is read whenever a token is refreshed.
+		return fmt.Sprintf("%d", pc.GetGeneration()), nil
+	case xpv1.CredentialsSourceSecret:
+		ref := pc.Spec.Credentials.SecretRef
+		if ref == nil {
+			return fmt.Sprintf("%d", pc.GetGeneration()), nil
+		}
+		s := &corev1.Secret{}
+		if err := c.Get(ctx, types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}, s); err != nil {
+			return "", errors.Wrap(err, errGetCredentialsSecret)
+		}
+		return fmt.Sprintf("%d/%s", pc.GetGeneration(), s.GetResourceVersion()), nil
+	default:
+		data, err := resource.CommonCredentialExtractor(ctx, pc.Spec.Credentials.Source, c, pc.Spec.Credentials.CommonCredentialSelectors)
+		if err != nil {
+			return "", errors.Wrap(err, errExtractCredentials)
+		}
+		return fmt.Sprintf("%d/%x", pc.GetGeneration(), sha256.Sum256(data)), nil
+	}
+}
+
+func copyCredentials(m map[string]string) map[string]string {
+	out := make(map[string]string, len(m))
+	for k, v := range m {
+		out[k] = v
+	}
+	return out
+}
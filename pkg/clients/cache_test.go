@@ -0,0 +1,178 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azure
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane-contrib/provider-azure/apis/v1beta1"
+)
+
+func TestAuthorizerCache(t *testing.T) {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "crossplane-system", Name: "azure", ResourceVersion: "1"},
+		Data: map[string][]byte{"credentials": []byte(`{
+			"clientId": "` + testClientID + `",
+			"clientSecret": "secret",
+			"tenantId": "` + testTenantID + `",
+			"subscriptionId": "` + testSubscriptionID + `"
+		}`)},
+	}
+	kube := &test.MockClient{
+		MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+			secret.DeepCopyInto(obj.(*corev1.Secret))
+			return nil
+		}),
+	}
+	pc := &v1beta1.ProviderConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "default", UID: "pc-uid", Generation: 1},
+		Spec: v1beta1.ProviderConfigSpec{
+			Credentials: v1beta1.ProviderCredentials{
+				Source: xpv1.CredentialsSourceSecret,
+				CommonCredentialSelectors: xpv1.CommonCredentialSelectors{
+					SecretRef: &xpv1.SecretKeySelector{
+						SecretReference: xpv1.SecretReference{Namespace: secret.Namespace, Name: secret.Name},
+						Key:             "credentials",
+					},
+				},
+			},
+		},
+	}
+
+	hits := prometheus.NewCounter(prometheus.CounterOpts{Name: "hits"})
+	misses := prometheus.NewCounter(prometheus.CounterOpts{Name: "misses"})
+	ac := NewAuthorizerCache(hits, misses)
+
+	get := func() (map[string]string, interface{}) {
+		t.Helper()
+		m, a, err := ac.Get(context.Background(), kube, pc)
+		if err != nil {
+			t.Fatalf("Get(...): %s", err)
+		}
+		return m, a
+	}
+
+	m1, a1 := get()
+	m1[CredentialsKeySubscriptionID] = "modified"
+	m2, a2 := get()
+	if a1 != a2 {
+		t.Errorf("Get(...): want the cached authorizer to be reused")
+	}
+	if diff := cmp.Diff(testSubscriptionID, m2[CredentialsKeySubscriptionID]); diff != "" {
+		t.Errorf("Get(...): callers must not be able to modify cached credentials: -want, +got:\n%s", diff)
+	}
+
+	// Authorizers for other resources must be cached separately.
+	getGraph := func() interface{} {
+		t.Helper()
+		_, a, err := ac.GetForResource(context.Background(), kube, pc, CredentialsKeyActiveDirectoryGraphResourceID)
+		if err != nil {
+			t.Fatalf("GetForResource(...): %s", err)
+		}
+		return a
+	}
+	g1 := getGraph()
+	if g1 == a2 {
+		t.Errorf("GetForResource(...): want a separate authorizer for the Azure AD Graph")
+	}
+	if g2 := getGraph(); g2 != g1 {
+		t.Errorf("GetForResource(...): want the cached Azure AD Graph authorizer to be reused")
+	}
+
+	// A change to the credentials Secret must replace the cached authorizer.
+	secret.ResourceVersion = "2"
+	_, a3 := get()
+	if a3 == a2 {
+		t.Errorf("Get(...): want a new authorizer after the credentials Secret changed")
+	}
+
+	// So must a change to the ProviderConfig spec.
+	pc.Generation = 2
+	_, a4 := get()
+	if a4 == a3 {
+		t.Errorf("Get(...): want a new authorizer after the ProviderConfig changed")
+	}
+
+	// Deleting the ProviderConfig must evict its cached authorizer.
+	ac.Delete(pc.GetName())
+	_, a5 := get()
+	if a5 == a4 {
+		t.Errorf("Get(...): want a new authorizer after the ProviderConfig was deleted")
+	}
+
+	if diff := cmp.Diff(float64(2), testutil.ToFloat64(hits)); diff != "" {
+		t.Errorf("hits: -want, +got:\n%s", diff)
+	}
+	if diff := cmp.Diff(float64(5), testutil.ToFloat64(misses)); diff != "" {
+		t.Errorf("misses: -want, +got:\n%s", diff)
+	}
+}
+
+func TestAuthorizerCacheEnvironment(t *testing.T) {
+	creds := func(secret string) string {
+		return `{
+			"clientId": "` + testClientID + `",
+			"clientSecret": "` + secret + `",
+			"tenantId": "` + testTenantID + `",
+			"subscriptionId": "` + testSubscriptionID + `"
+		}`
+	}
+	pc := &v1beta1.ProviderConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "default", UID: "pc-uid", Generation: 1},
+		Spec: v1beta1.ProviderConfigSpec{
+			Credentials: v1beta1.ProviderCredentials{
+				Source: xpv1.CredentialsSourceEnvironment,
+				CommonCredentialSelectors: xpv1.CommonCredentialSelectors{
+					Env: &xpv1.EnvSelector{Name: "AZURE_CREDENTIALS_TEST"},
+				},
+			},
+		},
+	}
+	ac := NewAuthorizerCache(prometheus.NewCounter(prometheus.CounterOpts{Name: "hits"}), prometheus.NewCounter(prometheus.CounterOpts{Name: "misses"}))
+
+	get := func() interface{} {
+		t.Helper()
+		_, a, err := ac.Get(context.Background(), &test.MockClient{}, pc)
+		if err != nil {
+			t.Fatalf("Get(...): %s", err)
+		}
+		return a
+	}
+
+	t.Setenv("AZURE_CREDENTIALS_TEST", creds("secret"))
+	a1 := get()
+	if a2 := get(); a2 != a1 {
+		t.Errorf("Get(...): want the cached authorizer to be reused")
+	}
+
+	// Rotated credentials must replace the cached authorizer.
+	t.Setenv("AZURE_CREDENTIALS_TEST", creds("rotated"))
+	if a3 := get(); a3 == a1 {
+		t.Errorf("Get(...): want a new authorizer after the credentials were rotated")
+	}
+}
@@ -0,0 +1,117 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azure
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Azure/go-autorest/autorest/azure"
+	"github.com/pkg/errors"
+
+	"github.com/crossplane-contrib/provider-azure/apis/v1beta1"
+)
+
+// Error strings.
+const (
+	errUnknownCloud          = "unknown Azure cloud"
+	errMissingCloudEndpoints = "activeDirectory and resourceManager endpoints must be set when the cloud is Custom"
+)
+
+// knownClouds are the named Azure clouds that may be selected by a
+// ProviderConfig.
+var knownClouds = map[v1beta1.CloudName]azure.Environment{
+	v1beta1.CloudAzurePublic:       azure.PublicCloud,
+	v1beta1.CloudAzureChina:        azure.ChinaCloud,
+	v1beta1.CloudAzureUSGovernment: azure.USGovernmentCloud,
+}
+
+// CloudEnvironment returns the Azure environment described by the supplied
+// cloud. The Azure public cloud is returned if it is nil.
+func CloudEnvironment(c *v1beta1.Cloud) (azure.Environment, error) {
+	if c == nil {
+		return azure.PublicCloud, nil
+	}
+	env := azure.Environment{Name: string(c.Name)}
+	if c.Name != v1beta1.CloudCustom {
+		e, ok := knownClouds[c.Name]
+		if !ok {
+			return azure.Environment{}, errors.Errorf("%s: %s", errUnknownCloud, c.Name)
+		}
+		env = e
+	}
+	if ep := c.Endpoints; ep != nil {
+		override(&env.ActiveDirectoryEndpoint, ep.ActiveDirectory)
+		override(&env.ResourceManagerEndpoint, ep.ResourceManager)
+		override(&env.GraphEndpoint, ep.Graph)
+		override(&env.ResourceIdentifiers.KeyVault, ep.KeyVaultResource)
+		override(&env.KeyVaultDNSSuffix, ep.KeyVaultDNSSuffix)
+		override(&env.StorageEndpointSuffix, ep.StorageEndpointSuffix)
+	}
+	if env.ActiveDirectoryEndpoint == "" || env.ResourceManagerEndpoint == "" {
+		return azure.Environment{}, errors.New(errMissingCloudEndpoints)
+	}
+	return env, nil
+}
+
+func override(s *string, v *string) {
+	if v != nil && *v != "" {
+		*s = *v
+	}
+}
+
+// credentialsEnvironment returns the well-known Azure environment whose
+// Resource Manager endpoint matches the one found in the supplied credentials,
+// or the Azure public cloud if there is no such environment.
+func credentialsEnvironment(creds map[string]string) azure.Environment {
+	rm := strings.TrimSuffix(creds[CredentialsKeyResourceManagerEndpointURL], "/")
+	for _, env := range knownClouds {
+		if rm != "" && strings.TrimSuffix(env.ResourceManagerEndpoint, "/") == rm {
+			return env
+		}
+	}
+	return azure.PublicCloud
+}
+
+// setCloudEndpoints writes the endpoints of the supplied environment to the
+// supplied credentials. Endpoints already present in the credentials are
+// only replaced if overwrite is true.
+func setCloudEndpoints(creds map[string]string, env azure.Environment, overwrite bool) {
+	for k, v := range map[string]string{
+		CredentialsKeyActiveDirectoryEndpointURL:     env.ActiveDirectoryEndpoint,
+		CredentialsKeyResourceManagerEndpointURL:     env.ResourceManagerEndpoint,
+		CredentialsKeyActiveDirectoryGraphResourceID: env.GraphEndpoint,
+		CredentialsKeyKeyVaultResourceID:             env.ResourceIdentifiers.KeyVault,
+		CredentialsKeyKeyVaultDNSSuffix:              env.KeyVaultDNSSuffix,
+		CredentialsKeyStorageEndpointSuffix:          env.StorageEndpointSuffix,
+	} {
+		if v == "" || (creds[k] != "" && !overwrite) {
+			continue
+		}
+		creds[k] = v
+	}
+}
+
+// KeyVaultBaseURL returns the base URL of the supplied Key Vault. The vault may
+// either be a URL, which is returned unchanged, or the name of a vault in the
+// cloud described by the supplied credentials.
+func KeyVaultBaseURL(creds map[string]string, vault string) string {
+	if strings.Contains(vault, "://") {
+		return vault
+	}
+	return fmt.Sprintf("https://%s.%s", vault, creds[CredentialsKeyKeyVaultDNSSuffix])
+}
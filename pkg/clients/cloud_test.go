@@ -0,0 +1,183 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azure
+
+import (
+	"testing"
+
+	"github.com/Azure/go-autorest/autorest/azure"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane-contrib/provider-azure/apis/v1beta1"
+)
+
+func TestCloudEnvironment(t *testing.T) {
+	custom := azure.Environment{
+		Name:                    string(v1beta1.CloudCustom),
+		ActiveDirectoryEndpoint: "https://login.example.org/",
+		ResourceManagerEndpoint: "https://management.example.org/",
+		StorageEndpointSuffix:   "core.example.org",
+	}
+	china := azure.ChinaCloud
+	china.StorageEndpointSuffix = "core.example.cn"
+
+	type want struct {
+		env azure.Environment
+		err error
+	}
+	cases := map[string]struct {
+		reason string
+		cloud  *v1beta1.Cloud
+		want   want
+	}{
+		"Unset": {
+			reason: "The Azure public cloud should be used if no cloud is set",
+			want:   want{env: azure.PublicCloud},
+		},
+		"AzureChina": {
+			reason: "The well-known endpoints of a named cloud should be used",
+			cloud:  &v1beta1.Cloud{Name: v1beta1.CloudAzureChina},
+			want:   want{env: azure.ChinaCloud},
+		},
+		"AzureChinaOverride": {
+			reason: "Endpoints should override the well-known endpoints of a named cloud",
+			cloud: &v1beta1.Cloud{
+				Name:      v1beta1.CloudAzureChina,
+				Endpoints: &v1beta1.CloudEndpoints{StorageEndpointSuffix: to.StringPtr("core.example.cn")},
+			},
+			want: want{env: china},
+		},
+		"Custom": {
+			reason: "The endpoints of a Custom cloud should be used",
+			cloud: &v1beta1.Cloud{
+				Name: v1beta1.CloudCustom,
+				Endpoints: &v1beta1.CloudEndpoints{
+					ActiveDirectory:       to.StringPtr(custom.ActiveDirectoryEndpoint),
+					ResourceManager:       to.StringPtr(custom.ResourceManagerEndpoint),
+					StorageEndpointSuffix: to.StringPtr(custom.StorageEndpointSuffix),
+				},
+			},
+			want: want{env: custom},
+		},
+		"CustomMissingEndpoints": {
+			reason: "A Custom cloud without Azure AD and Resource Manager endpoints should be rejected",
+			cloud:  &v1beta1.Cloud{Name: v1beta1.CloudCustom},
+			want:   want{err: errors.New(errMissingCloudEndpoints)},
+		},
+		"Unknown": {
+			reason: "An unknown cloud should be rejected",
+			cloud:  &v1beta1.Cloud{Name: "AzureGermany"},
+			want:   want{err: errors.Errorf("%s: %s", errUnknownCloud, "AzureGermany")},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			env, err := CloudEnvironment(tc.cloud)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nCloudEnvironment(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.env, env); diff != "" {
+				t.Errorf("\n%s\nCloudEnvironment(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestSetCloudEndpoints(t *testing.T) {
+	cases := map[string]struct {
+		reason    string
+		creds     map[string]string
+		env       azure.Environment
+		overwrite bool
+		want      map[string]string
+	}{
+		"InferFromResourceManager": {
+			reason: "Missing endpoints should be filled from the cloud whose Resource Manager endpoint is in the credentials",
+			creds: map[string]string{
+				CredentialsKeyResourceManagerEndpointURL: "https://management.chinacloudapi.cn",
+			},
+			want: map[string]string{
+				CredentialsKeyActiveDirectoryEndpointURL:     azure.ChinaCloud.ActiveDirectoryEndpoint,
+				CredentialsKeyResourceManagerEndpointURL:     "https://management.chinacloudapi.cn",
+				CredentialsKeyActiveDirectoryGraphResourceID: azure.ChinaCloud.GraphEndpoint,
+				CredentialsKeyKeyVaultResourceID:             azure.ChinaCloud.ResourceIdentifiers.KeyVault,
+				CredentialsKeyKeyVaultDNSSuffix:              azure.ChinaCloud.KeyVaultDNSSuffix,
+				CredentialsKeyStorageEndpointSuffix:          azure.ChinaCloud.StorageEndpointSuffix,
+			},
+		},
+		"Overwrite": {
+			reason: "Endpoints in the credentials should be replaced when overwrite is true",
+			creds: map[string]string{
+				CredentialsKeyResourceManagerEndpointURL: azure.PublicCloud.ResourceManagerEndpoint,
+			},
+			env:       azure.USGovernmentCloud,
+			overwrite: true,
+			want: map[string]string{
+				CredentialsKeyActiveDirectoryEndpointURL:     azure.USGovernmentCloud.ActiveDirectoryEndpoint,
+				CredentialsKeyResourceManagerEndpointURL:     azure.USGovernmentCloud.ResourceManagerEndpoint,
+				CredentialsKeyActiveDirectoryGraphResourceID: azure.USGovernmentCloud.GraphEndpoint,
+				CredentialsKeyKeyVaultResourceID:             azure.USGovernmentCloud.ResourceIdentifiers.KeyVault,
+				CredentialsKeyKeyVaultDNSSuffix:              azure.USGovernmentCloud.KeyVaultDNSSuffix,
+				CredentialsKeyStorageEndpointSuffix:          azure.USGovernmentCloud.StorageEndpointSuffix,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			env := tc.env
+			if !tc.overwrite {
+				env = credentialsEnvironment(tc.creds)
+			}
+			setCloudEndpoints(tc.creds, env, tc.overwrite)
+			if diff := cmp.Diff(tc.want, tc.creds); diff != "" {
+				t.Errorf("\n%s\nsetCloudEndpoints(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestKeyVaultBaseURL(t *testing.T) {
+	creds := map[string]string{CredentialsKeyKeyVaultDNSSuffix: azure.ChinaCloud.KeyVaultDNSSuffix}
+
+	cases := map[string]struct {
+		vault string
+		want  string
+	}{
+		"Name": {
+			vault: "myvault",
+			want:  "https://myvault.vault.azure.cn",
+		},
+		"URL": {
+			vault: "https://myvault.vault.azure.net",
+			want:  "https://myvault.vault.azure.net",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.want, KeyVaultBaseURL(creds, tc.vault)); diff != "" {
+				t.Errorf("KeyVaultBaseURL(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
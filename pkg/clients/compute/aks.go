@@ -19,6 +19,7 @@ package compute
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"time"
 
 	"github.com/Azure/azure-sdk-for-go/services/authorization/mgmt/2015-07-01/authorization"
@@ -26,7 +27,7 @@ import (
 	"github.com/Azure/azure-sdk-for-go/services/containerservice/mgmt/2018-03-31/containerservice"
 	"github.com/Azure/azure-sdk-for-go/services/graphrbac/1.6/graphrbac"
 	"github.com/Azure/go-autorest/autorest"
-	"github.com/Azure/go-autorest/autorest/adal"
+	azureautorest "github.com/Azure/go-autorest/autorest/azure"
 	"github.com/Azure/go-autorest/autorest/date"
 	"github.com/Azure/go-autorest/autorest/to"
 	"github.com/google/uuid"
@@ -55,9 +56,11 @@ const (
 // resources they require.
 type AKSClient interface {
 	GetManagedCluster(ctx context.Context, ac *v1alpha3.AKSCluster) (containerservice.ManagedCluster, error)
-	EnsureManagedCluster(ctx context.Context, ac *v1alpha3.AKSCluster, secret string) error
+	EnsureManagedCluster(ctx context.Context, ac *v1alpha3.AKSCluster, secret string, defaultTags map[string]string) error
+	UpdateManagedClusterTags(ctx context.Context, ac *v1alpha3.AKSCluster, defaultTags map[string]string) (containerservice.ManagedClustersUpdateTagsFuture, error)
 	DeleteManagedCluster(ctx context.Context, ac *v1alpha3.AKSCluster) error
 	GetKubeConfig(ctx context.Context, ac *v1alpha3.AKSCluster) ([]byte, error)
+	GetRESTClient() autorest.Sender
 }
 
 // An AggregateClient aggregates the various clients used by the AKS controller.
@@ -69,39 +72,23 @@ type AggregateClient struct {
 }
 
 // NewAggregateClient produces the various clients used by the AKS controller.
-func NewAggregateClient(creds map[string]string, auth autorest.Authorizer) (AKSClient, error) {
-	mcc := containerservice.NewManagedClustersClient(creds[azure.CredentialsKeySubscriptionID])
-	mcc.Authorizer = auth
+// The supplied Azure AD Graph authorizer is used to manage the service
+// principals of AKS clusters.
+func NewAggregateClient(creds map[string]string, auth, graphAuth autorest.Authorizer) AKSClient {
+	mcc := containerservice.NewManagedClustersClientWithBaseURI(creds[azure.CredentialsKeyResourceManagerEndpointURL], creds[azure.CredentialsKeySubscriptionID])
+	azure.ConfigureClient(&mcc.Client, auth)
 	_ = mcc.AddToUserAgent(azure.UserAgent)
 
-	rac := authorization.NewRoleAssignmentsClient(creds[azure.CredentialsKeySubscriptionID])
-	rac.Authorizer = auth
+	rac := authorization.NewRoleAssignmentsClientWithBaseURI(creds[azure.CredentialsKeyResourceManagerEndpointURL], creds[azure.CredentialsKeySubscriptionID])
+	azure.ConfigureClient(&rac.Client, auth)
 	_ = rac.AddToUserAgent(azure.UserAgent)
 
-	cfg, err := adal.NewOAuthConfig(creds[azure.CredentialsKeyActiveDirectoryEndpointURL], creds[azure.CredentialsKeyTenantID])
-	if err != nil {
-		return nil, errors.Wrap(err, "cannot create OAuth configuration")
-	}
-
-	token, err := adal.NewServicePrincipalToken(*cfg,
-		creds[azure.CredentialsKeyClientID],
-		creds[azure.CredentialsKeyClientSecret],
-		creds[azure.CredentialsKeyActiveDirectoryGraphResourceID])
-	if err != nil {
-		return nil, errors.Wrap(err, "cannot create service principal token")
-	}
-	if err := token.Refresh(); err != nil {
-		return nil, errors.Wrap(err, "cannot refresh service principal token")
-	}
-
-	ta := autorest.NewBearerAuthorizer(token)
-
-	ac := graphrbac.NewApplicationsClient(creds[azure.CredentialsKeyTenantID])
-	ac.Authorizer = ta
+	ac := graphrbac.NewApplicationsClientWithBaseURI(creds[azure.CredentialsKeyActiveDirectoryGraphResourceID], creds[azure.CredentialsKeyTenantID])
+	azure.ConfigureClient(&ac.Client, graphAuth)
 	_ = ac.AddToUserAgent(azure.UserAgent)
 
-	spc := graphrbac.NewServicePrincipalsClient(creds[azure.CredentialsKeyTenantID])
-	spc.Authorizer = ta
+	spc := graphrbac.NewServicePrincipalsClientWithBaseURI(creds[azure.CredentialsKeyActiveDirectoryGraphResourceID], creds[azure.CredentialsKeyTenantID])
+	azure.ConfigureClient(&spc.Client, graphAuth)
 	_ = spc.AddToUserAgent(azure.UserAgent)
 
 	return AggregateClient{
@@ -109,7 +96,12 @@ func NewAggregateClient(creds map[string]string, auth autorest.Authorizer) (AKSC
 		Applications:      ac,
 		ServicePrincipals: spc,
 		RoleAssignments:   rac,
-	}, nil
+	}
+}
+
+// GetRESTClient returns the REST client used to manage AKS clusters.
+func (c AggregateClient) GetRESTClient() autorest.Sender {
+	return c.ManagedClusters.Client
 }
 
 // GetManagedCluster returns the requested Azure managed cluster.
@@ -118,35 +110,63 @@ func (c AggregateClient) GetManagedCluster(ctx context.Context, ac *v1alpha3.AKS
 }
 
 // EnsureManagedCluster ensures the supplied AKS cluster exists, including
-// ensuring any required service principals and role assignments exist.
-func (c AggregateClient) EnsureManagedCluster(ctx context.Context, ac *v1alpha3.AKSCluster, secret string) error {
+// ensuring any required service principals and role assignments exist. The
+// operation, successful or not, is recorded as the cluster's last operation.
+func (c AggregateClient) EnsureManagedCluster(ctx context.Context, ac *v1alpha3.AKSCluster, secret string, defaultTags map[string]string) error {
+	f, err := c.ensureManagedCluster(ctx, ac, secret, defaultTags)
+	ac.Status.AtProvider.LastOperation = azure.NewAsyncOperation(http.MethodPut, f, err)
+	return err
+}
+
+func (c AggregateClient) ensureManagedCluster(ctx context.Context, ac *v1alpha3.AKSCluster, secret string, defaultTags map[string]string) (azureautorest.FutureAPI, error) {
 	app, err := c.ensureApplication(ctx, meta.GetExternalName(ac), secret)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	sp, err := c.ensureServicePrincipal(ctx, to.String(app.AppID))
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	if err := c.ensureRoleAssignment(ctx, to.String(sp.ObjectID), NetworkContributorRoleID, ac.Spec.VnetSubnetID); err != nil {
-		return err
+		return nil, err
 	}
 
-	mc := newManagedCluster(ac, to.String(app.AppID), secret)
-	_, err = c.ManagedClusters.CreateOrUpdate(ctx, ac.Spec.ResourceGroupName, meta.GetExternalName(ac), mc)
-	return err
+	mc := newManagedCluster(ac, to.String(app.AppID), secret, defaultTags)
+	op, err := c.ManagedClusters.CreateOrUpdate(ctx, ac.Spec.ResourceGroupName, meta.GetExternalName(ac), mc)
+	if err != nil {
+		return nil, err
+	}
+	return op.FutureAPI, nil
+}
+
+// UpdateManagedClusterTags replaces the tags of the supplied AKS cluster with
+// its desired and ownership tags. It returns the future of the update, which
+// completes asynchronously.
+func (c AggregateClient) UpdateManagedClusterTags(ctx context.Context, ac *v1alpha3.AKSCluster, defaultTags map[string]string) (containerservice.ManagedClustersUpdateTagsFuture, error) {
+	t := containerservice.TagsObject{Tags: azure.NewTags(ac, defaultTags, ac.Spec.Tags)}
+	return c.ManagedClusters.UpdateTags(ctx, ac.Spec.ResourceGroupName, meta.GetExternalName(ac), t)
 }
 
 // DeleteManagedCluster deletes the supplied AKS cluster, including its service
-// principals and any role assignments.
+// principals and any role assignments. The operation, successful or not, is
+// recorded as the cluster's last operation.
 func (c AggregateClient) DeleteManagedCluster(ctx context.Context, ac *v1alpha3.AKSCluster) error {
+	f, err := c.deleteManagedCluster(ctx, ac)
+	ac.Status.AtProvider.LastOperation = azure.NewAsyncOperation(http.MethodDelete, f, err)
+	return err
+}
+
+func (c AggregateClient) deleteManagedCluster(ctx context.Context, ac *v1alpha3.AKSCluster) (azureautorest.FutureAPI, error) {
 	if err := c.deleteApplication(ctx, meta.GetExternalName(ac)); err != nil {
-		return err
+		return nil, err
 	}
-	_, err := c.ManagedClusters.Delete(ctx, ac.Spec.ResourceGroupName, meta.GetExternalName(ac))
-	return err
+	op, err := c.ManagedClusters.Delete(ctx, ac.Spec.ResourceGroupName, meta.GetExternalName(ac))
+	if err != nil {
+		return nil, err
+	}
+	return op.FutureAPI, nil
 }
 
 // GetKubeConfig produces a kubeconfig file that configures access to the
@@ -264,7 +284,7 @@ func (c AggregateClient) deleteApplication(ctx context.Context, name string) err
 	return nil
 }
 
-func newManagedCluster(c *v1alpha3.AKSCluster, appID, secret string) containerservice.ManagedCluster {
+func newManagedCluster(c *v1alpha3.AKSCluster, appID, secret string, defaultTags map[string]string) containerservice.ManagedCluster {
 	nodeCount := int32(v1alpha3.DefaultNodeCount)
 	if c.Spec.NodeCount != nil {
 		nodeCount = int32(*c.Spec.NodeCount)
@@ -273,6 +293,7 @@ func newManagedCluster(c *v1alpha3.AKSCluster, appID, secret string) containerse
 	p := containerservice.ManagedCluster{
 		Name:     to.StringPtr(meta.GetExternalName(c)),
 		Location: to.StringPtr(c.Spec.Location),
+		Tags:     azure.NewTags(c, defaultTags, c.Spec.Tags),
 		ManagedClusterProperties: &containerservice.ManagedClusterProperties{
 			KubernetesVersion: to.StringPtr(c.Spec.Version),
 			DNSPrefix:         to.StringPtr(c.Spec.DNSNamePrefix),
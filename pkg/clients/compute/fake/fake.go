@@ -20,16 +20,19 @@ import (
 	"context"
 
 	"github.com/Azure/azure-sdk-for-go/services/containerservice/mgmt/2018-03-31/containerservice"
+	"github.com/Azure/go-autorest/autorest"
 
 	"github.com/crossplane-contrib/provider-azure/apis/compute/v1alpha3"
 )
 
 // AKSClient is a fake AKS client.
 type AKSClient struct {
-	MockGetManagedCluster    func(ctx context.Context, ac *v1alpha3.AKSCluster) (containerservice.ManagedCluster, error)
-	MockEnsureManagedCluster func(ctx context.Context, ac *v1alpha3.AKSCluster, secret string) error
-	MockDeleteManagedCluster func(ctx context.Context, ac *v1alpha3.AKSCluster) error
-	MockGetKubeConfig        func(ctx context.Context, ac *v1alpha3.AKSCluster) ([]byte, error)
+	MockGetManagedCluster        func(ctx context.Context, ac *v1alpha3.AKSCluster) (containerservice.ManagedCluster, error)
+	MockEnsureManagedCluster     func(ctx context.Context, ac *v1alpha3.AKSCluster, secret string, defaultTags map[string]string) error
+	MockUpdateManagedClusterTags func(ctx context.Context, ac *v1alpha3.AKSCluster, defaultTags map[string]string) (containerservice.ManagedClustersUpdateTagsFuture, error)
+	MockDeleteManagedCluster     func(ctx context.Context, ac *v1alpha3.AKSCluster) error
+	MockGetKubeConfig            func(ctx context.Context, ac *v1alpha3.AKSCluster) ([]byte, error)
+	MockGetRESTClient            func() autorest.Sender
 }
 
 // GetManagedCluster calls MockGetManagedCluster.
@@ -38,8 +41,13 @@ func (c AKSClient) GetManagedCluster(ctx context.Context, ac *v1alpha3.AKSCluste
 }
 
 // EnsureManagedCluster calls MockEnsureManagedCluster.
-func (c AKSClient) EnsureManagedCluster(ctx context.Context, ac *v1alpha3.AKSCluster, secret string) error {
-	return c.MockEnsureManagedCluster(ctx, ac, secret)
+func (c AKSClient) EnsureManagedCluster(ctx context.Context, ac *v1alpha3.AKSCluster, secret string, defaultTags map[string]string) error {
+	return c.MockEnsureManagedCluster(ctx, ac, secret, defaultTags)
+}
+
+// UpdateManagedClusterTags calls MockUpdateManagedClusterTags.
+func (c AKSClient) UpdateManagedClusterTags(ctx context.Context, ac *v1alpha3.AKSCluster, defaultTags map[string]string) (containerservice.ManagedClustersUpdateTagsFuture, error) {
+	return c.MockUpdateManagedClusterTags(ctx, ac, defaultTags)
 }
 
 // DeleteManagedCluster calls DeleteManagedCluster.
@@ -51,3 +59,8 @@ func (c AKSClient) DeleteManagedCluster(ctx context.Context, ac *v1alpha3.AKSClu
 func (c AKSClient) GetKubeConfig(ctx context.Context, ac *v1alpha3.AKSCluster) ([]byte, error) {
 	return c.MockGetKubeConfig(ctx, ac)
 }
+
+// GetRESTClient calls MockGetRESTClient.
+func (c AKSClient) GetRESTClient() autorest.Sender {
+	return c.MockGetRESTClient()
+}
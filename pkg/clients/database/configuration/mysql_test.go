@@ -72,7 +72,7 @@ func TestIsMySQLConfigurationUpToDate(t *testing.T) {
 	}
 	for name, tt := range tests {
 		t.Run(name, func(t *testing.T) {
-			got := IsMySQLConfigurationUpToDate(tt.args.p, tt.args.in)
+			got := IsMySQLConfigurationUpToDate(tt.args.p, tt.args.in).UpToDate()
 			if diff := cmp.Diff(tt.want, got); diff != "" {
 				t.Errorf("IsMySQLConfigurationUpToDate(...): -want, +got\n%s", diff)
 			}
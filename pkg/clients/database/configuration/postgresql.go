@@ -24,7 +24,6 @@ import (
 	"github.com/Azure/go-autorest/autorest"
 
 	azuredbv1beta1 "github.com/crossplane-contrib/provider-azure/apis/database/v1beta1"
-	"github.com/crossplane-contrib/provider-azure/apis/v1alpha3"
 	azure "github.com/crossplane-contrib/provider-azure/pkg/clients"
 )
 
@@ -92,10 +91,7 @@ func (c *PostgreSQLConfigurationClient) update(ctx context.Context, cr *azuredbv
 	if err != nil {
 		return err
 	}
-	cr.Status.AtProvider.LastOperation = v1alpha3.AsyncOperation{
-		PollingURL: op.PollingURL(),
-		Method:     http.MethodPut,
-	}
+	cr.Status.AtProvider.LastOperation = azure.NewAsyncOperation(http.MethodPut, op.FutureAPI, nil)
 	return nil
 }
 
@@ -111,8 +107,11 @@ func UpdatePostgreSQLConfigurationObservation(o *azuredbv1beta1.SQLServerConfigu
 	o.Description = azure.ToString(in.Description)
 }
 
-// IsPostgreSQLConfigurationUpToDate is used to report whether given postgresql.Configuration is in
-// sync with the SQLServerConfigurationParameters that user desires.
-func IsPostgreSQLConfigurationUpToDate(p azuredbv1beta1.SQLServerConfigurationParameters, in postgresql.Configuration) bool {
-	return azure.ToString(p.Value) == azure.ToString(in.Value)
+// IsPostgreSQLConfigurationUpToDate returns the fields in which the given
+// postgresql.Configuration is out of sync with the
+// SQLServerConfigurationParameters that user desires.
+func IsPostgreSQLConfigurationUpToDate(p azuredbv1beta1.SQLServerConfigurationParameters, in postgresql.Configuration) azure.Diff {
+	d := azure.Diff{}
+	d.Compare("value", azure.ToString(p.Value), azure.ToString(in.Value))
+	return d
 }
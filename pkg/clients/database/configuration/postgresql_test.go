@@ -72,7 +72,7 @@ func TestIsPostgreSQLConfigurationUpToDate(t *testing.T) {
 	}
 	for name, tt := range tests {
 		t.Run(name, func(t *testing.T) {
-			got := IsPostgreSQLConfigurationUpToDate(tt.args.p, tt.args.in)
+			got := IsPostgreSQLConfigurationUpToDate(tt.args.p, tt.args.in).UpToDate()
 			if diff := cmp.Diff(tt.want, got); diff != "" {
 				t.Errorf("IsPostgreSQLConfigurationUpToDate(...): -want, +got\n%s", diff)
 			}
@@ -17,15 +17,11 @@ limitations under the License.
 package cosmosdb
 
 import (
-	"encoding/json"
-
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
 
 	"github.com/Azure/azure-sdk-for-go/services/cosmos-db/mgmt/2015-04-08/documentdb"
 	"github.com/Azure/azure-sdk-for-go/services/cosmos-db/mgmt/2015-04-08/documentdb/documentdbapi"
-	"github.com/Azure/go-autorest/autorest/azure/auth"
-	"github.com/pkg/errors"
 
 	"github.com/crossplane-contrib/provider-azure/apis/database/v1alpha3"
 	azure "github.com/crossplane-contrib/provider-azure/pkg/clients"
@@ -34,33 +30,6 @@ import (
 // A AccountClient handles CRUD operations for Azure CosmosDB Accounts.
 type AccountClient documentdbapi.DatabaseAccountsClientAPI
 
-// NewDatabaseAccountClient create Azure DatabaseAccountsClient using provided
-// credentials data
-func NewDatabaseAccountClient(credentials []byte) (AccountClient, error) {
-	creds := &azure.Credentials{}
-	if err := json.Unmarshal(credentials, creds); err != nil {
-		return nil, errors.Wrap(err, "cannot unmarshal Azure client secret data")
-	}
-
-	config := auth.NewClientCredentialsConfig(creds.ClientID, creds.ClientSecret, creds.TenantID)
-	config.AADEndpoint = creds.ActiveDirectoryEndpointURL
-	config.Resource = creds.ResourceManagerEndpointURL
-
-	authorizer, err := config.Authorizer()
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to get authorizer from config")
-	}
-
-	client := documentdb.NewDatabaseAccountsClient(creds.SubscriptionID)
-	client.Authorizer = authorizer
-
-	if err := client.AddToUserAgent(azure.UserAgent); err != nil {
-		return nil, errors.Wrap(err, "cannot add to Azure client user agent")
-	}
-
-	return client, nil
-}
-
 // ToDatabaseAccountCreateOrUpdate from CosmosDBAccountSpec
 func ToDatabaseAccountCreateOrUpdate(s *v1alpha3.CosmosDBAccountSpec) documentdb.DatabaseAccountCreateUpdateParameters {
 	if s == nil {
@@ -76,12 +45,17 @@ func ToDatabaseAccountCreateOrUpdate(s *v1alpha3.CosmosDBAccountSpec) documentdb
 }
 
 // UpdateCosmosDBAccountObservation produces SQLServerObservation from
-// documentdb.CosmosDBAccountStatus.
+// documentdb.CosmosDBAccountStatus. The last operation started by the
+// controller is preserved.
 func UpdateCosmosDBAccountObservation(o *v1alpha3.CosmosDBAccountStatus, in documentdb.DatabaseAccount) {
-	o.AtProvider = &v1alpha3.CosmosDBAccountObservation{
+	obs := &v1alpha3.CosmosDBAccountObservation{
 		ID:    azure.ToString(in.ID),
 		State: azure.ToString(in.DatabaseAccountProperties.ProvisioningState),
 	}
+	if o.AtProvider != nil {
+		obs.LastOperation = o.AtProvider.LastOperation
+	}
+	o.AtProvider = obs
 }
 
 func toDatabaseProperties(a *v1alpha3.CosmosDBAccountProperties) *documentdb.DatabaseAccountCreateUpdateProperties {
@@ -117,15 +91,25 @@ func fromDatabaseProperties(a *documentdb.DatabaseAccountProperties) v1alpha3.Co
 }
 
 // CheckEqualDatabaseProperties compares the observed state with the desired
-// spec.
-func CheckEqualDatabaseProperties(p v1alpha3.CosmosDBAccountProperties, a documentdb.DatabaseAccount) bool {
+// spec, and returns the properties that differ.
+func CheckEqualDatabaseProperties(p v1alpha3.CosmosDBAccountProperties, a documentdb.DatabaseAccount) azure.Diff {
 	o := fromDatabaseProperties(a.DatabaseAccountProperties)
 
 	// asouza: only keep attributes that can be modified in the comparison.
-	return (equalConsistencyPolicyIfNotNull(p.ConsistencyPolicy, o.ConsistencyPolicy) &&
-		checkEqualLocations(p.Locations, o.Locations) &&
-		equalBoolIfNotNull(p.EnableAutomaticFailover, o.EnableAutomaticFailover) &&
-		equalBoolIfNotNull(p.EnableMultipleWriteLocations, o.EnableMultipleWriteLocations))
+	d := azure.Diff{}
+	if !equalConsistencyPolicyIfNotNull(p.ConsistencyPolicy, o.ConsistencyPolicy) {
+		d.Add("properties.consistencyPolicy", p.ConsistencyPolicy, o.ConsistencyPolicy)
+	}
+	if !checkEqualLocations(p.Locations, o.Locations) {
+		d.Add("properties.locations", p.Locations, o.Locations)
+	}
+	if !equalBoolIfNotNull(p.EnableAutomaticFailover, o.EnableAutomaticFailover) {
+		d.Add("properties.enableAutomaticFailover", p.EnableAutomaticFailover, o.EnableAutomaticFailover)
+	}
+	if !equalBoolIfNotNull(p.EnableMultipleWriteLocations, o.EnableMultipleWriteLocations) {
+		d.Add("properties.enableMultipleWriteLocations", p.EnableMultipleWriteLocations, o.EnableMultipleWriteLocations)
+	}
+	return d
 }
 
 func equalConsistencyPolicyIfNotNull(spec, current *v1alpha3.CosmosDBAccountConsistencyPolicy) bool {
@@ -21,59 +21,11 @@ import (
 
 	"github.com/Azure/azure-sdk-for-go/services/cosmos-db/mgmt/2015-04-08/documentdb"
 	"github.com/google/go-cmp/cmp"
-	"github.com/pkg/errors"
-
-	"github.com/crossplane/crossplane-runtime/pkg/test"
 
 	"github.com/crossplane-contrib/provider-azure/apis/database/v1alpha3"
 	azure "github.com/crossplane-contrib/provider-azure/pkg/clients"
 )
 
-func TestNewCosmosDBAccountClient(t *testing.T) {
-	tests := []struct {
-		name    string
-		args    []byte
-		wantRes *documentdb.DatabaseAccountsClient
-		wantErr error
-	}{
-		{
-			name:    "EmptyData",
-			args:    []byte{},
-			wantRes: nil,
-			wantErr: errors.WithStack(errors.New("cannot unmarshal Azure client secret data: unexpected end of JSON input")),
-		},
-		{
-			name: "Success",
-			args: []byte(`{"clientId": "0f32e96b-b9a4-49ce-a857-243a33b20e5c",
-	"clientSecret": "49d8cab5-d47a-4d1a-9133-5c5db29c345d",
-	"subscriptionId": "bf1b0e59-93da-42e0-82c6-5a1d94227911",
-	"tenantId": "302de427-dba9-4452-8583-a4268e46de6b",
-	"activeDirectoryEndpointUrl": "https://login.microsoftonline.com",
-	"resourceManagerEndpointUrl": "https://management.azure.com/",
-	"activeDirectoryGraphResourceId": "https://graph.windows.net/",
-	"sqlManagementEndpointUrl": "https://management.core.windows.net:8443/",
-	"galleryEndpointUrl": "https://gallery.azure.com/",
-	"managementEndpointUrl": "https://management.core.windows.net/"}`),
-			wantRes: &documentdb.DatabaseAccountsClient{},
-			wantErr: nil,
-		},
-	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			got, err := NewDatabaseAccountClient(tt.args)
-			if diff := cmp.Diff(err, tt.wantErr, test.EquateErrors()); diff != "" {
-				t.Errorf("NewCosmosDBAccountClient() error = %v, wantErr %v\n%s", err, tt.wantErr, diff)
-			}
-			if err != nil && got != nil {
-				t.Errorf("NewCosmosDBAccountClient() %v, want nil", got)
-			}
-			if err == nil && got == nil {
-				t.Errorf("NewCosmosDBAccountClient() %v, want not nil", got)
-			}
-		})
-	}
-}
-
 func TestToDatabaseAccountCreateOrUpdate(t *testing.T) {
 	resourceGroupName := "myrg"
 	kind := documentdb.DatabaseAccountKind("MongoDB")
@@ -133,7 +85,12 @@ func TestCheckEqualDatabaseProperties(t *testing.T) {
 	location := "uswest"
 
 	t.Run("NotEqualLocation", func(t *testing.T) {
-		diff := cmp.Diff(false, CheckEqualDatabaseProperties(
+		want := azure.Diff{{
+			Path:     "properties.locations",
+			Desired:  `[{"locationName":"uswest","failoverPriority":0,"isZoneRedundant":false}]`,
+			Observed: `[{"locationName":"some other location","failoverPriority":0,"isZoneRedundant":false}]`,
+		}}
+		diff := cmp.Diff(want, CheckEqualDatabaseProperties(
 			v1alpha3.CosmosDBAccountProperties{
 				Locations: []v1alpha3.CosmosDBAccountLocation{
 					{
@@ -155,7 +112,7 @@ func TestCheckEqualDatabaseProperties(t *testing.T) {
 		}
 	})
 	t.Run("EqualLocation", func(t *testing.T) {
-		diff := cmp.Diff(true, CheckEqualDatabaseProperties(
+		diff := cmp.Diff(azure.Diff{}, CheckEqualDatabaseProperties(
 			v1alpha3.CosmosDBAccountProperties{
 				Locations: []v1alpha3.CosmosDBAccountLocation{
 					{
@@ -179,7 +136,8 @@ func TestCheckEqualDatabaseProperties(t *testing.T) {
 		}
 	})
 	t.Run("NotEqualEnableAutomaticFailover", func(t *testing.T) {
-		diff := cmp.Diff(false, CheckEqualDatabaseProperties(
+		want := azure.Diff{{Path: "properties.enableAutomaticFailover", Desired: "true", Observed: "null"}}
+		diff := cmp.Diff(want, CheckEqualDatabaseProperties(
 			v1alpha3.CosmosDBAccountProperties{
 				EnableAutomaticFailover: azure.ToBoolPtr(true),
 			},
@@ -193,7 +151,7 @@ func TestCheckEqualDatabaseProperties(t *testing.T) {
 		}
 	})
 	t.Run("EqualEnableAutomaticFailover", func(t *testing.T) {
-		diff := cmp.Diff(true, CheckEqualDatabaseProperties(
+		diff := cmp.Diff(azure.Diff{}, CheckEqualDatabaseProperties(
 			v1alpha3.CosmosDBAccountProperties{
 				EnableAutomaticFailover: azure.ToBoolPtr(true),
 			},
@@ -259,7 +259,7 @@ func TestMySQLServerVirtualNetworkRuleNeedsUpdate(t *testing.T) {
 
 	for _, tc := range cases {
 		t.Run(tc.name, func(t *testing.T) {
-			got := MySQLServerVirtualNetworkRuleNeedsUpdate(tc.kube, tc.az)
+			got := !MySQLServerVirtualNetworkRuleNeedsUpdate(tc.kube, tc.az).UpToDate()
 			if diff := cmp.Diff(tc.want, got); diff != "" {
 				t.Errorf("MySQLServerVirtualNetworkRuleNeedsUpdate(...): -want, +got\n%s", diff)
 			}
@@ -436,7 +436,7 @@ func TestMySQLServerFirewallRuleIsUpToDate(t *testing.T) {
 
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
-			got := MySQLServerFirewallRuleIsUpToDate(tc.kube, tc.az)
+			got := MySQLServerFirewallRuleIsUpToDate(tc.kube, tc.az).UpToDate()
 			if diff := cmp.Diff(tc.want, got); diff != "" {
 				t.Errorf("MySQLServerFirewallRuleIsUpToDate(...): -want, +got\n%s", diff)
 			}
@@ -446,12 +446,13 @@ func TestMySQLServerFirewallRuleIsUpToDate(t *testing.T) {
 
 func TestIsMysqlUpToDate(t *testing.T) {
 	type args struct {
-		p  v1beta1.SQLServerParameters
-		in mysql.Server
+		p           v1beta1.SQLServerParameters
+		in          mysql.Server
+		defaultTags map[string]string
 	}
 	cases := map[string]struct {
 		args
-		want bool
+		want azure.Diff
 	}{
 		"IsUpToDateWithAllDefault": {
 			args: args{
@@ -463,7 +464,7 @@ func TestIsMysqlUpToDate(t *testing.T) {
 					},
 				},
 			},
-			want: true,
+			want: azure.Diff{},
 		},
 		"IsUpToDate": {
 			args: args{
@@ -510,7 +511,7 @@ func TestIsMysqlUpToDate(t *testing.T) {
 					},
 				},
 			},
-			want: true,
+			want: azure.Diff{},
 		},
 		"IsNotUpToDate": {
 			args: args{
@@ -525,7 +526,48 @@ func TestIsMysqlUpToDate(t *testing.T) {
 					},
 				},
 			},
-			want: false,
+			want: azure.Diff{
+				{Path: "publicNetworkAccess", Desired: `"Disabled"`, Observed: `"Enabled"`},
+			},
+		},
+		"IsUpToDateWithProviderTags": {
+			args: args{
+				p: v1beta1.SQLServerParameters{
+					Tags: map[string]string{"team": "db"},
+				},
+				in: mysql.Server{
+					Tags: map[string]*string{
+						"team":                     azure.ToStringPtr("db"),
+						"cost-center":              azure.ToStringPtr("42"),
+						azure.TagKeyName:           azure.ToStringPtr("cool-server"),
+						azure.TagKeyProviderConfig: azure.ToStringPtr("default"),
+					},
+					Sku: &mysql.Sku{},
+					ServerProperties: &mysql.ServerProperties{
+						StorageProfile: &mysql.StorageProfile{},
+					},
+				},
+				defaultTags: map[string]string{"cost-center": "42", "team": "platform"},
+			},
+			want: azure.Diff{},
+		},
+		"IsNotUpToDateWithoutDefaultTag": {
+			args: args{
+				p: v1beta1.SQLServerParameters{},
+				in: mysql.Server{
+					Tags: map[string]*string{
+						azure.TagKeyName: azure.ToStringPtr("cool-server"),
+					},
+					Sku: &mysql.Sku{},
+					ServerProperties: &mysql.ServerProperties{
+						StorageProfile: &mysql.StorageProfile{},
+					},
+				},
+				defaultTags: map[string]string{"cost-center": "42"},
+			},
+			want: azure.Diff{
+				{Path: "tags", Desired: `{"cost-center":"42"}`, Observed: "null"},
+			},
 		},
 		"IsNotUpToDateWithServerWithoutSku": {
 			args: args{
@@ -536,13 +578,15 @@ func TestIsMysqlUpToDate(t *testing.T) {
 					},
 				},
 			},
-			want: false,
+			want: azure.Diff{
+				{Path: "sku", Desired: `{"tier":"","capacity":0,"family":""}`, Observed: "null"},
+			},
 		},
 	}
 
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
-			got := IsMySQLUpToDate(tc.args.p, tc.args.in)
+			got := IsMySQLUpToDate(tc.args.p, tc.args.in, tc.args.defaultTags)
 			if diff := cmp.Diff(tc.want, got); diff != "" {
 				t.Errorf("IsMySQLUpToDate(...): -want, +got\n%s", diff)
 			}
@@ -552,8 +596,9 @@ func TestIsMysqlUpToDate(t *testing.T) {
 
 func TestLateInitializeMySQL(t *testing.T) {
 	type args struct {
-		p  *v1beta1.SQLServerParameters
-		in mysql.Server
+		p           *v1beta1.SQLServerParameters
+		in          mysql.Server
+		defaultTags map[string]string
 	}
 	cases := map[string]struct {
 		args
@@ -573,10 +618,28 @@ func TestLateInitializeMySQL(t *testing.T) {
 				PublicNetworkAccess: azure.ToStringPtr("Enabled"),
 			},
 		},
+		"TagsLateInitialize": {
+			args: args{
+				p: &v1beta1.SQLServerParameters{},
+				in: mysql.Server{
+					Tags: map[string]*string{
+						"team":           azure.ToStringPtr("db"),
+						"cost-center":    azure.ToStringPtr("42"),
+						azure.TagKeyKind: azure.ToStringPtr("MySQLServer"),
+					},
+					ServerProperties: &mysql.ServerProperties{},
+				},
+				defaultTags: map[string]string{"cost-center": "42"},
+			},
+			want: &v1beta1.SQLServerParameters{
+				Tags:                map[string]string{"team": "db"},
+				PublicNetworkAccess: azure.ToStringPtr(""),
+			},
+		},
 	}
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
-			LateInitializeMySQL(tc.args.p, tc.args.in)
+			LateInitializeMySQL(tc.args.p, tc.args.in, tc.args.defaultTags)
 			if diff := cmp.Diff(tc.want, tc.args.p); diff != "" {
 				t.Errorf("TestLateInitializeMySQL(...): -want, +got\n%s", diff)
 			}
@@ -20,20 +20,17 @@ import (
 	"context"
 	"fmt"
 	"net/http"
-	"reflect"
 	"strconv"
 
 	"github.com/Azure/azure-sdk-for-go/services/mysql/mgmt/2017-12-01/mysql"
 	"github.com/Azure/azure-sdk-for-go/services/postgresql/mgmt/2017-12-01/postgresql"
 	"github.com/Azure/go-autorest/autorest"
-	"github.com/google/go-cmp/cmp"
 
 	"github.com/crossplane/crossplane-runtime/pkg/meta"
 
 	azuredbv1alpha3 "github.com/crossplane-contrib/provider-azure/apis/database/v1alpha3"
 	"github.com/crossplane-contrib/provider-azure/apis/database/v1beta1"
 	azuredbv1beta1 "github.com/crossplane-contrib/provider-azure/apis/database/v1beta1"
-	"github.com/crossplane-contrib/provider-azure/apis/v1alpha3"
 	azure "github.com/crossplane-contrib/provider-azure/pkg/clients"
 )
 
@@ -47,9 +44,9 @@ import (
 // PostgreSQLServerAPI represents the API interface for a PostgreSQL Server client
 type PostgreSQLServerAPI interface {
 	GetServer(ctx context.Context, s *azuredbv1beta1.PostgreSQLServer) (postgresql.Server, error)
-	CreateServer(ctx context.Context, s *azuredbv1beta1.PostgreSQLServer, adminPassword string) error
+	CreateServer(ctx context.Context, s *azuredbv1beta1.PostgreSQLServer, adminPassword string, defaultTags map[string]string) error
 	DeleteServer(ctx context.Context, s *azuredbv1beta1.PostgreSQLServer) error
-	UpdateServer(ctx context.Context, s *azuredbv1beta1.PostgreSQLServer) error
+	UpdateServer(ctx context.Context, s *azuredbv1beta1.PostgreSQLServer, defaultTags map[string]string) error
 	GetRESTClient() autorest.Sender
 }
 
@@ -147,7 +144,7 @@ func toPGSQLProperties(s v1beta1.SQLServerParameters, adminPassword string) post
 }
 
 // CreateServer creates a PostgreSQL Server
-func (c *PostgreSQLServerClient) CreateServer(ctx context.Context, cr *azuredbv1beta1.PostgreSQLServer, adminPassword string) error {
+func (c *PostgreSQLServerClient) CreateServer(ctx context.Context, cr *azuredbv1beta1.PostgreSQLServer, adminPassword string, defaultTags map[string]string) error {
 	s := cr.Spec.ForProvider
 	sku, err := ToPostgreSQLSKU(s.SKU)
 	if err != nil {
@@ -157,21 +154,18 @@ func (c *PostgreSQLServerClient) CreateServer(ctx context.Context, cr *azuredbv1
 		Sku:        sku,
 		Properties: toPGSQLProperties(s, adminPassword),
 		Location:   &s.Location,
-		Tags:       azure.ToStringPtrMap(s.Tags),
+		Tags:       azure.NewTags(cr, defaultTags, s.Tags),
 	}
 	op, err := c.Create(ctx, s.ResourceGroupName, meta.GetExternalName(cr), createParams)
 	if err != nil {
 		return err
 	}
-	cr.Status.AtProvider.LastOperation = v1alpha3.AsyncOperation{
-		PollingURL: op.PollingURL(),
-		Method:     http.MethodPut,
-	}
+	cr.Status.AtProvider.LastOperation = azure.NewAsyncOperation(http.MethodPut, op.FutureAPI, nil)
 	return nil
 }
 
 // UpdateServer updates a PostgreSQL Server.
-func (c *PostgreSQLServerClient) UpdateServer(ctx context.Context, cr *azuredbv1beta1.PostgreSQLServer) error {
+func (c *PostgreSQLServerClient) UpdateServer(ctx context.Context, cr *azuredbv1beta1.PostgreSQLServer, defaultTags map[string]string) error {
 	// TODO(muvaf): password update via Update call is supported by Azure but
 	// we don't support that.
 	s := cr.Spec.ForProvider
@@ -194,16 +188,13 @@ func (c *PostgreSQLServerClient) UpdateServer(ctx context.Context, cr *azuredbv1
 	updateParams := postgresql.ServerUpdateParameters{
 		Sku:                              sku,
 		ServerUpdateParametersProperties: properties,
-		Tags:                             azure.ToStringPtrMap(s.Tags),
+		Tags:                             azure.NewTags(cr, defaultTags, s.Tags),
 	}
 	op, err := c.Update(ctx, s.ResourceGroupName, meta.GetExternalName(cr), updateParams)
 	if err != nil {
 		return err
 	}
-	cr.Status.AtProvider.LastOperation = v1alpha3.AsyncOperation{
-		PollingURL: op.PollingURL(),
-		Method:     http.MethodPatch,
-	}
+	cr.Status.AtProvider.LastOperation = azure.NewAsyncOperation(http.MethodPatch, op.FutureAPI, nil)
 	return nil
 }
 
@@ -213,10 +204,7 @@ func (c *PostgreSQLServerClient) DeleteServer(ctx context.Context, cr *azuredbv1
 	if err != nil {
 		return err
 	}
-	cr.Status.AtProvider.LastOperation = v1alpha3.AsyncOperation{
-		PollingURL: op.PollingURL(),
-		Method:     http.MethodDelete,
-	}
+	cr.Status.AtProvider.LastOperation = azure.NewAsyncOperation(http.MethodDelete, op.FutureAPI, nil)
 	return nil
 }
 
@@ -231,18 +219,16 @@ func NewPostgreSQLVirtualNetworkRuleParameters(v *azuredbv1alpha3.PostgreSQLServ
 	}
 }
 
-// PostgreSQLServerVirtualNetworkRuleNeedsUpdate determines if a virtual network rule needs to be updated
-func PostgreSQLServerVirtualNetworkRuleNeedsUpdate(kube *azuredbv1alpha3.PostgreSQLServerVirtualNetworkRule, az postgresql.VirtualNetworkRule) bool {
+// PostgreSQLServerVirtualNetworkRuleNeedsUpdate returns the fields in which the
+// supplied Azure virtual network rule differs from the supplied
+// PostgreSQLServerVirtualNetworkRule.
+func PostgreSQLServerVirtualNetworkRuleNeedsUpdate(kube *azuredbv1alpha3.PostgreSQLServerVirtualNetworkRule, az postgresql.VirtualNetworkRule) azure.Diff {
 	up := NewPostgreSQLVirtualNetworkRuleParameters(kube)
 
-	switch {
-	case !reflect.DeepEqual(up.VirtualNetworkRuleProperties.VirtualNetworkSubnetID, az.VirtualNetworkRuleProperties.VirtualNetworkSubnetID):
-		return true
-	case !reflect.DeepEqual(up.VirtualNetworkRuleProperties.IgnoreMissingVnetServiceEndpoint, az.VirtualNetworkRuleProperties.IgnoreMissingVnetServiceEndpoint):
-		return true
-	}
-
-	return false
+	d := azure.Diff{}
+	d.Compare("properties.virtualNetworkSubnetId", up.VirtualNetworkRuleProperties.VirtualNetworkSubnetID, az.VirtualNetworkRuleProperties.VirtualNetworkSubnetID)
+	d.Compare("properties.ignoreMissingVnetServiceEndpoint", up.VirtualNetworkRuleProperties.IgnoreMissingVnetServiceEndpoint, az.VirtualNetworkRuleProperties.IgnoreMissingVnetServiceEndpoint)
+	return d
 }
 
 // UpdatePostgreSQLVirtualNetworkRuleStatusFromAzure updates the status related to the external
@@ -265,11 +251,17 @@ func NewPostgreSQLFirewallRuleParameters(r *azuredbv1alpha3.PostgreSQLServerFire
 	}
 }
 
-// PostgreSQLServerFirewallRuleIsUpToDate returns true if the supplied FirewallRule
-// appears to be up to date with the supplied PostgreSQLServerFirewallRule.
-func PostgreSQLServerFirewallRuleIsUpToDate(kube *azuredbv1alpha3.PostgreSQLServerFirewallRule, az postgresql.FirewallRule) bool {
+// PostgreSQLServerFirewallRuleIsUpToDate returns the fields in which the supplied
+// FirewallRule differs from the supplied PostgreSQLServerFirewallRule.
+func PostgreSQLServerFirewallRuleIsUpToDate(kube *azuredbv1alpha3.PostgreSQLServerFirewallRule, az postgresql.FirewallRule) azure.Diff {
 	up := NewPostgreSQLFirewallRuleParameters(kube)
-	return cmp.Equal(up.FirewallRuleProperties, az.FirewallRuleProperties)
+	if az.FirewallRuleProperties == nil {
+		az.FirewallRuleProperties = &postgresql.FirewallRuleProperties{}
+	}
+	d := azure.Diff{}
+	d.Compare("properties.startIpAddress", up.FirewallRuleProperties.StartIPAddress, az.FirewallRuleProperties.StartIPAddress)
+	d.Compare("properties.endIpAddress", up.FirewallRuleProperties.EndIPAddress, az.FirewallRuleProperties.EndIPAddress)
+	return d
 }
 
 // The name must match the specification of the SKU, so, we don't allow user
@@ -302,11 +294,11 @@ func UpdatePostgreSQLObservation(o *azuredbv1beta1.SQLServerObservation, in post
 
 // LateInitializePostgreSQL fills the empty values of SQLServerParameters with the
 // ones that are retrieved from the Azure API.
-func LateInitializePostgreSQL(p *azuredbv1beta1.SQLServerParameters, in postgresql.Server) {
+func LateInitializePostgreSQL(p *azuredbv1beta1.SQLServerParameters, in postgresql.Server, defaultTags map[string]string) {
 	if in.Sku != nil {
 		p.SKU.Size = azure.LateInitializeStringPtrFromPtr(p.SKU.Size, in.Sku.Size)
 	}
-	p.Tags = azure.LateInitializeStringMap(p.Tags, in.Tags)
+	p.Tags = azure.LateInitializeTags(p.Tags, in.Tags, defaultTags)
 	if in.StorageProfile != nil {
 		p.StorageProfile.BackupRetentionDays = azure.LateInitializeIntPtrFromInt32Ptr(p.StorageProfile.BackupRetentionDays, in.StorageProfile.BackupRetentionDays)
 		p.StorageProfile.GeoRedundantBackup = azure.LateInitializeStringPtrFromVal(p.StorageProfile.GeoRedundantBackup, string(in.StorageProfile.GeoRedundantBackup))
@@ -324,37 +316,33 @@ func LateInitializePostgreSQL(p *azuredbv1beta1.SQLServerParameters, in postgres
 	}
 }
 
-// IsPostgreSQLUpToDate is used to report whether given postgresql.Server is in
-// sync with the SQLServerParameters that user desires.
-func IsPostgreSQLUpToDate(p azuredbv1beta1.SQLServerParameters, in postgresql.Server) bool { // nolint:gocyclo
-	if in.StorageProfile == nil || in.Sku == nil {
-		return false
+// IsPostgreSQLUpToDate returns the fields in which the given postgresql.Server is
+// out of sync with the SQLServerParameters that user desires.
+// Its tags are compared to the desired tags, i.e. the supplied default tags
+// overridden by the tags of the SQLServerParameters.
+func IsPostgreSQLUpToDate(p azuredbv1beta1.SQLServerParameters, in postgresql.Server, defaultTags map[string]string) azure.Diff {
+	d := azure.Diff{}
+	if p.SSLEnforcement != string(postgresql.SslEnforcementEnumDisabled) {
+		d.Compare("minimalTlsVersion", p.MinimalTLSVersion, string(in.MinimalTLSVersion))
+	}
+	d.Compare("sslEnforcement", p.SSLEnforcement, string(in.SslEnforcement))
+	d.Compare("version", p.Version, string(in.Version))
+	d.Compare("tags", azure.DesiredTags(defaultTags, p.Tags), azure.ObservedTags(in.Tags))
+	if in.Sku == nil {
+		d.Add("sku", p.SKU, nil)
+	} else {
+		d.Compare("sku.tier", p.SKU.Tier, string(in.Sku.Tier))
+		d.Compare("sku.capacity", p.SKU.Capacity, azure.ToInt(in.Sku.Capacity))
+		d.Compare("sku.family", p.SKU.Family, azure.ToString(in.Sku.Family))
 	}
-	switch {
-	case p.MinimalTLSVersion != string(in.MinimalTLSVersion) && p.SSLEnforcement != string(postgresql.SslEnforcementEnumDisabled):
-		return false
-	case p.SSLEnforcement != string(in.SslEnforcement):
-		return false
-	case p.Version != string(in.Version):
-		return false
-	case !reflect.DeepEqual(azure.ToStringPtrMap(p.Tags), in.Tags):
-		return false
-	case p.SKU.Tier != string(in.Sku.Tier):
-		return false
-	case p.SKU.Capacity != azure.ToInt(in.Sku.Capacity):
-		return false
-	case p.SKU.Family != azure.ToString(in.Sku.Family):
-		return false
-	case !reflect.DeepEqual(azure.ToInt32PtrFromIntPtr(p.StorageProfile.BackupRetentionDays), in.StorageProfile.BackupRetentionDays):
-		return false
-	case azure.ToString(p.StorageProfile.GeoRedundantBackup) != string(in.StorageProfile.GeoRedundantBackup):
-		return false
-	case p.StorageProfile.StorageMB != azure.ToInt(in.StorageProfile.StorageMB):
-		return false
-	case azure.ToString(p.StorageProfile.StorageAutogrow) != string(in.StorageProfile.StorageAutogrow):
-		return false
-	case azure.ToString(p.PublicNetworkAccess) != string(in.PublicNetworkAccess):
-		return false
+	if in.StorageProfile == nil {
+		d.Add("storageProfile", p.StorageProfile, nil)
+	} else {
+		d.Compare("storageProfile.backupRetentionDays", azure.ToInt32PtrFromIntPtr(p.StorageProfile.BackupRetentionDays), in.StorageProfile.BackupRetentionDays)
+		d.Compare("storageProfile.geoRedundantBackup", azure.ToString(p.StorageProfile.GeoRedundantBackup), string(in.StorageProfile.GeoRedundantBackup))
+		d.Compare("storageProfile.storageMB", p.StorageProfile.StorageMB, azure.ToInt(in.StorageProfile.StorageMB))
+		d.Compare("storageProfile.storageAutogrow", azure.ToString(p.StorageProfile.StorageAutogrow), string(in.StorageProfile.StorageAutogrow))
 	}
-	return true
+	d.Compare("publicNetworkAccess", azure.ToString(p.PublicNetworkAccess), string(in.PublicNetworkAccess))
+	return d
 }
@@ -248,7 +248,7 @@ func TestPostgreSQLServerVirtualNetworkRuleNeedsUpdate(t *testing.T) {
 
 	for _, tc := range cases {
 		t.Run(tc.name, func(t *testing.T) {
-			got := PostgreSQLServerVirtualNetworkRuleNeedsUpdate(tc.kube, tc.az)
+			got := !PostgreSQLServerVirtualNetworkRuleNeedsUpdate(tc.kube, tc.az).UpToDate()
 			if diff := cmp.Diff(tc.want, got); diff != "" {
 				t.Errorf("PostgreSQLServerVirtualNetworkRuleNeedsUpdate(...): -want, +got\n%s", diff)
 			}
@@ -424,7 +424,7 @@ func TestPostgreSQLServerFirewallRuleIsUpToDate(t *testing.T) {
 
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
-			got := PostgreSQLServerFirewallRuleIsUpToDate(tc.kube, tc.az)
+			got := PostgreSQLServerFirewallRuleIsUpToDate(tc.kube, tc.az).UpToDate()
 			if diff := cmp.Diff(tc.want, got); diff != "" {
 				t.Errorf("PostgreSQLServerFirewallRuleIsUpToDate(...): -want, +got\n%s", diff)
 			}
@@ -439,7 +439,7 @@ func TestIsPostgreSQLUpToDate(t *testing.T) {
 	}
 	cases := map[string]struct {
 		args
-		want bool
+		want azure.Diff
 	}{
 		"IsUpToDateWithAllDefault": {
 			args: args{
@@ -451,7 +451,7 @@ func TestIsPostgreSQLUpToDate(t *testing.T) {
 					},
 				},
 			},
-			want: true,
+			want: azure.Diff{},
 		},
 		"IsUpToDate": {
 			args: args{
@@ -498,7 +498,7 @@ func TestIsPostgreSQLUpToDate(t *testing.T) {
 					},
 				},
 			},
-			want: true,
+			want: azure.Diff{},
 		},
 		"IsNotUpToDate": {
 			args: args{
@@ -513,7 +513,9 @@ func TestIsPostgreSQLUpToDate(t *testing.T) {
 					},
 				},
 			},
-			want: false,
+			want: azure.Diff{
+				{Path: "publicNetworkAccess", Desired: `"Disabled"`, Observed: `"Enabled"`},
+			},
 		},
 		"IsNotUpToDateWithServerWithoutSku": {
 			args: args{
@@ -524,13 +526,15 @@ func TestIsPostgreSQLUpToDate(t *testing.T) {
 					},
 				},
 			},
-			want: false,
+			want: azure.Diff{
+				{Path: "sku", Desired: `{"tier":"","capacity":0,"family":""}`, Observed: "null"},
+			},
 		},
 	}
 
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
-			got := IsPostgreSQLUpToDate(tc.args.p, tc.args.in)
+			got := IsPostgreSQLUpToDate(tc.args.p, tc.args.in, nil)
 			if diff := cmp.Diff(tc.want, got); diff != "" {
 				t.Errorf("IsPostgreSQLUpToDate(...): -want, +got\n%s", diff)
 			}
@@ -564,7 +568,7 @@ func TestLateInitializePostgreSQL(t *testing.T) {
 	}
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
-			LateInitializePostgreSQL(tc.args.p, tc.args.in)
+			LateInitializePostgreSQL(tc.args.p, tc.args.in, nil)
 			if diff := cmp.Diff(tc.want, tc.args.p); diff != "" {
 				t.Errorf("LateInitializePostgreSQL(...): -want, +got\n%s", diff)
 			}
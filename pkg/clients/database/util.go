@@ -19,6 +19,7 @@ package database
 import (
 	"github.com/Azure/go-autorest/autorest/date"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 
 	"github.com/crossplane-contrib/provider-azure/apis/database/v1beta1"
 )
@@ -44,3 +45,44 @@ func safeDate(time *metav1.Time) *date.Time {
 	}
 	return &date.Time{Time: time.Time}
 }
+
+// ValidateSQLServerParameters returns the inconsistencies between the create
+// mode of the supplied MySQL or PostgreSQL server and the fields it requires.
+// Fields that a create mode does not use are not sent to Azure, and unknown
+// create modes are treated as the default create mode, so both are rejected
+// rather than silently ignored.
+func ValidateSQLServerParameters(p *v1beta1.SQLServerParameters, path *field.Path) field.ErrorList {
+	var errs field.ErrorList
+	mode := pointerToCreateMode(p.CreateMode)
+	switch mode {
+	case v1beta1.CreateModePointInTimeRestore:
+		if p.SourceServerID == nil {
+			errs = append(errs, field.Required(path.Child("sourceServerID"), "must be set when createMode is "+string(mode)))
+		}
+		if p.RestorePointInTime == nil {
+			errs = append(errs, field.Required(path.Child("restorePointInTime"), "must be set when createMode is "+string(mode)))
+		}
+	case v1beta1.CreateModeGeoRestore, v1beta1.CreateModeReplica:
+		if p.SourceServerID == nil {
+			errs = append(errs, field.Required(path.Child("sourceServerID"), "must be set when createMode is "+string(mode)))
+		}
+		if p.RestorePointInTime != nil {
+			errs = append(errs, field.Forbidden(path.Child("restorePointInTime"), "must not be set when createMode is "+string(mode)))
+		}
+	case v1beta1.CreateModeDefault:
+		if p.SourceServerID != nil {
+			errs = append(errs, field.Forbidden(path.Child("sourceServerID"), "must not be set when createMode is "+string(mode)))
+		}
+		if p.RestorePointInTime != nil {
+			errs = append(errs, field.Forbidden(path.Child("restorePointInTime"), "must not be set when createMode is "+string(mode)))
+		}
+	default:
+		errs = append(errs, field.NotSupported(path.Child("createMode"), mode, []string{
+			string(v1beta1.CreateModeDefault),
+			string(v1beta1.CreateModeReplica),
+			string(v1beta1.CreateModeGeoRestore),
+			string(v1beta1.CreateModePointInTimeRestore),
+		}))
+	}
+	return errs
+}
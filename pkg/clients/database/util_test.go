@@ -0,0 +1,98 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package database
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"github.com/crossplane-contrib/provider-azure/apis/database/v1beta1"
+	azure "github.com/crossplane-contrib/provider-azure/pkg/clients"
+)
+
+func TestValidateSQLServerParameters(t *testing.T) {
+	path := field.NewPath("spec", "forProvider")
+	source := azure.ToStringPtr("/subscriptions/cool/resourceGroups/cool/providers/Microsoft.DBforMySQL/servers/cool")
+	now := metav1.Now()
+
+	cases := map[string]struct {
+		reason string
+		p      v1beta1.SQLServerParameters
+		want   field.ErrorList
+	}{
+		"Default": {
+			reason: "A server without a create mode should be created using the default create mode",
+		},
+		"DefaultWithSource": {
+			reason: "The default create mode should not accept a source server, which would be ignored",
+			p: v1beta1.SQLServerParameters{
+				SourceServerID: source,
+			},
+			want: field.ErrorList{
+				field.Forbidden(path.Child("sourceServerID"), "must not be set when createMode is Default"),
+			},
+		},
+		"ReplicaWithoutSource": {
+			reason: "A replica should require a source server",
+			p: v1beta1.SQLServerParameters{
+				CreateMode: pointerFromCreateMode(v1beta1.CreateModeReplica),
+			},
+			want: field.ErrorList{
+				field.Required(path.Child("sourceServerID"), "must be set when createMode is Replica"),
+			},
+		},
+		"PointInTimeRestore": {
+			reason: "A point in time restore should accept a source server and restore point",
+			p: v1beta1.SQLServerParameters{
+				CreateMode:         pointerFromCreateMode(v1beta1.CreateModePointInTimeRestore),
+				SourceServerID:     source,
+				RestorePointInTime: &now,
+			},
+		},
+		"PointInTimeRestoreWithoutRestorePoint": {
+			reason: "A point in time restore should require a restore point",
+			p: v1beta1.SQLServerParameters{
+				CreateMode:     pointerFromCreateMode(v1beta1.CreateModePointInTimeRestore),
+				SourceServerID: source,
+			},
+			want: field.ErrorList{
+				field.Required(path.Child("restorePointInTime"), "must be set when createMode is PointInTimeRestore"),
+			},
+		},
+		"UnknownCreateMode": {
+			reason: "Unknown create modes should be rejected rather than treated as the default create mode",
+			p: v1beta1.SQLServerParameters{
+				CreateMode: pointerFromCreateMode("Clone"),
+			},
+			want: field.ErrorList{
+				field.NotSupported(path.Child("createMode"), v1beta1.CreateMode("Clone"), []string{"Default", "Replica", "GeoRestore", "PointInTimeRestore"}),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := ValidateSQLServerParameters(&tc.p, path)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nValidateSQLServerParameters(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
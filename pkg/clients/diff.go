@@ -0,0 +1,265 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azure
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+)
+
+// Redacted replaces the values of secret-bearing fields in a Diff.
+const Redacted = "<redacted>"
+
+// ReasonUpdateRequired is the reason of the event that lists the fields of an
+// external resource that are not up to date.
+const ReasonUpdateRequired event.Reason = "UpdateRequired"
+
+// ReasonUpdatedFields is the reason of the event that lists the fields of an
+// external resource that were updated.
+const ReasonUpdatedFields event.Reason = "UpdatedFields"
+
+const errFmtUpdateDiff = "cannot update fields %s"
+
+// A FieldDiff is a field of an external resource whose observed value differs
+// from the value desired by its managed resource.
+type FieldDiff struct {
+	// Path of the field. Fields of a managed resource's parameters are
+	// identified by their JSON path within spec.forProvider, and fields of
+	// an Azure API object by their Go path within it.
+	Path string
+
+	// Desired and Observed are the JSON encoded values of the field, or
+	// Redacted if the field bears a secret.
+	Desired  string
+	Observed string
+}
+
+func (f FieldDiff) String() string {
+	return fmt.Sprintf("%s: desired %s, observed %s", f.Path, f.Desired, f.Observed)
+}
+
+// A Diff lists the fields of an external resource that are not up to date.
+// An empty Diff means the external resource is up to date.
+type Diff []FieldDiff
+
+// Add a field with the supplied desired and observed values to the Diff.
+func (d *Diff) Add(path string, desired, observed interface{}) {
+	*d = append(*d, FieldDiff{Path: path, Desired: encode(desired), Observed: encode(observed)})
+}
+
+// AddRedacted adds a secret-bearing field to the Diff without its values.
+func (d *Diff) AddRedacted(path string) {
+	*d = append(*d, FieldDiff{Path: path, Desired: Redacted, Observed: Redacted})
+}
+
+// Compare adds the supplied field to the Diff if its desired and observed
+// values differ.
+func (d *Diff) Compare(path string, desired, observed interface{}) {
+	if !reflect.DeepEqual(desired, observed) {
+		d.Add(path, desired, observed)
+	}
+}
+
+// UpToDate returns true if the Diff is empty.
+func (d Diff) UpToDate() bool {
+	return len(d) == 0
+}
+
+func (d Diff) String() string {
+	s := make([]string, len(d))
+	for i, f := range d {
+		s[i] = f.String()
+	}
+	return strings.Join(s, "; ")
+}
+
+// DiffObjects returns the fields in which the supplied Azure API objects
+// differ. Fields whose path is one of the supplied redacted paths are added
+// without their values. The supplied options are passed to cmp.
+func DiffObjects(desired, observed interface{}, redacted []string, o ...cmp.Option) Diff {
+	r := &diffReporter{redacted: map[string]bool{}, diff: Diff{}}
+	for _, p := range redacted {
+		r.redacted[p] = true
+	}
+	cmp.Equal(desired, observed, append(o, cmp.Reporter(r))...)
+	return r.diff
+}
+
+// diffReporter is a cmp.Reporter that records the fields that differ.
+type diffReporter struct {
+	path     cmp.Path
+	redacted map[string]bool
+	diff     Diff
+}
+
+func (r *diffReporter) PushStep(ps cmp.PathStep) {
+	r.path = append(r.path, ps)
+}
+
+func (r *diffReporter) Report(rs cmp.Result) {
+	if rs.Equal() {
+		return
+	}
+	p := pathString(r.path)
+	if r.redacted[p] {
+		r.diff.AddRedacted(p)
+		return
+	}
+	vx, vy := r.path.Last().Values()
+	r.diff.Add(p, value(vx), value(vy))
+}
+
+func (r *diffReporter) PopStep() {
+	r.path = r.path[:len(r.path)-1]
+}
+
+// pathString returns the supplied path as a series of field names and indices,
+// e.g. ARecords[0].Ipv4Address.
+func pathString(p cmp.Path) string {
+	var b strings.Builder
+	for _, s := range p {
+		switch s := s.(type) {
+		case cmp.StructField:
+			if b.Len() > 0 {
+				b.WriteString(".")
+			}
+			b.WriteString(s.Name())
+		case cmp.SliceIndex:
+			// Elements present in only one of the slices have no
+			// common index.
+			k := s.Key()
+			if x, y := s.SplitKeys(); k < 0 && x >= 0 {
+				k = x
+			} else if k < 0 {
+				k = y
+			}
+			fmt.Fprintf(&b, "[%d]", k)
+		case cmp.MapIndex:
+			fmt.Fprintf(&b, "[%v]", s.Key())
+		}
+	}
+	return b.String()
+}
+
+func value(v reflect.Value) interface{} {
+	if !v.IsValid() || !v.CanInterface() {
+		return nil
+	}
+	return v.Interface()
+}
+
+func encode(v interface{}) string {
+	b, err := marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	return string(b)
+}
+
+// marshal returns the JSON encoding of the supplied value. Unlike json.Marshal
+// it does not escape HTML, so that values remain readable in messages.
+func marshal(v interface{}) ([]byte, error) {
+	b := &bytes.Buffer{}
+	e := json.NewEncoder(b)
+	e.SetEscapeHTML(false)
+	if err := e.Encode(v); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(b.Bytes(), []byte("\n")), nil
+}
+
+type diffKey struct{}
+
+// recordingDiff returns a copy of the supplied context to which ReportDiff
+// reports, and the Diff it reports to. A context that is already reported to
+// is returned as is, so that every wrapper of an ExternalClient that passes it
+// on shares the same Diff.
+func recordingDiff(ctx context.Context) (context.Context, *Diff) {
+	if d, ok := ctx.Value(diffKey{}).(*Diff); ok {
+		return ctx, d
+	}
+	d := &Diff{}
+	return context.WithValue(ctx, diffKey{}, d), d
+}
+
+// ReportDiff reports the fields in which an external resource differs from
+// its managed resource while observing it, so that the ConnecterWrappers of
+// this package can report them. It has no effect if the supplied context was
+// not passed to Observe by such a wrapper.
+func ReportDiff(ctx context.Context, d Diff) {
+	if ctx == nil {
+		return
+	}
+	if p, ok := ctx.Value(diffKey{}).(*Diff); ok {
+		*p = append(*p, d...)
+	}
+}
+
+// ReportUpdateDiffs returns a ConnecterWrapper that reports any fields an
+// external client reports as not up to date using ReportDiff in a normal
+// event, and in the error returned if the update fails. The fields of a
+// successful update are reported in another normal event. The managed
+// reconciler overwrites the Synced condition after a successful update, so
+// they are not reported there.
+func ReportUpdateDiffs(r event.Recorder) ConnecterWrapper {
+	return wrapExternal(func(e managed.ExternalClient) managed.ExternalClient {
+		return &diffReportingExternal{ExternalClient: e, record: r}
+	})
+}
+
+type diffReportingExternal struct {
+	managed.ExternalClient
+	record event.Recorder
+
+	// diff is the Diff reported by the latest observation.
+	diff Diff
+}
+
+func (e *diffReportingExternal) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	ctx, d := recordingDiff(ctx)
+	o, err := e.ExternalClient.Observe(ctx, mg)
+	e.diff = *d
+	if err == nil && o.ResourceExists && !o.ResourceUpToDate && !e.diff.UpToDate() {
+		e.record.Event(mg, event.Normal(ReasonUpdateRequired, e.diff.String()))
+	}
+	return o, err
+}
+
+func (e *diffReportingExternal) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
+	u, err := e.ExternalClient.Update(ctx, mg)
+	if e.diff.UpToDate() {
+		return u, err
+	}
+	if err != nil {
+		// The managed reconciler reports this error in its ReconcileError
+		// condition, so the fields that could not be updated appear there.
+		return u, errors.Wrapf(err, errFmtUpdateDiff, e.diff)
+	}
+	e.record.Event(mg, event.Normal(ReasonUpdatedFields, "Successfully requested update of fields "+e.diff.String()))
+	return u, nil
+}
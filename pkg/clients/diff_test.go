@@ -0,0 +1,190 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azure
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/pkg/errors"
+
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+type record struct {
+	Name     *string
+	Password *string
+	Labels   map[string]string
+	Ports    []int
+	Ignored  string
+}
+
+func TestDiffObjects(t *testing.T) {
+	type args struct {
+		desired  record
+		observed record
+		redacted []string
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   Diff
+	}{
+		"Equal": {
+			reason: "Equal objects should not differ",
+			args: args{
+				desired:  record{Name: ToStringPtr("cool"), Ports: []int{80}},
+				observed: record{Name: ToStringPtr("cool"), Ports: []int{80}, Ignored: "different"},
+			},
+			want: Diff{},
+		},
+		"Fields": {
+			reason: "Each field that differs should be identified by its path",
+			args: args{
+				desired:  record{Name: ToStringPtr("cool"), Labels: map[string]string{"k": "v"}, Ports: []int{80, 443}},
+				observed: record{Labels: map[string]string{"k": "w"}, Ports: []int{80}},
+			},
+			want: Diff{
+				{Path: "Name", Desired: `"cool"`, Observed: "null"},
+				{Path: "Labels[k]", Desired: `"v"`, Observed: `"w"`},
+				{Path: "Ports[1]", Desired: "443", Observed: "null"},
+			},
+		},
+		"Redacted": {
+			reason: "The values of redacted fields should not be reported",
+			args: args{
+				desired:  record{Password: ToStringPtr("secret")},
+				observed: record{Password: ToStringPtr("other-secret")},
+				redacted: []string{"Password"},
+			},
+			want: Diff{{Path: "Password", Desired: Redacted, Observed: Redacted}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := DiffObjects(tc.args.desired, tc.args.observed, tc.args.redacted, cmpopts.IgnoreFields(record{}, "Ignored"))
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nDiffObjects(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestDiffString(t *testing.T) {
+	d := Diff{}
+	d.Compare("sku.capacity", 2, 1)
+	d.Compare("sku.tier", "Basic", "Basic")
+	d.AddRedacted("password")
+
+	want := `sku.capacity: desired 2, observed 1; password: desired <redacted>, observed <redacted>`
+	if diff := cmp.Diff(want, d.String()); diff != "" {
+		t.Errorf("String(): -want, +got:\n%s", diff)
+	}
+}
+
+func TestReportDiff(t *testing.T) {
+	d := Diff{{Path: "sku.capacity", Desired: "2", Observed: "1"}}
+
+	// Reporting to a context without a Diff should have no effect.
+	ReportDiff(context.Background(), d)
+
+	ctx, got := recordingDiff(context.Background())
+	ReportDiff(ctx, d)
+	if diff := cmp.Diff(d, *got); diff != "" {
+		t.Errorf("ReportDiff(...): -want, +got:\n%s", diff)
+	}
+
+	// Wrappers that record the same context should share its Diff.
+	if _, shared := recordingDiff(ctx); shared != got {
+		t.Errorf("recordingDiff(...): want the Diff of a context that is already recorded")
+	}
+}
+
+func TestReportUpdateDiffs(t *testing.T) {
+	d := Diff{{Path: "sku.capacity", Desired: "2", Observed: "1"}}
+	errBoom := errors.New("boom")
+
+	type args struct {
+		diff   Diff
+		update error
+	}
+	type want struct {
+		events []event.Event
+		err    error
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"Updated": {
+			reason: "The fields of a successful update should be reported in an event.",
+			args:   args{diff: d},
+			want: want{events: []event.Event{
+				event.Normal(ReasonUpdateRequired, d.String()),
+				event.Normal(ReasonUpdatedFields, "Successfully requested update of fields "+d.String()),
+			}},
+		},
+		"UpdateFailed": {
+			reason: "The fields of a failed update should be reported in its error.",
+			args:   args{diff: d, update: errBoom},
+			want: want{
+				events: []event.Event{event.Normal(ReasonUpdateRequired, d.String())},
+				err:    errors.Wrapf(errBoom, errFmtUpdateDiff, d),
+			},
+		},
+		"NoDiff": {
+			reason: "Nothing should be reported if the external client reported no fields.",
+			args:   args{update: errBoom},
+			want:   want{err: errBoom},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			mg := &fake.Managed{}
+			r := &eventRecorder{}
+			e := connect(t, &managed.ExternalClientFns{
+				ObserveFn: func(ctx context.Context, _ resource.Managed) (managed.ExternalObservation, error) {
+					ReportDiff(ctx, tc.args.diff)
+					return managed.ExternalObservation{ResourceExists: true}, nil
+				},
+				UpdateFn: func(_ context.Context, _ resource.Managed) (managed.ExternalUpdate, error) {
+					return managed.ExternalUpdate{}, tc.args.update
+				},
+			}, mg, ReportUpdateDiffs(r))
+
+			if _, err := e.Observe(context.Background(), mg); err != nil {
+				t.Fatalf("e.Observe(...): %s", err)
+			}
+			_, err := e.Update(context.Background(), mg)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ne.Update(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.events, r.events); diff != "" {
+				t.Errorf("\n%s\ne.Update(...): -want events, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -5,8 +5,8 @@ import (
 
 	"github.com/Azure/azure-sdk-for-go/services/dns/mgmt/2018-05-01/dns"
 	"github.com/Azure/go-autorest/autorest/to"
-	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 
 	"github.com/crossplane/crossplane-runtime/pkg/meta"
 
@@ -24,7 +24,7 @@ const (
 // ZoneAPI represents the API interface for a DNS Zone client
 type ZoneAPI interface {
 	Get(ctx context.Context, z *v1alpha1.Zone) (dns.Zone, error)
-	CreateOrUpdate(ctx context.Context, z *v1alpha1.Zone) error
+	CreateOrUpdate(ctx context.Context, z *v1alpha1.Zone, defaultTags map[string]string) error
 	Delete(ctx context.Context, z *v1alpha1.Zone) error
 }
 
@@ -46,9 +46,9 @@ func (c *ZoneClient) Get(ctx context.Context, z *v1alpha1.Zone) (dns.Zone, error
 }
 
 // CreateOrUpdate creates or updates a DNS Zone
-func (c *ZoneClient) CreateOrUpdate(ctx context.Context, z *v1alpha1.Zone) error {
+func (c *ZoneClient) CreateOrUpdate(ctx context.Context, z *v1alpha1.Zone, defaultTags map[string]string) error {
 	_, err := c.ZonesClient.CreateOrUpdate(ctx, z.Spec.ForProvider.ResourceGroupName, meta.GetExternalName(z),
-		NewZoneParameters(z), "", "")
+		NewZoneParameters(z, defaultTags), "", "")
 
 	return err
 }
@@ -72,8 +72,9 @@ func UpdateZoneStatusFromAzure(v *v1alpha1.Zone, az dns.Zone) {
 	v.Status.AtProvider.NumberOfRecordSets = azure.Int64ToInt(az.NumberOfRecordSets)
 }
 
-// NewZoneParameters returns an Azure DNS Zone object.
-func NewZoneParameters(r *v1alpha1.Zone) dns.Zone {
+// NewZoneParameters returns an Azure DNS Zone object with the supplied default
+// tags.
+func NewZoneParameters(r *v1alpha1.Zone, defaultTags map[string]string) dns.Zone {
 	res := dns.Zone{
 		Name:           azure.ToStringPtr(meta.GetExternalName(r)),
 		ZoneProperties: &dns.ZoneProperties{},
@@ -88,11 +89,7 @@ func NewZoneParameters(r *v1alpha1.Zone) dns.Zone {
 
 	res.Location = &r.Spec.ForProvider.Location
 
-	// This empty initialization necessary because, Azure SDK returns an empty struct when this field is set to nil.
-	res.Tags = map[string]*string{}
-	if r.Spec.ForProvider.Tags != nil {
-		res.Tags = r.Spec.ForProvider.Tags
-	}
+	res.Tags = azure.NewTags(r, defaultTags, azure.ToStringMap(r.Spec.ForProvider.Tags))
 
 	return res
 }
@@ -113,15 +110,18 @@ func newSubResourceParameters(e []v1alpha1.SubResource) *[]dns.SubResource {
 	return nil
 }
 
-// ZoneIsUpToDate decides if an upgrade is needed.
-func ZoneIsUpToDate(r *v1alpha1.Zone, az dns.Zone) bool {
-	up := NewZoneParameters(r)
-	if !cmp.Equal(up.Tags, az.Tags) || !cmp.Equal(up.RegistrationVirtualNetworks, az.RegistrationVirtualNetworks) ||
-		!cmp.Equal(up.ResolutionVirtualNetworks, az.ResolutionVirtualNetworks) {
-		return false
+// ZoneIsUpToDate returns the fields in which the supplied Azure DNS Zone
+// differs from the supplied Zone. An empty Diff means no update is needed.
+func ZoneIsUpToDate(r *v1alpha1.Zone, az dns.Zone, defaultTags map[string]string) azure.Diff {
+	up := NewZoneParameters(r, defaultTags)
+	if az.ZoneProperties == nil {
+		az.ZoneProperties = &dns.ZoneProperties{}
 	}
-
-	return true
+	d := azure.Diff{}
+	d.Compare("tags", azure.DesiredTags(defaultTags, azure.ToStringMap(r.Spec.ForProvider.Tags)), azure.ObservedTags(az.Tags))
+	d.Compare("properties.registrationVirtualNetworks", up.RegistrationVirtualNetworks, az.RegistrationVirtualNetworks)
+	d.Compare("properties.resolutionVirtualNetworks", up.ResolutionVirtualNetworks, az.ResolutionVirtualNetworks)
+	return d
 }
 
 // RecordSetAPI represents the API interface for a DNS RecordSet client
@@ -306,10 +306,53 @@ func newRecordParameters(r *v1alpha1.RecordSetParameters, res *dns.RecordSet) {
 	}
 }
 
-// RecordSetIsUpToDate decides if an upgrade is needed.
-func RecordSetIsUpToDate(r *v1alpha1.RecordSetParameters, az *dns.RecordSetProperties) bool {
+// ValidateRecordSetParameters returns the inconsistencies between the type of
+// the supplied record set and the records it contains. The records of every
+// type that are populated are sent to Azure by newRecordParameters, which
+// rejects records that don't match the record set's type. Alias record sets,
+// i.e. those with a target resource, may omit their records.
+func ValidateRecordSetParameters(r *v1alpha1.RecordSetParameters, path *field.Path) field.ErrorList {
+	res := dns.RecordSet{RecordSetProperties: &dns.RecordSetProperties{}}
+	newRecordParameters(r, &res)
+
+	records := []struct {
+		t         v1alpha1.RecordType
+		field     string
+		populated bool
+	}{
+		{t: v1alpha1.A, field: "aRecords", populated: res.ARecords != nil},
+		{t: v1alpha1.AAAA, field: "aaaaRecords", populated: res.AaaaRecords != nil},
+		{t: v1alpha1.CAA, field: "caaRecords", populated: res.CaaRecords != nil},
+		{t: v1alpha1.CNAME, field: "cnameRecord", populated: res.CnameRecord != nil},
+		{t: v1alpha1.MX, field: "mxRecords", populated: res.MxRecords != nil},
+		{t: v1alpha1.NS, field: "nsRecords", populated: res.NsRecords != nil},
+		{t: v1alpha1.PTR, field: "ptrRecords", populated: res.PtrRecords != nil},
+		{t: v1alpha1.SOA, field: "soaRecord", populated: res.SoaRecord != nil},
+		{t: v1alpha1.SRV, field: "srvRecords", populated: res.SrvRecords != nil},
+		{t: v1alpha1.TXT, field: "txtRecords", populated: res.TxtRecords != nil},
+	}
+
+	alias := r.TargetResource != nil && r.TargetResource.ID != nil
+	var errs field.ErrorList
+	for _, rec := range records {
+		switch {
+		case rec.t != r.RecordType && rec.populated:
+			errs = append(errs, field.Forbidden(path.Child(rec.field), "must not be set for a record set of type "+string(r.RecordType)))
+		case rec.t == r.RecordType && !rec.populated && !alias:
+			errs = append(errs, field.Required(path.Child(rec.field), "must be set for a record set of type "+string(r.RecordType)))
+		}
+	}
+	if alias && r.RecordType != v1alpha1.A && r.RecordType != v1alpha1.AAAA && r.RecordType != v1alpha1.CNAME {
+		errs = append(errs, field.Forbidden(path.Child("targetResource"), "may only be set for a record set of type A, AAAA or CNAME"))
+	}
+	return errs
+}
+
+// RecordSetIsUpToDate returns the fields of the record set that need to be
+// updated.
+func RecordSetIsUpToDate(r *v1alpha1.RecordSetParameters, az *dns.RecordSetProperties) azure.Diff {
 	up := NewRecordSetParameters(r)
 
-	return cmp.Equal(up.RecordSetProperties, az,
+	return azure.DiffObjects(up.RecordSetProperties, az, nil,
 		cmpopts.IgnoreFields(dns.RecordSetProperties{}, "Fqdn", "ProvisioningState"))
 }
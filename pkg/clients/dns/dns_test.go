@@ -23,6 +23,7 @@ import (
 	"github.com/google/go-cmp/cmp"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 
 	"github.com/crossplane-contrib/provider-azure/apis/dns/v1alpha1"
 	azure "github.com/crossplane-contrib/provider-azure/pkg/clients"
@@ -139,7 +140,7 @@ func TestNewZoneParameters(t *testing.T) {
 			},
 			want: dns.Zone{
 				Location: azure.ToStringPtr(location),
-				Tags:     azure.ToStringPtrMap(tags),
+				Tags:     azure.NewTags(&v1alpha1.Zone{}, nil, tags),
 				ZoneProperties: &dns.ZoneProperties{
 					ZoneType: dns.ZoneType(publicZone),
 					RegistrationVirtualNetworks: &[]dns.SubResource{
@@ -155,7 +156,7 @@ func TestNewZoneParameters(t *testing.T) {
 
 	for _, tc := range cases {
 		t.Run(tc.name, func(t *testing.T) {
-			got := NewZoneParameters(tc.r)
+			got := NewZoneParameters(tc.r, nil)
 			if diff := cmp.Diff(tc.want, got); diff != "" {
 				t.Errorf("NewZoneParameters(...): -want, +got\n%s", diff)
 			}
@@ -227,7 +228,7 @@ func TestZoneIsUpToDate(t *testing.T) {
 
 	for _, tc := range cases {
 		t.Run(tc.name, func(t *testing.T) {
-			got := ZoneIsUpToDate(tc.kube, tc.az)
+			got := ZoneIsUpToDate(tc.kube, tc.az, nil).UpToDate()
 			if diff := cmp.Diff(tc.want, got); diff != "" {
 				t.Errorf("SubnetNeedsUpdate(...): -want, +got\n%s", diff)
 			}
@@ -535,7 +536,7 @@ func TestRecordSetIsUpToDate(t *testing.T) {
 		name string
 		kube *v1alpha1.RecordSetParameters
 		az   *dns.RecordSetProperties
-		want bool
+		want azure.Diff
 	}{
 		{
 			name: "NotUpToDate",
@@ -548,7 +549,12 @@ func TestRecordSetIsUpToDate(t *testing.T) {
 			az: &dns.RecordSetProperties{
 				ARecords: &[]dns.ARecord{},
 			},
-			want: false,
+			want: azure.Diff{
+				{Path: "Metadata", Desired: `{"one":"test","two":"test"}`, Observed: "null"},
+				{Path: "TTL", Desired: "3600", Observed: "null"},
+				{Path: "TargetResource", Desired: `{"id":"a-very-cool-id"}`, Observed: "null"},
+				{Path: "ARecords[0]", Desired: `{"ipv4Address":"1.1.1.1"}`, Observed: "null"},
+			},
 		},
 		{
 			name: "UpToDate",
@@ -568,7 +574,7 @@ func TestRecordSetIsUpToDate(t *testing.T) {
 					{Ipv4Address: azure.ToStringPtr(ip)},
 				},
 			},
-			want: true,
+			want: azure.Diff{},
 		},
 	}
 
@@ -578,7 +584,70 @@ func TestRecordSetIsUpToDate(t *testing.T) {
 
 			got := RecordSetIsUpToDate(tc.kube, tc.az)
 			if diff := cmp.Diff(tc.want, got); diff != "" {
-				t.Errorf("SubnetNeedsUpdate(...): -want, +got\n%s", diff)
+				t.Errorf("RecordSetIsUpToDate(...): -want, +got\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestValidateRecordSetParameters(t *testing.T) {
+	path := field.NewPath("spec", "forProvider")
+	cases := []struct {
+		name string
+		r    *v1alpha1.RecordSetParameters
+		want field.ErrorList
+	}{
+		{
+			name: "Valid",
+			r: &v1alpha1.RecordSetParameters{
+				RecordType: v1alpha1.A,
+				ARecords:   []v1alpha1.ARecord{{IPV4Address: &ip}},
+			},
+		},
+		{
+			name: "MismatchedRecords",
+			r: &v1alpha1.RecordSetParameters{
+				RecordType:  v1alpha1.CNAME,
+				ARecords:    []v1alpha1.ARecord{{IPV4Address: &ip}},
+				CNAMERecord: v1alpha1.CNAMERecord{CNAME: azure.ToStringPtr("cool.online")},
+			},
+			want: field.ErrorList{
+				field.Forbidden(path.Child("aRecords"), "must not be set for a record set of type CNAME"),
+			},
+		},
+		{
+			name: "MissingRecords",
+			r: &v1alpha1.RecordSetParameters{
+				RecordType: v1alpha1.MX,
+			},
+			want: field.ErrorList{
+				field.Required(path.Child("mxRecords"), "must be set for a record set of type MX"),
+			},
+		},
+		{
+			name: "Alias",
+			r: &v1alpha1.RecordSetParameters{
+				RecordType:     v1alpha1.AAAA,
+				TargetResource: &v1alpha1.SubResource{ID: azure.ToStringPtr(id)},
+			},
+		},
+		{
+			name: "UnsupportedAlias",
+			r: &v1alpha1.RecordSetParameters{
+				RecordType:     v1alpha1.TXT,
+				TargetResource: &v1alpha1.SubResource{ID: azure.ToStringPtr(id)},
+			},
+			want: field.ErrorList{
+				field.Forbidden(path.Child("targetResource"), "may only be set for a record set of type A, AAAA or CNAME"),
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := ValidateRecordSetParameters(tc.r, path)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("ValidateRecordSetParameters(...): -want, +got\n%s", diff)
 			}
 		})
 	}
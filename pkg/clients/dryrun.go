@@ -0,0 +1,348 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azure
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+)
+
+const errFmtPlan = "cannot plan %s"
+
+// A PlannedRequest is a request that a dry-run managed resource would have
+// sent to Azure.
+type PlannedRequest struct {
+	Method string
+	URL    string
+
+	// Body of the request, with the values of secret-bearing fields
+	// replaced by Redacted.
+	Body string
+}
+
+func (r PlannedRequest) String() string {
+	if r.Body == "" {
+		return r.Method + " " + r.URL
+	}
+	return r.Method + " " + r.URL + " " + r.Body
+}
+
+// A Plan lists the requests that a dry-run managed resource would have sent to
+// Azure, in order.
+type Plan []PlannedRequest
+
+func (p Plan) String() string {
+	s := make([]string, len(p))
+	for i, r := range p {
+		s[i] = r.String()
+	}
+	return strings.Join(s, "; ")
+}
+
+type planKey struct{}
+
+// withPlan returns a copy of the supplied context. Requests that would change
+// an external resource are not sent to Azure if their context is derived from
+// it. They are instead added to the supplied Plan.
+func withPlan(ctx context.Context, p *Plan) context.Context {
+	return context.WithValue(ctx, planKey{}, p)
+}
+
+// WithDryRun returns an autorest.SendDecorator that does not send requests that
+// would change an external resource if their context was derived from one
+// returned by withPlan. It adds them to the context's Plan instead, and
+// responds as if Azure had completed them synchronously.
+func WithDryRun() autorest.SendDecorator {
+	return func(s autorest.Sender) autorest.Sender {
+		return autorest.SenderFunc(func(r *http.Request) (*http.Response, error) {
+			p, ok := r.Context().Value(planKey{}).(*Plan)
+			if !ok || IsReadOnly(r.Method, r.URL.Path) {
+				return s.Do(r)
+			}
+			body, err := readBody(r)
+			if err != nil {
+				return nil, err
+			}
+			u := *r.URL
+			u.RawQuery = ""
+			*p = append(*p, PlannedRequest{Method: r.Method, URL: u.String(), Body: redactBody(u.Path, body)})
+			return planned(r, body), nil
+		})
+	}
+}
+
+// readOnlyActions are the lower case names of the Azure API actions that
+// read, but never change, the external resource they are POSTed to.
+var readOnlyActions = map[string]bool{
+	"listkeys":                   true,
+	"listclusteradmincredential": true,
+	"checknameavailability":      true,
+}
+
+// IsReadOnly returns true if a request with the supplied method and URL path
+// never changes an external resource. Such requests are sent to Azure even
+// while planning the requests of a dry-run managed resource.
+func IsReadOnly(method, urlPath string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead:
+		return true
+	case http.MethodPost:
+		return readOnlyActions[strings.ToLower(path.Base(urlPath))]
+	}
+	return false
+}
+
+// readBody reads the body of the supplied request, and replaces it such that
+// it may be read again.
+func readBody(r *http.Request) ([]byte, error) {
+	if r.Body == nil {
+		return nil, nil
+	}
+	b, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return nil, err
+	}
+	r.Body = ioutil.NopCloser(bytes.NewReader(b))
+	return b, nil
+}
+
+// planned returns the response to a planned request. The request's body is
+// echoed, so that the SDK can unmarshal a result from it. The response has no
+// polling headers, so that any long-running operation is considered complete.
+func planned(r *http.Request, body []byte) *http.Response {
+	if len(body) == 0 {
+		body = []byte("{}")
+	}
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    http.StatusOK,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": []string{"application/json"}},
+		Body:          ioutil.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       r,
+	}
+}
+
+// sensitiveFields are the lower case substrings of the JSON field names whose
+// string values are redacted from planned requests. Fields whose names end in
+// key or keys are redacted too.
+var sensitiveFields = []string{"password", "secret", "connectionstring", "token"}
+
+// redactBody returns the supplied JSON request body, with the string values of
+// any secret-bearing fields replaced by Redacted. Request bodies that are not
+// JSON objects are redacted entirely.
+func redactBody(path string, body []byte) string {
+	if len(body) == 0 {
+		return ""
+	}
+	var v interface{}
+	if err := json.Unmarshal(body, &v); err != nil {
+		return Redacted
+	}
+	// The value of a Key Vault secret is its only secret-bearing field.
+	redactValue := strings.Contains(strings.ToLower(path), "/secrets/")
+	b, err := marshal(redact(v, redactValue))
+	if err != nil {
+		return Redacted
+	}
+	return string(b)
+}
+
+func redact(v interface{}, redactValue bool) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, f := range v {
+			if _, ok := f.(string); ok && (sensitive(k) || (redactValue && k == "value")) {
+				v[k] = Redacted
+				continue
+			}
+			v[k] = redact(f, redactValue)
+		}
+	case []interface{}:
+		for i := range v {
+			v[i] = redact(v[i], redactValue)
+		}
+	}
+	return v
+}
+
+func sensitive(field string) bool {
+	f := strings.ToLower(field)
+	if strings.HasSuffix(f, "key") || strings.HasSuffix(f, "keys") {
+		return true
+	}
+	for _, s := range sensitiveFields {
+		if strings.Contains(f, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// Condition types and reasons.
+const (
+	// TypePlanned resources are dry-run, and report the requests that
+	// would be sent to Azure to reconcile their external resource.
+	TypePlanned xpv1.ConditionType = "Planned"
+
+	ReasonPlannedCreate xpv1.ConditionReason = "PlannedCreate"
+	ReasonPlannedUpdate xpv1.ConditionReason = "PlannedUpdate"
+	ReasonPlannedDelete xpv1.ConditionReason = "PlannedDelete"
+	ReasonNoChanges     xpv1.ConditionReason = "NoChanges"
+)
+
+// Planned returns a condition that indicates a dry-run managed resource would
+// send the supplied requests to Azure for the supplied reason. Its message
+// also lists the supplied fields, if any.
+func Planned(r xpv1.ConditionReason, p Plan, d Diff) xpv1.Condition {
+	msg := fmt.Sprintf("The managed resource is dry-run, and did not send %d request(s) to Azure: %s", len(p), p)
+	if !d.UpToDate() {
+		msg = fmt.Sprintf("%s (fields: %s)", msg, d)
+	}
+	return xpv1.Condition{
+		Type:               TypePlanned,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             r,
+		Message:            msg,
+	}
+}
+
+// NoChanges returns a condition that indicates a dry-run managed resource would
+// send no requests to Azure that change its external resource.
+func NoChanges() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypePlanned,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonNoChanges,
+	}
+}
+
+// PlanDryRuns returns a ConnecterWrapper that never creates, updates or
+// deletes the external resources of dry-run managed resources. It plans the
+// requests that would be sent to Azure to do so, and reports them instead.
+func PlanDryRuns(r event.Recorder) ConnecterWrapper {
+	return wrapExternal(func(e managed.ExternalClient) managed.ExternalClient {
+		return &dryRunExternal{ExternalClient: e, record: r}
+	})
+}
+
+type dryRunExternal struct {
+	managed.ExternalClient
+	record event.Recorder
+}
+
+// nolint:gocyclo
+func (e *dryRunExternal) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	ctx, d := recordingDiff(ctx)
+	o, err := e.ExternalClient.Observe(ctx, mg)
+	if err != nil || !IsDryRun(mg) {
+		return o, err
+	}
+
+	var r xpv1.ConditionReason
+	var name string
+	var op func(ctx context.Context, mg resource.Managed) error
+	switch {
+	case meta.WasDeleted(mg):
+		if !o.ResourceExists {
+			return o, nil
+		}
+		r, name, op = ReasonPlannedDelete, "delete", e.ExternalClient.Delete
+		// Reporting the external resource as gone lets the managed
+		// reconciler remove its finalizer without deleting it.
+		o.ResourceExists = false
+	case !o.ResourceExists:
+		r, name, op = ReasonPlannedCreate, "create", func(ctx context.Context, mg resource.Managed) error {
+			_, err := e.ExternalClient.Create(ctx, mg)
+			return err
+		}
+		o.ResourceExists, o.ResourceUpToDate = true, true
+	case !o.ResourceUpToDate:
+		r, name, op = ReasonPlannedUpdate, "update", func(ctx context.Context, mg resource.Managed) error {
+			_, err := e.ExternalClient.Update(ctx, mg)
+			return err
+		}
+		o.ResourceUpToDate = true
+	default:
+		mg.SetConditions(NoChanges())
+		return o, nil
+	}
+
+	// The external client may change the managed resource as it creates,
+	// updates or deletes the external resource, so it is passed a copy.
+	p := Plan{}
+	err = op(withPlan(ctx, &p), mg.DeepCopyObject().(resource.Managed))
+	switch {
+	case len(p) == 0 && err != nil:
+		// Any error after a request was planned is most likely caused
+		// by the planned response, so only an error that prevented the
+		// external client from sending any request is returned.
+		return managed.ExternalObservation{}, errors.Wrapf(err, errFmtPlan, name)
+	case len(p) == 0:
+		mg.SetConditions(NoChanges())
+		return o, nil
+	}
+	c := Planned(r, p, *d)
+	if old := mg.GetCondition(TypePlanned); old.Reason != c.Reason || old.Message != c.Message {
+		e.record.Event(mg, event.Normal(event.Reason(r), c.Message))
+	}
+	mg.SetConditions(c)
+	return o, nil
+}
+
+func (e *dryRunExternal) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
+	if IsDryRun(mg) {
+		return managed.ExternalCreation{}, errors.New(errObserveOnlyCreate)
+	}
+	return e.ExternalClient.Create(ctx, mg)
+}
+
+func (e *dryRunExternal) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
+	if IsDryRun(mg) {
+		return managed.ExternalUpdate{}, nil
+	}
+	return e.ExternalClient.Update(ctx, mg)
+}
+
+func (e *dryRunExternal) Delete(ctx context.Context, mg resource.Managed) error {
+	if IsDryRun(mg) {
+		return nil
+	}
+	return e.ExternalClient.Delete(ctx, mg)
+}
@@ -0,0 +1,187 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azure
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func TestWithDryRun(t *testing.T) {
+	var sent []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sent = append(sent, r.Method)
+	}))
+	defer srv.Close()
+	s := autorest.DecorateSender(srv.Client(), WithDryRun())
+
+	do := func(ctx context.Context, method, path, body string) *http.Response {
+		t.Helper()
+		r, _ := http.NewRequestWithContext(ctx, method, srv.URL+path+"?api-version=1", strings.NewReader(body))
+		rsp, err := s.Do(r)
+		if err != nil {
+			t.Fatalf("Do(...): %s", err)
+		}
+		return rsp
+	}
+
+	p := Plan{}
+	ctx := withPlan(context.Background(), &p)
+	do(context.Background(), http.MethodPut, "/servers/cool", `{}`)
+	do(ctx, http.MethodGet, "/servers/cool", "")
+	do(ctx, http.MethodPost, "/servers/cool/listKeys", "")
+	rsp := do(ctx, http.MethodPut, "/servers/cool", `{"location":"westus","properties":{"administratorLoginPassword":"hunter2"}}`)
+	do(ctx, http.MethodPost, "/servers/cool/restart", "")
+	do(ctx, http.MethodDelete, "/servers/cool", "")
+
+	if diff := cmp.Diff([]string{http.MethodPut, http.MethodGet, http.MethodPost}, sent); diff != "" {
+		t.Errorf("Do(...): -want requests sent, +got:\n%s", diff)
+	}
+	want := Plan{
+		{Method: http.MethodPut, URL: srv.URL + "/servers/cool", Body: `{"location":"westus","properties":{"administratorLoginPassword":"<redacted>"}}`},
+		{Method: http.MethodPost, URL: srv.URL + "/servers/cool/restart"},
+		{Method: http.MethodDelete, URL: srv.URL + "/servers/cool"},
+	}
+	if diff := cmp.Diff(want, p); diff != "" {
+		t.Errorf("Do(...): -want plan, +got:\n%s", diff)
+	}
+	if diff := cmp.Diff(http.StatusOK, rsp.StatusCode); diff != "" {
+		t.Errorf("Do(...): -want status of a planned request, +got:\n%s", diff)
+	}
+}
+
+func TestRedactBody(t *testing.T) {
+	type args struct {
+		path string
+		body string
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   string
+	}{
+		"Empty": {
+			reason: "An empty body should remain empty",
+			args:   args{path: "/servers/cool"},
+			want:   "",
+		},
+		"NotJSON": {
+			reason: "A body that is not JSON should be redacted entirely",
+			args:   args{path: "/containers/cool/blob", body: "data"},
+			want:   Redacted,
+		},
+		"Sensitive": {
+			reason: "Secret-bearing string fields should be redacted",
+			args: args{
+				path: "/servers/cool",
+				body: `{"tags":{"a":"b"},"keys":[{"primaryKey":"k"}],"connectionString":"c","value":"v"}`,
+			},
+			want: `{"connectionString":"<redacted>","keys":[{"primaryKey":"<redacted>"}],"tags":{"a":"b"},"value":"v"}`,
+		},
+		"KeyVaultSecret": {
+			reason: "The value of a Key Vault secret should be redacted",
+			args:   args{path: "/secrets/cool", body: `{"value":"v","contentType":"text/plain"}`},
+			want:   `{"contentType":"text/plain","value":"<redacted>"}`,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := redactBody(tc.args.path, []byte(tc.args.body))
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nredactBody(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestPlanDryRuns(t *testing.T) {
+	errBoom := errors.New("boom")
+	s := autorest.DecorateSender(http.DefaultClient, WithDryRun())
+	send := func(ctx context.Context) error {
+		r, _ := http.NewRequestWithContext(ctx, http.MethodPatch, "https://example.invalid/servers/cool", nil)
+		_, err := s.Do(r)
+		return err
+	}
+
+	type want struct {
+		o      managed.ExternalObservation
+		err    error
+		reason xpv1.ConditionReason
+	}
+	cases := map[string]struct {
+		reason string
+		o      managed.ExternalObservation
+		update func(ctx context.Context) error
+		want   want
+	}{
+		"UpToDate": {
+			reason: "A dry-run resource whose external resource is up to date should plan no changes",
+			o:      managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+			want:   want{o: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true}, reason: ReasonNoChanges},
+		},
+		"PlannedUpdate": {
+			reason: "The requests that would update the external resource should be planned rather than sent",
+			o:      managed.ExternalObservation{ResourceExists: true},
+			update: send,
+			want:   want{o: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true}, reason: ReasonPlannedUpdate},
+		},
+		"PlanError": {
+			reason: "An error that prevents the external client from planning any request should be returned",
+			o:      managed.ExternalObservation{ResourceExists: true},
+			update: func(_ context.Context) error { return errBoom },
+			want:   want{err: errors.Wrapf(errBoom, errFmtPlan, "update")},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			mg := &fake.Managed{}
+			mg.SetAnnotations(map[string]string{AnnotationKeyManagementMode: string(ManagementModeDryRun)})
+			e := connect(t, &managed.ExternalClientFns{
+				ObserveFn: func(_ context.Context, _ resource.Managed) (managed.ExternalObservation, error) { return tc.o, nil },
+				UpdateFn: func(ctx context.Context, _ resource.Managed) (managed.ExternalUpdate, error) {
+					return managed.ExternalUpdate{}, tc.update(ctx)
+				},
+			}, mg, PlanDryRuns(event.NewNopRecorder()))
+			got, err := e.Observe(context.Background(), mg)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.o, got); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.reason, mg.GetCondition(TypePlanned).Reason); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want Planned reason, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,403 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azure
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/Azure/azure-storage-blob-go/azblob"
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/azure"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	"github.com/crossplane-contrib/provider-azure/apis/v1alpha3"
+)
+
+// An ErrorCategory classifies an error returned by Azure.
+type ErrorCategory string
+
+// Error categories.
+const (
+	// ErrorNone is the category of errors that were not returned by Azure,
+	// including nil errors.
+	ErrorNone ErrorCategory = ""
+
+	ErrorNotFound                      ErrorCategory = "NotFound"
+	ErrorConflict                      ErrorCategory = "Conflict"
+	ErrorQuotaExceeded                 ErrorCategory = "QuotaExceeded"
+	ErrorAuthorizationFailed           ErrorCategory = "AuthorizationFailed"
+	ErrorInvalidParameters             ErrorCategory = "InvalidParameters"
+	ErrorResourceProviderNotRegistered ErrorCategory = "ResourceProviderNotRegistered"
+	ErrorThrottled                     ErrorCategory = "Throttled"
+	ErrorUnknown                       ErrorCategory = "Unknown"
+)
+
+// Retryable returns true if an operation that failed with an error of this
+// category may succeed if retried without any change to the managed resource,
+// its credentials or its subscription.
+func (c ErrorCategory) Retryable() bool {
+	switch c {
+	case ErrorQuotaExceeded, ErrorAuthorizationFailed, ErrorInvalidParameters, ErrorResourceProviderNotRegistered:
+		return false
+	default:
+		return true
+	}
+}
+
+// errorCodeCategories maps the error codes returned by Azure Resource Manager
+// and Azure Storage to error categories. Codes are lower case.
+var errorCodeCategories = map[string]ErrorCategory{
+	"missingsubscriptionregistration": ErrorResourceProviderNotRegistered,
+	"noregisteredproviderfound":       ErrorResourceProviderNotRegistered,
+	"subscriptionnotregistered":       ErrorResourceProviderNotRegistered,
+
+	"authorizationfailed":              ErrorAuthorizationFailed,
+	"linkedauthorizationfailed":        ErrorAuthorizationFailed,
+	"authenticationfailed":             ErrorAuthorizationFailed,
+	"invalidauthenticationtoken":       ErrorAuthorizationFailed,
+	"invalidauthenticationtokentenant": ErrorAuthorizationFailed,
+	"authorizationfailure":             ErrorAuthorizationFailed,
+	"authorizationpermissionmismatch":  ErrorAuthorizationFailed,
+	"insufficientaccountpermissions":   ErrorAuthorizationFailed,
+
+	"conflict":                   ErrorConflict,
+	"anotheroperationinprogress": ErrorConflict,
+	"resourcegroupbeingdeleted":  ErrorConflict,
+	"containeralreadyexists":     ErrorConflict,
+	"containerbeingdeleted":      ErrorConflict,
+	"leasealreadypresent":        ErrorConflict,
+	"leaseidmissing":             ErrorConflict,
+
+	"invalidparameter":                    ErrorInvalidParameters,
+	"invalidrequestcontent":               ErrorInvalidParameters,
+	"invalidrequestformat":                ErrorInvalidParameters,
+	"badrequest":                          ErrorInvalidParameters,
+	"invalidresourcename":                 ErrorInvalidParameters,
+	"invalidresourcelocation":             ErrorInvalidParameters,
+	"locationnotavailableforresourcetype": ErrorInvalidParameters,
+	"linkedinvalidpropertyid":             ErrorInvalidParameters,
+	"invalidapiversionparameter":          ErrorInvalidParameters,
+	"invalidheadervalue":                  ErrorInvalidParameters,
+	"invalidqueryparametervalue":          ErrorInvalidParameters,
+	"invalidinput":                        ErrorInvalidParameters,
+	"outofrangeinput":                     ErrorInvalidParameters,
+
+	"resourcenotfound":       ErrorNotFound,
+	"resourcegroupnotfound":  ErrorNotFound,
+	"parentresourcenotfound": ErrorNotFound,
+	"containernotfound":      ErrorNotFound,
+
+	"toomanyrequests": ErrorThrottled,
+}
+
+// Classify returns the category of the supplied error. Errors that were not
+// returned by Azure are categorized as ErrorNone. Errors that were returned by
+// Azure but could not be categorized more specifically are categorized as
+// ErrorUnknown.
+func Classify(err error) ErrorCategory {
+	if err == nil {
+		return ErrorNone
+	}
+	var tErr *ThrottledError
+	if errors.As(err, &tErr) {
+		return ErrorThrottled
+	}
+
+	code, status, ok := azureError(err)
+	if !ok {
+		return ErrorNone
+	}
+	code = strings.ToLower(code)
+	if c, ok := errorCodeCategories[code]; ok {
+		return c
+	}
+	if strings.Contains(code, "quota") {
+		return ErrorQuotaExceeded
+	}
+
+	switch status {
+	case http.StatusBadRequest:
+		return ErrorInvalidParameters
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return ErrorAuthorizationFailed
+	case http.StatusNotFound:
+		return ErrorNotFound
+	case http.StatusConflict:
+		return ErrorConflict
+	case http.StatusTooManyRequests:
+		return ErrorThrottled
+	}
+	return ErrorUnknown
+}
+
+// azureError returns the error code and HTTP status code of the supplied
+// error, and whether it was returned by Azure at all.
+func azureError(err error) (code string, status int, ok bool) {
+	var sErr azblob.StorageError
+	if errors.As(err, &sErr) {
+		if r := sErr.Response(); r != nil { // nolint: bodyclose
+			status = r.StatusCode
+		}
+		return string(sErr.ServiceCode()), status, true
+	}
+
+	var dErr autorest.DetailedError
+	if errors.As(err, &dErr) {
+		status, _ = dErr.StatusCode.(int)
+		ok = true
+	}
+
+	var rErr *azure.RequestError
+	if errors.As(err, &rErr) && rErr.ServiceError != nil {
+		return rErr.ServiceError.Code, status, true
+	}
+
+	// The Azure SDK returns a RequestError by value when it gives up on a
+	// request it retried in order to register a resource provider.
+	var rvErr azure.RequestError
+	if errors.As(err, &rvErr) && rvErr.ServiceError != nil {
+		return rvErr.ServiceError.Code, status, true
+	}
+
+	var seErr *azure.ServiceError
+	if errors.As(err, &seErr) {
+		return seErr.Code, status, true
+	}
+
+	return "", status, ok
+}
+
+// RequestIDs returns the request and correlation IDs Azure assigned to the
+// request that returned the supplied error. Either may be empty, for example
+// if the error was not returned by Azure.
+func RequestIDs(err error) (requestID, correlationID string) {
+	var h http.Header
+	var sErr azblob.StorageError
+	var dErr autorest.DetailedError
+	switch {
+	case errors.As(err, &sErr):
+		if r := sErr.Response(); r != nil { // nolint: bodyclose
+			h = r.Header
+		}
+	case errors.As(err, &dErr):
+		if dErr.Response != nil {
+			h = dErr.Response.Header
+		}
+	}
+	if h == nil {
+		return "", ""
+	}
+	return h.Get(HeaderRequestID), h.Get(HeaderCorrelationRequestID)
+}
+
+// WithRequestIDs returns an error that wraps the supplied error and whose
+// message includes the request and correlation IDs Azure assigned to the
+// request that returned it, so that the IDs appear wherever the error is
+// reported. The supplied error is returned as is if Azure assigned it no IDs.
+func WithRequestIDs(err error) error {
+	var iErr *identifiedError
+	if err == nil || errors.As(err, &iErr) {
+		return err
+	}
+	rid, cid := RequestIDs(err)
+	if rid == "" && cid == "" {
+		return err
+	}
+	return &identifiedError{err: err, requestID: rid, correlationID: cid}
+}
+
+// An identifiedError is an error returned by Azure, annotated with the IDs
+// Azure assigned to the request that returned it.
+type identifiedError struct {
+	err           error
+	requestID     string
+	correlationID string
+}
+
+func (e *identifiedError) Error() string {
+	ids := make([]string, 0, 2)
+	if e.requestID != "" {
+		ids = append(ids, "request ID: "+e.requestID)
+	}
+	if e.correlationID != "" {
+		ids = append(ids, "correlation ID: "+e.correlationID)
+	}
+	return fmt.Sprintf("%s (%s)", e.err, strings.Join(ids, ", "))
+}
+
+func (e *identifiedError) Unwrap() error { return e.err }
+
+// Cause returns the wrapped error, for compatibility with errors.Cause.
+func (e *identifiedError) Cause() error { return e.err }
+
+// A FailedRequestRecorder records the most recent request to Azure that
+// failed.
+type FailedRequestRecorder interface {
+	GetLastFailedRequest() *v1alpha3.FailedRequest
+	SetLastFailedRequest(r *v1alpha3.FailedRequest)
+}
+
+// SetLastFailedRequest records the request and correlation IDs Azure assigned
+// to the request that returned the supplied error, if any, as the last failed
+// request of the supplied resource. Errors without IDs leave any previously
+// recorded request unchanged.
+func SetLastFailedRequest(o FailedRequestRecorder, err error) {
+	rid, cid := RequestIDs(err)
+	if rid == "" && cid == "" {
+		return
+	}
+	o.SetLastFailedRequest(&v1alpha3.FailedRequest{RequestID: rid, CorrelationID: cid, Time: metav1.Now()})
+}
+
+// Condition types and reasons.
+const (
+	// TypeAzureError resources have had requests to Azure fail. The reason
+	// of the condition is the ErrorCategory of the most recent failure, and
+	// its message includes the ID of the failing request.
+	TypeAzureError xpv1.ConditionType = "AzureError"
+
+	ReasonNoAzureError xpv1.ConditionReason = "NoError"
+)
+
+// AzureError returns a condition that indicates a request to Azure failed
+// with an error of the supplied category.
+func AzureError(c ErrorCategory, err error) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeAzureError,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             xpv1.ConditionReason(c),
+		Message:            err.Error(),
+	}
+}
+
+// NoAzureError returns a condition that indicates requests to Azure are
+// succeeding.
+func NoAzureError() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeAzureError,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonNoAzureError,
+	}
+}
+
+// SetAzureError sets the AzureError condition of the supplied resource if the
+// supplied error was returned by Azure, and returns its category. If the error
+// is nil it clears the condition if it was previously set.
+func SetAzureError(o resource.Conditioned, err error) ErrorCategory {
+	c := Classify(err)
+	switch {
+	case c != ErrorNone:
+		o.SetConditions(AzureError(c, err))
+	case err == nil && o.GetCondition(TypeAzureError).Status == corev1.ConditionTrue:
+		o.SetConditions(NoAzureError())
+	}
+	return c
+}
+
+// ClassifyErrors returns a ConnecterWrapper that reports the category of any
+// error Azure returns while observing, creating, updating or deleting an
+// external resource, both as the reason of its managed resource's AzureError
+// condition and as the reason of a warning event.
+func ClassifyErrors(r event.Recorder) ConnecterWrapper {
+	return wrapExternal(func(e managed.ExternalClient) managed.ExternalClient {
+		return &classifyingExternal{ExternalClient: e, record: r}
+	})
+}
+
+type classifyingExternal struct {
+	managed.ExternalClient
+	record event.Recorder
+}
+
+func (e *classifyingExternal) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	o, err := e.ExternalClient.Observe(ctx, mg)
+	return o, e.classify(ctx, mg, err)
+}
+
+func (e *classifyingExternal) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
+	c, err := e.ExternalClient.Create(ctx, mg)
+	return c, e.classify(ctx, mg, err)
+}
+
+func (e *classifyingExternal) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
+	u, err := e.ExternalClient.Update(ctx, mg)
+	return u, e.classify(ctx, mg, err)
+}
+
+func (e *classifyingExternal) Delete(ctx context.Context, mg resource.Managed) error {
+	return e.classify(ctx, mg, e.ExternalClient.Delete(ctx, mg))
+}
+
+func (e *classifyingExternal) classify(ctx context.Context, mg resource.Managed, err error) error {
+	c := SetAzureError(mg, err)
+	recordErrorCategory(ctx, c)
+	if c != ErrorNone {
+		e.record.Event(mg, event.Warning(event.Reason(c), err))
+	}
+	return err
+}
+
+// IdentifyFailedRequests returns a ConnecterWrapper that annotates any error
+// Azure returns with the IDs of the failing request, and records them as the
+// last failed request of managed resources that are FailedRequestRecorders.
+func IdentifyFailedRequests() ConnecterWrapper {
+	return wrapExternal(func(e managed.ExternalClient) managed.ExternalClient {
+		return &identifyingExternal{ExternalClient: e}
+	})
+}
+
+type identifyingExternal struct {
+	managed.ExternalClient
+}
+
+func (e *identifyingExternal) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	o, err := e.ExternalClient.Observe(ctx, mg)
+	return o, identify(mg, err)
+}
+
+func (e *identifyingExternal) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
+	c, err := e.ExternalClient.Create(ctx, mg)
+	return c, identify(mg, err)
+}
+
+func (e *identifyingExternal) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
+	u, err := e.ExternalClient.Update(ctx, mg)
+	return u, identify(mg, err)
+}
+
+func (e *identifyingExternal) Delete(ctx context.Context, mg resource.Managed) error {
+	return identify(mg, e.ExternalClient.Delete(ctx, mg))
+}
+
+func identify(mg resource.Managed, err error) error {
+	if r, ok := mg.(FailedRequestRecorder); ok {
+		SetLastFailedRequest(r, err)
+	}
+	return WithRequestIDs(err)
+}
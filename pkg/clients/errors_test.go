@@ -0,0 +1,354 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azure
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/Azure/azure-storage-blob-go/azblob"
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/azure"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane-contrib/provider-azure/apis/v1alpha3"
+)
+
+type storageError struct {
+	code   azblob.ServiceCodeType
+	status int
+}
+
+func (s *storageError) ServiceCode() azblob.ServiceCodeType { return s.code }
+func (s *storageError) Error() string                       { return string(s.code) }
+func (s *storageError) Timeout() bool                       { return false }
+func (s *storageError) Temporary() bool                     { return false }
+func (s *storageError) Response() *http.Response            { return &http.Response{StatusCode: s.status} }
+
+var _ azblob.StorageError = &storageError{}
+
+// requestError returns an error as returned by an Azure SDK client when Azure
+// Resource Manager responds with an error.
+func requestError(status int, code string) error {
+	return autorest.NewErrorWithError(&azure.RequestError{
+		DetailedError: autorest.DetailedError{StatusCode: status},
+		ServiceError:  &azure.ServiceError{Code: code},
+	}, "resources.GroupsClient", "CreateOrUpdate", &http.Response{StatusCode: status}, "Failure responding to request")
+}
+
+func TestClassify(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		err    error
+		want   ErrorCategory
+	}{
+		"Nil": {
+			reason: "A nil error should not be categorized",
+			want:   ErrorNone,
+		},
+		"NotAzure": {
+			reason: "Errors that were not returned by Azure should not be categorized",
+			err:    errors.New("boom"),
+			want:   ErrorNone,
+		},
+		"NotFound": {
+			reason: "A 404 should be categorized as not found",
+			err:    autorest.DetailedError{StatusCode: http.StatusNotFound},
+			want:   ErrorNotFound,
+		},
+		"ResourceProviderNotRegistered": {
+			reason: "A MissingSubscriptionRegistration error should be categorized by its code rather than its 409 status",
+			err:    errors.Wrap(requestError(http.StatusConflict, "MissingSubscriptionRegistration"), "cannot create"),
+			want:   ErrorResourceProviderNotRegistered,
+		},
+		"QuotaExceeded": {
+			reason: "Errors with codes mentioning a quota should be categorized as quota exceeded",
+			err:    requestError(http.StatusConflict, "SubscriptionQuotaExceeded"),
+			want:   ErrorQuotaExceeded,
+		},
+		"AuthorizationFailed": {
+			reason: "An AuthorizationFailed error should be categorized as such",
+			err:    requestError(http.StatusForbidden, "AuthorizationFailed"),
+			want:   ErrorAuthorizationFailed,
+		},
+		"InvalidParameters": {
+			reason: "A 400 with an unrecognized code should be categorized as invalid parameters",
+			err:    requestError(http.StatusBadRequest, "SomethingNew"),
+			want:   ErrorInvalidParameters,
+		},
+		"Conflict": {
+			reason: "A failed long-running operation should be categorized by its code",
+			err:    errors.Wrap(&azure.ServiceError{Code: "AnotherOperationInProgress"}, "cannot update"),
+			want:   ErrorConflict,
+		},
+		"ConflictAfterRegistration": {
+			reason: "An error the Azure SDK returns after retrying to register a resource provider should be categorized by its code",
+			err: autorest.NewErrorWithError(azure.RequestError{
+				ServiceError: &azure.ServiceError{Code: "AnotherOperationInProgress"},
+			}, "redis.Client", "Create", nil, "Failure sending request"),
+			want: ErrorConflict,
+		},
+		"Throttled": {
+			reason: "A request held back by a Throttler should be categorized as throttled",
+			err:    &ThrottledError{},
+			want:   ErrorThrottled,
+		},
+		"Unknown": {
+			reason: "Azure errors that cannot be categorized more specifically should be unknown",
+			err:    requestError(http.StatusInternalServerError, "InternalServerError"),
+			want:   ErrorUnknown,
+		},
+		"StorageConflict": {
+			reason: "Storage errors should be categorized by their service code",
+			err:    errors.Wrap(&storageError{code: azblob.ServiceCodeContainerBeingDeleted, status: http.StatusConflict}, "cannot create"),
+			want:   ErrorConflict,
+		},
+		"StorageAuthorization": {
+			reason: "Storage errors with unrecognized codes should be categorized by their status",
+			err:    &storageError{code: "SomethingNew", status: http.StatusForbidden},
+			want:   ErrorAuthorizationFailed,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := Classify(tc.err)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nClassify(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestSetAzureError(t *testing.T) {
+	mg := &fake.Managed{}
+
+	SetAzureError(mg, nil)
+	if diff := cmp.Diff(corev1.ConditionUnknown, mg.GetCondition(TypeAzureError).Status); diff != "" {
+		t.Errorf("SetAzureError(...): resources that never saw an error should not have a condition: -want, +got:\n%s", diff)
+	}
+
+	SetAzureError(mg, errors.New("boom"))
+	if diff := cmp.Diff(corev1.ConditionUnknown, mg.GetCondition(TypeAzureError).Status); diff != "" {
+		t.Errorf("SetAzureError(...): errors not returned by Azure should not set the condition: -want, +got:\n%s", diff)
+	}
+
+	SetAzureError(mg, requestError(http.StatusConflict, "QuotaExceeded"))
+	if diff := cmp.Diff(ErrorQuotaExceeded, ErrorCategory(mg.GetCondition(TypeAzureError).Reason)); diff != "" {
+		t.Errorf("SetAzureError(...): -want, +got:\n%s", diff)
+	}
+
+	SetAzureError(mg, nil)
+	if diff := cmp.Diff(ReasonNoAzureError, mg.GetCondition(TypeAzureError).Reason); diff != "" {
+		t.Errorf("SetAzureError(...): -want, +got:\n%s", diff)
+	}
+}
+
+func TestWithRequestIDs(t *testing.T) {
+	identified := func(h http.Header) error {
+		return autorest.NewErrorWithError(&azure.RequestError{
+			DetailedError: autorest.DetailedError{StatusCode: http.StatusConflict},
+			ServiceError:  &azure.ServiceError{Code: "Conflict"},
+		}, "mysql.ServersClient", "Create", &http.Response{StatusCode: http.StatusConflict, Header: h}, "Failure responding to request")
+	}
+	headers := http.Header{}
+	headers.Set(HeaderRequestID, "request")
+	headers.Set(HeaderCorrelationRequestID, "correlation")
+
+	cases := map[string]struct {
+		reason string
+		err    error
+		want   string
+	}{
+		"Nil": {
+			reason: "A nil error should remain nil",
+		},
+		"NotAzure": {
+			reason: "Errors that were not returned by Azure should not be changed",
+			err:    errors.New("boom"),
+			want:   "boom",
+		},
+		"NoIDs": {
+			reason: "Azure errors without request IDs should not be changed",
+			err:    requestError(http.StatusConflict, "Conflict"),
+			want:   requestError(http.StatusConflict, "Conflict").Error(),
+		},
+		"IDs": {
+			reason: "The IDs of the failing request should be appended to the error message",
+			err:    errors.Wrap(identified(headers), "cannot create"),
+			want:   errors.Wrap(identified(headers), "cannot create").Error() + " (request ID: request, correlation ID: correlation)",
+		},
+		"AlreadyIdentified": {
+			reason: "The IDs should be appended only once",
+			err:    WithRequestIDs(identified(headers)),
+			want:   identified(headers).Error() + " (request ID: request, correlation ID: correlation)",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := WithRequestIDs(tc.err)
+			if tc.err == nil {
+				if got != nil {
+					t.Errorf("\n%s\nWithRequestIDs(...): want nil, got %v", tc.reason, got)
+				}
+				return
+			}
+			if diff := cmp.Diff(tc.want, got.Error()); diff != "" {
+				t.Errorf("\n%s\nWithRequestIDs(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(Classify(tc.err), Classify(got)); diff != "" {
+				t.Errorf("\n%s\nClassify(WithRequestIDs(...)): the wrapped error should be classified as before: -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestSetLastFailedRequest(t *testing.T) {
+	headers := http.Header{}
+	headers.Set(HeaderRequestID, "request")
+	headers.Set(HeaderCorrelationRequestID, "correlation")
+	identified := autorest.NewErrorWithError(&azure.RequestError{
+		DetailedError: autorest.DetailedError{StatusCode: http.StatusConflict},
+		ServiceError:  &azure.ServiceError{Code: "Conflict"},
+	}, "mysql.ServersClient", "Create", &http.Response{StatusCode: http.StatusConflict, Header: headers}, "Failure responding to request")
+	previous := &v1alpha3.FailedRequest{RequestID: "previous"}
+
+	cases := map[string]struct {
+		reason string
+		err    error
+		want   *v1alpha3.FailedRequest
+	}{
+		"Nil": {
+			reason: "A nil error should leave the previously failed request as is",
+			want:   previous,
+		},
+		"NoIDs": {
+			reason: "An error without request IDs should leave the previously failed request as is",
+			err:    requestError(http.StatusConflict, "Conflict"),
+			want:   previous,
+		},
+		"IDs": {
+			reason: "The IDs of the failing request should be recorded",
+			err:    errors.Wrap(WithRequestIDs(identified), "cannot create"),
+			want:   &v1alpha3.FailedRequest{RequestID: "request", CorrelationID: "correlation"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			rg := &v1alpha3.ResourceGroup{Status: v1alpha3.ResourceGroupStatus{LastFailedRequest: previous}}
+			SetLastFailedRequest(rg, tc.err)
+			if diff := cmp.Diff(tc.want, rg.GetLastFailedRequest(), cmpopts.IgnoreFields(v1alpha3.FailedRequest{}, "Time")); diff != "" {
+				t.Errorf("\n%s\nSetLastFailedRequest(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestClassifyErrors(t *testing.T) {
+	errQuota := requestError(http.StatusConflict, "QuotaExceeded")
+
+	type want struct {
+		reason   xpv1.ConditionReason
+		category ErrorCategory
+		events   []event.Event
+	}
+	cases := map[string]struct {
+		reason string
+		err    error
+		want   want
+	}{
+		"NotAzure": {
+			reason: "Errors that were not returned by Azure should not be reported",
+			err:    errors.New("boom"),
+		},
+		"Azure": {
+			reason: "The category of an error returned by Azure should be reported in a condition and a warning event",
+			err:    errQuota,
+			want: want{
+				reason:   xpv1.ConditionReason(ErrorQuotaExceeded),
+				category: ErrorQuotaExceeded,
+				events:   []event.Event{event.Warning(event.Reason(ErrorQuotaExceeded), errQuota)},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			mg := &fake.Managed{}
+			r := &eventRecorder{}
+			e := connect(t, &managed.ExternalClientFns{
+				ObserveFn: func(_ context.Context, _ resource.Managed) (managed.ExternalObservation, error) {
+					return managed.ExternalObservation{}, tc.err
+				},
+			}, mg, ClassifyErrors(r))
+
+			var c ErrorCategory
+			_, err := e.Observe(withErrorCategory(context.Background(), &c), mg)
+			if diff := cmp.Diff(tc.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.reason, mg.GetCondition(TypeAzureError).Reason); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want AzureError reason, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.category, c); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want recorded category, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.events, r.events, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want events, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestIdentifyFailedRequests(t *testing.T) {
+	headers := http.Header{}
+	headers.Set(HeaderRequestID, "request")
+	headers.Set(HeaderCorrelationRequestID, "correlation")
+	errIdentified := autorest.NewErrorWithError(&azure.RequestError{
+		DetailedError: autorest.DetailedError{StatusCode: http.StatusConflict},
+		ServiceError:  &azure.ServiceError{Code: "Conflict"},
+	}, "resources.GroupsClient", "CreateOrUpdate", &http.Response{StatusCode: http.StatusConflict, Header: headers}, "Failure responding to request")
+
+	mg := &v1alpha3.ResourceGroup{}
+	e := connect(t, &managed.ExternalClientFns{
+		CreateFn: func(_ context.Context, _ resource.Managed) (managed.ExternalCreation, error) {
+			return managed.ExternalCreation{}, errIdentified
+		},
+	}, mg, IdentifyFailedRequests())
+
+	_, err := e.Create(context.Background(), mg)
+	if diff := cmp.Diff(WithRequestIDs(errIdentified).Error(), err.Error()); diff != "" {
+		t.Errorf("e.Create(...): the error should include the IDs of the failing request: -want, +got:\n%s", diff)
+	}
+	want := &v1alpha3.FailedRequest{RequestID: "request", CorrelationID: "correlation"}
+	if diff := cmp.Diff(want, mg.GetLastFailedRequest(), cmpopts.IgnoreFields(v1alpha3.FailedRequest{}, "Time")); diff != "" {
+		t.Errorf("e.Create(...): -want last failed request, +got:\n%s", diff)
+	}
+}
@@ -0,0 +1,95 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azure
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	"github.com/crossplane-contrib/provider-azure/pkg/tracing"
+)
+
+// A ConnecterWrapper wraps an ExternalConnecter, and the ExternalClients it
+// returns, with additional behaviour.
+type ConnecterWrapper func(c managed.ExternalConnecter) managed.ExternalConnecter
+
+// WrapConnecter returns the supplied ExternalConnecter wrapped with the
+// supplied wrappers. The first wrapper is the outermost.
+func WrapConnecter(c managed.ExternalConnecter, w ...ConnecterWrapper) managed.ExternalConnecter {
+	for i := len(w) - 1; i >= 0; i-- {
+		c = w[i](c)
+	}
+	return c
+}
+
+// wrapExternal returns a ConnecterWrapper that wraps each ExternalClient its
+// ExternalConnecter returns using the supplied function.
+func wrapExternal(fn func(e managed.ExternalClient) managed.ExternalClient) ConnecterWrapper {
+	return func(c managed.ExternalConnecter) managed.ExternalConnecter {
+		return managed.ExternalConnectorFn(func(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
+			e, err := c.Connect(ctx, mg)
+			if err != nil {
+				return nil, err
+			}
+			return fn(e), nil
+		})
+	}
+}
+
+// NewConnecter wraps the supplied ExternalConnecter with every
+// ConnecterWrapper of this package, in the order they depend on each other.
+// Besides reporting on the calls of the ExternalClients it returns, this
+// changes what they do. They label the metrics of their requests with the
+// ProviderConfig of their managed resource (LabelMetrics) and trace their
+// calls (TraceCalls). They report the category of Azure errors as the
+// AzureError condition and as events (ClassifyErrors), and record the IDs of
+// failed requests (IdentifyFailedRequests). They report the fields an update
+// changes (ReportUpdateDiffs). They never create, update or delete the
+// external resources of observe-only managed resources (EnforceObserveOnly),
+// and plan rather than make changes to those of dry-run managed resources
+// (PlanDryRuns). Finally, they refuse to delete, and may lock, the external
+// resources of managed resources whose deletion is protected
+// (ProtectDeletion), which must wrap the connecter's own ExternalClients so
+// that it may find out whether they are Lockers.
+func NewConnecter(c managed.ExternalConnecter, r event.Recorder) managed.ExternalConnecter {
+	return WrapConnecter(c,
+		LabelMetrics(),
+		TraceCalls(),
+		ClassifyErrors(r),
+		IdentifyFailedRequests(),
+		ReportUpdateDiffs(r),
+		EnforceObserveOnly(r),
+		PlanDryRuns(r),
+		ProtectDeletion(r),
+	)
+}
+
+// NewReconciler returns a managed reconciler of the supplied kind of managed
+// resource, configured with the supplied options, wrapped with every
+// Reconciler wrapper of this package. Each reconcile is traced
+// (tracing.NewReconciler) and requeued without backoff if it failed with a
+// non-retryable Azure error (NewRequeueReconciler).
+func NewReconciler(m manager.Manager, name string, gvk schema.GroupVersionKind, o ...managed.ReconcilerOption) reconcile.Reconciler {
+	return tracing.NewReconciler(name, NewRequeueReconciler(managed.NewReconciler(m, resource.ManagedKind(gvk), o...)))
+}
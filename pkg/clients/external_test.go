@@ -0,0 +1,87 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azure
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+)
+
+type eventRecorder struct {
+	events []event.Event
+}
+
+func (r *eventRecorder) Event(_ runtime.Object, e event.Event) {
+	r.events = append(r.events, e)
+}
+
+func (r *eventRecorder) WithAnnotations(_ ...string) event.Recorder {
+	return r
+}
+
+// connect the supplied ExternalClient, wrapped with the supplied wrappers, to
+// the supplied managed resource.
+func connect(t *testing.T, e managed.ExternalClient, mg resource.Managed, w ...ConnecterWrapper) managed.ExternalClient {
+	t.Helper()
+	c := WrapConnecter(managed.ExternalConnectorFn(func(_ context.Context, _ resource.Managed) (managed.ExternalClient, error) {
+		return e, nil
+	}), w...)
+	ext, err := c.Connect(context.Background(), mg)
+	if err != nil {
+		t.Fatalf("Connect(...): %s", err)
+	}
+	return ext
+}
+
+// calling returns a ConnecterWrapper that appends the supplied name to the
+// supplied calls whenever it observes an external resource.
+func calling(name string, calls *[]string) ConnecterWrapper {
+	return wrapExternal(func(e managed.ExternalClient) managed.ExternalClient {
+		return &managed.ExternalClientFns{
+			ObserveFn: func(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+				*calls = append(*calls, name)
+				return e.Observe(ctx, mg)
+			},
+		}
+	})
+}
+
+func TestWrapConnecter(t *testing.T) {
+	var calls []string
+	mg := &fake.Managed{}
+	e := connect(t, &managed.ExternalClientFns{
+		ObserveFn: func(_ context.Context, _ resource.Managed) (managed.ExternalObservation, error) {
+			calls = append(calls, "client")
+			return managed.ExternalObservation{}, nil
+		},
+	}, mg, calling("outer", &calls), calling("inner", &calls))
+
+	if _, err := e.Observe(context.Background(), mg); err != nil {
+		t.Fatalf("e.Observe(...): %s", err)
+	}
+	if diff := cmp.Diff([]string{"outer", "inner", "client"}, calls); diff != "" {
+		t.Errorf("e.Observe(...): the first wrapper should be the outermost: -want calls, +got:\n%s", diff)
+	}
+}
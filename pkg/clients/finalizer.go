@@ -0,0 +1,84 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azure
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+)
+
+const errUpdateFinalizers = "cannot update finalizers"
+
+// A migratingFinalizer manages the finalizer of the managed reconciler, and
+// removes the finalizers that a controller added before it used the managed
+// reconciler.
+type migratingFinalizer struct {
+	client client.Client
+	legacy []string
+}
+
+// NewMigratingFinalizer returns a finalizer for the managed reconciler of a
+// controller that used to add the supplied legacy finalizers. Nothing removes
+// them otherwise, so resources that carry them could never be deleted.
+func NewMigratingFinalizer(c client.Client, legacy ...string) resource.Finalizer {
+	return &migratingFinalizer{client: c, legacy: legacy}
+}
+
+// AddFinalizer replaces any legacy finalizers of the supplied object with the
+// finalizer of the managed reconciler.
+func (f *migratingFinalizer) AddFinalizer(ctx context.Context, obj resource.Object) error {
+	changed := f.removeLegacy(obj)
+	if !meta.FinalizerExists(obj, managed.FinalizerName) {
+		meta.AddFinalizer(obj, managed.FinalizerName)
+		changed = true
+	}
+	if !changed {
+		return nil
+	}
+	return errors.Wrap(f.client.Update(ctx, obj), errUpdateFinalizers)
+}
+
+// RemoveFinalizer removes any legacy finalizers and the finalizer of the
+// managed reconciler from the supplied object.
+func (f *migratingFinalizer) RemoveFinalizer(ctx context.Context, obj resource.Object) error {
+	changed := f.removeLegacy(obj)
+	if meta.FinalizerExists(obj, managed.FinalizerName) {
+		meta.RemoveFinalizer(obj, managed.FinalizerName)
+		changed = true
+	}
+	if !changed {
+		return nil
+	}
+	return errors.Wrap(resource.IgnoreNotFound(f.client.Update(ctx, obj)), errUpdateFinalizers)
+}
+
+func (f *migratingFinalizer) removeLegacy(obj resource.Object) bool {
+	removed := false
+	for _, l := range f.legacy {
+		if meta.FinalizerExists(obj, l) {
+			meta.RemoveFinalizer(obj, l)
+			removed = true
+		}
+	}
+	return removed
+}
@@ -0,0 +1,136 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azure
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func TestMigratingFinalizer(t *testing.T) {
+	errBoom := errors.New("boom")
+	legacy := "finalizer.cool.azure.crossplane.io"
+
+	withFinalizers := func(f ...string) *fake.Managed {
+		mg := &fake.Managed{}
+		mg.SetFinalizers(f)
+		return mg
+	}
+
+	type args struct {
+		kube client.Client
+		mg   *fake.Managed
+	}
+	type want struct {
+		finalizers []string
+		err        error
+	}
+	cases := map[string]struct {
+		reason string
+		remove bool
+		args   args
+		want   want
+	}{
+		"AddReplacesLegacy": {
+			reason: "Adding the finalizer should replace a legacy finalizer",
+			args: args{
+				kube: &test.MockClient{MockUpdate: test.NewMockUpdateFn(nil)},
+				mg:   withFinalizers(legacy),
+			},
+			want: want{finalizers: []string{managed.FinalizerName}},
+		},
+		"AddUnchanged": {
+			reason: "Adding a finalizer that exists should not update the object",
+			args: args{
+				kube: &test.MockClient{MockUpdate: test.NewMockUpdateFn(errBoom)},
+				mg:   withFinalizers(managed.FinalizerName),
+			},
+			want: want{finalizers: []string{managed.FinalizerName}},
+		},
+		"AddError": {
+			reason: "Errors updating the object should be returned",
+			args: args{
+				kube: &test.MockClient{MockUpdate: test.NewMockUpdateFn(errBoom)},
+				mg:   withFinalizers(),
+			},
+			want: want{finalizers: []string{managed.FinalizerName}, err: errors.Wrap(errBoom, errUpdateFinalizers)},
+		},
+		"RemoveLegacyOnly": {
+			reason: "Removing the finalizer should remove a legacy finalizer from an object that lacks the managed finalizer",
+			remove: true,
+			args: args{
+				kube: &test.MockClient{MockUpdate: test.NewMockUpdateFn(nil)},
+				mg:   withFinalizers("other", legacy),
+			},
+			want: want{finalizers: []string{"other"}},
+		},
+		"RemoveBoth": {
+			reason: "Removing the finalizer should remove both the legacy and the managed finalizer",
+			remove: true,
+			args: args{
+				kube: &test.MockClient{MockUpdate: test.NewMockUpdateFn(nil)},
+				mg:   withFinalizers(legacy, managed.FinalizerName),
+			},
+			want: want{finalizers: []string{}},
+		},
+		"RemoveNotFound": {
+			reason: "An object that was deleted once its finalizers were removed should not be an error",
+			remove: true,
+			args: args{
+				kube: &test.MockClient{MockUpdate: test.NewMockUpdateFn(kerrors.NewNotFound(schema.GroupResource{}, "cool"))},
+				mg:   withFinalizers(managed.FinalizerName),
+			},
+			want: want{finalizers: []string{}},
+		},
+		"RemoveUnchanged": {
+			reason: "Removing finalizers that do not exist should not update the object",
+			remove: true,
+			args: args{
+				kube: &test.MockClient{MockUpdate: test.NewMockUpdateFn(errBoom)},
+				mg:   withFinalizers("other"),
+			},
+			want: want{finalizers: []string{"other"}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			f := NewMigratingFinalizer(tc.args.kube, legacy)
+			fn := f.AddFinalizer
+			if tc.remove {
+				fn = f.RemoveFinalizer
+			}
+			err := fn(context.Background(), tc.args.mg)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nFinalizer: -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.finalizers, tc.args.mg.GetFinalizers()); diff != "" {
+				t.Errorf("\n%s\nFinalizer: -want finalizers, +got finalizers:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,211 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azure
+
+import (
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/adal"
+	"github.com/pkg/errors"
+)
+
+const (
+	// clientAssertionType is the OAuth client assertion type used when a
+	// federated token is presented in place of a client secret.
+	clientAssertionType = "urn:ietf:params:oauth:client-assertion-type:jwt-bearer"
+)
+
+// Error strings.
+const (
+	errNewOAuthConfig           = "cannot create OAuth configuration"
+	errNewSPT                   = "cannot create service principal token"
+	errNewMSIToken              = "cannot create managed identity token"
+	errReadFederatedToken       = "cannot read federated token file"
+	errEmptyFederatedToken      = "federated token file is empty"
+	errMissingOIDCIdentity      = "clientID and tenantID must be set when the credentials source is OIDCTokenFile"
+	errMissingMISubscription    = "subscriptionID must be set when the credentials source is ManagedIdentity"
+	errAmbiguousManagedIdentity = "only one of clientID and managedIdentityResourceID can be set"
+	errDecodeClientCertificate  = "cannot decode client certificate"
+	errParseCertificate         = "cannot parse PEM encoded certificate"
+	errParsePrivateKey          = "cannot parse PEM encoded private key"
+	errNoCertificate            = "no certificate found in PEM data"
+	errNoPrivateKey             = "no private key found in PEM data"
+	errNotRSAPrivateKey         = "private key is not an RSA key"
+)
+
+// NewServicePrincipalToken returns a token for the supplied resource, e.g. the
+// Azure Resource Manager or Azure AD Graph endpoint, that authenticates using
+// the identity described by the supplied credentials. The returned token is
+// refreshed automatically when it is about to expire.
+func NewServicePrincipalToken(creds map[string]string, resource string) (*adal.ServicePrincipalToken, error) {
+	if creds[CredentialsKeyUseManagedIdentity] == "true" {
+		t, err := adal.NewServicePrincipalTokenFromManagedIdentity(resource, &adal.ManagedIdentityOptions{
+			ClientID:           creds[CredentialsKeyClientID],
+			IdentityResourceID: creds[CredentialsKeyManagedIdentityResourceID],
+		})
+		return t, errors.Wrap(err, errNewMSIToken)
+	}
+	cfg, err := adal.NewOAuthConfig(creds[CredentialsKeyActiveDirectoryEndpointURL], creds[CredentialsKeyTenantID])
+	if err != nil {
+		return nil, errors.Wrap(err, errNewOAuthConfig)
+	}
+	var t *adal.ServicePrincipalToken
+	switch {
+	case creds[CredentialsKeyFederatedTokenFile] != "":
+		t, err = adal.NewServicePrincipalTokenWithSecret(*cfg, creds[CredentialsKeyClientID], resource, &federatedTokenSecret{path: creds[CredentialsKeyFederatedTokenFile]})
+	case creds[CredentialsKeyClientCertificate] != "":
+		cert, key, derr := decodeClientCertificate(creds[CredentialsKeyClientCertificate], creds[CredentialsKeyClientCertificatePassword])
+		if derr != nil {
+			return nil, errors.Wrap(derr, errDecodeClientCertificate)
+		}
+		t, err = adal.NewServicePrincipalTokenFromCertificate(*cfg, creds[CredentialsKeyClientID], cert, key, resource)
+	default:
+		t, err = adal.NewServicePrincipalToken(*cfg, creds[CredentialsKeyClientID], creds[CredentialsKeyClientSecret], resource)
+	}
+	return t, errors.Wrap(err, errNewSPT)
+}
+
+// NewAuthorizer returns an authorizer for the Azure Resource Manager endpoint
+// of the supplied credentials.
+func NewAuthorizer(creds map[string]string) (autorest.Authorizer, error) {
+	return NewResourceAuthorizer(creds, creds[CredentialsKeyResourceManagerEndpointURL])
+}
+
+// NewResourceAuthorizer returns an authorizer for the supplied resource, e.g.
+// the Key Vault data plane or Azure AD Graph endpoint of the cloud described by
+// the supplied credentials.
+func NewResourceAuthorizer(creds map[string]string, resource string) (autorest.Authorizer, error) {
+	t, err := NewServicePrincipalToken(creds, resource)
+	if err != nil {
+		return nil, err
+	}
+	return autorest.NewBearerAuthorizer(t), nil
+}
+
+// TokenTenantID returns the ID of the Azure AD tenant that issued the supplied
+// access token, or an empty string if it cannot be determined. The token is
+// not verified.
+func TokenTenantID(accessToken string) string {
+	parts := strings.Split(accessToken, ".")
+	if len(parts) != 3 {
+		return ""
+	}
+	b, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return ""
+	}
+	claims := struct {
+		TenantID string `json:"tid"`
+	}{}
+	if err := json.Unmarshal(b, &claims); err != nil {
+		return ""
+	}
+	return claims.TenantID
+}
+
+// A federatedTokenSecret authenticates a service principal token request by
+// presenting a projected Kubernetes service account token as the client
+// assertion of an Azure AD federated identity credential. The token file is
+// read every time a new Azure AD token is requested, since the kubelet rotates
+// it well before it expires.
+type federatedTokenSecret struct {
+	path string
+}
+
+// SetAuthenticationValues populates the form submitted during OAuth token
+// acquisition with the content of the federated token file.
+func (s *federatedTokenSecret) SetAuthenticationValues(_ *adal.ServicePrincipalToken, v *url.Values) error {
+	b, err := os.ReadFile(s.path)
+	if err != nil {
+		return errors.Wrap(err, errReadFederatedToken)
+	}
+	assertion := strings.TrimSpace(string(b))
+	if assertion == "" {
+		return errors.New(errEmptyFederatedToken)
+	}
+	v.Set("client_assertion", assertion)
+	v.Set("client_assertion_type", clientAssertionType)
+	return nil
+}
+
+// decodeClientCertificate returns the certificate and RSA private key encoded
+// in the supplied data. The data may either be PEM encoded, containing both the
+// certificate and an unencrypted private key, or a base64 encoded PKCS#12 (PFX)
+// archive protected by the supplied password.
+func decodeClientCertificate(data, password string) (*x509.Certificate, *rsa.PrivateKey, error) {
+	if !strings.Contains(data, "-----BEGIN") {
+		pfx, err := base64.StdEncoding.DecodeString(strings.TrimSpace(data))
+		if err != nil {
+			return nil, nil, err
+		}
+		return adal.DecodePfxCertificateData(pfx, password)
+	}
+
+	var cert *x509.Certificate
+	var key *rsa.PrivateKey
+	rest := []byte(data)
+	for {
+		var b *pem.Block
+		b, rest = pem.Decode(rest)
+		if b == nil {
+			break
+		}
+		switch b.Type {
+		case "CERTIFICATE":
+			// The first certificate is the leaf; any others form its chain.
+			if cert != nil {
+				continue
+			}
+			c, err := x509.ParseCertificate(b.Bytes)
+			if err != nil {
+				return nil, nil, errors.Wrap(err, errParseCertificate)
+			}
+			cert = c
+		case "RSA PRIVATE KEY":
+			k, err := x509.ParsePKCS1PrivateKey(b.Bytes)
+			if err != nil {
+				return nil, nil, errors.Wrap(err, errParsePrivateKey)
+			}
+			key = k
+		case "PRIVATE KEY":
+			k, err := x509.ParsePKCS8PrivateKey(b.Bytes)
+			if err != nil {
+				return nil, nil, errors.Wrap(err, errParsePrivateKey)
+			}
+			rk, ok := k.(*rsa.PrivateKey)
+			if !ok {
+				return nil, nil, errors.New(errNotRSAPrivateKey)
+			}
+			key = rk
+		}
+	}
+	if cert == nil {
+		return nil, nil, errors.New(errNoCertificate)
+	}
+	if key == nil {
+		return nil, nil, errors.New(errNoPrivateKey)
+	}
+	return cert, key, nil
+}
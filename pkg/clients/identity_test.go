@@ -0,0 +1,443 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azure
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Azure/go-autorest/autorest/azure"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane-contrib/provider-azure/apis/v1beta1"
+)
+
+const (
+	testClientID       = "0f32e96b-b9a4-49ce-a857-243a33b20e5c"
+	testTenantID       = "302de427-dba9-4452-8583-a4268e46de6b"
+	testSubscriptionID = "bf1b0e59-93da-42e0-82c6-5a1d94227911"
+	testResource       = "https://management.azure.com/"
+)
+
+// A tokenServer is a stand-in for the Azure AD token endpoint. It records the
+// client assertions it receives and issues tokens that expire immediately so
+// that every use of the token triggers a refresh.
+type tokenServer struct {
+	mu         sync.Mutex
+	assertions []string
+	issued     int
+}
+
+func (s *tokenServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != fmt.Sprintf("/%s/oauth2/token", testTenantID) {
+		http.Error(w, "unknown tenant", http.StatusNotFound)
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if r.PostForm.Get("client_assertion_type") != clientAssertionType || r.PostForm.Get("client_id") != testClientID {
+		http.Error(w, `{"error":"invalid_client"}`, http.StatusUnauthorized)
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.assertions = append(s.assertions, r.PostForm.Get("client_assertion"))
+	s.issued++
+	w.Header().Set("Content-Type", "application/json")
+	fmt.Fprintf(w, `{"access_token":"token-%d","token_type":"Bearer","expires_in":"0","expires_on":"0","resource":%q}`, s.issued, r.PostForm.Get("resource"))
+}
+
+func TestFederatedServicePrincipalToken(t *testing.T) {
+	ts := &tokenServer{}
+	srv := httptest.NewServer(ts)
+	defer srv.Close()
+
+	path := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(path, []byte("first-projected-token\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	creds := map[string]string{
+		CredentialsKeyClientID:                   testClientID,
+		CredentialsKeyTenantID:                   testTenantID,
+		CredentialsKeyActiveDirectoryEndpointURL: srv.URL + "/",
+		CredentialsKeyFederatedTokenFile:         path,
+	}
+	spt, err := NewServicePrincipalToken(creds, testResource)
+	if err != nil {
+		t.Fatalf("NewServicePrincipalToken(...): %s", err)
+	}
+	if err := spt.EnsureFresh(); err != nil {
+		t.Fatalf("EnsureFresh(...): %s", err)
+	}
+	if diff := cmp.Diff("token-1", spt.OAuthToken()); diff != "" {
+		t.Errorf("OAuthToken(): -want, +got:\n%s", diff)
+	}
+
+	// The kubelet rotates the projected token; the next refresh must present
+	// the new one.
+	if err := os.WriteFile(path, []byte("second-projected-token"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := spt.EnsureFresh(); err != nil {
+		t.Fatalf("EnsureFresh(...): %s", err)
+	}
+	if diff := cmp.Diff("token-2", spt.OAuthToken()); diff != "" {
+		t.Errorf("OAuthToken(): -want, +got:\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"first-projected-token", "second-projected-token"}, ts.assertions); diff != "" {
+		t.Errorf("client assertions: -want, +got:\n%s", diff)
+	}
+}
+
+func TestFederatedServicePrincipalTokenMissingFile(t *testing.T) {
+	srv := httptest.NewServer(&tokenServer{})
+	defer srv.Close()
+
+	creds := map[string]string{
+		CredentialsKeyClientID:                   testClientID,
+		CredentialsKeyTenantID:                   testTenantID,
+		CredentialsKeyActiveDirectoryEndpointURL: srv.URL + "/",
+		CredentialsKeyFederatedTokenFile:         filepath.Join(t.TempDir(), "missing"),
+	}
+	spt, err := NewServicePrincipalToken(creds, testResource)
+	if err != nil {
+		t.Fatalf("NewServicePrincipalToken(...): %s", err)
+	}
+	if err := spt.Refresh(); err == nil {
+		t.Errorf("Refresh(...): expected an error when the token file does not exist")
+	}
+}
+
+func TestManagedIdentityServicePrincipalToken(t *testing.T) {
+	var got url.Values
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Metadata") != "true" {
+			http.Error(w, "missing metadata header", http.StatusBadRequest)
+			return
+		}
+		if err := r.ParseForm(); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		got = r.PostForm
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"access_token":"msi-token","token_type":"Bearer","expires_in":"3599","expires_on":"%d","resource":%q}`, time.Now().Add(time.Hour).Unix(), r.PostForm.Get("resource"))
+	}))
+	defer srv.Close()
+
+	// Setting MSI_ENDPOINT makes the token library talk to our stand-in
+	// rather than probing the instance metadata service.
+	t.Setenv("MSI_ENDPOINT", srv.URL)
+
+	creds := map[string]string{
+		CredentialsKeyUseManagedIdentity: "true",
+		CredentialsKeyClientID:           testClientID,
+	}
+	spt, err := NewServicePrincipalToken(creds, testResource)
+	if err != nil {
+		t.Fatalf("NewServicePrincipalToken(...): %s", err)
+	}
+	if err := spt.EnsureFresh(); err != nil {
+		t.Fatalf("EnsureFresh(...): %s", err)
+	}
+	if diff := cmp.Diff("msi-token", spt.OAuthToken()); diff != "" {
+		t.Errorf("OAuthToken(): -want, +got:\n%s", diff)
+	}
+	want := url.Values{"resource": []string{testResource}, "client_id": []string{testClientID}}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("token request: -want, +got:\n%s", diff)
+	}
+}
+
+// testCertificate returns a self-signed certificate and its RSA private key,
+// PEM encoded. The key is PKCS#8 encoded if pkcs8 is true.
+func testCertificate(t *testing.T, pkcs8 bool) (string, string) {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "crossplane"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	kb := &pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}
+	if pkcs8 {
+		b, err := x509.MarshalPKCS8PrivateKey(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		kb = &pem.Block{Type: "PRIVATE KEY", Bytes: b}
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})), string(pem.EncodeToMemory(kb))
+}
+
+func TestCertificateServicePrincipalToken(t *testing.T) {
+	ts := &tokenServer{}
+	srv := httptest.NewServer(ts)
+	defer srv.Close()
+
+	cert, key := testCertificate(t, false)
+	creds := map[string]string{
+		CredentialsKeyClientID:                   testClientID,
+		CredentialsKeyTenantID:                   testTenantID,
+		CredentialsKeyActiveDirectoryEndpointURL: srv.URL + "/",
+		CredentialsKeyClientCertificate:          cert + key,
+	}
+	spt, err := NewServicePrincipalToken(creds, testResource)
+	if err != nil {
+		t.Fatalf("NewServicePrincipalToken(...): %s", err)
+	}
+	if err := spt.Refresh(); err != nil {
+		t.Fatalf("Refresh(...): %s", err)
+	}
+	if diff := cmp.Diff("token-1", spt.OAuthToken()); diff != "" {
+		t.Errorf("OAuthToken(): -want, +got:\n%s", diff)
+	}
+	// The client assertion must be a signed JWT rather than a client secret.
+	if len(ts.assertions) != 1 || len(strings.Split(ts.assertions[0], ".")) != 3 {
+		t.Errorf("client assertions: want a single JWT, got %v", ts.assertions)
+	}
+}
+
+func TestDecodeClientCertificate(t *testing.T) {
+	cert, pkcs1 := testCertificate(t, false)
+	_, pkcs8 := testCertificate(t, true)
+
+	cases := map[string]struct {
+		reason string
+		data   string
+		err    error
+	}{
+		"PKCS1": {
+			reason: "A PEM encoded certificate and PKCS#1 private key should be decoded",
+			data:   cert + pkcs1,
+		},
+		"PKCS8": {
+			reason: "A PEM encoded certificate and PKCS#8 private key should be decoded",
+			data:   pkcs8 + cert,
+		},
+		"NoPrivateKey": {
+			reason: "PEM data without a private key should be rejected",
+			data:   cert,
+			err:    errors.New(errNoPrivateKey),
+		},
+		"NoCertificate": {
+			reason: "PEM data without a certificate should be rejected",
+			data:   pkcs1,
+			err:    errors.New(errNoCertificate),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			c, k, err := decodeClientCertificate(tc.data, "")
+			if diff := cmp.Diff(tc.err, err, test.EquateErrors()); diff != "" {
+				t.Fatalf("\n%s\ndecodeClientCertificate(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if tc.err == nil && (c == nil || k == nil) {
+				t.Errorf("\n%s\ndecodeClientCertificate(...): want certificate and key, got %v, %v", tc.reason, c, k)
+			}
+		})
+	}
+
+	if _, _, err := decodeClientCertificate("not-base64!", ""); err == nil {
+		t.Errorf("decodeClientCertificate(...): expected an error for data that is neither PEM nor base64 PFX")
+	}
+}
+
+func TestProviderConfigCredentials(t *testing.T) {
+	clientID := testClientID
+	tenantID := testTenantID
+	subscriptionID := testSubscriptionID
+	resourceID := "/subscriptions/sub/resourceGroups/rg/providers/Microsoft.ManagedIdentity/userAssignedIdentities/id"
+	path := "/tmp/token"
+
+	type want struct {
+		creds map[string]string
+		err   error
+	}
+	cases := map[string]struct {
+		pc   *v1beta1.ProviderConfig
+		want want
+	}{
+		"OIDCTokenFileDefaultPath": {
+			pc: &v1beta1.ProviderConfig{
+				Spec: v1beta1.ProviderConfigSpec{
+					Credentials:    v1beta1.ProviderCredentials{Source: v1beta1.CredentialsSourceOIDCTokenFile},
+					ClientID:       &clientID,
+					TenantID:       &tenantID,
+					SubscriptionID: &subscriptionID,
+				},
+			},
+			want: want{
+				creds: map[string]string{
+					CredentialsKeyClientID:                       clientID,
+					CredentialsKeyTenantID:                       tenantID,
+					CredentialsKeySubscriptionID:                 subscriptionID,
+					CredentialsKeyFederatedTokenFile:             v1beta1.DefaultOIDCTokenFilePath,
+					CredentialsKeyActiveDirectoryEndpointURL:     azure.PublicCloud.ActiveDirectoryEndpoint,
+					CredentialsKeyResourceManagerEndpointURL:     azure.PublicCloud.ResourceManagerEndpoint,
+					CredentialsKeyActiveDirectoryGraphResourceID: azure.PublicCloud.GraphEndpoint,
+					CredentialsKeyKeyVaultResourceID:             azure.PublicCloud.ResourceIdentifiers.KeyVault,
+					CredentialsKeyKeyVaultDNSSuffix:              azure.PublicCloud.KeyVaultDNSSuffix,
+					CredentialsKeyStorageEndpointSuffix:          azure.PublicCloud.StorageEndpointSuffix,
+				},
+			},
+		},
+		"OIDCTokenFileCustomPath": {
+			pc: &v1beta1.ProviderConfig{
+				Spec: v1beta1.ProviderConfigSpec{
+					Credentials: v1beta1.ProviderCredentials{
+						Source:            v1beta1.CredentialsSourceOIDCTokenFile,
+						OIDCTokenFilePath: &path,
+					},
+					ClientID: &clientID,
+					TenantID: &tenantID,
+				},
+			},
+			want: want{
+				creds: map[string]string{
+					CredentialsKeyClientID:                       clientID,
+					CredentialsKeyTenantID:                       tenantID,
+					CredentialsKeyFederatedTokenFile:             path,
+					CredentialsKeyActiveDirectoryEndpointURL:     azure.PublicCloud.ActiveDirectoryEndpoint,
+					CredentialsKeyResourceManagerEndpointURL:     azure.PublicCloud.ResourceManagerEndpoint,
+					CredentialsKeyActiveDirectoryGraphResourceID: azure.PublicCloud.GraphEndpoint,
+					CredentialsKeyKeyVaultResourceID:             azure.PublicCloud.ResourceIdentifiers.KeyVault,
+					CredentialsKeyKeyVaultDNSSuffix:              azure.PublicCloud.KeyVaultDNSSuffix,
+					CredentialsKeyStorageEndpointSuffix:          azure.PublicCloud.StorageEndpointSuffix,
+				},
+			},
+		},
+		"OIDCTokenFileMissingIdentity": {
+			pc: &v1beta1.ProviderConfig{
+				Spec: v1beta1.ProviderConfigSpec{
+					Credentials: v1beta1.ProviderCredentials{Source: v1beta1.CredentialsSourceOIDCTokenFile},
+					ClientID:    &clientID,
+				},
+			},
+			want: want{
+				err: errors.New(errMissingOIDCIdentity),
+			},
+		},
+		"ManagedIdentitySystemAssigned": {
+			pc: &v1beta1.ProviderConfig{
+				Spec: v1beta1.ProviderConfigSpec{
+					Credentials:    v1beta1.ProviderCredentials{Source: v1beta1.CredentialsSourceManagedIdentity},
+					SubscriptionID: &subscriptionID,
+				},
+			},
+			want: want{
+				creds: map[string]string{
+					CredentialsKeyUseManagedIdentity:             "true",
+					CredentialsKeySubscriptionID:                 subscriptionID,
+					CredentialsKeyActiveDirectoryEndpointURL:     azure.PublicCloud.ActiveDirectoryEndpoint,
+					CredentialsKeyResourceManagerEndpointURL:     azure.PublicCloud.ResourceManagerEndpoint,
+					CredentialsKeyActiveDirectoryGraphResourceID: azure.PublicCloud.GraphEndpoint,
+					CredentialsKeyKeyVaultResourceID:             azure.PublicCloud.ResourceIdentifiers.KeyVault,
+					CredentialsKeyKeyVaultDNSSuffix:              azure.PublicCloud.KeyVaultDNSSuffix,
+					CredentialsKeyStorageEndpointSuffix:          azure.PublicCloud.StorageEndpointSuffix,
+				},
+			},
+		},
+		"ManagedIdentityUserAssignedByResourceID": {
+			pc: &v1beta1.ProviderConfig{
+				Spec: v1beta1.ProviderConfigSpec{
+					Credentials:               v1beta1.ProviderCredentials{Source: v1beta1.CredentialsSourceManagedIdentity},
+					SubscriptionID:            &subscriptionID,
+					ManagedIdentityResourceID: &resourceID,
+				},
+			},
+			want: want{
+				creds: map[string]string{
+					CredentialsKeyUseManagedIdentity:             "true",
+					CredentialsKeyManagedIdentityResourceID:      resourceID,
+					CredentialsKeySubscriptionID:                 subscriptionID,
+					CredentialsKeyActiveDirectoryEndpointURL:     azure.PublicCloud.ActiveDirectoryEndpoint,
+					CredentialsKeyResourceManagerEndpointURL:     azure.PublicCloud.ResourceManagerEndpoint,
+					CredentialsKeyActiveDirectoryGraphResourceID: azure.PublicCloud.GraphEndpoint,
+					CredentialsKeyKeyVaultResourceID:             azure.PublicCloud.ResourceIdentifiers.KeyVault,
+					CredentialsKeyKeyVaultDNSSuffix:              azure.PublicCloud.KeyVaultDNSSuffix,
+					CredentialsKeyStorageEndpointSuffix:          azure.PublicCloud.StorageEndpointSuffix,
+				},
+			},
+		},
+		"ManagedIdentityMissingSubscription": {
+			pc: &v1beta1.ProviderConfig{
+				Spec: v1beta1.ProviderConfigSpec{
+					Credentials: v1beta1.ProviderCredentials{Source: v1beta1.CredentialsSourceManagedIdentity},
+				},
+			},
+			want: want{
+				err: errors.New(errMissingMISubscription),
+			},
+		},
+		"ManagedIdentityAmbiguous": {
+			pc: &v1beta1.ProviderConfig{
+				Spec: v1beta1.ProviderConfigSpec{
+					Credentials:               v1beta1.ProviderCredentials{Source: v1beta1.CredentialsSourceManagedIdentity},
+					SubscriptionID:            &subscriptionID,
+					ClientID:                  &clientID,
+					ManagedIdentityResourceID: &resourceID,
+				},
+			},
+			want: want{
+				err: errors.New(errAmbiguousManagedIdentity),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := ProviderConfigCredentials(context.Background(), &test.MockClient{}, tc.pc)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("ProviderConfigCredentials(...): -want error, +got error:\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want.creds, got); diff != "" {
+				t.Errorf("ProviderConfigCredentials(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
@@ -64,29 +64,33 @@ func unixTimeComparer() cmp.Option {
 	})
 }
 
-// IsUpToDate checks whether SecretBundle is configured with given KeyVaultSecretParameters.
-func IsUpToDate(ctx context.Context, client client.Client, spec v1alpha1.KeyVaultSecretParameters, observed *keyvault.SecretBundle) (bool, error) {
+// IsUpToDate returns the fields in which SecretBundle differs from the given
+// KeyVaultSecretParameters and default tags. The secret's value is redacted.
+func IsUpToDate(ctx context.Context, client client.Client, spec v1alpha1.KeyVaultSecretParameters, observed *keyvault.SecretBundle, defaultTags map[string]string) (azure.Diff, error) {
 	// Add unixTimeCopier to copystructure to copy date.UnixTime correctly
 	copystructure.Copiers[reflect.TypeOf(date.UnixTime{})] = unixTimeCopier
 
 	generated, err := copystructure.Copy(observed)
 	if err != nil {
-		return true, errors.Wrap(err, errCheckUpToDate)
+		return nil, errors.Wrap(err, errCheckUpToDate)
 	}
 	clone, ok := generated.(*keyvault.SecretBundle)
 	if !ok {
-		return true, errors.New(errCheckUpToDate)
+		return nil, errors.New(errCheckUpToDate)
 	}
 	val, err := ExtractSecretValue(ctx, client, &spec.Value)
 	if err != nil {
-		return true, err
+		return nil, err
 	}
 
-	desired := overrideParameters(spec, *clone, val)
+	desired := overrideParameters(spec, *clone, val, defaultTags)
+	current := *observed
+	current.Tags = azure.ToStringPtrMap(azure.ObservedTags(observed.Tags))
 
-	return cmp.Equal(
+	return azure.DiffObjects(
 		desired,
-		*observed,
+		current,
+		[]string{"Value"},
 		cmpopts.IgnoreFields(keyvault.SecretBundle{}, "Response"),
 		unixTimeComparer(),
 	), nil
@@ -115,8 +119,8 @@ func ExtractSecretValue(ctx context.Context, client client.Client, value *xpv1.S
 
 // LateInitialize fills the spec values that user did not fill with their
 // corresponding value in the Azure, if there is any.
-func LateInitialize(spec *v1alpha1.KeyVaultSecretParameters, az keyvault.SecretBundle) {
-	spec.Tags = azure.LateInitializeStringMap(spec.Tags, az.Tags)
+func LateInitialize(spec *v1alpha1.KeyVaultSecretParameters, az keyvault.SecretBundle, defaultTags map[string]string) {
+	spec.Tags = azure.LateInitializeTags(spec.Tags, az.Tags, defaultTags)
 	spec.ContentType = azure.LateInitializeStringPtrFromPtr(spec.ContentType, az.ContentType)
 	spec.SecretAttributes = lateInitializeSecretAttributes(spec.SecretAttributes, az.Attributes)
 }
@@ -156,22 +160,14 @@ func metav1TimeToUnixTime(t *metav1.Time) *date.UnixTime {
 	return (*date.UnixTime)(&t.Time)
 }
 
-func overrideParameters(sp v1alpha1.KeyVaultSecretParameters, sb keyvault.SecretBundle, val string) keyvault.SecretBundle {
+func overrideParameters(sp v1alpha1.KeyVaultSecretParameters, sb keyvault.SecretBundle, val string, defaultTags map[string]string) keyvault.SecretBundle {
 	sb.Value = azure.ToStringPtr(val)
 
 	if sp.ContentType != nil {
 		sb.ContentType = sp.ContentType
 	}
 
-	if sp.Tags != nil {
-		if sb.Tags == nil {
-			sb.Tags = azure.ToStringPtrMap(sp.Tags)
-		} else {
-			for k, v := range sp.Tags {
-				sb.Tags[k] = azure.ToStringPtr(v)
-			}
-		}
-	}
+	sb.Tags = azure.ToStringPtrMap(azure.DesiredTags(defaultTags, sp.Tags))
 
 	if sp.SecretAttributes != nil {
 		attr := GenerateAttributes(sp.SecretAttributes)
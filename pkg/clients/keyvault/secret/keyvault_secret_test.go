@@ -148,8 +148,9 @@ func TestGenerateAttributes(t *testing.T) {
 
 func TestLateInitialize(t *testing.T) {
 	type args struct {
-		az   keyvault.SecretBundle
-		spec *v1alpha1.KeyVaultSecretParameters
+		az          keyvault.SecretBundle
+		spec        *v1alpha1.KeyVaultSecretParameters
+		defaultTags map[string]string
 	}
 	cases := map[string]struct {
 		args
@@ -209,11 +210,27 @@ func TestLateInitialize(t *testing.T) {
 				},
 			},
 		},
+		"Must not initialize ownership or default tags": {
+			args: args{
+				spec: &v1alpha1.KeyVaultSecretParameters{},
+				az: keyvault.SecretBundle{
+					Tags: azure.ToStringPtrMap(map[string]string{
+						"created_by":     "crossplane",
+						"team":           "default",
+						azure.TagKeyName: "cool-secret",
+					}),
+				},
+				defaultTags: map[string]string{"team": "default"},
+			},
+			want: &v1alpha1.KeyVaultSecretParameters{
+				Tags: tags,
+			},
+		},
 	}
 
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
-			LateInitialize(tc.args.spec, tc.args.az)
+			LateInitialize(tc.args.spec, tc.args.az, tc.args.defaultTags)
 			if diff := cmp.Diff(tc.args.spec, tc.want); diff != "" {
 				t.Errorf("LateInitialize(...): -want, +got:\n%s", diff)
 			}
@@ -223,13 +240,14 @@ func TestLateInitialize(t *testing.T) {
 
 func TestIsUpToDate(t *testing.T) {
 	type args struct {
-		client client.Client
-		az     *keyvault.SecretBundle
-		spec   v1alpha1.KeyVaultSecretParameters
+		client      client.Client
+		az          *keyvault.SecretBundle
+		spec        v1alpha1.KeyVaultSecretParameters
+		defaultTags map[string]string
 	}
 	cases := map[string]struct {
 		args
-		want bool
+		want azure.Diff
 	}{
 		"NotUpToDate": {
 			args: args{
@@ -250,7 +268,7 @@ func TestIsUpToDate(t *testing.T) {
 					Value: azure.ToStringPtr("other value"),
 				},
 			},
-			want: false,
+			want: azure.Diff{{Path: "Value", Desired: azure.Redacted, Observed: azure.Redacted}},
 		},
 		"DiffTags": {
 			args: args{
@@ -273,7 +291,58 @@ func TestIsUpToDate(t *testing.T) {
 					Tags:  azure.ToStringPtrMap(map[string]string{"created_by": "somebody"}),
 				},
 			},
-			want: false,
+			want: azure.Diff{{Path: "Tags[created_by]", Desired: `"crossplane"`, Observed: `"somebody"`}},
+		},
+		"RemovedTag": {
+			args: args{
+				spec: v1alpha1.KeyVaultSecretParameters{
+					Value: value,
+					Tags:  tags,
+				},
+				client: &test.MockClient{
+					MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+						s, _ := obj.(*corev1.Secret)
+						s.Data = map[string][]byte{
+							"secret-key": secretValue,
+						}
+
+						return nil
+					}),
+				},
+				az: &keyvault.SecretBundle{
+					Value: azure.ToStringPtr(string(secretValue)),
+					Tags:  azure.ToStringPtrMap(map[string]string{"created_by": "crossplane", "team": "infra"}),
+				},
+			},
+			want: azure.Diff{{Path: "Tags[team]", Desired: "null", Observed: `"infra"`}},
+		},
+		"DefaultAndOwnershipTags": {
+			args: args{
+				spec: v1alpha1.KeyVaultSecretParameters{
+					Value: value,
+					Tags:  tags,
+				},
+				client: &test.MockClient{
+					MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+						s, _ := obj.(*corev1.Secret)
+						s.Data = map[string][]byte{
+							"secret-key": secretValue,
+						}
+
+						return nil
+					}),
+				},
+				az: &keyvault.SecretBundle{
+					Value: azure.ToStringPtr(string(secretValue)),
+					Tags: azure.ToStringPtrMap(map[string]string{
+						"created_by":     "crossplane",
+						"team":           "infra",
+						azure.TagKeyName: "cool-secret",
+					}),
+				},
+				defaultTags: map[string]string{"team": "infra"},
+			},
+			want: azure.Diff{},
 		},
 		"DiffAttributes": {
 			args: args{
@@ -300,7 +369,7 @@ func TestIsUpToDate(t *testing.T) {
 					},
 				},
 			},
-			want: false,
+			want: azure.Diff{{Path: "Attributes.Enabled", Desired: "true", Observed: "null"}},
 		},
 		"UpToDate": {
 			args: args{
@@ -321,7 +390,7 @@ func TestIsUpToDate(t *testing.T) {
 					Value: azure.ToStringPtr(string(secretValue)),
 				},
 			},
-			want: true,
+			want: azure.Diff{},
 		},
 		"SameExpiresDates": {
 			args: args{
@@ -348,13 +417,13 @@ func TestIsUpToDate(t *testing.T) {
 					},
 				},
 			},
-			want: true,
+			want: azure.Diff{},
 		},
 	}
 
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
-			got, _ := IsUpToDate(context.Background(), tc.args.client, tc.args.spec, tc.args.az)
+			got, _ := IsUpToDate(context.Background(), tc.args.client, tc.args.spec, tc.args.az, tc.args.defaultTags)
 			if diff := cmp.Diff(tc.want, got); diff != "" {
 				t.Errorf("IsUpToDate(...): -want, +got:\n%s", diff)
 			}
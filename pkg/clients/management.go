@@ -0,0 +1,182 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azure
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+)
+
+// Error strings.
+const (
+	errObserveOnlyNotFound = "external resource does not exist, and will not be created because the managed resource is observe-only"
+	errObserveOnlyCreate   = "cannot create the external resource of an observe-only or dry-run managed resource"
+)
+
+// AnnotationKeyManagementMode is the annotation of a managed resource that
+// selects how the provider manages its external resource. The provider fully
+// manages external resources whose managed resource does not set it.
+const AnnotationKeyManagementMode = "azure.crossplane.io/management-mode"
+
+// A ManagementMode determines which operations the provider may perform on an
+// external resource.
+type ManagementMode string
+
+// Management modes.
+const (
+	// ManagementModeFull external resources are created, updated and
+	// deleted as their managed resource requires.
+	ManagementModeFull ManagementMode = ""
+
+	// ManagementModeObserveOnly external resources are observed, but never
+	// created, updated or deleted. Their managed resource's spec is late
+	// initialized from them, and any drift from it is only reported.
+	ManagementModeObserveOnly ManagementMode = "ObserveOnly"
+
+	// ManagementModeDryRun external resources are observed, but never
+	// created, updated or deleted. The requests that would be sent to Azure
+	// to create, update or delete them are reported instead.
+	ManagementModeDryRun ManagementMode = "DryRun"
+)
+
+// GetManagementMode returns the ManagementMode of the supplied managed
+// resource.
+func GetManagementMode(mg resource.Managed) ManagementMode {
+	return ManagementMode(mg.GetAnnotations()[AnnotationKeyManagementMode])
+}
+
+// IsObserveOnly returns true if the external resource of the supplied managed
+// resource must never be created, updated or deleted.
+func IsObserveOnly(mg resource.Managed) bool {
+	return GetManagementMode(mg) == ManagementModeObserveOnly
+}
+
+// IsDryRun returns true if the external resource of the supplied managed
+// resource must never be created, updated or deleted, but the requests to do
+// so must be reported.
+func IsDryRun(mg resource.Managed) bool {
+	return GetManagementMode(mg) == ManagementModeDryRun
+}
+
+// Condition types and reasons.
+const (
+	// TypeDrifted resources are observe-only, and report whether their
+	// external resource differs from their spec.
+	TypeDrifted xpv1.ConditionType = "Drifted"
+
+	ReasonObservedDrift xpv1.ConditionReason = "ObservedDrift"
+	ReasonNoDrift       xpv1.ConditionReason = "NoDrift"
+)
+
+// Drifted returns a condition that indicates the external resource of an
+// observe-only managed resource differs from its spec. The condition's message
+// lists the supplied fields, if any.
+func Drifted(d Diff) xpv1.Condition {
+	msg := "The external resource differs from the spec, and will not be updated because the managed resource is observe-only"
+	if !d.UpToDate() {
+		msg += ": " + d.String()
+	}
+	return xpv1.Condition{
+		Type:               TypeDrifted,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonObservedDrift,
+		Message:            msg,
+	}
+}
+
+// NoDrift returns a condition that indicates the external resource of an
+// observe-only managed resource matches its spec.
+func NoDrift() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeDrifted,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonNoDrift,
+	}
+}
+
+// EnforceObserveOnly returns a ConnecterWrapper that never creates, updates
+// or deletes the external resources of observe-only managed resources, and
+// reports any drift from their managed resources instead.
+func EnforceObserveOnly(r event.Recorder) ConnecterWrapper {
+	return wrapExternal(func(e managed.ExternalClient) managed.ExternalClient {
+		return &observeOnlyExternal{ExternalClient: e, record: r}
+	})
+}
+
+type observeOnlyExternal struct {
+	managed.ExternalClient
+	record event.Recorder
+}
+
+func (e *observeOnlyExternal) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	ctx, d := recordingDiff(ctx)
+	o, err := e.ExternalClient.Observe(ctx, mg)
+	if err != nil || !IsObserveOnly(mg) {
+		return o, err
+	}
+	switch {
+	case meta.WasDeleted(mg):
+		// Reporting the external resource as gone lets the managed
+		// reconciler remove its finalizer without deleting it.
+		o.ResourceExists = false
+		return o, nil
+	case !o.ResourceExists:
+		return o, errors.New(errObserveOnlyNotFound)
+	case o.ResourceUpToDate:
+		mg.SetConditions(NoDrift())
+		return o, nil
+	}
+	c := Drifted(*d)
+	if old := mg.GetCondition(TypeDrifted); old.Status != corev1.ConditionTrue || old.Message != c.Message {
+		e.record.Event(mg, event.Normal(event.Reason(ReasonObservedDrift), c.Message))
+	}
+	mg.SetConditions(c)
+	o.ResourceUpToDate = true
+	return o, nil
+}
+
+func (e *observeOnlyExternal) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
+	if IsObserveOnly(mg) {
+		return managed.ExternalCreation{}, errors.New(errObserveOnlyCreate)
+	}
+	return e.ExternalClient.Create(ctx, mg)
+}
+
+func (e *observeOnlyExternal) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
+	if IsObserveOnly(mg) {
+		return managed.ExternalUpdate{}, nil
+	}
+	return e.ExternalClient.Update(ctx, mg)
+}
+
+func (e *observeOnlyExternal) Delete(ctx context.Context, mg resource.Managed) error {
+	if IsObserveOnly(mg) {
+		return nil
+	}
+	return e.ExternalClient.Delete(ctx, mg)
+}
@@ -0,0 +1,137 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azure
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func observeOnly() *fake.Managed {
+	mg := &fake.Managed{}
+	mg.SetAnnotations(map[string]string{AnnotationKeyManagementMode: string(ManagementModeObserveOnly)})
+	return mg
+}
+
+func TestEnforceObserveOnly(t *testing.T) {
+	now := metav1.Now()
+	deleted := observeOnly()
+	deleted.SetDeletionTimestamp(&now)
+
+	type want struct {
+		o       managed.ExternalObservation
+		err     error
+		drifted corev1.ConditionStatus
+	}
+	cases := map[string]struct {
+		reason string
+		mg     *fake.Managed
+		o      managed.ExternalObservation
+		want   want
+	}{
+		"FullyManaged": {
+			reason: "Observations of fully managed resources should not be changed",
+			mg:     &fake.Managed{},
+			o:      managed.ExternalObservation{ResourceExists: true},
+			want:   want{o: managed.ExternalObservation{ResourceExists: true}, drifted: corev1.ConditionUnknown},
+		},
+		"NotFound": {
+			reason: "An observe-only resource whose external resource does not exist should not be created",
+			mg:     observeOnly(),
+			o:      managed.ExternalObservation{},
+			want:   want{err: errors.New(errObserveOnlyNotFound), drifted: corev1.ConditionUnknown},
+		},
+		"Deleted": {
+			reason: "A deleted observe-only resource should report its external resource as gone, so that it is not deleted",
+			mg:     deleted,
+			o:      managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+			want:   want{o: managed.ExternalObservation{ResourceUpToDate: true}, drifted: corev1.ConditionUnknown},
+		},
+		"Drifted": {
+			reason: "Drift of an observe-only resource should be reported rather than corrected",
+			mg:     observeOnly(),
+			o:      managed.ExternalObservation{ResourceExists: true, ResourceLateInitialized: true},
+			want:   want{o: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true, ResourceLateInitialized: true}, drifted: corev1.ConditionTrue},
+		},
+		"UpToDate": {
+			reason: "An observe-only resource whose external resource matches its spec should report no drift",
+			mg:     observeOnly(),
+			o:      managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+			want:   want{o: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true}, drifted: corev1.ConditionFalse},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			e := connect(t, &managed.ExternalClientFns{
+				ObserveFn: func(_ context.Context, _ resource.Managed) (managed.ExternalObservation, error) { return tc.o, nil },
+			}, tc.mg, EnforceObserveOnly(event.NewNopRecorder()))
+			got, err := e.Observe(context.Background(), tc.mg)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.o, got); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.drifted, tc.mg.GetCondition(TypeDrifted).Status); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want Drifted status, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestEnforceObserveOnlyMutations(t *testing.T) {
+	called := false
+	mutate := func(_ context.Context, _ resource.Managed) error {
+		called = true
+		return nil
+	}
+	mg := observeOnly()
+	e := connect(t, &managed.ExternalClientFns{
+		CreateFn: func(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
+			return managed.ExternalCreation{}, mutate(ctx, mg)
+		},
+		UpdateFn: func(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
+			return managed.ExternalUpdate{}, mutate(ctx, mg)
+		},
+		DeleteFn: mutate,
+	}, mg, EnforceObserveOnly(event.NewNopRecorder()))
+
+	if _, err := e.Create(context.Background(), mg); err == nil {
+		t.Errorf("e.Create(...): want error for an observe-only resource")
+	}
+	if _, err := e.Update(context.Background(), mg); err != nil {
+		t.Errorf("e.Update(...): %s", err)
+	}
+	if err := e.Delete(context.Background(), mg); err != nil {
+		t.Errorf("e.Delete(...): %s", err)
+	}
+	if called {
+		t.Errorf("the external client of an observe-only resource should never be asked to create, update or delete")
+	}
+}
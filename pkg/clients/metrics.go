@@ -0,0 +1,245 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azure
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+)
+
+// Metric labels.
+const (
+	labelService        = "service"
+	labelOperation      = "operation"
+	labelProviderConfig = "providerconfig"
+	labelCode           = "code"
+	labelMethod         = "method"
+
+	// codeError is the code label of requests that did not get a response.
+	codeError = "error"
+)
+
+var (
+	requestsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "crossplane_azure_requests_total",
+		Help: "Number of requests sent to Azure, by service, operation, ProviderConfig and HTTP status code.",
+	}, []string{labelService, labelOperation, labelProviderConfig, labelCode})
+	requestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "crossplane_azure_request_duration_seconds",
+		Help:    "Latency of requests sent to Azure, by service, operation, ProviderConfig and HTTP status code.",
+		Buckets: []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60},
+	}, []string{labelService, labelOperation, labelProviderConfig, labelCode})
+	operationsInFlight = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "crossplane_azure_long_running_operations_in_flight",
+		Help: "Number of long-running Azure operations started by the provider that have not yet completed, by service and method.",
+	}, []string{labelService, labelMethod})
+)
+
+func init() {
+	metrics.Registry.MustRegister(requestsTotal, requestDuration, operationsInFlight)
+}
+
+// DefaultRequestMetrics are the RequestMetrics recorded by all clients
+// configured by ConfigureClient.
+var DefaultRequestMetrics = NewRequestMetrics(requestsTotal, requestDuration, operationsInFlight)
+
+// RequestMetrics record the requests sent to Azure and the long-running
+// operations they start.
+type RequestMetrics struct {
+	requests *prometheus.CounterVec
+	latency  *prometheus.HistogramVec
+	inFlight *prometheus.GaugeVec
+
+	mu         sync.Mutex
+	operations map[string]prometheus.Labels
+}
+
+// NewRequestMetrics returns RequestMetrics that use the supplied collectors.
+// The request counter and latency histogram must have service, operation,
+// providerconfig and code labels. The gauge of in-flight operations must have
+// service and method labels.
+func NewRequestMetrics(requests *prometheus.CounterVec, latency *prometheus.HistogramVec, inFlight *prometheus.GaugeVec) *RequestMetrics {
+	return &RequestMetrics{
+		requests:   requests,
+		latency:    latency,
+		inFlight:   inFlight,
+		operations: map[string]prometheus.Labels{},
+	}
+}
+
+// WithMetrics returns a SendDecorator that counts and times every attempt to
+// send a request. Requests are labelled with the ProviderConfig stored in
+// their context by WithProviderConfig.
+func (m *RequestMetrics) WithMetrics() autorest.SendDecorator {
+	return func(s autorest.Sender) autorest.Sender {
+		return autorest.SenderFunc(func(r *http.Request) (*http.Response, error) {
+			start := time.Now()
+			resp, err := s.Do(r)
+			code := codeError
+			if resp != nil {
+				code = strconv.Itoa(resp.StatusCode)
+			}
+			service, resourceType := describeRequest(r.URL)
+			l := prometheus.Labels{
+				labelService:        service,
+				labelOperation:      r.Method + " " + resourceType,
+				labelProviderConfig: providerConfigFrom(r.Context()),
+				labelCode:           code,
+			}
+			m.requests.With(l).Inc()
+			m.latency.With(l).Observe(time.Since(start).Seconds())
+			return resp, err
+		})
+	}
+}
+
+// TrackOperation records whether the long-running operation with the supplied
+// polling URL is still in flight. Operations are identified by their polling
+// URL, so tracking the same operation repeatedly has no further effect.
+func (m *RequestMetrics) TrackOperation(method, pollingURL string, inProgress bool) {
+	if pollingURL == "" {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	l, tracked := m.operations[pollingURL]
+	switch {
+	case inProgress && !tracked:
+		service := ""
+		if u, err := url.Parse(pollingURL); err == nil {
+			service, _ = describeRequest(u)
+		}
+		l = prometheus.Labels{labelService: service, labelMethod: method}
+		m.operations[pollingURL] = l
+		m.inFlight.With(l).Inc()
+	case !inProgress && tracked:
+		delete(m.operations, pollingURL)
+		m.inFlight.With(l).Dec()
+	}
+}
+
+// describeRequest returns the service and resource type addressed by the
+// supplied URL. Azure Resource Manager services are identified by their
+// resource provider namespace, e.g. Microsoft.Cache, and resource types omit
+// the names of resources, e.g. redis/listKeys. Other services are identified
+// by the first label of their host following the resource name, and their
+// resource type is the first segment of the path.
+func describeRequest(u *url.URL) (service, resourceType string) {
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	for i := 0; i < len(parts)-1; i++ {
+		if strings.EqualFold(parts[i], "providers") {
+			return parts[i+1], typeOf(parts[i+2:])
+		}
+	}
+	if len(parts) >= 2 && strings.EqualFold(parts[0], "subscriptions") {
+		return "Microsoft.Resources", typeOf(parts[2:])
+	}
+	service = u.Hostname()
+	if i := strings.Index(service, "."); i > 0 {
+		// Data plane hosts are named after the resource, e.g.
+		// myvault.vault.azure.net.
+		service = strings.SplitN(service[i+1:], ".", 2)[0]
+	}
+	return service, parts[0]
+}
+
+// typeOf returns the resource type of the supplied path segments, which
+// alternate between types and names.
+func typeOf(parts []string) string {
+	types := make([]string, 0, (len(parts)+1)/2)
+	for i := 0; i < len(parts); i += 2 {
+		if parts[i] != "" {
+			types = append(types, parts[i])
+		}
+	}
+	return strings.Join(types, "/")
+}
+
+type providerConfigKey struct{}
+
+// WithProviderConfig returns a copy of the supplied context that labels the
+// metrics of any request sent with it with the ProviderConfig (or deprecated
+// Provider) of the supplied managed resource.
+func WithProviderConfig(ctx context.Context, mg resource.Managed) context.Context {
+	name := ""
+	switch {
+	case mg.GetProviderConfigReference() != nil:
+		name = mg.GetProviderConfigReference().Name
+	case mg.GetProviderReference() != nil:
+		name = mg.GetProviderReference().Name
+	}
+	return context.WithValue(ctx, providerConfigKey{}, name)
+}
+
+func providerConfigFrom(ctx context.Context) string {
+	name, _ := ctx.Value(providerConfigKey{}).(string)
+	return name
+}
+
+// LabelMetrics returns a ConnecterWrapper that labels the metrics of any
+// request sent to Azure on behalf of a managed resource with its
+// ProviderConfig.
+func LabelMetrics() ConnecterWrapper {
+	return func(c managed.ExternalConnecter) managed.ExternalConnecter {
+		return &labellingConnecter{ExternalConnecter: c}
+	}
+}
+
+type labellingConnecter struct {
+	managed.ExternalConnecter
+}
+
+func (c *labellingConnecter) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
+	e, err := c.ExternalConnecter.Connect(WithProviderConfig(ctx, mg), mg)
+	if err != nil {
+		return nil, err
+	}
+	return &labellingExternal{ExternalClient: e}, nil
+}
+
+type labellingExternal struct {
+	managed.ExternalClient
+}
+
+func (e *labellingExternal) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	return e.ExternalClient.Observe(WithProviderConfig(ctx, mg), mg)
+}
+
+func (e *labellingExternal) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
+	return e.ExternalClient.Create(WithProviderConfig(ctx, mg), mg)
+}
+
+func (e *labellingExternal) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
+	return e.ExternalClient.Update(WithProviderConfig(ctx, mg), mg)
+}
+
+func (e *labellingExternal) Delete(ctx context.Context, mg resource.Managed) error {
+	return e.ExternalClient.Delete(WithProviderConfig(ctx, mg), mg)
+}
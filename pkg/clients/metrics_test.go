@@ -0,0 +1,185 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azure
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/services/resources/mgmt/2018-05-01/resources"
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/google/go-cmp/cmp"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+)
+
+func newTestRequestMetrics() *RequestMetrics {
+	return NewRequestMetrics(
+		prometheus.NewCounterVec(prometheus.CounterOpts{Name: "requests"}, []string{labelService, labelOperation, labelProviderConfig, labelCode}),
+		prometheus.NewHistogramVec(prometheus.HistogramOpts{Name: "latency"}, []string{labelService, labelOperation, labelProviderConfig, labelCode}),
+		prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "inflight"}, []string{labelService, labelMethod}),
+	)
+}
+
+func TestDescribeRequest(t *testing.T) {
+	type want struct {
+		service      string
+		resourceType string
+	}
+	cases := map[string]struct {
+		url  string
+		want want
+	}{
+		"Resource": {
+			url:  "https://management.azure.com/subscriptions/sub/resourceGroups/rg/providers/Microsoft.ContainerService/managedClusters/cool?api-version=2021-10-01",
+			want: want{service: "Microsoft.ContainerService", resourceType: "managedClusters"},
+		},
+		"Action": {
+			url:  "https://management.azure.com/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Cache/redis/cool/listKeys",
+			want: want{service: "Microsoft.Cache", resourceType: "redis/listKeys"},
+		},
+		"ChildResource": {
+			url:  "https://management.azure.com/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Network/virtualNetworks/vnet/subnets/subnet",
+			want: want{service: "Microsoft.Network", resourceType: "virtualNetworks/subnets"},
+		},
+		"Operation": {
+			url:  "https://management.azure.com/subscriptions/sub/providers/Microsoft.Cache/locations/westus/asyncOperations/id",
+			want: want{service: "Microsoft.Cache", resourceType: "locations/asyncOperations"},
+		},
+		"ResourceGroup": {
+			url:  "https://management.azure.com/subscriptions/sub/resourcegroups/rg",
+			want: want{service: "Microsoft.Resources", resourceType: "resourcegroups"},
+		},
+		"DataPlane": {
+			url:  "https://cool.vault.azure.net/secrets/secret/version",
+			want: want{service: "vault", resourceType: "secrets"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			u, _ := url.Parse(tc.url)
+			service, resourceType := describeRequest(u)
+			if diff := cmp.Diff(tc.want, want{service: service, resourceType: resourceType}, cmp.AllowUnexported(want{})); diff != "" {
+				t.Errorf("describeRequest(%q): -want, +got:\n%s", tc.url, diff)
+			}
+		})
+	}
+}
+
+func TestWithMetrics(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/subscriptions/sub/resourcegroups/missing" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(`{"name":"rg"}`))
+	}))
+	defer srv.Close()
+
+	m := newTestRequestMetrics()
+	cl := resources.NewGroupsClientWithBaseURI(srv.URL, "sub")
+	cl.Authorizer = autorest.NullAuthorizer{}
+	cl.Sender = autorest.DecorateSender(autorest.CreateSender(), m.WithMetrics())
+
+	mg := &fake.Managed{ProviderConfigReferencer: fake.ProviderConfigReferencer{Ref: &xpv1.Reference{Name: "default"}}}
+	ctx := WithProviderConfig(context.Background(), mg)
+	_, _ = cl.Get(ctx, "rg")
+	_, _ = cl.Get(ctx, "rg")
+	_, _ = cl.Get(ctx, "missing")
+
+	ok := m.requests.WithLabelValues("Microsoft.Resources", "GET resourcegroups", "default", "200")
+	if diff := cmp.Diff(float64(2), testutil.ToFloat64(ok)); diff != "" {
+		t.Errorf("WithMetrics(): successful requests: -want, +got:\n%s", diff)
+	}
+	notFound := m.requests.WithLabelValues("Microsoft.Resources", "GET resourcegroups", "default", "404")
+	if diff := cmp.Diff(float64(1), testutil.ToFloat64(notFound)); diff != "" {
+		t.Errorf("WithMetrics(): failed requests: -want, +got:\n%s", diff)
+	}
+	if diff := cmp.Diff(2, testutil.CollectAndCount(m.latency)); diff != "" {
+		t.Errorf("WithMetrics(): latency series: -want, +got:\n%s", diff)
+	}
+}
+
+func TestTrackOperation(t *testing.T) {
+	m := newTestRequestMetrics()
+	const pollingURL = "https://management.azure.com/subscriptions/sub/providers/Microsoft.Cache/locations/westus/asyncOperations/id"
+	g := m.inFlight.WithLabelValues("Microsoft.Cache", http.MethodPut)
+
+	m.TrackOperation(http.MethodPut, pollingURL, true)
+	m.TrackOperation(http.MethodPut, pollingURL, true)
+	if diff := cmp.Diff(float64(1), testutil.ToFloat64(g)); diff != "" {
+		t.Errorf("TrackOperation(...): an operation should be counted once however often it is tracked: -want, +got:\n%s", diff)
+	}
+
+	m.TrackOperation(http.MethodPut, pollingURL, false)
+	m.TrackOperation(http.MethodPut, pollingURL, false)
+	if diff := cmp.Diff(float64(0), testutil.ToFloat64(g)); diff != "" {
+		t.Errorf("TrackOperation(...): a completed operation should no longer be in flight: -want, +got:\n%s", diff)
+	}
+}
+
+func TestLabelMetrics(t *testing.T) {
+	var got []string
+	record := func(ctx context.Context) { got = append(got, providerConfigFrom(ctx)) }
+	mg := &fake.Managed{ProviderConfigReferencer: fake.ProviderConfigReferencer{Ref: &xpv1.Reference{Name: "default"}}}
+	c := LabelMetrics()(managed.ExternalConnectorFn(func(ctx context.Context, _ resource.Managed) (managed.ExternalClient, error) {
+		record(ctx)
+		return &managed.ExternalClientFns{
+			ObserveFn: func(ctx context.Context, _ resource.Managed) (managed.ExternalObservation, error) {
+				record(ctx)
+				return managed.ExternalObservation{}, nil
+			},
+			CreateFn: func(ctx context.Context, _ resource.Managed) (managed.ExternalCreation, error) {
+				record(ctx)
+				return managed.ExternalCreation{}, nil
+			},
+			UpdateFn: func(ctx context.Context, _ resource.Managed) (managed.ExternalUpdate, error) {
+				record(ctx)
+				return managed.ExternalUpdate{}, nil
+			},
+			DeleteFn: func(ctx context.Context, _ resource.Managed) error {
+				record(ctx)
+				return nil
+			},
+		}, nil
+	}))
+
+	ctx := context.Background()
+	e, err := c.Connect(ctx, mg)
+	if err != nil {
+		t.Fatalf("Connect(...): %s", err)
+	}
+	_, _ = e.Observe(ctx, mg)
+	_, _ = e.Create(ctx, mg)
+	_, _ = e.Update(ctx, mg)
+	_ = e.Delete(ctx, mg)
+
+	want := []string{"default", "default", "default", "default", "default"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("LabelMetrics(): every call should be labelled with the ProviderConfig: -want, +got:\n%s", diff)
+	}
+}
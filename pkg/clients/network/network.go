@@ -17,7 +17,6 @@ limitations under the License.
 package network
 
 import (
-	"reflect"
 	"sort"
 	"strings"
 
@@ -30,10 +29,11 @@ import (
 )
 
 // NewVirtualNetworkParameters returns an Azure VirtualNetwork object from a virtual network spec
-func NewVirtualNetworkParameters(v *v1alpha3.VirtualNetwork) networkmgmt.VirtualNetwork {
+// and the supplied default tags.
+func NewVirtualNetworkParameters(v *v1alpha3.VirtualNetwork, defaultTags map[string]string) networkmgmt.VirtualNetwork {
 	return networkmgmt.VirtualNetwork{
 		Location: azure.ToStringPtr(v.Spec.Location),
-		Tags:     azure.ToStringPtrMap(v.Spec.Tags),
+		Tags:     azure.NewTags(v, defaultTags, v.Spec.Tags),
 		VirtualNetworkPropertiesFormat: &networkmgmt.VirtualNetworkPropertiesFormat{
 			EnableDdosProtection: azure.ToBoolPtr(v.Spec.VirtualNetworkPropertiesFormat.EnableDDOSProtection, azure.FieldRequired),
 			EnableVMProtection:   azure.ToBoolPtr(v.Spec.VirtualNetworkPropertiesFormat.EnableVMProtection),
@@ -44,22 +44,17 @@ func NewVirtualNetworkParameters(v *v1alpha3.VirtualNetwork) networkmgmt.Virtual
 	}
 }
 
-// VirtualNetworkNeedsUpdate determines if a virtual network need to be updated
-func VirtualNetworkNeedsUpdate(kube *v1alpha3.VirtualNetwork, az networkmgmt.VirtualNetwork) bool {
-	up := NewVirtualNetworkParameters(kube)
+// VirtualNetworkNeedsUpdate returns the fields in which the supplied Azure
+// virtual network differs from the supplied VirtualNetwork.
+func VirtualNetworkNeedsUpdate(kube *v1alpha3.VirtualNetwork, az networkmgmt.VirtualNetwork, defaultTags map[string]string) azure.Diff {
+	up := NewVirtualNetworkParameters(kube, defaultTags)
 
-	switch {
-	case !reflect.DeepEqual(up.VirtualNetworkPropertiesFormat.AddressSpace, az.VirtualNetworkPropertiesFormat.AddressSpace):
-		return true
-	case !reflect.DeepEqual(up.VirtualNetworkPropertiesFormat.EnableDdosProtection, az.VirtualNetworkPropertiesFormat.EnableDdosProtection):
-		return true
-	case !reflect.DeepEqual(up.VirtualNetworkPropertiesFormat.EnableVMProtection, az.VirtualNetworkPropertiesFormat.EnableVMProtection):
-		return true
-	case !reflect.DeepEqual(up.Tags, az.Tags):
-		return true
-	}
-
-	return false
+	d := azure.Diff{}
+	d.Compare("properties.addressSpace", up.VirtualNetworkPropertiesFormat.AddressSpace, az.VirtualNetworkPropertiesFormat.AddressSpace)
+	d.Compare("properties.enableDdosProtection", up.VirtualNetworkPropertiesFormat.EnableDdosProtection, az.VirtualNetworkPropertiesFormat.EnableDdosProtection)
+	d.Compare("properties.enableVmProtection", up.VirtualNetworkPropertiesFormat.EnableVMProtection, az.VirtualNetworkPropertiesFormat.EnableVMProtection)
+	d.Compare("tags", azure.DesiredTags(defaultTags, kube.Spec.Tags), azure.ObservedTags(az.Tags))
+	return d
 }
 
 // UpdateVirtualNetworkStatusFromAzure updates the status related to the external
@@ -83,7 +78,8 @@ func NewSubnetParameters(s *v1alpha3.Subnet) networkmgmt.Subnet {
 }
 
 // NewPublicIPAddressParameters returns an Azure PublicIPAddress object from a public ip address spec
-func NewPublicIPAddressParameters(s *v1alpha3.PublicIPAddress) networkmgmt.PublicIPAddress {
+// and the supplied default tags.
+func NewPublicIPAddressParameters(s *v1alpha3.PublicIPAddress, defaultTags map[string]string) networkmgmt.PublicIPAddress {
 	p := s.Spec.ForProvider
 	return networkmgmt.PublicIPAddress{
 		Sku: NewPublicIPAddressSKU(s.Spec.ForProvider.SKU),
@@ -96,7 +92,7 @@ func NewPublicIPAddressParameters(s *v1alpha3.PublicIPAddress) networkmgmt.Publi
 			IPTags:                   newIPTags(p.IPTags),
 		},
 		Location: &p.Location,
-		Tags:     azure.ToStringPtrMap(p.Tags),
+		Tags:     azure.NewTags(s, defaultTags, p.Tags),
 	}
 }
 
@@ -157,11 +153,14 @@ func NewServiceEndpoints(e []v1alpha3.ServiceEndpointPropertiesFormat) *[]networ
 	return &endpoints
 }
 
-// SubnetNeedsUpdate determines if a virtual network need to be updated
-func SubnetNeedsUpdate(kube *v1alpha3.Subnet, az networkmgmt.Subnet) bool {
+// SubnetNeedsUpdate returns the fields in which the supplied Azure subnet
+// differs from the supplied Subnet.
+func SubnetNeedsUpdate(kube *v1alpha3.Subnet, az networkmgmt.Subnet) azure.Diff {
 	up := NewSubnetParameters(kube)
 
-	return !reflect.DeepEqual(up.SubnetPropertiesFormat.AddressPrefix, az.SubnetPropertiesFormat.AddressPrefix)
+	d := azure.Diff{}
+	d.Compare("properties.addressPrefix", up.SubnetPropertiesFormat.AddressPrefix, az.SubnetPropertiesFormat.AddressPrefix)
+	return d
 }
 
 // UpdateSubnetStatusFromAzure updates the status related to the external
@@ -200,9 +199,9 @@ func GeneratePublicIPAddressObservation(az networkmgmt.PublicIPAddress) *v1alpha
 }
 
 // LateInitializePublicIPAddress late-initilizes a PublicIPAddress resource
-func LateInitializePublicIPAddress(p *v1alpha3.PublicIPAddressProperties, in *networkmgmt.PublicIPAddress) {
+func LateInitializePublicIPAddress(p *v1alpha3.PublicIPAddressProperties, in *networkmgmt.PublicIPAddress, defaultTags map[string]string) {
 	p.PublicIPAddressDNSSettings = lateInitializeDNSSettings(p.PublicIPAddressDNSSettings, in.DNSSettings)
-	p.Tags = azure.LateInitializeStringMap(p.Tags, in.Tags)
+	p.Tags = azure.LateInitializeTags(p.Tags, in.Tags, defaultTags)
 	if p.SKU == nil && in.Sku != nil {
 		p.SKU = &v1alpha3.SKU{
 			Name: string(in.Sku.Name),
@@ -244,30 +243,28 @@ func lateInitializeDNSSettings(d *v1alpha3.PublicIPAddressDNSSettings, in *netwo
 	return d
 }
 
-// IsPublicIPAddressUpToDate is used to report whether given network.PublicIPAddress is in
-// sync with the PublicIPAddressProperties that the user desires.
-func IsPublicIPAddressUpToDate(p v1alpha3.PublicIPAddressProperties, in networkmgmt.PublicIPAddress) bool {
-	if !cmp.Equal(p.Tags, azure.ToStringMap(in.Tags), cmpopts.EquateEmpty()) {
-		return false
+// IsPublicIPAddressUpToDate returns the fields in which the given
+// network.PublicIPAddress is out of sync with the PublicIPAddressProperties
+// that the user desires.
+func IsPublicIPAddressUpToDate(p v1alpha3.PublicIPAddressProperties, in networkmgmt.PublicIPAddress, defaultTags map[string]string) azure.Diff {
+	d := azure.Diff{}
+	if desired, observed := azure.DesiredTags(defaultTags, p.Tags), azure.ObservedTags(in.Tags); !cmp.Equal(desired, observed, cmpopts.EquateEmpty()) {
+		d.Add("tags", desired, observed)
 	}
-
 	if !isIPPrefixIDUpToDate(p.PublicIPPrefixID, in.PublicIPPrefix) {
-		return false
+		d.Add("publicIPPrefixID", p.PublicIPPrefixID, in.PublicIPPrefix)
 	}
-
-	if azure.ToInt(p.TCPIdleTimeoutInMinutes) != azure.ToInt(in.IdleTimeoutInMinutes) {
-		return false
-	}
-
+	d.Compare("tcpIdleTimeoutInMinutes", azure.ToInt(p.TCPIdleTimeoutInMinutes), azure.ToInt(in.IdleTimeoutInMinutes))
 	if !isIPTagsUpToDate(p.IPTags, in.IPTags) {
-		return false
+		d.Add("ipTags", p.IPTags, in.IPTags)
 	}
-
 	if !isSKUUpToDate(p.SKU, in.Sku) {
-		return false
+		d.Add("sku", p.SKU, in.Sku)
 	}
-
-	return isDNSSettingsUpToDate(p.PublicIPAddressDNSSettings, in.PublicIPAddressPropertiesFormat.DNSSettings)
+	if !isDNSSettingsUpToDate(p.PublicIPAddressDNSSettings, in.PublicIPAddressPropertiesFormat.DNSSettings) {
+		d.Add("dnsSettings", p.PublicIPAddressDNSSettings, in.PublicIPAddressPropertiesFormat.DNSSettings)
+	}
+	return d
 }
 
 func isDNSSettingsUpToDate(d *v1alpha3.PublicIPAddressDNSSettings, in *networkmgmt.PublicIPAddressDNSSettings) bool {
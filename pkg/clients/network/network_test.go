@@ -86,7 +86,7 @@ func TestNewVirtualNetworkParameters(t *testing.T) {
 			},
 			want: networkmgmt.VirtualNetwork{
 				Location: azure.ToStringPtr(location),
-				Tags:     azure.ToStringPtrMap(nil),
+				Tags:     azure.ToStringPtrMap(map[string]string{azure.TagKeyKind: "VirtualNetwork", azure.TagKeyName: ""}),
 				VirtualNetworkPropertiesFormat: &networkmgmt.VirtualNetworkPropertiesFormat{
 					EnableDdosProtection: to.BoolPtr(enableDDOSProtection),
 					EnableVMProtection:   to.BoolPtr(enableVMProtection),
@@ -112,7 +112,7 @@ func TestNewVirtualNetworkParameters(t *testing.T) {
 			},
 			want: networkmgmt.VirtualNetwork{
 				Location: azure.ToStringPtr(location),
-				Tags:     azure.ToStringPtrMap(nil),
+				Tags:     azure.ToStringPtrMap(map[string]string{azure.TagKeyKind: "VirtualNetwork", azure.TagKeyName: ""}),
 				VirtualNetworkPropertiesFormat: &networkmgmt.VirtualNetworkPropertiesFormat{
 					EnableDdosProtection: to.BoolPtr(enableDDOSProtection),
 					EnableVMProtection:   nil,
@@ -126,7 +126,7 @@ func TestNewVirtualNetworkParameters(t *testing.T) {
 
 	for _, tc := range cases {
 		t.Run(tc.name, func(t *testing.T) {
-			got := NewVirtualNetworkParameters(tc.r)
+			got := NewVirtualNetworkParameters(tc.r, nil)
 			if diff := cmp.Diff(tc.want, got); diff != "" {
 				t.Errorf("NewVirtualNetworkParameters(...): -want, +got\n%s", diff)
 			}
@@ -275,8 +275,8 @@ func TestVirtualNetworkNeedsUpdate(t *testing.T) {
 
 	for _, tc := range cases {
 		t.Run(tc.name, func(t *testing.T) {
-			got := VirtualNetworkNeedsUpdate(tc.kube, tc.az)
-			if diff := cmp.Diff(tc.want, got); diff != "" {
+			got := VirtualNetworkNeedsUpdate(tc.kube, tc.az, nil)
+			if diff := cmp.Diff(tc.want, !got.UpToDate()); diff != "" {
 				t.Errorf("VirtualNetworkNeedsUpdate(...): -want, +got\n%s", diff)
 			}
 		})
@@ -465,16 +465,18 @@ func TestNewPublicIPAddressParameters(t *testing.T) {
 				Sku: &networkmgmt.PublicIPAddressSku{
 					Name: networkmgmt.PublicIPAddressSkuName(skuName),
 				},
-				Tags: map[string]*string{
-					tagKey: &tagVal,
-				},
+				Tags: azure.ToStringPtrMap(map[string]string{
+					tagKey:           tagVal,
+					azure.TagKeyKind: "PublicIPAddress",
+					azure.TagKeyName: "",
+				}),
 			},
 		},
 	}
 
 	for _, tc := range cases {
 		t.Run(tc.name, func(t *testing.T) {
-			got := NewPublicIPAddressParameters(tc.r)
+			got := NewPublicIPAddressParameters(tc.r, nil)
 			if diff := cmp.Diff(tc.want, got); diff != "" {
 				t.Errorf("NewSubnetParameters(...): -want, +got\n%s", diff)
 			}
@@ -558,7 +560,7 @@ func TestSubnetNeedsUpdate(t *testing.T) {
 	for _, tc := range cases {
 		t.Run(tc.name, func(t *testing.T) {
 			got := SubnetNeedsUpdate(tc.kube, tc.az)
-			if diff := cmp.Diff(tc.want, got); diff != "" {
+			if diff := cmp.Diff(tc.want, !got.UpToDate()); diff != "" {
 				t.Errorf("SubnetNeedsUpdate(...): -want, +got\n%s", diff)
 			}
 		})
@@ -954,7 +956,7 @@ func TestIsPublicIPAddressUpToDate(t *testing.T) {
 	}
 	for name, tt := range tests {
 		t.Run(name, func(t *testing.T) {
-			if got := IsPublicIPAddressUpToDate(tt.args.p, tt.args.in); got != tt.want {
+			if got := IsPublicIPAddressUpToDate(tt.args.p, tt.args.in, nil); got.UpToDate() != tt.want {
 				t.Errorf("IsPublicIPAddressUpToDate() = %v, want %v", got, tt.want)
 			}
 		})
@@ -1126,7 +1128,7 @@ func TestLateInitializePublicIPAddress(t *testing.T) {
 	}
 	for name, tt := range tests {
 		t.Run(name, func(t *testing.T) {
-			LateInitializePublicIPAddress(&tt.args.p, &tt.args.in)
+			LateInitializePublicIPAddress(&tt.args.p, &tt.args.in, nil)
 			if diff := cmp.Diff(tt.want, tt.args.p); diff != "" {
 				t.Errorf("LateInitializePublicIPAddress(tt.args.p, tt.args.in): -want, +got\n%s", diff)
 			}
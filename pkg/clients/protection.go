@@ -0,0 +1,383 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azure
+
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/services/resources/mgmt/2016-09-01/locks"
+	"github.com/Azure/azure-sdk-for-go/services/resources/mgmt/2016-09-01/locks/locksapi"
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+)
+
+// AnnotationKeyDeletionProtection is the annotation of a managed resource that
+// protects its external resource from deletion. External resources whose
+// managed resource does not set it are not protected.
+const AnnotationKeyDeletionProtection = "azure.crossplane.io/deletion-protection"
+
+// A DeletionProtection determines how an external resource is protected from
+// deletion.
+type DeletionProtection string
+
+// Deletion protections.
+const (
+	// DeletionProtectionDisabled external resources are deleted with their
+	// managed resource, subject to its deletion policy.
+	DeletionProtectionDisabled DeletionProtection = ""
+
+	// DeletionProtectionEnabled external resources are never deleted by
+	// the provider. Deleting their managed resource fails until the
+	// protection is removed.
+	DeletionProtectionEnabled DeletionProtection = "Enabled"
+
+	// DeletionProtectionLock external resources are protected like
+	// DeletionProtectionEnabled ones, and by a CanNotDelete management lock
+	// that protects them from deletion outside Kubernetes too. Only some
+	// kinds of managed resource support management locks; the others are
+	// protected like DeletionProtectionEnabled ones, and report that they
+	// could not be locked. The lock is checked whenever the managed resource
+	// is observed, which costs an extra read request per poll.
+	DeletionProtectionLock DeletionProtection = "Lock"
+)
+
+// DeletionLockName is the name of the management lock that protects an
+// external resource from deletion.
+const DeletionLockName = "crossplane-deletion-protection"
+
+// Error strings.
+const (
+	errDeletionProtected = "refusing to delete the external resource because deletion protection is enabled; remove the " + AnnotationKeyDeletionProtection + " annotation to delete it"
+	errGetLock           = "cannot get management lock"
+	errCreateLock        = "cannot create management lock"
+	errDeleteLock        = "cannot delete management lock"
+	errCannotLock        = "cannot protect the external resource with a management lock: its kind does not support " + string(DeletionProtectionLock) + " protection; it is only protected from deletion by the provider"
+
+	errFmtUnknownDeletionProtection = "unknown " + AnnotationKeyDeletionProtection + " annotation %q: must be " + string(DeletionProtectionEnabled) + " or " + string(DeletionProtectionLock)
+)
+
+// GetDeletionProtection returns the DeletionProtection of the supplied managed
+// resource. It returns an error if the managed resource's annotation is not a
+// known DeletionProtection.
+func GetDeletionProtection(mg resource.Managed) (DeletionProtection, error) {
+	switch p := DeletionProtection(mg.GetAnnotations()[AnnotationKeyDeletionProtection]); p {
+	case DeletionProtectionDisabled, DeletionProtectionEnabled, DeletionProtectionLock:
+		return p, nil
+	default:
+		return p, errors.Errorf(errFmtUnknownDeletionProtection, p)
+	}
+}
+
+// IsDeletionProtected returns true if the external resource of the supplied
+// managed resource must not be deleted. It returns an error if the managed
+// resource's annotation is not a known DeletionProtection.
+func IsDeletionProtected(mg resource.Managed) (bool, error) {
+	p, err := GetDeletionProtection(mg)
+	return p != DeletionProtectionDisabled, err
+}
+
+// ErrDeletionProtected returns the error that the supplied managed resource
+// reports when it is deleted while its external resource is protected from
+// deletion.
+func ErrDeletionProtected() error {
+	return errors.New(errDeletionProtected)
+}
+
+// A retainedError is returned by an ExternalClient that refuses to delete an
+// external resource that Azure requires to be retained.
+type retainedError struct {
+	error
+}
+
+// RefuseDeletion returns an error that an ExternalClient's Delete returns when
+// it refuses to delete an external resource that must be retained, for
+// example because it holds data under a retention policy. Unlike other
+// errors, the refusal is reported as the DeletionProtected condition.
+func RefuseDeletion(err error) error {
+	return retainedError{error: err}
+}
+
+// IsRetained returns true if the supplied error indicates an ExternalClient
+// refused to delete an external resource that must be retained.
+func IsRetained(err error) bool {
+	return errors.As(err, &retainedError{})
+}
+
+// Condition types and reasons.
+const (
+	// TypeDeletionProtected resources report whether their external
+	// resource is protected from deletion.
+	TypeDeletionProtected xpv1.ConditionType = "DeletionProtected"
+
+	ReasonProtected       xpv1.ConditionReason = "Protected"
+	ReasonLocked          xpv1.ConditionReason = "Locked"
+	ReasonDeletionRefused xpv1.ConditionReason = "DeletionRefused"
+	ReasonRetained        xpv1.ConditionReason = "Retained"
+	ReasonUnprotected     xpv1.ConditionReason = "Unprotected"
+)
+
+// reasonCannotLock is the reason of the event recorded when a managed resource
+// whose kind does not support management locks requests one.
+const reasonCannotLock event.Reason = "CannotLock"
+
+// Protected returns a condition that indicates the external resource of a
+// managed resource is protected from deletion by the provider.
+func Protected() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeDeletionProtected,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonProtected,
+	}
+}
+
+// ProtectedWithoutLock returns a condition that indicates the external resource
+// of a managed resource is protected from deletion by the provider, but not by
+// the management lock it requested, because its kind does not support them.
+func ProtectedWithoutLock() xpv1.Condition {
+	c := Protected()
+	c.Message = errCannotLock
+	return c
+}
+
+// Locked returns a condition that indicates the external resource of a
+// managed resource is protected from deletion by the provider, and by a
+// management lock.
+func Locked() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeDeletionProtected,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonLocked,
+		Message:            "The external resource is protected by the " + DeletionLockName + " management lock",
+	}
+}
+
+// DeletionRefused returns a condition that indicates the provider refused to
+// delete the external resource of a deleted managed resource.
+func DeletionRefused() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeDeletionProtected,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonDeletionRefused,
+		Message:            errDeletionProtected,
+	}
+}
+
+// Retained returns a condition that indicates the external client of a
+// deleted managed resource refused to delete its external resource for the
+// supplied reason, because Azure requires it to be retained.
+func Retained(err error) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeDeletionProtected,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonRetained,
+		Message:            err.Error(),
+	}
+}
+
+// Unprotected returns a condition that indicates the external resource of a
+// managed resource is no longer protected from deletion.
+func Unprotected() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeDeletionProtected,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonUnprotected,
+	}
+}
+
+// A Locker protects the external resource of a managed resource from deletion
+// outside Kubernetes using a management lock. External clients implement it
+// if their external resources support the DeletionProtectionLock protection.
+type Locker interface {
+	// Lock the external resource of the supplied managed resource, unless
+	// it is already locked. It is called whenever the managed resource is
+	// observed, so that a lock that was removed outside Kubernetes is
+	// created again. It returns false if the external resource cannot be
+	// locked yet, for example because it is still being created.
+	Lock(ctx context.Context, mg resource.Managed) (bool, error)
+
+	// Unlock the external resource of the supplied managed resource,
+	// removing only the lock created by Lock.
+	Unlock(ctx context.Context, mg resource.Managed) error
+}
+
+// NewManagementLocksClient returns a client for the management locks of the
+// subscription in the supplied credentials.
+func NewManagementLocksClient(creds map[string]string, auth autorest.Authorizer) locks.ManagementLocksClient {
+	cl := locks.NewManagementLocksClientWithBaseURI(creds[CredentialsKeyResourceManagerEndpointURL], creds[CredentialsKeySubscriptionID])
+	ConfigureClient(&cl.Client, auth)
+	return cl
+}
+
+// LockDeletion creates a CanNotDelete management lock named DeletionLockName
+// on the Azure resource with the supplied ID, unless it already exists. It
+// gets the lock to find out whether it exists, so a Locker that calls it
+// whenever a managed resource is observed sends one more read request per
+// poll, which counts against the subscription's quota of read requests.
+func LockDeletion(ctx context.Context, c locksapi.ManagementLocksClientAPI, id string) error {
+	_, err := c.GetByScope(ctx, id, DeletionLockName)
+	if !IsNotFound(err) {
+		return errors.Wrap(err, errGetLock)
+	}
+	_, err = c.CreateOrUpdateByScope(ctx, id, DeletionLockName, locks.ManagementLockObject{
+		ManagementLockProperties: &locks.ManagementLockProperties{
+			Level: locks.CanNotDelete,
+			Notes: ToStringPtr("Deletion protection of the Crossplane managed resource for this resource"),
+		},
+	})
+	return errors.Wrap(err, errCreateLock)
+}
+
+// UnlockDeletion deletes the management lock created by LockDeletion from the
+// Azure resource with the supplied ID, if it exists.
+func UnlockDeletion(ctx context.Context, c locksapi.ManagementLocksClientAPI, id string) error {
+	_, err := c.DeleteByScope(ctx, id, DeletionLockName)
+	return errors.Wrap(resource.Ignore(IsNotFound, err), errDeleteLock)
+}
+
+// ProtectDeletion returns a ConnecterWrapper that never deletes external
+// resources that are protected from deletion, and that locks them if their
+// ExternalClient is a Locker. It reports external resources that their
+// ExternalClient refuses to delete because they must be retained as the
+// Retained condition. It does not protect the external resources of
+// observe-only or dry-run managed resources, which are never deleted.
+func ProtectDeletion(r event.Recorder) ConnecterWrapper {
+	return wrapExternal(func(e managed.ExternalClient) managed.ExternalClient {
+		return &protectingExternal{ExternalClient: e, record: r}
+	})
+}
+
+type protectingExternal struct {
+	managed.ExternalClient
+	record event.Recorder
+}
+
+func (e *protectingExternal) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	o, err := e.ExternalClient.Observe(ctx, mg)
+	if err != nil || !o.ResourceExists || IsObserveOnly(mg) || IsDryRun(mg) {
+		return o, err
+	}
+	if err := e.protect(ctx, mg); err != nil {
+		return managed.ExternalObservation{}, err
+	}
+	return o, nil
+}
+
+// protect reconciles the deletion protection of the supplied managed resource's
+// external resource, locking or unlocking it if its external client is a
+// Locker.
+func (e *protectingExternal) protect(ctx context.Context, mg resource.Managed) error {
+	p, err := GetDeletionProtection(mg)
+	if err != nil {
+		return err
+	}
+	l, lockable := e.ExternalClient.(Locker)
+	c := mg.GetCondition(TypeDeletionProtected)
+	switch {
+	case p == DeletionProtectionDisabled:
+		if c.Status != corev1.ConditionTrue || (c.Reason == ReasonRetained && meta.WasDeleted(mg)) {
+			// The Retained condition is kept until the external
+			// resource is deleted.
+			return nil
+		}
+		if lockable {
+			if err := l.Unlock(ctx, mg); err != nil {
+				return err
+			}
+		}
+		mg.SetConditions(Unprotected())
+	case meta.WasDeleted(mg):
+		// The DeletionRefused condition is kept until protection is
+		// removed.
+		return nil
+	case p == DeletionProtectionLock && lockable:
+		// The lock may have been removed outside Kubernetes, so it is
+		// locked again even if the Locked condition is set.
+		locked, err := l.Lock(ctx, mg)
+		if err != nil || !locked {
+			return err
+		}
+		mg.SetConditions(Locked())
+	default:
+		want := Protected()
+		if p == DeletionProtectionLock {
+			want = ProtectedWithoutLock()
+		}
+		if c.Reason == want.Reason && c.Message == want.Message {
+			return nil
+		}
+		if lockable && c.Reason == ReasonLocked {
+			if err := l.Unlock(ctx, mg); err != nil {
+				return err
+			}
+		}
+		if p == DeletionProtectionLock {
+			e.record.Event(mg, event.Warning(reasonCannotLock, errors.New(errCannotLock)))
+		}
+		mg.SetConditions(want)
+	}
+	return nil
+}
+
+func (e *protectingExternal) Delete(ctx context.Context, mg resource.Managed) error {
+	if IsObserveOnly(mg) || IsDryRun(mg) {
+		return e.ExternalClient.Delete(ctx, mg)
+	}
+	protected, err := IsDeletionProtected(mg)
+	if err != nil {
+		// An external resource whose protection is unknown is not
+		// deleted, in case its protection was misspelled.
+		return err
+	}
+	if !protected {
+		return e.retain(mg, e.ExternalClient.Delete(ctx, mg))
+	}
+	err = ErrDeletionProtected()
+	if mg.GetCondition(TypeDeletionProtected).Reason != ReasonDeletionRefused {
+		e.record.Event(mg, event.Warning(event.Reason(ReasonDeletionRefused), err))
+	}
+	mg.SetConditions(DeletionRefused())
+	return err
+}
+
+// retain reports the supplied error of a Delete as the Retained condition, and
+// as an event when the reason the external resource is retained changes, if
+// it indicates the external client refused to delete a retained external
+// resource.
+func (e *protectingExternal) retain(mg resource.Managed, err error) error {
+	if !IsRetained(err) {
+		return err
+	}
+	want := Retained(err)
+	if c := mg.GetCondition(TypeDeletionProtected); c.Reason != want.Reason || c.Message != want.Message {
+		e.record.Event(mg, event.Warning(event.Reason(ReasonRetained), err))
+	}
+	mg.SetConditions(want)
+	return err
+}
@@ -0,0 +1,291 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azure
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/services/resources/mgmt/2016-09-01/locks"
+	"github.com/Azure/azure-sdk-for-go/services/resources/mgmt/2016-09-01/locks/locksapi"
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+type lockingExternal struct {
+	managed.ExternalClientFns
+	locked bool
+}
+
+type fakeLocks struct {
+	locksapi.ManagementLocksClientAPI
+	locks   map[string]bool
+	created int
+}
+
+func (c *fakeLocks) GetByScope(_ context.Context, scope string, name string) (locks.ManagementLockObject, error) {
+	if !c.locks[scope+"/"+name] {
+		return locks.ManagementLockObject{}, autorest.DetailedError{StatusCode: http.StatusNotFound}
+	}
+	return locks.ManagementLockObject{Name: &name}, nil
+}
+
+func (c *fakeLocks) CreateOrUpdateByScope(_ context.Context, scope string, name string, _ locks.ManagementLockObject) (locks.ManagementLockObject, error) {
+	c.locks[scope+"/"+name] = true
+	c.created++
+	return locks.ManagementLockObject{Name: &name}, nil
+}
+
+func (e *lockingExternal) Lock(_ context.Context, _ resource.Managed) (bool, error) {
+	e.locked = true
+	return true, nil
+}
+
+func (e *lockingExternal) Unlock(_ context.Context, _ resource.Managed) error {
+	e.locked = false
+	return nil
+}
+
+func TestProtectDeletion(t *testing.T) {
+	errRetained := RefuseDeletion(errors.New("cannot delete a container with a legal hold"))
+
+	type want struct {
+		reason  xpv1.ConditionReason
+		message string
+		locked  bool
+		events  []event.Event
+		err     error
+	}
+	cases := map[string]struct {
+		reason     string
+		protection DeletionProtection
+		conditions []xpv1.Condition
+		locked     bool
+		unlockable bool
+		deleted    bool
+		deleteErr  error
+		want       want
+	}{
+		"Unprotected": {
+			reason: "A resource that was never protected should not report a DeletionProtected condition",
+		},
+		"Protected": {
+			reason:     "A protected resource should report that it is protected",
+			protection: DeletionProtectionEnabled,
+			want:       want{reason: ReasonProtected},
+		},
+		"Locked": {
+			reason:     "A resource protected by a lock should be locked",
+			protection: DeletionProtectionLock,
+			want:       want{reason: ReasonLocked, message: Locked().Message, locked: true},
+		},
+		"CannotLock": {
+			reason:     "A resource whose kind cannot be locked should be protected, and report that no lock was created",
+			protection: DeletionProtectionLock,
+			unlockable: true,
+			want: want{
+				reason:  ReasonProtected,
+				message: errCannotLock,
+				events:  []event.Event{event.Warning(reasonCannotLock, errors.New(errCannotLock))},
+			},
+		},
+		"CannotLockReported": {
+			reason:     "A resource whose kind cannot be locked should report so only once",
+			protection: DeletionProtectionLock,
+			conditions: []xpv1.Condition{ProtectedWithoutLock()},
+			unlockable: true,
+			want:       want{reason: ReasonProtected, message: errCannotLock},
+		},
+		"LockRemoved": {
+			reason:     "A resource whose lock was removed outside Kubernetes should be locked again",
+			protection: DeletionProtectionLock,
+			conditions: []xpv1.Condition{Locked()},
+			want:       want{reason: ReasonLocked, message: Locked().Message, locked: true},
+		},
+		"ProtectionRemoved": {
+			reason:     "A resource whose protection was removed should be unlocked and report that it is unprotected",
+			conditions: []xpv1.Condition{Locked()},
+			locked:     true,
+			want:       want{reason: ReasonUnprotected},
+		},
+		"UnknownProtection": {
+			reason:     "A resource with an unknown protection should not be deleted",
+			protection: DeletionProtection("false"),
+			deleted:    true,
+			want:       want{err: errors.Errorf(errFmtUnknownDeletionProtection, "false")},
+		},
+		"DeletionRefused": {
+			reason:     "Deleting a protected resource should fail without deleting its external resource",
+			protection: DeletionProtectionEnabled,
+			deleted:    true,
+			want: want{
+				reason:  ReasonDeletionRefused,
+				message: errDeletionProtected,
+				events:  []event.Event{event.Warning(event.Reason(ReasonDeletionRefused), ErrDeletionProtected())},
+				err:     ErrDeletionProtected(),
+			},
+		},
+		"Retained": {
+			reason:    "An external resource that its external client refuses to delete because it must be retained should be reported",
+			deleted:   true,
+			deleteErr: errRetained,
+			want: want{
+				reason:  ReasonRetained,
+				message: errRetained.Error(),
+				events:  []event.Event{event.Warning(event.Reason(ReasonRetained), errRetained)},
+				err:     errRetained,
+			},
+		},
+		"RetainedReported": {
+			reason:     "An external resource that must be retained should be reported only once, and not as unprotected",
+			conditions: []xpv1.Condition{Retained(errRetained)},
+			deleted:    true,
+			deleteErr:  errRetained,
+			want: want{
+				reason:  ReasonRetained,
+				message: errRetained.Error(),
+				err:     errRetained,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			mg := &fake.Managed{}
+			if tc.protection != DeletionProtectionDisabled {
+				mg.SetAnnotations(map[string]string{AnnotationKeyDeletionProtection: string(tc.protection)})
+			}
+			mg.SetConditions(tc.conditions...)
+			if tc.deleted {
+				now := metav1.Now()
+				mg.SetDeletionTimestamp(&now)
+			}
+			deleted := false
+			ext := &lockingExternal{ExternalClientFns: managed.ExternalClientFns{
+				ObserveFn: func(_ context.Context, _ resource.Managed) (managed.ExternalObservation, error) {
+					return managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true}, nil
+				},
+				DeleteFn: func(_ context.Context, _ resource.Managed) error {
+					deleted = tc.deleteErr == nil
+					return tc.deleteErr
+				},
+			}, locked: tc.locked}
+			var ec managed.ExternalClient = ext
+			if tc.unlockable {
+				ec = &ext.ExternalClientFns
+			}
+			rec := &eventRecorder{}
+			e := connect(t, ec, mg, ProtectDeletion(rec))
+
+			_, err := e.Observe(context.Background(), mg)
+			if tc.deleted {
+				err = e.Delete(context.Background(), mg)
+			}
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...) and e.Delete(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if deleted && tc.want.err != nil {
+				t.Errorf("\n%s\ne.Delete(...): the external resource of a protected resource should not be deleted", tc.reason)
+			}
+			if diff := cmp.Diff(tc.want.reason, mg.GetCondition(TypeDeletionProtected).Reason); diff != "" {
+				t.Errorf("\n%s\n-want DeletionProtected reason, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.message, mg.GetCondition(TypeDeletionProtected).Message); diff != "" {
+				t.Errorf("\n%s\n-want DeletionProtected message, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.locked, ext.locked); diff != "" {
+				t.Errorf("\n%s\n-want locked, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.events, rec.events); diff != "" {
+				t.Errorf("\n%s\n-want events, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestGetDeletionProtection(t *testing.T) {
+	type want struct {
+		p   DeletionProtection
+		err error
+	}
+	cases := map[string]struct {
+		reason     string
+		annotation map[string]string
+		want       want
+	}{
+		"NoAnnotation": {
+			reason: "A resource without the annotation should not be protected",
+			want:   want{p: DeletionProtectionDisabled},
+		},
+		"Enabled": {
+			reason:     "Enabled should be a known protection",
+			annotation: map[string]string{AnnotationKeyDeletionProtection: "Enabled"},
+			want:       want{p: DeletionProtectionEnabled},
+		},
+		"Lock": {
+			reason:     "Lock should be a known protection",
+			annotation: map[string]string{AnnotationKeyDeletionProtection: "Lock"},
+			want:       want{p: DeletionProtectionLock},
+		},
+		"Disabled": {
+			reason:     "Any other value should be an error rather than protect the resource",
+			annotation: map[string]string{AnnotationKeyDeletionProtection: "Disabled"},
+			want:       want{p: DeletionProtection("Disabled"), err: errors.Errorf(errFmtUnknownDeletionProtection, "Disabled")},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			mg := &fake.Managed{}
+			mg.SetAnnotations(tc.annotation)
+			p, err := GetDeletionProtection(mg)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nGetDeletionProtection(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.p, p); diff != "" {
+				t.Errorf("\n%s\nGetDeletionProtection(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestLockDeletion(t *testing.T) {
+	id := "/subscriptions/a/resourceGroups/b/providers/Microsoft.Network/dnsZones/c"
+	c := &fakeLocks{locks: map[string]bool{}}
+
+	for i := 0; i < 2; i++ {
+		if err := LockDeletion(context.Background(), c, id); err != nil {
+			t.Fatalf("LockDeletion(...): %s", err)
+		}
+	}
+	if diff := cmp.Diff(map[string]bool{id + "/" + DeletionLockName: true}, c.locks); diff != "" {
+		t.Errorf("LockDeletion(...): -want locks, +got:\n%s", diff)
+	}
+	if diff := cmp.Diff(1, c.created); diff != "" {
+		t.Errorf("LockDeletion(...): -want locks created, +got:\n%s", diff)
+	}
+}
@@ -18,8 +18,12 @@ package redis
 
 import (
 	"reflect"
+	"sort"
+	"strings"
 
 	"github.com/Azure/azure-sdk-for-go/services/redis/mgmt/2018-03-01/redis"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
 
 	"github.com/crossplane-contrib/provider-azure/apis/cache/v1beta1"
 	azure "github.com/crossplane-contrib/provider-azure/pkg/clients"
@@ -35,11 +39,11 @@ const (
 
 // NewCreateParameters returns Redis resource creation parameters suitable for
 // use with the Azure API.
-func NewCreateParameters(cr *v1beta1.Redis) redis.CreateParameters {
+func NewCreateParameters(cr *v1beta1.Redis, defaultTags map[string]string) redis.CreateParameters {
 	return redis.CreateParameters{
 		Location: azure.ToStringPtr(cr.Spec.ForProvider.Location),
 		Zones:    azure.ToStringArrayPtr(cr.Spec.ForProvider.Zones),
-		Tags:     azure.ToStringPtrMap(cr.Spec.ForProvider.Tags),
+		Tags:     azure.NewTags(cr, defaultTags, cr.Spec.ForProvider.Tags),
 		CreateProperties: &redis.CreateProperties{
 			Sku:                NewSKU(cr.Spec.ForProvider.SKU),
 			SubnetID:           cr.Spec.ForProvider.SubnetID,
@@ -54,7 +58,8 @@ func NewCreateParameters(cr *v1beta1.Redis) redis.CreateParameters {
 }
 
 // NewUpdateParameters returns a redis.UpdateParameters object only with changed
-// fields.
+// fields. Tags are replaced as a whole, so all desired tags are returned if
+// any of them changed or an observed tag is no longer desired.
 // TODO(muvaf): Removal of an entry from the maps such as RedisConfiguration and
 // TenantSettings is not properly supported. The user has to give empty string
 // for deletion instead of just deleting the whole entry.
@@ -62,9 +67,8 @@ func NewCreateParameters(cr *v1beta1.Redis) redis.CreateParameters {
 // statements which increase the cyclomatic complexity even though it's actually
 // easier to maintain all this in one function.
 // nolint:gocyclo
-func NewUpdateParameters(spec v1beta1.RedisParameters, state redis.ResourceType) redis.UpdateParameters {
+func NewUpdateParameters(spec v1beta1.RedisParameters, state redis.ResourceType, defaultTags map[string]string) redis.UpdateParameters {
 	patch := redis.UpdateParameters{
-		Tags: azure.ToStringPtrMap(spec.Tags),
 		UpdateProperties: &redis.UpdateProperties{
 			Sku:                NewSKU(spec.SKU),
 			RedisConfiguration: azure.ToStringPtrMap(spec.RedisConfiguration),
@@ -78,14 +82,13 @@ func NewUpdateParameters(spec v1beta1.RedisParameters, state redis.ResourceType)
 	// ResourceType and extract a JSON patch. But since the number of fields
 	// are not that many, I wanted to go with if statements. Hopefully, we'll
 	// generate this code in the future.
-	for k, v := range state.Tags {
-		if patch.Tags[k] == v {
-			delete(patch.Tags, k)
+	if desired := azure.DesiredTags(defaultTags, spec.Tags); !tagsUpToDate(desired, state.Tags) {
+		// An empty map, rather than nil, removes all observed tags.
+		patch.Tags = map[string]*string{}
+		for k, v := range desired {
+			patch.Tags[k] = azure.ToStringPtr(v)
 		}
 	}
-	if len(patch.Tags) == 0 {
-		patch.Tags = nil
-	}
 	if state.Properties == nil {
 		return patch
 	}
@@ -129,16 +132,70 @@ func NewSKU(s v1beta1.SKU) *redis.Sku {
 	}
 }
 
-// NeedsUpdate returns true if the supplied spec object differs from the
-// supplied Azure resource. It considers only fields that can be modified in
-// place without deleting and recreating the instance.
-func NeedsUpdate(spec v1beta1.RedisParameters, az redis.ResourceType) bool {
+// NeedsUpdate returns the fields in which the supplied spec object differs
+// from the supplied Azure resource. It considers only fields that can be
+// modified in place without deleting and recreating the instance. The values
+// of Redis configuration settings that hold connection strings are redacted.
+func NeedsUpdate(spec v1beta1.RedisParameters, az redis.ResourceType, defaultTags map[string]string) azure.Diff {
+	d := azure.Diff{}
 	if az.Properties == nil {
-		return true
+		d.Add("sku", spec.SKU, nil)
+		return d
+	}
+	patch := NewUpdateParameters(spec, az, defaultTags)
+	if patch.Tags != nil {
+		observed := azure.ToStringPtrMap(azure.ObservedTags(az.Tags))
+		for _, k := range sortedKeys(patch.Tags, observed) {
+			if !reflect.DeepEqual(patch.Tags[k], observed[k]) {
+				d.Add("tags."+k, patch.Tags[k], observed[k])
+			}
+		}
+	}
+	if patch.Sku != nil {
+		d.Add("sku", spec.SKU, az.Sku)
+	}
+	for _, k := range sortedKeys(patch.RedisConfiguration) {
+		if strings.Contains(k, "connection-string") {
+			d.AddRedacted("redisConfiguration." + k)
+			continue
+		}
+		d.Add("redisConfiguration."+k, patch.RedisConfiguration[k], az.RedisConfiguration[k])
+	}
+	if patch.EnableNonSslPort != nil {
+		d.Add("enableNonSslPort", patch.EnableNonSslPort, az.EnableNonSslPort)
+	}
+	if patch.ShardCount != nil {
+		d.Add("shardCount", patch.ShardCount, az.ShardCount)
+	}
+	for _, k := range sortedKeys(patch.TenantSettings) {
+		d.Add("tenantSettings."+k, patch.TenantSettings[k], az.TenantSettings[k])
+	}
+	if patch.MinimumTLSVersion != "" {
+		d.Add("minimumTlsVersion", patch.MinimumTLSVersion, az.MinimumTLSVersion)
+	}
+	return d
+}
+
+// tagsUpToDate returns true if the supplied desired tags are exactly the
+// supplied observed tags, ignoring ownership tags.
+func tagsUpToDate(desired map[string]string, observed map[string]*string) bool {
+	return cmp.Equal(desired, azure.ObservedTags(observed), cmpopts.EquateEmpty())
+}
+
+// sortedKeys returns the sorted union of the keys of the supplied maps.
+func sortedKeys(ms ...map[string]*string) []string {
+	seen := map[string]bool{}
+	keys := []string{}
+	for _, m := range ms {
+		for k := range m {
+			if !seen[k] {
+				seen[k] = true
+				keys = append(keys, k)
+			}
+		}
 	}
-	patch := NewUpdateParameters(spec, az)
-	empty := redis.UpdateParameters{UpdateProperties: &redis.UpdateProperties{}}
-	return !reflect.DeepEqual(empty, patch)
+	sort.Strings(keys)
+	return keys
 }
 
 // GenerateObservation produces a RedisObservation object from the redis.ResourceType
@@ -167,9 +224,9 @@ func GenerateObservation(az redis.ResourceType) v1beta1.RedisObservation {
 
 // LateInitialize fills the spec values that user did not fill with their
 // corresponding value in the Azure, if there is any.
-func LateInitialize(spec *v1beta1.RedisParameters, az redis.ResourceType) {
+func LateInitialize(spec *v1beta1.RedisParameters, az redis.ResourceType, defaultTags map[string]string) {
 	spec.Zones = azure.LateInitializeStringValArrFromArrPtr(spec.Zones, az.Zones)
-	spec.Tags = azure.LateInitializeStringMap(spec.Tags, az.Tags)
+	spec.Tags = azure.LateInitializeTags(spec.Tags, az.Tags, defaultTags)
 	if az.Properties == nil {
 		return
 	}
@@ -21,6 +21,7 @@ import (
 
 	redismgmt "github.com/Azure/azure-sdk-for-go/services/redis/mgmt/2018-03-01/redis"
 	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/crossplane-contrib/provider-azure/apis/cache/v1beta1"
 	azure "github.com/crossplane-contrib/provider-azure/pkg/clients"
@@ -37,6 +38,7 @@ var (
 	zones              = []string{"us-east1a", "us-east1b"}
 	tags               = map[string]string{"key1": "val1"}
 	tags2              = map[string]string{"key1": "val1", "key2": "val2"}
+	defaultTags        = map[string]string{"key1": "default", "key3": "val3"}
 	enableNonSSLPort   = true
 	subnetID           = "coolsubnet"
 	staticIP           = "172.16.0.1"
@@ -63,6 +65,7 @@ func TestNewCreateParameters(t *testing.T) {
 		{
 			name: "Successful",
 			r: &v1beta1.Redis{
+				ObjectMeta: metav1.ObjectMeta{Name: resourceName},
 				Spec: v1beta1.RedisSpec{
 					ForProvider: v1beta1.RedisParameters{
 						Location: location,
@@ -86,7 +89,12 @@ func TestNewCreateParameters(t *testing.T) {
 			want: redismgmt.CreateParameters{
 				Location: azure.ToStringPtr(location),
 				Zones:    azure.ToStringArrayPtr(zones),
-				Tags:     azure.ToStringPtrMap(tags),
+				Tags: azure.ToStringPtrMap(map[string]string{
+					"key1":           "val1",
+					"key3":           "val3",
+					azure.TagKeyKind: "Redis",
+					azure.TagKeyName: resourceName,
+				}),
 				CreateProperties: &redismgmt.CreateProperties{
 					Sku: &redismgmt.Sku{
 						Name:     redismgmt.SkuName(skuName),
@@ -107,7 +115,7 @@ func TestNewCreateParameters(t *testing.T) {
 
 	for _, tc := range cases {
 		t.Run(tc.name, func(t *testing.T) {
-			got := NewCreateParameters(tc.r)
+			got := NewCreateParameters(tc.r, defaultTags)
 			if diff := cmp.Diff(tc.want, got); diff != "" {
 				t.Errorf("NewCreateParameters(...): -want, +got\n%s", diff)
 			}
@@ -224,11 +232,35 @@ func TestNewUpdateParameters(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "RemoveTags",
+			spec: v1beta1.RedisParameters{
+				SKU: v1beta1.SKU{
+					Name:     skuName,
+					Family:   skuFamily,
+					Capacity: skuCapacity,
+				},
+			},
+			current: redismgmt.ResourceType{
+				Tags: azure.ToStringPtrMap(map[string]string{"key1": "val1", azure.TagKeyName: "cool-redis"}),
+				Properties: &redismgmt.Properties{
+					Sku: &redismgmt.Sku{
+						Name:     redismgmt.SkuName(skuName),
+						Family:   redismgmt.SkuFamily(skuFamily),
+						Capacity: azure.ToInt32Ptr(skuCapacity),
+					},
+				},
+			},
+			want: redismgmt.UpdateParameters{
+				UpdateProperties: &redismgmt.UpdateProperties{},
+				Tags:             map[string]*string{},
+			},
+		},
 	}
 
 	for _, tc := range cases {
 		t.Run(tc.name, func(t *testing.T) {
-			got := NewUpdateParameters(tc.spec, tc.current)
+			got := NewUpdateParameters(tc.spec, tc.current, nil)
 			if diff := cmp.Diff(tc.want, got); diff != "" {
 				t.Errorf("NewUpdateParameters(...): -want, +got\n%s", diff)
 			}
@@ -241,7 +273,7 @@ func TestNeedsUpdate(t *testing.T) {
 		name string
 		spec v1beta1.RedisParameters
 		az   redismgmt.ResourceType
-		want bool
+		want azure.Diff
 	}{
 		{
 			name: "DifferentField",
@@ -269,7 +301,10 @@ func TestNeedsUpdate(t *testing.T) {
 					ShardCount:         azure.ToInt32Ptr(shardCount + 1),
 				},
 			},
-			want: true,
+			want: azure.Diff{
+				{Path: "tags.key2", Desired: `"val2"`, Observed: "null"},
+				{Path: "shardCount", Desired: "3", Observed: "4"},
+			},
 		},
 		{
 			name: "NoProperties",
@@ -287,7 +322,33 @@ func TestNeedsUpdate(t *testing.T) {
 			az: redismgmt.ResourceType{
 				Tags: azure.ToStringPtrMap(tags),
 			},
-			want: true,
+			want: azure.Diff{
+				{Path: "sku", Desired: `{"name":"basic","family":"C","capacity":1}`, Observed: "null"},
+			},
+		},
+		{
+			name: "RemovedTag",
+			spec: v1beta1.RedisParameters{
+				SKU: v1beta1.SKU{
+					Name:     skuName,
+					Family:   skuFamily,
+					Capacity: skuCapacity,
+				},
+				Tags: tags,
+			},
+			az: redismgmt.ResourceType{
+				Tags: azure.ToStringPtrMap(map[string]string{"key1": "val1", "key2": "val2", azure.TagKeyName: "cool-redis"}),
+				Properties: &redismgmt.Properties{
+					Sku: &redismgmt.Sku{
+						Name:     redismgmt.SkuName(skuName),
+						Family:   redismgmt.SkuFamily(skuFamily),
+						Capacity: azure.ToInt32Ptr(skuCapacity),
+					},
+				},
+			},
+			want: azure.Diff{
+				{Path: "tags.key2", Desired: "null", Observed: `"val2"`},
+			},
 		},
 		{
 			name: "NeedsNoUpdate",
@@ -313,15 +374,38 @@ func TestNeedsUpdate(t *testing.T) {
 					ShardCount:         azure.ToInt32Ptr(shardCount),
 				},
 			},
-			want: false,
+			want: azure.Diff{},
+		},
+		{
+			name: "ConnectionStringRedacted",
+			spec: v1beta1.RedisParameters{
+				SKU: v1beta1.SKU{
+					Name:     skuName,
+					Family:   skuFamily,
+					Capacity: skuCapacity,
+				},
+				RedisConfiguration: map[string]string{"rdb-storage-connection-string": "secret"},
+			},
+			az: redismgmt.ResourceType{
+				Properties: &redismgmt.Properties{
+					Sku: &redismgmt.Sku{
+						Name:     redismgmt.SkuName(skuName),
+						Family:   redismgmt.SkuFamily(skuFamily),
+						Capacity: azure.ToInt32Ptr(skuCapacity),
+					},
+				},
+			},
+			want: azure.Diff{
+				{Path: "redisConfiguration.rdb-storage-connection-string", Desired: azure.Redacted, Observed: azure.Redacted},
+			},
 		},
 	}
 
 	for _, tc := range cases {
 		t.Run(tc.name, func(t *testing.T) {
-			got := NeedsUpdate(tc.spec, tc.az)
-			if got != tc.want {
-				t.Errorf("NeedsUpdate(...): want %t, got %t", tc.want, got)
+			got := NeedsUpdate(tc.spec, tc.az, nil)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("NeedsUpdate(...): -want, +got\n%s", diff)
 			}
 		})
 	}
@@ -442,7 +526,7 @@ func TestLateInitialize(t *testing.T) {
 	}
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
-			LateInitialize(tc.args.spec, tc.args.az)
+			LateInitialize(tc.args.spec, tc.args.az, nil)
 			if diff := cmp.Diff(tc.want.spec, tc.args.spec); diff != "" {
 				t.Errorf("LateInitialize(...): -want, +got\n%s", diff)
 			}
@@ -0,0 +1,69 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azure
+
+import (
+	"context"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// nonRetryableRequeueAfter is how long to wait before reconciling a managed
+// resource again after Azure returned an error that is not retryable. A change
+// to the managed resource triggers a reconcile regardless.
+const nonRetryableRequeueAfter = 5 * time.Minute
+
+type errorCategoryKey struct{}
+
+// withErrorCategory returns a context in which recordErrorCategory records the
+// category of errors returned by Azure in the supplied ErrorCategory.
+func withErrorCategory(ctx context.Context, c *ErrorCategory) context.Context {
+	return context.WithValue(ctx, errorCategoryKey{}, c)
+}
+
+// recordErrorCategory records the category of the error returned by the most
+// recent request to Azure, if the supplied context was returned by
+// withErrorCategory.
+func recordErrorCategory(ctx context.Context, c ErrorCategory) {
+	if p, ok := ctx.Value(errorCategoryKey{}).(*ErrorCategory); ok {
+		*p = c
+	}
+}
+
+// NewRequeueReconciler wraps the supplied managed resource Reconciler such that
+// a managed resource is not retried with exponential backoff when Azure
+// returned an error that is not retryable, for example because a quota was
+// exceeded. Such a managed resource is reconciled again after a fixed period,
+// or as soon as it changes. Errors are only categorized for managed resources
+// whose ExternalConnecter was wrapped with ClassifyErrors.
+func NewRequeueReconciler(r reconcile.Reconciler) reconcile.Reconciler {
+	return &requeueReconciler{wrapped: r}
+}
+
+type requeueReconciler struct {
+	wrapped reconcile.Reconciler
+}
+
+func (r *requeueReconciler) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	c := ErrorNone
+	result, err := r.wrapped.Reconcile(withErrorCategory(ctx, &c), req)
+	if err == nil && result.Requeue && !c.Retryable() {
+		return reconcile.Result{RequeueAfter: nonRetryableRequeueAfter}, nil
+	}
+	return result, err
+}
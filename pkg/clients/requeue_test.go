@@ -0,0 +1,94 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azure
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func TestRequeueReconciler(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	type want struct {
+		result reconcile.Result
+		err    error
+	}
+	cases := map[string]struct {
+		reason  string
+		observe error
+		result  reconcile.Result
+		err     error
+		want    want
+	}{
+		"NotRetryable": {
+			reason:  "A managed resource should not be retried with backoff if Azure returned an error that is not retryable",
+			observe: requestError(http.StatusConflict, "SubscriptionQuotaExceeded"),
+			result:  reconcile.Result{Requeue: true},
+			want:    want{result: reconcile.Result{RequeueAfter: nonRetryableRequeueAfter}},
+		},
+		"Retryable": {
+			reason:  "A managed resource should be retried with backoff if Azure returned an error that is retryable",
+			observe: requestError(http.StatusConflict, "AnotherOperationInProgress"),
+			result:  reconcile.Result{Requeue: true},
+			want:    want{result: reconcile.Result{Requeue: true}},
+		},
+		"NotAzureError": {
+			reason: "A managed resource should be retried with backoff if the error was not returned by Azure",
+			result: reconcile.Result{Requeue: true},
+			want:   want{result: reconcile.Result{Requeue: true}},
+		},
+		"ReconcileError": {
+			reason:  "Errors returned by the wrapped reconciler should be returned as is",
+			observe: requestError(http.StatusForbidden, "AuthorizationFailed"),
+			err:     errBoom,
+			want:    want{err: errBoom},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			e := connect(t, &managed.ExternalClientFns{
+				ObserveFn: func(_ context.Context, _ resource.Managed) (managed.ExternalObservation, error) {
+					return managed.ExternalObservation{}, tc.observe
+				},
+			}, &fake.Managed{}, ClassifyErrors(event.NewNopRecorder()))
+			r := NewRequeueReconciler(reconcile.Func(func(ctx context.Context, _ reconcile.Request) (reconcile.Result, error) {
+				_, _ = e.Observe(ctx, &fake.Managed{})
+				return tc.result, tc.err
+			}))
+			got, err := r.Reconcile(context.Background(), reconcile.Request{})
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nr.Reconcile(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.result, got); diff != "" {
+				t.Errorf("\n%s\nr.Reconcile(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -34,6 +34,7 @@ type MockClient struct {
 	MockCheckExistence func(ctx context.Context, resourceGroupName string) (result autorest.Response, err error)
 	MockDelete         func(ctx context.Context, resourceGroupName string) (result resources.GroupsDeleteFuture, err error)
 	MockGet            func(ctx context.Context, resourceGroupName string) (result resources.Group, err error)
+	MockUpdate         func(ctx context.Context, resourceGroupName string, parameters resources.GroupPatchable) (result resources.Group, err error)
 }
 
 // CreateOrUpdate calls the underlying MockCreateOrUpdate method.
@@ -55,3 +56,8 @@ func (m *MockClient) Delete(ctx context.Context, resourceGroupName string) (resu
 func (m *MockClient) Get(ctx context.Context, resourceGroupName string) (result resources.Group, err error) {
 	return m.MockGet(ctx, resourceGroupName)
 }
+
+// Update calls the underlying MockUpdate method.
+func (m *MockClient) Update(ctx context.Context, resourceGroupName string, parameters resources.GroupPatchable) (result resources.Group, err error) {
+	return m.MockUpdate(ctx, resourceGroupName, parameters)
+}
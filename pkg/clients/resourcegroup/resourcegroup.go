@@ -17,12 +17,8 @@ limitations under the License.
 package resourcegroup
 
 import (
-	"encoding/json"
-
 	"github.com/Azure/azure-sdk-for-go/services/resources/mgmt/2018-05-01/resources"
 	"github.com/Azure/azure-sdk-for-go/services/resources/mgmt/2018-05-01/resources/resourcesapi"
-	"github.com/Azure/go-autorest/autorest/azure/auth"
-	"github.com/pkg/errors"
 
 	"github.com/crossplane/crossplane-runtime/pkg/meta"
 
@@ -33,39 +29,28 @@ import (
 // A GroupsClient handles CRUD operations for Azure Resource Group resources.
 type GroupsClient resourcesapi.GroupsClientAPI
 
-// NewClient returns a new Azure Resource Groups client. Credentials must be
-// passed as JSON encoded data.
-func NewClient(credentials []byte) (GroupsClient, error) {
-	c := azure.Credentials{}
-	if err := json.Unmarshal(credentials, &c); err != nil {
-		return nil, errors.Wrap(err, "cannot unmarshal Azure client secret data")
-	}
-	client := resources.NewGroupsClient(c.SubscriptionID)
-
-	cfg := auth.ClientCredentialsConfig{
-		ClientID:     c.ClientID,
-		ClientSecret: c.ClientSecret,
-		TenantID:     c.TenantID,
-		AADEndpoint:  c.ActiveDirectoryEndpointURL,
-		Resource:     c.ResourceManagerEndpointURL,
-	}
-	a, err := cfg.Authorizer()
-	if err != nil {
-		return nil, errors.Wrapf(err, "cannot create Azure authorizer from credentials config")
-	}
-	client.Authorizer = a
-	if err := client.AddToUserAgent(azure.UserAgent); err != nil {
-		return nil, errors.Wrap(err, "cannot add to Azure client user agent")
-	}
-
-	return client, nil
-}
-
 // NewParameters returns Resource Group resource creation parameters suitable for
 // use with the Azure API.
-func NewParameters(r *v1alpha3.ResourceGroup) resources.Group {
+func NewParameters(r *v1alpha3.ResourceGroup, defaultTags map[string]string) resources.Group {
 	return resources.Group{
 		Name:     azure.ToStringPtr(meta.GetExternalName(r)),
 		Location: azure.ToStringPtr(r.Spec.Location),
+		Tags:     azure.NewTags(r, defaultTags, r.Spec.Tags),
 	}
 }
+
+// NewPatchParameters returns Resource Group resource update parameters
+// suitable for use with the Azure API.
+func NewPatchParameters(r *v1alpha3.ResourceGroup, defaultTags map[string]string) resources.GroupPatchable {
+	return resources.GroupPatchable{
+		Tags: azure.NewTags(r, defaultTags, r.Spec.Tags),
+	}
+}
+
+// IsUpToDate returns the fields in which the supplied Azure Resource Group
+// differs from the supplied ResourceGroup. Only its tags can be updated.
+func IsUpToDate(r *v1alpha3.ResourceGroup, g resources.Group, defaultTags map[string]string) azure.Diff {
+	d := azure.Diff{}
+	d.Compare("tags", azure.DesiredTags(defaultTags, r.Spec.Tags), azure.ObservedTags(g.Tags))
+	return d
+}
@@ -53,13 +53,18 @@ func TestNewParameters(t *testing.T) {
 			want: resources.Group{
 				Name:     azure.ToStringPtr(name),
 				Location: azure.ToStringPtr(location),
+				Tags: azure.ToStringPtrMap(map[string]string{
+					"cost-center":    "42",
+					azure.TagKeyKind: "ResourceGroup",
+					azure.TagKeyName: "",
+				}),
 			},
 		},
 	}
 
 	for _, tc := range cases {
 		t.Run(tc.name, func(t *testing.T) {
-			got := NewParameters(tc.r)
+			got := NewParameters(tc.r, map[string]string{"cost-center": "42"})
 			if diff := cmp.Diff(tc.want, got); diff != "" {
 				t.Errorf("NewParameters(...): -want, +got\n%s", diff)
 			}
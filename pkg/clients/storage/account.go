@@ -18,45 +18,36 @@ package storage
 
 import (
 	"context"
-	"encoding/json"
-	"fmt"
+	"regexp"
 
 	"github.com/Azure/azure-sdk-for-go/services/storage/mgmt/2017-06-01/storage"
-	"github.com/Azure/go-autorest/autorest/azure/auth"
+	"github.com/Azure/go-autorest/autorest/date"
 	"github.com/Azure/go-autorest/autorest/to"
 	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 
+	"github.com/crossplane-contrib/provider-azure/apis/storage/v1beta1"
 	azure "github.com/crossplane-contrib/provider-azure/pkg/clients"
 )
 
-// NewStorageAccountClient create Azure storage.AccountClient using provided credentials data
-func NewStorageAccountClient(data []byte) (*storage.AccountsClient, error) {
-	creds := &azure.Credentials{}
-	if err := json.Unmarshal(data, creds); err != nil {
-		return nil, errors.Wrap(err, "cannot unmarshal Azure client secret data")
-	}
-
-	config := auth.NewClientCredentialsConfig(creds.ClientID, creds.ClientSecret, creds.TenantID)
-	config.AADEndpoint = creds.ActiveDirectoryEndpointURL
-	config.Resource = creds.ResourceManagerEndpointURL
-
-	authorizer, err := config.Authorizer()
-	if err != nil {
-		return nil, fmt.Errorf("failed to get authorizer from config: %w", err)
-	}
+// accountName matches valid storage account names: 3 to 24 lowercase letters
+// and numbers.
+var accountName = regexp.MustCompile(`^[a-z0-9]{3,24}$`)
 
-	client := storage.NewAccountsClient(creds.SubscriptionID)
-	client.Authorizer = authorizer
-
-	if err := client.AddToUserAgent(azure.UserAgent); err != nil {
-		return nil, errors.Wrap(err, "cannot add to Azure client user agent")
+// ValidateAccountName returns an error if the supplied name is not a valid
+// storage account name. Storage account names are part of their endpoints, so
+// Azure only accepts names that are valid DNS labels of limited length.
+func ValidateAccountName(name string, path *field.Path) field.ErrorList {
+	if !accountName.MatchString(name) {
+		return field.ErrorList{field.Invalid(path, name, "must be between 3 and 24 characters long and contain only lowercase letters and numbers")}
 	}
-	return &client, nil
+	return nil
 }
 
 // AccountOperations Azure storate account interface
 type AccountOperations interface {
-	Create(context.Context, storage.AccountCreateParameters) (*storage.Account, error)
+	Create(context.Context, storage.AccountCreateParameters) (storage.AccountsCreateFuture, error)
 	Update(context.Context, storage.AccountUpdateParameters) (*storage.Account, error)
 	Get(ctx context.Context) (*storage.Account, error)
 	Delete(ctx context.Context) error
@@ -82,27 +73,15 @@ func NewAccountHandle(client *storage.AccountsClient, groupName, accountName str
 	}
 }
 
-// Create create new storage account with given location
-func (a *AccountHandle) Create(ctx context.Context, params storage.AccountCreateParameters) (*storage.Account, error) {
+// Create starts creating a new storage account with the given parameters. It
+// returns the future of the creation without waiting for it to complete.
+func (a *AccountHandle) Create(ctx context.Context, params storage.AccountCreateParameters) (storage.AccountsCreateFuture, error) {
 	if err := a.IsAccountNameAvailable(ctx, a.accountName); err != nil {
-		return nil, errors.Wrapf(err, "failed to check account name availability")
+		return storage.AccountsCreateFuture{}, errors.Wrapf(err, "failed to check account name availability")
 	}
 
 	future, err := a.client.Create(ctx, a.groupName, a.accountName, params)
-	if err != nil {
-		return nil, errors.Wrapf(err, "failed to start creating storage account")
-	}
-
-	err = future.WaitForCompletionRef(ctx, a.client.Client)
-	if err != nil {
-		return nil, errors.Wrapf(err, "failed to finish creating storage account")
-	}
-
-	acct, err := future.Result(*a.client)
-	if err != nil {
-		return nil, err
-	}
-	return &acct, nil
+	return future, errors.Wrapf(err, "failed to start creating storage account")
 }
 
 // Update create new storage account with given location
@@ -157,3 +136,258 @@ func (a *AccountHandle) ListKeys(ctx context.Context) ([]storage.AccountKey, err
 
 	return *rs.Keys, nil
 }
+
+// NewAccountCreateParameters returns the parameters to create the storage
+// account of an Account with the supplied parameters. Its tags are left to the
+// caller, which must add the ownership tags of the Account.
+func NewAccountCreateParameters(p v1beta1.AccountParameters) storage.AccountCreateParameters {
+	return storage.AccountCreateParameters{
+		Sku:      newSku(p.SKU),
+		Kind:     storage.Kind(p.Kind),
+		Location: azure.ToStringPtr(p.Location),
+		Identity: newIdentity(p.Identity),
+		AccountPropertiesCreateParameters: &storage.AccountPropertiesCreateParameters{
+			AccessTier:             storage.AccessTier(azure.ToString(p.AccessTier)),
+			CustomDomain:           newCustomDomain(p.CustomDomain),
+			EnableHTTPSTrafficOnly: to.BoolPtr(azure.ToBool(p.EnableHTTPSTrafficOnly)),
+			Encryption:             newEncryption(p.Encryption),
+			NetworkRuleSet:         newNetworkRuleSet(p.NetworkRuleSet),
+		},
+	}
+}
+
+// NewAccountUpdateParameters returns the parameters to update the storage
+// account of an Account with the supplied parameters. Its tags are left to the
+// caller, which must add the ownership tags of the Account.
+func NewAccountUpdateParameters(p v1beta1.AccountParameters) storage.AccountUpdateParameters {
+	return storage.AccountUpdateParameters{
+		Sku:      newSku(p.SKU),
+		Identity: newIdentity(p.Identity),
+		AccountPropertiesUpdateParameters: &storage.AccountPropertiesUpdateParameters{
+			AccessTier:             storage.AccessTier(azure.ToString(p.AccessTier)),
+			CustomDomain:           newCustomDomain(p.CustomDomain),
+			EnableHTTPSTrafficOnly: to.BoolPtr(azure.ToBool(p.EnableHTTPSTrafficOnly)),
+			Encryption:             newEncryption(p.Encryption),
+			NetworkRuleSet:         newNetworkRuleSet(p.NetworkRuleSet),
+		},
+	}
+}
+
+// GenerateAccountObservation returns the observed state of the supplied
+// storage account.
+func GenerateAccountObservation(a storage.Account) v1beta1.AccountObservation {
+	o := v1beta1.AccountObservation{
+		ID:   azure.ToString(a.ID),
+		Name: azure.ToString(a.Name),
+		Type: azure.ToString(a.Type),
+	}
+	p := a.AccountProperties
+	if p == nil {
+		return o
+	}
+	o.CreationTime = toTime(p.CreationTime)
+	o.LastGeoFailoverTime = toTime(p.LastGeoFailoverTime)
+	o.PrimaryEndpoints = toEndpoints(p.PrimaryEndpoints)
+	o.PrimaryLocation = azure.ToString(p.PrimaryLocation)
+	o.ProvisioningState = string(p.ProvisioningState)
+	o.SecondaryEndpoints = toEndpoints(p.SecondaryEndpoints)
+	o.SecondaryLocation = azure.ToString(p.SecondaryLocation)
+	o.StatusOfPrimary = string(p.StatusOfPrimary)
+	o.StatusOfSecondary = string(p.StatusOfSecondary)
+	return o
+}
+
+// LateInitializeAccount fills the empty values of the supplied AccountParameters
+// with the ones that are retrieved from the Azure API.
+func LateInitializeAccount(p *v1beta1.AccountParameters, a storage.Account, defaultTags map[string]string) {
+	o := toAccountParameters(a)
+	if p.SKU.Tier == "" {
+		p.SKU.Tier = o.SKU.Tier
+	}
+	if p.Identity == nil {
+		p.Identity = o.Identity
+	}
+	if p.AccessTier == nil {
+		p.AccessTier = o.AccessTier
+	}
+	if p.CustomDomain == nil {
+		p.CustomDomain = o.CustomDomain
+	}
+	if p.EnableHTTPSTrafficOnly == nil {
+		p.EnableHTTPSTrafficOnly = o.EnableHTTPSTrafficOnly
+	}
+	if p.Encryption == nil {
+		p.Encryption = o.Encryption
+	}
+	if p.NetworkRuleSet == nil {
+		p.NetworkRuleSet = o.NetworkRuleSet
+	}
+	p.Tags = azure.LateInitializeTags(p.Tags, a.Tags, defaultTags)
+}
+
+// IsAccountUpToDate returns the fields in which the supplied storage account
+// is out of sync with the supplied AccountParameters. Optional parameters are
+// only compared if they are set. Its tags are compared to the desired tags,
+// i.e. the supplied default tags overridden by the tags of the
+// AccountParameters.
+func IsAccountUpToDate(p v1beta1.AccountParameters, a storage.Account, defaultTags map[string]string) azure.Diff {
+	o := toAccountParameters(a)
+	d := azure.Diff{}
+	d.Compare("sku", p.SKU, o.SKU)
+	if p.Identity != nil {
+		d.Compare("identity", p.Identity, o.Identity)
+	}
+	if p.AccessTier != nil {
+		d.Compare("accessTier", p.AccessTier, o.AccessTier)
+	}
+	if p.CustomDomain != nil {
+		d.Compare("customDomain", p.CustomDomain, o.CustomDomain)
+	}
+	if p.EnableHTTPSTrafficOnly != nil {
+		d.Compare("supportsHttpsTrafficOnly", azure.ToBool(p.EnableHTTPSTrafficOnly), azure.ToBool(o.EnableHTTPSTrafficOnly))
+	}
+	if p.Encryption != nil {
+		d.Compare("encryption", p.Encryption, o.Encryption)
+	}
+	if p.NetworkRuleSet != nil {
+		d.Compare("networkAcls", p.NetworkRuleSet, o.NetworkRuleSet)
+	}
+	d.Compare("tags", azure.DesiredTags(defaultTags, p.Tags), azure.ObservedTags(a.Tags))
+	return d
+}
+
+// toAccountParameters returns the parameters of the supplied storage account,
+// excluding its tags.
+func toAccountParameters(a storage.Account) v1beta1.AccountParameters {
+	p := v1beta1.AccountParameters{
+		Location: azure.ToString(a.Location),
+		Kind:     string(a.Kind),
+	}
+	if a.Sku != nil {
+		p.SKU = v1beta1.SKU{Name: string(a.Sku.Name), Tier: string(a.Sku.Tier)}
+	}
+	if a.Identity != nil {
+		p.Identity = &v1beta1.Identity{Type: azure.ToString(a.Identity.Type)}
+	}
+	ap := a.AccountProperties
+	if ap == nil {
+		return p
+	}
+	if ap.AccessTier != "" {
+		p.AccessTier = azure.ToStringPtr(string(ap.AccessTier))
+	}
+	if d := ap.CustomDomain; d != nil {
+		p.CustomDomain = &v1beta1.CustomDomain{Name: azure.ToString(d.Name), UseSubDomainName: azure.ToBool(d.UseSubDomainName)}
+	}
+	if ap.EnableHTTPSTrafficOnly != nil {
+		p.EnableHTTPSTrafficOnly = to.BoolPtr(*ap.EnableHTTPSTrafficOnly)
+	}
+	if e := ap.Encryption; e != nil {
+		p.Encryption = &v1beta1.Encryption{KeySource: string(e.KeySource)}
+		if s := e.Services; s != nil {
+			enabled := func(s *storage.EncryptionService) bool { return s != nil && azure.ToBool(s.Enabled) }
+			p.Encryption.Services = &v1beta1.EnabledEncryptionServices{Blob: enabled(s.Blob), File: enabled(s.File)}
+		}
+		if kv := e.KeyVaultProperties; kv != nil {
+			p.Encryption.KeyVaultProperties = &v1beta1.KeyVaultProperties{
+				KeyName:     azure.ToString(kv.KeyName),
+				KeyVersion:  azure.ToString(kv.KeyVersion),
+				KeyVaultURI: azure.ToString(kv.KeyVaultURI),
+			}
+		}
+	}
+	if n := ap.NetworkRuleSet; n != nil {
+		p.NetworkRuleSet = &v1beta1.NetworkRuleSet{Bypass: string(n.Bypass), DefaultAction: string(n.DefaultAction)}
+		if n.VirtualNetworkRules != nil {
+			for _, r := range *n.VirtualNetworkRules {
+				p.NetworkRuleSet.VirtualNetworkRules = append(p.NetworkRuleSet.VirtualNetworkRules, v1beta1.VirtualNetworkRule{VirtualNetworkResourceID: azure.ToString(r.VirtualNetworkResourceID), Action: string(r.Action)})
+			}
+		}
+		if n.IPRules != nil {
+			for _, r := range *n.IPRules {
+				p.NetworkRuleSet.IPRules = append(p.NetworkRuleSet.IPRules, v1beta1.IPRule{IPAddressOrRange: azure.ToString(r.IPAddressOrRange), Action: string(r.Action)})
+			}
+		}
+	}
+	return p
+}
+
+func newSku(s v1beta1.SKU) *storage.Sku {
+	return &storage.Sku{Name: storage.SkuName(s.Name), Tier: storage.SkuTier(s.Tier)}
+}
+
+func newIdentity(i *v1beta1.Identity) *storage.Identity {
+	if i == nil {
+		return nil
+	}
+	return &storage.Identity{Type: azure.ToStringPtr(i.Type)}
+}
+
+func newCustomDomain(d *v1beta1.CustomDomain) *storage.CustomDomain {
+	if d == nil {
+		return nil
+	}
+	return &storage.CustomDomain{Name: azure.ToStringPtr(d.Name), UseSubDomainName: to.BoolPtr(d.UseSubDomainName)}
+}
+
+func newEncryption(e *v1beta1.Encryption) *storage.Encryption {
+	if e == nil {
+		return nil
+	}
+	enc := &storage.Encryption{KeySource: storage.KeySource(e.KeySource)}
+	if s := e.Services; s != nil {
+		enc.Services = &storage.EncryptionServices{
+			Blob: &storage.EncryptionService{Enabled: to.BoolPtr(s.Blob)},
+			File: &storage.EncryptionService{Enabled: to.BoolPtr(s.File)},
+		}
+	}
+	if kv := e.KeyVaultProperties; kv != nil {
+		enc.KeyVaultProperties = &storage.KeyVaultProperties{
+			KeyName:     azure.ToStringPtr(kv.KeyName),
+			KeyVersion:  azure.ToStringPtr(kv.KeyVersion),
+			KeyVaultURI: azure.ToStringPtr(kv.KeyVaultURI),
+		}
+	}
+	return enc
+}
+
+func newNetworkRuleSet(n *v1beta1.NetworkRuleSet) *storage.NetworkRuleSet {
+	if n == nil {
+		return nil
+	}
+	s := &storage.NetworkRuleSet{Bypass: storage.Bypass(n.Bypass), DefaultAction: storage.DefaultAction(n.DefaultAction)}
+	if len(n.VirtualNetworkRules) > 0 {
+		r := make([]storage.VirtualNetworkRule, len(n.VirtualNetworkRules))
+		for i, v := range n.VirtualNetworkRules {
+			r[i] = storage.VirtualNetworkRule{VirtualNetworkResourceID: azure.ToStringPtr(v.VirtualNetworkResourceID), Action: storage.Action(v.Action)}
+		}
+		s.VirtualNetworkRules = &r
+	}
+	if len(n.IPRules) > 0 {
+		r := make([]storage.IPRule, len(n.IPRules))
+		for i, v := range n.IPRules {
+			r[i] = storage.IPRule{IPAddressOrRange: azure.ToStringPtr(v.IPAddressOrRange), Action: storage.Action(v.Action)}
+		}
+		s.IPRules = &r
+	}
+	return s
+}
+
+func toEndpoints(e *storage.Endpoints) *v1beta1.Endpoints {
+	if e == nil {
+		return nil
+	}
+	return &v1beta1.Endpoints{
+		Blob:  azure.ToString(e.Blob),
+		Queue: azure.ToString(e.Queue),
+		Table: azure.ToString(e.Table),
+		File:  azure.ToString(e.File),
+	}
+}
+
+func toTime(t *date.Time) *metav1.Time {
+	if t == nil {
+		return nil
+	}
+	return &metav1.Time{Time: t.Time}
+}
@@ -18,59 +18,20 @@ package storage
 
 import (
 	"testing"
+	"time"
 
 	"github.com/Azure/azure-sdk-for-go/services/storage/mgmt/2017-06-01/storage"
+	"github.com/Azure/go-autorest/autorest/date"
+	"github.com/Azure/go-autorest/autorest/to"
 	"github.com/google/go-cmp/cmp"
-	"github.com/pkg/errors"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 
-	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/crossplane-contrib/provider-azure/apis/storage/v1beta1"
+	azure "github.com/crossplane-contrib/provider-azure/pkg/clients"
 )
 
-func TestNewStorageAccountClient(t *testing.T) {
-	tests := []struct {
-		name    string
-		args    []byte
-		wantRes *storage.AccountsClient
-		wantErr error
-	}{
-		{
-			name:    "EmptyData",
-			args:    []byte{},
-			wantRes: nil,
-			wantErr: errors.WithStack(errors.New("cannot unmarshal Azure client secret data: unexpected end of JSON input")),
-		},
-		{
-			name: "Success",
-			args: []byte(`{"clientId": "0f32e96b-b9a4-49ce-a857-243a33b20e5c",
-	"clientSecret": "49d8cab5-d47a-4d1a-9133-5c5db29c345d",
-	"subscriptionId": "bf1b0e59-93da-42e0-82c6-5a1d94227911",
-	"tenantId": "302de427-dba9-4452-8583-a4268e46de6b",
-	"activeDirectoryEndpointUrl": "https://login.microsoftonline.com",
-	"resourceManagerEndpointUrl": "https://management.azure.com/",
-	"activeDirectoryGraphResourceId": "https://graph.windows.net/",
-	"sqlManagementEndpointUrl": "https://management.core.windows.net:8443/",
-	"galleryEndpointUrl": "https://gallery.azure.com/",
-	"managementEndpointUrl": "https://management.core.windows.net/"}`),
-			wantRes: &storage.AccountsClient{},
-			wantErr: nil,
-		},
-	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			got, err := NewStorageAccountClient(tt.args)
-			if diff := cmp.Diff(err, tt.wantErr, test.EquateErrors()); diff != "" {
-				t.Errorf("NewStorageAccountClient() error = %v, wantErr %v\n%s", err, tt.wantErr, diff)
-			}
-			if err != nil && got != nil {
-				t.Errorf("NewStorageAccountClient() %v, want nil", got)
-			}
-			if err == nil && got == nil {
-				t.Errorf("NewStorageAccountClient() %v, want not nil", got)
-			}
-		})
-	}
-}
-
 func TestNewAccountHandle(t *testing.T) {
 	type args struct {
 		client      *storage.AccountsClient
@@ -105,3 +66,274 @@ func TestNewAccountHandle(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateAccountName(t *testing.T) {
+	cases := []struct {
+		name  string
+		valid bool
+	}{
+		{"coolaccount42", true},
+		{"ab", false},
+		{"cool-account", false},
+		{"CoolAccount", false},
+		{"averyveryverycoolaccount42", false},
+	}
+
+	for _, tt := range cases {
+		got := ValidateAccountName(tt.name, field.NewPath("metadata", "name"))
+		if (len(got) == 0) != tt.valid {
+			t.Errorf("ValidateAccountName(%q) = %v, want valid %t", tt.name, got, tt.valid)
+		}
+	}
+}
+
+func TestNewAccountCreateParameters(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		p      v1beta1.AccountParameters
+		want   storage.AccountCreateParameters
+	}{
+		"Minimal": {
+			reason: "HTTPS-only traffic should always be sent, and unset optional parameters should not",
+			p: v1beta1.AccountParameters{
+				Location: "westus",
+				Kind:     "Storage",
+				SKU:      v1beta1.SKU{Name: "Standard_LRS"},
+			},
+			want: storage.AccountCreateParameters{
+				Sku:      &storage.Sku{Name: storage.StandardLRS},
+				Kind:     storage.Storage,
+				Location: azure.ToStringPtr("westus"),
+				AccountPropertiesCreateParameters: &storage.AccountPropertiesCreateParameters{
+					EnableHTTPSTrafficOnly: to.BoolPtr(false),
+				},
+			},
+		},
+		"Full": {
+			reason: "All parameters should be converted to their Azure representation",
+			p: v1beta1.AccountParameters{
+				Location:               "westus",
+				Kind:                   "BlobStorage",
+				SKU:                    v1beta1.SKU{Name: "Standard_GRS", Tier: "Standard"},
+				Identity:               &v1beta1.Identity{Type: "SystemAssigned"},
+				AccessTier:             azure.ToStringPtr("Cool"),
+				CustomDomain:           &v1beta1.CustomDomain{Name: "example.org", UseSubDomainName: true},
+				EnableHTTPSTrafficOnly: to.BoolPtr(true),
+				Encryption: &v1beta1.Encryption{
+					Services:           &v1beta1.EnabledEncryptionServices{Blob: true},
+					KeySource:          "Microsoft.Keyvault",
+					KeyVaultProperties: &v1beta1.KeyVaultProperties{KeyName: "key", KeyVersion: "1", KeyVaultURI: "https://vault"},
+				},
+				NetworkRuleSet: &v1beta1.NetworkRuleSet{
+					Bypass:              "AzureServices",
+					DefaultAction:       "Deny",
+					VirtualNetworkRules: []v1beta1.VirtualNetworkRule{{VirtualNetworkResourceID: "subnet", Action: "Allow"}},
+					IPRules:             []v1beta1.IPRule{{IPAddressOrRange: "10.0.0.0/8", Action: "Allow"}},
+				},
+			},
+			want: storage.AccountCreateParameters{
+				Sku:      &storage.Sku{Name: storage.StandardGRS, Tier: storage.Standard},
+				Kind:     storage.BlobStorage,
+				Location: azure.ToStringPtr("westus"),
+				Identity: &storage.Identity{Type: azure.ToStringPtr("SystemAssigned")},
+				AccountPropertiesCreateParameters: &storage.AccountPropertiesCreateParameters{
+					AccessTier:             storage.Cool,
+					CustomDomain:           &storage.CustomDomain{Name: azure.ToStringPtr("example.org"), UseSubDomainName: to.BoolPtr(true)},
+					EnableHTTPSTrafficOnly: to.BoolPtr(true),
+					Encryption: &storage.Encryption{
+						Services: &storage.EncryptionServices{
+							Blob: &storage.EncryptionService{Enabled: to.BoolPtr(true)},
+							File: &storage.EncryptionService{Enabled: to.BoolPtr(false)},
+						},
+						KeySource:          storage.MicrosoftKeyvault,
+						KeyVaultProperties: &storage.KeyVaultProperties{KeyName: azure.ToStringPtr("key"), KeyVersion: azure.ToStringPtr("1"), KeyVaultURI: azure.ToStringPtr("https://vault")},
+					},
+					NetworkRuleSet: &storage.NetworkRuleSet{
+						Bypass:              storage.AzureServices,
+						DefaultAction:       storage.DefaultActionDeny,
+						VirtualNetworkRules: &[]storage.VirtualNetworkRule{{VirtualNetworkResourceID: azure.ToStringPtr("subnet"), Action: storage.Allow}},
+						IPRules:             &[]storage.IPRule{{IPAddressOrRange: azure.ToStringPtr("10.0.0.0/8"), Action: storage.Allow}},
+					},
+				},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := NewAccountCreateParameters(tc.p)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nNewAccountCreateParameters(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestGenerateAccountObservation(t *testing.T) {
+	created := time.Date(2022, 4, 1, 0, 0, 0, 0, time.UTC)
+
+	cases := map[string]struct {
+		reason string
+		a      storage.Account
+		want   v1beta1.AccountObservation
+	}{
+		"NoProperties": {
+			reason: "A storage account without properties should only be observed by its identity",
+			a:      storage.Account{ID: azure.ToStringPtr("id"), Name: azure.ToStringPtr("coolaccount")},
+			want:   v1beta1.AccountObservation{ID: "id", Name: "coolaccount"},
+		},
+		"Full": {
+			reason: "The properties of a storage account should be observed",
+			a: storage.Account{
+				ID:   azure.ToStringPtr("id"),
+				Name: azure.ToStringPtr("coolaccount"),
+				Type: azure.ToStringPtr("Microsoft.Storage/storageAccounts"),
+				AccountProperties: &storage.AccountProperties{
+					CreationTime:      &date.Time{Time: created},
+					PrimaryEndpoints:  &storage.Endpoints{Blob: azure.ToStringPtr("https://coolaccount.blob.core.windows.net/")},
+					PrimaryLocation:   azure.ToStringPtr("westus"),
+					ProvisioningState: storage.Succeeded,
+					StatusOfPrimary:   storage.Available,
+				},
+			},
+			want: v1beta1.AccountObservation{
+				ID:                "id",
+				Name:              "coolaccount",
+				Type:              "Microsoft.Storage/storageAccounts",
+				CreationTime:      &metav1.Time{Time: created},
+				PrimaryEndpoints:  &v1beta1.Endpoints{Blob: "https://coolaccount.blob.core.windows.net/"},
+				PrimaryLocation:   "westus",
+				ProvisioningState: "Succeeded",
+				StatusOfPrimary:   "available",
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := GenerateAccountObservation(tc.a)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nGenerateAccountObservation(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestLateInitializeAccount(t *testing.T) {
+	observed := storage.Account{
+		Sku:      &storage.Sku{Name: storage.StandardLRS, Tier: storage.Standard},
+		Identity: &storage.Identity{Type: azure.ToStringPtr("SystemAssigned")},
+		Tags: map[string]*string{
+			"env":            azure.ToStringPtr("prod"),
+			azure.TagKeyKind: azure.ToStringPtr("Account"),
+		},
+		AccountProperties: &storage.AccountProperties{
+			AccessTier:             storage.Hot,
+			EnableHTTPSTrafficOnly: to.BoolPtr(true),
+			Encryption: &storage.Encryption{
+				KeySource: storage.MicrosoftStorage,
+				Services:  &storage.EncryptionServices{Blob: &storage.EncryptionService{Enabled: to.BoolPtr(true)}},
+			},
+		},
+	}
+
+	cases := map[string]struct {
+		reason string
+		p      v1beta1.AccountParameters
+		want   v1beta1.AccountParameters
+	}{
+		"Empty": {
+			reason: "Unset parameters should be late-initialized, except for ownership tags",
+			p:      v1beta1.AccountParameters{SKU: v1beta1.SKU{Name: "Standard_LRS"}},
+			want: v1beta1.AccountParameters{
+				SKU:                    v1beta1.SKU{Name: "Standard_LRS", Tier: "Standard"},
+				Identity:               &v1beta1.Identity{Type: "SystemAssigned"},
+				AccessTier:             azure.ToStringPtr("Hot"),
+				EnableHTTPSTrafficOnly: to.BoolPtr(true),
+				Encryption: &v1beta1.Encryption{
+					KeySource: "Microsoft.Storage",
+					Services:  &v1beta1.EnabledEncryptionServices{Blob: true},
+				},
+				Tags: map[string]string{"env": "prod"},
+			},
+		},
+		"AlreadySet": {
+			reason: "Parameters that are already set should not be late-initialized",
+			p: v1beta1.AccountParameters{
+				SKU:                    v1beta1.SKU{Name: "Standard_LRS", Tier: "Premium"},
+				AccessTier:             azure.ToStringPtr("Cool"),
+				EnableHTTPSTrafficOnly: to.BoolPtr(false),
+				Tags:                   map[string]string{"env": "test"},
+			},
+			want: v1beta1.AccountParameters{
+				SKU:                    v1beta1.SKU{Name: "Standard_LRS", Tier: "Premium"},
+				Identity:               &v1beta1.Identity{Type: "SystemAssigned"},
+				AccessTier:             azure.ToStringPtr("Cool"),
+				EnableHTTPSTrafficOnly: to.BoolPtr(false),
+				Encryption: &v1beta1.Encryption{
+					KeySource: "Microsoft.Storage",
+					Services:  &v1beta1.EnabledEncryptionServices{Blob: true},
+				},
+				Tags: map[string]string{"env": "test"},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			LateInitializeAccount(&tc.p, observed, nil)
+			if diff := cmp.Diff(tc.want, tc.p); diff != "" {
+				t.Errorf("\n%s\nLateInitializeAccount(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestIsAccountUpToDate(t *testing.T) {
+	observed := storage.Account{
+		Sku: &storage.Sku{Name: storage.StandardLRS, Tier: storage.Standard},
+		Tags: map[string]*string{
+			"cost-center":    azure.ToStringPtr("42"),
+			azure.TagKeyKind: azure.ToStringPtr("Account"),
+		},
+		AccountProperties: &storage.AccountProperties{
+			AccessTier:             storage.Hot,
+			EnableHTTPSTrafficOnly: to.BoolPtr(false),
+		},
+	}
+	defaults := map[string]string{"cost-center": "42"}
+
+	cases := map[string]struct {
+		reason string
+		p      v1beta1.AccountParameters
+		want   []string
+	}{
+		"UpToDate": {
+			reason: "Unset optional parameters, ownership tags and default tags should not be reported as drift",
+			p:      v1beta1.AccountParameters{SKU: v1beta1.SKU{Name: "Standard_LRS", Tier: "Standard"}},
+		},
+		"NotUpToDate": {
+			reason: "Parameters that differ from the storage account should be reported as drift",
+			p: v1beta1.AccountParameters{
+				SKU:                    v1beta1.SKU{Name: "Standard_GRS", Tier: "Standard"},
+				AccessTier:             azure.ToStringPtr("Cool"),
+				EnableHTTPSTrafficOnly: to.BoolPtr(true),
+				Tags:                   map[string]string{"env": "prod"},
+			},
+			want: []string{"sku", "accessTier", "supportsHttpsTrafficOnly", "tags"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			d := IsAccountUpToDate(tc.p, observed, defaults)
+			got := make([]string, len(d))
+			for i, f := range d {
+				got[i] = f.Path
+			}
+			if diff := cmp.Diff(tc.want, got, cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("\n%s\nIsAccountUpToDate(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -17,94 +17,282 @@ limitations under the License.
 package storage
 
 import (
-	"context"
-	"errors"
-	"fmt"
-	"net/http"
-	"net/url"
+	"regexp"
+	"sort"
+	"strings"
 
-	"github.com/Azure/azure-storage-blob-go/azblob"
+	"github.com/Azure/azure-sdk-for-go/services/storage/mgmt/2021-04-01/storage"
+	"github.com/Azure/go-autorest/autorest/to"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+
+	"github.com/crossplane-contrib/provider-azure/apis/storage/v1beta1"
 	azure "github.com/crossplane-contrib/provider-azure/pkg/clients"
 )
 
-// ContainerOperations interface to perform operations on Container resources
-type ContainerOperations interface {
-	Create(ctx context.Context, publicAccessType azblob.PublicAccessType, metadata azblob.Metadata) error
-	Update(ctx context.Context, publicAccessType azblob.PublicAccessType, metadata azblob.Metadata) error
-	Get(ctx context.Context) (*azblob.PublicAccessType, azblob.Metadata, error)
-	Delete(ctx context.Context) error
+// containerName matches valid container names: lowercase letters, numbers
+// and single hyphens, starting and ending with a letter or number. The root
+// and static website containers have reserved names.
+var containerName = regexp.MustCompile(`^(\$root|\$web|[a-z0-9]+(-[a-z0-9]+)*)$`)
+
+// ValidateContainerName returns an error if the supplied name is not a valid
+// blob container name.
+func ValidateContainerName(name string, path *field.Path) field.ErrorList {
+	if len(name) < 3 || len(name) > 63 || !containerName.MatchString(name) {
+		return field.ErrorList{field.Invalid(path, name, "must be between 3 and 63 characters long, contain only lowercase letters, numbers and hyphens, and start and end with a letter or number; consecutive hyphens are not permitted")}
+	}
+	return nil
+}
+
+// legalHoldTag matches valid legal hold tags.
+var legalHoldTag = regexp.MustCompile(`^[a-zA-Z0-9]{3,23}$`)
+
+// ValidateLegalHold returns an error for each tag of the supplied legal hold
+// that is not a valid legal hold tag.
+func ValidateLegalHold(lh *v1beta1.LegalHold, path *field.Path) field.ErrorList {
+	if lh == nil {
+		return nil
+	}
+	var errs field.ErrorList
+	for i, t := range lh.Tags {
+		if !legalHoldTag.MatchString(t) {
+			errs = append(errs, field.Invalid(path.Child("tags").Index(i), t, "must be between 3 and 23 characters long and contain only letters and numbers"))
+		}
+	}
+	return errs
+}
+
+// NewBlobContainer returns the blob container of a Container with the supplied
+// parameters.
+func NewBlobContainer(p v1beta1.ContainerParameters) storage.BlobContainer {
+	return storage.BlobContainer{
+		ContainerProperties: &storage.ContainerProperties{
+			PublicAccess: publicAccess(p.PublicAccessType),
+			Metadata:     azure.ToStringPtrMap(p.Metadata),
+		},
+	}
+}
+
+// GenerateContainerObservation returns the observed state of the supplied
+// blob container.
+func GenerateContainerObservation(c storage.BlobContainer) v1beta1.ContainerObservation {
+	o := v1beta1.ContainerObservation{ID: azure.ToString(c.ID)}
+	p := c.ContainerProperties
+	if p == nil {
+		return o
+	}
+	if p.LastModifiedTime != nil {
+		o.LastModified = &metav1.Time{Time: p.LastModifiedTime.Time}
+	}
+	o.LeaseState = string(p.LeaseState)
+	o.LeaseStatus = string(p.LeaseStatus)
+	o.HasImmutabilityPolicy = azure.ToBool(p.HasImmutabilityPolicy)
+	o.HasLegalHold = azure.ToBool(p.HasLegalHold)
+	if ip := p.ImmutabilityPolicy; ip != nil && ip.ImmutabilityPolicyProperty != nil {
+		o.ImmutabilityPolicy = &v1beta1.ImmutabilityPolicyObservation{
+			ImmutabilityPeriodSinceCreationInDays: to.Int32(ip.ImmutabilityPeriodSinceCreationInDays),
+			AllowProtectedAppendWrites:            azure.ToBool(ip.AllowProtectedAppendWrites),
+			State:                                 string(ip.State),
+			Etag:                                  azure.ToString(ip.Etag),
+		}
+	}
+	o.LegalHoldTags = legalHoldTags(p)
+	return o
+}
+
+// AnnotationKeyImmutabilityPolicy is set on a Container once its immutability
+// policy has been specified or late-initialized. The policy of a Container
+// with this annotation is deleted, rather than late-initialized again, when it
+// is removed from the Container.
+const AnnotationKeyImmutabilityPolicy = "storage.azure.crossplane.io/immutability-policy"
+
+// LateInitializeContainer fills the unassigned fields of the supplied
+// Container with the immutability policy and legal hold of the supplied blob
+// container, so that existing retention settings are never removed from a
+// container that is imported. It annotates a Container that has an
+// immutability policy with AnnotationKeyImmutabilityPolicy.
+func LateInitializeContainer(cr *v1beta1.Container, c storage.BlobContainer) {
+	p := &cr.Spec.ForProvider
+	o := GenerateContainerObservation(c)
+	if p.ImmutabilityPolicy == nil && o.ImmutabilityPolicy != nil && !hasImmutabilityPolicyAnnotation(cr) {
+		p.ImmutabilityPolicy = &v1beta1.ImmutabilityPolicy{
+			ImmutabilityPeriodSinceCreationInDays: o.ImmutabilityPolicy.ImmutabilityPeriodSinceCreationInDays,
+			AllowProtectedAppendWrites:            to.BoolPtr(o.ImmutabilityPolicy.AllowProtectedAppendWrites),
+			Locked:                                to.BoolPtr(o.ImmutabilityPolicy.State == string(storage.ImmutabilityPolicyStateLocked)),
+		}
+	}
+	if p.LegalHold == nil && len(o.LegalHoldTags) > 0 {
+		p.LegalHold = &v1beta1.LegalHold{Tags: o.LegalHoldTags}
+	}
+	if p.ImmutabilityPolicy != nil {
+		meta.AddAnnotations(cr, map[string]string{AnnotationKeyImmutabilityPolicy: "true"})
+	}
+}
+
+// DesiredImmutabilityPolicy returns the immutability policy that the supplied
+// Container specifies. It returns an empty policy if the Container's policy
+// was removed after it was annotated with AnnotationKeyImmutabilityPolicy, and
+// nil if the Container does not manage its policy.
+func DesiredImmutabilityPolicy(cr *v1beta1.Container) *v1beta1.ImmutabilityPolicy {
+	if ip := cr.Spec.ForProvider.ImmutabilityPolicy; ip != nil || !hasImmutabilityPolicyAnnotation(cr) {
+		return ip
+	}
+	return &v1beta1.ImmutabilityPolicy{}
 }
 
-// ContainerHandle implements ContainerOperations
-type ContainerHandle struct {
-	azblob.ContainerURL
-	PublicAccessType azblob.PublicAccessType
+// IsImmutabilityPolicyEmpty returns true if the supplied immutability policy
+// has no retention period, i.e. if it specifies that there is no policy.
+func IsImmutabilityPolicyEmpty(p v1beta1.ImmutabilityPolicy) bool {
+	return p.ImmutabilityPeriodSinceCreationInDays == 0
 }
 
-var _ ContainerOperations = &ContainerHandle{}
+func hasImmutabilityPolicyAnnotation(cr *v1beta1.Container) bool {
+	_, ok := cr.GetAnnotations()[AnnotationKeyImmutabilityPolicy]
+	return ok
+}
 
-const blobFormatString = `https://%s.blob.core.windows.net`
+// IsContainerUpToDate returns the fields in which the supplied blob container
+// is out of sync with the supplied ContainerParameters.
+func IsContainerUpToDate(p v1beta1.ContainerParameters, c storage.BlobContainer) azure.Diff {
+	o := storage.ContainerProperties{}
+	if c.ContainerProperties != nil {
+		o = *c.ContainerProperties
+	}
+	d := azure.Diff{}
+	d.Compare("publicAccessType", publicAccess(p.PublicAccessType), observedPublicAccess(o))
+	d.Compare("metadata", emptyToNil(p.Metadata), emptyToNil(azure.ToStringMap(o.Metadata)))
+	if ip := p.ImmutabilityPolicy; ip != nil {
+		compareImmutabilityPolicy(&d, *ip, GenerateContainerObservation(c).ImmutabilityPolicy)
+	}
+	if p.LegalHold != nil {
+		d.Compare("legalHold.tags", normalizeTags(p.LegalHold.Tags), normalizeTags(legalHoldTags(&o)))
+	}
+	return d
+}
 
-// NewContainerHandle creates a new instance of ContainerHandle for given storage account and given container name
-func NewContainerHandle(accountName, accountKey, containerName string) (*ContainerHandle, error) {
-	c, err := azblob.NewSharedKeyCredential(accountName, accountKey)
-	if err != nil {
-		return nil, err
+// compareImmutabilityPolicy adds the fields in which the supplied observed
+// immutability policy is out of sync with the supplied desired policy to the
+// supplied Diff.
+func compareImmutabilityPolicy(d *azure.Diff, p v1beta1.ImmutabilityPolicy, o *v1beta1.ImmutabilityPolicyObservation) {
+	if IsImmutabilityPolicyEmpty(p) {
+		if o != nil {
+			d.Add("immutabilityPolicy", nil, o)
+		}
+		return
+	}
+	if o == nil {
+		d.Add("immutabilityPolicy", p, nil)
+		return
+	}
+	d.Compare("immutabilityPolicy.immutabilityPeriodSinceCreationInDays", p.ImmutabilityPeriodSinceCreationInDays, o.ImmutabilityPeriodSinceCreationInDays)
+	if p.AllowProtectedAppendWrites != nil {
+		d.Compare("immutabilityPolicy.allowProtectedAppendWrites", *p.AllowProtectedAppendWrites, o.AllowProtectedAppendWrites)
 	}
+	d.Compare("immutabilityPolicy.locked", azure.ToBool(p.Locked), IsImmutabilityPolicyLocked(o))
+}
 
-	p := azblob.NewPipeline(c, azblob.PipelineOptions{
-		Telemetry: azblob.TelemetryOptions{Value: azure.UserAgent},
-	})
+// NewImmutabilityPolicy returns the immutability policy of a container with
+// the supplied ImmutabilityPolicy.
+func NewImmutabilityPolicy(p v1beta1.ImmutabilityPolicy) *storage.ImmutabilityPolicy {
+	return &storage.ImmutabilityPolicy{
+		ImmutabilityPolicyProperty: &storage.ImmutabilityPolicyProperty{
+			ImmutabilityPeriodSinceCreationInDays: to.Int32Ptr(p.ImmutabilityPeriodSinceCreationInDays),
+			AllowProtectedAppendWrites:            p.AllowProtectedAppendWrites,
+		},
+	}
+}
 
-	u, _ := url.Parse(fmt.Sprintf(blobFormatString, accountName))
-	service := azblob.NewServiceURL(*u, p)
+// IsImmutabilityPolicyLocked returns true if the supplied immutability policy
+// exists and is locked.
+func IsImmutabilityPolicyLocked(o *v1beta1.ImmutabilityPolicyObservation) bool {
+	return o != nil && o.State == string(storage.ImmutabilityPolicyStateLocked)
+}
 
-	return &ContainerHandle{
-		ContainerURL: service.NewContainerURL(containerName),
-	}, nil
+// DiffLegalHoldTags returns the desired legal hold tags that are not observed,
+// and the observed legal hold tags that are not desired. Tags are compared
+// case-insensitively.
+func DiffLegalHoldTags(desired, observed []string) (set, clear []string) {
+	desired, observed = normalizeTags(desired), normalizeTags(observed)
+	d, o := map[string]bool{}, map[string]bool{}
+	for _, t := range desired {
+		d[t] = true
+	}
+	for _, t := range observed {
+		o[t] = true
+		if !d[t] {
+			clear = append(clear, t)
+		}
+	}
+	for _, t := range desired {
+		if !o[t] {
+			set = append(set, t)
+		}
+	}
+	return set, clear
 }
 
-// Create container resource
-func (a *ContainerHandle) Create(ctx context.Context, publicAccessType azblob.PublicAccessType, metadata azblob.Metadata) error {
-	_, err := a.ContainerURL.Create(ctx, azblob.Metadata{}, publicAccessType)
-	return err
+// legalHoldTags returns the legal hold tags of the supplied container
+// properties.
+func legalHoldTags(p *storage.ContainerProperties) []string {
+	if p.LegalHold == nil || p.LegalHold.Tags == nil {
+		return nil
+	}
+	var tags []string
+	for _, t := range *p.LegalHold.Tags {
+		if t.Tag != nil {
+			tags = append(tags, *t.Tag)
+		}
+	}
+	return tags
 }
 
-// Update container resource
-func (a *ContainerHandle) Update(ctx context.Context, publicAccessType azblob.PublicAccessType, metadata azblob.Metadata) error {
-	if _, err := a.ContainerURL.SetMetadata(ctx, metadata, azblob.ContainerAccessConditions{}); err != nil {
-		return err
+// normalizeTags returns the supplied legal hold tags in lower case, sorted
+// and without duplicates, the way Azure stores them.
+func normalizeTags(tags []string) []string {
+	if len(tags) == 0 {
+		return nil
+	}
+	seen := map[string]bool{}
+	n := make([]string, 0, len(tags))
+	for _, t := range tags {
+		t = strings.ToLower(t)
+		if !seen[t] {
+			seen[t] = true
+			n = append(n, t)
+		}
 	}
-	_, err := a.ContainerURL.SetAccessPolicy(ctx, publicAccessType, nil, azblob.ContainerAccessConditions{})
-	return err
+	sort.Strings(n)
+	return n
 }
 
-// Get resource information
-func (a *ContainerHandle) Get(ctx context.Context) (*azblob.PublicAccessType, azblob.Metadata, error) {
-	rs, err := a.ContainerURL.GetProperties(ctx, azblob.LeaseAccessConditions{})
-	if err != nil {
-		return nil, nil, err
+// publicAccess returns the public access of a container with the supplied
+// public access type. Containers without a public access type are private.
+func publicAccess(t string) storage.PublicAccess {
+	switch t {
+	case "":
+		return storage.PublicAccessNone
+	case "blob":
+		return storage.PublicAccessBlob
+	case "container":
+		return storage.PublicAccessContainer
 	}
-	publicAccess := rs.BlobPublicAccess()
-	return &publicAccess, emtpyMetaToNil(rs.NewMetadata()), nil
+	return storage.PublicAccess(t)
 }
 
-// Delete deletes the named container.
-func (a *ContainerHandle) Delete(ctx context.Context) error {
-	_, err := a.ContainerURL.Delete(ctx, azblob.ContainerAccessConditions{})
-	return err
+// observedPublicAccess returns the public access of the supplied container
+// properties. Containers that do not report one are private.
+func observedPublicAccess(p storage.ContainerProperties) storage.PublicAccess {
+	if p.PublicAccess == "" {
+		return storage.PublicAccessNone
+	}
+	return p.PublicAccess
 }
 
-func emtpyMetaToNil(m azblob.Metadata) azblob.Metadata {
+func emptyToNil(m map[string]string) map[string]string {
 	if len(m) == 0 {
 		return nil
 	}
 	return m
 }
-
-// IsNotFoundError tests for azblob not found error
-func IsNotFoundError(err error) bool {
-	var sErr azblob.StorageError
-	return errors.As(err, &sErr) && sErr.Response().StatusCode == http.StatusNotFound // nolint: bodyclose
-}
@@ -1,56 +1,384 @@
 package storage
 
 import (
-	"net/http"
 	"testing"
+	"time"
 
-	"github.com/Azure/azure-storage-blob-go/azblob"
+	"github.com/Azure/azure-sdk-for-go/services/storage/mgmt/2021-04-01/storage"
+	"github.com/Azure/go-autorest/autorest/date"
+	"github.com/Azure/go-autorest/autorest/to"
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 
-	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane-contrib/provider-azure/apis/storage/v1beta1"
+	azure "github.com/crossplane-contrib/provider-azure/pkg/clients"
 )
 
-type storageError struct{}
+func TestValidateContainerName(t *testing.T) {
+	cases := []struct {
+		name  string
+		valid bool
+	}{
+		{"cool-container-42", true},
+		{"$web", true},
+		{"ab", false},
+		{"-cool", false},
+		{"cool-", false},
+		{"cool--container", false},
+		{"Cool", false},
+	}
 
-func (s *storageError) ServiceCode() azblob.ServiceCodeType {
-	return "boom"
+	for _, tt := range cases {
+		got := ValidateContainerName(tt.name, field.NewPath("metadata", "name"))
+		if (len(got) == 0) != tt.valid {
+			t.Errorf("ValidateContainerName(%q) = %v, want valid %t", tt.name, got, tt.valid)
+		}
+	}
 }
 
-func (s *storageError) Error() string {
-	return "boom"
+func TestValidateLegalHold(t *testing.T) {
+	cases := map[string]struct {
+		lh   *v1beta1.LegalHold
+		want field.ErrorList
+	}{
+		"Unset": {},
+		"Valid": {lh: &v1beta1.LegalHold{Tags: []string{"Audit2022"}}},
+		"Invalid": {
+			lh: &v1beta1.LegalHold{Tags: []string{"audit", "no"}},
+			want: field.ErrorList{
+				field.Invalid(field.NewPath("legalHold", "tags").Index(1), "no", "must be between 3 and 23 characters long and contain only letters and numbers"),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := ValidateLegalHold(tc.lh, field.NewPath("legalHold"))
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("ValidateLegalHold(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
 }
 
-func (s *storageError) Timeout() bool {
-	return false
+func TestNewBlobContainer(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		p      v1beta1.ContainerParameters
+		want   storage.BlobContainer
+	}{
+		"Private": {
+			reason: "A container without a public access type should be private",
+			p:      v1beta1.ContainerParameters{},
+			want:   storage.BlobContainer{ContainerProperties: &storage.ContainerProperties{PublicAccess: storage.PublicAccessNone}},
+		},
+		"Full": {
+			reason: "The public access type and metadata of a container should be sent",
+			p: v1beta1.ContainerParameters{
+				PublicAccessType: "blob",
+				Metadata:         map[string]string{"cool": "very"},
+			},
+			want: storage.BlobContainer{ContainerProperties: &storage.ContainerProperties{
+				PublicAccess: storage.PublicAccessBlob,
+				Metadata:     map[string]*string{"cool": azure.ToStringPtr("very")},
+			}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := NewBlobContainer(tc.p)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nNewBlobContainer(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestGenerateContainerObservation(t *testing.T) {
+	modified := time.Date(2022, 4, 1, 0, 0, 0, 0, time.UTC)
+
+	cases := map[string]struct {
+		reason string
+		c      storage.BlobContainer
+		want   v1beta1.ContainerObservation
+	}{
+		"NoProperties": {
+			reason: "A blob container without properties should only be observed by its identity",
+			c:      storage.BlobContainer{ID: azure.ToStringPtr("id")},
+			want:   v1beta1.ContainerObservation{ID: "id"},
+		},
+		"Full": {
+			reason: "The properties of a blob container should be observed",
+			c: storage.BlobContainer{
+				ID: azure.ToStringPtr("id"),
+				ContainerProperties: &storage.ContainerProperties{
+					LastModifiedTime:      &date.Time{Time: modified},
+					LeaseState:            storage.LeaseStateLeased,
+					LeaseStatus:           storage.LeaseStatusLocked,
+					HasImmutabilityPolicy: azure.ToBoolPtr(true),
+					HasLegalHold:          azure.ToBoolPtr(true),
+					ImmutabilityPolicy: &storage.ImmutabilityPolicyProperties{
+						Etag: azure.ToStringPtr("etag"),
+						ImmutabilityPolicyProperty: &storage.ImmutabilityPolicyProperty{
+							ImmutabilityPeriodSinceCreationInDays: to.Int32Ptr(7),
+							State:                                 storage.ImmutabilityPolicyStateLocked,
+						},
+					},
+					LegalHold: &storage.LegalHoldProperties{
+						HasLegalHold: azure.ToBoolPtr(true),
+						Tags:         &[]storage.TagProperty{{Tag: azure.ToStringPtr("audit")}},
+					},
+				},
+			},
+			want: v1beta1.ContainerObservation{
+				ID:                    "id",
+				LastModified:          &metav1.Time{Time: modified},
+				LeaseState:            "Leased",
+				LeaseStatus:           "Locked",
+				HasImmutabilityPolicy: true,
+				HasLegalHold:          true,
+				ImmutabilityPolicy: &v1beta1.ImmutabilityPolicyObservation{
+					ImmutabilityPeriodSinceCreationInDays: 7,
+					State:                                 "Locked",
+					Etag:                                  "etag",
+				},
+				LegalHoldTags: []string{"audit"},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := GenerateContainerObservation(tc.c)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nGenerateContainerObservation(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
 }
 
-func (s *storageError) Temporary() bool {
-	return false
+func TestIsContainerUpToDate(t *testing.T) {
+	observed := storage.BlobContainer{
+		ContainerProperties: &storage.ContainerProperties{
+			PublicAccess: storage.PublicAccessNone,
+			Metadata:     map[string]*string{},
+			ImmutabilityPolicy: &storage.ImmutabilityPolicyProperties{
+				ImmutabilityPolicyProperty: &storage.ImmutabilityPolicyProperty{
+					ImmutabilityPeriodSinceCreationInDays: to.Int32Ptr(7),
+					AllowProtectedAppendWrites:            to.BoolPtr(false),
+					State:                                 storage.ImmutabilityPolicyStateUnlocked,
+				},
+			},
+			LegalHold: &storage.LegalHoldProperties{
+				Tags: &[]storage.TagProperty{{Tag: azure.ToStringPtr("audit")}},
+			},
+		},
+	}
+
+	cases := map[string]struct {
+		reason string
+		p      v1beta1.ContainerParameters
+		want   []string
+	}{
+		"UpToDate": {
+			reason: "A private container without metadata should be up to date with a Container that specifies neither",
+			p:      v1beta1.ContainerParameters{},
+		},
+		"PolicyUpToDate": {
+			reason: "An immutability policy and legal hold tags that match the blob container should be up to date",
+			p: v1beta1.ContainerParameters{
+				ImmutabilityPolicy: &v1beta1.ImmutabilityPolicy{ImmutabilityPeriodSinceCreationInDays: 7},
+				LegalHold:          &v1beta1.LegalHold{Tags: []string{"AUDIT"}},
+			},
+		},
+		"NotUpToDate": {
+			reason: "Parameters that differ from the blob container should be reported as drift",
+			p: v1beta1.ContainerParameters{
+				PublicAccessType: "container",
+				Metadata:         map[string]string{"cool": "very"},
+				ImmutabilityPolicy: &v1beta1.ImmutabilityPolicy{
+					ImmutabilityPeriodSinceCreationInDays: 14,
+					AllowProtectedAppendWrites:            to.BoolPtr(true),
+					Locked:                                to.BoolPtr(true),
+				},
+				LegalHold: &v1beta1.LegalHold{},
+			},
+			want: []string{
+				"publicAccessType",
+				"metadata",
+				"immutabilityPolicy.immutabilityPeriodSinceCreationInDays",
+				"immutabilityPolicy.allowProtectedAppendWrites",
+				"immutabilityPolicy.locked",
+				"legalHold.tags",
+			},
+		},
+		"EmptyPolicy": {
+			reason: "An observed immutability policy should be reported as drift if the Container specifies an empty policy",
+			p: v1beta1.ContainerParameters{
+				ImmutabilityPolicy: &v1beta1.ImmutabilityPolicy{},
+			},
+			want: []string{"immutabilityPolicy"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			d := IsContainerUpToDate(tc.p, observed)
+			got := make([]string, len(d))
+			for i, f := range d {
+				got[i] = f.Path
+			}
+			if diff := cmp.Diff(tc.want, got, cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("\n%s\nIsContainerUpToDate(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
 }
 
-func (s *storageError) Response() *http.Response {
-	return &http.Response{
-		StatusCode: http.StatusNotFound,
+func TestLateInitializeContainer(t *testing.T) {
+	observed := storage.BlobContainer{
+		ContainerProperties: &storage.ContainerProperties{
+			ImmutabilityPolicy: &storage.ImmutabilityPolicyProperties{
+				ImmutabilityPolicyProperty: &storage.ImmutabilityPolicyProperty{
+					ImmutabilityPeriodSinceCreationInDays: to.Int32Ptr(7),
+					State:                                 storage.ImmutabilityPolicyStateLocked,
+				},
+			},
+			LegalHold: &storage.LegalHoldProperties{
+				Tags: &[]storage.TagProperty{{Tag: azure.ToStringPtr("audit")}},
+			},
+		},
+	}
+	annotated := map[string]string{AnnotationKeyImmutabilityPolicy: "true"}
+
+	cases := map[string]struct {
+		reason string
+		cr     *v1beta1.Container
+		want   *v1beta1.Container
+	}{
+		"Unset": {
+			reason: "The immutability policy and legal hold of the blob container should be late-initialized",
+			cr:     &v1beta1.Container{},
+			want: &v1beta1.Container{
+				ObjectMeta: metav1.ObjectMeta{Annotations: annotated},
+				Spec: v1beta1.ContainerSpec{ForProvider: v1beta1.ContainerParameters{
+					ImmutabilityPolicy: &v1beta1.ImmutabilityPolicy{
+						ImmutabilityPeriodSinceCreationInDays: 7,
+						AllowProtectedAppendWrites:            to.BoolPtr(false),
+						Locked:                                to.BoolPtr(true),
+					},
+					LegalHold: &v1beta1.LegalHold{Tags: []string{"audit"}},
+				}},
+			},
+		},
+		"Set": {
+			reason: "A specified immutability policy and legal hold should not be overwritten",
+			cr: &v1beta1.Container{Spec: v1beta1.ContainerSpec{ForProvider: v1beta1.ContainerParameters{
+				ImmutabilityPolicy: &v1beta1.ImmutabilityPolicy{ImmutabilityPeriodSinceCreationInDays: 14},
+				LegalHold:          &v1beta1.LegalHold{},
+			}}},
+			want: &v1beta1.Container{
+				ObjectMeta: metav1.ObjectMeta{Annotations: annotated},
+				Spec: v1beta1.ContainerSpec{ForProvider: v1beta1.ContainerParameters{
+					ImmutabilityPolicy: &v1beta1.ImmutabilityPolicy{ImmutabilityPeriodSinceCreationInDays: 14},
+					LegalHold:          &v1beta1.LegalHold{},
+				}},
+			},
+		},
+		"Removed": {
+			reason: "An immutability policy that was removed from the Container should not be late-initialized again",
+			cr:     &v1beta1.Container{ObjectMeta: metav1.ObjectMeta{Annotations: annotated}},
+			want: &v1beta1.Container{
+				ObjectMeta: metav1.ObjectMeta{Annotations: annotated},
+				Spec: v1beta1.ContainerSpec{ForProvider: v1beta1.ContainerParameters{
+					LegalHold: &v1beta1.LegalHold{Tags: []string{"audit"}},
+				}},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			LateInitializeContainer(tc.cr, observed)
+			if diff := cmp.Diff(tc.want, tc.cr); diff != "" {
+				t.Errorf("\n%s\nLateInitializeContainer(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
 	}
 }
 
-var _ azblob.StorageError = &storageError{}
+func TestDesiredImmutabilityPolicy(t *testing.T) {
+	policy := &v1beta1.ImmutabilityPolicy{ImmutabilityPeriodSinceCreationInDays: 7}
+	annotated := metav1.ObjectMeta{Annotations: map[string]string{AnnotationKeyImmutabilityPolicy: "true"}}
 
-func TestIsNotFound(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		cr     *v1beta1.Container
+		want   *v1beta1.ImmutabilityPolicy
+	}{
+		"Unmanaged": {
+			reason: "A Container that has never had an immutability policy should not desire one",
+			cr:     &v1beta1.Container{},
+		},
+		"Specified": {
+			reason: "The immutability policy of a Container should be desired",
+			cr: &v1beta1.Container{
+				ObjectMeta: annotated,
+				Spec:       v1beta1.ContainerSpec{ForProvider: v1beta1.ContainerParameters{ImmutabilityPolicy: policy}},
+			},
+			want: policy,
+		},
+		"Removed": {
+			reason: "A Container whose immutability policy was removed should desire an empty policy",
+			cr:     &v1beta1.Container{ObjectMeta: annotated},
+			want:   &v1beta1.ImmutabilityPolicy{},
+		},
+	}
 
-	cases := []struct {
-		err      error
-		expected bool
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := DesiredImmutabilityPolicy(tc.cr)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nDesiredImmutabilityPolicy(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestDiffLegalHoldTags(t *testing.T) {
+	type want struct {
+		set   []string
+		clear []string
+	}
+
+	cases := map[string]struct {
+		reason   string
+		desired  []string
+		observed []string
+		want     want
 	}{
-		{nil, false},
-		{errors.New("boom"), false},
-		{&storageError{}, true},
+		"InSync": {
+			reason:   "Tags should be compared case-insensitively",
+			desired:  []string{"Audit"},
+			observed: []string{"audit"},
+		},
+		"Changed": {
+			reason:   "Tags that are only desired should be set, and tags that are only observed cleared",
+			desired:  []string{"audit", "litigation"},
+			observed: []string{"audit", "sec17a4"},
+			want:     want{set: []string{"litigation"}, clear: []string{"sec17a4"}},
+		},
 	}
 
-	for _, tt := range cases {
-		got := IsNotFoundError(tt.err)
-		if diff := cmp.Diff(got, tt.expected); diff != "" {
-			t.Errorf("IsNotFoundError() = %v, expected %v\n%s", got, tt.expected, diff)
-		}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			set, clear := DiffLegalHoldTags(tc.desired, tc.observed)
+			if diff := cmp.Diff(tc.want, want{set: set, clear: clear}, cmp.AllowUnexported(want{})); diff != "" {
+				t.Errorf("\n%s\nDiffLegalHoldTags(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
 	}
 }
@@ -26,7 +26,7 @@ import (
 
 // MockAccountOperations mock implementation of AccountOperations
 type MockAccountOperations struct {
-	MockCreate                 func(context.Context, storage.AccountCreateParameters) (*storage.Account, error)
+	MockCreate                 func(context.Context, storage.AccountCreateParameters) (storage.AccountsCreateFuture, error)
 	MockUpdate                 func(context.Context, storage.AccountUpdateParameters) (*storage.Account, error)
 	MockGet                    func(ctx context.Context) (*storage.Account, error)
 	MockDelete                 func(ctx context.Context) error
@@ -39,8 +39,8 @@ var _ azurestorage.AccountOperations = &MockAccountOperations{}
 // NewMockAccountOperations returns new mock instance with default mocks
 func NewMockAccountOperations() *MockAccountOperations {
 	return &MockAccountOperations{
-		MockCreate: func(i context.Context, parameters storage.AccountCreateParameters) (account *storage.Account, e error) {
-			return nil, nil
+		MockCreate: func(i context.Context, parameters storage.AccountCreateParameters) (storage.AccountsCreateFuture, error) {
+			return storage.AccountsCreateFuture{}, nil
 		},
 		MockUpdate: func(i context.Context, parameters storage.AccountUpdateParameters) (account *storage.Account, e error) {
 			return nil, nil
@@ -61,7 +61,7 @@ func NewMockAccountOperations() *MockAccountOperations {
 }
 
 // Create mock create
-func (m *MockAccountOperations) Create(ctx context.Context, params storage.AccountCreateParameters) (*storage.Account, error) {
+func (m *MockAccountOperations) Create(ctx context.Context, params storage.AccountCreateParameters) (storage.AccountsCreateFuture, error) {
 	return m.MockCreate(ctx, params)
 }
 
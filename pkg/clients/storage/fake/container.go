@@ -19,60 +19,80 @@ package fake
 import (
 	"context"
 
-	"github.com/Azure/azure-storage-blob-go/azblob"
-
-	azurestorage "github.com/crossplane-contrib/provider-azure/pkg/clients/storage"
+	"github.com/Azure/azure-sdk-for-go/services/storage/mgmt/2021-04-01/storage"
+	"github.com/Azure/azure-sdk-for-go/services/storage/mgmt/2021-04-01/storage/storageapi"
+	"github.com/Azure/go-autorest/autorest"
 )
 
-// MockContainerOperations mock implementation of ContainerOperations
-type MockContainerOperations struct {
-	MockCreate func(context.Context, azblob.PublicAccessType, azblob.Metadata) error
-	MockUpdate func(context.Context, azblob.PublicAccessType, azblob.Metadata) error
-	MockGet    func(ctx context.Context) (*azblob.PublicAccessType, azblob.Metadata, error)
-	MockDelete func(ctx context.Context) error
+var _ storageapi.BlobContainersClientAPI = &MockBlobContainersClient{}
+
+// MockBlobContainersClient is a fake implementation of
+// storage.BlobContainersClient.
+type MockBlobContainersClient struct {
+	storageapi.BlobContainersClientAPI
+
+	MockClearLegalHold                   func(ctx context.Context, resourceGroupName string, accountName string, containerName string, legalHold storage.LegalHold) (result storage.LegalHold, err error)
+	MockCreate                           func(ctx context.Context, resourceGroupName string, accountName string, containerName string, blobContainer storage.BlobContainer) (result storage.BlobContainer, err error)
+	MockCreateOrUpdateImmutabilityPolicy func(ctx context.Context, resourceGroupName string, accountName string, containerName string, parameters *storage.ImmutabilityPolicy, ifMatch string) (result storage.ImmutabilityPolicy, err error)
+	MockDelete                           func(ctx context.Context, resourceGroupName string, accountName string, containerName string) (result autorest.Response, err error)
+	MockDeleteImmutabilityPolicy         func(ctx context.Context, resourceGroupName string, accountName string, containerName string, ifMatch string) (result storage.ImmutabilityPolicy, err error)
+	MockExtendImmutabilityPolicy         func(ctx context.Context, resourceGroupName string, accountName string, containerName string, ifMatch string, parameters *storage.ImmutabilityPolicy) (result storage.ImmutabilityPolicy, err error)
+	MockGet                              func(ctx context.Context, resourceGroupName string, accountName string, containerName string) (result storage.BlobContainer, err error)
+	MockLockImmutabilityPolicy           func(ctx context.Context, resourceGroupName string, accountName string, containerName string, ifMatch string) (result storage.ImmutabilityPolicy, err error)
+	MockSetLegalHold                     func(ctx context.Context, resourceGroupName string, accountName string, containerName string, legalHold storage.LegalHold) (result storage.LegalHold, err error)
+	MockUpdate                           func(ctx context.Context, resourceGroupName string, accountName string, containerName string, blobContainer storage.BlobContainer) (result storage.BlobContainer, err error)
+}
+
+// ClearLegalHold calls the MockBlobContainersClient's MockClearLegalHold method.
+func (c *MockBlobContainersClient) ClearLegalHold(ctx context.Context, resourceGroupName string, accountName string, containerName string, legalHold storage.LegalHold) (result storage.LegalHold, err error) {
+	return c.MockClearLegalHold(ctx, resourceGroupName, accountName, containerName, legalHold)
+}
+
+// Create calls the MockBlobContainersClient's MockCreate method.
+func (c *MockBlobContainersClient) Create(ctx context.Context, resourceGroupName string, accountName string, containerName string, blobContainer storage.BlobContainer) (result storage.BlobContainer, err error) {
+	return c.MockCreate(ctx, resourceGroupName, accountName, containerName, blobContainer)
+}
+
+// CreateOrUpdateImmutabilityPolicy calls the MockBlobContainersClient's
+// MockCreateOrUpdateImmutabilityPolicy method.
+func (c *MockBlobContainersClient) CreateOrUpdateImmutabilityPolicy(ctx context.Context, resourceGroupName string, accountName string, containerName string, parameters *storage.ImmutabilityPolicy, ifMatch string) (result storage.ImmutabilityPolicy, err error) {
+	return c.MockCreateOrUpdateImmutabilityPolicy(ctx, resourceGroupName, accountName, containerName, parameters, ifMatch)
+}
+
+// Delete calls the MockBlobContainersClient's MockDelete method.
+func (c *MockBlobContainersClient) Delete(ctx context.Context, resourceGroupName string, accountName string, containerName string) (result autorest.Response, err error) {
+	return c.MockDelete(ctx, resourceGroupName, accountName, containerName)
 }
 
-var _ azurestorage.ContainerOperations = &MockContainerOperations{}
-
-// NewMockContainerOperations create new mock instance with default mocks
-func NewMockContainerOperations() *MockContainerOperations {
-	return &MockContainerOperations{
-		MockCreate: func(ctx context.Context, pat azblob.PublicAccessType, meta azblob.Metadata) error {
-			return nil
-		},
-		MockUpdate: func(ctx context.Context, pat azblob.PublicAccessType, meta azblob.Metadata) error {
-			return nil
-		},
-		MockGet: func(ctx context.Context) (*azblob.PublicAccessType, azblob.Metadata, error) {
-			return nil, nil, nil
-		},
-		MockDelete: func(ctx context.Context) error {
-			return nil
-		},
-	}
+// DeleteImmutabilityPolicy calls the MockBlobContainersClient's
+// MockDeleteImmutabilityPolicy method.
+func (c *MockBlobContainersClient) DeleteImmutabilityPolicy(ctx context.Context, resourceGroupName string, accountName string, containerName string, ifMatch string) (result storage.ImmutabilityPolicy, err error) {
+	return c.MockDeleteImmutabilityPolicy(ctx, resourceGroupName, accountName, containerName, ifMatch)
 }
 
-// Create mock create function
-func (m *MockContainerOperations) Create(ctx context.Context, pat azblob.PublicAccessType, meta azblob.Metadata) error {
-	return m.MockCreate(ctx, pat, meta)
+// ExtendImmutabilityPolicy calls the MockBlobContainersClient's
+// MockExtendImmutabilityPolicy method.
+func (c *MockBlobContainersClient) ExtendImmutabilityPolicy(ctx context.Context, resourceGroupName string, accountName string, containerName string, ifMatch string, parameters *storage.ImmutabilityPolicy) (result storage.ImmutabilityPolicy, err error) {
+	return c.MockExtendImmutabilityPolicy(ctx, resourceGroupName, accountName, containerName, ifMatch, parameters)
 }
 
-// Update mock update function
-func (m *MockContainerOperations) Update(ctx context.Context, pat azblob.PublicAccessType, meta azblob.Metadata) error {
-	return m.MockUpdate(ctx, pat, meta)
+// Get calls the MockBlobContainersClient's MockGet method.
+func (c *MockBlobContainersClient) Get(ctx context.Context, resourceGroupName string, accountName string, containerName string) (result storage.BlobContainer, err error) {
+	return c.MockGet(ctx, resourceGroupName, accountName, containerName)
 }
 
-// Get mock get function
-func (m *MockContainerOperations) Get(ctx context.Context) (*azblob.PublicAccessType, azblob.Metadata, error) {
-	return m.MockGet(ctx)
+// LockImmutabilityPolicy calls the MockBlobContainersClient's
+// MockLockImmutabilityPolicy method.
+func (c *MockBlobContainersClient) LockImmutabilityPolicy(ctx context.Context, resourceGroupName string, accountName string, containerName string, ifMatch string) (result storage.ImmutabilityPolicy, err error) {
+	return c.MockLockImmutabilityPolicy(ctx, resourceGroupName, accountName, containerName, ifMatch)
 }
 
-// Delete mock delete function
-func (m *MockContainerOperations) Delete(ctx context.Context) error {
-	return m.MockDelete(ctx)
+// SetLegalHold calls the MockBlobContainersClient's MockSetLegalHold method.
+func (c *MockBlobContainersClient) SetLegalHold(ctx context.Context, resourceGroupName string, accountName string, containerName string, legalHold storage.LegalHold) (result storage.LegalHold, err error) {
+	return c.MockSetLegalHold(ctx, resourceGroupName, accountName, containerName, legalHold)
 }
 
-// PublicAccessTypePtr returns pointer of the PublicAccessType value
-func PublicAccessTypePtr(pab azblob.PublicAccessType) *azblob.PublicAccessType {
-	return &pab
+// Update calls the MockBlobContainersClient's MockUpdate method.
+func (c *MockBlobContainersClient) Update(ctx context.Context, resourceGroupName string, accountName string, containerName string, blobContainer storage.BlobContainer) (result storage.BlobContainer, err error) {
+	return c.MockUpdate(ctx, resourceGroupName, accountName, containerName, blobContainer)
 }
@@ -0,0 +1,50 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fake
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/Azure/go-autorest/autorest"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane-contrib/provider-azure/apis/storage/v1beta1"
+)
+
+// Account returns a storage Account with the supplied name.
+func Account(name string) *v1beta1.Account {
+	return &v1beta1.Account{ObjectMeta: metav1.ObjectMeta{Name: name, UID: "cool-uid"}}
+}
+
+// GetAccount returns a kube client Get function that returns the supplied
+// storage Account.
+func GetAccount(a *v1beta1.Account) test.MockGetFn {
+	return func(_ context.Context, _ client.ObjectKey, obj client.Object) error {
+		a.DeepCopyInto(obj.(*v1beta1.Account))
+		return nil
+	}
+}
+
+// NotFound returns the error that Azure returns for a resource that does not
+// exist.
+func NotFound() error {
+	return autorest.DetailedError{StatusCode: http.StatusNotFound}
+}
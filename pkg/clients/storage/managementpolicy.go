@@ -0,0 +1,176 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage
+
+import (
+	"github.com/Azure/azure-sdk-for-go/services/storage/mgmt/2021-04-01/storage"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/crossplane-contrib/provider-azure/apis/storage/v1alpha1"
+	azure "github.com/crossplane-contrib/provider-azure/pkg/clients"
+)
+
+const (
+	// ruleTypeLifecycle is the only valid type of a management policy rule.
+	ruleTypeLifecycle = "Lifecycle"
+
+	// tagFilterOpEquals is the only supported blob index tag operator.
+	tagFilterOpEquals = "=="
+)
+
+// NewManagementPolicy returns the management policy of a storage account
+// with the supplied parameters.
+func NewManagementPolicy(p v1alpha1.ManagementPolicyParameters) storage.ManagementPolicy {
+	rules := make([]storage.ManagementPolicyRule, len(p.Rules))
+	for i, r := range p.Rules {
+		enabled := true
+		if r.Enabled != nil {
+			enabled = *r.Enabled
+		}
+		rules[i] = storage.ManagementPolicyRule{
+			Name:    to.StringPtr(r.Name),
+			Enabled: to.BoolPtr(enabled),
+			Type:    to.StringPtr(ruleTypeLifecycle),
+			Definition: &storage.ManagementPolicyDefinition{
+				Actions: newManagementPolicyAction(r.Definition.Actions),
+				Filters: newManagementPolicyFilter(r.Definition.Filters),
+			},
+		}
+	}
+	return storage.ManagementPolicy{
+		ManagementPolicyProperties: &storage.ManagementPolicyProperties{
+			Policy: &storage.ManagementPolicySchema{Rules: &rules},
+		},
+	}
+}
+
+func newManagementPolicyAction(a v1alpha1.ManagementPolicyActions) *storage.ManagementPolicyAction {
+	out := &storage.ManagementPolicyAction{}
+	if b := a.BaseBlob; b != nil {
+		out.BaseBlob = &storage.ManagementPolicyBaseBlob{
+			TierToCool:                  newDateAfterModification(b.TierToCool),
+			TierToArchive:               newDateAfterModification(b.TierToArchive),
+			Delete:                      newDateAfterModification(b.Delete),
+			EnableAutoTierToHotFromCool: b.EnableAutoTierToHotFromCool,
+		}
+	}
+	if s := a.Snapshot; s != nil {
+		out.Snapshot = &storage.ManagementPolicySnapShot{
+			TierToCool:    newDateAfterCreation(s.TierToCool),
+			TierToArchive: newDateAfterCreation(s.TierToArchive),
+			Delete:        newDateAfterCreation(s.Delete),
+		}
+	}
+	if v := a.Version; v != nil {
+		out.Version = &storage.ManagementPolicyVersion{
+			TierToCool:    newDateAfterCreation(v.TierToCool),
+			TierToArchive: newDateAfterCreation(v.TierToArchive),
+			Delete:        newDateAfterCreation(v.Delete),
+		}
+	}
+	return out
+}
+
+func newDateAfterModification(d *v1alpha1.DateAfterModification) *storage.DateAfterModification {
+	if d == nil {
+		return nil
+	}
+	return &storage.DateAfterModification{
+		DaysAfterModificationGreaterThan:   toFloat64Ptr(d.DaysAfterModificationGreaterThan),
+		DaysAfterLastAccessTimeGreaterThan: toFloat64Ptr(d.DaysAfterLastAccessTimeGreaterThan),
+	}
+}
+
+func newDateAfterCreation(d *v1alpha1.DateAfterCreation) *storage.DateAfterCreation {
+	if d == nil {
+		return nil
+	}
+	return &storage.DateAfterCreation{DaysAfterCreationGreaterThan: to.Float64Ptr(float64(d.DaysAfterCreationGreaterThan))}
+}
+
+func newManagementPolicyFilter(f *v1alpha1.ManagementPolicyFilter) *storage.ManagementPolicyFilter {
+	if f == nil {
+		return nil
+	}
+	out := &storage.ManagementPolicyFilter{
+		PrefixMatch: azure.ToStringArrayPtr(f.PrefixMatch),
+		BlobTypes:   azure.ToStringArrayPtr(f.BlobTypes),
+	}
+	if f.BlobIndexMatch != nil {
+		tags := make([]storage.TagFilter, len(f.BlobIndexMatch))
+		for i, t := range f.BlobIndexMatch {
+			op := t.Op
+			if op == "" {
+				op = tagFilterOpEquals
+			}
+			tags[i] = storage.TagFilter{Name: to.StringPtr(t.Name), Op: to.StringPtr(op), Value: to.StringPtr(t.Value)}
+		}
+		out.BlobIndexMatch = &tags
+	}
+	return out
+}
+
+func toFloat64Ptr(i *int) *float64 {
+	if i == nil {
+		return nil
+	}
+	return to.Float64Ptr(float64(*i))
+}
+
+// GenerateManagementPolicyObservation returns the observed state of the
+// supplied management policy.
+func GenerateManagementPolicyObservation(mp storage.ManagementPolicy) v1alpha1.ManagementPolicyObservation {
+	o := v1alpha1.ManagementPolicyObservation{ID: azure.ToString(mp.ID)}
+	if mp.ManagementPolicyProperties != nil && mp.LastModifiedTime != nil {
+		o.LastModifiedTime = &metav1.Time{Time: mp.LastModifiedTime.Time}
+	}
+	return o
+}
+
+// LateInitializeManagementPolicy fills the unassigned fields of the supplied
+// ManagementPolicyParameters with the values of the supplied management
+// policy. Rules are matched by name.
+func LateInitializeManagementPolicy(p *v1alpha1.ManagementPolicyParameters, mp storage.ManagementPolicy) {
+	if mp.ManagementPolicyProperties == nil || mp.Policy == nil || mp.Policy.Rules == nil {
+		return
+	}
+	observed := map[string]storage.ManagementPolicyRule{}
+	for _, r := range *mp.Policy.Rules {
+		observed[azure.ToString(r.Name)] = r
+	}
+	for i := range p.Rules {
+		r, ok := observed[p.Rules[i].Name]
+		if !ok {
+			continue
+		}
+		p.Rules[i].Enabled = azure.LateInitializeBoolPtrFromPtr(p.Rules[i].Enabled, r.Enabled)
+	}
+}
+
+// IsManagementPolicyUpToDate returns the fields in which the supplied
+// management policy is out of sync with the supplied
+// ManagementPolicyParameters.
+func IsManagementPolicyUpToDate(p v1alpha1.ManagementPolicyParameters, mp storage.ManagementPolicy) azure.Diff {
+	if mp.ManagementPolicyProperties == nil || mp.Policy == nil {
+		d := azure.Diff{}
+		d.Add("rules", p.Rules, nil)
+		return d
+	}
+	return azure.DiffObjects(NewManagementPolicy(p).Policy, mp.Policy, nil, cmpopts.EquateEmpty())
+}
@@ -0,0 +1,216 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/services/storage/mgmt/2021-04-01/storage"
+	"github.com/Azure/go-autorest/autorest/date"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/crossplane-contrib/provider-azure/apis/storage/v1alpha1"
+)
+
+func days(i int) *int { return &i }
+
+// managementPolicyParameters returns parameters with a rule that tiers and
+// deletes blobs, and a rule that deletes snapshots and versions.
+func managementPolicyParameters() v1alpha1.ManagementPolicyParameters {
+	return v1alpha1.ManagementPolicyParameters{
+		Rules: []v1alpha1.ManagementPolicyRule{
+			{
+				Name: "tier",
+				Definition: v1alpha1.ManagementPolicyDefinition{
+					Actions: v1alpha1.ManagementPolicyActions{
+						BaseBlob: &v1alpha1.ManagementPolicyBaseBlob{
+							TierToCool:    &v1alpha1.DateAfterModification{DaysAfterModificationGreaterThan: days(30)},
+							TierToArchive: &v1alpha1.DateAfterModification{DaysAfterModificationGreaterThan: days(90)},
+						},
+					},
+					Filters: &v1alpha1.ManagementPolicyFilter{
+						PrefixMatch:    []string{"logs/"},
+						BlobTypes:      []string{"blockBlob"},
+						BlobIndexMatch: []v1alpha1.TagFilter{{Name: "project", Value: "cool"}},
+					},
+				},
+			},
+			{
+				Name:    "cleanup",
+				Enabled: to.BoolPtr(false),
+				Definition: v1alpha1.ManagementPolicyDefinition{
+					Actions: v1alpha1.ManagementPolicyActions{
+						Snapshot: &v1alpha1.ManagementPolicySnapshot{Delete: &v1alpha1.DateAfterCreation{DaysAfterCreationGreaterThan: 7}},
+						Version:  &v1alpha1.ManagementPolicyVersion{Delete: &v1alpha1.DateAfterCreation{DaysAfterCreationGreaterThan: 14}},
+					},
+				},
+			},
+		},
+	}
+}
+
+// managementPolicy returns the management policy of managementPolicyParameters.
+func managementPolicy() storage.ManagementPolicy {
+	return storage.ManagementPolicy{
+		ManagementPolicyProperties: &storage.ManagementPolicyProperties{
+			Policy: &storage.ManagementPolicySchema{Rules: &[]storage.ManagementPolicyRule{
+				{
+					Name:    to.StringPtr("tier"),
+					Enabled: to.BoolPtr(true),
+					Type:    to.StringPtr("Lifecycle"),
+					Definition: &storage.ManagementPolicyDefinition{
+						Actions: &storage.ManagementPolicyAction{
+							BaseBlob: &storage.ManagementPolicyBaseBlob{
+								TierToCool:    &storage.DateAfterModification{DaysAfterModificationGreaterThan: to.Float64Ptr(30)},
+								TierToArchive: &storage.DateAfterModification{DaysAfterModificationGreaterThan: to.Float64Ptr(90)},
+							},
+						},
+						Filters: &storage.ManagementPolicyFilter{
+							PrefixMatch:    &[]string{"logs/"},
+							BlobTypes:      &[]string{"blockBlob"},
+							BlobIndexMatch: &[]storage.TagFilter{{Name: to.StringPtr("project"), Op: to.StringPtr("=="), Value: to.StringPtr("cool")}},
+						},
+					},
+				},
+				{
+					Name:    to.StringPtr("cleanup"),
+					Enabled: to.BoolPtr(false),
+					Type:    to.StringPtr("Lifecycle"),
+					Definition: &storage.ManagementPolicyDefinition{
+						Actions: &storage.ManagementPolicyAction{
+							Snapshot: &storage.ManagementPolicySnapShot{Delete: &storage.DateAfterCreation{DaysAfterCreationGreaterThan: to.Float64Ptr(7)}},
+							Version:  &storage.ManagementPolicyVersion{Delete: &storage.DateAfterCreation{DaysAfterCreationGreaterThan: to.Float64Ptr(14)}},
+						},
+					},
+				},
+			}},
+		},
+	}
+}
+
+func TestNewManagementPolicy(t *testing.T) {
+	got := NewManagementPolicy(managementPolicyParameters())
+	if diff := cmp.Diff(managementPolicy(), got); diff != "" {
+		t.Errorf("\nRules should be enabled unless specified otherwise, and tag filters should compare for equality\nNewManagementPolicy(...): -want, +got:\n%s", diff)
+	}
+}
+
+func TestGenerateManagementPolicyObservation(t *testing.T) {
+	modified := time.Date(2022, 4, 1, 0, 0, 0, 0, time.UTC)
+
+	cases := map[string]struct {
+		reason string
+		mp     storage.ManagementPolicy
+		want   v1alpha1.ManagementPolicyObservation
+	}{
+		"NoProperties": {
+			reason: "A management policy without properties should only be observed by its identity",
+			mp:     storage.ManagementPolicy{ID: to.StringPtr("id")},
+			want:   v1alpha1.ManagementPolicyObservation{ID: "id"},
+		},
+		"Full": {
+			reason: "The last modification time of a management policy should be observed",
+			mp: storage.ManagementPolicy{
+				ID:                         to.StringPtr("id"),
+				ManagementPolicyProperties: &storage.ManagementPolicyProperties{LastModifiedTime: &date.Time{Time: modified}},
+			},
+			want: v1alpha1.ManagementPolicyObservation{ID: "id", LastModifiedTime: &metav1.Time{Time: modified}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := GenerateManagementPolicyObservation(tc.mp)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nGenerateManagementPolicyObservation(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestLateInitializeManagementPolicy(t *testing.T) {
+	observed := storage.ManagementPolicy{
+		ManagementPolicyProperties: &storage.ManagementPolicyProperties{
+			Policy: &storage.ManagementPolicySchema{Rules: &[]storage.ManagementPolicyRule{
+				{Name: to.StringPtr("tier"), Enabled: to.BoolPtr(false)},
+				{Name: to.StringPtr("cleanup"), Enabled: to.BoolPtr(true)},
+			}},
+		},
+	}
+
+	p := managementPolicyParameters()
+	p.Rules = append(p.Rules, v1alpha1.ManagementPolicyRule{Name: "new"})
+	LateInitializeManagementPolicy(&p, observed)
+
+	want := managementPolicyParameters()
+	want.Rules[0].Enabled = to.BoolPtr(false)
+	want.Rules = append(want.Rules, v1alpha1.ManagementPolicyRule{Name: "new"})
+	if diff := cmp.Diff(want, p); diff != "" {
+		t.Errorf("\nUnset fields of rules should be late-initialized from the observed rule of the same name\nLateInitializeManagementPolicy(...): -want, +got:\n%s", diff)
+	}
+}
+
+func TestIsManagementPolicyUpToDate(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		p      func() v1alpha1.ManagementPolicyParameters
+		mp     storage.ManagementPolicy
+		want   []string
+	}{
+		"NoPolicy": {
+			reason: "A storage account without a management policy should not be up to date",
+			p:      managementPolicyParameters,
+			want:   []string{"rules"},
+		},
+		"UpToDate": {
+			reason: "A management policy that matches the parameters should be up to date",
+			p:      managementPolicyParameters,
+			mp:     managementPolicy(),
+		},
+		"NotUpToDate": {
+			reason: "Rules that differ from the management policy should be reported as drift",
+			p: func() v1alpha1.ManagementPolicyParameters {
+				p := managementPolicyParameters()
+				p.Rules[0].Definition.Actions.BaseBlob.TierToCool.DaysAfterModificationGreaterThan = days(60)
+				p.Rules[0].Definition.Filters.PrefixMatch = nil
+				return p
+			},
+			mp: managementPolicy(),
+			want: []string{
+				"Rules[0].Definition.Actions.BaseBlob.TierToCool.DaysAfterModificationGreaterThan",
+				"Rules[0].Definition.Filters.PrefixMatch",
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			d := IsManagementPolicyUpToDate(tc.p(), tc.mp)
+			got := make([]string, len(d))
+			for i, f := range d {
+				got[i] = f.Path
+			}
+			if diff := cmp.Diff(tc.want, got, cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("\n%s\nIsManagementPolicyUpToDate(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,63 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage
+
+import (
+	"context"
+
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+
+	"github.com/crossplane-contrib/provider-azure/apis/storage/v1beta1"
+)
+
+const errGetAccount = "cannot get storage account"
+
+// AccountOwnerReference returns an owner reference to the supplied storage
+// Account that blocks its deletion until the owned object is deleted.
+func AccountOwnerReference(a *v1beta1.Account) metav1.OwnerReference {
+	or := meta.AsOwner(meta.TypedReferenceTo(a, v1beta1.AccountGroupVersionKind))
+	or.BlockOwnerDeletion = to.BoolPtr(true)
+	return or
+}
+
+// AddAccountOwner makes the storage Account that the supplied reference
+// refers to the owner of the supplied object, so that the object is garbage
+// collected along with it. Nothing is done if there is no reference, or if
+// the Account does not exist.
+func AddAccountOwner(ctx context.Context, kube client.Client, o metav1.Object, ref *xpv1.Reference) error {
+	if ref == nil {
+		return nil
+	}
+	a := &v1beta1.Account{}
+	err := kube.Get(ctx, types.NamespacedName{Name: ref.Name}, a)
+	if kerrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return errors.Wrapf(err, "%s: %s", errGetAccount, ref.Name)
+	}
+	meta.AddOwnerReference(o, AccountOwnerReference(a))
+	return nil
+}
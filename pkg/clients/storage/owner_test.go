@@ -0,0 +1,97 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane-contrib/provider-azure/apis/storage/v1beta1"
+)
+
+func TestAddAccountOwner(t *testing.T) {
+	errBoom := errors.New("boom")
+	account := &v1beta1.Account{ObjectMeta: metav1.ObjectMeta{Name: "coolaccount", UID: "cool-uid"}}
+	ref := &xpv1.Reference{Name: account.GetName()}
+
+	type args struct {
+		kube client.Client
+		ref  *xpv1.Reference
+	}
+	type want struct {
+		owners []metav1.OwnerReference
+		err    error
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"NoReference": {
+			reason: "An object that does not reference a storage Account should not get an owner",
+		},
+		"AccountNotFound": {
+			reason: "An object whose storage Account does not exist should not get an owner",
+			args: args{
+				kube: &test.MockClient{MockGet: test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{}, ref.Name))},
+				ref:  ref,
+			},
+		},
+		"GetAccountFailed": {
+			reason: "Errors getting the referenced storage Account should be returned",
+			args: args{
+				kube: &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+				ref:  ref,
+			},
+			want: want{err: errors.Wrapf(errBoom, "%s: %s", errGetAccount, ref.Name)},
+		},
+		"OwnerAdded": {
+			reason: "The referenced storage Account should become the owner of the object",
+			args: args{
+				kube: &test.MockClient{MockGet: func(_ context.Context, _ client.ObjectKey, obj client.Object) error {
+					account.DeepCopyInto(obj.(*v1beta1.Account))
+					return nil
+				}},
+				ref: ref,
+			},
+			want: want{owners: []metav1.OwnerReference{AccountOwnerReference(account)}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			c := &v1beta1.Container{}
+			err := AddAccountOwner(context.Background(), tc.args.kube, c, tc.args.ref)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nAddAccountOwner(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.owners, c.GetOwnerReferences()); diff != "" {
+				t.Errorf("\n%s\nAddAccountOwner(...): -want owners, +got owners:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,74 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	"github.com/crossplane-contrib/provider-azure/apis/storage/v1beta1"
+)
+
+const (
+	errNotContainer    = "managed resource is not a Container"
+	errUpdateContainer = "cannot update Container"
+)
+
+// An AccountProviderConfig initializer gives a Container that is marked to
+// inherit the ProviderConfig of its Account that ProviderConfig.
+type AccountProviderConfig struct {
+	client client.Client
+}
+
+// NewAccountProviderConfig returns an initializer that gives Containers that
+// were converted from v1alpha3 the ProviderConfig of their Account.
+func NewAccountProviderConfig(c client.Client) *AccountProviderConfig {
+	return &AccountProviderConfig{client: c}
+}
+
+// Initialize replaces the ProviderConfig of the supplied Container with that
+// of the Account its accountNameRef refers to, if the Container is marked to
+// inherit it. A Container that is being deleted keeps its ProviderConfig if
+// its Account no longer exists.
+func (a *AccountProviderConfig) Initialize(ctx context.Context, mg resource.Managed) error {
+	cr, ok := mg.(*v1beta1.Container)
+	if !ok {
+		return errors.New(errNotContainer)
+	}
+	if _, ok := cr.GetAnnotations()[v1beta1.AnnotationKeyInheritProviderConfig]; !ok {
+		return nil
+	}
+	if ref := cr.Spec.ForProvider.AccountNameRef; ref != nil {
+		acc := &v1beta1.Account{}
+		err := a.client.Get(ctx, types.NamespacedName{Name: ref.Name}, acc)
+		if err != nil && !(kerrors.IsNotFound(err) && meta.WasDeleted(cr)) {
+			return errors.Wrapf(err, "%s: %s", errGetAccount, ref.Name)
+		}
+		if pc := acc.GetProviderConfigReference(); pc != nil {
+			cr.SetProviderConfigReference(pc.DeepCopy())
+		}
+	}
+	meta.RemoveAnnotations(cr, v1beta1.AnnotationKeyInheritProviderConfig)
+	return errors.Wrap(a.client.Update(ctx, cr), errUpdateContainer)
+}
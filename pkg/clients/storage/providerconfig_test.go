@@ -0,0 +1,123 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane-contrib/provider-azure/apis/storage/v1beta1"
+)
+
+func TestAccountProviderConfig(t *testing.T) {
+	errBoom := errors.New("boom")
+	account := &v1beta1.Account{ObjectMeta: metav1.ObjectMeta{Name: "coolaccount"}}
+	account.SetProviderConfigReference(&xpv1.Reference{Name: "cool-pc"})
+
+	container := func(inherit bool, deleted bool) *v1beta1.Container {
+		c := &v1beta1.Container{ObjectMeta: metav1.ObjectMeta{Name: "cool-container"}}
+		c.SetProviderConfigReference(&xpv1.Reference{Name: "default"})
+		c.Spec.ForProvider.AccountNameRef = &xpv1.Reference{Name: account.GetName()}
+		if inherit {
+			c.SetAnnotations(map[string]string{v1beta1.AnnotationKeyInheritProviderConfig: "true"})
+		}
+		if deleted {
+			c.SetDeletionTimestamp(&metav1.Time{Time: time.Now()})
+		}
+		return c
+	}
+	getAccount := func(_ context.Context, _ client.ObjectKey, obj client.Object) error {
+		account.DeepCopyInto(obj.(*v1beta1.Account))
+		return nil
+	}
+
+	type want struct {
+		pc      string
+		inherit bool
+		err     error
+	}
+	cases := map[string]struct {
+		reason string
+		kube   client.Client
+		cr     *v1beta1.Container
+		want   want
+	}{
+		"NotMarked": {
+			reason: "A Container that is not marked should keep its ProviderConfig",
+			cr:     container(false, false),
+			want:   want{pc: "default"},
+		},
+		"GetAccountFailed": {
+			reason: "Errors getting the Account of a marked Container should be returned",
+			kube:   &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+			cr:     container(true, false),
+			want: want{
+				pc:      "default",
+				inherit: true,
+				err:     errors.Wrapf(errBoom, "%s: %s", errGetAccount, account.GetName()),
+			},
+		},
+		"AccountDeleted": {
+			reason: "A marked Container that is being deleted should keep its ProviderConfig if its Account does not exist",
+			kube: &test.MockClient{
+				MockGet:    test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{}, account.GetName())),
+				MockUpdate: test.NewMockUpdateFn(nil),
+			},
+			cr:   container(true, true),
+			want: want{pc: "default"},
+		},
+		"UpdateFailed": {
+			reason: "Errors updating a marked Container should be returned",
+			kube:   &test.MockClient{MockGet: getAccount, MockUpdate: test.NewMockUpdateFn(errBoom)},
+			cr:     container(true, false),
+			want:   want{pc: "cool-pc", err: errors.Wrap(errBoom, errUpdateContainer)},
+		},
+		"Inherited": {
+			reason: "A marked Container should get the ProviderConfig of its Account",
+			kube:   &test.MockClient{MockGet: getAccount, MockUpdate: test.NewMockUpdateFn(nil)},
+			cr:     container(true, false),
+			want:   want{pc: "cool-pc"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := NewAccountProviderConfig(tc.kube).Initialize(context.Background(), tc.cr)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nInitialize(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.pc, tc.cr.GetProviderConfigReference().Name); diff != "" {
+				t.Errorf("\n%s\nInitialize(...): -want ProviderConfig, +got ProviderConfig:\n%s", tc.reason, diff)
+			}
+			_, inherit := tc.cr.GetAnnotations()[v1beta1.AnnotationKeyInheritProviderConfig]
+			if diff := cmp.Diff(tc.want.inherit, inherit); diff != "" {
+				t.Errorf("\n%s\nInitialize(...): -want marked, +got marked:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
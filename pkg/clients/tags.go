@@ -0,0 +1,160 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azure
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	"github.com/crossplane-contrib/provider-azure/apis/v1beta1"
+)
+
+// Ownership tags. The provider adds them to every external resource it
+// creates or updates, in order to identify the managed resource that manages
+// it. They are owned by the provider: an external resource whose ownership
+// tags are missing or wrong is not up to date, but they are never reported as
+// drift of its tags, and tags with the same keys in a managed resource or
+// ProviderConfig are ignored.
+const (
+	TagKeyKind           = "crossplane-kind"
+	TagKeyName           = "crossplane-name"
+	TagKeyProviderConfig = "crossplane-providerconfig"
+)
+
+// IsOwnershipTag returns true if the supplied tag key is that of an ownership
+// tag.
+func IsOwnershipTag(k string) bool {
+	return k == TagKeyKind || k == TagKeyName || k == TagKeyProviderConfig
+}
+
+// OwnershipTags returns the ownership tags of the external resource of the
+// supplied managed resource.
+func OwnershipTags(mg resource.Managed) map[string]string {
+	t := map[string]string{
+		TagKeyKind: reflect.Indirect(reflect.ValueOf(mg)).Type().Name(),
+		TagKeyName: mg.GetName(),
+	}
+	if ref := mg.GetProviderConfigReference(); ref != nil {
+		t[TagKeyProviderConfig] = ref.Name
+	}
+	return t
+}
+
+// OwnershipTagsUpToDate returns true if the supplied tags of the external
+// resource of the supplied managed resource include its ownership tags. An
+// external resource whose ownership tags are missing or wrong is not up to
+// date, so that its next update applies them.
+func OwnershipTagsUpToDate(mg resource.Managed, tags map[string]*string) bool {
+	for k, v := range OwnershipTags(mg) {
+		if t, ok := tags[k]; !ok || ToString(t) != v {
+			return false
+		}
+	}
+	return true
+}
+
+// DefaultTags returns the default tags of the ProviderConfig of the supplied
+// managed resource. Managed resources that use a Provider have none.
+func DefaultTags(ctx context.Context, c client.Client, mg resource.Managed) (map[string]string, error) {
+	ref := mg.GetProviderConfigReference()
+	if ref == nil {
+		return nil, nil
+	}
+	pc := &v1beta1.ProviderConfig{}
+	if err := c.Get(ctx, types.NamespacedName{Name: ref.Name}, pc); err != nil {
+		return nil, errors.Wrap(err, errGetProviderConfig)
+	}
+	return pc.Spec.DefaultTags, nil
+}
+
+// DesiredTags returns the supplied default tags, overridden by the supplied
+// tags of a managed resource. These are the tags of its external resource
+// that the provider keeps up to date. Ownership tags are omitted. It returns
+// nil rather than an empty map.
+func DesiredTags(defaults, tags map[string]string) map[string]string {
+	var t map[string]string
+	for _, m := range []map[string]string{defaults, tags} {
+		for k, v := range m {
+			if IsOwnershipTag(k) {
+				continue
+			}
+			if t == nil {
+				t = map[string]string{}
+			}
+			t[k] = v
+		}
+	}
+	return t
+}
+
+// NewTags returns the tags that the external resource of the supplied managed
+// resource should be created or updated with, i.e. its desired tags and its
+// ownership tags.
+func NewTags(mg resource.Managed, defaults, tags map[string]string) map[string]*string {
+	t := DesiredTags(defaults, tags)
+	if t == nil {
+		t = map[string]string{}
+	}
+	for k, v := range OwnershipTags(mg) {
+		t[k] = v
+	}
+	return ToStringPtrMap(t)
+}
+
+// ObservedTags returns the supplied tags of an external resource, omitting
+// ownership tags, such that they may be compared to its DesiredTags. It
+// returns nil rather than an empty map.
+func ObservedTags(tags map[string]*string) map[string]string {
+	var t map[string]string
+	for k, v := range tags {
+		if IsOwnershipTag(k) {
+			continue
+		}
+		if t == nil {
+			t = map[string]string{}
+		}
+		t[k] = ToString(v)
+	}
+	return t
+}
+
+// LateInitializeTags late-initializes the tags of a managed resource from the
+// supplied tags of its external resource, omitting ownership tags and default
+// tags that have their default value. Such tags are applied by the provider,
+// so persisting them would pin defaults that may later change.
+func LateInitializeTags(in map[string]string, from map[string]*string, defaults map[string]string) map[string]string {
+	if in != nil {
+		return in
+	}
+	var t map[string]string
+	for k, v := range ObservedTags(from) {
+		if d, ok := defaults[k]; ok && d == v {
+			continue
+		}
+		if t == nil {
+			t = map[string]string{}
+		}
+		t[k] = v
+	}
+	return t
+}
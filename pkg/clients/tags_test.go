@@ -0,0 +1,226 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azure
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane-contrib/provider-azure/apis/v1alpha3"
+	"github.com/crossplane-contrib/provider-azure/apis/v1beta1"
+)
+
+func TestDefaultTags(t *testing.T) {
+	errBoom := errors.New("boom")
+	withRef := &fake.Managed{}
+	withRef.SetProviderConfigReference(&xpv1.Reference{Name: "default"})
+
+	type args struct {
+		kube client.Client
+		mg   *fake.Managed
+	}
+	type want struct {
+		tags map[string]string
+		err  error
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"NoProviderConfig": {
+			reason: "A managed resource without a ProviderConfig reference should have no default tags",
+			args: args{
+				mg: &fake.Managed{},
+			},
+		},
+		"GetError": {
+			reason: "Errors getting the ProviderConfig should be returned",
+			args: args{
+				kube: &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+				mg:   withRef,
+			},
+			want: want{err: errors.Wrap(errBoom, errGetProviderConfig)},
+		},
+		"Success": {
+			reason: "The default tags of the ProviderConfig should be returned",
+			args: args{
+				kube: &test.MockClient{MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+					obj.(*v1beta1.ProviderConfig).Spec.DefaultTags = map[string]string{"cost-center": "42"}
+					return nil
+				})},
+				mg: withRef,
+			},
+			want: want{tags: map[string]string{"cost-center": "42"}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := DefaultTags(context.Background(), tc.args.kube, tc.args.mg)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nDefaultTags(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.tags, got); diff != "" {
+				t.Errorf("\n%s\nDefaultTags(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestNewTags(t *testing.T) {
+	rg := &v1alpha3.ResourceGroup{ObjectMeta: metav1.ObjectMeta{Name: "cool-rg"}}
+	rg.SetProviderConfigReference(&xpv1.Reference{Name: "default"})
+
+	type args struct {
+		defaults map[string]string
+		tags     map[string]string
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   map[string]*string
+	}{
+		"OwnershipOnly": {
+			reason: "A resource without tags should be tagged with its ownership tags",
+			want: map[string]*string{
+				TagKeyKind:           ToStringPtr("ResourceGroup"),
+				TagKeyName:           ToStringPtr("cool-rg"),
+				TagKeyProviderConfig: ToStringPtr("default"),
+			},
+		},
+		"Merged": {
+			reason: "Tags of the resource should take precedence over default tags, and ownership tags over both",
+			args: args{
+				defaults: map[string]string{"cost-center": "42", "env": "test", TagKeyName: "spoofed"},
+				tags:     map[string]string{"env": "prod", TagKeyKind: "spoofed"},
+			},
+			want: map[string]*string{
+				"cost-center":        ToStringPtr("42"),
+				"env":                ToStringPtr("prod"),
+				TagKeyKind:           ToStringPtr("ResourceGroup"),
+				TagKeyName:           ToStringPtr("cool-rg"),
+				TagKeyProviderConfig: ToStringPtr("default"),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := NewTags(rg, tc.args.defaults, tc.args.tags)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nNewTags(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestOwnershipTagsUpToDate(t *testing.T) {
+	rg := &v1alpha3.ResourceGroup{ObjectMeta: metav1.ObjectMeta{Name: "cool-rg"}}
+	rg.SetProviderConfigReference(&xpv1.Reference{Name: "default"})
+
+	cases := map[string]struct {
+		reason string
+		tags   map[string]*string
+		want   bool
+	}{
+		"UpToDate": {
+			reason: "Tags that include the ownership tags of a resource should be up to date",
+			tags:   NewTags(rg, nil, map[string]string{"env": "prod"}),
+			want:   true,
+		},
+		"Missing": {
+			reason: "Tags that are missing an ownership tag should not be up to date",
+			tags: map[string]*string{
+				TagKeyKind: ToStringPtr("ResourceGroup"),
+				TagKeyName: ToStringPtr("cool-rg"),
+			},
+		},
+		"Wrong": {
+			reason: "Tags whose ownership tags identify another resource should not be up to date",
+			tags: map[string]*string{
+				TagKeyKind:           ToStringPtr("ResourceGroup"),
+				TagKeyName:           ToStringPtr("other-rg"),
+				TagKeyProviderConfig: ToStringPtr("default"),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := OwnershipTagsUpToDate(rg, tc.tags)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nOwnershipTagsUpToDate(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestLateInitializeTags(t *testing.T) {
+	type args struct {
+		in       map[string]string
+		from     map[string]*string
+		defaults map[string]string
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   map[string]string
+	}{
+		"AlreadyInitialized": {
+			reason: "Tags that are already set should not be late-initialized",
+			args: args{
+				in:   map[string]string{"env": "prod"},
+				from: map[string]*string{"env": ToStringPtr("test")},
+			},
+			want: map[string]string{"env": "prod"},
+		},
+		"OnlyProviderTags": {
+			reason: "Ownership tags and default tags with their default value should not be late-initialized",
+			args: args{
+				from:     map[string]*string{"cost-center": ToStringPtr("42"), TagKeyKind: ToStringPtr("ResourceGroup")},
+				defaults: map[string]string{"cost-center": "42"},
+			},
+		},
+		"Initialized": {
+			reason: "Other tags, including default tags without their default value, should be late-initialized",
+			args: args{
+				from:     map[string]*string{"cost-center": ToStringPtr("7"), "env": ToStringPtr("prod")},
+				defaults: map[string]string{"cost-center": "42"},
+			},
+			want: map[string]string{"cost-center": "7", "env": "prod"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := LateInitializeTags(tc.args.in, tc.args.from, tc.args.defaults)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nLateInitializeTags(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
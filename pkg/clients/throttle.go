@@ -0,0 +1,332 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azure
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/azure"
+)
+
+const (
+	// headerRetryAfter is the header Azure uses to tell clients how long to
+	// wait before retrying a throttled request.
+	headerRetryAfter = "Retry-After"
+
+	// headerRemainingPrefix prefixes the headers Azure Resource Manager uses
+	// to report how many requests of each class remain in the current window
+	// of a subscription, e.g. x-ms-ratelimit-remaining-subscription-reads.
+	headerRemainingPrefix = "x-ms-ratelimit-remaining-subscription-"
+
+	// defaultRetryAfter is how long requests are held back after a 429 that
+	// did not include a Retry-After header.
+	defaultRetryAfter = 30 * time.Second
+
+	// DefaultLowWatermark is the number of remaining requests below which
+	// the DefaultThrottler starts to delay requests.
+	DefaultLowWatermark = 100
+
+	// DefaultMaxDelay is the longest the DefaultThrottler spaces requests
+	// when a subscription is about to run out of requests.
+	DefaultMaxDelay = 10 * time.Second
+)
+
+// Request classes, as used by the x-ms-ratelimit-remaining-subscription-*
+// headers.
+const (
+	requestClassReads   = "reads"
+	requestClassWrites  = "writes"
+	requestClassDeletes = "deletes"
+)
+
+// A ThrottledError is returned when a request was not sent because Azure
+// throttled an earlier request with the same scope and class, or because too
+// few requests of that scope and class remain to send it yet.
+type ThrottledError struct {
+	// Scope is the subscription, or for requests outside a subscription the
+	// host, that was throttled.
+	Scope string
+
+	// Class is the class of requests that was throttled, i.e. reads, writes
+	// or deletes.
+	Class string
+
+	// RetryAfter is how long to wait before requests may be sent again.
+	RetryAfter time.Duration
+}
+
+func (e *ThrottledError) Error() string {
+	return fmt.Sprintf("Azure is throttling %s in %s: retry after %s", e.Class, e.Scope, e.RetryAfter.Round(time.Second))
+}
+
+// IsThrottled returns true if the supplied error indicates that Azure
+// throttled a request, either because it returned HTTP 429 or because the
+// request was held back by a Throttler.
+func IsThrottled(err error) bool {
+	return Classify(err) == ErrorThrottled
+}
+
+// DefaultThrottler is the Throttler shared by all clients configured by
+// ConfigureClient.
+var DefaultThrottler = NewThrottler(DefaultLowWatermark, DefaultMaxDelay)
+
+type throttleKey struct {
+	scope string
+	class string
+}
+
+type throttleState struct {
+	blockedUntil time.Time
+	next         time.Time
+	remaining    int
+	known        bool
+}
+
+// A Throttler tracks the request quotas Azure reports for each subscription
+// and class of request. Once a quota is nearly exhausted it spaces further
+// requests out, and once Azure throttles a request it fails further requests
+// without sending them until the Retry-After period has passed. A Throttler
+// never blocks the caller; requests it holds back fail with a ThrottledError
+// so that their reconciles are requeued.
+type Throttler struct {
+	mu     sync.Mutex
+	states map[throttleKey]*throttleState
+
+	lowWatermark int
+	maxDelay     time.Duration
+	now          func() time.Time
+}
+
+// NewThrottler returns a Throttler that starts to space requests once fewer
+// than lowWatermark requests remain, up to maxDelay apart.
+func NewThrottler(lowWatermark int, maxDelay time.Duration) *Throttler {
+	return &Throttler{
+		states:       map[throttleKey]*throttleState{},
+		lowWatermark: lowWatermark,
+		maxDelay:     maxDelay,
+		now:          time.Now,
+	}
+}
+
+// WithThrottling returns a SendDecorator that fails requests whose scope and
+// class are currently throttled, or that are sent too soon after another
+// request whose scope and class are about to be throttled. It should wrap any
+// retries, so that a request is checked once no matter how often it is
+// retried.
+func (t *Throttler) WithThrottling() autorest.SendDecorator {
+	return func(s autorest.Sender) autorest.Sender {
+		return autorest.SenderFunc(func(r *http.Request) (*http.Response, error) {
+			if err := t.admit(keyFor(r)); err != nil {
+				return nil, err
+			}
+			return s.Do(r)
+		})
+	}
+}
+
+// WithRateLimitTracking returns a SendDecorator that records the remaining
+// request quotas and Retry-After periods Azure returns. It should be the
+// innermost decorator, so that it observes every attempt to send a request.
+func (t *Throttler) WithRateLimitTracking() autorest.SendDecorator {
+	return func(s autorest.Sender) autorest.Sender {
+		return autorest.SenderFunc(func(r *http.Request) (*http.Response, error) {
+			resp, err := s.Do(r)
+			if resp != nil {
+				t.record(keyFor(r), resp)
+			}
+			return resp, err
+		})
+	}
+}
+
+// admit returns a ThrottledError if a request of the supplied scope and class
+// may not be sent yet. While few requests remain, it admits one request per
+// delay and fails the others, rather than sleeping until their turn.
+func (t *Throttler) admit(k throttleKey) error {
+	now := t.now()
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	st := t.states[k]
+	if st == nil {
+		return nil
+	}
+	if st.blockedUntil.After(now) {
+		return &ThrottledError{Scope: k.scope, Class: k.class, RetryAfter: st.blockedUntil.Sub(now)}
+	}
+	d := t.delay(st)
+	if d <= 0 {
+		return nil
+	}
+	if st.next.After(now) {
+		return &ThrottledError{Scope: k.scope, Class: k.class, RetryAfter: st.next.Sub(now)}
+	}
+	st.next = now.Add(d)
+	return nil
+}
+
+// delay returns how far apart to space requests given the supplied state. The
+// delay grows linearly from zero at the low watermark to maxDelay when no
+// requests remain.
+func (t *Throttler) delay(st *throttleState) time.Duration {
+	if !st.known || t.lowWatermark <= 0 || st.remaining >= t.lowWatermark {
+		return 0
+	}
+	remaining := st.remaining
+	if remaining < 0 {
+		remaining = 0
+	}
+	return t.maxDelay * time.Duration(t.lowWatermark-remaining) / time.Duration(t.lowWatermark)
+}
+
+func (t *Throttler) record(k throttleKey, resp *http.Response) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	st, ok := t.states[k]
+	if !ok {
+		st = &throttleState{}
+		t.states[k] = st
+	}
+	if v, err := strconv.Atoi(resp.Header.Get(headerRemainingPrefix + k.class)); err == nil {
+		st.remaining = v
+		st.known = true
+	}
+	if resp.StatusCode == http.StatusTooManyRequests {
+		st.blockedUntil = t.now().Add(retryAfter(resp, t.now()))
+		return
+	}
+	// Any other response means Azure is accepting requests again.
+	st.blockedUntil = time.Time{}
+}
+
+// retryAfter returns the period specified by the supplied response's
+// Retry-After header, which may be either a number of seconds or a date.
+func retryAfter(resp *http.Response, now time.Time) time.Duration {
+	ra := resp.Header.Get(headerRetryAfter)
+	if s, err := strconv.Atoi(ra); err == nil && s > 0 {
+		return time.Duration(s) * time.Second
+	}
+	if t, err := http.ParseTime(ra); err == nil && t.After(now) {
+		return t.Sub(now)
+	}
+	return defaultRetryAfter
+}
+
+// keyFor returns the throttling scope and class of the supplied request.
+// Azure Resource Manager throttles each subscription separately; requests
+// outside a subscription are scoped to their host.
+func keyFor(r *http.Request) throttleKey {
+	return throttleKey{scope: requestScope(r.URL), class: requestClass(r.Method)}
+}
+
+func requestScope(u *url.URL) string {
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	for i := 0; i < len(parts)-1; i++ {
+		if strings.EqualFold(parts[i], "subscriptions") && parts[i+1] != "" {
+			return "subscription " + strings.ToLower(parts[i+1])
+		}
+	}
+	return u.Host
+}
+
+func requestClass(method string) string {
+	switch method {
+	case http.MethodGet, http.MethodHead:
+		return requestClassReads
+	case http.MethodDelete:
+		return requestClassDeletes
+	default:
+		return requestClassWrites
+	}
+}
+
+// WithoutThrottledRetries returns a SendDecorator that applies the supplied
+// SendDecorator, but stops it from retrying a request that Azure throttled.
+// Throttled requests are not retried in place; they fail fast so that the
+// reconcile is requeued rather than blocking a worker for the Retry-After
+// period. The Azure SDK's retry decorators wait for as long as Azure asks
+// them to, and can only be interrupted by cancelling the request's context,
+// so the throttled response is buffered and the context cancelled.
+func WithoutThrottledRetries(d autorest.SendDecorator) autorest.SendDecorator {
+	return func(s autorest.Sender) autorest.Sender {
+		return autorest.SenderFunc(func(r *http.Request) (*http.Response, error) {
+			ctx, cancel := context.WithCancel(r.Context())
+			defer cancel()
+
+			var throttled *http.Response
+			var throttledErr error
+			resp, err := d(autorest.SenderFunc(func(r *http.Request) (*http.Response, error) {
+				resp, err := s.Do(r)
+				if err != nil || resp.StatusCode != http.StatusTooManyRequests {
+					return resp, err
+				}
+				throttled, throttledErr = resp, buffer(resp)
+				cancel()
+				return resp, nil
+			})).Do(r.WithContext(ctx))
+			if throttled != nil {
+				return throttled, throttledErr
+			}
+			return resp, err
+		})
+	}
+}
+
+// buffer reads the body of the supplied response into memory, so that it can
+// be read after the request's context was cancelled.
+func buffer(resp *http.Response) error {
+	if resp.Body == nil {
+		return nil
+	}
+	defer resp.Body.Close() //nolint:errcheck // Only reading.
+	b, err := io.ReadAll(resp.Body)
+	resp.Body = io.NopCloser(bytes.NewReader(b))
+	return err
+}
+
+// ConfigureClient configures the supplied Azure SDK client to authorize its
+// requests using the supplied authorizer, to send them through the
+// DefaultThrottler, and to record them in the DefaultRequestMetrics and in
+// any trace in their context. Requests that would change an external resource
+// of a dry-run managed resource are planned rather than sent, so every client
+// a controller uses must be configured by it. Like the Azure SDK's defaults,
+// requests are retried, and resource providers that the subscription is not
+// registered for are registered, but throttled requests are not retried.
+func ConfigureClient(c *autorest.Client, auth autorest.Authorizer) {
+	c.Authorizer = auth
+	s := c.Sender
+	if s == nil {
+		s = autorest.CreateSender()
+	}
+	c.Sender = autorest.DecorateSender(s, DefaultThrottler.WithRateLimitTracking(), DefaultRequestMetrics.WithMetrics(), WithTracing(), WithDryRun())
+	c.SendDecorators = []autorest.SendDecorator{
+		WithoutThrottledRetries(azure.DoRetryWithRegistration(*c)),
+		DefaultThrottler.WithThrottling(),
+	}
+}
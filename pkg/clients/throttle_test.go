@@ -0,0 +1,235 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azure
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/services/resources/mgmt/2018-05-01/resources"
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+)
+
+func TestIsThrottled(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		err    error
+		want   bool
+	}{
+		"Nil": {
+			reason: "A nil error is not throttled",
+		},
+		"TooManyRequests": {
+			reason: "An HTTP 429 error is throttled",
+			err:    errors.Wrap(autorest.DetailedError{StatusCode: http.StatusTooManyRequests}, "boom"),
+			want:   true,
+		},
+		"ThrottledError": {
+			reason: "A request held back by a Throttler is throttled",
+			err:    autorest.NewErrorWithError(&ThrottledError{}, "resources.GroupsClient", "Get", nil, "Failure sending request"),
+			want:   true,
+		},
+		"NotFound": {
+			reason: "Other errors are not throttled",
+			err:    autorest.DetailedError{StatusCode: http.StatusNotFound},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := IsThrottled(tc.err)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nIsThrottled(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestThrottlerDelay(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		state  throttleState
+		want   time.Duration
+	}{
+		"Unknown": {
+			reason: "Requests should not be delayed until Azure has reported a remaining quota",
+		},
+		"Plenty": {
+			reason: "Requests should not be delayed while plenty remain",
+			state:  throttleState{remaining: 11999, known: true},
+		},
+		"Low": {
+			reason: "Requests should be delayed in proportion to how few remain",
+			state:  throttleState{remaining: 75, known: true},
+			want:   2500 * time.Millisecond,
+		},
+		"Exhausted": {
+			reason: "Requests should be delayed by the maximum delay when none remain",
+			state:  throttleState{remaining: 0, known: true},
+			want:   10 * time.Second,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			th := NewThrottler(100, 10*time.Second)
+			got := th.delay(&tc.state)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nt.delay(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestThrottlerAdmit(t *testing.T) {
+	now := time.Now()
+	th := NewThrottler(100, 10*time.Second)
+	th.now = func() time.Time { return now }
+	k := throttleKey{scope: "subscription abc", class: requestClassWrites}
+	th.states[k] = &throttleState{remaining: 75, known: true}
+
+	// The first request should be sent without waiting.
+	if err := th.admit(k); err != nil {
+		t.Fatalf("t.admit(...): want no error for the first request, got %v", err)
+	}
+
+	// A request sent too soon after it should fail rather than wait.
+	err := th.admit(k)
+	var tErr *ThrottledError
+	if !errors.As(err, &tErr) {
+		t.Fatalf("t.admit(...): want *ThrottledError, got %v", err)
+	}
+	if diff := cmp.Diff(2500*time.Millisecond, tErr.RetryAfter); diff != "" {
+		t.Errorf("t.admit(...): -want retry after, +got:\n%s", diff)
+	}
+
+	// Once the delay has passed another request should be sent.
+	now = now.Add(2500 * time.Millisecond)
+	if err := th.admit(k); err != nil {
+		t.Errorf("t.admit(...): want no error once the delay has passed, got %v", err)
+	}
+}
+
+func TestRequestScope(t *testing.T) {
+	cases := map[string]struct {
+		url  string
+		want string
+	}{
+		"Subscription": {
+			url:  "https://management.azure.com/subscriptions/ABC/resourceGroups/rg?api-version=2019-05-01",
+			want: "subscription abc",
+		},
+		"Host": {
+			url:  "https://myvault.vault.azure.net/secrets/foo",
+			want: "myvault.vault.azure.net",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			u, _ := url.Parse(tc.url)
+			if diff := cmp.Diff(tc.want, requestScope(u)); diff != "" {
+				t.Errorf("requestScope(%q): -want, +got:\n%s", tc.url, diff)
+			}
+		})
+	}
+}
+
+func TestConfigureClient(t *testing.T) {
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set(headerRemainingPrefix+requestClassReads, strconv.Itoa(11999))
+		if r.URL.Path == "/subscriptions/throttled/resourcegroups/rg" {
+			w.Header().Set(headerRetryAfter, "60")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		_, _ = w.Write([]byte(`{"name":"rg"}`))
+	}))
+	defer srv.Close()
+
+	get := func(sub string) error {
+		cl := resources.NewGroupsClientWithBaseURI(srv.URL, sub)
+		ConfigureClient(&cl.Client, autorest.NullAuthorizer{})
+		_, err := cl.Get(context.Background(), "rg")
+		return err
+	}
+
+	// A throttled request should fail fast, rather than being retried.
+	if err := get("throttled"); !IsThrottled(err) {
+		t.Fatalf("Get(...): want throttled error, got %v", err)
+	}
+	if diff := cmp.Diff(1, calls); diff != "" {
+		t.Errorf("Get(...): a 429 should not be retried: -want calls, +got calls:\n%s", diff)
+	}
+
+	// Further requests to the throttled subscription should not be sent.
+	err := get("throttled")
+	var tErr *ThrottledError
+	if !errors.As(err, &tErr) {
+		t.Fatalf("Get(...): want *ThrottledError, got %v", err)
+	}
+	if diff := cmp.Diff(1, calls); diff != "" {
+		t.Errorf("Get(...): a throttled subscription should not be called: -want calls, +got calls:\n%s", diff)
+	}
+	if tErr.RetryAfter <= 0 || tErr.RetryAfter > time.Minute {
+		t.Errorf("Get(...): want a retry after of up to one minute, got %s", tErr.RetryAfter)
+	}
+
+	// Other subscriptions should not be affected.
+	if err := get("other"); err != nil {
+		t.Errorf("Get(...): want no error for another subscription, got %v", err)
+	}
+}
+
+func TestConfigureClientRegistration(t *testing.T) {
+	registered := false
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/subscriptions/sub/providers/Microsoft.Resources/register":
+			registered = true
+			_, _ = w.Write([]byte(`{"registrationState":"Registering"}`))
+		case r.URL.Path == "/subscriptions/sub/providers/Microsoft.Resources":
+			_, _ = w.Write([]byte(`{"registrationState":"Registered"}`))
+		case !registered:
+			w.WriteHeader(http.StatusConflict)
+			_, _ = w.Write([]byte(`{"error":{"code":"MissingSubscriptionRegistration","message":"not registered","details":[{"code":"MissingSubscriptionRegistration","target":"Microsoft.Resources"}]}}`))
+		default:
+			_, _ = w.Write([]byte(`{"name":"rg"}`))
+		}
+	}))
+	defer srv.Close()
+
+	cl := resources.NewGroupsClientWithBaseURI(srv.URL, "sub")
+	ConfigureClient(&cl.Client, autorest.NullAuthorizer{})
+	if _, err := cl.Get(context.Background(), "rg"); err != nil {
+		t.Fatalf("Get(...): want the resource provider to be registered, got %v", err)
+	}
+	if !registered {
+		t.Errorf("Get(...): want the resource provider to be registered")
+	}
+}
@@ -0,0 +1,157 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azure
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/Azure/go-autorest/autorest"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	"github.com/crossplane-contrib/provider-azure/pkg/tracing"
+)
+
+// Headers Azure uses to identify requests.
+const (
+	// HeaderRequestID is the header in which Azure returns the ID it assigned
+	// to a request.
+	HeaderRequestID = "x-ms-request-id"
+
+	// HeaderCorrelationRequestID is the header in which Azure Resource
+	// Manager returns the ID that correlates all requests it made to fulfil a
+	// request.
+	HeaderCorrelationRequestID = "x-ms-correlation-request-id"
+
+	// HeaderClientRequestID is the header in which clients may send an ID of
+	// their own for a request.
+	HeaderClientRequestID = "x-ms-client-request-id"
+)
+
+// Span attributes.
+const (
+	attrService              = attribute.Key("azure.service")
+	attrOperation            = attribute.Key("azure.operation")
+	attrRequestID            = attribute.Key("azure.request_id")
+	attrCorrelationRequestID = attribute.Key("azure.correlation_request_id")
+	attrClientRequestID      = attribute.Key("azure.client_request_id")
+)
+
+// WithTracing returns a SendDecorator that covers every attempt to send a
+// request with a span, as a child of any span in the request's context. The
+// span records the IDs Azure assigned to the request.
+func WithTracing() autorest.SendDecorator {
+	return func(s autorest.Sender) autorest.Sender {
+		return autorest.SenderFunc(func(r *http.Request) (*http.Response, error) {
+			service, resourceType := describeRequest(r.URL)
+			u := *r.URL
+			u.RawQuery = ""
+			ctx, span := tracing.Tracer().Start(r.Context(), r.Method+" "+service+"/"+resourceType,
+				trace.WithSpanKind(trace.SpanKindClient),
+				trace.WithAttributes(
+					semconv.HTTPMethodKey.String(r.Method),
+					semconv.HTTPURLKey.String(u.String()),
+					attrService.String(service),
+					attrOperation.String(resourceType),
+				))
+			defer span.End()
+
+			resp, err := s.Do(r.WithContext(ctx))
+			if err != nil {
+				span.RecordError(err)
+				span.SetStatus(codes.Error, err.Error())
+			}
+			if resp == nil {
+				return resp, err
+			}
+			span.SetAttributes(semconv.HTTPStatusCodeKey.Int(resp.StatusCode))
+			for k, h := range map[attribute.Key]string{
+				attrRequestID:            HeaderRequestID,
+				attrCorrelationRequestID: HeaderCorrelationRequestID,
+				attrClientRequestID:      HeaderClientRequestID,
+			} {
+				if v := resp.Header.Get(h); v != "" {
+					span.SetAttributes(k.String(v))
+				}
+			}
+			if resp.StatusCode >= http.StatusBadRequest {
+				span.SetStatus(codes.Error, http.StatusText(resp.StatusCode))
+			}
+			return resp, err
+		})
+	}
+}
+
+// TraceCalls returns a ConnecterWrapper that covers each call to connect to,
+// observe, create, update or delete an external resource with a span.
+func TraceCalls() ConnecterWrapper {
+	return func(c managed.ExternalConnecter) managed.ExternalConnecter {
+		return &tracingConnecter{ExternalConnecter: c}
+	}
+}
+
+type tracingConnecter struct {
+	managed.ExternalConnecter
+}
+
+func (c *tracingConnecter) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
+	ctx, end := tracing.StartSpan(ctx, "Connect")
+	e, err := c.ExternalConnecter.Connect(ctx, mg)
+	end(err)
+	if err != nil {
+		return nil, err
+	}
+	return &tracingExternal{ExternalClient: e}, nil
+}
+
+type tracingExternal struct {
+	managed.ExternalClient
+}
+
+func (e *tracingExternal) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	ctx, end := tracing.StartSpan(ctx, "Observe")
+	o, err := e.ExternalClient.Observe(ctx, mg)
+	end(err)
+	return o, err
+}
+
+func (e *tracingExternal) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
+	ctx, end := tracing.StartSpan(ctx, "Create")
+	c, err := e.ExternalClient.Create(ctx, mg)
+	end(err)
+	return c, err
+}
+
+func (e *tracingExternal) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
+	ctx, end := tracing.StartSpan(ctx, "Update")
+	u, err := e.ExternalClient.Update(ctx, mg)
+	end(err)
+	return u, err
+}
+
+func (e *tracingExternal) Delete(ctx context.Context, mg resource.Managed) error {
+	ctx, end := tracing.StartSpan(ctx, "Delete")
+	err := e.ExternalClient.Delete(ctx, mg)
+	end(err)
+	return err
+}
@@ -0,0 +1,116 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azure
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/services/resources/mgmt/2018-05-01/resources"
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+)
+
+func TestWithTracing(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set(HeaderRequestID, "request")
+		w.Header().Set(HeaderCorrelationRequestID, "correlation")
+		_, _ = w.Write([]byte(`{"name":"rg"}`))
+	}))
+	defer srv.Close()
+
+	sr := tracetest.NewSpanRecorder()
+	prev := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr)))
+	defer otel.SetTracerProvider(prev)
+
+	cl := resources.NewGroupsClientWithBaseURI(srv.URL, "sub")
+	cl.Authorizer = autorest.NullAuthorizer{}
+	cl.Sender = autorest.DecorateSender(autorest.CreateSender(), WithTracing())
+
+	ctx, parent := otel.Tracer("test").Start(context.Background(), "Reconcile")
+	if _, err := cl.Get(ctx, "rg"); err != nil {
+		t.Fatalf("Get(...): %s", err)
+	}
+	parent.End()
+
+	spans := sr.Ended()
+	if diff := cmp.Diff(2, len(spans)); diff != "" {
+		t.Fatalf("WithTracing(): -want spans, +got spans:\n%s", diff)
+	}
+	got := spans[0]
+	if diff := cmp.Diff(parent.SpanContext().SpanID(), got.Parent().SpanID()); diff != "" {
+		t.Errorf("WithTracing(): the request span should be a child of the span in its context: -want, +got:\n%s", diff)
+	}
+	if diff := cmp.Diff("GET Microsoft.Resources/resourcegroups", got.Name()); diff != "" {
+		t.Errorf("WithTracing(): -want name, +got name:\n%s", diff)
+	}
+	attrs := map[attribute.Key]string{}
+	for _, kv := range got.Attributes() {
+		attrs[kv.Key] = kv.Value.Emit()
+	}
+	want := map[attribute.Key]string{
+		attrRequestID:            "request",
+		attrCorrelationRequestID: "correlation",
+		"http.status_code":       "200",
+	}
+	for k, v := range want {
+		if diff := cmp.Diff(v, attrs[k]); diff != "" {
+			t.Errorf("WithTracing(): attribute %s: -want, +got:\n%s", k, diff)
+		}
+	}
+}
+
+func TestTraceCalls(t *testing.T) {
+	sr := tracetest.NewSpanRecorder()
+	prev := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr)))
+	defer otel.SetTracerProvider(prev)
+
+	errBoom := errors.New("boom")
+	mg := &fake.Managed{}
+	e := connect(t, &managed.ExternalClientFns{
+		ObserveFn: func(_ context.Context, _ resource.Managed) (managed.ExternalObservation, error) {
+			return managed.ExternalObservation{}, nil
+		},
+		DeleteFn: func(_ context.Context, _ resource.Managed) error { return errBoom },
+	}, mg, TraceCalls())
+	_, _ = e.Observe(context.Background(), mg)
+	_ = e.Delete(context.Background(), mg)
+
+	got := map[string]codes.Code{}
+	for _, s := range sr.Ended() {
+		got[s.Name()] = s.Status().Code
+	}
+	want := map[string]codes.Code{"Connect": codes.Unset, "Observe": codes.Unset, "Delete": codes.Error}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("TraceCalls(): -want span statuses, +got:\n%s", diff)
+	}
+}
@@ -42,6 +42,7 @@ import (
 	"github.com/crossplane-contrib/provider-azure/pkg/controller/resourcegroup"
 	"github.com/crossplane-contrib/provider-azure/pkg/controller/storage/account"
 	"github.com/crossplane-contrib/provider-azure/pkg/controller/storage/container"
+	"github.com/crossplane-contrib/provider-azure/pkg/controller/storage/managementpolicy"
 )
 
 // Setup Azure controllers.
@@ -64,6 +65,7 @@ func Setup(mgr ctrl.Manager, o controller.Options) error {
 		resourcegroup.Setup,
 		account.Setup,
 		container.Setup,
+		managementpolicy.Setup,
 		secret.SetupSecret,
 		zone.Setup,
 		recordset.Setup,
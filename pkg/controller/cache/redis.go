@@ -18,10 +18,12 @@ package cache
 
 import (
 	"context"
+	"net/http"
 	"strconv"
 
 	"github.com/Azure/azure-sdk-for-go/services/redis/mgmt/2018-03-01/redis"
 	"github.com/Azure/azure-sdk-for-go/services/redis/mgmt/2018-03-01/redis/redisapi"
+	"github.com/Azure/go-autorest/autorest"
 	"github.com/pkg/errors"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -51,6 +53,7 @@ const (
 	errCreateFailed         = "cannot create the Redis instance"
 	errUpdateFailed         = "cannot update the Redis instance"
 	errDeleteFailed         = "cannot delete the Redis instance"
+	errFetchLastOperation   = "cannot fetch last operation"
 )
 
 // SetupRedis adds a controller that reconciles Redis resources.
@@ -62,17 +65,18 @@ func SetupRedis(mgr ctrl.Manager, o controller.Options) error {
 		cps = append(cps, connection.NewDetailsManager(mgr.GetClient(), v1alpha1.StoreConfigGroupVersionKind))
 	}
 
+	recorder := event.NewAPIRecorder(mgr.GetEventRecorderFor(name))
+
 	return ctrl.NewControllerManagedBy(mgr).
 		Named(name).
 		WithOptions(o.ForControllerRuntime()).
 		For(&v1beta1.Redis{}).
-		Complete(managed.NewReconciler(mgr,
-			resource.ManagedKind(v1beta1.RedisGroupVersionKind),
-			managed.WithExternalConnecter(&connector{kube: mgr.GetClient()}),
+		Complete(azure.NewReconciler(mgr, name, v1beta1.RedisGroupVersionKind,
+			managed.WithExternalConnecter(azure.NewConnecter(&connector{kube: mgr.GetClient()}, recorder)),
 			managed.WithReferenceResolver(managed.NewAPISimpleReferenceResolver(mgr.GetClient())),
 			managed.WithPollInterval(o.PollInterval),
 			managed.WithLogger(o.Logger.WithValues("controller", name)),
-			managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
+			managed.WithRecorder(recorder),
 			managed.WithConnectionPublishers(cps...)))
 }
 
@@ -85,14 +89,20 @@ func (c connector) Connect(ctx context.Context, mg resource.Managed) (managed.Ex
 	if err != nil {
 		return nil, errors.Wrap(err, errConnectFailed)
 	}
-	cl := redis.NewClient(creds[azure.CredentialsKeySubscriptionID])
-	cl.Authorizer = auth
-	return &external{kube: c.kube, client: cl}, nil
+	cl := redis.NewClientWithBaseURI(creds[azure.CredentialsKeyResourceManagerEndpointURL], creds[azure.CredentialsKeySubscriptionID])
+	azure.ConfigureClient(&cl.Client, auth)
+	tags, err := azure.DefaultTags(ctx, c.kube, mg)
+	if err != nil {
+		return nil, errors.Wrap(err, errConnectFailed)
+	}
+	return &external{kube: c.kube, client: cl, sender: cl.Client, defaultTags: tags}, nil
 }
 
 type external struct {
-	kube   client.Client
-	client redisapi.ClientAPI
+	kube        client.Client
+	client      redisapi.ClientAPI
+	sender      autorest.Sender
+	defaultTags map[string]string
 }
 
 func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
@@ -101,15 +111,29 @@ func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 		return managed.ExternalObservation{}, errors.New(errNotRedis)
 	}
 	cache, err := c.client.Get(ctx, cr.Spec.ForProvider.ResourceGroupName, meta.GetExternalName(cr))
+	if azure.IsNotFound(err) {
+		forgotten := azure.RestoreCreateOperation(cr, &cr.Status.AtProvider.LastOperation)
+		if err := azure.FetchAsyncOperation(ctx, c.sender, &cr.Status.AtProvider.LastOperation); err != nil {
+			return managed.ExternalObservation{}, errors.Wrap(err, errFetchLastOperation)
+		}
+		// A creation that is still in progress must not be started again.
+		return managed.ExternalObservation{ResourceExists: azure.IsOperationInProgress(cr.Status.AtProvider.LastOperation, http.MethodPut), ResourceLateInitialized: forgotten}, nil
+	}
 	if err != nil {
-		return managed.ExternalObservation{ResourceExists: false}, errors.Wrap(resource.Ignore(azure.IsNotFound, err), errGetFailed)
+		return managed.ExternalObservation{}, errors.Wrap(err, errGetFailed)
 	}
 
-	redisclients.LateInitialize(&cr.Spec.ForProvider, cache)
+	redisclients.LateInitialize(&cr.Spec.ForProvider, cache, c.defaultTags)
 	if err := c.kube.Update(ctx, cr); err != nil {
 		return managed.ExternalObservation{}, errors.Wrap(err, errUpdateRedisCRFailed)
 	}
+	op := cr.Status.AtProvider.LastOperation
+	forgotten := azure.RestoreCreateOperation(cr, &op)
 	cr.Status.AtProvider = redisclients.GenerateObservation(cache)
+	cr.Status.AtProvider.LastOperation = op
+	if err := azure.FetchAsyncOperation(ctx, c.sender, &cr.Status.AtProvider.LastOperation); err != nil {
+		return managed.ExternalObservation{}, errors.Wrap(err, errFetchLastOperation)
+	}
 
 	var conn managed.ConnectionDetails
 	switch cr.Status.AtProvider.ProvisioningState {
@@ -131,10 +155,13 @@ func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 	default:
 		cr.Status.SetConditions(xpv1.Unavailable())
 	}
+	diff := redisclients.NeedsUpdate(cr.Spec.ForProvider, cache, c.defaultTags)
+	azure.ReportDiff(ctx, diff)
 	return managed.ExternalObservation{
-		ResourceExists:    true,
-		ResourceUpToDate:  !redisclients.NeedsUpdate(cr.Spec.ForProvider, cache),
-		ConnectionDetails: conn,
+		ResourceExists:          true,
+		ResourceUpToDate:        diff.UpToDate() && azure.OwnershipTagsUpToDate(cr, cache.Tags),
+		ResourceLateInitialized: forgotten,
+		ConnectionDetails:       conn,
 	}, nil
 }
 
@@ -144,7 +171,9 @@ func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.Ext
 		return managed.ExternalCreation{}, errors.New(errNotRedis)
 	}
 	cr.Status.SetConditions(xpv1.Creating())
-	_, err := c.client.Create(ctx, cr.Spec.ForProvider.ResourceGroupName, meta.GetExternalName(cr), redisclients.NewCreateParameters(cr))
+	op, err := c.client.Create(ctx, cr.Spec.ForProvider.ResourceGroupName, meta.GetExternalName(cr), redisclients.NewCreateParameters(cr, c.defaultTags))
+	cr.Status.AtProvider.LastOperation = azure.NewAsyncOperation(http.MethodPut, op.FutureAPI, err)
+	azure.RecordCreateOperation(cr, cr.Status.AtProvider.LastOperation)
 	return managed.ExternalCreation{}, errors.Wrap(err, errCreateFailed)
 }
 
@@ -155,18 +184,22 @@ func (c *external) Update(ctx context.Context, mg resource.Managed) (managed.Ext
 	}
 	// NOTE(muvaf): redis service rejects updates while another operation
 	// is ongoing.
-	if cr.Status.AtProvider.ProvisioningState != redisclients.ProvisioningStateSucceeded {
+	if cr.Status.AtProvider.ProvisioningState != redisclients.ProvisioningStateSucceeded ||
+		azure.IsOperationInProgress(cr.Status.AtProvider.LastOperation) {
 		return managed.ExternalUpdate{}, nil
 	}
 	cache, err := c.client.Get(ctx, cr.Spec.ForProvider.ResourceGroupName, meta.GetExternalName(cr))
 	if err != nil {
 		return managed.ExternalUpdate{}, errors.Wrap(err, errGetFailed)
 	}
-	_, err = c.client.Update(
-		ctx,
-		cr.Spec.ForProvider.ResourceGroupName,
-		meta.GetExternalName(cr),
-		redisclients.NewUpdateParameters(cr.Spec.ForProvider, cache))
+	patch := redisclients.NewUpdateParameters(cr.Spec.ForProvider, cache, c.defaultTags)
+	if patch.Tags != nil || !azure.OwnershipTagsUpToDate(cr, cache.Tags) {
+		// Tags are replaced as a whole, so the ownership tags must be
+		// sent with any change to them.
+		patch.Tags = azure.NewTags(cr, c.defaultTags, cr.Spec.ForProvider.Tags)
+	}
+	_, err = c.client.Update(ctx, cr.Spec.ForProvider.ResourceGroupName, meta.GetExternalName(cr), patch)
+	cr.Status.AtProvider.LastOperation = azure.NewAsyncOperation(http.MethodPatch, nil, err)
 	return managed.ExternalUpdate{}, errors.Wrap(err, errUpdateFailed)
 }
 
@@ -179,6 +212,8 @@ func (c *external) Delete(ctx context.Context, mg resource.Managed) error {
 	if cr.Status.AtProvider.ProvisioningState == redisclients.ProvisioningStateDeleting {
 		return nil
 	}
-	_, err := c.client.Delete(ctx, cr.Spec.ForProvider.ResourceGroupName, meta.GetExternalName(cr))
-	return errors.Wrap(resource.Ignore(azure.IsNotFound, err), errDeleteFailed)
+	op, err := c.client.Delete(ctx, cr.Spec.ForProvider.ResourceGroupName, meta.GetExternalName(cr))
+	err = resource.Ignore(azure.IsNotFound, err)
+	cr.Status.AtProvider.LastOperation = azure.NewAsyncOperation(http.MethodDelete, op.FutureAPI, err)
+	return errors.Wrap(err, errDeleteFailed)
 }
@@ -18,24 +18,35 @@ package cache
 
 import (
 	"context"
+	"io/ioutil"
 	"net/http"
 	"strconv"
+	"strings"
 	"testing"
 
 	"github.com/Azure/azure-sdk-for-go/services/redis/mgmt/2018-03-01/redis"
 	"github.com/Azure/azure-sdk-for-go/services/redis/mgmt/2018-03-01/redis/redisapi"
 	"github.com/Azure/go-autorest/autorest"
+	azureautorest "github.com/Azure/go-autorest/autorest/azure"
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/pkg/meta"
 	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	xpfake "github.com/crossplane/crossplane-runtime/pkg/resource/fake"
 	"github.com/crossplane/crossplane-runtime/pkg/test"
 
 	"github.com/crossplane-contrib/provider-azure/apis/cache/v1beta1"
+	apisv1alpha3 "github.com/crossplane-contrib/provider-azure/apis/v1alpha3"
 	azure "github.com/crossplane-contrib/provider-azure/pkg/clients"
+	"github.com/crossplane-contrib/provider-azure/pkg/clients/armtest"
 	redisclient "github.com/crossplane-contrib/provider-azure/pkg/clients/redis"
 	"github.com/crossplane-contrib/provider-azure/pkg/clients/redis/fake"
 )
@@ -66,6 +77,9 @@ var (
 var (
 	errorBoom          = errors.New("boom")
 	redisConfiguration = map[string]string{"cool": "socool"}
+
+	ignoreStartTime       = cmpopts.IgnoreFields(apisv1alpha3.AsyncOperation{}, "StartTime")
+	ignoreCreateOperation = cmpopts.IgnoreMapEntries(func(k, _ string) bool { return k == azure.AnnotationKeyCreateOperation })
 )
 
 type redisResourceModifier func(*v1beta1.Redis)
@@ -86,6 +100,10 @@ func withPort(p int) redisResourceModifier {
 	return func(r *v1beta1.Redis) { r.Status.AtProvider.Port = p }
 }
 
+func withLastOperation(op apisv1alpha3.AsyncOperation) redisResourceModifier {
+	return func(r *v1beta1.Redis) { r.Status.AtProvider.LastOperation = op }
+}
+
 func instance(rm ...redisResourceModifier) *v1beta1.Redis {
 	r := &v1beta1.Redis{
 		Spec: v1beta1.RedisSpec{
@@ -199,6 +217,20 @@ func TestObserve(t *testing.T) {
 				err: errors.Wrap(errorBoom, errGetFailed),
 			},
 		},
+		"CreationInProgress": {
+			args: args{
+				cr: instance(withLastOperation(apisv1alpha3.AsyncOperation{Method: http.MethodPut, Status: azure.AsyncOperationStatusInProgress})),
+				r: &fake.MockClient{
+					MockGet: func(_ context.Context, resourceGroupName string, name string) (result redis.ResourceType, err error) {
+						return redis.ResourceType{}, autorest.DetailedError{StatusCode: http.StatusNotFound}
+					},
+				},
+			},
+			want: want{
+				cr: instance(withLastOperation(apisv1alpha3.AsyncOperation{Method: http.MethodPut, Status: azure.AsyncOperationStatusInProgress})),
+				o:  managed.ExternalObservation{ResourceExists: true},
+			},
+		},
 		"KubeUpdateFailed": {
 			args: args{
 				cr: instance(),
@@ -325,7 +357,7 @@ func TestObserve(t *testing.T) {
 				client: tc.r,
 			}
 			o, err := e.Observe(context.Background(), tc.args.cr)
-			if diff := cmp.Diff(tc.want.cr, tc.args.cr); diff != "" {
+			if diff := cmp.Diff(tc.want.cr, tc.args.cr, ignoreStartTime); diff != "" {
 				t.Errorf("Observe(...): -want, +got\n%s", diff)
 			}
 			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
@@ -364,6 +396,7 @@ func TestCreate(t *testing.T) {
 			want: want{
 				cr: instance(
 					withConditions(xpv1.Creating()),
+					withLastOperation(apisv1alpha3.AsyncOperation{Method: http.MethodPut, Status: azure.AsyncOperationStatusSucceeded}),
 				),
 			},
 		},
@@ -379,6 +412,7 @@ func TestCreate(t *testing.T) {
 			want: want{
 				cr: instance(
 					withConditions(xpv1.Creating()),
+					withLastOperation(apisv1alpha3.AsyncOperation{Method: http.MethodPut, Status: azure.AsyncOperationStatusFailed, ErrorMessage: errorBoom.Error()}),
 				),
 				err: errors.Wrap(errorBoom, errCreateFailed),
 			},
@@ -390,7 +424,7 @@ func TestCreate(t *testing.T) {
 			e := external{client: tc.r}
 
 			c, err := e.Create(context.Background(), tc.args.cr)
-			if diff := cmp.Diff(tc.want.cr, tc.args.cr); diff != "" {
+			if diff := cmp.Diff(tc.want.cr, tc.args.cr, ignoreStartTime, ignoreCreateOperation); diff != "" {
 				t.Errorf("Create(...): -want, +got\n%s", diff)
 			}
 			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
@@ -430,7 +464,10 @@ func TestUpdate(t *testing.T) {
 				},
 			},
 			want: want{
-				cr: instance(withProvisioningState(redisclient.ProvisioningStateSucceeded)),
+				cr: instance(
+					withProvisioningState(redisclient.ProvisioningStateSucceeded),
+					withLastOperation(apisv1alpha3.AsyncOperation{Method: http.MethodPatch, Status: azure.AsyncOperationStatusSucceeded}),
+				),
 			},
 		},
 		"NotReady": {
@@ -441,6 +478,20 @@ func TestUpdate(t *testing.T) {
 				cr: instance(withProvisioningState(redisclient.ProvisioningStateFailed)),
 			},
 		},
+		"OperationInProgress": {
+			args: args{
+				cr: instance(
+					withProvisioningState(redisclient.ProvisioningStateSucceeded),
+					withLastOperation(apisv1alpha3.AsyncOperation{Method: http.MethodPut, Status: azure.AsyncOperationStatusInProgress}),
+				),
+			},
+			want: want{
+				cr: instance(
+					withProvisioningState(redisclient.ProvisioningStateSucceeded),
+					withLastOperation(apisv1alpha3.AsyncOperation{Method: http.MethodPut, Status: azure.AsyncOperationStatusInProgress}),
+				),
+			},
+		},
 		"GetFailed": {
 			args: args{
 				cr: instance(withProvisioningState(redisclient.ProvisioningStateSucceeded)),
@@ -468,7 +519,10 @@ func TestUpdate(t *testing.T) {
 				},
 			},
 			want: want{
-				cr:  instance(withProvisioningState(redisclient.ProvisioningStateSucceeded)),
+				cr: instance(
+					withProvisioningState(redisclient.ProvisioningStateSucceeded),
+					withLastOperation(apisv1alpha3.AsyncOperation{Method: http.MethodPatch, Status: azure.AsyncOperationStatusFailed, ErrorMessage: errorBoom.Error()}),
+				),
 				err: errors.Wrap(errorBoom, errUpdateFailed),
 			},
 		},
@@ -479,7 +533,7 @@ func TestUpdate(t *testing.T) {
 			e := external{client: tc.r}
 
 			c, err := e.Update(context.Background(), tc.args.cr)
-			if diff := cmp.Diff(tc.want.cr, tc.args.cr); diff != "" {
+			if diff := cmp.Diff(tc.want.cr, tc.args.cr, ignoreStartTime); diff != "" {
 				t.Errorf("Update(...): -want, +got\n%s", diff)
 			}
 			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
@@ -517,6 +571,7 @@ func TestDelete(t *testing.T) {
 			want: want{
 				cr: instance(
 					withConditions(xpv1.Deleting()),
+					withLastOperation(apisv1alpha3.AsyncOperation{Method: http.MethodDelete, Status: azure.AsyncOperationStatusSucceeded}),
 				),
 			},
 		},
@@ -532,6 +587,7 @@ func TestDelete(t *testing.T) {
 			want: want{
 				cr: instance(
 					withConditions(xpv1.Deleting()),
+					withLastOperation(apisv1alpha3.AsyncOperation{Method: http.MethodDelete, Status: azure.AsyncOperationStatusSucceeded}),
 				),
 			},
 		},
@@ -556,7 +612,10 @@ func TestDelete(t *testing.T) {
 				},
 			},
 			want: want{
-				cr:  instance(withConditions(xpv1.Deleting())),
+				cr: instance(
+					withConditions(xpv1.Deleting()),
+					withLastOperation(apisv1alpha3.AsyncOperation{Method: http.MethodDelete, Status: azure.AsyncOperationStatusFailed, ErrorMessage: errorBoom.Error()}),
+				),
 				err: errors.Wrap(errorBoom, errDeleteFailed),
 			},
 		},
@@ -567,7 +626,7 @@ func TestDelete(t *testing.T) {
 			e := external{client: tc.r}
 
 			err := e.Delete(context.Background(), tc.args.cr)
-			if diff := cmp.Diff(tc.want.cr, tc.args.cr); diff != "" {
+			if diff := cmp.Diff(tc.want.cr, tc.args.cr, ignoreStartTime); diff != "" {
 				t.Errorf("Update(...): -want, +got\n%s", diff)
 			}
 			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
@@ -576,3 +635,154 @@ func TestDelete(t *testing.T) {
 		})
 	}
 }
+
+func TestReconcileCreateOperation(t *testing.T) {
+	future := &azureautorest.Future{}
+	if err := future.UnmarshalJSON([]byte(`{"method":"PUT","pollingMethod":"AsyncOperation","pollingURI":"https://crossplane.io/operation","lroState":"InProgress"}`)); err != nil {
+		t.Fatal(err)
+	}
+	inProgress := `{"status":"InProgress"}`
+
+	// stored is the Redis as persisted by the API server, which ignores any
+	// changes to the status of a Redis that is not updated through the status
+	// subresource.
+	stored := instance()
+	stored.SetName(name)
+	kube := &test.MockClient{
+		MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+			stored.DeepCopyInto(obj.(*v1beta1.Redis))
+			return nil
+		}),
+		MockUpdate: func(_ context.Context, obj client.Object, _ ...client.UpdateOption) error {
+			s := stored.Status
+			obj.(*v1beta1.Redis).DeepCopyInto(stored)
+			stored.Status = s
+			stored.DeepCopyInto(obj.(*v1beta1.Redis))
+			return nil
+		},
+		MockStatusUpdate: func(_ context.Context, obj client.Object, _ ...client.UpdateOption) error {
+			obj.(*v1beta1.Redis).Status.DeepCopyInto(&stored.Status)
+			return nil
+		},
+	}
+	s := runtime.NewScheme()
+	if err := v1beta1.SchemeBuilder.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+
+	creates := 0
+	e := &external{
+		client: &fake.MockClient{
+			MockGet: func(_ context.Context, _ string, _ string) (redis.ResourceType, error) {
+				return redis.ResourceType{}, autorest.DetailedError{StatusCode: http.StatusNotFound}
+			},
+			MockCreate: func(_ context.Context, _ string, _ string, _ redis.CreateParameters) (redis.CreateFuture, error) {
+				creates++
+				return redis.CreateFuture{FutureAPI: future}, nil
+			},
+		},
+		sender: autorest.SenderFunc(func(req *http.Request) (*http.Response, error) {
+			return &http.Response{
+				Request:       req,
+				StatusCode:    http.StatusAccepted,
+				Body:          ioutil.NopCloser(strings.NewReader(inProgress)),
+				ContentLength: int64(len(inProgress)),
+			}, nil
+		}),
+	}
+	r := managed.NewReconciler(&xpfake.Manager{Client: kube, Scheme: s},
+		resource.ManagedKind(v1beta1.RedisGroupVersionKind),
+		managed.WithExternalConnecter(managed.ExternalConnectorFn(func(_ context.Context, _ resource.Managed) (managed.ExternalClient, error) {
+			return e, nil
+		})),
+		managed.WithConnectionPublishers(),
+		managed.WithCreationGracePeriod(0),
+	)
+
+	// The first reconcile creates the Redis, and the others must observe
+	// that its creation is still in progress rather than create it again.
+	// Once the third has found the operation persisted in the status, the
+	// fourth must not need the annotation recording it anymore.
+	for i := 0; i < 4; i++ {
+		if _, err := r.Reconcile(context.Background(), reconcile.Request{NamespacedName: types.NamespacedName{Name: name}}); err != nil {
+			t.Fatalf("r.Reconcile(...): %s", err)
+		}
+	}
+	if diff := cmp.Diff(1, creates); diff != "" {
+		t.Errorf("r.Reconcile(...): -want creates, +got creates:\n%s", diff)
+	}
+	want := apisv1alpha3.AsyncOperation{Method: http.MethodPut, PollingURL: "https://crossplane.io/operation", Status: azure.AsyncOperationStatusInProgress}
+	if diff := cmp.Diff(want, stored.Status.AtProvider.LastOperation, ignoreStartTime); diff != "" {
+		t.Errorf("r.Reconcile(...): -want last operation, +got last operation:\n%s", diff)
+	}
+	if _, ok := stored.GetAnnotations()[azure.AnnotationKeyCreateOperation]; ok {
+		t.Errorf("r.Reconcile(...): want the create operation annotation removed once the operation is persisted in the status")
+	}
+}
+
+func TestDryRun(t *testing.T) {
+	id := "/subscriptions/" + armtest.DefaultSubscriptionID + "/resourceGroups/cool-rg/providers/Microsoft.Cache/Redis/cool-redis"
+	tags := map[string]interface{}{"team": "cache", "crossplane-kind": "Redis", "crossplane-name": "cool-redis", "crossplane-providerconfig": "armtest"}
+
+	type want struct {
+		reason xpv1.ConditionReason
+		method string
+		body   string
+		diff   azure.Diff
+	}
+	cases := map[string]struct {
+		reason string
+		redis  map[string]interface{}
+		want   want
+	}{
+		"Create": {
+			reason: "The plan should show the request body built by NewCreateParameters.",
+			want: want{
+				reason: azure.ReasonPlannedCreate,
+				method: http.MethodPut,
+				body:   `{"location":"westus","properties":{"sku":{"capacity":1,"family":"C","name":"Basic"}},"tags":{"crossplane-kind":"Redis","crossplane-name":"cool-redis","crossplane-providerconfig":"armtest","team":"cache"}}`,
+			},
+		},
+		"Update": {
+			reason: "The plan should show the request body built by NewUpdateParameters, and the fields that differ.",
+			redis: map[string]interface{}{
+				"location": "westus",
+				"tags":     tags,
+				"properties": map[string]interface{}{
+					"provisioningState": "Succeeded",
+					"sku":               map[string]interface{}{"name": "Basic", "family": "C", "capacity": 2},
+				},
+			},
+			want: want{
+				reason: azure.ReasonPlannedUpdate,
+				method: http.MethodPatch,
+				body:   `{"properties":{"sku":{"capacity":1,"family":"C","name":"Basic"}}}`,
+				diff:   azure.Diff{{Path: "sku", Desired: `{"name":"Basic","family":"C","capacity":1}`, Observed: `{"name":"Basic","family":"C","capacity":2}`}},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			s := armtest.NewServer()
+			defer s.Close()
+			if tc.redis != nil {
+				s.SetResource(id, tc.redis)
+			}
+			cr := &v1beta1.Redis{Spec: v1beta1.RedisSpec{ForProvider: v1beta1.RedisParameters{
+				Location:          "westus",
+				ResourceGroupName: "cool-rg",
+				SKU:               v1beta1.SKU{Name: "Basic", Family: "C", Capacity: 1},
+				Tags:              map[string]string{"team": "cache"},
+			}}}
+			cr.SetName("cool-redis")
+			meta.SetExternalName(cr, "cool-redis")
+
+			got := s.PlanDryRun(t, connector{kube: s.Kube(nil)}, cr)
+			p := azure.Plan{{Method: tc.want.method, URL: s.URL + id, Body: tc.want.body}}
+			if diff := cmp.Diff(azure.Planned(tc.want.reason, p, tc.want.diff), got, test.EquateConditions()); diff != "" {
+				t.Errorf("\n%s\nPlanDryRun(...): -want Planned condition, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
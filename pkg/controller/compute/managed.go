@@ -18,7 +18,9 @@ package compute
 
 import (
 	"context"
+	"net/http"
 
+	"github.com/Azure/go-autorest/autorest"
 	"github.com/Azure/go-autorest/autorest/to"
 	"github.com/pkg/errors"
 	v1 "k8s.io/api/core/v1"
@@ -50,8 +52,11 @@ const (
 	errCreateAKSCluster = "cannot create AKSCluster"
 	errGetAKSCluster    = "cannot get AKSCluster"
 	errGetKubeConfig    = "cannot get AKSCluster kubeconfig"
+	errUpdateAKSCluster = "cannot update AKSCluster tags"
 	errDeleteAKSCluster = "cannot delete AKSCluster"
 	errGetConnSecret    = "cannot get connection secret"
+
+	errFetchLastOperation = "cannot fetch last operation"
 )
 
 // SetupAKSCluster adds a controller that reconciles AKSClusters.
@@ -63,17 +68,18 @@ func SetupAKSCluster(mgr ctrl.Manager, o controller.Options) error {
 		cps = append(cps, connection.NewDetailsManager(mgr.GetClient(), v1alpha1.StoreConfigGroupVersionKind))
 	}
 
+	recorder := event.NewAPIRecorder(mgr.GetEventRecorderFor(name))
+
 	return ctrl.NewControllerManagedBy(mgr).
 		Named(name).
 		WithOptions(o.ForControllerRuntime()).
 		For(&v1alpha3.AKSCluster{}).
-		Complete(managed.NewReconciler(mgr,
-			resource.ManagedKind(v1alpha3.AKSClusterGroupVersionKind),
-			managed.WithExternalConnecter(&connecter{client: mgr.GetClient()}),
+		Complete(azure.NewReconciler(mgr, name, v1alpha3.AKSClusterGroupVersionKind,
+			managed.WithExternalConnecter(azure.NewConnecter(&connecter{client: mgr.GetClient()}, recorder)),
 			managed.WithReferenceResolver(managed.NewAPISimpleReferenceResolver(mgr.GetClient())),
 			managed.WithPollInterval(o.PollInterval),
 			managed.WithLogger(o.Logger.WithValues("controller", name)),
-			managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
+			managed.WithRecorder(recorder),
 			managed.WithConnectionPublishers(cps...)))
 }
 
@@ -86,17 +92,26 @@ func (c *connecter) Connect(ctx context.Context, mg resource.Managed) (managed.E
 	if err != nil {
 		return nil, err
 	}
-	cl, err := compute.NewAggregateClient(creds, auth)
+	// Service principals are managed through the Azure AD Graph, which
+	// requires a token of its own.
+	_, graphAuth, err := azure.GetResourceAuthInfo(ctx, c.client, mg, azure.CredentialsKeyActiveDirectoryGraphResourceID)
 	if err != nil {
 		return nil, err
 	}
-	return &external{kube: c.client, client: cl, newPasswordFn: password.Generate}, nil
+	cl := compute.NewAggregateClient(creds, auth, graphAuth)
+	tags, err := azure.DefaultTags(ctx, c.client, mg)
+	if err != nil {
+		return nil, err
+	}
+	return &external{kube: c.client, client: cl, sender: cl.GetRESTClient(), newPasswordFn: password.Generate, defaultTags: tags}, nil
 }
 
 type external struct {
 	kube          client.Client
 	client        compute.AKSClient
+	sender        autorest.Sender
 	newPasswordFn func() (password string, err error)
+	defaultTags   map[string]string
 }
 
 func (e *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
@@ -107,19 +122,33 @@ func (e *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 
 	c, err := e.client.GetManagedCluster(ctx, cr)
 	if azure.IsNotFound(err) {
-		return managed.ExternalObservation{ResourceExists: false}, nil
+		forgotten := azure.RestoreCreateOperation(cr, &cr.Status.AtProvider.LastOperation)
+		if err := azure.FetchAsyncOperation(ctx, e.sender, &cr.Status.AtProvider.LastOperation); err != nil {
+			return managed.ExternalObservation{}, errors.Wrap(err, errFetchLastOperation)
+		}
+		// A creation that is still in progress must not be started again.
+		return managed.ExternalObservation{ResourceExists: azure.IsOperationInProgress(cr.Status.AtProvider.LastOperation, http.MethodPut), ResourceLateInitialized: forgotten}, nil
 	}
 	if err != nil {
 		return managed.ExternalObservation{}, errors.Wrap(err, errGetAKSCluster)
 	}
+	forgotten := azure.RestoreCreateOperation(cr, &cr.Status.AtProvider.LastOperation)
+	if err := azure.FetchAsyncOperation(ctx, e.sender, &cr.Status.AtProvider.LastOperation); err != nil {
+		return managed.ExternalObservation{}, errors.Wrap(err, errFetchLastOperation)
+	}
 
 	cr.Status.ProviderID = to.String(c.ID)
 	cr.Status.State = to.String(c.ProvisioningState)
 	cr.Status.Endpoint = to.String(c.Fqdn)
 
+	lateInit := cr.Spec.Tags == nil
+	cr.Spec.Tags = azure.LateInitializeTags(cr.Spec.Tags, c.Tags, e.defaultTags)
+	lateInit = lateInit && cr.Spec.Tags != nil
+	lateInit = lateInit || forgotten
+
 	if cr.Status.State != "Succeeded" {
-		// AKS clusters are always up to date because we can't yet update them.
-		return managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true}, nil
+		// Clusters that are still being created or updated are not updated.
+		return managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true, ResourceLateInitialized: lateInit}, nil
 	}
 
 	kubeconfig, err := e.client.GetKubeConfig(ctx, cr)
@@ -134,11 +163,16 @@ func (e *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 
 	cr.SetConditions(xpv1.Available())
 
-	// AKS clusters are always up to date because we can't yet update them.
+	// Only the tags of AKS clusters can be updated.
+	diff := azure.Diff{}
+	diff.Compare("tags", azure.DesiredTags(e.defaultTags, cr.Spec.Tags), azure.ObservedTags(c.Tags))
+	azure.ReportDiff(ctx, diff)
+
 	o := managed.ExternalObservation{
-		ResourceExists:    true,
-		ResourceUpToDate:  true,
-		ConnectionDetails: cd,
+		ResourceExists:          true,
+		ResourceUpToDate:        diff.UpToDate() && azure.OwnershipTagsUpToDate(cr, c.Tags),
+		ResourceLateInitialized: lateInit,
+		ConnectionDetails:       cd,
 	}
 	return o, nil
 }
@@ -160,11 +194,13 @@ func (e *external) Create(ctx context.Context, mg resource.Managed) (managed.Ext
 			return managed.ExternalCreation{}, errors.Wrap(err, errGenPassword)
 		}
 	}
+	err = e.client.EnsureManagedCluster(ctx, cr, pw, e.defaultTags)
+	azure.RecordCreateOperation(cr, cr.Status.AtProvider.LastOperation)
 	return managed.ExternalCreation{
 		ConnectionDetails: managed.ConnectionDetails{
 			xpv1.ResourceCredentialsSecretPasswordKey: []byte(pw),
 		},
-	}, errors.Wrap(e.client.EnsureManagedCluster(ctx, cr, pw), errCreateAKSCluster)
+	}, errors.Wrap(err, errCreateAKSCluster)
 }
 
 func (e *external) getPassword(ctx context.Context, cr *v1alpha3.AKSCluster) (string, error) {
@@ -184,9 +220,19 @@ func (e *external) getPassword(ctx context.Context, cr *v1alpha3.AKSCluster) (st
 	return string(s.Data[xpv1.ResourceCredentialsSecretPasswordKey]), nil
 }
 
-func (e *external) Update(_ context.Context, _ resource.Managed) (managed.ExternalUpdate, error) {
-	// TODO(negz): Support updates.
-	return managed.ExternalUpdate{}, nil
+func (e *external) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
+	cr, ok := mg.(*v1alpha3.AKSCluster)
+	if !ok {
+		return managed.ExternalUpdate{}, errors.New(errNotAKSCluster)
+	}
+	// The cluster may not exist until the operation creating it has finished,
+	// and tag updates must not overlap.
+	if azure.IsOperationInProgress(cr.Status.AtProvider.LastOperation) {
+		return managed.ExternalUpdate{}, nil
+	}
+	f, err := e.client.UpdateManagedClusterTags(ctx, cr, e.defaultTags)
+	cr.Status.AtProvider.LastOperation = azure.NewAsyncOperation(http.MethodPatch, f.FutureAPI, err)
+	return managed.ExternalUpdate{}, errors.Wrap(err, errUpdateAKSCluster)
 }
 
 func (e *external) Delete(ctx context.Context, mg resource.Managed) error {
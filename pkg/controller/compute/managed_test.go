@@ -30,11 +30,15 @@ import (
 	v1 "k8s.io/api/core/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
 	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
 	"github.com/crossplane/crossplane-runtime/pkg/test"
 
 	"github.com/crossplane-contrib/provider-azure/apis/compute/v1alpha3"
+	apisv1alpha3 "github.com/crossplane-contrib/provider-azure/apis/v1alpha3"
+	azure "github.com/crossplane-contrib/provider-azure/pkg/clients"
+	"github.com/crossplane-contrib/provider-azure/pkg/clients/armtest"
 	"github.com/crossplane-contrib/provider-azure/pkg/clients/compute/fake"
 )
 
@@ -63,6 +67,18 @@ func withEndpoint(ep string) modifier {
 	}
 }
 
+func withLastOperation(op apisv1alpha3.AsyncOperation) modifier {
+	return func(c *v1alpha3.AKSCluster) {
+		c.Status.AtProvider.LastOperation = op
+	}
+}
+
+func withTags(t map[string]string) modifier {
+	return func(c *v1alpha3.AKSCluster) {
+		c.Spec.Tags = t
+	}
+}
+
 func withConnectionSecretRef(ref *xpv1.SecretReference) modifier {
 	return func(c *v1alpha3.AKSCluster) {
 		c.Spec.WriteConnectionSecretToReference = ref
@@ -127,6 +143,23 @@ func TestObserve(t *testing.T) {
 				mg: aksCluster(),
 			},
 		},
+		"CreationInProgress": {
+			e: &external{
+				client: fake.AKSClient{
+					MockGetManagedCluster: func(_ context.Context, _ *v1alpha3.AKSCluster) (containerservice.ManagedCluster, error) {
+						return containerservice.ManagedCluster{}, autorest.DetailedError{StatusCode: http.StatusNotFound}
+					},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  aksCluster(withLastOperation(apisv1alpha3.AsyncOperation{Method: http.MethodPut, Status: azure.AsyncOperationStatusInProgress})),
+			},
+			want: want{
+				eo: managed.ExternalObservation{ResourceExists: true},
+				mg: aksCluster(withLastOperation(apisv1alpha3.AsyncOperation{Method: http.MethodPut, Status: azure.AsyncOperationStatusInProgress})),
+			},
+		},
 		"ErrGetCluster": {
 			e: &external{
 				client: fake.AKSClient{
@@ -171,6 +204,40 @@ func TestObserve(t *testing.T) {
 				),
 			},
 		},
+		"LateInitializeTags": {
+			e: &external{
+				client: fake.AKSClient{
+					MockGetManagedCluster: func(_ context.Context, _ *v1alpha3.AKSCluster) (containerservice.ManagedCluster, error) {
+						return containerservice.ManagedCluster{
+							ID: to.StringPtr(id),
+							Tags: map[string]*string{
+								"team":           to.StringPtr("infra"),
+								"env":            to.StringPtr("prod"),
+								azure.TagKeyName: to.StringPtr("cool-cluster"),
+							},
+							ManagedClusterProperties: &containerservice.ManagedClusterProperties{
+								ProvisioningState: to.StringPtr(stateWat),
+								Fqdn:              to.StringPtr(endpoint),
+							},
+						}, nil
+					},
+				},
+				defaultTags: map[string]string{"env": "prod"},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  aksCluster(),
+			},
+			want: want{
+				eo: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true, ResourceLateInitialized: true},
+				mg: aksCluster(
+					withProviderID(id),
+					withState(stateWat),
+					withEndpoint(endpoint),
+					withTags(map[string]string{"team": "infra"}),
+				),
+			},
+		},
 		"ErrGetKubeConfig": {
 			e: &external{
 				client: fake.AKSClient{
@@ -257,7 +324,7 @@ func TestCreate(t *testing.T) {
 			e: &external{
 				newPasswordFn: func() (string, error) { return "", nil },
 				client: fake.AKSClient{
-					MockEnsureManagedCluster: func(_ context.Context, _ *v1alpha3.AKSCluster, _ string) error {
+					MockEnsureManagedCluster: func(_ context.Context, _ *v1alpha3.AKSCluster, _ string, _ map[string]string) error {
 						return errBoom
 					},
 				},
@@ -279,7 +346,7 @@ func TestCreate(t *testing.T) {
 			e: &external{
 				newPasswordFn: func() (string, error) { return testPasswd, nil },
 				client: fake.AKSClient{
-					MockEnsureManagedCluster: func(_ context.Context, _ *v1alpha3.AKSCluster, _ string) error {
+					MockEnsureManagedCluster: func(_ context.Context, _ *v1alpha3.AKSCluster, _ string, _ map[string]string) error {
 						return nil
 					},
 				},
@@ -300,7 +367,7 @@ func TestCreate(t *testing.T) {
 			e: &external{
 				newPasswordFn: func() (string, error) { return testPasswd, nil },
 				client: fake.AKSClient{
-					MockEnsureManagedCluster: func(_ context.Context, _ *v1alpha3.AKSCluster, _ string) error {
+					MockEnsureManagedCluster: func(_ context.Context, _ *v1alpha3.AKSCluster, _ string, _ map[string]string) error {
 						return nil
 					},
 				},
@@ -334,7 +401,7 @@ func TestCreate(t *testing.T) {
 			e: &external{
 				newPasswordFn: func() (string, error) { return testPasswd, nil },
 				client: fake.AKSClient{
-					MockEnsureManagedCluster: func(_ context.Context, _ *v1alpha3.AKSCluster, _ string) error {
+					MockEnsureManagedCluster: func(_ context.Context, _ *v1alpha3.AKSCluster, _ string, _ map[string]string) error {
 						return nil
 					},
 				},
@@ -368,7 +435,7 @@ func TestCreate(t *testing.T) {
 			e: &external{
 				newPasswordFn: func() (string, error) { return testPasswd, nil },
 				client: fake.AKSClient{
-					MockEnsureManagedCluster: func(_ context.Context, _ *v1alpha3.AKSCluster, _ string) error {
+					MockEnsureManagedCluster: func(_ context.Context, _ *v1alpha3.AKSCluster, _ string, _ map[string]string) error {
 						return nil
 					},
 				},
@@ -403,6 +470,99 @@ func TestCreate(t *testing.T) {
 	}
 }
 
+func TestUpdate(t *testing.T) {
+	errBoom := errors.New("boom")
+	defaultTags := map[string]string{"env": "prod"}
+
+	type args struct {
+		ctx context.Context
+		mg  resource.Managed
+	}
+	type want struct {
+		// status of the last operation.
+		status string
+		err    error
+	}
+
+	cases := map[string]struct {
+		e    managed.ExternalClient
+		args args
+		want want
+	}{
+		"ErrNotAKSCluster": {
+			e: &external{},
+			args: args{
+				ctx: context.Background(),
+			},
+			want: want{err: errors.New(errNotAKSCluster)},
+		},
+		"ErrUpdateTags": {
+			e: &external{
+				client: fake.AKSClient{
+					MockUpdateManagedClusterTags: func(_ context.Context, _ *v1alpha3.AKSCluster, _ map[string]string) (containerservice.ManagedClustersUpdateTagsFuture, error) {
+						return containerservice.ManagedClustersUpdateTagsFuture{}, errBoom
+					},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  aksCluster(),
+			},
+			want: want{
+				status: azure.AsyncOperationStatusFailed,
+				err:    errors.Wrap(errBoom, errUpdateAKSCluster),
+			},
+		},
+		"OperationInProgress": {
+			e: &external{
+				client: fake.AKSClient{
+					MockUpdateManagedClusterTags: func(_ context.Context, _ *v1alpha3.AKSCluster, _ map[string]string) (containerservice.ManagedClustersUpdateTagsFuture, error) {
+						return containerservice.ManagedClustersUpdateTagsFuture{}, errBoom
+					},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  aksCluster(withLastOperation(apisv1alpha3.AsyncOperation{Method: http.MethodPut, Status: azure.AsyncOperationStatusInProgress})),
+			},
+			want: want{status: azure.AsyncOperationStatusInProgress},
+		},
+		"Successful": {
+			e: &external{
+				client: fake.AKSClient{
+					MockUpdateManagedClusterTags: func(_ context.Context, _ *v1alpha3.AKSCluster, t map[string]string) (containerservice.ManagedClustersUpdateTagsFuture, error) {
+						if diff := cmp.Diff(defaultTags, t); diff != "" {
+							return containerservice.ManagedClustersUpdateTagsFuture{}, errors.New(diff)
+						}
+						return containerservice.ManagedClustersUpdateTagsFuture{}, nil
+					},
+				},
+				defaultTags: defaultTags,
+			},
+			args: args{
+				ctx: context.Background(),
+				mg:  aksCluster(withTags(map[string]string{"team": "infra"})),
+			},
+			want: want{status: azure.AsyncOperationStatusSucceeded},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := tc.e.Update(tc.args.ctx, tc.args.mg)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("tc.e.Update(...): -want error, +got error:\n%s", diff)
+			}
+			if cr, ok := tc.args.mg.(*v1alpha3.AKSCluster); ok {
+				if diff := cmp.Diff(tc.want.status, cr.Status.AtProvider.LastOperation.Status); diff != "" {
+					t.Errorf("tc.e.Update(...): -want last operation status, +got:\n%s", diff)
+				}
+			}
+		})
+	}
+}
+
 func TestDelete(t *testing.T) {
 	errBoom := errors.New("boom")
 
@@ -450,3 +610,18 @@ func TestDelete(t *testing.T) {
 		})
 	}
 }
+
+func TestDryRun(t *testing.T) {
+	s := armtest.NewServer()
+	defer s.Close()
+	cr := aksCluster()
+	meta.SetExternalName(cr, "cool-cluster")
+	cr.Spec.ResourceGroupName = "cool-rg"
+	cr.Spec.Location = "westus"
+	cr.Spec.DNSNamePrefix = "cool"
+
+	got := s.PlanDryRun(t, &connecter{client: s.Kube(nil)}, cr)
+	if diff := cmp.Diff(azure.ReasonPlannedCreate, got.Reason); diff != "" {
+		t.Errorf("PlanDryRun(...): -want Planned reason, +got:\n%s", diff)
+	}
+}
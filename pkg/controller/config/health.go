@@ -0,0 +1,259 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/controller"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	"github.com/crossplane-contrib/provider-azure/apis/v1beta1"
+	azure "github.com/crossplane-contrib/provider-azure/pkg/clients"
+)
+
+const (
+	healthCheckTimeout = 1 * time.Minute
+	healthCheckPeriod  = 5 * time.Minute
+)
+
+// Error strings.
+const (
+	errGetProviderConfig    = "cannot get ProviderConfig"
+	errGetCredentials       = "cannot get credentials"
+	errNewToken             = "cannot create Azure AD token"
+	errAcquireToken         = "cannot acquire Azure AD token"
+	errAccessSubscription   = "cannot access Azure subscription"
+	errUpdateProviderConfig = "cannot update ProviderConfig status"
+)
+
+// Event reasons.
+const (
+	reasonHealthy   event.Reason = "HealthyCredentials"
+	reasonUnhealthy event.Reason = "UnhealthyCredentials"
+)
+
+// Health is the result of a credentials check.
+type Health struct {
+	TenantID       string
+	SubscriptionID string
+	TokenExpiry    time.Time
+}
+
+// A HealthCheckFn checks the supplied credentials.
+type HealthCheckFn func(ctx context.Context, creds map[string]string) (Health, error)
+
+// CheckHealth acquires an Azure Resource Manager token using the supplied
+// credentials, then uses it to access their subscription.
+func CheckHealth(ctx context.Context, creds map[string]string) (Health, error) {
+	h := Health{
+		TenantID:       creds[azure.CredentialsKeyTenantID],
+		SubscriptionID: creds[azure.CredentialsKeySubscriptionID],
+	}
+	t, err := azure.NewServicePrincipalToken(creds, creds[azure.CredentialsKeyResourceManagerEndpointURL])
+	if err != nil {
+		return h, errors.Wrap(err, errNewToken)
+	}
+	if err := t.RefreshWithContext(ctx); err != nil {
+		return h, errors.Wrap(err, errAcquireToken)
+	}
+	h.TokenExpiry = t.Token().Expires()
+	if tid := azure.TokenTenantID(t.OAuthToken()); tid != "" {
+		h.TenantID = tid
+	}
+
+	cl := &azure.Client{
+		Authorizer: autorest.NewBearerAuthorizer(t),
+		Credentials: azure.Credentials{
+			SubscriptionID:             h.SubscriptionID,
+			ResourceManagerEndpointURL: creds[azure.CredentialsKeyResourceManagerEndpointURL],
+		},
+	}
+	return h, errors.Wrap(azure.ValidateClient(ctx, cl), errAccessSubscription)
+}
+
+// SetupHealth adds a controller that periodically validates the credentials of
+// ProviderConfigs and reports the result in their status. Updates to the status
+// of a ProviderConfig don't change its generation, so they don't trigger
+// another check.
+func SetupHealth(mgr ctrl.Manager, o controller.Options) error {
+	name := "health/" + strings.ToLower(v1beta1.ProviderConfigGroupKind)
+
+	r := NewHealthReconciler(mgr,
+		WithHealthLogger(o.Logger.WithValues("controller", name)),
+		WithHealthRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))))
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(name).
+		WithOptions(o.ForControllerRuntime()).
+		For(&v1beta1.ProviderConfig{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Complete(r)
+}
+
+// A CredentialsCache caches the credentials of ProviderConfigs.
+type CredentialsCache interface {
+	// Delete the cached credentials of the named ProviderConfig.
+	Delete(name string)
+}
+
+// A HealthReconcilerOption configures a HealthReconciler.
+type HealthReconcilerOption func(*HealthReconciler)
+
+// WithHealthLogger specifies how the HealthReconciler should log messages.
+func WithHealthLogger(l logging.Logger) HealthReconcilerOption {
+	return func(r *HealthReconciler) {
+		r.log = l
+	}
+}
+
+// WithHealthRecorder specifies how the HealthReconciler should record events.
+func WithHealthRecorder(er event.Recorder) HealthReconcilerOption {
+	return func(r *HealthReconciler) {
+		r.record = er
+	}
+}
+
+// WithHealthCheck specifies how the HealthReconciler should check credentials.
+func WithHealthCheck(fn HealthCheckFn) HealthReconcilerOption {
+	return func(r *HealthReconciler) {
+		r.check = fn
+	}
+}
+
+// WithCredentialsCache specifies the cache the HealthReconciler should evict
+// deleted ProviderConfigs from.
+func WithCredentialsCache(c CredentialsCache) HealthReconcilerOption {
+	return func(r *HealthReconciler) {
+		r.cache = c
+	}
+}
+
+// A HealthReconciler validates the credentials of ProviderConfigs.
+type HealthReconciler struct {
+	client client.Client
+	check  HealthCheckFn
+	cache  CredentialsCache
+
+	log    logging.Logger
+	record event.Recorder
+}
+
+// NewHealthReconciler returns a HealthReconciler of ProviderConfigs.
+func NewHealthReconciler(m ctrl.Manager, o ...HealthReconcilerOption) *HealthReconciler {
+	r := &HealthReconciler{
+		client: m.GetClient(),
+		check:  CheckHealth,
+		cache:  azure.DefaultAuthorizerCache,
+		log:    logging.NewNopLogger(),
+		record: event.NewNopRecorder(),
+	}
+	for _, ro := range o {
+		ro(r)
+	}
+	return r
+}
+
+// Reconcile a ProviderConfig by validating its credentials.
+func (r *HealthReconciler) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	log := r.log.WithValues("request", req)
+	log.Debug("Reconciling")
+
+	ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	defer cancel()
+
+	pc := &v1beta1.ProviderConfig{}
+	if err := r.client.Get(ctx, req.NamespacedName, pc); err != nil {
+		log.Debug(errGetProviderConfig, "error", err)
+		if kerrors.IsNotFound(err) {
+			r.cache.Delete(req.Name)
+		}
+		return reconcile.Result{}, errors.Wrap(resource.IgnoreNotFound(err), errGetProviderConfig)
+	}
+	if pc.GetDeletionTimestamp() != nil {
+		r.cache.Delete(pc.GetName())
+		return reconcile.Result{}, nil
+	}
+
+	current := pc.Status.DeepCopy()
+	h, err := r.checkProviderConfig(ctx, pc)
+	pc.Status.TenantID = h.TenantID
+	pc.Status.SubscriptionID = h.SubscriptionID
+	pc.Status.TokenExpiry = nil
+	if !h.TokenExpiry.IsZero() {
+		pc.Status.TokenExpiry = &metav1.Time{Time: h.TokenExpiry}
+	}
+
+	switch {
+	case err != nil:
+		log.Debug("Credentials are unhealthy", "error", err)
+		if pc.Status.GetCondition(xpv1.TypeReady).Reason != v1beta1.ReasonUnhealthy {
+			r.record.Event(pc, event.Warning(reasonUnhealthy, err))
+		}
+		pc.Status.SetConditions(v1beta1.Unhealthy(err))
+	default:
+		if pc.Status.GetCondition(xpv1.TypeReady).Reason != v1beta1.ReasonHealthy {
+			r.record.Event(pc, event.Normal(reasonHealthy, "Successfully validated credentials"))
+		}
+		pc.Status.SetConditions(v1beta1.Healthy())
+	}
+
+	if !statusChanged(current, &pc.Status) {
+		return reconcile.Result{RequeueAfter: healthCheckPeriod}, nil
+	}
+	if err := r.client.Status().Update(ctx, pc); err != nil {
+		return reconcile.Result{}, errors.Wrap(err, errUpdateProviderConfig)
+	}
+	return reconcile.Result{RequeueAfter: healthCheckPeriod}, nil
+}
+
+// statusChanged returns true if the desired status of a ProviderConfig differs
+// from its current status. A fresh token is acquired on every check, so its
+// expiry advances every time. A later expiry alone is not a change until the
+// currently recorded token has expired, so that the status is not updated on
+// every check.
+func statusChanged(current, desired *v1beta1.ProviderConfigStatus) bool {
+	if current.TokenExpiry != nil && desired.TokenExpiry != nil && current.TokenExpiry.Time.After(time.Now()) {
+		d := desired.DeepCopy()
+		d.TokenExpiry = current.TokenExpiry
+		return !cmp.Equal(current, d)
+	}
+	return !cmp.Equal(current, desired)
+}
+
+func (r *HealthReconciler) checkProviderConfig(ctx context.Context, pc *v1beta1.ProviderConfig) (Health, error) {
+	creds, err := azure.ProviderConfigCredentials(ctx, r.client, pc)
+	if err != nil {
+		return Health{}, errors.Wrap(err, errGetCredentials)
+	}
+	return r.check(ctx, creds)
+}
@@ -0,0 +1,347 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane-contrib/provider-azure/apis/v1beta1"
+	azure "github.com/crossplane-contrib/provider-azure/pkg/clients"
+)
+
+const (
+	testTenantID       = "302de427-dba9-4452-8583-a4268e46de6b"
+	testSubscriptionID = "bf1b0e59-93da-42e0-82c6-5a1d94227911"
+)
+
+// azureServer is a stand-in for both the Azure AD token endpoint and the Azure
+// Resource Manager endpoint.
+func azureServer(t *testing.T, armStatus int) *httptest.Server {
+	t.Helper()
+	claims := base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf(`{"tid":%q}`, testTenantID)))
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/oauth2/token"):
+			fmt.Fprintf(w, `{"access_token":"header.%s.signature","token_type":"Bearer","expires_in":"3600","expires_on":"%d"}`, claims, time.Now().Add(time.Hour).Unix())
+		case r.URL.Path == fmt.Sprintf("/subscriptions/%s/resourcegroups", testSubscriptionID):
+			w.WriteHeader(armStatus)
+			if armStatus != http.StatusOK {
+				fmt.Fprint(w, `{"error":{"code":"AuthorizationFailed","message":"The client does not have authorization"}}`)
+				return
+			}
+			fmt.Fprint(w, `{"value":[]}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+}
+
+func TestCheckHealth(t *testing.T) {
+	cases := map[string]struct {
+		reason    string
+		armStatus int
+		wantErr   bool
+	}{
+		"Healthy": {
+			reason:    "Credentials that can acquire a token and access their subscription should be healthy",
+			armStatus: http.StatusOK,
+		},
+		"Forbidden": {
+			reason:    "Credentials that cannot access their subscription should be unhealthy",
+			armStatus: http.StatusForbidden,
+			wantErr:   true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			srv := azureServer(t, tc.armStatus)
+			defer srv.Close()
+
+			creds := map[string]string{
+				azure.CredentialsKeyClientID:                   "client",
+				azure.CredentialsKeyClientSecret:               "secret",
+				azure.CredentialsKeyTenantID:                   "tenant",
+				azure.CredentialsKeySubscriptionID:             testSubscriptionID,
+				azure.CredentialsKeyActiveDirectoryEndpointURL: srv.URL + "/",
+				azure.CredentialsKeyResourceManagerEndpointURL: srv.URL + "/",
+			}
+			h, err := CheckHealth(context.Background(), creds)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("\n%s\nCheckHealth(...): want error %t, got %v", tc.reason, tc.wantErr, err)
+			}
+			if diff := cmp.Diff(testTenantID, h.TenantID); diff != "" {
+				t.Errorf("\n%s\nCheckHealth(...): -want tenant, +got tenant:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(testSubscriptionID, h.SubscriptionID); diff != "" {
+				t.Errorf("\n%s\nCheckHealth(...): -want subscription, +got subscription:\n%s", tc.reason, diff)
+			}
+			if h.TokenExpiry.Before(time.Now()) {
+				t.Errorf("\n%s\nCheckHealth(...): want token expiry in the future, got %s", tc.reason, h.TokenExpiry)
+			}
+		})
+	}
+}
+
+type credentialsCacheFn func(name string)
+
+func (fn credentialsCacheFn) Delete(name string) { fn(name) }
+
+func TestHealthReconcile(t *testing.T) {
+	errBoom := errors.New("boom")
+	expiry := time.Now().Add(time.Hour).Round(time.Second)
+
+	pc := func(m ...func(*v1beta1.ProviderConfig)) *v1beta1.ProviderConfig {
+		p := &v1beta1.ProviderConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: "default"},
+			Spec: v1beta1.ProviderConfigSpec{
+				Credentials:    v1beta1.ProviderCredentials{Source: v1beta1.CredentialsSourceManagedIdentity},
+				SubscriptionID: to.StringPtr(testSubscriptionID),
+			},
+		}
+		for _, fn := range m {
+			fn(p)
+		}
+		return p
+	}
+	get := func(p *v1beta1.ProviderConfig) test.MockGetFn {
+		return test.NewMockGetFn(nil, func(obj client.Object) error {
+			p.DeepCopyInto(obj.(*v1beta1.ProviderConfig))
+			return nil
+		})
+	}
+
+	type args struct {
+		kube  client.Client
+		check HealthCheckFn
+	}
+	type want struct {
+		result  reconcile.Result
+		err     error
+		deleted []string
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"NotFound": {
+			reason: "We should not return an error if the ProviderConfig was not found, and should evict its cached credentials",
+			args: args{
+				kube: &test.MockClient{
+					MockGet: test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{}, "")),
+				},
+			},
+			want: want{deleted: []string{"default"}},
+		},
+		"GetError": {
+			reason: "Errors getting the ProviderConfig should be returned",
+			args: args{
+				kube: &test.MockClient{
+					MockGet: test.NewMockGetFn(errBoom),
+				},
+			},
+			want: want{err: errors.Wrap(errBoom, errGetProviderConfig)},
+		},
+		"Deleting": {
+			reason: "We should not check a ProviderConfig that is being deleted, and should evict its cached credentials",
+			args: args{
+				kube: &test.MockClient{
+					MockGet: get(pc(func(p *v1beta1.ProviderConfig) {
+						now := metav1.Now()
+						p.SetDeletionTimestamp(&now)
+					})),
+				},
+			},
+			want: want{deleted: []string{"default"}},
+		},
+		"Unhealthy": {
+			reason: "A failed check should be reported as an Unhealthy condition",
+			args: args{
+				kube: &test.MockClient{
+					MockGet: get(pc()),
+					MockStatusUpdate: test.NewMockStatusUpdateFn(nil, func(obj client.Object) error {
+						want := pc(func(p *v1beta1.ProviderConfig) {
+							p.Status.SubscriptionID = testSubscriptionID
+							p.Status.SetConditions(v1beta1.Unhealthy(errors.Wrap(errBoom, errAccessSubscription)))
+						})
+						if diff := cmp.Diff(want, obj, test.EquateConditions()); diff != "" {
+							t.Errorf("Status().Update(...): -want, +got:\n%s", diff)
+						}
+						return nil
+					}),
+				},
+				check: func(_ context.Context, creds map[string]string) (Health, error) {
+					return Health{SubscriptionID: creds[azure.CredentialsKeySubscriptionID]}, errors.Wrap(errBoom, errAccessSubscription)
+				},
+			},
+			want: want{result: reconcile.Result{RequeueAfter: healthCheckPeriod}},
+		},
+		"Healthy": {
+			reason: "A successful check should be reported as a Healthy condition",
+			args: args{
+				kube: &test.MockClient{
+					MockGet: get(pc()),
+					MockStatusUpdate: test.NewMockStatusUpdateFn(nil, func(obj client.Object) error {
+						want := pc(func(p *v1beta1.ProviderConfig) {
+							p.Status.TenantID = testTenantID
+							p.Status.SubscriptionID = testSubscriptionID
+							p.Status.TokenExpiry = &metav1.Time{Time: expiry}
+							p.Status.SetConditions(v1beta1.Healthy())
+						})
+						if diff := cmp.Diff(want, obj, test.EquateConditions()); diff != "" {
+							t.Errorf("Status().Update(...): -want, +got:\n%s", diff)
+						}
+						return nil
+					}),
+				},
+				check: func(_ context.Context, creds map[string]string) (Health, error) {
+					return Health{TenantID: testTenantID, SubscriptionID: creds[azure.CredentialsKeySubscriptionID], TokenExpiry: expiry}, nil
+				},
+			},
+			want: want{result: reconcile.Result{RequeueAfter: healthCheckPeriod}},
+		},
+		"Unchanged": {
+			reason: "The ProviderConfig status should not be updated if the check did not change it, even though every check acquires a token that expires later",
+			args: args{
+				kube: &test.MockClient{
+					MockGet: get(pc(func(p *v1beta1.ProviderConfig) {
+						p.Status.TenantID = testTenantID
+						p.Status.SubscriptionID = testSubscriptionID
+						p.Status.TokenExpiry = &metav1.Time{Time: expiry}
+						p.Status.SetConditions(v1beta1.Healthy())
+					})),
+					MockStatusUpdate: test.NewMockStatusUpdateFn(errBoom),
+				},
+				check: func(_ context.Context, creds map[string]string) (Health, error) {
+					return Health{TenantID: testTenantID, SubscriptionID: creds[azure.CredentialsKeySubscriptionID], TokenExpiry: time.Now().Add(time.Hour)}, nil
+				},
+			},
+			want: want{result: reconcile.Result{RequeueAfter: healthCheckPeriod}},
+		},
+		"TokenExpired": {
+			reason: "The ProviderConfig status should be updated with a new token expiry once the recorded token has expired",
+			args: args{
+				kube: &test.MockClient{
+					MockGet: get(pc(func(p *v1beta1.ProviderConfig) {
+						p.Status.TenantID = testTenantID
+						p.Status.SubscriptionID = testSubscriptionID
+						p.Status.TokenExpiry = &metav1.Time{Time: expiry.Add(-2 * time.Hour)}
+						p.Status.SetConditions(v1beta1.Healthy())
+					})),
+					MockStatusUpdate: test.NewMockStatusUpdateFn(nil, func(obj client.Object) error {
+						want := pc(func(p *v1beta1.ProviderConfig) {
+							p.Status.TenantID = testTenantID
+							p.Status.SubscriptionID = testSubscriptionID
+							p.Status.TokenExpiry = &metav1.Time{Time: expiry}
+							p.Status.SetConditions(v1beta1.Healthy())
+						})
+						if diff := cmp.Diff(want, obj, test.EquateConditions()); diff != "" {
+							t.Errorf("Status().Update(...): -want, +got:\n%s", diff)
+						}
+						return nil
+					}),
+				},
+				check: func(_ context.Context, creds map[string]string) (Health, error) {
+					return Health{TenantID: testTenantID, SubscriptionID: creds[azure.CredentialsKeySubscriptionID], TokenExpiry: expiry}, nil
+				},
+			},
+			want: want{result: reconcile.Result{RequeueAfter: healthCheckPeriod}},
+		},
+		"ChangedWithUnexpiredToken": {
+			reason: "A status update should record the expiry of the token acquired by the check, even if the recorded token has not expired",
+			args: args{
+				kube: &test.MockClient{
+					MockGet: get(pc(func(p *v1beta1.ProviderConfig) {
+						p.Status.TenantID = testTenantID
+						p.Status.SubscriptionID = testSubscriptionID
+						p.Status.TokenExpiry = &metav1.Time{Time: expiry.Add(-30 * time.Minute)}
+						p.Status.SetConditions(v1beta1.Unhealthy(errBoom))
+					})),
+					MockStatusUpdate: test.NewMockStatusUpdateFn(nil, func(obj client.Object) error {
+						want := pc(func(p *v1beta1.ProviderConfig) {
+							p.Status.TenantID = testTenantID
+							p.Status.SubscriptionID = testSubscriptionID
+							p.Status.TokenExpiry = &metav1.Time{Time: expiry}
+							p.Status.SetConditions(v1beta1.Healthy())
+						})
+						if diff := cmp.Diff(want, obj, test.EquateConditions()); diff != "" {
+							t.Errorf("Status().Update(...): -want, +got:\n%s", diff)
+						}
+						return nil
+					}),
+				},
+				check: func(_ context.Context, creds map[string]string) (Health, error) {
+					return Health{TenantID: testTenantID, SubscriptionID: creds[azure.CredentialsKeySubscriptionID], TokenExpiry: expiry}, nil
+				},
+			},
+			want: want{result: reconcile.Result{RequeueAfter: healthCheckPeriod}},
+		},
+		"UpdateError": {
+			reason: "Errors updating the ProviderConfig status should be returned",
+			args: args{
+				kube: &test.MockClient{
+					MockGet:          get(pc()),
+					MockStatusUpdate: test.NewMockStatusUpdateFn(errBoom),
+				},
+				check: func(_ context.Context, _ map[string]string) (Health, error) {
+					return Health{}, nil
+				},
+			},
+			want: want{err: errors.Wrap(errBoom, errUpdateProviderConfig)},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var deleted []string
+			cache := credentialsCacheFn(func(name string) { deleted = append(deleted, name) })
+			r := NewHealthReconciler(&fake.Manager{Client: tc.args.kube}, WithHealthCheck(tc.args.check), WithCredentialsCache(cache))
+			got, err := r.Reconcile(context.Background(), reconcile.Request{NamespacedName: types.NamespacedName{Name: "default"}})
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nr.Reconcile(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.result, got); diff != "" {
+				t.Errorf("\n%s\nr.Reconcile(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.deleted, deleted); diff != "" {
+				t.Errorf("\n%s\nr.Reconcile(...): -want deleted, +got deleted:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -21,6 +21,7 @@ import (
 	"net/http"
 
 	"github.com/Azure/azure-sdk-for-go/services/cosmos-db/mgmt/2015-04-08/documentdb"
+	"github.com/Azure/go-autorest/autorest"
 	"github.com/pkg/errors"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -35,6 +36,7 @@ import (
 
 	"github.com/crossplane-contrib/provider-azure/apis/database/v1alpha3"
 	"github.com/crossplane-contrib/provider-azure/apis/v1alpha1"
+	apisv1alpha3 "github.com/crossplane-contrib/provider-azure/apis/v1alpha3"
 	azure "github.com/crossplane-contrib/provider-azure/pkg/clients"
 	"github.com/crossplane-contrib/provider-azure/pkg/clients/database/cosmosdb"
 	"github.com/crossplane-contrib/provider-azure/pkg/features"
@@ -46,6 +48,7 @@ const (
 	errCreateNoSQLAccount = "cannot create Database Account"
 	errGetNoSQLAccount    = "cannot get Database Account"
 	errDeleteNoSQLAccount = "cannot delete Database Account"
+	errFetchLastOperation = "cannot fetch last operation"
 )
 
 // Setup adds a controller that reconciles NoSQLAccount.
@@ -57,18 +60,19 @@ func Setup(mgr ctrl.Manager, o controller.Options) error {
 		cps = append(cps, connection.NewDetailsManager(mgr.GetClient(), v1alpha1.StoreConfigGroupVersionKind))
 	}
 
+	recorder := event.NewAPIRecorder(mgr.GetEventRecorderFor(name))
+
 	return ctrl.NewControllerManagedBy(mgr).
 		Named(name).
 		WithOptions(o.ForControllerRuntime()).
 		For(&v1alpha3.CosmosDBAccount{}).
-		Complete(managed.NewReconciler(mgr,
-			resource.ManagedKind(v1alpha3.CosmosDBAccountGroupVersionKind),
+		Complete(azure.NewReconciler(mgr, name, v1alpha3.CosmosDBAccountGroupVersionKind,
 			managed.WithConnectionPublishers(),
-			managed.WithExternalConnecter(&connecter{kube: mgr.GetClient()}),
+			managed.WithExternalConnecter(azure.NewConnecter(&connecter{kube: mgr.GetClient()}, recorder)),
 			managed.WithReferenceResolver(managed.NewAPISimpleReferenceResolver(mgr.GetClient())),
 			managed.WithPollInterval(o.PollInterval),
 			managed.WithLogger(o.Logger.WithValues("controller", name)),
-			managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
+			managed.WithRecorder(recorder),
 			managed.WithConnectionPublishers(cps...)))
 }
 
@@ -81,15 +85,21 @@ func (c *connecter) Connect(ctx context.Context, mg resource.Managed) (managed.E
 	if err != nil {
 		return nil, err
 	}
-	cl := documentdb.NewDatabaseAccountsClient(creds[azure.CredentialsKeySubscriptionID])
-	cl.Authorizer = auth
-	return &external{kube: c.kube, client: cl}, nil
+	cl := documentdb.NewDatabaseAccountsClientWithBaseURI(creds[azure.CredentialsKeyResourceManagerEndpointURL], creds[azure.CredentialsKeySubscriptionID])
+	azure.ConfigureClient(&cl.Client, auth)
+	tags, err := azure.DefaultTags(ctx, c.kube, mg)
+	if err != nil {
+		return nil, err
+	}
+	return &external{kube: c.kube, client: cl, sender: cl.Client, defaultTags: tags}, nil
 }
 
 // external is a createsyncdeleter using the Azure API.
 type external struct {
-	kube   client.Client
-	client cosmosdb.AccountClient
+	kube        client.Client
+	client      cosmosdb.AccountClient
+	sender      autorest.Sender
+	defaultTags map[string]string
 }
 
 func (e *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
@@ -100,7 +110,15 @@ func (e *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 
 	res, err := e.client.CheckNameExists(ctx, meta.GetExternalName(r))
 	if res.IsHTTPStatus(http.StatusNotFound) {
-		return managed.ExternalObservation{ResourceExists: false}, nil
+		forgotten := restoreLastOperation(r)
+		if r.Status.AtProvider == nil {
+			return managed.ExternalObservation{ResourceExists: false}, nil
+		}
+		if err := azure.FetchAsyncOperation(ctx, e.sender, &r.Status.AtProvider.LastOperation); err != nil {
+			return managed.ExternalObservation{}, errors.Wrap(err, errFetchLastOperation)
+		}
+		// A creation that is still in progress must not be started again.
+		return managed.ExternalObservation{ResourceExists: azure.IsOperationInProgress(r.Status.AtProvider.LastOperation, http.MethodPut), ResourceLateInitialized: forgotten}, nil
 	}
 	if err != nil {
 		return managed.ExternalObservation{}, errors.Wrap(err, errGetNoSQLAccount)
@@ -111,6 +129,10 @@ func (e *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 		return managed.ExternalObservation{}, errors.Wrap(err, errGetNoSQLAccount)
 	}
 	cosmosdb.UpdateCosmosDBAccountObservation(&r.Status, account)
+	forgotten := restoreLastOperation(r)
+	if err := azure.FetchAsyncOperation(ctx, e.sender, &r.Status.AtProvider.LastOperation); err != nil {
+		return managed.ExternalObservation{}, errors.Wrap(err, errFetchLastOperation)
+	}
 
 	switch r.Status.AtProvider.State {
 	case "Succeeded":
@@ -118,8 +140,10 @@ func (e *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 	default:
 		r.SetConditions(xpv1.Unavailable())
 	}
-	resourceUpToDate := cosmosdb.CheckEqualDatabaseProperties(r.Spec.ForProvider.Properties, account)
-	return managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: resourceUpToDate}, nil
+	diff := cosmosdb.CheckEqualDatabaseProperties(r.Spec.ForProvider.Properties, account)
+	diff.Compare("tags", azure.DesiredTags(e.defaultTags, r.Spec.ForProvider.Tags), azure.ObservedTags(account.Tags))
+	azure.ReportDiff(ctx, diff)
+	return managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: diff.UpToDate() && azure.OwnershipTagsUpToDate(r, account.Tags), ResourceLateInitialized: forgotten}, nil
 }
 
 func (e *external) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
@@ -129,15 +153,24 @@ func (e *external) Create(ctx context.Context, mg resource.Managed) (managed.Ext
 	}
 
 	r.Status.SetConditions(xpv1.Creating())
-	_, err := e.client.CreateOrUpdate(ctx,
-		r.Spec.ForProvider.ResourceGroupName,
-		meta.GetExternalName(r),
-		cosmosdb.ToDatabaseAccountCreateOrUpdate(&r.Spec))
+	p := cosmosdb.ToDatabaseAccountCreateOrUpdate(&r.Spec)
+	p.Tags = azure.NewTags(r, e.defaultTags, r.Spec.ForProvider.Tags)
+	op, err := e.client.CreateOrUpdate(ctx, r.Spec.ForProvider.ResourceGroupName, meta.GetExternalName(r), p)
+	setLastOperation(r, azure.NewAsyncOperation(http.MethodPut, op.FutureAPI, err))
+	azure.RecordCreateOperation(r, r.Status.AtProvider.LastOperation)
 	// TODO(artursouza): handle secrets.
 	return managed.ExternalCreation{}, errors.Wrap(err, errCreateNoSQLAccount)
 }
 
 func (e *external) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
+	r, ok := mg.(*v1alpha3.CosmosDBAccount)
+	if !ok {
+		return managed.ExternalUpdate{}, errors.New(errNotNoSQLAccount)
+	}
+	// Azure rejects updates while another operation is ongoing.
+	if r.Status.AtProvider != nil && azure.IsOperationInProgress(r.Status.AtProvider.LastOperation) {
+		return managed.ExternalUpdate{}, nil
+	}
 	_, err := e.Create(ctx, mg)
 	return managed.ExternalUpdate{}, err
 }
@@ -149,6 +182,31 @@ func (e *external) Delete(ctx context.Context, mg resource.Managed) error {
 	}
 
 	r.Status.SetConditions(xpv1.Deleting())
-	_, err := e.client.Delete(ctx, r.Spec.ForProvider.ResourceGroupName, meta.GetExternalName(r))
+	op, err := e.client.Delete(ctx, r.Spec.ForProvider.ResourceGroupName, meta.GetExternalName(r))
+	setLastOperation(r, azure.NewAsyncOperation(http.MethodDelete, op.FutureAPI, err))
 	return errors.Wrap(err, errDeleteNoSQLAccount)
 }
+
+// setLastOperation records the supplied operation as the last operation
+// started for the supplied account.
+func setLastOperation(r *v1alpha3.CosmosDBAccount, op apisv1alpha3.AsyncOperation) {
+	if r.Status.AtProvider == nil {
+		r.Status.AtProvider = &v1alpha3.CosmosDBAccountObservation{}
+	}
+	r.Status.AtProvider.LastOperation = op
+}
+
+// restoreLastOperation restores the operation started by Create, which the
+// managed reconciler removes from the status of the supplied account. It
+// returns true if the account must be updated, as RestoreCreateOperation does.
+func restoreLastOperation(r *v1alpha3.CosmosDBAccount) bool {
+	op := apisv1alpha3.AsyncOperation{}
+	if r.Status.AtProvider != nil {
+		op = r.Status.AtProvider.LastOperation
+	}
+	forgotten := azure.RestoreCreateOperation(r, &op)
+	if op.StartTime != nil {
+		setLastOperation(r, op)
+	}
+	return forgotten
+}
@@ -24,6 +24,7 @@ import (
 	"github.com/Azure/azure-sdk-for-go/services/cosmos-db/mgmt/2015-04-08/documentdb"
 	"github.com/Azure/go-autorest/autorest"
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/pkg/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
@@ -36,7 +37,9 @@ import (
 	"github.com/crossplane/crossplane-runtime/pkg/test"
 
 	"github.com/crossplane-contrib/provider-azure/apis/database/v1alpha3"
+	apisv1alpha3 "github.com/crossplane-contrib/provider-azure/apis/v1alpha3"
 	azure "github.com/crossplane-contrib/provider-azure/pkg/clients"
+	"github.com/crossplane-contrib/provider-azure/pkg/clients/armtest"
 	cosmosdbclient "github.com/crossplane-contrib/provider-azure/pkg/clients/database/cosmosdb"
 )
 
@@ -51,6 +54,9 @@ const (
 	stateSucceeded = "Succeeded"
 )
 
+var ignoreStartTime = cmpopts.IgnoreFields(apisv1alpha3.AsyncOperation{}, "StartTime")
+var ignoreCreateOperation = cmpopts.IgnoreMapEntries(func(k, _ string) bool { return k == azure.AnnotationKeyCreateOperation })
+
 type cosmosDBAccountModifier func(*v1alpha3.CosmosDBAccount)
 
 // MockClient is a fake implementation of the azure cosmosdb client.
@@ -87,6 +93,10 @@ func withConditions(c ...xpv1.Condition) cosmosDBAccountModifier {
 	return func(r *v1alpha3.CosmosDBAccount) { r.Status.ConditionedStatus.Conditions = c }
 }
 
+func withLastOperation(op apisv1alpha3.AsyncOperation) cosmosDBAccountModifier {
+	return func(r *v1alpha3.CosmosDBAccount) { r.Status.AtProvider.LastOperation = op }
+}
+
 func cosmosDBAccount(rm ...cosmosDBAccountModifier) *v1alpha3.CosmosDBAccount {
 	r := &v1alpha3.CosmosDBAccount{
 		ObjectMeta: metav1.ObjectMeta{
@@ -198,6 +208,23 @@ func TestObserve(t *testing.T) {
 				mg: cosmosDBAccount(),
 			},
 		},
+		"CreationInProgress": {
+			e: &external{
+				kube: mockKube,
+				client: &MockClient{
+					MockCheckNameExists: func(_ context.Context, _ string) (result autorest.Response, err error) {
+						return autorest.Response{Response: &http.Response{StatusCode: http.StatusNotFound}}, nil
+					},
+				},
+			},
+			args: args{
+				mg: cosmosDBAccount(withLastOperation(apisv1alpha3.AsyncOperation{Method: http.MethodPut, Status: azure.AsyncOperationStatusInProgress})),
+			},
+			want: want{
+				o:  managed.ExternalObservation{ResourceExists: true},
+				mg: cosmosDBAccount(withLastOperation(apisv1alpha3.AsyncOperation{Method: http.MethodPut, Status: azure.AsyncOperationStatusInProgress})),
+			},
+		},
 		"Success": {
 			e: &external{
 				kube: mockKube,
@@ -210,6 +237,7 @@ func TestObserve(t *testing.T) {
 							ID:       azure.ToStringPtr(id),
 							Kind:     kind,
 							Location: azure.ToStringPtr(location),
+							Tags:     azure.NewTags(cosmosDBAccount(), nil, nil),
 							DatabaseAccountProperties: &documentdb.DatabaseAccountProperties{
 								ProvisioningState: azure.ToStringPtr(stateSucceeded),
 								ReadLocations: &[]documentdb.Location{
@@ -298,7 +326,10 @@ func TestCreate(t *testing.T) {
 				mg: cosmosDBAccount(),
 			},
 			want: want{
-				mg:  cosmosDBAccount(withConditions(xpv1.Creating())),
+				mg: cosmosDBAccount(
+					withConditions(xpv1.Creating()),
+					withLastOperation(apisv1alpha3.AsyncOperation{Method: http.MethodPut, Status: azure.AsyncOperationStatusFailed, ErrorMessage: errBoom.Error()}),
+				),
 				err: errors.Wrap(errBoom, errCreateNoSQLAccount),
 			},
 		},
@@ -312,7 +343,7 @@ func TestCreate(t *testing.T) {
 				t.Errorf("tc.e.Create(...): want error != got error:\n%s", diff)
 			}
 
-			if diff := cmp.Diff(tc.want.mg, tc.args.mg); diff != "" {
+			if diff := cmp.Diff(tc.want.mg, tc.args.mg, ignoreStartTime, ignoreCreateOperation); diff != "" {
 				t.Errorf("tc.e.Create(...): -want managed, +got managed:\n%s", diff)
 			}
 
@@ -323,6 +354,80 @@ func TestCreate(t *testing.T) {
 	}
 }
 
+func TestUpdate(t *testing.T) {
+	type args struct {
+		ctx context.Context
+		mg  resource.Managed
+	}
+
+	type want struct {
+		mg  resource.Managed
+		u   managed.ExternalUpdate
+		err error
+	}
+
+	cases := map[string]struct {
+		e    managed.ExternalClient
+		args args
+		want want
+	}{
+		"NotCosmosDBAccount": {
+			e: &external{},
+			args: args{
+				mg: nil,
+			},
+			want: want{
+				err: errors.New(errNotNoSQLAccount),
+			},
+		},
+		"OperationInProgress": {
+			e: &external{},
+			args: args{
+				mg: cosmosDBAccount(withLastOperation(apisv1alpha3.AsyncOperation{Method: http.MethodPut, Status: azure.AsyncOperationStatusInProgress})),
+			},
+			want: want{
+				mg: cosmosDBAccount(withLastOperation(apisv1alpha3.AsyncOperation{Method: http.MethodPut, Status: azure.AsyncOperationStatusInProgress})),
+			},
+		},
+		"Success": {
+			e: &external{
+				client: &MockClient{
+					MockCreateOrUpdate: func(_ context.Context, _ string, _ string, _ documentdb.DatabaseAccountCreateUpdateParameters) (result documentdb.DatabaseAccountsCreateOrUpdateFuture, err error) {
+						return documentdb.DatabaseAccountsCreateOrUpdateFuture{}, nil
+					},
+				},
+			},
+			args: args{
+				mg: cosmosDBAccount(),
+			},
+			want: want{
+				mg: cosmosDBAccount(
+					withConditions(xpv1.Creating()),
+					withLastOperation(apisv1alpha3.AsyncOperation{Method: http.MethodPut, Status: azure.AsyncOperationStatusSucceeded}),
+				),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := tc.e.Update(tc.args.ctx, tc.args.mg)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("tc.e.Update(...): want error != got error:\n%s", diff)
+			}
+
+			if diff := cmp.Diff(tc.want.mg, tc.args.mg, ignoreStartTime, ignoreCreateOperation); diff != "" {
+				t.Errorf("tc.e.Update(...): -want managed, +got managed:\n%s", diff)
+			}
+
+			if diff := cmp.Diff(tc.want.u, got); diff != "" {
+				t.Errorf("tc.e.Update(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
 func TestDelete(t *testing.T) {
 	errBoom := errors.New("boom")
 
@@ -362,7 +467,10 @@ func TestDelete(t *testing.T) {
 				mg: cosmosDBAccount(),
 			},
 			want: want{
-				mg:  cosmosDBAccount(withConditions(xpv1.Deleting())),
+				mg: cosmosDBAccount(
+					withConditions(xpv1.Deleting()),
+					withLastOperation(apisv1alpha3.AsyncOperation{Method: http.MethodDelete, Status: azure.AsyncOperationStatusFailed, ErrorMessage: errBoom.Error()}),
+				),
 				err: errors.Wrap(errBoom, errDeleteNoSQLAccount),
 			},
 		},
@@ -376,9 +484,21 @@ func TestDelete(t *testing.T) {
 				t.Errorf("tc.e.Delete(...): -want error, +got error:\n%s", diff)
 			}
 
-			if diff := cmp.Diff(tc.want.mg, tc.args.mg, test.EquateErrors()); diff != "" {
+			if diff := cmp.Diff(tc.want.mg, tc.args.mg, test.EquateErrors(), ignoreStartTime); diff != "" {
 				t.Errorf("tc.e.Delete(...): -want, +got:\n%s", diff)
 			}
 		})
 	}
 }
+
+func TestDryRun(t *testing.T) {
+	s := armtest.NewServer()
+	defer s.Close()
+	cr := cosmosDBAccount()
+	cr.Spec.ForProvider.Properties.DatabaseAccountOfferType = "Standard"
+
+	got := s.PlanDryRun(t, &connecter{kube: s.Kube(nil)}, cr)
+	if diff := cmp.Diff(azure.ReasonPlannedCreate, got.Reason); diff != "" {
+		t.Errorf("PlanDryRun(...): -want Planned reason, +got:\n%s", diff)
+	}
+}
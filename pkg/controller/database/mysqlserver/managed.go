@@ -19,8 +19,10 @@ package mysqlserver
 import (
 	"context"
 	"fmt"
+	"net/http"
 
 	"github.com/Azure/azure-sdk-for-go/services/mysql/mgmt/2017-12-01/mysql"
+	"github.com/Azure/azure-sdk-for-go/services/resources/mgmt/2016-09-01/locks/locksapi"
 	"github.com/pkg/errors"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -62,17 +64,18 @@ func Setup(mgr ctrl.Manager, o controller.Options) error {
 		cps = append(cps, connection.NewDetailsManager(mgr.GetClient(), v1alpha1.StoreConfigGroupVersionKind))
 	}
 
+	recorder := event.NewAPIRecorder(mgr.GetEventRecorderFor(name))
+
 	return ctrl.NewControllerManagedBy(mgr).
 		Named(name).
 		WithOptions(o.ForControllerRuntime()).
 		For(&v1beta1.MySQLServer{}).
-		Complete(managed.NewReconciler(mgr,
-			resource.ManagedKind(v1beta1.MySQLServerGroupVersionKind),
-			managed.WithExternalConnecter(&connecter{client: mgr.GetClient()}),
+		Complete(azure.NewReconciler(mgr, name, v1beta1.MySQLServerGroupVersionKind,
+			managed.WithExternalConnecter(azure.NewConnecter(&connecter{client: mgr.GetClient()}, recorder)),
 			managed.WithReferenceResolver(managed.NewAPISimpleReferenceResolver(mgr.GetClient())),
 			managed.WithPollInterval(o.PollInterval),
 			managed.WithLogger(o.Logger.WithValues("controller", name)),
-			managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
+			managed.WithRecorder(recorder),
 			managed.WithConnectionPublishers(cps...)))
 }
 
@@ -85,15 +88,21 @@ func (c *connecter) Connect(ctx context.Context, mg resource.Managed) (managed.E
 	if err != nil {
 		return nil, err
 	}
-	cl := mysql.NewServersClient(creds[azure.CredentialsKeySubscriptionID])
-	cl.Authorizer = auth
-	return &external{kube: c.client, client: database.NewMySQLServerClient(cl), newPasswordFn: password.Generate}, nil
+	cl := mysql.NewServersClientWithBaseURI(creds[azure.CredentialsKeyResourceManagerEndpointURL], creds[azure.CredentialsKeySubscriptionID])
+	azure.ConfigureClient(&cl.Client, auth)
+	tags, err := azure.DefaultTags(ctx, c.client, mg)
+	if err != nil {
+		return nil, err
+	}
+	return &external{kube: c.client, client: database.NewMySQLServerClient(cl), defaultTags: tags, locks: azure.NewManagementLocksClient(creds, auth), newPasswordFn: password.Generate}, nil
 }
 
 type external struct {
 	kube          client.Client
 	client        database.MySQLServerAPI
+	locks         locksapi.ManagementLocksClientAPI
 	newPasswordFn func() (password string, err error)
+	defaultTags   map[string]string
 }
 
 func (e *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
@@ -104,6 +113,7 @@ func (e *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 
 	server, err := e.client.GetServer(ctx, cr)
 	if azure.IsNotFound(err) {
+		forgotten := azure.RestoreCreateOperation(cr, &cr.Status.AtProvider.LastOperation)
 		if err := azure.FetchAsyncOperation(ctx, e.client.GetRESTClient(), &cr.Status.AtProvider.LastOperation); err != nil {
 			return managed.ExternalObservation{}, errors.Wrap(err, errFetchLastOperation)
 		}
@@ -111,14 +121,13 @@ func (e *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 		// successfully and we cannot return `ResourceExists: false` during creation
 		// since this will cause `Create` to be called again and it's not idempotent.
 		// So, we check whether a creation operation in fact is in motion.
-		creating := cr.Status.AtProvider.LastOperation.Method == "PUT" &&
-			cr.Status.AtProvider.LastOperation.Status == azure.AsyncOperationStatusInProgress
-		return managed.ExternalObservation{ResourceExists: creating}, nil
+		creating := azure.IsOperationInProgress(cr.Status.AtProvider.LastOperation, http.MethodPut)
+		return managed.ExternalObservation{ResourceExists: creating, ResourceLateInitialized: forgotten}, nil
 	}
 	if err != nil {
 		return managed.ExternalObservation{}, errors.Wrap(err, errGetMySQLServer)
 	}
-	database.LateInitializeMySQL(&cr.Spec.ForProvider, server)
+	database.LateInitializeMySQL(&cr.Spec.ForProvider, server, e.defaultTags)
 	if err := e.kube.Update(ctx, cr); err != nil {
 		return managed.ExternalObservation{}, errors.Wrap(err, errUpdateCR)
 	}
@@ -127,6 +136,7 @@ func (e *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 	// status subresource but fetches the the whole object after it's done. So,
 	// changes to status has to be done after kube.Update in order not to get them
 	// lost.
+	forgotten := azure.RestoreCreateOperation(cr, &cr.Status.AtProvider.LastOperation)
 	if err := azure.FetchAsyncOperation(ctx, e.client.GetRESTClient(), &cr.Status.AtProvider.LastOperation); err != nil {
 		return managed.ExternalObservation{}, errors.Wrap(err, errFetchLastOperation)
 	}
@@ -137,9 +147,12 @@ func (e *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 		cr.SetConditions(xpv1.Unavailable())
 	}
 
+	diff := database.IsMySQLUpToDate(cr.Spec.ForProvider, server, e.defaultTags)
+	azure.ReportDiff(ctx, diff)
 	return managed.ExternalObservation{
-		ResourceExists:   true,
-		ResourceUpToDate: database.IsMySQLUpToDate(cr.Spec.ForProvider, server),
+		ResourceExists:          true,
+		ResourceUpToDate:        diff.UpToDate() && azure.OwnershipTagsUpToDate(cr, server.Tags),
+		ResourceLateInitialized: forgotten,
 		ConnectionDetails: managed.ConnectionDetails{
 			xpv1.ResourceCredentialsSecretEndpointKey: []byte(cr.Status.AtProvider.FullyQualifiedDomainName),
 			xpv1.ResourceCredentialsSecretUserKey:     []byte(fmt.Sprintf("%s@%s", cr.Spec.ForProvider.AdministratorLogin, meta.GetExternalName(cr))),
@@ -158,17 +171,17 @@ func (e *external) Create(ctx context.Context, mg resource.Managed) (managed.Ext
 	if err != nil {
 		return managed.ExternalCreation{}, errors.Wrap(err, errGenPassword)
 	}
-	if err := e.client.CreateServer(ctx, cr, pw); err != nil {
+	if err := e.client.CreateServer(ctx, cr, pw, e.defaultTags); err != nil {
 		return managed.ExternalCreation{}, errors.Wrap(err, errCreateMySQLServer)
 	}
 
+	err = azure.FetchAsyncOperation(ctx, e.client.GetRESTClient(), &cr.Status.AtProvider.LastOperation)
+	azure.RecordCreateOperation(cr, cr.Status.AtProvider.LastOperation)
 	return managed.ExternalCreation{
-			ConnectionDetails: managed.ConnectionDetails{
-				xpv1.ResourceCredentialsSecretPasswordKey: []byte(pw),
-			},
-		}, errors.Wrap(
-			azure.FetchAsyncOperation(ctx, e.client.GetRESTClient(), &cr.Status.AtProvider.LastOperation),
-			errFetchLastOperation)
+		ConnectionDetails: managed.ConnectionDetails{
+			xpv1.ResourceCredentialsSecretPasswordKey: []byte(pw),
+		},
+	}, errors.Wrap(err, errFetchLastOperation)
 }
 
 func (e *external) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
@@ -176,10 +189,10 @@ func (e *external) Update(ctx context.Context, mg resource.Managed) (managed.Ext
 	if !ok {
 		return managed.ExternalUpdate{}, errors.New(errNotMySQLServer)
 	}
-	if cr.Status.AtProvider.LastOperation.Status == azure.AsyncOperationStatusInProgress {
+	if azure.IsOperationInProgress(cr.Status.AtProvider.LastOperation) {
 		return managed.ExternalUpdate{}, nil
 	}
-	if err := e.client.UpdateServer(ctx, cr); err != nil {
+	if err := e.client.UpdateServer(ctx, cr, e.defaultTags); err != nil {
 		return managed.ExternalUpdate{}, errors.Wrap(err, errUpdateMySQLServer)
 	}
 
@@ -205,3 +218,22 @@ func (e *external) Delete(ctx context.Context, mg resource.Managed) error {
 		azure.FetchAsyncOperation(ctx, e.client.GetRESTClient(), &cr.Status.AtProvider.LastOperation),
 		errFetchLastOperation)
 }
+
+func (e *external) Lock(ctx context.Context, mg resource.Managed) (bool, error) {
+	cr, ok := mg.(*v1beta1.MySQLServer)
+	if !ok {
+		return false, errors.New(errNotMySQLServer)
+	}
+	if cr.Status.AtProvider.ID == "" {
+		return false, nil
+	}
+	return true, azure.LockDeletion(ctx, e.locks, cr.Status.AtProvider.ID)
+}
+
+func (e *external) Unlock(ctx context.Context, mg resource.Managed) error {
+	cr, ok := mg.(*v1beta1.MySQLServer)
+	if !ok {
+		return errors.New(errNotMySQLServer)
+	}
+	return azure.UnlockDeletion(ctx, e.locks, cr.Status.AtProvider.ID)
+}
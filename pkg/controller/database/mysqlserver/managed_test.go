@@ -37,6 +37,8 @@ import (
 
 	"github.com/crossplane-contrib/provider-azure/apis/database/v1beta1"
 	azurev1alpha3 "github.com/crossplane-contrib/provider-azure/apis/v1alpha3"
+	azure "github.com/crossplane-contrib/provider-azure/pkg/clients"
+	"github.com/crossplane-contrib/provider-azure/pkg/clients/armtest"
 	"github.com/crossplane-contrib/provider-azure/pkg/clients/database"
 )
 
@@ -48,8 +50,8 @@ var (
 
 type MockMySQLServerAPI struct {
 	MockGetServer     func(ctx context.Context, s *v1beta1.MySQLServer) (mysql.Server, error)
-	MockCreateServer  func(ctx context.Context, s *v1beta1.MySQLServer, adminPassword string) error
-	MockUpdateServer  func(ctx context.Context, s *v1beta1.MySQLServer) error
+	MockCreateServer  func(ctx context.Context, s *v1beta1.MySQLServer, adminPassword string, defaultTags map[string]string) error
+	MockUpdateServer  func(ctx context.Context, s *v1beta1.MySQLServer, defaultTags map[string]string) error
 	MockDeleteServer  func(ctx context.Context, s *v1beta1.MySQLServer) error
 	MockGetRESTClient func() autorest.Sender
 }
@@ -62,12 +64,12 @@ func (m *MockMySQLServerAPI) GetServer(ctx context.Context, s *v1beta1.MySQLServ
 	return m.MockGetServer(ctx, s)
 }
 
-func (m *MockMySQLServerAPI) CreateServer(ctx context.Context, s *v1beta1.MySQLServer, adminPassword string) error {
-	return m.MockCreateServer(ctx, s, adminPassword)
+func (m *MockMySQLServerAPI) CreateServer(ctx context.Context, s *v1beta1.MySQLServer, adminPassword string, defaultTags map[string]string) error {
+	return m.MockCreateServer(ctx, s, adminPassword, defaultTags)
 }
 
-func (m *MockMySQLServerAPI) UpdateServer(ctx context.Context, s *v1beta1.MySQLServer) error {
-	return m.MockUpdateServer(ctx, s)
+func (m *MockMySQLServerAPI) UpdateServer(ctx context.Context, s *v1beta1.MySQLServer, defaultTags map[string]string) error {
+	return m.MockUpdateServer(ctx, s, defaultTags)
 }
 
 func (m *MockMySQLServerAPI) DeleteServer(ctx context.Context, s *v1beta1.MySQLServer) error {
@@ -209,7 +211,8 @@ func TestObserve(t *testing.T) {
 				client: &MockMySQLServerAPI{
 					MockGetServer: func(_ context.Context, _ *v1beta1.MySQLServer) (mysql.Server, error) {
 						return mysql.Server{
-							Sku: &mysql.Sku{},
+							Tags: azure.NewTags(mysqlserver(), nil, nil),
+							Sku:  &mysql.Sku{},
 							ServerProperties: &mysql.ServerProperties{
 								UserVisibleState:         mysql.ServerStateReady,
 								FullyQualifiedDomainName: &endpoint,
@@ -241,6 +244,46 @@ func TestObserve(t *testing.T) {
 				},
 			},
 		},
+		"MissingOwnershipTags": {
+			e: &external{
+				kube: &test.MockClient{
+					MockUpdate: test.NewMockUpdateFn(nil),
+				},
+				client: &MockMySQLServerAPI{
+					MockGetServer: func(_ context.Context, _ *v1beta1.MySQLServer) (mysql.Server, error) {
+						return mysql.Server{
+							Sku: &mysql.Sku{},
+							ServerProperties: &mysql.ServerProperties{
+								UserVisibleState:         mysql.ServerStateReady,
+								FullyQualifiedDomainName: &endpoint,
+								StorageProfile:           &mysql.StorageProfile{},
+							}}, nil
+					},
+					MockGetRESTClient: func() autorest.Sender {
+						return autorest.SenderFunc(func(*http.Request) (*http.Response, error) {
+							return nil, nil
+						})
+					},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg: mysqlserver(
+					withExternalName(name),
+					withAdminName(admin),
+				),
+			},
+			want: want{
+				eo: managed.ExternalObservation{
+					ResourceExists:   true,
+					ResourceUpToDate: false,
+					ConnectionDetails: managed.ConnectionDetails{
+						xpv1.ResourceCredentialsSecretEndpointKey: []byte(endpoint),
+						xpv1.ResourceCredentialsSecretUserKey:     []byte(fmt.Sprintf("%s@%s", admin, name)),
+					},
+				},
+			},
+		},
 	}
 
 	for name, tc := range cases {
@@ -299,7 +342,7 @@ func TestCreate(t *testing.T) {
 		"ErrCreateServer": {
 			e: &external{
 				client: &MockMySQLServerAPI{
-					MockCreateServer: func(_ context.Context, _ *v1beta1.MySQLServer, _ string) error { return errBoom },
+					MockCreateServer: func(_ context.Context, _ *v1beta1.MySQLServer, _ string, _ map[string]string) error { return errBoom },
 				},
 				newPasswordFn: func() (string, error) { return password, nil },
 			},
@@ -314,7 +357,7 @@ func TestCreate(t *testing.T) {
 		"Successful": {
 			e: &external{
 				client: &MockMySQLServerAPI{
-					MockCreateServer: func(_ context.Context, _ *v1beta1.MySQLServer, _ string) error { return nil },
+					MockCreateServer: func(_ context.Context, _ *v1beta1.MySQLServer, _ string, _ map[string]string) error { return nil },
 					MockGetRESTClient: func() autorest.Sender {
 						return autorest.SenderFunc(func(*http.Request) (*http.Response, error) {
 							return nil, nil
@@ -410,3 +453,74 @@ func TestDelete(t *testing.T) {
 		})
 	}
 }
+
+func TestDryRun(t *testing.T) {
+	// The SDK spells the provider namespace DBForMySQL.
+	id := "/subscriptions/" + armtest.DefaultSubscriptionID + "/resourceGroups/cool-rg/providers/Microsoft.DBForMySQL/servers/cool-server"
+	tags := `{"crossplane-kind":"MySQLServer","crossplane-name":"cool-server","crossplane-providerconfig":"armtest"}`
+	sku := `{"capacity":1,"family":"Gen5","name":"B_Gen5_1","tier":"Basic"}`
+
+	type want struct {
+		reason xpv1.ConditionReason
+		method string
+		body   string
+		diff   azure.Diff
+	}
+	cases := map[string]struct {
+		reason string
+		server map[string]interface{}
+		want   want
+	}{
+		"Create": {
+			reason: "The plan should show the request body built by toMySQLProperties, without the administrator password.",
+			want: want{
+				reason: azure.ReasonPlannedCreate,
+				method: http.MethodPut,
+				body:   `{"location":"westus","properties":{"administratorLogin":"cooladmin","administratorLoginPassword":"<redacted>","createMode":"Default","storageProfile":{"storageMB":51200}},"sku":` + sku + `,"tags":` + tags + `}`,
+			},
+		},
+		"Update": {
+			reason: "The plan should show the request body built from the desired parameters, and the fields that differ.",
+			server: map[string]interface{}{
+				"location": "westus",
+				"tags":     map[string]interface{}{"crossplane-kind": "MySQLServer", "crossplane-name": "cool-server", "crossplane-providerconfig": "armtest"},
+				"sku":      map[string]interface{}{"name": "B_Gen5_2", "tier": "Basic", "capacity": 2, "family": "Gen5"},
+				"properties": map[string]interface{}{
+					"userVisibleState":   "Ready",
+					"administratorLogin": "cooladmin",
+					"storageProfile":     map[string]interface{}{"storageMB": 51200},
+				},
+			},
+			want: want{
+				reason: azure.ReasonPlannedUpdate,
+				method: http.MethodPatch,
+				body:   `{"properties":{"storageProfile":{"storageMB":51200}},"sku":` + sku + `,"tags":` + tags + `}`,
+				diff:   azure.Diff{{Path: "sku.capacity", Desired: "1", Observed: "2"}},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			s := armtest.NewServer()
+			defer s.Close()
+			if tc.server != nil {
+				s.SetResource(id, tc.server)
+			}
+			cr := mysqlserver()
+			cr.SetName("cool-server")
+			meta.SetExternalName(cr, "cool-server")
+			cr.Spec.ForProvider.ResourceGroupName = "cool-rg"
+			cr.Spec.ForProvider.Location = "westus"
+			cr.Spec.ForProvider.SKU = v1beta1.SKU{Tier: "Basic", Capacity: 1, Family: "Gen5"}
+			cr.Spec.ForProvider.AdministratorLogin = "cooladmin"
+			cr.Spec.ForProvider.StorageProfile.StorageMB = 51200
+
+			got := s.PlanDryRun(t, &connecter{client: s.Kube(nil)}, cr)
+			p := azure.Plan{{Method: tc.want.method, URL: s.URL + id, Body: tc.want.body}}
+			if diff := cmp.Diff(azure.Planned(tc.want.reason, p, tc.want.diff), got, test.EquateConditions()); diff != "" {
+				t.Errorf("\n%s\nPlanDryRun(...): -want Planned condition, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -62,18 +62,19 @@ func Setup(mgr ctrl.Manager, o controller.Options) error {
 		cps = append(cps, connection.NewDetailsManager(mgr.GetClient(), v1alpha1.StoreConfigGroupVersionKind))
 	}
 
+	recorder := event.NewAPIRecorder(mgr.GetEventRecorderFor(name))
+
 	return ctrl.NewControllerManagedBy(mgr).
 		Named(name).
 		WithOptions(o.ForControllerRuntime()).
 		For(&v1beta1.MySQLServerConfiguration{}).
-		Complete(managed.NewReconciler(mgr,
-			resource.ManagedKind(v1beta1.MySQLServerConfigurationGroupVersionKind),
-			managed.WithExternalConnecter(&connecter{client: mgr.GetClient()}),
+		Complete(azure.NewReconciler(mgr, name, v1beta1.MySQLServerConfigurationGroupVersionKind,
+			managed.WithExternalConnecter(azure.NewConnecter(&connecter{client: mgr.GetClient()}, recorder)),
 			managed.WithReferenceResolver(managed.NewAPISimpleReferenceResolver(mgr.GetClient())),
 			managed.WithInitializers(managed.NewDefaultProviderConfig(mgr.GetClient())),
 			managed.WithPollInterval(o.PollInterval),
 			managed.WithLogger(o.Logger.WithValues("controller", name)),
-			managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
+			managed.WithRecorder(recorder),
 			managed.WithConnectionPublishers(cps...)))
 }
 
@@ -86,8 +87,8 @@ func (c *connecter) Connect(ctx context.Context, mg resource.Managed) (managed.E
 	if err != nil {
 		return nil, err
 	}
-	cl := mysql.NewConfigurationsClient(creds[azure.CredentialsKeySubscriptionID])
-	cl.Authorizer = auth
+	cl := mysql.NewConfigurationsClientWithBaseURI(creds[azure.CredentialsKeyResourceManagerEndpointURL], creds[azure.CredentialsKeySubscriptionID])
+	azure.ConfigureClient(&cl.Client, auth)
 	return &external{
 		kube:           c.client,
 		client:         configuration.NewMySQLConfigurationClient(cl),
@@ -143,6 +144,7 @@ func (e *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 	// status subresource but fetches the whole object after it's done. So,
 	// changes to status has to be done after kube.Update in order not to get them
 	// lost.
+	forgotten := azure.RestoreCreateOperation(cr, &cr.Status.AtProvider.LastOperation)
 	if err := azure.FetchAsyncOperation(ctx, e.client.GetRESTClient(), &cr.Status.AtProvider.LastOperation); err != nil {
 		return managed.ExternalObservation{}, errors.Wrap(err, errFetchLastOperation)
 	}
@@ -153,10 +155,13 @@ func (e *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 		cr.SetConditions(xpv1.Unavailable())
 	}
 
+	diff := configuration.IsMySQLConfigurationUpToDate(cr.Spec.ForProvider, config)
+	azure.ReportDiff(ctx, diff)
+
 	return managed.ExternalObservation{
 		ResourceExists:          true,
-		ResourceUpToDate:        configuration.IsMySQLConfigurationUpToDate(cr.Spec.ForProvider, config),
-		ResourceLateInitialized: l.IsChanged(),
+		ResourceUpToDate:        diff.UpToDate(),
+		ResourceLateInitialized: l.IsChanged() || forgotten,
 	}, nil
 }
 
@@ -173,11 +178,11 @@ func (e *external) Create(ctx context.Context, mg resource.Managed) (managed.Ext
 	meta.SetExternalName(cr, e.generateExtName(cr.Spec.ForProvider.ResourceGroupName,
 		cr.Spec.ForProvider.ServerName, cr.Spec.ForProvider.Name))
 
+	err := azure.FetchAsyncOperation(ctx, e.client.GetRESTClient(), &cr.Status.AtProvider.LastOperation)
+	azure.RecordCreateOperation(cr, cr.Status.AtProvider.LastOperation)
 	return managed.ExternalCreation{
-			ExternalNameAssigned: true,
-		}, errors.Wrap(
-			azure.FetchAsyncOperation(ctx, e.client.GetRESTClient(), &cr.Status.AtProvider.LastOperation),
-			errFetchLastOperation)
+		ExternalNameAssigned: true,
+	}, errors.Wrap(err, errFetchLastOperation)
 }
 
 func (e *external) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
@@ -185,7 +190,7 @@ func (e *external) Update(ctx context.Context, mg resource.Managed) (managed.Ext
 	if !ok {
 		return managed.ExternalUpdate{}, errors.New(errNotMySQLServerConfig)
 	}
-	if cr.Status.AtProvider.LastOperation.Status == azure.AsyncOperationStatusInProgress {
+	if azure.IsOperationInProgress(cr.Status.AtProvider.LastOperation) {
 		return managed.ExternalUpdate{}, nil
 	}
 	if err := e.client.CreateOrUpdate(ctx, cr); err != nil {
@@ -33,6 +33,8 @@ import (
 
 	"github.com/crossplane-contrib/provider-azure/apis/database/v1beta1"
 	azurev1alpha3 "github.com/crossplane-contrib/provider-azure/apis/v1alpha3"
+	azure "github.com/crossplane-contrib/provider-azure/pkg/clients"
+	"github.com/crossplane-contrib/provider-azure/pkg/clients/armtest"
 )
 
 const (
@@ -446,3 +448,22 @@ func TestUpdate(t *testing.T) {
 		})
 	}
 }
+
+func TestDryRun(t *testing.T) {
+	s := armtest.NewServer()
+	defer s.Close()
+	cr := mysqlserverconfiguration()
+	cr.Spec.ForProvider.ResourceGroupName = "cool-rg"
+	cr.Spec.ForProvider.ServerName = "cool-server"
+	cr.Spec.ForProvider.Name = "max_connections"
+	cr.Spec.ForProvider.Value = azure.ToStringPtr("100")
+
+	// Configurations cannot be created, so only their updates are planned.
+	s.SetResource("/subscriptions/"+armtest.DefaultSubscriptionID+"/resourceGroups/cool-rg/providers/Microsoft.DBforMySQL/servers/cool-server/configurations/max_connections",
+		map[string]interface{}{"properties": map[string]interface{}{"value": "50"}})
+
+	got := s.PlanDryRun(t, &connecter{client: s.Kube(nil)}, cr)
+	if diff := cmp.Diff(azure.ReasonPlannedUpdate, got.Reason); diff != "" {
+		t.Errorf("PlanDryRun(...): -want Planned reason, +got:\n%s", diff)
+	}
+}
@@ -58,18 +58,19 @@ func Setup(mgr ctrl.Manager, o controller.Options) error {
 		cps = append(cps, connection.NewDetailsManager(mgr.GetClient(), v1alpha1.StoreConfigGroupVersionKind))
 	}
 
+	recorder := event.NewAPIRecorder(mgr.GetEventRecorderFor(name))
+
 	return ctrl.NewControllerManagedBy(mgr).
 		Named(name).
 		WithOptions(o.ForControllerRuntime()).
 		For(&v1alpha3.MySQLServerVirtualNetworkRule{}).
-		Complete(managed.NewReconciler(mgr,
-			resource.ManagedKind(v1alpha3.MySQLServerVirtualNetworkRuleGroupVersionKind),
+		Complete(azure.NewReconciler(mgr, name, v1alpha3.MySQLServerVirtualNetworkRuleGroupVersionKind,
 			managed.WithConnectionPublishers(),
-			managed.WithExternalConnecter(&connecter{client: mgr.GetClient()}),
+			managed.WithExternalConnecter(azure.NewConnecter(&connecter{client: mgr.GetClient()}, recorder)),
 			managed.WithReferenceResolver(managed.NewAPISimpleReferenceResolver(mgr.GetClient())),
 			managed.WithPollInterval(o.PollInterval),
 			managed.WithLogger(o.Logger.WithValues("controller", name)),
-			managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
+			managed.WithRecorder(recorder),
 			managed.WithConnectionPublishers(cps...)))
 }
 
@@ -83,8 +84,8 @@ func (c *connecter) Connect(ctx context.Context, mg resource.Managed) (managed.E
 		return nil, err
 	}
 
-	cl := mysql.NewVirtualNetworkRulesClient(creds[azure.CredentialsKeySubscriptionID])
-	cl.Authorizer = auth
+	cl := mysql.NewVirtualNetworkRulesClientWithBaseURI(creds[azure.CredentialsKeyResourceManagerEndpointURL], creds[azure.CredentialsKeySubscriptionID])
+	azure.ConfigureClient(&cl.Client, auth)
 	return &external{client: cl}, nil
 }
 
@@ -109,9 +110,13 @@ func (e *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 	database.UpdateMySQLVirtualNetworkRuleStatusFromAzure(v, az)
 	v.SetConditions(xpv1.Available())
 
+	diff := database.MySQLServerVirtualNetworkRuleNeedsUpdate(v, az)
+	azure.ReportDiff(ctx, diff)
+
 	o := managed.ExternalObservation{
 		ResourceExists:    true,
 		ConnectionDetails: managed.ConnectionDetails{},
+		ResourceUpToDate:  diff.UpToDate(),
 	}
 
 	return o, nil
@@ -144,7 +149,7 @@ func (e *external) Update(ctx context.Context, mg resource.Managed) (managed.Ext
 		return managed.ExternalUpdate{}, errors.Wrap(err, errGetMySQLServerVirtualNetworkRule)
 	}
 
-	if database.MySQLServerVirtualNetworkRuleNeedsUpdate(v, az) {
+	if !database.MySQLServerVirtualNetworkRuleNeedsUpdate(v, az).UpToDate() {
 		vnet := database.NewMySQLVirtualNetworkRuleParameters(v)
 		if _, err := e.client.CreateOrUpdate(ctx, v.Spec.ResourceGroupName, v.Spec.ServerName, meta.GetExternalName(v), vnet); err != nil {
 			return managed.ExternalUpdate{}, errors.Wrap(err, errUpdateMySQLServerVirtualNetworkRule)
@@ -19,6 +19,7 @@ package postgresqlserver
 import (
 	"context"
 	"fmt"
+	"net/http"
 
 	"github.com/Azure/azure-sdk-for-go/services/postgresql/mgmt/2017-12-01/postgresql"
 	"github.com/pkg/errors"
@@ -65,17 +66,18 @@ func Setup(mgr ctrl.Manager, o controller.Options) error {
 		cps = append(cps, connection.NewDetailsManager(mgr.GetClient(), v1alpha1.StoreConfigGroupVersionKind))
 	}
 
+	recorder := event.NewAPIRecorder(mgr.GetEventRecorderFor(name))
+
 	return ctrl.NewControllerManagedBy(mgr).
 		Named(name).
 		WithOptions(o.ForControllerRuntime()).
 		For(&v1beta1.PostgreSQLServer{}).
-		Complete(managed.NewReconciler(mgr,
-			resource.ManagedKind(v1beta1.PostgreSQLServerGroupVersionKind),
-			managed.WithExternalConnecter(&connecter{client: mgr.GetClient()}),
+		Complete(azure.NewReconciler(mgr, name, v1beta1.PostgreSQLServerGroupVersionKind,
+			managed.WithExternalConnecter(azure.NewConnecter(&connecter{client: mgr.GetClient()}, recorder)),
 			managed.WithReferenceResolver(managed.NewAPISimpleReferenceResolver(mgr.GetClient())),
 			managed.WithPollInterval(o.PollInterval),
 			managed.WithLogger(o.Logger.WithValues("controller", name)),
-			managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
+			managed.WithRecorder(recorder),
 			managed.WithConnectionPublishers(cps...)))
 }
 
@@ -88,15 +90,20 @@ func (c *connecter) Connect(ctx context.Context, mg resource.Managed) (managed.E
 	if err != nil {
 		return nil, err
 	}
-	cl := postgresql.NewServersClient(creds[azure.CredentialsKeySubscriptionID])
-	cl.Authorizer = auth
-	return &external{kube: c.client, client: database.NewPostgreSQLServerClient(cl), newPasswordFn: password.Generate}, nil
+	cl := postgresql.NewServersClientWithBaseURI(creds[azure.CredentialsKeyResourceManagerEndpointURL], creds[azure.CredentialsKeySubscriptionID])
+	azure.ConfigureClient(&cl.Client, auth)
+	tags, err := azure.DefaultTags(ctx, c.client, mg)
+	if err != nil {
+		return nil, err
+	}
+	return &external{kube: c.client, client: database.NewPostgreSQLServerClient(cl), defaultTags: tags, newPasswordFn: password.Generate}, nil
 }
 
 type external struct {
 	kube          client.Client
 	client        database.PostgreSQLServerAPI
 	newPasswordFn func() (password string, err error)
+	defaultTags   map[string]string
 }
 
 func (e *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
@@ -106,6 +113,7 @@ func (e *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 	}
 	server, err := e.client.GetServer(ctx, cr)
 	if azure.IsNotFound(err) {
+		forgotten := azure.RestoreCreateOperation(cr, &cr.Status.AtProvider.LastOperation)
 		if err := azure.FetchAsyncOperation(ctx, e.client.GetRESTClient(), &cr.Status.AtProvider.LastOperation); err != nil {
 			return managed.ExternalObservation{}, errors.Wrap(err, errFetchLastOperation)
 		}
@@ -113,14 +121,13 @@ func (e *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 		// successfully and we cannot return `ResourceExists: false` during creation
 		// since this will cause `Create` to be called again and it's not idempotent.
 		// So, we check whether a creation operation in fact is in motion.
-		creating := cr.Status.AtProvider.LastOperation.Method == "PUT" &&
-			cr.Status.AtProvider.LastOperation.Status == azure.AsyncOperationStatusInProgress
-		return managed.ExternalObservation{ResourceExists: creating}, nil
+		creating := azure.IsOperationInProgress(cr.Status.AtProvider.LastOperation, http.MethodPut)
+		return managed.ExternalObservation{ResourceExists: creating, ResourceLateInitialized: forgotten}, nil
 	}
 	if err != nil {
 		return managed.ExternalObservation{}, errors.Wrap(err, errGetPostgreSQLServer)
 	}
-	database.LateInitializePostgreSQL(&cr.Spec.ForProvider, server)
+	database.LateInitializePostgreSQL(&cr.Spec.ForProvider, server, e.defaultTags)
 	if err := e.kube.Update(ctx, cr); err != nil {
 		return managed.ExternalObservation{}, errors.Wrap(err, errUpdateCR)
 	}
@@ -129,6 +136,7 @@ func (e *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 	// status subresource but fetches the the whole object after it's done. So,
 	// changes to status has to be done after kube.Update in order not to get them
 	// lost.
+	forgotten := azure.RestoreCreateOperation(cr, &cr.Status.AtProvider.LastOperation)
 	if err := azure.FetchAsyncOperation(ctx, e.client.GetRESTClient(), &cr.Status.AtProvider.LastOperation); err != nil {
 		return managed.ExternalObservation{}, errors.Wrap(err, errFetchLastOperation)
 	}
@@ -140,9 +148,12 @@ func (e *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 		cr.SetConditions(xpv1.Unavailable())
 	}
 
+	diff := database.IsPostgreSQLUpToDate(cr.Spec.ForProvider, server, e.defaultTags)
+	azure.ReportDiff(ctx, diff)
 	o := managed.ExternalObservation{
-		ResourceExists:   true,
-		ResourceUpToDate: database.IsPostgreSQLUpToDate(cr.Spec.ForProvider, server), // NOTE(negz): We don't yet support updating Azure SQL servers.
+		ResourceExists:          true,
+		ResourceUpToDate:        diff.UpToDate() && azure.OwnershipTagsUpToDate(cr, server.Tags),
+		ResourceLateInitialized: forgotten, // NOTE(negz): We don't yet support updating Azure SQL servers.
 		ConnectionDetails: managed.ConnectionDetails{
 			xpv1.ResourceCredentialsSecretEndpointKey: []byte(cr.Status.AtProvider.FullyQualifiedDomainName),
 			xpv1.ResourceCredentialsSecretUserKey:     []byte(fmt.Sprintf("%s@%s", cr.Spec.ForProvider.AdministratorLogin, meta.GetExternalName(cr))),
@@ -188,17 +199,17 @@ func (e *external) Create(ctx context.Context, mg resource.Managed) (managed.Ext
 			return managed.ExternalCreation{}, errors.Wrap(err, errGenPassword)
 		}
 	}
-	if err := e.client.CreateServer(ctx, cr, pw); err != nil {
+	if err := e.client.CreateServer(ctx, cr, pw, e.defaultTags); err != nil {
 		return managed.ExternalCreation{}, errors.Wrap(err, errCreatePostgreSQLServer)
 	}
 
+	err = azure.FetchAsyncOperation(ctx, e.client.GetRESTClient(), &cr.Status.AtProvider.LastOperation)
+	azure.RecordCreateOperation(cr, cr.Status.AtProvider.LastOperation)
 	return managed.ExternalCreation{
-			ConnectionDetails: managed.ConnectionDetails{
-				xpv1.ResourceCredentialsSecretPasswordKey: []byte(pw),
-			},
-		}, errors.Wrap(
-			azure.FetchAsyncOperation(ctx, e.client.GetRESTClient(), &cr.Status.AtProvider.LastOperation),
-			errFetchLastOperation)
+		ConnectionDetails: managed.ConnectionDetails{
+			xpv1.ResourceCredentialsSecretPasswordKey: []byte(pw),
+		},
+	}, errors.Wrap(err, errFetchLastOperation)
 }
 
 func (e *external) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
@@ -206,10 +217,10 @@ func (e *external) Update(ctx context.Context, mg resource.Managed) (managed.Ext
 	if !ok {
 		return managed.ExternalUpdate{}, errors.New(errNotPostgreSQLServer)
 	}
-	if cr.Status.AtProvider.LastOperation.Status == azure.AsyncOperationStatusInProgress {
+	if azure.IsOperationInProgress(cr.Status.AtProvider.LastOperation) {
 		return managed.ExternalUpdate{}, nil
 	}
-	if err := e.client.UpdateServer(ctx, cr); err != nil {
+	if err := e.client.UpdateServer(ctx, cr, e.defaultTags); err != nil {
 		return managed.ExternalUpdate{}, errors.Wrap(err, errUpdatePostgreSQLServer)
 	}
 
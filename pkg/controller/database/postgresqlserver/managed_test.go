@@ -37,6 +37,8 @@ import (
 
 	"github.com/crossplane-contrib/provider-azure/apis/database/v1beta1"
 	azurev1alpha3 "github.com/crossplane-contrib/provider-azure/apis/v1alpha3"
+	azure "github.com/crossplane-contrib/provider-azure/pkg/clients"
+	"github.com/crossplane-contrib/provider-azure/pkg/clients/armtest"
 	"github.com/crossplane-contrib/provider-azure/pkg/clients/database"
 )
 
@@ -48,9 +50,9 @@ var (
 
 type MockPostgreSQLServerAPI struct {
 	MockGetServer     func(ctx context.Context, s *v1beta1.PostgreSQLServer) (postgresql.Server, error)
-	MockCreateServer  func(ctx context.Context, s *v1beta1.PostgreSQLServer, adminPassword string) error
+	MockCreateServer  func(ctx context.Context, s *v1beta1.PostgreSQLServer, adminPassword string, defaultTags map[string]string) error
 	MockDeleteServer  func(ctx context.Context, s *v1beta1.PostgreSQLServer) error
-	MockUpdateServer  func(ctx context.Context, s *v1beta1.PostgreSQLServer) error
+	MockUpdateServer  func(ctx context.Context, s *v1beta1.PostgreSQLServer, defaultTags map[string]string) error
 	MockGetRESTClient func() autorest.Sender
 }
 
@@ -62,12 +64,12 @@ func (m *MockPostgreSQLServerAPI) GetServer(ctx context.Context, s *v1beta1.Post
 	return m.MockGetServer(ctx, s)
 }
 
-func (m *MockPostgreSQLServerAPI) CreateServer(ctx context.Context, s *v1beta1.PostgreSQLServer, adminPassword string) error {
-	return m.MockCreateServer(ctx, s, adminPassword)
+func (m *MockPostgreSQLServerAPI) CreateServer(ctx context.Context, s *v1beta1.PostgreSQLServer, adminPassword string, defaultTags map[string]string) error {
+	return m.MockCreateServer(ctx, s, adminPassword, defaultTags)
 }
 
-func (m *MockPostgreSQLServerAPI) UpdateServer(ctx context.Context, s *v1beta1.PostgreSQLServer) error {
-	return m.MockUpdateServer(ctx, s)
+func (m *MockPostgreSQLServerAPI) UpdateServer(ctx context.Context, s *v1beta1.PostgreSQLServer, defaultTags map[string]string) error {
+	return m.MockUpdateServer(ctx, s, defaultTags)
 }
 
 func (m *MockPostgreSQLServerAPI) DeleteServer(ctx context.Context, s *v1beta1.PostgreSQLServer) error {
@@ -209,7 +211,8 @@ func TestObserve(t *testing.T) {
 				client: &MockPostgreSQLServerAPI{
 					MockGetServer: func(_ context.Context, _ *v1beta1.PostgreSQLServer) (postgresql.Server, error) {
 						return postgresql.Server{
-							Sku: &postgresql.Sku{},
+							Tags: azure.NewTags(postgresqlserver(), nil, nil),
+							Sku:  &postgresql.Sku{},
 							ServerProperties: &postgresql.ServerProperties{
 								UserVisibleState:         postgresql.ServerStateReady,
 								FullyQualifiedDomainName: &endpoint,
@@ -300,7 +303,9 @@ func TestCreate(t *testing.T) {
 		"ErrCreateServer": {
 			e: &external{
 				client: &MockPostgreSQLServerAPI{
-					MockCreateServer: func(_ context.Context, _ *v1beta1.PostgreSQLServer, _ string) error { return errBoom },
+					MockCreateServer: func(_ context.Context, _ *v1beta1.PostgreSQLServer, _ string, _ map[string]string) error {
+						return errBoom
+					},
 				},
 				newPasswordFn: func() (string, error) { return password, nil },
 			},
@@ -315,7 +320,7 @@ func TestCreate(t *testing.T) {
 		"Successful": {
 			e: &external{
 				client: &MockPostgreSQLServerAPI{
-					MockCreateServer: func(_ context.Context, _ *v1beta1.PostgreSQLServer, _ string) error { return nil },
+					MockCreateServer: func(_ context.Context, _ *v1beta1.PostgreSQLServer, _ string, _ map[string]string) error { return nil },
 					MockGetRESTClient: func() autorest.Sender {
 						return autorest.SenderFunc(func(*http.Request) (*http.Response, error) {
 							return nil, nil
@@ -411,3 +416,18 @@ func TestDelete(t *testing.T) {
 		})
 	}
 }
+
+func TestDryRun(t *testing.T) {
+	s := armtest.NewServer()
+	defer s.Close()
+	cr := postgresqlserver()
+	meta.SetExternalName(cr, "cool-server")
+	cr.Spec.ForProvider.ResourceGroupName = "cool-rg"
+	cr.Spec.ForProvider.Location = "westus"
+	cr.Spec.ForProvider.SKU = v1beta1.SKU{Tier: "Basic", Capacity: 1, Family: "Gen5"}
+
+	got := s.PlanDryRun(t, &connecter{client: s.Kube(nil)}, cr)
+	if diff := cmp.Diff(azure.ReasonPlannedCreate, got.Reason); diff != "" {
+		t.Errorf("PlanDryRun(...): -want Planned reason, +got:\n%s", diff)
+	}
+}
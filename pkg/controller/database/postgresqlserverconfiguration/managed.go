@@ -19,6 +19,7 @@ package postgresqlserverconfiguration
 import (
 	"context"
 	"fmt"
+	"net/http"
 
 	"github.com/Azure/azure-sdk-for-go/services/postgresql/mgmt/2017-12-01/postgresql"
 	"github.com/pkg/errors"
@@ -61,18 +62,19 @@ func Setup(mgr ctrl.Manager, o controller.Options) error {
 		cps = append(cps, connection.NewDetailsManager(mgr.GetClient(), v1alpha1.StoreConfigGroupVersionKind))
 	}
 
+	recorder := event.NewAPIRecorder(mgr.GetEventRecorderFor(name))
+
 	return ctrl.NewControllerManagedBy(mgr).
 		Named(name).
 		WithOptions(o.ForControllerRuntime()).
 		For(&v1beta1.PostgreSQLServerConfiguration{}).
-		Complete(managed.NewReconciler(mgr,
-			resource.ManagedKind(v1beta1.PostgreSQLServerConfigurationGroupVersionKind),
-			managed.WithExternalConnecter(&connecter{client: mgr.GetClient()}),
+		Complete(azure.NewReconciler(mgr, name, v1beta1.PostgreSQLServerConfigurationGroupVersionKind,
+			managed.WithExternalConnecter(azure.NewConnecter(&connecter{client: mgr.GetClient()}, recorder)),
 			managed.WithReferenceResolver(managed.NewAPISimpleReferenceResolver(mgr.GetClient())),
 			managed.WithInitializers(managed.NewDefaultProviderConfig(mgr.GetClient())),
 			managed.WithPollInterval(o.PollInterval),
 			managed.WithLogger(o.Logger.WithValues("controller", name)),
-			managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
+			managed.WithRecorder(recorder),
 			managed.WithConnectionPublishers(cps...)))
 }
 
@@ -85,8 +87,8 @@ func (c *connecter) Connect(ctx context.Context, mg resource.Managed) (managed.E
 	if err != nil {
 		return nil, err
 	}
-	cl := postgresql.NewConfigurationsClient(creds[azure.CredentialsKeySubscriptionID])
-	cl.Authorizer = auth
+	cl := postgresql.NewConfigurationsClientWithBaseURI(creds[azure.CredentialsKeyResourceManagerEndpointURL], creds[azure.CredentialsKeySubscriptionID])
+	azure.ConfigureClient(&cl.Client, auth)
 	return &external{
 		kube:           c.client,
 		client:         configuration.NewPostgreSQLConfigurationClient(cl),
@@ -112,6 +114,7 @@ func (e *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 	}
 	config, err := e.client.Get(ctx, cr)
 	if azure.IsNotFound(err) {
+		forgotten := azure.RestoreCreateOperation(cr, &cr.Status.AtProvider.LastOperation)
 		if err := azure.FetchAsyncOperation(ctx, e.client.GetRESTClient(), &cr.Status.AtProvider.LastOperation); err != nil {
 			return managed.ExternalObservation{}, errors.Wrap(err, errFetchLastOperation)
 		}
@@ -119,9 +122,8 @@ func (e *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 		// successfully and we cannot return `ResourceExists: false` during creation
 		// since this will cause `Create` to be called again and it's not idempotent.
 		// So, we check whether a creation operation in fact is in motion.
-		creating := cr.Status.AtProvider.LastOperation.Method == "PUT" &&
-			cr.Status.AtProvider.LastOperation.Status == azure.AsyncOperationStatusInProgress
-		return managed.ExternalObservation{ResourceExists: creating}, nil
+		creating := azure.IsOperationInProgress(cr.Status.AtProvider.LastOperation, http.MethodPut)
+		return managed.ExternalObservation{ResourceExists: creating, ResourceLateInitialized: forgotten}, nil
 	}
 	if err != nil {
 		return managed.ExternalObservation{}, errors.Wrap(err, errGetPostgreSQLServerConfig)
@@ -147,6 +149,7 @@ func (e *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 	// status subresource but fetches the whole object after it's done. So,
 	// changes to status has to be done after kube.Update in order not to get them
 	// lost.
+	forgotten := azure.RestoreCreateOperation(cr, &cr.Status.AtProvider.LastOperation)
 	if err := azure.FetchAsyncOperation(ctx, e.client.GetRESTClient(), &cr.Status.AtProvider.LastOperation); err != nil {
 		return managed.ExternalObservation{}, errors.Wrap(err, errFetchLastOperation)
 	}
@@ -157,10 +160,13 @@ func (e *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 		cr.SetConditions(xpv1.Unavailable())
 	}
 
+	diff := configuration.IsPostgreSQLConfigurationUpToDate(cr.Spec.ForProvider, config)
+	azure.ReportDiff(ctx, diff)
+
 	return managed.ExternalObservation{
 		ResourceExists:          true,
-		ResourceUpToDate:        configuration.IsPostgreSQLConfigurationUpToDate(cr.Spec.ForProvider, config),
-		ResourceLateInitialized: l.IsChanged(),
+		ResourceUpToDate:        diff.UpToDate(),
+		ResourceLateInitialized: l.IsChanged() || forgotten,
 	}, nil
 }
 
@@ -177,11 +183,11 @@ func (e *external) Create(ctx context.Context, mg resource.Managed) (managed.Ext
 	meta.SetExternalName(cr, e.generateExtName(cr.Spec.ForProvider.ResourceGroupName,
 		cr.Spec.ForProvider.ServerName, cr.Spec.ForProvider.Name))
 
+	err := azure.FetchAsyncOperation(ctx, e.client.GetRESTClient(), &cr.Status.AtProvider.LastOperation)
+	azure.RecordCreateOperation(cr, cr.Status.AtProvider.LastOperation)
 	return managed.ExternalCreation{
-			ExternalNameAssigned: true,
-		}, errors.Wrap(
-			azure.FetchAsyncOperation(ctx, e.client.GetRESTClient(), &cr.Status.AtProvider.LastOperation),
-			errFetchLastOperation)
+		ExternalNameAssigned: true,
+	}, errors.Wrap(err, errFetchLastOperation)
 }
 
 func (e *external) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
@@ -189,7 +195,7 @@ func (e *external) Update(ctx context.Context, mg resource.Managed) (managed.Ext
 	if !ok {
 		return managed.ExternalUpdate{}, errors.New(errNotPostgreSQLServerConfig)
 	}
-	if cr.Status.AtProvider.LastOperation.Status == azure.AsyncOperationStatusInProgress {
+	if azure.IsOperationInProgress(cr.Status.AtProvider.LastOperation) {
 		return managed.ExternalUpdate{}, nil
 	}
 	if err := e.client.CreateOrUpdate(ctx, cr); err != nil {
@@ -58,18 +58,19 @@ func Setup(mgr ctrl.Manager, o controller.Options) error {
 		cps = append(cps, connection.NewDetailsManager(mgr.GetClient(), v1alpha1.StoreConfigGroupVersionKind))
 	}
 
+	recorder := event.NewAPIRecorder(mgr.GetEventRecorderFor(name))
+
 	return ctrl.NewControllerManagedBy(mgr).
 		Named(name).
 		WithOptions(o.ForControllerRuntime()).
 		For(&v1alpha3.PostgreSQLServerFirewallRule{}).
-		Complete(managed.NewReconciler(mgr,
-			resource.ManagedKind(v1alpha3.PostgreSQLServerFirewallRuleGroupVersionKind),
+		Complete(azure.NewReconciler(mgr, name, v1alpha3.PostgreSQLServerFirewallRuleGroupVersionKind,
 			managed.WithConnectionPublishers(),
-			managed.WithExternalConnecter(&connecter{client: mgr.GetClient()}),
+			managed.WithExternalConnecter(azure.NewConnecter(&connecter{client: mgr.GetClient()}, recorder)),
 			managed.WithReferenceResolver(managed.NewAPISimpleReferenceResolver(mgr.GetClient())),
 			managed.WithPollInterval(o.PollInterval),
 			managed.WithLogger(o.Logger.WithValues("controller", name)),
-			managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
+			managed.WithRecorder(recorder),
 			managed.WithConnectionPublishers(cps...)))
 }
 
@@ -82,8 +83,8 @@ func (c *connecter) Connect(ctx context.Context, mg resource.Managed) (managed.E
 	if err != nil {
 		return nil, err
 	}
-	cl := postgresql.NewFirewallRulesClient(creds[azure.CredentialsKeySubscriptionID])
-	cl.Authorizer = auth
+	cl := postgresql.NewFirewallRulesClientWithBaseURI(creds[azure.CredentialsKeyResourceManagerEndpointURL], creds[azure.CredentialsKeySubscriptionID])
+	azure.ConfigureClient(&cl.Client, auth)
 	return &external{client: cl}, nil
 }
 
@@ -109,9 +110,12 @@ func (e *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 	v.Status.AtProvider.Type = azure.ToString(az.Type)
 	v.SetConditions(xpv1.Available())
 
+	diff := database.PostgreSQLServerFirewallRuleIsUpToDate(v, az)
+	azure.ReportDiff(ctx, diff)
+
 	o := managed.ExternalObservation{
 		ResourceExists:   true,
-		ResourceUpToDate: database.PostgreSQLServerFirewallRuleIsUpToDate(v, az),
+		ResourceUpToDate: diff.UpToDate(),
 	}
 
 	return o, nil
@@ -35,6 +35,7 @@ import (
 
 	"github.com/crossplane-contrib/provider-azure/apis/database/v1alpha3"
 	azure "github.com/crossplane-contrib/provider-azure/pkg/clients"
+	"github.com/crossplane-contrib/provider-azure/pkg/clients/armtest"
 	"github.com/crossplane-contrib/provider-azure/pkg/clients/fake"
 )
 
@@ -422,3 +423,14 @@ func TestDelete(t *testing.T) {
 		})
 	}
 }
+
+func TestDryRun(t *testing.T) {
+	s := armtest.NewServer()
+	defer s.Close()
+	cr := firewallRule()
+
+	got := s.PlanDryRun(t, &connecter{client: s.Kube(nil)}, cr)
+	if diff := cmp.Diff(azure.ReasonPlannedCreate, got.Reason); diff != "" {
+		t.Errorf("PlanDryRun(...): -want Planned reason, +got:\n%s", diff)
+	}
+}
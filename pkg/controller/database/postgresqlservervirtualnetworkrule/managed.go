@@ -58,18 +58,19 @@ func Setup(mgr ctrl.Manager, o controller.Options) error {
 		cps = append(cps, connection.NewDetailsManager(mgr.GetClient(), v1alpha1.StoreConfigGroupVersionKind))
 	}
 
+	recorder := event.NewAPIRecorder(mgr.GetEventRecorderFor(name))
+
 	return ctrl.NewControllerManagedBy(mgr).
 		Named(name).
 		WithOptions(o.ForControllerRuntime()).
 		For(&v1alpha3.PostgreSQLServerVirtualNetworkRule{}).
-		Complete(managed.NewReconciler(mgr,
-			resource.ManagedKind(v1alpha3.PostgreSQLServerVirtualNetworkRuleGroupVersionKind),
+		Complete(azure.NewReconciler(mgr, name, v1alpha3.PostgreSQLServerVirtualNetworkRuleGroupVersionKind,
 			managed.WithConnectionPublishers(),
-			managed.WithExternalConnecter(&connecter{client: mgr.GetClient()}),
+			managed.WithExternalConnecter(azure.NewConnecter(&connecter{client: mgr.GetClient()}, recorder)),
 			managed.WithReferenceResolver(managed.NewAPISimpleReferenceResolver(mgr.GetClient())),
 			managed.WithPollInterval(o.PollInterval),
 			managed.WithLogger(o.Logger.WithValues("controller", name)),
-			managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
+			managed.WithRecorder(recorder),
 			managed.WithConnectionPublishers(cps...)))
 }
 
@@ -83,8 +84,8 @@ func (c *connecter) Connect(ctx context.Context, mg resource.Managed) (managed.E
 		return nil, err
 	}
 
-	cl := postgresql.NewVirtualNetworkRulesClient(creds[azure.CredentialsKeySubscriptionID])
-	cl.Authorizer = auth
+	cl := postgresql.NewVirtualNetworkRulesClientWithBaseURI(creds[azure.CredentialsKeyResourceManagerEndpointURL], creds[azure.CredentialsKeySubscriptionID])
+	azure.ConfigureClient(&cl.Client, auth)
 	return &external{client: cl}, nil
 }
 
@@ -110,10 +111,13 @@ func (e *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 
 	v.SetConditions(xpv1.Available())
 
+	diff := database.PostgreSQLServerVirtualNetworkRuleNeedsUpdate(v, az)
+	azure.ReportDiff(ctx, diff)
+
 	return managed.ExternalObservation{
 		ResourceExists:    true,
 		ConnectionDetails: managed.ConnectionDetails{},
-		ResourceUpToDate:  !database.PostgreSQLServerVirtualNetworkRuleNeedsUpdate(v, az),
+		ResourceUpToDate:  diff.UpToDate(),
 	}, nil
 }
 
@@ -56,18 +56,19 @@ func Setup(mgr ctrl.Manager, o controller.Options) error {
 		cps = append(cps, connection.NewDetailsManager(mgr.GetClient(), v1alpha1.StoreConfigGroupVersionKind))
 	}
 
+	recorder := event.NewAPIRecorder(mgr.GetEventRecorderFor(name))
+
 	return ctrl.NewControllerManagedBy(mgr).
 		Named(name).
 		WithOptions(o.ForControllerRuntime()).
 		For(&dnsv1alpha1.RecordSet{}).
-		Complete(managed.NewReconciler(mgr,
-			resource.ManagedKind(dnsv1alpha1.RecordSetGroupVersionKind),
+		Complete(azureclients.NewReconciler(mgr, name, dnsv1alpha1.RecordSetGroupVersionKind,
 			managed.WithConnectionPublishers(),
-			managed.WithExternalConnecter(&connecter{client: mgr.GetClient()}),
+			managed.WithExternalConnecter(azureclients.NewConnecter(&connecter{client: mgr.GetClient()}, recorder)),
 			managed.WithReferenceResolver(managed.NewAPISimpleReferenceResolver(mgr.GetClient())),
 			managed.WithPollInterval(o.PollInterval),
 			managed.WithLogger(o.Logger.WithValues("controller", name)),
-			managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
+			managed.WithRecorder(recorder),
 			managed.WithConnectionPublishers(cps...)))
 }
 
@@ -80,8 +81,8 @@ func (c *connecter) Connect(ctx context.Context, mg resource.Managed) (managed.E
 	if err != nil {
 		return nil, err
 	}
-	cl := dns.NewRecordSetsClient(creds[azureclients.CredentialsKeySubscriptionID])
-	cl.Authorizer = auth
+	cl := dns.NewRecordSetsClientWithBaseURI(creds[azureclients.CredentialsKeyResourceManagerEndpointURL], creds[azureclients.CredentialsKeySubscriptionID])
+	azureclients.ConfigureClient(&cl.Client, auth)
 	return &external{
 		client: dnsclients.NewRecordSetClient(cl),
 	}, nil
@@ -115,9 +116,11 @@ func (e *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 
 	r.SetConditions(xpv1.Available())
 
+	diff := dnsclients.RecordSetIsUpToDate(&r.Spec.ForProvider, az.RecordSetProperties)
+	azureclients.ReportDiff(ctx, diff)
 	o := managed.ExternalObservation{
 		ResourceExists:   true,
-		ResourceUpToDate: dnsclients.RecordSetIsUpToDate(&r.Spec.ForProvider, az.RecordSetProperties),
+		ResourceUpToDate: diff.UpToDate(),
 	}
 
 	return o, nil
@@ -32,6 +32,8 @@ import (
 	"github.com/crossplane/crossplane-runtime/pkg/test"
 
 	"github.com/crossplane-contrib/provider-azure/apis/dns/v1alpha1"
+	azure "github.com/crossplane-contrib/provider-azure/pkg/clients"
+	"github.com/crossplane-contrib/provider-azure/pkg/clients/armtest"
 )
 
 type MockRecordSetAPI struct {
@@ -168,3 +170,18 @@ func TestObserve(t *testing.T) {
 		})
 	}
 }
+
+func TestDryRun(t *testing.T) {
+	s := armtest.NewServer()
+	defer s.Close()
+	cr := zone()
+	meta.SetExternalName(cr, "cool")
+	cr.Spec.ForProvider.ResourceGroupName = "cool-rg"
+	cr.Spec.ForProvider.ZoneName = "cool.example.org"
+	cr.Spec.ForProvider.RecordType = "A"
+
+	got := s.PlanDryRun(t, &connecter{client: s.Kube(nil)}, cr)
+	if diff := cmp.Diff(azure.ReasonPlannedCreate, got.Reason); diff != "" {
+		t.Errorf("PlanDryRun(...): -want Planned reason, +got:\n%s", diff)
+	}
+}
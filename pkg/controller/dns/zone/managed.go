@@ -20,6 +20,7 @@ import (
 	"context"
 
 	dnsapi "github.com/Azure/azure-sdk-for-go/services/dns/mgmt/2018-05-01/dns"
+	"github.com/Azure/azure-sdk-for-go/services/resources/mgmt/2016-09-01/locks/locksapi"
 	"github.com/pkg/errors"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -56,18 +57,19 @@ func Setup(mgr ctrl.Manager, o controller.Options) error {
 		cps = append(cps, connection.NewDetailsManager(mgr.GetClient(), v1alpha1.StoreConfigGroupVersionKind))
 	}
 
+	recorder := event.NewAPIRecorder(mgr.GetEventRecorderFor(name))
+
 	return ctrl.NewControllerManagedBy(mgr).
 		Named(name).
 		WithOptions(o.ForControllerRuntime()).
 		For(&dnsv1alpha1.Zone{}).
-		Complete(managed.NewReconciler(mgr,
-			resource.ManagedKind(dnsv1alpha1.ZoneGroupVersionKind),
+		Complete(azureclients.NewReconciler(mgr, name, dnsv1alpha1.ZoneGroupVersionKind,
 			managed.WithConnectionPublishers(),
-			managed.WithExternalConnecter(&connecter{client: mgr.GetClient()}),
+			managed.WithExternalConnecter(azureclients.NewConnecter(&connecter{client: mgr.GetClient()}, recorder)),
 			managed.WithReferenceResolver(managed.NewAPISimpleReferenceResolver(mgr.GetClient())),
 			managed.WithPollInterval(o.PollInterval),
 			managed.WithLogger(o.Logger.WithValues("controller", name)),
-			managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
+			managed.WithRecorder(recorder),
 			managed.WithConnectionPublishers(cps...)))
 }
 
@@ -80,15 +82,23 @@ func (c *connecter) Connect(ctx context.Context, mg resource.Managed) (managed.E
 	if err != nil {
 		return nil, err
 	}
-	cl := dnsapi.NewZonesClient(creds[azureclients.CredentialsKeySubscriptionID])
-	cl.Authorizer = auth
+	cl := dnsapi.NewZonesClientWithBaseURI(creds[azureclients.CredentialsKeyResourceManagerEndpointURL], creds[azureclients.CredentialsKeySubscriptionID])
+	azureclients.ConfigureClient(&cl.Client, auth)
+	tags, err := azureclients.DefaultTags(ctx, c.client, mg)
+	if err != nil {
+		return nil, err
+	}
 	return &external{
-		client: dns.NewZoneClient(cl),
+		client:      dns.NewZoneClient(cl),
+		locks:       azureclients.NewManagementLocksClient(creds, auth),
+		defaultTags: tags,
 	}, nil
 }
 
 type external struct {
-	client dns.ZoneAPI
+	client      dns.ZoneAPI
+	locks       locksapi.ManagementLocksClientAPI
+	defaultTags map[string]string
 }
 
 func (e *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
@@ -109,9 +119,12 @@ func (e *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 
 	z.SetConditions(xpv1.Available())
 
+	diff := dns.ZoneIsUpToDate(z, az, e.defaultTags)
+	azureclients.ReportDiff(ctx, diff)
+
 	o := managed.ExternalObservation{
 		ResourceExists:   true,
-		ResourceUpToDate: dns.ZoneIsUpToDate(z, az),
+		ResourceUpToDate: diff.UpToDate() && azureclients.OwnershipTagsUpToDate(z, az.Tags),
 	}
 
 	return o, nil
@@ -123,7 +136,7 @@ func (e *external) Create(ctx context.Context, mg resource.Managed) (managed.Ext
 		return managed.ExternalCreation{}, errors.New(errNotDNSZone)
 	}
 
-	return managed.ExternalCreation{}, errors.Wrap(e.client.CreateOrUpdate(ctx, z), errCreateDNSZone)
+	return managed.ExternalCreation{}, errors.Wrap(e.client.CreateOrUpdate(ctx, z, e.defaultTags), errCreateDNSZone)
 }
 
 func (e *external) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
@@ -139,7 +152,7 @@ func (e *external) Update(ctx context.Context, mg resource.Managed) (managed.Ext
 
 	dns.UpdateZoneStatusFromAzure(z, az)
 
-	return managed.ExternalUpdate{}, errors.Wrap(e.client.CreateOrUpdate(ctx, z), errUpdateDNSZone)
+	return managed.ExternalUpdate{}, errors.Wrap(e.client.CreateOrUpdate(ctx, z, e.defaultTags), errUpdateDNSZone)
 }
 
 func (e *external) Delete(ctx context.Context, mg resource.Managed) error {
@@ -159,3 +172,22 @@ func (e *external) Delete(ctx context.Context, mg resource.Managed) error {
 
 	return errors.Wrap(resource.Ignore(azureclients.IsNotFound, err), errDeleteDNSZone)
 }
+
+func (e *external) Lock(ctx context.Context, mg resource.Managed) (bool, error) {
+	z, ok := mg.(*dnsv1alpha1.Zone)
+	if !ok {
+		return false, errors.New(errNotDNSZone)
+	}
+	if z.Status.AtProvider.ID == "" {
+		return false, nil
+	}
+	return true, azureclients.LockDeletion(ctx, e.locks, z.Status.AtProvider.ID)
+}
+
+func (e *external) Unlock(ctx context.Context, mg resource.Managed) error {
+	z, ok := mg.(*dnsv1alpha1.Zone)
+	if !ok {
+		return errors.New(errNotDNSZone)
+	}
+	return azureclients.UnlockDeletion(ctx, e.locks, z.Status.AtProvider.ID)
+}
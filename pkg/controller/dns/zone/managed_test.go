@@ -32,11 +32,13 @@ import (
 	"github.com/crossplane/crossplane-runtime/pkg/test"
 
 	"github.com/crossplane-contrib/provider-azure/apis/dns/v1alpha1"
+	azure "github.com/crossplane-contrib/provider-azure/pkg/clients"
+	"github.com/crossplane-contrib/provider-azure/pkg/clients/armtest"
 )
 
 type MockZoneAPI struct {
 	MockGet            func(ctx context.Context, z *v1alpha1.Zone) (dns.Zone, error)
-	MockCreateOrUpdate func(ctx context.Context, z *v1alpha1.Zone) error
+	MockCreateOrUpdate func(ctx context.Context, z *v1alpha1.Zone, defaultTags map[string]string) error
 	MockDelete         func(ctx context.Context, z *v1alpha1.Zone) error
 }
 
@@ -44,8 +46,8 @@ func (m *MockZoneAPI) Get(ctx context.Context, z *v1alpha1.Zone) (dns.Zone, erro
 	return m.MockGet(ctx, z)
 }
 
-func (m *MockZoneAPI) CreateOrUpdate(ctx context.Context, z *v1alpha1.Zone) error {
-	return m.MockCreateOrUpdate(ctx, z)
+func (m *MockZoneAPI) CreateOrUpdate(ctx context.Context, z *v1alpha1.Zone, defaultTags map[string]string) error {
+	return m.MockCreateOrUpdate(ctx, z, defaultTags)
 }
 
 func (m *MockZoneAPI) Delete(ctx context.Context, z *v1alpha1.Zone) error {
@@ -135,6 +137,7 @@ func TestObserve(t *testing.T) {
 				client: &MockZoneAPI{
 					MockGet: func(_ context.Context, _ *v1alpha1.Zone) (dns.Zone, error) {
 						return dns.Zone{
+							Tags:           azure.NewTags(zone(), nil, nil),
 							ZoneProperties: &dns.ZoneProperties{},
 						}, nil
 					},
@@ -148,7 +151,31 @@ func TestObserve(t *testing.T) {
 			},
 			want: want{
 				eo: managed.ExternalObservation{
-					ResourceExists: true,
+					ResourceExists:   true,
+					ResourceUpToDate: true,
+				},
+			},
+		},
+		"MissingOwnershipTags": {
+			e: &external{
+				client: &MockZoneAPI{
+					MockGet: func(_ context.Context, _ *v1alpha1.Zone) (dns.Zone, error) {
+						return dns.Zone{
+							ZoneProperties: &dns.ZoneProperties{},
+						}, nil
+					},
+				},
+			},
+			args: args{
+				ctx: context.Background(),
+				mg: zone(
+					withExternalName(name),
+				),
+			},
+			want: want{
+				eo: managed.ExternalObservation{
+					ResourceExists:   true,
+					ResourceUpToDate: false,
 				},
 			},
 		},
@@ -197,7 +224,7 @@ func TestCreate(t *testing.T) {
 		"ErrCreateServer": {
 			e: &external{
 				client: &MockZoneAPI{
-					MockCreateOrUpdate: func(_ context.Context, _ *v1alpha1.Zone) error { return errBoom },
+					MockCreateOrUpdate: func(_ context.Context, _ *v1alpha1.Zone, _ map[string]string) error { return errBoom },
 				},
 			},
 			args: args{
@@ -211,7 +238,7 @@ func TestCreate(t *testing.T) {
 		"Successful": {
 			e: &external{
 				client: &MockZoneAPI{
-					MockCreateOrUpdate: func(_ context.Context, _ *v1alpha1.Zone) error { return nil },
+					MockCreateOrUpdate: func(_ context.Context, _ *v1alpha1.Zone, _ map[string]string) error { return nil },
 				},
 			},
 			args: args{
@@ -340,7 +367,7 @@ func TestUpdate(t *testing.T) {
 							ZoneProperties: &dns.ZoneProperties{},
 						}, nil
 					},
-					MockCreateOrUpdate: func(_ context.Context, _ *v1alpha1.Zone) error { return errBoom },
+					MockCreateOrUpdate: func(_ context.Context, _ *v1alpha1.Zone, _ map[string]string) error { return errBoom },
 				},
 			},
 			args: args{
@@ -359,7 +386,7 @@ func TestUpdate(t *testing.T) {
 							ZoneProperties: &dns.ZoneProperties{},
 						}, nil
 					},
-					MockCreateOrUpdate: func(_ context.Context, _ *v1alpha1.Zone) error { return nil },
+					MockCreateOrUpdate: func(_ context.Context, _ *v1alpha1.Zone, _ map[string]string) error { return nil },
 				},
 			},
 			args: args{
@@ -385,3 +412,16 @@ func TestUpdate(t *testing.T) {
 		})
 	}
 }
+
+func TestDryRun(t *testing.T) {
+	s := armtest.NewServer()
+	defer s.Close()
+	cr := zone()
+	meta.SetExternalName(cr, "cool.example.org")
+	cr.Spec.ForProvider.ResourceGroupName = "cool-rg"
+
+	got := s.PlanDryRun(t, &connecter{client: s.Kube(nil)}, cr)
+	if diff := cmp.Diff(azure.ReasonPlannedCreate, got.Reason); diff != "" {
+		t.Errorf("PlanDryRun(...): -want Planned reason, +got:\n%s", diff)
+	}
+}
@@ -60,16 +60,17 @@ func SetupSecret(mgr ctrl.Manager, o controller.Options) error {
 		cps = append(cps, connection.NewDetailsManager(mgr.GetClient(), v1alpha1.StoreConfigGroupVersionKind))
 	}
 
+	recorder := event.NewAPIRecorder(mgr.GetEventRecorderFor(name))
+
 	return ctrl.NewControllerManagedBy(mgr).
 		Named(name).
 		WithOptions(o.ForControllerRuntime()).
 		For(&keyvaultv1alpha1.KeyVaultSecret{}).
-		Complete(managed.NewReconciler(mgr,
-			resource.ManagedKind(keyvaultv1alpha1.KeyVaultSecretGroupVersionKind),
-			managed.WithExternalConnecter(&connector{kube: mgr.GetClient()}),
+		Complete(azure.NewReconciler(mgr, name, keyvaultv1alpha1.KeyVaultSecretGroupVersionKind,
+			managed.WithExternalConnecter(azure.NewConnecter(&connector{kube: mgr.GetClient()}, recorder)),
 			managed.WithPollInterval(o.PollInterval),
 			managed.WithLogger(o.Logger.WithValues("controller", name)),
-			managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
+			managed.WithRecorder(recorder),
 			managed.WithConnectionPublishers(cps...)))
 }
 
@@ -78,18 +79,30 @@ type connector struct {
 }
 
 func (c connector) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
-	_, auth, err := azure.GetAuthInfo(ctx, c.kube, mg)
+	// Key Vault is a data plane API, so it requires a token for the Key Vault
+	// resource of the selected cloud rather than for Azure Resource Manager.
+	creds, auth, err := azure.GetResourceAuthInfo(ctx, c.kube, mg, azure.CredentialsKeyKeyVaultResourceID)
 	if err != nil {
 		return nil, errors.Wrap(err, errConnectFailed)
 	}
 	cl := keyvault.New()
-	cl.Authorizer = auth
-	return &external{kube: c.kube, client: cl}, nil
+	azure.ConfigureClient(&cl.Client, auth)
+	tags, err := azure.DefaultTags(ctx, c.kube, mg)
+	if err != nil {
+		return nil, errors.Wrap(err, errConnectFailed)
+	}
+	return &external{kube: c.kube, client: cl, creds: creds, defaultTags: tags}, nil
 }
 
 type external struct {
-	kube   client.Client
-	client keyvaultapi.BaseClientAPI
+	kube        client.Client
+	client      keyvaultapi.BaseClientAPI
+	creds       map[string]string
+	defaultTags map[string]string
+}
+
+func (c *external) vaultBaseURL(cr *keyvaultv1alpha1.KeyVaultSecret) string {
+	return azure.KeyVaultBaseURL(c.creds, cr.Spec.ForProvider.VaultBaseURL)
 }
 
 func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
@@ -98,14 +111,14 @@ func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 		return managed.ExternalObservation{}, errors.New(errNotSecret)
 	}
 
-	secret, err := c.client.GetSecret(ctx, cr.Spec.ForProvider.VaultBaseURL, cr.Spec.ForProvider.Name, "" /* latest */)
+	secret, err := c.client.GetSecret(ctx, c.vaultBaseURL(cr), cr.Spec.ForProvider.Name, "" /* latest */)
 	if err != nil {
 		return managed.ExternalObservation{ResourceExists: false}, errors.Wrap(resource.Ignore(azure.IsNotFound, err), errGetFailed)
 	}
 
 	lateInit := false
 	currentSpec := cr.Spec.ForProvider.DeepCopy()
-	secretclients.LateInitialize(&cr.Spec.ForProvider, secret)
+	secretclients.LateInitialize(&cr.Spec.ForProvider, secret, c.defaultTags)
 	if !cmp.Equal(currentSpec, &cr.Spec.ForProvider) {
 		lateInit = true
 	}
@@ -113,15 +126,16 @@ func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 	cr.Status.SetConditions(xpv1.Available())
 	cr.Status.AtProvider = secretclients.GenerateObservation(secret)
 
-	isUpToDate, err := secretclients.IsUpToDate(ctx, c.kube, cr.Spec.ForProvider, &secret)
+	diff, err := secretclients.IsUpToDate(ctx, c.kube, cr.Spec.ForProvider, &secret, c.defaultTags)
 
 	if err != nil {
 		return managed.ExternalObservation{}, errors.Wrap(err, errCheckUpToDate)
 	}
+	azure.ReportDiff(ctx, diff)
 
 	return managed.ExternalObservation{
 		ResourceExists:          true,
-		ResourceUpToDate:        isUpToDate,
+		ResourceUpToDate:        diff.UpToDate() && azure.OwnershipTagsUpToDate(cr, secret.Tags),
 		ResourceLateInitialized: lateInit,
 	}, nil
 }
@@ -137,9 +151,9 @@ func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.Ext
 		return managed.ExternalCreation{}, err
 	}
 
-	_, err = c.client.SetSecret(ctx, cr.Spec.ForProvider.VaultBaseURL, cr.Spec.ForProvider.Name, keyvault.SecretSetParameters{
+	_, err = c.client.SetSecret(ctx, c.vaultBaseURL(cr), cr.Spec.ForProvider.Name, keyvault.SecretSetParameters{
 		Value:            azure.ToStringPtr(val),
-		Tags:             azure.ToStringPtrMap(cr.Spec.ForProvider.Tags),
+		Tags:             azure.NewTags(cr, c.defaultTags, cr.Spec.ForProvider.Tags),
 		ContentType:      cr.Spec.ForProvider.ContentType,
 		SecretAttributes: secretclients.GenerateAttributes(cr.Spec.ForProvider.SecretAttributes),
 	})
@@ -162,9 +176,9 @@ func (c *external) Update(ctx context.Context, mg resource.Managed) (managed.Ext
 		return managed.ExternalUpdate{}, err
 	}
 
-	_, err = c.client.SetSecret(ctx, cr.Spec.ForProvider.VaultBaseURL, cr.Spec.ForProvider.Name, keyvault.SecretSetParameters{
+	_, err = c.client.SetSecret(ctx, c.vaultBaseURL(cr), cr.Spec.ForProvider.Name, keyvault.SecretSetParameters{
 		Value:            azure.ToStringPtr(val),
-		Tags:             azure.ToStringPtrMap(cr.Spec.ForProvider.Tags),
+		Tags:             azure.NewTags(cr, c.defaultTags, cr.Spec.ForProvider.Tags),
 		ContentType:      cr.Spec.ForProvider.ContentType,
 		SecretAttributes: secretclients.GenerateAttributes(cr.Spec.ForProvider.SecretAttributes),
 	})
@@ -182,7 +196,7 @@ func (c *external) Delete(ctx context.Context, mg resource.Managed) error {
 		return errors.New(errNotSecret)
 	}
 
-	_, err := c.client.DeleteSecret(ctx, cr.Spec.ForProvider.VaultBaseURL, cr.Spec.ForProvider.Name)
+	_, err := c.client.DeleteSecret(ctx, c.vaultBaseURL(cr), cr.Spec.ForProvider.Name)
 
 	return errors.Wrap(resource.Ignore(azure.IsNotFound, err), errDeleteFailed)
 }
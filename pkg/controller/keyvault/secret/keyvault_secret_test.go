@@ -35,6 +35,7 @@ import (
 
 	"github.com/crossplane-contrib/provider-azure/apis/keyvault/v1alpha1"
 	azure "github.com/crossplane-contrib/provider-azure/pkg/clients"
+	"github.com/crossplane-contrib/provider-azure/pkg/clients/armtest"
 	"github.com/crossplane-contrib/provider-azure/pkg/clients/keyvault/secret/fake"
 )
 
@@ -488,3 +489,16 @@ func TestDelete(t *testing.T) {
 		})
 	}
 }
+
+func TestDryRun(t *testing.T) {
+	s := armtest.NewServer()
+	defer s.Close()
+	cr := instance()
+	cr.Spec.ForProvider.VaultBaseURL = s.URL
+	cr.Spec.ForProvider.Value.Key = armtest.SecretKey
+
+	got := s.PlanDryRun(t, connector{kube: s.Kube(nil)}, cr)
+	if diff := cmp.Diff(azure.ReasonPlannedCreate, got.Reason); diff != "" {
+		t.Errorf("PlanDryRun(...): -want Planned reason, +got:\n%s", diff)
+	}
+}
@@ -18,9 +18,11 @@ package publicipaddress
 
 import (
 	"context"
+	"net/http"
 
 	azurenetwork "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network"
 	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network/networkapi"
+	"github.com/Azure/go-autorest/autorest"
 	"github.com/pkg/errors"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -48,6 +50,7 @@ const (
 	errUpdatePublicIPAddress = "cannot update PublicIPAddress"
 	errGetPublicIPAddress    = "cannot get PublicIPAddress"
 	errDeletePublicIPAddress = "cannot delete PublicIPAddress"
+	errFetchLastOperation    = "cannot fetch last operation"
 )
 
 // Setup adds a controller that reconciles Public Ip Address.
@@ -59,18 +62,19 @@ func Setup(mgr ctrl.Manager, o controller.Options) error {
 		cps = append(cps, connection.NewDetailsManager(mgr.GetClient(), v1alpha1.StoreConfigGroupVersionKind))
 	}
 
+	recorder := event.NewAPIRecorder(mgr.GetEventRecorderFor(name))
+
 	return ctrl.NewControllerManagedBy(mgr).
 		Named(name).
 		WithOptions(o.ForControllerRuntime()).
 		For(&v1alpha3.PublicIPAddress{}).
-		Complete(managed.NewReconciler(mgr,
-			resource.ManagedKind(v1alpha3.PublicIPAddressGroupVersionKind),
+		Complete(azureclients.NewReconciler(mgr, name, v1alpha3.PublicIPAddressGroupVersionKind,
 			managed.WithConnectionPublishers(),
-			managed.WithExternalConnecter(&connecter{client: mgr.GetClient()}),
+			managed.WithExternalConnecter(azureclients.NewConnecter(&connecter{client: mgr.GetClient()}, recorder)),
 			managed.WithReferenceResolver(managed.NewAPISimpleReferenceResolver(mgr.GetClient())),
 			managed.WithPollInterval(o.PollInterval),
 			managed.WithLogger(o.Logger.WithValues("controller", name)),
-			managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
+			managed.WithRecorder(recorder),
 			managed.WithConnectionPublishers(cps...)))
 }
 
@@ -83,14 +87,20 @@ func (c *connecter) Connect(ctx context.Context, mg resource.Managed) (managed.E
 	if err != nil {
 		return nil, err
 	}
-	cl := azurenetwork.NewPublicIPAddressesClient(creds[azureclients.CredentialsKeySubscriptionID])
-	cl.Authorizer = auth
-	return &external{kube: c.client, client: cl}, nil
+	cl := azurenetwork.NewPublicIPAddressesClientWithBaseURI(creds[azureclients.CredentialsKeyResourceManagerEndpointURL], creds[azureclients.CredentialsKeySubscriptionID])
+	azureclients.ConfigureClient(&cl.Client, auth)
+	tags, err := azureclients.DefaultTags(ctx, c.client, mg)
+	if err != nil {
+		return nil, err
+	}
+	return &external{kube: c.client, client: cl, sender: cl.Client, defaultTags: tags}, nil
 }
 
 type external struct {
-	kube   client.Client
-	client networkapi.PublicIPAddressesClientAPI
+	kube        client.Client
+	client      networkapi.PublicIPAddressesClientAPI
+	sender      autorest.Sender
+	defaultTags map[string]string
 }
 
 func (e *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
@@ -100,21 +110,38 @@ func (e *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 	}
 
 	az, err := e.client.Get(ctx, s.Spec.ForProvider.ResourceGroupName, meta.GetExternalName(s), "")
+	if azureclients.IsNotFound(err) {
+		forgotten := azureclients.RestoreCreateOperation(s, &s.Status.AtProvider.LastOperation)
+		if err := azureclients.FetchAsyncOperation(ctx, e.sender, &s.Status.AtProvider.LastOperation); err != nil {
+			return managed.ExternalObservation{}, errors.Wrap(err, errFetchLastOperation)
+		}
+		// A creation that is still in progress must not be started again.
+		return managed.ExternalObservation{ResourceExists: azureclients.IsOperationInProgress(s.Status.AtProvider.LastOperation, http.MethodPut), ResourceLateInitialized: forgotten}, nil
+	}
 	if err != nil {
-		return managed.ExternalObservation{}, errors.Wrap(resource.Ignore(azureclients.IsNotFound, err), errGetPublicIPAddress)
+		return managed.ExternalObservation{}, errors.Wrap(err, errGetPublicIPAddress)
 	}
 
-	network.LateInitializePublicIPAddress(&s.Spec.ForProvider, &az)
+	network.LateInitializePublicIPAddress(&s.Spec.ForProvider, &az, e.defaultTags)
 	if err := e.kube.Update(ctx, s); err != nil {
 		return managed.ExternalObservation{}, errors.Wrap(err, errUpdateCR)
 	}
 
+	op := s.Status.AtProvider.LastOperation
+	forgotten := azureclients.RestoreCreateOperation(s, &op)
 	s.Status.AtProvider = *network.GeneratePublicIPAddressObservation(az)
+	s.Status.AtProvider.LastOperation = op
+	if err := azureclients.FetchAsyncOperation(ctx, e.sender, &s.Status.AtProvider.LastOperation); err != nil {
+		return managed.ExternalObservation{}, errors.Wrap(err, errFetchLastOperation)
+	}
 	s.SetConditions(xpv1.Available())
 
+	diff := network.IsPublicIPAddressUpToDate(s.Spec.ForProvider, az, e.defaultTags)
+	azureclients.ReportDiff(ctx, diff)
 	return managed.ExternalObservation{
-		ResourceExists:   true,
-		ResourceUpToDate: network.IsPublicIPAddressUpToDate(s.Spec.ForProvider, az),
+		ResourceExists:          true,
+		ResourceUpToDate:        diff.UpToDate() && azureclients.OwnershipTagsUpToDate(s, az.Tags),
+		ResourceLateInitialized: forgotten,
 	}, nil
 }
 
@@ -124,12 +151,11 @@ func (e *external) Create(ctx context.Context, mg resource.Managed) (managed.Ext
 		return managed.ExternalCreation{}, errors.New(errNotPublicIPAddress)
 	}
 
-	snet := network.NewPublicIPAddressParameters(s)
-	if _, err := e.client.CreateOrUpdate(ctx, s.Spec.ForProvider.ResourceGroupName, meta.GetExternalName(s), snet); err != nil {
-		return managed.ExternalCreation{}, errors.Wrap(err, errCreatePublicIPAddress)
-	}
-
-	return managed.ExternalCreation{}, nil
+	snet := network.NewPublicIPAddressParameters(s, e.defaultTags)
+	op, err := e.client.CreateOrUpdate(ctx, s.Spec.ForProvider.ResourceGroupName, meta.GetExternalName(s), snet)
+	s.Status.AtProvider.LastOperation = azureclients.NewAsyncOperation(http.MethodPut, op.FutureAPI, err)
+	azureclients.RecordCreateOperation(s, s.Status.AtProvider.LastOperation)
+	return managed.ExternalCreation{}, errors.Wrap(err, errCreatePublicIPAddress)
 }
 
 func (e *external) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
@@ -137,9 +163,13 @@ func (e *external) Update(ctx context.Context, mg resource.Managed) (managed.Ext
 	if !ok {
 		return managed.ExternalUpdate{}, errors.New(errNotPublicIPAddress)
 	}
+	if azureclients.IsOperationInProgress(cr.Status.AtProvider.LastOperation) {
+		return managed.ExternalUpdate{}, nil
+	}
 
-	snet := network.NewPublicIPAddressParameters(cr)
-	_, err := e.client.CreateOrUpdate(ctx, cr.Spec.ForProvider.ResourceGroupName, meta.GetExternalName(cr), snet)
+	snet := network.NewPublicIPAddressParameters(cr, e.defaultTags)
+	op, err := e.client.CreateOrUpdate(ctx, cr.Spec.ForProvider.ResourceGroupName, meta.GetExternalName(cr), snet)
+	cr.Status.AtProvider.LastOperation = azureclients.NewAsyncOperation(http.MethodPut, op.FutureAPI, err)
 	return managed.ExternalUpdate{}, errors.Wrap(err, errUpdatePublicIPAddress)
 }
 
@@ -149,6 +179,8 @@ func (e *external) Delete(ctx context.Context, mg resource.Managed) error {
 		return errors.New(errNotPublicIPAddress)
 	}
 
-	_, err := e.client.Delete(ctx, s.Spec.ForProvider.ResourceGroupName, meta.GetExternalName(s))
-	return errors.Wrap(resource.Ignore(azureclients.IsNotFound, err), errDeletePublicIPAddress)
+	op, err := e.client.Delete(ctx, s.Spec.ForProvider.ResourceGroupName, meta.GetExternalName(s))
+	err = resource.Ignore(azureclients.IsNotFound, err)
+	s.Status.AtProvider.LastOperation = azureclients.NewAsyncOperation(http.MethodDelete, op.FutureAPI, err)
+	return errors.Wrap(err, errDeletePublicIPAddress)
 }
@@ -8,6 +8,7 @@ import (
 	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network"
 	"github.com/Azure/go-autorest/autorest"
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/pkg/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
@@ -19,7 +20,9 @@ import (
 	"github.com/crossplane/crossplane-runtime/pkg/test"
 
 	"github.com/crossplane-contrib/provider-azure/apis/network/v1alpha3"
+	apisv1alpha3 "github.com/crossplane-contrib/provider-azure/apis/v1alpha3"
 	azure "github.com/crossplane-contrib/provider-azure/pkg/clients"
+	"github.com/crossplane-contrib/provider-azure/pkg/clients/armtest"
 	"github.com/crossplane-contrib/provider-azure/pkg/clients/network/fake"
 )
 
@@ -32,6 +35,9 @@ const (
 var (
 	ctx       = context.Background()
 	errorBoom = errors.New("boom")
+
+	ignoreStartTime       = cmpopts.IgnoreFields(apisv1alpha3.AsyncOperation{}, "StartTime")
+	ignoreCreateOperation = cmpopts.IgnoreMapEntries(func(k, _ string) bool { return k == azure.AnnotationKeyCreateOperation })
 )
 
 type testCase struct {
@@ -52,6 +58,10 @@ func withState(s string) publicIPAddressModifier {
 	return func(r *v1alpha3.PublicIPAddress) { r.Status.AtProvider.State = s }
 }
 
+func withLastOperation(op apisv1alpha3.AsyncOperation) publicIPAddressModifier {
+	return func(r *v1alpha3.PublicIPAddress) { r.Status.AtProvider.LastOperation = op }
+}
+
 func publicIPAddress(sm ...publicIPAddressModifier) *v1alpha3.PublicIPAddress {
 	r := &v1alpha3.PublicIPAddress{
 		ObjectMeta: metav1.ObjectMeta{
@@ -95,7 +105,7 @@ func TestCreate(t *testing.T) {
 				},
 			}},
 			r:    publicIPAddress(),
-			want: publicIPAddress(),
+			want: publicIPAddress(withLastOperation(apisv1alpha3.AsyncOperation{Method: http.MethodPut, Status: azure.AsyncOperationStatusSucceeded})),
 		},
 		{
 			name: "FailedCreate",
@@ -105,7 +115,7 @@ func TestCreate(t *testing.T) {
 				},
 			}},
 			r:       publicIPAddress(),
-			want:    publicIPAddress(),
+			want:    publicIPAddress(withLastOperation(apisv1alpha3.AsyncOperation{Method: http.MethodPut, Status: azure.AsyncOperationStatusFailed, ErrorMessage: errorBoom.Error()})),
 			wantErr: errors.Wrap(errorBoom, errCreatePublicIPAddress),
 		},
 	}
@@ -118,7 +128,7 @@ func TestCreate(t *testing.T) {
 				t.Errorf("tc.e.Create(...): want error != got error:\n%s", diff)
 			}
 
-			if diff := cmp.Diff(tc.want, tc.r, test.EquateConditions()); diff != "" {
+			if diff := cmp.Diff(tc.want, tc.r, test.EquateConditions(), ignoreStartTime, ignoreCreateOperation); diff != "" {
 				t.Errorf("r: -want, +got:\n%s", diff)
 			}
 		})
@@ -150,6 +160,16 @@ func TestObserve(t *testing.T) {
 			r:    publicIPAddress(),
 			want: publicIPAddress(),
 		},
+		{
+			name: "CreationInProgress",
+			e: &external{client: &fake.MockPublicIPAddressClient{
+				MockGet: func(_ context.Context, _ string, _ string, _ string) (result network.PublicIPAddress, err error) {
+					return network.PublicIPAddress{}, autorest.DetailedError{StatusCode: http.StatusNotFound}
+				},
+			}},
+			r:    publicIPAddress(withLastOperation(apisv1alpha3.AsyncOperation{Method: http.MethodPut, Status: azure.AsyncOperationStatusInProgress})),
+			want: publicIPAddress(withLastOperation(apisv1alpha3.AsyncOperation{Method: http.MethodPut, Status: azure.AsyncOperationStatusInProgress})),
+		},
 		{
 			name: "SuccessfulObserveExists",
 			e: &external{
@@ -193,7 +213,7 @@ func TestObserve(t *testing.T) {
 				t.Errorf("tc.e.Observe(...): want error != got error:\n%s", diff)
 			}
 
-			if diff := cmp.Diff(tc.want, tc.r, test.EquateConditions()); diff != "" {
+			if diff := cmp.Diff(tc.want, tc.r, test.EquateConditions(), ignoreStartTime); diff != "" {
 				t.Errorf("r: -want, +got:\n%s", diff)
 			}
 		})
@@ -217,7 +237,7 @@ func TestDelete(t *testing.T) {
 				},
 			}},
 			r:    publicIPAddress(),
-			want: publicIPAddress(),
+			want: publicIPAddress(withLastOperation(apisv1alpha3.AsyncOperation{Method: http.MethodDelete, Status: azure.AsyncOperationStatusSucceeded})),
 		},
 		{
 			name: "SuccessfulNotFound",
@@ -229,7 +249,7 @@ func TestDelete(t *testing.T) {
 				},
 			}},
 			r:    publicIPAddress(),
-			want: publicIPAddress(),
+			want: publicIPAddress(withLastOperation(apisv1alpha3.AsyncOperation{Method: http.MethodDelete, Status: azure.AsyncOperationStatusSucceeded})),
 		},
 		{
 			name: "Failed",
@@ -239,7 +259,7 @@ func TestDelete(t *testing.T) {
 				},
 			}},
 			r:       publicIPAddress(),
-			want:    publicIPAddress(),
+			want:    publicIPAddress(withLastOperation(apisv1alpha3.AsyncOperation{Method: http.MethodDelete, Status: azure.AsyncOperationStatusFailed, ErrorMessage: errorBoom.Error()})),
 			wantErr: errors.Wrap(errorBoom, errDeletePublicIPAddress),
 		},
 	}
@@ -252,7 +272,7 @@ func TestDelete(t *testing.T) {
 				t.Errorf("tc.e.Delete(...): want error != got error:\n%s", diff)
 			}
 
-			if diff := cmp.Diff(tc.want, tc.r, test.EquateConditions()); diff != "" {
+			if diff := cmp.Diff(tc.want, tc.r, test.EquateConditions(), ignoreStartTime); diff != "" {
 				t.Errorf("r: -want, +got:\n%s", diff)
 			}
 		})
@@ -268,6 +288,12 @@ func TestUpdate(t *testing.T) {
 			want:    &v1alpha3.Subnet{},
 			wantErr: errors.New(errNotPublicIPAddress),
 		},
+		{
+			name: "OperationInProgress",
+			e:    &external{client: &fake.MockPublicIPAddressClient{}},
+			r:    publicIPAddress(withLastOperation(apisv1alpha3.AsyncOperation{Method: http.MethodPut, Status: azure.AsyncOperationStatusInProgress})),
+			want: publicIPAddress(withLastOperation(apisv1alpha3.AsyncOperation{Method: http.MethodPut, Status: azure.AsyncOperationStatusInProgress})),
+		},
 		{
 			name: "SuccessfulUpdate",
 			e: &external{client: &fake.MockPublicIPAddressClient{
@@ -276,7 +302,7 @@ func TestUpdate(t *testing.T) {
 				},
 			}},
 			r:    publicIPAddress(),
-			want: publicIPAddress(),
+			want: publicIPAddress(withLastOperation(apisv1alpha3.AsyncOperation{Method: http.MethodPut, Status: azure.AsyncOperationStatusSucceeded})),
 		},
 		{
 			name: "FailedUpdate",
@@ -286,7 +312,7 @@ func TestUpdate(t *testing.T) {
 				},
 			}},
 			r:       publicIPAddress(),
-			want:    publicIPAddress(),
+			want:    publicIPAddress(withLastOperation(apisv1alpha3.AsyncOperation{Method: http.MethodPut, Status: azure.AsyncOperationStatusFailed, ErrorMessage: errorBoom.Error()})),
 			wantErr: errors.Wrap(errorBoom, errUpdatePublicIPAddress),
 		},
 	}
@@ -299,9 +325,20 @@ func TestUpdate(t *testing.T) {
 				t.Errorf("tc.e.Create(...): want error != got error:\n%s", diff)
 			}
 
-			if diff := cmp.Diff(tc.want, tc.r, test.EquateConditions()); diff != "" {
+			if diff := cmp.Diff(tc.want, tc.r, test.EquateConditions(), ignoreStartTime); diff != "" {
 				t.Errorf("r: -want, +got:\n%s", diff)
 			}
 		})
 	}
 }
+
+func TestDryRun(t *testing.T) {
+	s := armtest.NewServer()
+	defer s.Close()
+	cr := publicIPAddress()
+
+	got := s.PlanDryRun(t, &connecter{client: s.Kube(nil)}, cr)
+	if diff := cmp.Diff(azure.ReasonPlannedCreate, got.Reason); diff != "" {
+		t.Errorf("PlanDryRun(...): -want Planned reason, +got:\n%s", diff)
+	}
+}
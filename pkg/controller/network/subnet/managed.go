@@ -18,9 +18,11 @@ package subnet
 
 import (
 	"context"
+	"net/http"
 
 	azurenetwork "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network"
 	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network/networkapi"
+	"github.com/Azure/go-autorest/autorest"
 	"github.com/pkg/errors"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -47,6 +49,8 @@ const (
 	errUpdateSubnet = "cannot update Subnet"
 	errGetSubnet    = "cannot get Subnet"
 	errDeleteSubnet = "cannot delete Subnet"
+
+	errFetchLastOperation = "cannot fetch last operation"
 )
 
 // Setup adds a controller that reconciles Subnets.
@@ -58,18 +62,19 @@ func Setup(mgr ctrl.Manager, o controller.Options) error {
 		cps = append(cps, connection.NewDetailsManager(mgr.GetClient(), v1alpha1.StoreConfigGroupVersionKind))
 	}
 
+	recorder := event.NewAPIRecorder(mgr.GetEventRecorderFor(name))
+
 	return ctrl.NewControllerManagedBy(mgr).
 		Named(name).
 		WithOptions(o.ForControllerRuntime()).
 		For(&v1alpha3.Subnet{}).
-		Complete(managed.NewReconciler(mgr,
-			resource.ManagedKind(v1alpha3.SubnetGroupVersionKind),
+		Complete(azureclients.NewReconciler(mgr, name, v1alpha3.SubnetGroupVersionKind,
 			managed.WithConnectionPublishers(),
-			managed.WithExternalConnecter(&connecter{client: mgr.GetClient()}),
+			managed.WithExternalConnecter(azureclients.NewConnecter(&connecter{client: mgr.GetClient()}, recorder)),
 			managed.WithReferenceResolver(managed.NewAPISimpleReferenceResolver(mgr.GetClient())),
 			managed.WithPollInterval(o.PollInterval),
 			managed.WithLogger(o.Logger.WithValues("controller", name)),
-			managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
+			managed.WithRecorder(recorder),
 			managed.WithConnectionPublishers(cps...)))
 }
 
@@ -82,12 +87,15 @@ func (c *connecter) Connect(ctx context.Context, mg resource.Managed) (managed.E
 	if err != nil {
 		return nil, err
 	}
-	cl := azurenetwork.NewSubnetsClient(creds[azureclients.CredentialsKeySubscriptionID])
-	cl.Authorizer = auth
-	return &external{client: cl}, nil
+	cl := azurenetwork.NewSubnetsClientWithBaseURI(creds[azureclients.CredentialsKeyResourceManagerEndpointURL], creds[azureclients.CredentialsKeySubscriptionID])
+	azureclients.ConfigureClient(&cl.Client, auth)
+	return &external{client: cl, sender: cl.Client}, nil
 }
 
-type external struct{ client networkapi.SubnetsClientAPI }
+type external struct {
+	client networkapi.SubnetsClientAPI
+	sender autorest.Sender
+}
 
 func (e *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
 	s, ok := mg.(*v1alpha3.Subnet)
@@ -97,18 +105,31 @@ func (e *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 
 	az, err := e.client.Get(ctx, s.Spec.ResourceGroupName, s.Spec.VirtualNetworkName, meta.GetExternalName(s), "")
 	if azureclients.IsNotFound(err) {
-		return managed.ExternalObservation{ResourceExists: false}, nil
+		forgotten := azureclients.RestoreCreateOperation(s, &s.Status.AtProvider.LastOperation)
+		if err := azureclients.FetchAsyncOperation(ctx, e.sender, &s.Status.AtProvider.LastOperation); err != nil {
+			return managed.ExternalObservation{}, errors.Wrap(err, errFetchLastOperation)
+		}
+		// A creation that is still in progress must not be started again.
+		return managed.ExternalObservation{ResourceExists: azureclients.IsOperationInProgress(s.Status.AtProvider.LastOperation, http.MethodPut), ResourceLateInitialized: forgotten}, nil
 	}
 	if err != nil {
 		return managed.ExternalObservation{}, errors.Wrap(err, errGetSubnet)
 	}
 
 	network.UpdateSubnetStatusFromAzure(s, az)
+	forgotten := azureclients.RestoreCreateOperation(s, &s.Status.AtProvider.LastOperation)
+	if err := azureclients.FetchAsyncOperation(ctx, e.sender, &s.Status.AtProvider.LastOperation); err != nil {
+		return managed.ExternalObservation{}, errors.Wrap(err, errFetchLastOperation)
+	}
 	s.SetConditions(xpv1.Available())
 
+	diff := network.SubnetNeedsUpdate(s, az)
+	azureclients.ReportDiff(ctx, diff)
 	o := managed.ExternalObservation{
-		ResourceExists:    true,
-		ConnectionDetails: managed.ConnectionDetails{},
+		ResourceExists:          true,
+		ResourceUpToDate:        diff.UpToDate(),
+		ResourceLateInitialized: forgotten,
+		ConnectionDetails:       managed.ConnectionDetails{},
 	}
 
 	return o, nil
@@ -123,11 +144,10 @@ func (e *external) Create(ctx context.Context, mg resource.Managed) (managed.Ext
 	s.Status.SetConditions(xpv1.Creating())
 
 	snet := network.NewSubnetParameters(s)
-	if _, err := e.client.CreateOrUpdate(ctx, s.Spec.ResourceGroupName, s.Spec.VirtualNetworkName, meta.GetExternalName(s), snet); err != nil {
-		return managed.ExternalCreation{}, errors.Wrap(err, errCreateSubnet)
-	}
-
-	return managed.ExternalCreation{}, nil
+	op, err := e.client.CreateOrUpdate(ctx, s.Spec.ResourceGroupName, s.Spec.VirtualNetworkName, meta.GetExternalName(s), snet)
+	s.Status.AtProvider.LastOperation = azureclients.NewAsyncOperation(http.MethodPut, op.FutureAPI, err)
+	azureclients.RecordCreateOperation(s, s.Status.AtProvider.LastOperation)
+	return managed.ExternalCreation{}, errors.Wrap(err, errCreateSubnet)
 }
 
 func (e *external) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
@@ -135,15 +155,20 @@ func (e *external) Update(ctx context.Context, mg resource.Managed) (managed.Ext
 	if !ok {
 		return managed.ExternalUpdate{}, errors.New(errNotSubnet)
 	}
+	if azureclients.IsOperationInProgress(s.Status.AtProvider.LastOperation) {
+		return managed.ExternalUpdate{}, nil
+	}
 
 	az, err := e.client.Get(ctx, s.Spec.ResourceGroupName, s.Spec.VirtualNetworkName, meta.GetExternalName(s), "")
 	if err != nil {
 		return managed.ExternalUpdate{}, errors.Wrap(err, errGetSubnet)
 	}
 
-	if network.SubnetNeedsUpdate(s, az) {
+	if !network.SubnetNeedsUpdate(s, az).UpToDate() {
 		snet := network.NewSubnetParameters(s)
-		if _, err := e.client.CreateOrUpdate(ctx, s.Spec.ResourceGroupName, s.Spec.VirtualNetworkName, meta.GetExternalName(s), snet); err != nil {
+		op, err := e.client.CreateOrUpdate(ctx, s.Spec.ResourceGroupName, s.Spec.VirtualNetworkName, meta.GetExternalName(s), snet)
+		s.Status.AtProvider.LastOperation = azureclients.NewAsyncOperation(http.MethodPut, op.FutureAPI, err)
+		if err != nil {
 			return managed.ExternalUpdate{}, errors.Wrap(err, errUpdateSubnet)
 		}
 	}
@@ -158,6 +183,8 @@ func (e *external) Delete(ctx context.Context, mg resource.Managed) error {
 
 	mg.SetConditions(xpv1.Deleting())
 
-	_, err := e.client.Delete(ctx, s.Spec.ResourceGroupName, s.Spec.VirtualNetworkName, meta.GetExternalName(s))
-	return errors.Wrap(resource.Ignore(azureclients.IsNotFound, err), errDeleteSubnet)
+	op, err := e.client.Delete(ctx, s.Spec.ResourceGroupName, s.Spec.VirtualNetworkName, meta.GetExternalName(s))
+	err = resource.Ignore(azureclients.IsNotFound, err)
+	s.Status.AtProvider.LastOperation = azureclients.NewAsyncOperation(http.MethodDelete, op.FutureAPI, err)
+	return errors.Wrap(err, errDeleteSubnet)
 }
@@ -24,6 +24,7 @@ import (
 	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network"
 	"github.com/Azure/go-autorest/autorest"
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/pkg/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
@@ -35,7 +36,9 @@ import (
 	"github.com/crossplane/crossplane-runtime/pkg/test"
 
 	"github.com/crossplane-contrib/provider-azure/apis/network/v1alpha3"
+	apisv1alpha3 "github.com/crossplane-contrib/provider-azure/apis/v1alpha3"
 	azure "github.com/crossplane-contrib/provider-azure/pkg/clients"
+	"github.com/crossplane-contrib/provider-azure/pkg/clients/armtest"
 	"github.com/crossplane-contrib/provider-azure/pkg/clients/network/fake"
 )
 
@@ -50,6 +53,9 @@ const (
 var (
 	ctx       = context.Background()
 	errorBoom = errors.New("boom")
+
+	ignoreStartTime       = cmpopts.IgnoreFields(apisv1alpha3.AsyncOperation{}, "StartTime")
+	ignoreCreateOperation = cmpopts.IgnoreMapEntries(func(k, _ string) bool { return k == azure.AnnotationKeyCreateOperation })
 )
 
 type testCase struct {
@@ -69,6 +75,11 @@ func withConditions(c ...xpv1.Condition) subnetModifier {
 func withState(s string) subnetModifier {
 	return func(r *v1alpha3.Subnet) { r.Status.State = s }
 }
+
+func withLastOperation(op apisv1alpha3.AsyncOperation) subnetModifier {
+	return func(r *v1alpha3.Subnet) { r.Status.AtProvider.LastOperation = op }
+}
+
 func subnet(sm ...subnetModifier) *v1alpha3.Subnet {
 	r := &v1alpha3.Subnet{
 		ObjectMeta: metav1.ObjectMeta{
@@ -118,6 +129,7 @@ func TestCreate(t *testing.T) {
 			r: subnet(),
 			want: subnet(
 				withConditions(xpv1.Creating()),
+				withLastOperation(apisv1alpha3.AsyncOperation{Method: http.MethodPut, Status: azure.AsyncOperationStatusSucceeded}),
 			),
 		},
 		{
@@ -130,6 +142,7 @@ func TestCreate(t *testing.T) {
 			r: subnet(),
 			want: subnet(
 				withConditions(xpv1.Creating()),
+				withLastOperation(apisv1alpha3.AsyncOperation{Method: http.MethodPut, Status: azure.AsyncOperationStatusFailed, ErrorMessage: errorBoom.Error()}),
 			),
 			wantErr: errors.Wrap(errorBoom, errCreateSubnet),
 		},
@@ -143,7 +156,7 @@ func TestCreate(t *testing.T) {
 				t.Errorf("tc.e.Create(...): want error != got error:\n%s", diff)
 			}
 
-			if diff := cmp.Diff(tc.want, tc.r, test.EquateConditions()); diff != "" {
+			if diff := cmp.Diff(tc.want, tc.r, test.EquateConditions(), ignoreStartTime, ignoreCreateOperation); diff != "" {
 				t.Errorf("r: -want, +got:\n%s", diff)
 			}
 		})
@@ -175,6 +188,16 @@ func TestObserve(t *testing.T) {
 			r:    subnet(),
 			want: subnet(),
 		},
+		{
+			name: "CreationInProgress",
+			e: &external{client: &fake.MockSubnetsClient{
+				MockGet: func(_ context.Context, _ string, _ string, _ string, _ string) (result network.Subnet, err error) {
+					return network.Subnet{}, autorest.DetailedError{StatusCode: http.StatusNotFound}
+				},
+			}},
+			r:    subnet(withLastOperation(apisv1alpha3.AsyncOperation{Method: http.MethodPut, Status: azure.AsyncOperationStatusInProgress})),
+			want: subnet(withLastOperation(apisv1alpha3.AsyncOperation{Method: http.MethodPut, Status: azure.AsyncOperationStatusInProgress})),
+		},
 		{
 			name: "SuccessfulObserveExists",
 			e: &external{client: &fake.MockSubnetsClient{
@@ -214,7 +237,7 @@ func TestObserve(t *testing.T) {
 				t.Errorf("tc.e.Observe(...): want error != got error:\n%s", diff)
 			}
 
-			if diff := cmp.Diff(tc.want, tc.r, test.EquateConditions()); diff != "" {
+			if diff := cmp.Diff(tc.want, tc.r, test.EquateConditions(), ignoreStartTime); diff != "" {
 				t.Errorf("r: -want, +got:\n%s", diff)
 			}
 		})
@@ -230,6 +253,12 @@ func TestUpdate(t *testing.T) {
 			want:    &v1alpha3.VirtualNetwork{},
 			wantErr: errors.New(errNotSubnet),
 		},
+		{
+			name: "OperationInProgress",
+			e:    &external{client: &fake.MockSubnetsClient{}},
+			r:    subnet(withLastOperation(apisv1alpha3.AsyncOperation{Method: http.MethodPut, Status: azure.AsyncOperationStatusInProgress})),
+			want: subnet(withLastOperation(apisv1alpha3.AsyncOperation{Method: http.MethodPut, Status: azure.AsyncOperationStatusInProgress})),
+		},
 		{
 			name: "SuccessfulDoesNotNeedUpdate",
 			e: &external{client: &fake.MockSubnetsClient{
@@ -259,7 +288,7 @@ func TestUpdate(t *testing.T) {
 				},
 			}},
 			r:    subnet(),
-			want: subnet(),
+			want: subnet(withLastOperation(apisv1alpha3.AsyncOperation{Method: http.MethodPut, Status: azure.AsyncOperationStatusSucceeded})),
 		},
 		{
 			name: "UnsuccessfulGet",
@@ -291,7 +320,7 @@ func TestUpdate(t *testing.T) {
 				},
 			}},
 			r:       subnet(),
-			want:    subnet(),
+			want:    subnet(withLastOperation(apisv1alpha3.AsyncOperation{Method: http.MethodPut, Status: azure.AsyncOperationStatusFailed, ErrorMessage: errorBoom.Error()})),
 			wantErr: errors.Wrap(errorBoom, errUpdateSubnet),
 		},
 	}
@@ -304,7 +333,7 @@ func TestUpdate(t *testing.T) {
 				t.Errorf("tc.e.Update(...): want error != got error:\n%s", diff)
 			}
 
-			if diff := cmp.Diff(tc.want, tc.r, test.EquateConditions()); diff != "" {
+			if diff := cmp.Diff(tc.want, tc.r, test.EquateConditions(), ignoreStartTime); diff != "" {
 				t.Errorf("r: -want, +got:\n%s", diff)
 			}
 		})
@@ -330,6 +359,7 @@ func TestDelete(t *testing.T) {
 			r: subnet(),
 			want: subnet(
 				withConditions(xpv1.Deleting()),
+				withLastOperation(apisv1alpha3.AsyncOperation{Method: http.MethodDelete, Status: azure.AsyncOperationStatusSucceeded}),
 			),
 		},
 		{
@@ -344,6 +374,7 @@ func TestDelete(t *testing.T) {
 			r: subnet(),
 			want: subnet(
 				withConditions(xpv1.Deleting()),
+				withLastOperation(apisv1alpha3.AsyncOperation{Method: http.MethodDelete, Status: azure.AsyncOperationStatusSucceeded}),
 			),
 		},
 		{
@@ -356,6 +387,7 @@ func TestDelete(t *testing.T) {
 			r: subnet(),
 			want: subnet(
 				withConditions(xpv1.Deleting()),
+				withLastOperation(apisv1alpha3.AsyncOperation{Method: http.MethodDelete, Status: azure.AsyncOperationStatusFailed, ErrorMessage: errorBoom.Error()}),
 			),
 			wantErr: errors.Wrap(errorBoom, errDeleteSubnet),
 		},
@@ -369,9 +401,20 @@ func TestDelete(t *testing.T) {
 				t.Errorf("tc.e.Delete(...): want error != got error:\n%s", diff)
 			}
 
-			if diff := cmp.Diff(tc.want, tc.r, test.EquateConditions()); diff != "" {
+			if diff := cmp.Diff(tc.want, tc.r, test.EquateConditions(), ignoreStartTime); diff != "" {
 				t.Errorf("r: -want, +got:\n%s", diff)
 			}
 		})
 	}
 }
+
+func TestDryRun(t *testing.T) {
+	s := armtest.NewServer()
+	defer s.Close()
+	cr := subnet()
+
+	got := s.PlanDryRun(t, &connecter{client: s.Kube(nil)}, cr)
+	if diff := cmp.Diff(azure.ReasonPlannedCreate, got.Reason); diff != "" {
+		t.Errorf("PlanDryRun(...): -want Planned reason, +got:\n%s", diff)
+	}
+}
@@ -18,9 +18,11 @@ package virtualnetwork
 
 import (
 	"context"
+	"net/http"
 
 	azurenetwork "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network"
 	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network/networkapi"
+	"github.com/Azure/go-autorest/autorest"
 	"github.com/pkg/errors"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -47,6 +49,7 @@ const (
 	errUpdateVirtualNetwork = "cannot update VirtualNetwork"
 	errGetVirtualNetwork    = "cannot get VirtualNetwork"
 	errDeleteVirtualNetwork = "cannot delete VirtualNetwork"
+	errFetchLastOperation   = "cannot fetch last operation"
 )
 
 // Setup adds a controller that reconciles VirtualNetworks.
@@ -58,18 +61,19 @@ func Setup(mgr ctrl.Manager, o controller.Options) error {
 		cps = append(cps, connection.NewDetailsManager(mgr.GetClient(), v1alpha1.StoreConfigGroupVersionKind))
 	}
 
+	recorder := event.NewAPIRecorder(mgr.GetEventRecorderFor(name))
+
 	return ctrl.NewControllerManagedBy(mgr).
 		Named(name).
 		WithOptions(o.ForControllerRuntime()).
 		For(&v1alpha3.VirtualNetwork{}).
-		Complete(managed.NewReconciler(mgr,
-			resource.ManagedKind(v1alpha3.VirtualNetworkGroupVersionKind),
+		Complete(azureclients.NewReconciler(mgr, name, v1alpha3.VirtualNetworkGroupVersionKind,
 			managed.WithConnectionPublishers(),
-			managed.WithExternalConnecter(&connecter{client: mgr.GetClient()}),
+			managed.WithExternalConnecter(azureclients.NewConnecter(&connecter{client: mgr.GetClient()}, recorder)),
 			managed.WithReferenceResolver(managed.NewAPISimpleReferenceResolver(mgr.GetClient())),
 			managed.WithPollInterval(o.PollInterval),
 			managed.WithLogger(o.Logger.WithValues("controller", name)),
-			managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
+			managed.WithRecorder(recorder),
 			managed.WithConnectionPublishers(cps...)))
 }
 
@@ -82,13 +86,19 @@ func (c *connecter) Connect(ctx context.Context, mg resource.Managed) (managed.E
 	if err != nil {
 		return nil, err
 	}
-	cl := azurenetwork.NewVirtualNetworksClient(creds[azureclients.CredentialsKeySubscriptionID])
-	cl.Authorizer = auth
-	return &external{client: cl}, nil
+	cl := azurenetwork.NewVirtualNetworksClientWithBaseURI(creds[azureclients.CredentialsKeyResourceManagerEndpointURL], creds[azureclients.CredentialsKeySubscriptionID])
+	azureclients.ConfigureClient(&cl.Client, auth)
+	tags, err := azureclients.DefaultTags(ctx, c.client, mg)
+	if err != nil {
+		return nil, err
+	}
+	return &external{client: cl, sender: cl.Client, defaultTags: tags}, nil
 }
 
 type external struct {
-	client networkapi.VirtualNetworksClientAPI
+	client      networkapi.VirtualNetworksClientAPI
+	sender      autorest.Sender
+	defaultTags map[string]string
 }
 
 func (e *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
@@ -99,19 +109,32 @@ func (e *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 
 	az, err := e.client.Get(ctx, v.Spec.ResourceGroupName, meta.GetExternalName(v), "")
 	if azureclients.IsNotFound(err) {
-		return managed.ExternalObservation{ResourceExists: false}, nil
+		forgotten := azureclients.RestoreCreateOperation(v, &v.Status.AtProvider.LastOperation)
+		if err := azureclients.FetchAsyncOperation(ctx, e.sender, &v.Status.AtProvider.LastOperation); err != nil {
+			return managed.ExternalObservation{}, errors.Wrap(err, errFetchLastOperation)
+		}
+		// A creation that is still in progress must not be started again.
+		return managed.ExternalObservation{ResourceExists: azureclients.IsOperationInProgress(v.Status.AtProvider.LastOperation, http.MethodPut), ResourceLateInitialized: forgotten}, nil
 	}
 	if err != nil {
 		return managed.ExternalObservation{}, errors.Wrap(err, errGetVirtualNetwork)
 	}
 
 	network.UpdateVirtualNetworkStatusFromAzure(v, az)
+	forgotten := azureclients.RestoreCreateOperation(v, &v.Status.AtProvider.LastOperation)
+	if err := azureclients.FetchAsyncOperation(ctx, e.sender, &v.Status.AtProvider.LastOperation); err != nil {
+		return managed.ExternalObservation{}, errors.Wrap(err, errFetchLastOperation)
+	}
 
 	v.SetConditions(xpv1.Available())
 
+	diff := network.VirtualNetworkNeedsUpdate(v, az, e.defaultTags)
+	azureclients.ReportDiff(ctx, diff)
 	o := managed.ExternalObservation{
-		ResourceExists:    true,
-		ConnectionDetails: managed.ConnectionDetails{},
+		ResourceExists:          true,
+		ResourceUpToDate:        diff.UpToDate() && azureclients.OwnershipTagsUpToDate(v, az.Tags),
+		ResourceLateInitialized: forgotten,
+		ConnectionDetails:       managed.ConnectionDetails{},
 	}
 
 	return o, nil
@@ -125,12 +148,11 @@ func (e *external) Create(ctx context.Context, mg resource.Managed) (managed.Ext
 
 	v.Status.SetConditions(xpv1.Creating())
 
-	vnet := network.NewVirtualNetworkParameters(v)
-	if _, err := e.client.CreateOrUpdate(ctx, v.Spec.ResourceGroupName, meta.GetExternalName(v), vnet); err != nil {
-		return managed.ExternalCreation{}, errors.Wrap(err, errCreateVirtualNetwork)
-	}
-
-	return managed.ExternalCreation{}, nil
+	vnet := network.NewVirtualNetworkParameters(v, e.defaultTags)
+	op, err := e.client.CreateOrUpdate(ctx, v.Spec.ResourceGroupName, meta.GetExternalName(v), vnet)
+	v.Status.AtProvider.LastOperation = azureclients.NewAsyncOperation(http.MethodPut, op.FutureAPI, err)
+	azureclients.RecordCreateOperation(v, v.Status.AtProvider.LastOperation)
+	return managed.ExternalCreation{}, errors.Wrap(err, errCreateVirtualNetwork)
 }
 
 func (e *external) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
@@ -138,15 +160,20 @@ func (e *external) Update(ctx context.Context, mg resource.Managed) (managed.Ext
 	if !ok {
 		return managed.ExternalUpdate{}, errors.New(errNotVirtualNetwork)
 	}
+	if azureclients.IsOperationInProgress(v.Status.AtProvider.LastOperation) {
+		return managed.ExternalUpdate{}, nil
+	}
 
 	az, err := e.client.Get(ctx, v.Spec.ResourceGroupName, meta.GetExternalName(v), "")
 	if err != nil {
 		return managed.ExternalUpdate{}, errors.Wrap(err, errGetVirtualNetwork)
 	}
 
-	if network.VirtualNetworkNeedsUpdate(v, az) {
-		vnet := network.NewVirtualNetworkParameters(v)
-		if _, err := e.client.CreateOrUpdate(ctx, v.Spec.ResourceGroupName, meta.GetExternalName(v), vnet); err != nil {
+	if !network.VirtualNetworkNeedsUpdate(v, az, e.defaultTags).UpToDate() {
+		vnet := network.NewVirtualNetworkParameters(v, e.defaultTags)
+		op, err := e.client.CreateOrUpdate(ctx, v.Spec.ResourceGroupName, meta.GetExternalName(v), vnet)
+		v.Status.AtProvider.LastOperation = azureclients.NewAsyncOperation(http.MethodPut, op.FutureAPI, err)
+		if err != nil {
 			return managed.ExternalUpdate{}, errors.Wrap(err, errUpdateVirtualNetwork)
 		}
 	}
@@ -161,6 +188,8 @@ func (e *external) Delete(ctx context.Context, mg resource.Managed) error {
 
 	mg.SetConditions(xpv1.Deleting())
 
-	_, err := e.client.Delete(ctx, v.Spec.ResourceGroupName, meta.GetExternalName(v))
-	return errors.Wrap(resource.Ignore(azureclients.IsNotFound, err), errDeleteVirtualNetwork)
+	op, err := e.client.Delete(ctx, v.Spec.ResourceGroupName, meta.GetExternalName(v))
+	err = resource.Ignore(azureclients.IsNotFound, err)
+	v.Status.AtProvider.LastOperation = azureclients.NewAsyncOperation(http.MethodDelete, op.FutureAPI, err)
+	return errors.Wrap(err, errDeleteVirtualNetwork)
 }
@@ -24,6 +24,7 @@ import (
 	"github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network"
 	"github.com/Azure/go-autorest/autorest"
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/pkg/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
@@ -35,7 +36,9 @@ import (
 	"github.com/crossplane/crossplane-runtime/pkg/test"
 
 	"github.com/crossplane-contrib/provider-azure/apis/network/v1alpha3"
+	apisv1alpha3 "github.com/crossplane-contrib/provider-azure/apis/v1alpha3"
 	azure "github.com/crossplane-contrib/provider-azure/pkg/clients"
+	"github.com/crossplane-contrib/provider-azure/pkg/clients/armtest"
 	"github.com/crossplane-contrib/provider-azure/pkg/clients/network/fake"
 )
 
@@ -51,6 +54,9 @@ var (
 	ctx       = context.Background()
 	errorBoom = errors.New("boom")
 	tags      = map[string]string{"one": "test", "two": "test"}
+
+	ignoreStartTime       = cmpopts.IgnoreFields(apisv1alpha3.AsyncOperation{}, "StartTime")
+	ignoreCreateOperation = cmpopts.IgnoreMapEntries(func(k, _ string) bool { return k == azure.AnnotationKeyCreateOperation })
 )
 
 type testCase struct {
@@ -71,6 +77,10 @@ func withState(s string) virtualNetworkModifier {
 	return func(r *v1alpha3.VirtualNetwork) { r.Status.State = s }
 }
 
+func withLastOperation(op apisv1alpha3.AsyncOperation) virtualNetworkModifier {
+	return func(r *v1alpha3.VirtualNetwork) { r.Status.AtProvider.LastOperation = op }
+}
+
 func virtualNetwork(vm ...virtualNetworkModifier) *v1alpha3.VirtualNetwork {
 	r := &v1alpha3.VirtualNetwork{
 		ObjectMeta: metav1.ObjectMeta{
@@ -124,6 +134,7 @@ func TestCreate(t *testing.T) {
 			r: virtualNetwork(),
 			want: virtualNetwork(
 				withConditions(xpv1.Creating()),
+				withLastOperation(apisv1alpha3.AsyncOperation{Method: http.MethodPut, Status: azure.AsyncOperationStatusSucceeded}),
 			),
 		},
 		{
@@ -136,6 +147,7 @@ func TestCreate(t *testing.T) {
 			r: virtualNetwork(),
 			want: virtualNetwork(
 				withConditions(xpv1.Creating()),
+				withLastOperation(apisv1alpha3.AsyncOperation{Method: http.MethodPut, Status: azure.AsyncOperationStatusFailed, ErrorMessage: errorBoom.Error()}),
 			),
 			wantErr: errors.Wrap(errorBoom, errCreateVirtualNetwork),
 		},
@@ -149,7 +161,7 @@ func TestCreate(t *testing.T) {
 				t.Errorf("tc.e.Create(...): want error != got error:\n%s", diff)
 			}
 
-			if diff := cmp.Diff(tc.want, tc.r, test.EquateConditions()); diff != "" {
+			if diff := cmp.Diff(tc.want, tc.r, test.EquateConditions(), ignoreStartTime, ignoreCreateOperation); diff != "" {
 				t.Errorf("r: -want, +got:\n%s", diff)
 			}
 		})
@@ -186,6 +198,16 @@ func TestObserve(t *testing.T) {
 			r:    virtualNetwork(),
 			want: virtualNetwork(),
 		},
+		{
+			name: "CreationInProgress",
+			e: &external{client: &fake.MockVirtualNetworksClient{
+				MockGet: func(_ context.Context, _ string, _ string, _ string) (result network.VirtualNetwork, err error) {
+					return network.VirtualNetwork{}, autorest.DetailedError{StatusCode: http.StatusNotFound}
+				},
+			}},
+			r:    virtualNetwork(withLastOperation(apisv1alpha3.AsyncOperation{Method: http.MethodPut, Status: azure.AsyncOperationStatusInProgress})),
+			want: virtualNetwork(withLastOperation(apisv1alpha3.AsyncOperation{Method: http.MethodPut, Status: azure.AsyncOperationStatusInProgress})),
+		},
 		{
 			name: "SuccessfulObserveExists",
 			e: &external{client: &fake.MockVirtualNetworksClient{
@@ -230,7 +252,7 @@ func TestObserve(t *testing.T) {
 				t.Errorf("tc.e.Observe(...): want error != got error:\n%s", diff)
 			}
 
-			if diff := cmp.Diff(tc.want, tc.r, test.EquateConditions()); diff != "" {
+			if diff := cmp.Diff(tc.want, tc.r, test.EquateConditions(), ignoreStartTime); diff != "" {
 				t.Errorf("r: -want, +got:\n%s", diff)
 			}
 		})
@@ -246,6 +268,12 @@ func TestUpdate(t *testing.T) {
 			want:    &v1alpha3.Subnet{},
 			wantErr: errors.New(errNotVirtualNetwork),
 		},
+		{
+			name: "OperationInProgress",
+			e:    &external{client: &fake.MockVirtualNetworksClient{}},
+			r:    virtualNetwork(withLastOperation(apisv1alpha3.AsyncOperation{Method: http.MethodPut, Status: azure.AsyncOperationStatusInProgress})),
+			want: virtualNetwork(withLastOperation(apisv1alpha3.AsyncOperation{Method: http.MethodPut, Status: azure.AsyncOperationStatusInProgress})),
+		},
 		{
 			name: "SuccessfulDoesNotNeedUpdate",
 			e: &external{client: &fake.MockVirtualNetworksClient{
@@ -285,7 +313,7 @@ func TestUpdate(t *testing.T) {
 				},
 			}},
 			r:    virtualNetwork(),
-			want: virtualNetwork(),
+			want: virtualNetwork(withLastOperation(apisv1alpha3.AsyncOperation{Method: http.MethodPut, Status: azure.AsyncOperationStatusSucceeded})),
 		},
 		{
 			name: "UnsuccessfulGet",
@@ -327,7 +355,7 @@ func TestUpdate(t *testing.T) {
 				},
 			}},
 			r:       virtualNetwork(),
-			want:    virtualNetwork(),
+			want:    virtualNetwork(withLastOperation(apisv1alpha3.AsyncOperation{Method: http.MethodPut, Status: azure.AsyncOperationStatusFailed, ErrorMessage: errorBoom.Error()})),
 			wantErr: errors.Wrap(errorBoom, errUpdateVirtualNetwork),
 		},
 	}
@@ -340,7 +368,7 @@ func TestUpdate(t *testing.T) {
 				t.Errorf("tc.e.Update(...): want error != got error:\n%s", diff)
 			}
 
-			if diff := cmp.Diff(tc.want, tc.r, test.EquateConditions()); diff != "" {
+			if diff := cmp.Diff(tc.want, tc.r, test.EquateConditions(), ignoreStartTime); diff != "" {
 				t.Errorf("r: -want, +got:\n%s", diff)
 			}
 		})
@@ -366,6 +394,7 @@ func TestDelete(t *testing.T) {
 			r: virtualNetwork(),
 			want: virtualNetwork(
 				withConditions(xpv1.Deleting()),
+				withLastOperation(apisv1alpha3.AsyncOperation{Method: http.MethodDelete, Status: azure.AsyncOperationStatusSucceeded}),
 			),
 		},
 		{
@@ -380,6 +409,7 @@ func TestDelete(t *testing.T) {
 			r: virtualNetwork(),
 			want: virtualNetwork(
 				withConditions(xpv1.Deleting()),
+				withLastOperation(apisv1alpha3.AsyncOperation{Method: http.MethodDelete, Status: azure.AsyncOperationStatusSucceeded}),
 			),
 		},
 		{
@@ -392,6 +422,7 @@ func TestDelete(t *testing.T) {
 			r: virtualNetwork(),
 			want: virtualNetwork(
 				withConditions(xpv1.Deleting()),
+				withLastOperation(apisv1alpha3.AsyncOperation{Method: http.MethodDelete, Status: azure.AsyncOperationStatusFailed, ErrorMessage: errorBoom.Error()}),
 			),
 			wantErr: errors.Wrap(errorBoom, errDeleteVirtualNetwork),
 		},
@@ -405,9 +436,20 @@ func TestDelete(t *testing.T) {
 				t.Errorf("tc.e.Delete(...): want error != got error:\n%s", diff)
 			}
 
-			if diff := cmp.Diff(tc.want, tc.r, test.EquateConditions()); diff != "" {
+			if diff := cmp.Diff(tc.want, tc.r, test.EquateConditions(), ignoreStartTime); diff != "" {
 				t.Errorf("r: -want, +got:\n%s", diff)
 			}
 		})
 	}
 }
+
+func TestDryRun(t *testing.T) {
+	s := armtest.NewServer()
+	defer s.Close()
+	cr := virtualNetwork()
+
+	got := s.PlanDryRun(t, &connecter{client: s.Kube(nil)}, cr)
+	if diff := cmp.Diff(azure.ReasonPlannedCreate, got.Reason); diff != "" {
+		t.Errorf("PlanDryRun(...): -want Planned reason, +got:\n%s", diff)
+	}
+}
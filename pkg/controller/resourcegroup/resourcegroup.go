@@ -21,6 +21,7 @@ import (
 	"net/http"
 
 	"github.com/Azure/azure-sdk-for-go/services/resources/mgmt/2018-05-01/resources"
+	"github.com/Azure/go-autorest/autorest"
 	"github.com/Azure/go-autorest/autorest/to"
 	"github.com/pkg/errors"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -47,7 +48,9 @@ const (
 	errCreateResourceGroup = "cannot create ResourceGroup"
 	errCheckResourceGroup  = "cannot check existence of ResourceGroup"
 	errGetResourceGroup    = "cannot get ResourceGroup"
+	errUpdateResourceGroup = "cannot update ResourceGroup"
 	errDeleteResourceGroup = "cannot delete ResourceGroup"
+	errFetchLastOperation  = "cannot fetch last operation"
 )
 
 // Setup adds a controller that reconciles ResourceGroups.
@@ -59,17 +62,18 @@ func Setup(mgr ctrl.Manager, o controller.Options) error {
 		cps = append(cps, connection.NewDetailsManager(mgr.GetClient(), v1alpha1.StoreConfigGroupVersionKind))
 	}
 
+	recorder := event.NewAPIRecorder(mgr.GetEventRecorderFor(name))
+
 	return ctrl.NewControllerManagedBy(mgr).
 		Named(name).
 		WithOptions(o.ForControllerRuntime()).
 		For(&v1alpha3.ResourceGroup{}).
-		Complete(managed.NewReconciler(mgr,
-			resource.ManagedKind(v1alpha3.ResourceGroupGroupVersionKind),
+		Complete(azure.NewReconciler(mgr, name, v1alpha3.ResourceGroupGroupVersionKind,
 			managed.WithConnectionPublishers(),
-			managed.WithExternalConnecter(&connecter{kube: mgr.GetClient()}),
+			managed.WithExternalConnecter(azure.NewConnecter(&connecter{kube: mgr.GetClient()}, recorder)),
 			managed.WithPollInterval(o.PollInterval),
 			managed.WithLogger(o.Logger.WithValues("controller", name)),
-			managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
+			managed.WithRecorder(recorder),
 			managed.WithConnectionPublishers(cps...)))
 }
 
@@ -82,14 +86,20 @@ func (c *connecter) Connect(ctx context.Context, mg resource.Managed) (managed.E
 	if err != nil {
 		return nil, err
 	}
-	cl := resources.NewGroupsClient(creds[azure.CredentialsKeySubscriptionID])
-	cl.Authorizer = auth
-	return &external{client: cl}, nil
+	cl := resources.NewGroupsClientWithBaseURI(creds[azure.CredentialsKeyResourceManagerEndpointURL], creds[azure.CredentialsKeySubscriptionID])
+	azure.ConfigureClient(&cl.Client, auth)
+	tags, err := azure.DefaultTags(ctx, c.kube, mg)
+	if err != nil {
+		return nil, err
+	}
+	return &external{client: cl, sender: cl.Client, defaultTags: tags}, nil
 }
 
 // external is a createsyncdeleter using the Azure Groups API.
 type external struct {
-	client resourcegroup.GroupsClient
+	client      resourcegroup.GroupsClient
+	sender      autorest.Sender
+	defaultTags map[string]string
 }
 
 func (e *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
@@ -103,6 +113,11 @@ func (e *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 		return managed.ExternalObservation{}, errors.Wrap(err, errCheckResourceGroup)
 	}
 
+	forgotten := azure.RestoreCreateOperation(r, &r.Status.AtProvider.LastOperation)
+	if err := azure.FetchAsyncOperation(ctx, e.sender, &r.Status.AtProvider.LastOperation); err != nil {
+		return managed.ExternalObservation{}, errors.Wrap(err, errFetchLastOperation)
+	}
+
 	if res.Response.StatusCode == http.StatusNotFound {
 		return managed.ExternalObservation{ResourceExists: false}, nil
 	}
@@ -116,7 +131,9 @@ func (e *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 	}
 
 	r.SetConditions(xpv1.Available())
-	return managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true}, nil
+	diff := resourcegroup.IsUpToDate(r, g, e.defaultTags)
+	azure.ReportDiff(ctx, diff)
+	return managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: diff.UpToDate() && azure.OwnershipTagsUpToDate(r, g.Tags), ResourceLateInitialized: forgotten}, nil
 }
 
 func (e *external) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
@@ -126,13 +143,22 @@ func (e *external) Create(ctx context.Context, mg resource.Managed) (managed.Ext
 	}
 
 	r.Status.SetConditions(xpv1.Creating())
-	_, err := e.client.CreateOrUpdate(ctx, meta.GetExternalName(r), resourcegroup.NewParameters(r))
+	// Resource groups are created synchronously.
+	_, err := e.client.CreateOrUpdate(ctx, meta.GetExternalName(r), resourcegroup.NewParameters(r, e.defaultTags))
+	r.Status.AtProvider.LastOperation = azure.NewAsyncOperation(http.MethodPut, nil, err)
+	azure.RecordCreateOperation(r, r.Status.AtProvider.LastOperation)
 	return managed.ExternalCreation{}, errors.Wrap(err, errCreateResourceGroup)
 }
 
-func (e *external) Update(_ context.Context, _ resource.Managed) (managed.ExternalUpdate, error) {
-	// TODO(negz): Support updates, if applicable.
-	return managed.ExternalUpdate{}, nil
+func (e *external) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
+	r, ok := mg.(*v1alpha3.ResourceGroup)
+	if !ok {
+		return managed.ExternalUpdate{}, errors.New(errNotResourceGroup)
+	}
+
+	// Only the tags of a resource group can be updated.
+	_, err := e.client.Update(ctx, meta.GetExternalName(r), resourcegroup.NewPatchParameters(r, e.defaultTags))
+	return managed.ExternalUpdate{}, errors.Wrap(err, errUpdateResourceGroup)
 }
 
 func (e *external) Delete(ctx context.Context, mg resource.Managed) error {
@@ -149,6 +175,7 @@ func (e *external) Delete(ctx context.Context, mg resource.Managed) error {
 	}
 
 	r.Status.SetConditions(xpv1.Deleting())
-	_, err := e.client.Delete(ctx, meta.GetExternalName(r))
+	op, err := e.client.Delete(ctx, meta.GetExternalName(r))
+	r.Status.AtProvider.LastOperation = azure.NewAsyncOperation(http.MethodDelete, op.FutureAPI, err)
 	return errors.Wrap(err, errDeleteResourceGroup)
 }
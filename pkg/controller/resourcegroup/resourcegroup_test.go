@@ -25,17 +25,21 @@ import (
 	"github.com/Azure/go-autorest/autorest"
 	"github.com/Azure/go-autorest/autorest/to"
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/pkg/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
 	"github.com/crossplane/crossplane-runtime/pkg/meta"
 	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
 	"github.com/crossplane/crossplane-runtime/pkg/test"
 
 	"github.com/crossplane-contrib/provider-azure/apis/v1alpha3"
+	azure "github.com/crossplane-contrib/provider-azure/pkg/clients"
+	"github.com/crossplane-contrib/provider-azure/pkg/clients/armtest"
 	fakerg "github.com/crossplane-contrib/provider-azure/pkg/clients/resourcegroup/fake"
 )
 
@@ -45,6 +49,9 @@ const (
 	location = "coolplace"
 )
 
+var ignoreStartTime = cmpopts.IgnoreFields(v1alpha3.AsyncOperation{}, "StartTime")
+var ignoreCreateOperation = cmpopts.IgnoreMapEntries(func(k, _ string) bool { return k == azure.AnnotationKeyCreateOperation })
+
 type resourceGroupModifier func(*v1alpha3.ResourceGroup)
 
 func withConditions(c ...xpv1.Condition) resourceGroupModifier {
@@ -55,6 +62,10 @@ func withProvisioningstate(s v1alpha3.ProvisioningState) resourceGroupModifier {
 	return func(r *v1alpha3.ResourceGroup) { r.Status.ProvisioningState = s }
 }
 
+func withLastOperation(op v1alpha3.AsyncOperation) resourceGroupModifier {
+	return func(r *v1alpha3.ResourceGroup) { r.Status.AtProvider.LastOperation = op }
+}
+
 func resourceGrp(rm ...resourceGroupModifier) *v1alpha3.ResourceGroup {
 	r := &v1alpha3.ResourceGroup{
 		ObjectMeta: metav1.ObjectMeta{
@@ -164,9 +175,12 @@ func TestObserve(t *testing.T) {
 						return autorest.Response{Response: &http.Response{StatusCode: http.StatusOK}}, nil
 					},
 					MockGet: func(_ context.Context, _ string) (result resources.Group, err error) {
-						return resources.Group{Properties: &resources.GroupProperties{
-							ProvisioningState: to.StringPtr(string(v1alpha3.ProvisioningStateSucceeded)),
-						}}, nil
+						return resources.Group{
+							Tags: azure.NewTags(resourceGrp(), nil, nil),
+							Properties: &resources.GroupProperties{
+								ProvisioningState: to.StringPtr(string(v1alpha3.ProvisioningStateSucceeded)),
+							},
+						}, nil
 					},
 				},
 			},
@@ -246,10 +260,31 @@ func TestCreate(t *testing.T) {
 				mg: resourceGrp(),
 			},
 			want: want{
-				mg:  resourceGrp(withConditions(xpv1.Creating())),
+				mg: resourceGrp(
+					withConditions(xpv1.Creating()),
+					withLastOperation(v1alpha3.AsyncOperation{Method: http.MethodPut, Status: azure.AsyncOperationStatusFailed, ErrorMessage: errBoom.Error()}),
+				),
 				err: errors.Wrap(errBoom, errCreateResourceGroup),
 			},
 		},
+		"Success": {
+			e: &external{
+				client: &fakerg.MockClient{
+					MockCreateOrUpdate: func(_ context.Context, _ string, _ resources.Group) (result resources.Group, err error) {
+						return resources.Group{}, nil
+					},
+				},
+			},
+			args: args{
+				mg: resourceGrp(),
+			},
+			want: want{
+				mg: resourceGrp(
+					withConditions(xpv1.Creating()),
+					withLastOperation(v1alpha3.AsyncOperation{Method: http.MethodPut, Status: azure.AsyncOperationStatusSucceeded}),
+				),
+			},
+		},
 	}
 
 	for name, tc := range cases {
@@ -260,7 +295,7 @@ func TestCreate(t *testing.T) {
 				t.Errorf("tc.e.Create(...): want error != got error:\n%s", diff)
 			}
 
-			if diff := cmp.Diff(tc.want.mg, tc.args.mg); diff != "" {
+			if diff := cmp.Diff(tc.want.mg, tc.args.mg, ignoreStartTime, ignoreCreateOperation); diff != "" {
 				t.Errorf("tc.e.Create(...): -want managed, +got managed:\n%s", diff)
 			}
 
@@ -325,7 +360,10 @@ func TestDelete(t *testing.T) {
 				mg: resourceGrp(),
 			},
 			want: want{
-				mg:  resourceGrp(withConditions(xpv1.Deleting())),
+				mg: resourceGrp(
+					withConditions(xpv1.Deleting()),
+					withLastOperation(v1alpha3.AsyncOperation{Method: http.MethodDelete, Status: azure.AsyncOperationStatusFailed, ErrorMessage: errBoom.Error()}),
+				),
 				err: errors.Wrap(errBoom, errDeleteResourceGroup),
 			},
 		},
@@ -339,9 +377,118 @@ func TestDelete(t *testing.T) {
 				t.Errorf("tc.e.Delete(...): -want error, +got error:\n%s", diff)
 			}
 
-			if diff := cmp.Diff(tc.want.mg, tc.args.mg, test.EquateErrors()); diff != "" {
+			if diff := cmp.Diff(tc.want.mg, tc.args.mg, test.EquateErrors(), ignoreStartTime); diff != "" {
 				t.Errorf("tc.e.Delete(...): -want, +got:\n%s", diff)
 			}
 		})
 	}
 }
+
+func TestEndToEnd(t *testing.T) {
+	s := armtest.NewServer(armtest.WithPolls(0))
+	defer s.Close()
+	ctx := context.Background()
+	cr := resourceGrp()
+	cr.SetProviderConfigReference(&xpv1.Reference{Name: armtest.ProviderConfigName})
+
+	e, err := (&connecter{kube: s.Kube(nil)}).Connect(ctx, cr)
+	if err != nil {
+		t.Fatalf("Connect(...): %s", err)
+	}
+	o, err := e.Observe(ctx, cr)
+	if err != nil || o.ResourceExists {
+		t.Fatalf("Observe(...): want resource not to exist, got %+v, %v", o, err)
+	}
+	if _, err := e.Create(ctx, cr); err != nil {
+		t.Fatalf("Create(...): %s", err)
+	}
+	o, err = e.Observe(ctx, cr)
+	if err != nil || !o.ResourceExists {
+		t.Fatalf("Observe(...): want resource to exist, got %+v, %v", o, err)
+	}
+	if diff := cmp.Diff(v1alpha3.ProvisioningState(armtest.ProvisioningStateSucceeded), cr.Status.ProvisioningState); diff != "" {
+		t.Errorf("Observe(...): -want provisioning state, +got:\n%s", diff)
+	}
+	if err := e.Delete(ctx, cr); err != nil {
+		t.Fatalf("Delete(...): %s", err)
+	}
+	o, err = e.Observe(ctx, cr)
+	if err != nil || o.ResourceExists {
+		t.Fatalf("Observe(...): want resource not to exist after deletion, got %+v, %v", o, err)
+	}
+}
+
+func TestEndToEndDryRun(t *testing.T) {
+	s := armtest.NewServer(armtest.WithPolls(0))
+	defer s.Close()
+	ctx := context.Background()
+	cr := resourceGrp()
+	armtest.DryRun(cr)
+
+	c := azure.NewConnecter(&connecter{kube: s.Kube(nil)}, event.NewNopRecorder())
+	e, err := c.Connect(ctx, cr)
+	if err != nil {
+		t.Fatalf("Connect(...): %s", err)
+	}
+	o, err := e.Observe(ctx, cr)
+	if err != nil || !o.ResourceExists || !o.ResourceUpToDate {
+		t.Fatalf("Observe(...): want the planned resource to be reported as up to date, got %+v, %v", o, err)
+	}
+	if diff := cmp.Diff(azure.ReasonPlannedCreate, cr.GetCondition(azure.TypePlanned).Reason); diff != "" {
+		t.Errorf("Observe(...): -want Planned reason, +got:\n%s", diff)
+	}
+	if diff := cmp.Diff([]armtest.Request(nil), s.Changes()); diff != "" {
+		t.Errorf("Observe(...): -want no requests that change resources, +got:\n%s", diff)
+	}
+	if _, ok := s.Resource("/subscriptions/" + armtest.DefaultSubscriptionID + "/resourceGroups/" + name); ok {
+		t.Errorf("Observe(...): want the planned resource group not to exist")
+	}
+}
+
+func TestEndToEndDefaultTags(t *testing.T) {
+	s := armtest.NewServer(armtest.WithPolls(0))
+	defer s.Close()
+	ctx := context.Background()
+	id := "/subscriptions/" + armtest.DefaultSubscriptionID + "/resourceGroups/" + name
+	cr := resourceGrp()
+	cr.SetProviderConfigReference(&xpv1.Reference{Name: armtest.ProviderConfigName})
+	cr.Spec.Tags = map[string]string{"env": "prod"}
+
+	e, err := (&connecter{kube: s.Kube(map[string]string{"cost-center": "42", "env": "test"})}).Connect(ctx, cr)
+	if err != nil {
+		t.Fatalf("Connect(...): %s", err)
+	}
+	if _, err := e.Create(ctx, cr); err != nil {
+		t.Fatalf("Create(...): %s", err)
+	}
+	res, _ := s.Resource(id)
+	want := map[string]interface{}{
+		"cost-center":              "42",
+		"env":                      "prod",
+		azure.TagKeyKind:           "ResourceGroup",
+		azure.TagKeyName:           name,
+		azure.TagKeyProviderConfig: "armtest",
+	}
+	if diff := cmp.Diff(want, res["tags"]); diff != "" {
+		t.Errorf("Create(...): -want tags, +got:\n%s", diff)
+	}
+	o, err := e.Observe(ctx, cr)
+	if err != nil || !o.ResourceUpToDate {
+		t.Fatalf("Observe(...): want the ownership tags to be ignored, got %+v, %v", o, err)
+	}
+
+	// The cost-center default tag is removed outside Kubernetes.
+	res["tags"] = map[string]interface{}{"env": "prod"}
+	s.SetResource(id, res)
+	o, err = e.Observe(ctx, cr)
+	if err != nil || o.ResourceUpToDate {
+		t.Fatalf("Observe(...): want a missing default tag to be reported, got %+v, %v", o, err)
+	}
+	if _, err := e.Update(ctx, cr); err != nil {
+		t.Fatalf("Update(...): %s", err)
+	}
+	o, err = e.Observe(ctx, cr)
+	if err != nil || !o.ResourceUpToDate {
+		t.Fatalf("Observe(...): want the default tag to be restored, got %+v, %v", o, err)
+	}
+}
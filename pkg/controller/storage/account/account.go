@@ -18,355 +18,215 @@ package account
 
 import (
 	"context"
-	"reflect"
-	"time"
+	"net/http"
 
 	"github.com/Azure/azure-sdk-for-go/services/storage/mgmt/2017-06-01/storage"
-	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/google/go-cmp/cmp"
 	"github.com/pkg/errors"
-	corev1 "k8s.io/api/core/v1"
-	kerrors "k8s.io/apimachinery/pkg/api/errors"
-	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
-	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/connection"
 	"github.com/crossplane/crossplane-runtime/pkg/controller"
-	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
 	"github.com/crossplane/crossplane-runtime/pkg/meta"
 	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
 
-	"github.com/crossplane-contrib/provider-azure/apis/storage/v1alpha3"
+	"github.com/crossplane-contrib/provider-azure/apis/storage/v1beta1"
+	"github.com/crossplane-contrib/provider-azure/apis/v1alpha1"
 	azure "github.com/crossplane-contrib/provider-azure/pkg/clients"
 	azurestorage "github.com/crossplane-contrib/provider-azure/pkg/clients/storage"
+	"github.com/crossplane-contrib/provider-azure/pkg/features"
 )
 
-const (
-	controllerName = "account.storage.azure.crossplane.io"
-	finalizer      = "finalizer." + controllerName
-
-	reconcileTimeout   = 2 * time.Minute
-	requeueAfterOnWait = 30 * time.Second
-)
+// The finalizer that this controller added before it used the managed
+// reconciler.
+const legacyFinalizer = "finalizer.account.storage.azure.crossplane.io"
 
-var (
-	resultRequeue = reconcile.Result{Requeue: true}
-	requeueOnWait = reconcile.Result{RequeueAfter: requeueAfterOnWait}
+// Error strings.
+const (
+	errNotAccount         = "managed resource is not an Account"
+	errConnectFailed      = "cannot connect to Azure API"
+	errGetFailed          = "cannot get storage account"
+	errListKeysFailed     = "cannot list storage account keys"
+	errNoKeys             = "storage account has no keys"
+	errCreateFailed       = "cannot create storage account"
+	errUpdateFailed       = "cannot update storage account"
+	errDeleteFailed       = "cannot delete storage account"
+	errDefaultTagsFail    = "cannot get default tags"
+	errFetchLastOperation = "cannot fetch last operation"
 )
 
-// Reconciler reconciles an Azure storage account
-type Reconciler struct {
-	client.Client
-	syncdeleterMaker
-	managed.ReferenceResolver
-	managed.Initializer
-
-	poll time.Duration
-
-	log logging.Logger
-}
-
 // Setup adds a controller that reconciles Accounts.
 func Setup(mgr ctrl.Manager, o controller.Options) error {
-	name := managed.ControllerName(v1alpha3.AccountGroupKind)
+	name := managed.ControllerName(v1beta1.AccountGroupKind)
 
-	// NOTE(turkenh): We cannot add support for external secret stores to this
-	// resource since it does not use Crossplane Runtime Managed Reconciler.
-	r := &Reconciler{
-		Client:           mgr.GetClient(),
-		syncdeleterMaker: &accountSyncdeleterMaker{mgr.GetClient()},
-		Initializer:      managed.NewNameAsExternalName(mgr.GetClient()),
-		poll:             o.PollInterval,
-		log:              o.Logger.WithValues("controller", name),
+	cps := []managed.ConnectionPublisher{managed.NewAPISecretPublisher(mgr.GetClient(), mgr.GetScheme())}
+	if o.Features.Enabled(features.EnableAlphaExternalSecretStores) {
+		cps = append(cps, connection.NewDetailsManager(mgr.GetClient(), v1alpha1.StoreConfigGroupVersionKind))
 	}
 
+	recorder := event.NewAPIRecorder(mgr.GetEventRecorderFor(name))
+
 	return ctrl.NewControllerManagedBy(mgr).
 		Named(name).
 		WithOptions(o.ForControllerRuntime()).
-		For(&v1alpha3.Account{}).
-		Owns(&corev1.Secret{}).
-		Complete(r)
+		For(&v1beta1.Account{}).
+		Complete(azure.NewReconciler(mgr, name, v1beta1.AccountGroupVersionKind,
+			managed.WithFinalizer(azure.NewMigratingFinalizer(mgr.GetClient(), legacyFinalizer)),
+			managed.WithExternalConnecter(azure.NewConnecter(&connector{kube: mgr.GetClient()}, recorder)),
+			managed.WithReferenceResolver(managed.NewAPISimpleReferenceResolver(mgr.GetClient())),
+			managed.WithPollInterval(o.PollInterval),
+			managed.WithLogger(o.Logger.WithValues("controller", name)),
+			managed.WithRecorder(recorder),
+			managed.WithConnectionPublishers(cps...)))
+}
+
+type connector struct {
+	kube client.Client
 }
 
-// Reconcile reads that state of the cluster for a Provider acct and makes changes based on the state read
-// and what is in the Provider.Spec
-func (r *Reconciler) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
-	r.log.Debug("Reconciling", "request", request)
-
-	ctx, cancel := context.WithTimeout(ctx, reconcileTimeout)
-	defer cancel()
-
-	b := &v1alpha3.Account{}
-	if err := r.Get(ctx, request.NamespacedName, b); err != nil {
-		if kerrors.IsNotFound(err) {
-			return reconcile.Result{}, nil
-		}
-		return reconcile.Result{}, err
-	}
-	if err := r.Initialize(ctx, b); err != nil {
-		return reconcile.Result{}, err
+func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
+	cr, ok := mg.(*v1beta1.Account)
+	if !ok {
+		return nil, errors.New(errNotAccount)
 	}
-
-	bh, err := r.newSyncdeleter(ctx, b, r.poll)
+	creds, auth, err := azure.GetAuthInfo(ctx, c.kube, cr)
 	if err != nil {
-		b.Status.SetConditions(xpv1.ReconcileError(err))
-		return resultRequeue, r.Status().Update(ctx, b)
+		return nil, errors.Wrap(err, errConnectFailed)
 	}
-
-	// Check for deletion
-	if b.DeletionTimestamp != nil {
-		return bh.delete(ctx)
-	}
-
-	return bh.sync(ctx)
-}
-
-type syncdeleterMaker interface {
-	newSyncdeleter(context.Context, *v1alpha3.Account, time.Duration) (syncdeleter, error)
-}
-
-type accountSyncdeleterMaker struct {
-	client.Client
-}
-
-func (m *accountSyncdeleterMaker) newSyncdeleter(ctx context.Context, b *v1alpha3.Account, poll time.Duration) (syncdeleter, error) {
-	creds, auth, err := azure.GetAuthInfo(ctx, m.Client, b)
+	cl := storage.NewAccountsClientWithBaseURI(creds[azure.CredentialsKeyResourceManagerEndpointURL], creds[azure.CredentialsKeySubscriptionID])
+	azure.ConfigureClient(&cl.Client, auth)
+	tags, err := azure.DefaultTags(ctx, c.kube, cr)
 	if err != nil {
-		return nil, errors.Wrap(err, "cannot get auth information")
+		return nil, errors.Wrap(err, errDefaultTagsFail)
 	}
-
-	cl := storage.NewAccountsClient(creds[azure.CredentialsKeySubscriptionID])
-	cl.Authorizer = auth
-
-	return newAccountSyncDeleter(
-		azurestorage.NewAccountHandle(&cl, b.Spec.ResourceGroupName, meta.GetExternalName(b)),
-		m.Client, b, poll), nil
-}
-
-type deleter interface {
-	delete(context.Context) (reconcile.Result, error)
-}
-
-type syncer interface {
-	sync(context.Context) (reconcile.Result, error)
-}
-
-type creator interface {
-	create(context.Context) (reconcile.Result, error)
-}
-
-type updater interface {
-	update(context.Context, *storage.Account) (reconcile.Result, error)
-}
-
-type syncbacker interface {
-	syncback(context.Context, *storage.Account) (reconcile.Result, error)
+	return &external{
+		client:      azurestorage.NewAccountHandle(&cl, cr.Spec.ForProvider.ResourceGroupName, meta.GetExternalName(cr)),
+		sender:      cl.Client,
+		defaultTags: tags,
+	}, nil
 }
 
-type secretupdater interface {
-	updatesecret(ctx context.Context, acct *storage.Account) error
+type external struct {
+	client      azurestorage.AccountOperations
+	sender      autorest.Sender
+	defaultTags map[string]string
 }
 
-type syncdeleter interface {
-	deleter
-	syncer
-}
-
-type accountSyncDeleter struct {
-	createupdater
-	azurestorage.AccountOperations
-	kube client.Client
-	acct *v1alpha3.Account
-}
-
-func newAccountSyncDeleter(ao azurestorage.AccountOperations, kube client.Client, b *v1alpha3.Account, poll time.Duration) *accountSyncDeleter {
-	return &accountSyncDeleter{
-		createupdater:     newAccountCreateUpdater(ao, kube, b, poll),
-		AccountOperations: ao,
-		kube:              kube,
-		acct:              b,
+func (e *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	cr, ok := mg.(*v1beta1.Account)
+	if !ok {
+		return managed.ExternalObservation{}, errors.New(errNotAccount)
 	}
-}
-
-func (asd *accountSyncDeleter) delete(ctx context.Context) (reconcile.Result, error) {
-	asd.acct.Status.SetConditions(xpv1.Deleting())
-	switch asd.acct.Spec.DeletionPolicy {
-	case xpv1.DeletionDelete, "":
-		if err := asd.Delete(ctx); err != nil && !azure.IsNotFound(err) {
-			asd.acct.Status.SetConditions(xpv1.ReconcileError(err))
-			return resultRequeue, asd.kube.Status().Update(ctx, asd.acct)
+	a, err := e.client.Get(ctx)
+	if azure.IsNotFound(err) {
+		forgotten := azure.RestoreCreateOperation(cr, &cr.Status.AtProvider.LastOperation)
+		if err := azure.FetchAsyncOperation(ctx, e.sender, &cr.Status.AtProvider.LastOperation); err != nil {
+			return managed.ExternalObservation{}, errors.Wrap(err, errFetchLastOperation)
 		}
-	case xpv1.DeletionOrphan:
-		// No need to do anything if we plan to orphan this account.
-	}
-
-	// NOTE(negz): We don't update the conditioned status here because assuming
-	// no other finalizers need to be cleaned up the object should cease to
-	// exist after we update it.
-	meta.RemoveFinalizer(asd.acct, finalizer)
-	return reconcile.Result{}, asd.kube.Update(ctx, asd.acct)
-}
-
-// sync - synchronizes the state of the storage account resource with the state of the
-// account Kubernetes acct
-func (asd *accountSyncDeleter) sync(ctx context.Context) (reconcile.Result, error) {
-	account, err := asd.Get(ctx)
-	if err != nil && !azure.IsNotFound(err) {
-		asd.acct.Status.SetConditions(xpv1.ReconcileError(err))
-		return resultRequeue, asd.kube.Status().Update(ctx, asd.acct)
+		// A creation that is still in progress must not be started again.
+		return managed.ExternalObservation{ResourceExists: azure.IsOperationInProgress(cr.Status.AtProvider.LastOperation, http.MethodPut), ResourceLateInitialized: forgotten}, nil
 	}
-
-	if account == nil {
-		return asd.create(ctx)
-	}
-
-	return asd.update(ctx, account)
-}
-
-// createupdater interface defining create and update operations on/for storage account resource
-type createupdater interface {
-	creator
-	updater
-}
-
-// accountCreateUpdater implementation of createupdater interface
-type accountCreateUpdater struct {
-	syncbacker
-	azurestorage.AccountOperations
-	kube      client.Client
-	acct      *v1alpha3.Account
-	poll      time.Duration
-	projectID string
-}
-
-// newAccountCreateUpdater new instance of accountCreateUpdater
-func newAccountCreateUpdater(ao azurestorage.AccountOperations, kube client.Client, acct *v1alpha3.Account, poll time.Duration) *accountCreateUpdater {
-	return &accountCreateUpdater{
-		syncbacker:        newAccountSyncBacker(ao, kube, acct, poll),
-		AccountOperations: ao,
-		kube:              kube,
-		acct:              acct,
-		poll:              poll,
-	}
-}
-
-// create new storage account resource and save changes back to account specs
-func (acu *accountCreateUpdater) create(ctx context.Context) (reconcile.Result, error) {
-	acu.acct.Status.SetConditions(xpv1.Creating())
-	meta.AddFinalizer(acu.acct, finalizer)
-
-	accountSpec := v1alpha3.ToStorageAccountCreate(acu.acct.Spec.StorageAccountSpec)
-
-	a, err := acu.Create(ctx, accountSpec)
 	if err != nil {
-		acu.acct.Status.SetConditions(xpv1.ReconcileError(err))
-		return resultRequeue, acu.kube.Status().Update(ctx, acu.acct)
+		return managed.ExternalObservation{}, errors.Wrap(err, errGetFailed)
 	}
 
-	return acu.syncback(ctx, a)
-}
-
-// update storage account resource if needed
-func (acu *accountCreateUpdater) update(ctx context.Context, account *storage.Account) (reconcile.Result, error) {
-	if account.ProvisioningState == storage.Succeeded {
-		acu.acct.Status.SetConditions(xpv1.Available())
-
-		current := v1alpha3.NewStorageAccountSpec(account)
-		if reflect.DeepEqual(current, acu.acct.Spec.StorageAccountSpec) {
-			acu.acct.Status.SetConditions(xpv1.ReconcileSuccess())
-			return reconcile.Result{RequeueAfter: acu.poll}, acu.kube.Status().Update(ctx, acu.acct)
-		}
-
-		a, err := acu.Update(ctx, v1alpha3.ToStorageAccountUpdate(acu.acct.Spec.StorageAccountSpec))
-		if err != nil {
-			acu.acct.Status.SetConditions(xpv1.ReconcileError(err))
-			return resultRequeue, acu.kube.Status().Update(ctx, acu.acct)
-		}
-		account = a
+	current := cr.Spec.ForProvider.DeepCopy()
+	azurestorage.LateInitializeAccount(&cr.Spec.ForProvider, *a, e.defaultTags)
+	op := cr.Status.AtProvider.LastOperation
+	forgotten := azure.RestoreCreateOperation(cr, &op)
+	cr.Status.AtProvider = azurestorage.GenerateAccountObservation(*a)
+	cr.Status.AtProvider.LastOperation = op
+	if err := azure.FetchAsyncOperation(ctx, e.sender, &cr.Status.AtProvider.LastOperation); err != nil {
+		return managed.ExternalObservation{}, errors.Wrap(err, errFetchLastOperation)
 	}
 
-	return acu.syncback(ctx, account)
-}
-
-type accountSyncbacker struct {
-	secretupdater
-	acct *v1alpha3.Account
-	kube client.Client
-	poll time.Duration
-}
-
-func newAccountSyncBacker(ao azurestorage.AccountOperations, kube client.Client, acct *v1alpha3.Account, poll time.Duration) *accountSyncbacker {
-	return &accountSyncbacker{
-		secretupdater: newAccountSecretUpdater(ao, kube, acct),
-		kube:          kube,
-		acct:          acct,
-		poll:          poll,
+	var conn managed.ConnectionDetails
+	switch a.ProvisioningState {
+	case storage.Succeeded:
+		if conn, err = e.connectionDetails(ctx, cr, a); err != nil {
+			return managed.ExternalObservation{}, err
+		}
+		cr.Status.SetConditions(xpv1.Available())
+	case storage.Creating, storage.ResolvingDNS:
+		cr.Status.SetConditions(xpv1.Creating())
+	default:
+		cr.Status.SetConditions(xpv1.Unavailable())
+	}
+
+	diff := azurestorage.IsAccountUpToDate(cr.Spec.ForProvider, *a, e.defaultTags)
+	azure.ReportDiff(ctx, diff)
+	return managed.ExternalObservation{
+		ResourceExists:          true,
+		ResourceUpToDate:        diff.UpToDate() && azure.OwnershipTagsUpToDate(cr, a.Tags),
+		ResourceLateInitialized: !cmp.Equal(current, &cr.Spec.ForProvider) || forgotten,
+		ConnectionDetails:       conn,
+	}, nil
+}
+
+// connectionDetails returns the blob endpoint of the supplied storage account,
+// and its name and first key to authenticate with.
+func (e *external) connectionDetails(ctx context.Context, cr *v1beta1.Account, a *storage.Account) (managed.ConnectionDetails, error) {
+	keys, err := e.client.ListKeys(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, errListKeysFailed)
 	}
-}
-
-func (asb *accountSyncbacker) syncback(ctx context.Context, acct *storage.Account) (reconcile.Result, error) {
-	asb.acct.Spec.StorageAccountSpec = v1alpha3.NewStorageAccountSpec(acct)
-	if err := asb.kube.Update(ctx, asb.acct); err != nil {
-		return resultRequeue, err
+	if len(keys) == 0 {
+		return nil, errors.New(errNoKeys)
 	}
-
-	asb.acct.Status.StorageAccountStatus = v1alpha3.NewStorageAccountStatus(acct)
-
-	if acct.ProvisioningState != storage.Succeeded {
-		asb.acct.Status.SetConditions(xpv1.ReconcileSuccess())
-		return requeueOnWait, asb.kube.Status().Update(ctx, asb.acct)
+	conn := managed.ConnectionDetails{
+		xpv1.ResourceCredentialsSecretUserKey:     []byte(meta.GetExternalName(cr)),
+		xpv1.ResourceCredentialsSecretPasswordKey: []byte(azure.ToString(keys[0].Value)),
 	}
-
-	if err := asb.updatesecret(ctx, acct); err != nil {
-		asb.acct.Status.SetConditions(xpv1.ReconcileError(err))
-		return resultRequeue, asb.kube.Status().Update(ctx, asb.acct)
+	if a.PrimaryEndpoints != nil {
+		conn[xpv1.ResourceCredentialsSecretEndpointKey] = []byte(azure.ToString(a.PrimaryEndpoints.Blob))
 	}
-
-	asb.acct.Status.SetConditions(xpv1.ReconcileSuccess())
-	return reconcile.Result{RequeueAfter: asb.poll}, asb.kube.Status().Update(ctx, asb.acct)
+	return conn, nil
 }
 
-type accountSecretUpdater struct {
-	azurestorage.AccountOperations
-	acct *v1alpha3.Account
-	kube client.Client
-}
-
-func newAccountSecretUpdater(ao azurestorage.AccountOperations, kube client.Client, acct *v1alpha3.Account) *accountSecretUpdater {
-	return &accountSecretUpdater{
-		AccountOperations: ao,
-		acct:              acct,
-		kube:              kube,
+func (e *external) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
+	cr, ok := mg.(*v1beta1.Account)
+	if !ok {
+		return managed.ExternalCreation{}, errors.New(errNotAccount)
 	}
+	cr.Status.SetConditions(xpv1.Creating())
+	p := azurestorage.NewAccountCreateParameters(cr.Spec.ForProvider)
+	p.Tags = azure.NewTags(cr, e.defaultTags, cr.Spec.ForProvider.Tags)
+	op, err := e.client.Create(ctx, p)
+	cr.Status.AtProvider.LastOperation = azure.NewAsyncOperation(http.MethodPut, op.FutureAPI, err)
+	azure.RecordCreateOperation(cr, cr.Status.AtProvider.LastOperation)
+	return managed.ExternalCreation{}, errors.Wrap(err, errCreateFailed)
 }
 
-func (asu *accountSecretUpdater) updatesecret(ctx context.Context, acct *storage.Account) error {
-	secret := resource.ConnectionSecretFor(asu.acct, v1alpha3.AccountGroupVersionKind)
-	key := types.NamespacedName{Namespace: secret.Namespace, Name: secret.Name}
-
-	if acct.PrimaryEndpoints != nil {
-		secret.Data[xpv1.ResourceCredentialsSecretEndpointKey] = []byte(to.String(acct.PrimaryEndpoints.Blob))
+func (e *external) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
+	cr, ok := mg.(*v1beta1.Account)
+	if !ok {
+		return managed.ExternalUpdate{}, errors.New(errNotAccount)
 	}
-
-	keys, err := asu.ListKeys(ctx)
-	if err != nil {
-		return errors.Wrapf(err, "failed to list account keys")
+	// Azure rejects updates to storage accounts that are still being
+	// provisioned.
+	if cr.Status.AtProvider.ProvisioningState != string(storage.Succeeded) ||
+		azure.IsOperationInProgress(cr.Status.AtProvider.LastOperation) {
+		return managed.ExternalUpdate{}, nil
 	}
-	if len(keys) == 0 {
-		return errors.New("account keys are empty")
-	}
-
-	secret.Data[xpv1.ResourceCredentialsSecretUserKey] = []byte(meta.GetExternalName(asu.acct))
-	secret.Data[xpv1.ResourceCredentialsSecretPasswordKey] = []byte(to.String(keys[0].Value))
+	p := azurestorage.NewAccountUpdateParameters(cr.Spec.ForProvider)
+	p.Tags = azure.NewTags(cr, e.defaultTags, cr.Spec.ForProvider.Tags)
+	_, err := e.client.Update(ctx, p)
+	cr.Status.AtProvider.LastOperation = azure.NewAsyncOperation(http.MethodPatch, nil, err)
+	return managed.ExternalUpdate{}, errors.Wrap(err, errUpdateFailed)
+}
 
-	if err := asu.kube.Create(ctx, secret); err != nil {
-		if kerrors.IsAlreadyExists(err) {
-			return errors.Wrapf(asu.kube.Update(ctx, secret), "failed to update secret: %s", key)
-		}
-		return errors.Wrapf(err, "failed to create secret: %s", key)
+func (e *external) Delete(ctx context.Context, mg resource.Managed) error {
+	cr, ok := mg.(*v1beta1.Account)
+	if !ok {
+		return errors.New(errNotAccount)
 	}
-
-	return nil
+	cr.Status.SetConditions(xpv1.Deleting())
+	err := resource.Ignore(azure.IsNotFound, e.client.Delete(ctx))
+	cr.Status.AtProvider.LastOperation = azure.NewAsyncOperation(http.MethodDelete, nil, err)
+	return errors.Wrap(err, errDeleteFailed)
 }
@@ -18,286 +18,276 @@ package container
 
 import (
 	"context"
-	"reflect"
-	"time"
 
-	"github.com/Azure/azure-storage-blob-go/azblob"
+	"github.com/Azure/azure-sdk-for-go/services/storage/mgmt/2021-04-01/storage"
+	"github.com/Azure/azure-sdk-for-go/services/storage/mgmt/2021-04-01/storage/storageapi"
 	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/google/go-cmp/cmp"
 	"github.com/pkg/errors"
-	corev1 "k8s.io/api/core/v1"
-	kerrors "k8s.io/apimachinery/pkg/api/errors"
-	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
-	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/connection"
 	"github.com/crossplane/crossplane-runtime/pkg/controller"
-	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
 	"github.com/crossplane/crossplane-runtime/pkg/meta"
 	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
 
-	"github.com/crossplane-contrib/provider-azure/apis/storage/v1alpha3"
+	"github.com/crossplane-contrib/provider-azure/apis/storage/v1beta1"
+	"github.com/crossplane-contrib/provider-azure/apis/v1alpha1"
 	azure "github.com/crossplane-contrib/provider-azure/pkg/clients"
-	"github.com/crossplane-contrib/provider-azure/pkg/clients/storage"
+	azurestorage "github.com/crossplane-contrib/provider-azure/pkg/clients/storage"
+	"github.com/crossplane-contrib/provider-azure/pkg/features"
 )
 
-const (
-	controllerName = "container.storage.azure.crossplane.io"
-	finalizer      = "finalizer." + controllerName
-
-	reconcileTimeout = 2 * time.Minute
-)
+// The finalizer that this controller added before it used the managed
+// reconciler.
+const legacyFinalizer = "finalizer.container.storage.azure.crossplane.io"
 
-// Error strings
+// Error strings.
 const (
-	errAcctSecretNil = "account does not have a connection secret"
-)
-
-var (
-	resultRequeue = reconcile.Result{Requeue: true}
+	errNotContainer  = "managed resource is not a Container"
+	errConnectFailed = "cannot connect to Azure API"
+	errNoAccount     = "neither spec.forProvider.accountName nor spec.forProvider.resourceGroupName may be empty"
+	errGetFailed     = "cannot get container"
+	errCreateFailed  = "cannot create container"
+	errUpdateFailed  = "cannot update container"
+	errDeleteFailed  = "cannot delete container"
+
+	errUpdatePolicyFailed = "cannot create or update immutability policy"
+	errDeletePolicyFailed = "cannot delete immutability policy"
+	errLockPolicyFailed   = "cannot lock immutability policy"
+	errExtendPolicyFailed = "cannot extend immutability policy"
+	errPolicyLocked       = "a locked immutability policy can only be extended"
+	errSetLegalHold       = "cannot set legal hold tags"
+	errClearLegalHold     = "cannot clear legal hold tags"
+	errDeletePolicyLocked = "cannot delete a container with a locked immutability policy"
+	errDeleteLegalHold    = "cannot delete a container with a legal hold"
 )
 
-// Reconciler reconciles an Azure storage container
-type Reconciler struct {
-	client.Client
-	syncdeleterMaker
-	managed.ReferenceResolver
-	managed.Initializer
-
-	poll time.Duration
-
-	log logging.Logger
-}
-
 // Setup adds a controller that reconciles Containers.
 func Setup(mgr ctrl.Manager, o controller.Options) error {
-	name := managed.ControllerName(v1alpha3.ContainerGroupKind)
+	name := managed.ControllerName(v1beta1.ContainerGroupKind)
 
-	r := &Reconciler{
-		Client:           mgr.GetClient(),
-		syncdeleterMaker: &containerSyncdeleterMaker{mgr.GetClient()},
-		Initializer:      managed.NewNameAsExternalName(mgr.GetClient()),
-		poll:             o.PollInterval,
-		log:              o.Logger.WithValues("controller", name),
+	cps := []managed.ConnectionPublisher{managed.NewAPISecretPublisher(mgr.GetClient(), mgr.GetScheme())}
+	if o.Features.Enabled(features.EnableAlphaExternalSecretStores) {
+		cps = append(cps, connection.NewDetailsManager(mgr.GetClient(), v1alpha1.StoreConfigGroupVersionKind))
 	}
 
+	recorder := event.NewAPIRecorder(mgr.GetEventRecorderFor(name))
+
 	return ctrl.NewControllerManagedBy(mgr).
 		Named(name).
 		WithOptions(o.ForControllerRuntime()).
-		For(&v1alpha3.Container{}).
-		Complete(r)
+		For(&v1beta1.Container{}).
+		Complete(azure.NewReconciler(mgr, name, v1beta1.ContainerGroupVersionKind,
+			managed.WithFinalizer(azure.NewMigratingFinalizer(mgr.GetClient(), legacyFinalizer)),
+			managed.WithInitializers(managed.NewNameAsExternalName(mgr.GetClient()), azurestorage.NewAccountProviderConfig(mgr.GetClient())),
+			managed.WithExternalConnecter(azure.NewConnecter(&connector{kube: mgr.GetClient()}, recorder)),
+			managed.WithReferenceResolver(managed.NewAPISimpleReferenceResolver(mgr.GetClient())),
+			managed.WithPollInterval(o.PollInterval),
+			managed.WithLogger(o.Logger.WithValues("controller", name)),
+			managed.WithRecorder(recorder),
+			managed.WithConnectionPublishers(cps...)))
 }
 
-// Reconcile reads that state of the cluster for a Provider acct and makes changes based on the state read
-// and what is in the Provider.Spec
-func (r *Reconciler) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
-	r.log.Debug("Reconciling", "request", request)
-
-	ctx, cancel := context.WithTimeout(ctx, reconcileTimeout)
-	defer cancel()
+type connector struct {
+	kube client.Client
+}
 
-	c := &v1alpha3.Container{}
-	if err := r.Get(ctx, request.NamespacedName, c); err != nil {
-		return reconcile.Result{}, resource.Ignore(kerrors.IsNotFound, err)
+// Connect to the blob containers API of the Azure Resource Manager, using the
+// credentials of the ProviderConfig of the supplied Container. Containers are
+// managed through the management plane so that they keep working when the
+// keys of their storage account are rotated or shared key access is disabled.
+func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
+	cr, ok := mg.(*v1beta1.Container)
+	if !ok {
+		return nil, errors.New(errNotContainer)
 	}
-	if err := r.Initialize(ctx, c); err != nil {
-		return reconcile.Result{}, err
-	}
-
-	sd, err := r.newSyncdeleter(ctx, c, r.poll)
+	creds, auth, err := azure.GetAuthInfo(ctx, c.kube, cr)
 	if err != nil {
-		c.Status.SetConditions(xpv1.ReconcileError(err))
-		return resultRequeue, r.Status().Update(ctx, c)
-	}
-
-	// Check for deletion
-	if c.DeletionTimestamp != nil {
-		return sd.delete(ctx)
+		return nil, errors.Wrap(err, errConnectFailed)
 	}
-
-	return sd.sync(ctx)
-}
-
-type syncdeleterMaker interface {
-	newSyncdeleter(context.Context, *v1alpha3.Container, time.Duration) (syncdeleter, error)
+	cl := storage.NewBlobContainersClientWithBaseURI(creds[azure.CredentialsKeyResourceManagerEndpointURL], creds[azure.CredentialsKeySubscriptionID])
+	azure.ConfigureClient(&cl.Client, auth)
+	return &external{kube: c.kube, client: cl}, nil
 }
 
-type containerSyncdeleterMaker struct {
-	client.Client
+type external struct {
+	kube   client.Client
+	client storageapi.BlobContainersClientAPI
 }
 
-func (m *containerSyncdeleterMaker) newSyncdeleter(ctx context.Context, c *v1alpha3.Container, poll time.Duration) (syncdeleter, error) { // nolint:gocyclo
-	nn := types.NamespacedName{}
-	switch {
-	case c.GetProviderConfigReference() != nil && c.GetProviderConfigReference().Name != "":
-		nn.Name = c.GetProviderConfigReference().Name
-	case c.GetProviderReference() != nil && c.GetProviderReference().Name != "":
-		nn.Name = c.GetProviderReference().Name
-	default:
-		return nil, errors.New("neither providerConfigRef nor providerRef is given")
-	}
-	// Storage containers use a storage account as their 'provider', not a
-	// typical Azure provider.
-	acct := &v1alpha3.Account{}
-	if err := m.Get(ctx, nn, acct); err != nil {
-		// For storage account not found errors - check if we are on deletion path
-		// if so - remove finalizer from this container object
-		if kerrors.IsNotFound(err) && c.DeletionTimestamp != nil {
-			meta.RemoveFinalizer(c, finalizer)
-			if err := m.Client.Update(ctx, c); err != nil {
-				return nil, errors.Wrapf(err, "failed to update after removing finalizer")
-			}
-		}
-		return nil, errors.Wrapf(err, "failed to retrieve storage account: %s", nn.Name)
-	}
-
-	if acct.GetWriteConnectionSecretToReference() == nil {
-		return nil, errors.New(errAcctSecretNil)
+func (e *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	cr, ok := mg.(*v1beta1.Container)
+	if !ok {
+		return managed.ExternalObservation{}, errors.New(errNotContainer)
 	}
-
-	// Retrieve storage account secret
-	s := &corev1.Secret{}
-	n := types.NamespacedName{
-		Namespace: acct.Spec.WriteConnectionSecretToReference.Namespace,
-		Name:      acct.Spec.WriteConnectionSecretToReference.Name,
+	p := cr.Spec.ForProvider
+	if p.ResourceGroupName == "" || p.AccountName == "" {
+		return managed.ExternalObservation{}, errors.New(errNoAccount)
 	}
-	if err := m.Get(ctx, n, s); err != nil {
-		return nil, errors.Wrapf(err, "failed to retrieve storage account secret: %s", n)
+	c, err := e.client.Get(ctx, p.ResourceGroupName, p.AccountName, meta.GetExternalName(cr))
+	if azure.IsNotFound(err) {
+		return managed.ExternalObservation{ResourceExists: false}, nil
 	}
-
-	accountName := string(s.Data[xpv1.ResourceCredentialsSecretUserKey])
-	accountPassword := string(s.Data[xpv1.ResourceCredentialsSecretPasswordKey])
-	containerName := meta.GetExternalName(c)
-
-	ch, err := storage.NewContainerHandle(accountName, accountPassword, containerName)
 	if err != nil {
-		return nil, errors.Wrapf(err, "failed to create client handle: %s, storage account: %s", containerName, accountName)
+		return managed.ExternalObservation{}, errors.Wrap(err, errGetFailed)
 	}
 
-	// set owner reference on the container to storage account, thus
-	// if the account is delete - container is garbage collected as well
-	or := meta.AsOwner(meta.TypedReferenceTo(acct, v1alpha3.AccountGroupVersionKind))
-	or.BlockOwnerDeletion = to.BoolPtr(true)
-	meta.AddOwnerReference(c, or)
+	owners := len(cr.GetOwnerReferences())
+	if err := azurestorage.AddAccountOwner(ctx, e.kube, cr, cr.Spec.ForProvider.AccountNameRef); err != nil {
+		return managed.ExternalObservation{}, err
+	}
 
-	return &containerSyncdeleter{
-		createupdater: &containerCreateUpdater{
-			ContainerOperations: ch,
-			kube:                m.Client,
-			container:           c,
-			poll:                poll,
-		},
-		ContainerOperations: ch,
-		kube:                m.Client,
-		container:           c,
+	annotations := len(cr.GetAnnotations())
+	current := cr.Spec.ForProvider.DeepCopy()
+	azurestorage.LateInitializeContainer(cr, c)
+	cr.Status.AtProvider = azurestorage.GenerateContainerObservation(c)
+	cr.Status.SetConditions(xpv1.Available())
+
+	diff := azurestorage.IsContainerUpToDate(desired(cr), c)
+	azure.ReportDiff(ctx, diff)
+	return managed.ExternalObservation{
+		ResourceExists:   true,
+		ResourceUpToDate: diff.UpToDate(),
+		ResourceLateInitialized: len(cr.GetOwnerReferences()) != owners || len(cr.GetAnnotations()) != annotations ||
+			!cmp.Equal(current, &cr.Spec.ForProvider),
 	}, nil
 }
 
-type deleter interface {
-	delete(context.Context) (reconcile.Result, error)
-}
-
-type syncer interface {
-	sync(context.Context) (reconcile.Result, error)
-}
-
-type creator interface {
-	create(context.Context) (reconcile.Result, error)
-}
-
-type updater interface {
-	update(context.Context, *azblob.PublicAccessType, azblob.Metadata) (reconcile.Result, error)
-}
-
-type syncdeleter interface {
-	deleter
-	syncer
+// desired returns the ContainerParameters of the supplied Container, with the
+// immutability policy it desires.
+func desired(cr *v1beta1.Container) v1beta1.ContainerParameters {
+	p := cr.Spec.ForProvider
+	p.ImmutabilityPolicy = azurestorage.DesiredImmutabilityPolicy(cr)
+	return p
 }
 
-type containerSyncdeleter struct {
-	createupdater
-	storage.ContainerOperations
-	kube      client.Client
-	container *v1alpha3.Container
+func (e *external) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
+	cr, ok := mg.(*v1beta1.Container)
+	if !ok {
+		return managed.ExternalCreation{}, errors.New(errNotContainer)
+	}
+	cr.Status.SetConditions(xpv1.Creating())
+	p := cr.Spec.ForProvider
+	_, err := e.client.Create(ctx, p.ResourceGroupName, p.AccountName, meta.GetExternalName(cr), azurestorage.NewBlobContainer(p))
+	return managed.ExternalCreation{}, errors.Wrap(err, errCreateFailed)
 }
 
-func (csd *containerSyncdeleter) delete(ctx context.Context) (reconcile.Result, error) {
-	csd.container.Status.SetConditions(xpv1.Deleting())
-	if csd.container.Spec.DeletionPolicy == xpv1.DeletionDelete {
-		if err := csd.Delete(ctx); err != nil && !azure.IsNotFound(err) {
-			csd.container.Status.SetConditions(xpv1.ReconcileError(err))
-			return resultRequeue, csd.kube.Status().Update(ctx, csd.container)
+func (e *external) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
+	cr, ok := mg.(*v1beta1.Container)
+	if !ok {
+		return managed.ExternalUpdate{}, errors.New(errNotContainer)
+	}
+	p := cr.Spec.ForProvider
+	if _, err := e.client.Update(ctx, p.ResourceGroupName, p.AccountName, meta.GetExternalName(cr), azurestorage.NewBlobContainer(p)); err != nil {
+		return managed.ExternalUpdate{}, errors.Wrap(err, errUpdateFailed)
+	}
+	if want := azurestorage.DesiredImmutabilityPolicy(cr); want != nil {
+		if err := e.updateImmutabilityPolicy(ctx, cr, *want); err != nil {
+			return managed.ExternalUpdate{}, err
 		}
 	}
-
-	// NOTE(negz): We don't update the conditioned status here because assuming
-	// no other finalizers need to be cleaned up the object should cease to
-	// exist after we update it.
-	meta.RemoveFinalizer(csd.container, finalizer)
-	return reconcile.Result{}, csd.kube.Update(ctx, csd.container)
+	if p.LegalHold != nil {
+		if err := e.updateLegalHold(ctx, cr); err != nil {
+			return managed.ExternalUpdate{}, err
+		}
+	}
+	return managed.ExternalUpdate{}, nil
 }
 
-func (csd *containerSyncdeleter) sync(ctx context.Context) (reconcile.Result, error) {
-	access, meta, err := csd.Get(ctx)
-	if err != nil && !storage.IsNotFoundError(err) {
-		csd.container.Status.SetConditions(xpv1.ReconcileError(err))
-		return resultRequeue, csd.kube.Status().Update(ctx, csd.container)
+// updateImmutabilityPolicy brings the observed immutability policy of the
+// supplied Container in sync with the supplied policy. An unlocked policy is
+// created or updated, and locked if desired, or deleted if the supplied policy
+// is empty. A locked policy can't be unlocked, shortened, deleted or otherwise
+// changed, and may only be extended.
+func (e *external) updateImmutabilityPolicy(ctx context.Context, cr *v1beta1.Container, want v1beta1.ImmutabilityPolicy) error {
+	p := cr.Spec.ForProvider
+	name := meta.GetExternalName(cr)
+	o := cr.Status.AtProvider.ImmutabilityPolicy
+
+	if azurestorage.IsImmutabilityPolicyLocked(o) {
+		if azurestorage.IsImmutabilityPolicyEmpty(want) || !azure.ToBool(want.Locked) ||
+			want.ImmutabilityPeriodSinceCreationInDays < o.ImmutabilityPeriodSinceCreationInDays ||
+			(want.AllowProtectedAppendWrites != nil && *want.AllowProtectedAppendWrites != o.AllowProtectedAppendWrites) {
+			return errors.New(errPolicyLocked)
+		}
+		if want.ImmutabilityPeriodSinceCreationInDays == o.ImmutabilityPeriodSinceCreationInDays {
+			return nil
+		}
+		_, err := e.client.ExtendImmutabilityPolicy(ctx, p.ResourceGroupName, p.AccountName, name, o.Etag, &storage.ImmutabilityPolicy{
+			ImmutabilityPolicyProperty: &storage.ImmutabilityPolicyProperty{
+				ImmutabilityPeriodSinceCreationInDays: to.Int32Ptr(want.ImmutabilityPeriodSinceCreationInDays),
+			},
+		})
+		return errors.Wrap(err, errExtendPolicyFailed)
 	}
 
-	if access == nil {
-		return csd.create(ctx)
+	var etag string
+	if o != nil {
+		etag = o.Etag
 	}
-
-	return csd.update(ctx, access, meta)
-}
-
-type createupdater interface {
-	creator
-	updater
-}
-
-// containerCreateUpdater implementation of createupdater interface
-type containerCreateUpdater struct {
-	storage.ContainerOperations
-	kube      client.Client
-	container *v1alpha3.Container
-	poll      time.Duration
-}
-
-var _ createupdater = &containerCreateUpdater{}
-
-func (ccu *containerCreateUpdater) create(ctx context.Context) (reconcile.Result, error) {
-	container := ccu.container
-	container.Status.SetConditions(xpv1.Creating())
-
-	meta.AddFinalizer(container, finalizer)
-	if err := ccu.kube.Update(ctx, container); err != nil {
-		return resultRequeue, errors.Wrapf(err, "failed to update container spec")
+	if azurestorage.IsImmutabilityPolicyEmpty(want) {
+		if o == nil {
+			return nil
+		}
+		_, err := e.client.DeleteImmutabilityPolicy(ctx, p.ResourceGroupName, p.AccountName, name, etag)
+		return errors.Wrap(resource.Ignore(azure.IsNotFound, err), errDeletePolicyFailed)
 	}
-
-	spec := container.Spec
-	if err := ccu.Create(ctx, spec.PublicAccessType, spec.Metadata); err != nil {
-		container.Status.SetConditions(xpv1.ReconcileError(err))
-		return resultRequeue, ccu.kube.Status().Update(ctx, container)
+	if o == nil || want.ImmutabilityPeriodSinceCreationInDays != o.ImmutabilityPeriodSinceCreationInDays ||
+		(want.AllowProtectedAppendWrites != nil && *want.AllowProtectedAppendWrites != o.AllowProtectedAppendWrites) {
+		ip, err := e.client.CreateOrUpdateImmutabilityPolicy(ctx, p.ResourceGroupName, p.AccountName, name, azurestorage.NewImmutabilityPolicy(want), etag)
+		if err != nil {
+			return errors.Wrap(err, errUpdatePolicyFailed)
+		}
+		etag = azure.ToString(ip.Etag)
 	}
-
-	container.Status.SetConditions(xpv1.Available(), xpv1.ReconcileSuccess())
-	return reconcile.Result{}, ccu.kube.Status().Update(ctx, ccu.container)
+	if !azure.ToBool(want.Locked) {
+		return nil
+	}
+	_, err := e.client.LockImmutabilityPolicy(ctx, p.ResourceGroupName, p.AccountName, name, etag)
+	return errors.Wrap(err, errLockPolicyFailed)
 }
 
-func (ccu *containerCreateUpdater) update(ctx context.Context, accessType *azblob.PublicAccessType, meta azblob.Metadata) (reconcile.Result, error) {
-	container := ccu.container
-	spec := container.Spec
-
-	if !reflect.DeepEqual(*accessType, spec.PublicAccessType) || !reflect.DeepEqual(meta, spec.Metadata) {
-		if err := ccu.Update(ctx, spec.PublicAccessType, spec.Metadata); err != nil {
-			container.Status.SetConditions(xpv1.ReconcileError(err))
-			return resultRequeue, ccu.kube.Status().Update(ctx, container)
+// updateLegalHold sets the legal hold tags of the supplied Container that it
+// did not observe, and clears those it observed but does not specify.
+func (e *external) updateLegalHold(ctx context.Context, cr *v1beta1.Container) error {
+	p := cr.Spec.ForProvider
+	name := meta.GetExternalName(cr)
+	set, clear := azurestorage.DiffLegalHoldTags(p.LegalHold.Tags, cr.Status.AtProvider.LegalHoldTags)
+	if len(set) > 0 {
+		if _, err := e.client.SetLegalHold(ctx, p.ResourceGroupName, p.AccountName, name, storage.LegalHold{Tags: &set}); err != nil {
+			return errors.Wrap(err, errSetLegalHold)
+		}
+	}
+	if len(clear) > 0 {
+		if _, err := e.client.ClearLegalHold(ctx, p.ResourceGroupName, p.AccountName, name, storage.LegalHold{Tags: &clear}); err != nil {
+			return errors.Wrap(err, errClearLegalHold)
 		}
 	}
+	return nil
+}
 
-	container.Status.SetConditions(xpv1.Available(), xpv1.ReconcileSuccess())
-	return reconcile.Result{RequeueAfter: ccu.poll}, ccu.kube.Status().Update(ctx, ccu.container)
+func (e *external) Delete(ctx context.Context, mg resource.Managed) error {
+	cr, ok := mg.(*v1beta1.Container)
+	if !ok {
+		return errors.New(errNotContainer)
+	}
+	// Blobs in a container with a locked immutability policy or a legal hold
+	// must be retained, so the container is not deleted while either is in
+	// effect.
+	o := cr.Status.AtProvider
+	if azurestorage.IsImmutabilityPolicyLocked(o.ImmutabilityPolicy) {
+		return azure.RefuseDeletion(errors.New(errDeletePolicyLocked))
+	}
+	if o.HasLegalHold {
+		return azure.RefuseDeletion(errors.New(errDeleteLegalHold))
+	}
+	cr.Status.SetConditions(xpv1.Deleting())
+	p := cr.Spec.ForProvider
+	_, err := e.client.Delete(ctx, p.ResourceGroupName, p.AccountName, meta.GetExternalName(cr))
+	return errors.Wrap(resource.Ignore(azure.IsNotFound, err), errDeleteFailed)
 }
@@ -18,931 +18,654 @@ package container
 
 import (
 	"context"
-	"net/http"
-	"strings"
 	"testing"
 	"time"
 
-	"github.com/crossplane-contrib/provider-azure/apis"
-
-	"github.com/Azure/azure-storage-blob-go/azblob"
+	"github.com/Azure/azure-sdk-for-go/services/storage/mgmt/2021-04-01/storage"
 	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/date"
+	"github.com/Azure/go-autorest/autorest/to"
 	"github.com/google/go-cmp/cmp"
-	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/pkg/errors"
-	v1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
-	"k8s.io/apimachinery/pkg/types"
-	"k8s.io/client-go/kubernetes/scheme"
 	"sigs.k8s.io/controller-runtime/pkg/client"
-	"sigs.k8s.io/controller-runtime/pkg/client/fake"
-	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
-	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
 	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
 	"github.com/crossplane/crossplane-runtime/pkg/test"
 
-	"github.com/crossplane-contrib/provider-azure/apis/storage/v1alpha3"
-	v1alpha3test "github.com/crossplane-contrib/provider-azure/apis/storage/v1alpha3/test"
-	"github.com/crossplane-contrib/provider-azure/pkg/clients/storage"
-	azurestoragefake "github.com/crossplane-contrib/provider-azure/pkg/clients/storage/fake"
+	"github.com/crossplane-contrib/provider-azure/apis/storage/v1beta1"
+	azure "github.com/crossplane-contrib/provider-azure/pkg/clients"
+	"github.com/crossplane-contrib/provider-azure/pkg/clients/armtest"
+	azurestorage "github.com/crossplane-contrib/provider-azure/pkg/clients/storage"
+	"github.com/crossplane-contrib/provider-azure/pkg/clients/storage/fake"
 )
 
-func init() {
-	_ = apis.AddToScheme(scheme.Scheme)
-}
+const (
+	containerName     = "cool-container"
+	accountName       = "coolaccount"
+	resourceGroupName = "cool-rg"
+)
 
-type mockCreateUpdater struct {
-	mockCreate func(context.Context) (reconcile.Result, error)
-	mockUpdate func(context.Context, *azblob.PublicAccessType, azblob.Metadata) (reconcile.Result, error)
-}
+var errBoom = errors.New("boom")
 
-func (m *mockCreateUpdater) create(ctx context.Context) (reconcile.Result, error) {
-	return m.mockCreate(ctx)
-}
+var _ managed.ExternalClient = &external{}
+var _ managed.ExternalConnecter = &connector{}
 
-func (m *mockCreateUpdater) update(ctx context.Context, pat *azblob.PublicAccessType, meta azblob.Metadata) (reconcile.Result, error) {
-	return m.mockUpdate(ctx, pat, meta)
-}
+type containerModifier func(*v1beta1.Container)
 
-func newMockCreateUpdater() *mockCreateUpdater {
-	return &mockCreateUpdater{
-		mockCreate: func(context.Context) (result reconcile.Result, e error) {
-			return reconcile.Result{}, nil
-		},
-		mockUpdate: func(context.Context, *azblob.PublicAccessType, azblob.Metadata) (reconcile.Result, error) {
-			return reconcile.Result{}, nil
-		},
-	}
+func withConditions(c ...xpv1.Condition) containerModifier {
+	return func(cr *v1beta1.Container) { cr.Status.ConditionedStatus.Conditions = c }
 }
 
-var _ createupdater = &mockCreateUpdater{}
-
-type mockSyncdeleter struct {
-	mockDelete func(context.Context) (reconcile.Result, error)
-	mockSync   func(context.Context) (reconcile.Result, error)
+func withPublicAccessType(t string) containerModifier {
+	return func(cr *v1beta1.Container) { cr.Spec.ForProvider.PublicAccessType = t }
 }
 
-func (m *mockSyncdeleter) delete(ctx context.Context) (reconcile.Result, error) {
-	return m.mockDelete(ctx)
+func withMetadata(m map[string]string) containerModifier {
+	return func(cr *v1beta1.Container) { cr.Spec.ForProvider.Metadata = m }
 }
 
-func (m *mockSyncdeleter) sync(ctx context.Context) (reconcile.Result, error) {
-	return m.mockSync(ctx)
+func withAccountName(n string) containerModifier {
+	return func(cr *v1beta1.Container) { cr.Spec.ForProvider.AccountName = n }
 }
 
-var _ syncdeleter = &mockSyncdeleter{}
-
-type mockSyncdeleteMaker struct {
-	mockNewSyncdeleter func(context.Context, *v1alpha3.Container, time.Duration) (syncdeleter, error)
+func withAccountNameRef(n string) containerModifier {
+	return func(cr *v1beta1.Container) { cr.Spec.ForProvider.AccountNameRef = &xpv1.Reference{Name: n} }
 }
 
-func (m *mockSyncdeleteMaker) newSyncdeleter(ctx context.Context, c *v1alpha3.Container, poll time.Duration) (syncdeleter, error) {
-	return m.mockNewSyncdeleter(ctx, c, poll)
+func withImmutabilityPolicy(ip *v1beta1.ImmutabilityPolicy) containerModifier {
+	return func(cr *v1beta1.Container) { cr.Spec.ForProvider.ImmutabilityPolicy = ip }
 }
 
-var _ syncdeleterMaker = &mockSyncdeleteMaker{}
-
-func newContainerNotFoundError(name string) error {
-	return kerrors.NewNotFound(
-		schema.GroupResource{Group: v1alpha3.Group, Resource: v1alpha3.ContainerKind}, name)
+func withPolicyAnnotation() containerModifier {
+	return func(cr *v1beta1.Container) {
+		meta.AddAnnotations(cr, map[string]string{azurestorage.AnnotationKeyImmutabilityPolicy: "true"})
+	}
 }
 
-func newAccountNotFoundError(name string) error {
-	return kerrors.NewNotFound(
-		schema.GroupResource{Group: v1alpha3.Group, Resource: strings.ToLower(v1alpha3.AccountKind) + "s"}, name)
+func withLegalHold(tags ...string) containerModifier {
+	return func(cr *v1beta1.Container) { cr.Spec.ForProvider.LegalHold = &v1beta1.LegalHold{Tags: tags} }
 }
 
-func newSecret(ns, name string, data map[string][]byte) *v1.Secret {
-	return &v1.Secret{
-		ObjectMeta: metav1.ObjectMeta{
-			Namespace: ns,
-			Name:      name,
-		},
-		Data: data,
+func withOwner(a *v1beta1.Account) containerModifier {
+	return func(cr *v1beta1.Container) {
+		meta.AddOwnerReference(cr, azurestorage.AccountOwnerReference(a))
 	}
 }
 
-func newSecretNotFoundError(name string) error {
-	return kerrors.NewNotFound(
-		schema.GroupResource{Group: v1.GroupName, Resource: "secrets"}, name)
+func withObservation(o v1beta1.ContainerObservation) containerModifier {
+	return func(cr *v1beta1.Container) { cr.Status.AtProvider = o }
 }
 
-func newStorageNotFoundError() error {
-	return azblob.NewResponseError(nil, &http.Response{StatusCode: http.StatusNotFound}, "")
+func container(m ...containerModifier) *v1beta1.Container {
+	cr := &v1beta1.Container{ObjectMeta: metav1.ObjectMeta{Name: containerName}}
+	cr.Spec.ForProvider.ResourceGroupName = resourceGroupName
+	cr.Spec.ForProvider.AccountName = accountName
+	meta.SetExternalName(cr, containerName)
+	for _, f := range m {
+		f(cr)
+	}
+	return cr
 }
 
-const (
-	testNamespace     = "default"
-	testContainerName = "testContainer"
-	testAccountName   = "testAccount"
-)
-
-func TestReconciler_Reconcile(t *testing.T) {
-	key := types.NamespacedName{Name: testContainerName}
-	req := reconcile.Request{NamespacedName: key}
-	ctx := context.TODO()
-	errBoom := errors.New("boom")
+func TestObserve(t *testing.T) {
+	modified := time.Date(2022, 4, 1, 0, 0, 0, 0, time.UTC)
 
-	type fields struct {
-		Client           client.Client
-		syncdeleterMaker syncdeleterMaker
-	}
 	type want struct {
+		cr  *v1beta1.Container
+		o   managed.ExternalObservation
 		err error
-		res reconcile.Result
-		con *v1alpha3.Container
 	}
-	tests := []struct {
-		name   string
-		fields fields
+
+	cases := map[string]struct {
+		reason string
+		kube   client.Client
+		client *fake.MockBlobContainersClient
+		cr     *v1beta1.Container
 		want   want
 	}{
-		{
-			name: "GetErrNotFound",
-			fields: fields{
-				Client: &test.MockClient{
-					MockGet: func(ctx context.Context, key client.ObjectKey, obj client.Object) error {
-						return newContainerNotFoundError(testContainerName)
-					},
-				},
-				syncdeleterMaker: nil,
-			},
+		"NoAccount": {
+			reason: "A Container whose storage account is not resolved should return an error",
+			cr:     container(withAccountName("")),
 			want: want{
-				res: reconcile.Result{},
+				cr:  container(withAccountName("")),
+				err: errors.New(errNoAccount),
 			},
 		},
-		{
-			name: "GetErrOther",
-			fields: fields{
-				Client: &test.MockClient{
-					MockGet: func(ctx context.Context, key client.ObjectKey, obj client.Object) error {
-						return errors.New("test-get-error")
-					},
+		"NotFound": {
+			reason: "A container that does not exist should be reported as such",
+			client: &fake.MockBlobContainersClient{
+				MockGet: func(_ context.Context, _, _, _ string) (storage.BlobContainer, error) {
+					return storage.BlobContainer{}, fake.NotFound()
 				},
-				syncdeleterMaker: nil,
-			},
-			want: want{
-				res: reconcile.Result{},
-				err: errors.New("test-get-error"),
 			},
+			cr:   container(),
+			want: want{cr: container()},
 		},
-		{
-			name: "SyncdeleteMakerError",
-			fields: fields{
-				Client: fake.NewClientBuilder().WithObjects(v1alpha3test.NewMockContainer(testContainerName).
-					WithFinalizer("foo.bar").Container).Build(),
-				syncdeleterMaker: &mockSyncdeleteMaker{
-					mockNewSyncdeleter: func(ctx context.Context, c *v1alpha3.Container, _ time.Duration) (syncdeleter, error) {
-						return nil, errBoom
-					},
+		"GetFailed": {
+			reason: "Errors getting the container should be returned",
+			client: &fake.MockBlobContainersClient{
+				MockGet: func(_ context.Context, _, _, _ string) (storage.BlobContainer, error) {
+					return storage.BlobContainer{}, errBoom
 				},
 			},
+			cr: container(),
 			want: want{
-				res: resultRequeue,
-				con: v1alpha3test.NewMockContainer(testContainerName).
-					WithFinalizer("foo.bar").
-					WithStatusConditions(
-						xpv1.ReconcileError(errBoom),
-					).
-					Container,
+				cr:  container(),
+				err: errors.Wrap(errBoom, errGetFailed),
 			},
 		},
-		{
-			name: "Delete",
-			fields: fields{
-				Client: fake.NewClientBuilder().WithObjects(v1alpha3test.NewMockContainer(testContainerName).
-					WithDeleteTimestamp(time.Now()).Container).Build(),
-				syncdeleterMaker: &mockSyncdeleteMaker{
-					mockNewSyncdeleter: func(ctx context.Context, c *v1alpha3.Container, _ time.Duration) (syncdeleter, error) {
-						return &mockSyncdeleter{
-							mockDelete: func(ctx context.Context) (reconcile.Result, error) {
-								return reconcile.Result{}, nil
-							},
-						}, nil
-					},
+		"AccountNotFound": {
+			reason: "A container whose referenced storage account does not exist should be observed without an owner",
+			kube:   &test.MockClient{MockGet: test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{}, accountName))},
+			client: &fake.MockBlobContainersClient{
+				MockGet: func(_ context.Context, _, _, _ string) (storage.BlobContainer, error) {
+					return storage.BlobContainer{ContainerProperties: &storage.ContainerProperties{PublicAccess: storage.PublicAccessNone}}, nil
 				},
 			},
+			cr: container(withAccountNameRef(accountName)),
 			want: want{
-				res: reconcile.Result{},
+				cr: container(withAccountNameRef(accountName), withConditions(xpv1.Available())),
+				o:  managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
 			},
 		},
-		{
-			name: "Sync",
-			fields: fields{
-				Client: fake.NewClientBuilder().WithObjects(v1alpha3test.NewMockContainer(testContainerName).Container).Build(),
-				syncdeleterMaker: &mockSyncdeleteMaker{
-					mockNewSyncdeleter: func(ctx context.Context, c *v1alpha3.Container, _ time.Duration) (syncdeleter, error) {
-						return &mockSyncdeleter{
-							mockSync: func(ctx context.Context) (reconcile.Result, error) {
-								return reconcile.Result{}, nil
-							},
-						}, nil
-					},
+		"OwnerAdded": {
+			reason: "A container should be owned by its referenced storage account, and the owner reference persisted",
+			kube:   &test.MockClient{MockGet: fake.GetAccount(fake.Account(accountName))},
+			client: &fake.MockBlobContainersClient{
+				MockGet: func(_ context.Context, _, _, _ string) (storage.BlobContainer, error) {
+					return storage.BlobContainer{
+						ID: to.StringPtr("id"),
+						ContainerProperties: &storage.ContainerProperties{
+							LastModifiedTime: &date.Time{Time: modified},
+							LeaseState:       storage.LeaseStateAvailable,
+							HasLegalHold:     to.BoolPtr(true),
+						},
+					}, nil
 				},
 			},
+			cr: container(withAccountNameRef(accountName)),
 			want: want{
-				res: reconcile.Result{},
+				cr: container(
+					withAccountNameRef(accountName),
+					withOwner(fake.Account(accountName)),
+					withObservation(v1beta1.ContainerObservation{ID: "id", LastModified: &metav1.Time{Time: modified}, LeaseState: "Available", HasLegalHold: true}),
+					withConditions(xpv1.Available()),
+				),
+				o: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true, ResourceLateInitialized: true},
 			},
 		},
-	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			r := &Reconciler{
-				Client:           tt.fields.Client,
-				syncdeleterMaker: tt.fields.syncdeleterMaker,
-				Initializer:      managed.NewNameAsExternalName(tt.fields.Client),
-				log:              logging.NewNopLogger(),
-			}
-			got, err := r.Reconcile(ctx, req)
-			if diff := cmp.Diff(tt.want.err, err, test.EquateErrors()); diff != "" {
-				t.Errorf("Reconciler.Reconcile(): -want error, +got error:\n%s", diff)
-			}
-			if diff := cmp.Diff(tt.want.res, got); diff != "" {
-				t.Errorf("Reconciler.Reconcile(): -want, +got\n%s", diff)
-			}
-			if tt.want.con != nil {
-				c := &v1alpha3.Container{}
-				if err := tt.fields.Client.Get(ctx, key, c); err != nil {
-					t.Errorf("Reconciler.Reconcile() container error: %s", err)
-				}
-				// NOTE(muvaf): Get call returns TypeMeta and ResourceVersion
-				// that we are not interested. Since diff shows them, we have to
-				// assign the right values.
-				c.TypeMeta = tt.want.con.TypeMeta
-				tt.want.con.ResourceVersion = c.ResourceVersion
-				if diff := cmp.Diff(tt.want.con, c, test.EquateConditions()); diff != "" {
-					t.Errorf("Reconciler.Reconcile() container: -want, +got\n%s", diff)
-				}
-			}
-		})
-	}
-}
-
-func Test_containerSyncdeleterMaker_newSyncdeleter(t *testing.T) {
-	key := types.NamespacedName{Name: testContainerName}
-	newCont := func() *v1alpha3test.MockContainer {
-		return v1alpha3test.NewMockContainer(testContainerName)
-	}
-	ctx := context.TODO()
-	testAccountKey := "dGVzdC1rZXkK"
-
-	ch, err := storage.NewContainerHandle(testAccountName, testAccountKey, testContainerName)
-	if err != nil {
-		t.Errorf("containerSyncdeleterMaker.newSyncdeleter() unexpected error %v", err)
-	}
-
-	type fields struct {
-		Client client.Client
-	}
-	type args struct {
-		ctx context.Context
-		c   *v1alpha3.Container
-	}
-	type want struct {
-		err    error
-		syndel syncdeleter
-		cont   *v1alpha3.Container
-	}
-	tests := []struct {
-		name   string
-		fields fields
-		args   args
-		want   want
-	}{
-		{
-			name: "FailedToGetAccountNotFoundNoDeleteProviderRef",
-			fields: fields{
-				Client: &test.MockClient{
-					MockGet: func(ctx context.Context, key client.ObjectKey, obj client.Object) error {
-						return newAccountNotFoundError(testAccountName)
-					},
+		"UpToDate": {
+			reason: "A container whose access type and metadata match the Container should be up to date",
+			kube:   &test.MockClient{MockGet: fake.GetAccount(fake.Account(accountName))},
+			client: &fake.MockBlobContainersClient{
+				MockGet: func(_ context.Context, rg, acct, name string) (storage.BlobContainer, error) {
+					if rg != resourceGroupName || acct != accountName || name != containerName {
+						return storage.BlobContainer{}, errBoom
+					}
+					return storage.BlobContainer{ContainerProperties: &storage.ContainerProperties{
+						PublicAccess: storage.PublicAccessBlob,
+						Metadata:     map[string]*string{"cool": to.StringPtr("very")},
+					}}, nil
 				},
 			},
-			args: args{
-				ctx: ctx,
-				c:   newCont().WithSpecProviderRef(testAccountName).WithFinalizer(finalizer).Container,
-			},
+			cr: container(withAccountNameRef(accountName), withOwner(fake.Account(accountName)), withPublicAccessType("blob"), withMetadata(map[string]string{"cool": "very"})),
 			want: want{
-				err: errors.Wrapf(newAccountNotFoundError(testAccountName),
-					"failed to retrieve storage account: %s", testAccountName),
+				cr: container(
+					withAccountNameRef(accountName),
+					withOwner(fake.Account(accountName)),
+					withPublicAccessType("blob"),
+					withMetadata(map[string]string{"cool": "very"}),
+					withConditions(xpv1.Available()),
+				),
+				o: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
 			},
 		},
-		{
-			name: "FailedToGetAccountNotFoundNoDeleteProviderConfigRef",
-			fields: fields{
-				Client: &test.MockClient{
-					MockGet: func(ctx context.Context, key client.ObjectKey, obj client.Object) error {
-						return newAccountNotFoundError(testAccountName)
-					},
+		"LateInitialized": {
+			reason: "The immutability policy and legal hold of a container should be late-initialized",
+			client: &fake.MockBlobContainersClient{
+				MockGet: func(_ context.Context, _, _, _ string) (storage.BlobContainer, error) {
+					return storage.BlobContainer{ContainerProperties: &storage.ContainerProperties{
+						PublicAccess: storage.PublicAccessNone,
+						HasLegalHold: to.BoolPtr(true),
+						ImmutabilityPolicy: &storage.ImmutabilityPolicyProperties{
+							Etag: to.StringPtr("etag"),
+							ImmutabilityPolicyProperty: &storage.ImmutabilityPolicyProperty{
+								ImmutabilityPeriodSinceCreationInDays: to.Int32Ptr(7),
+								AllowProtectedAppendWrites:            to.BoolPtr(false),
+								State:                                 storage.ImmutabilityPolicyStateUnlocked,
+							},
+						},
+						LegalHold: &storage.LegalHoldProperties{
+							HasLegalHold: to.BoolPtr(true),
+							Tags:         &[]storage.TagProperty{{Tag: to.StringPtr("audit")}},
+						},
+					}}, nil
 				},
 			},
-			args: args{
-				ctx: ctx,
-				c:   newCont().WithSpecProviderConfigRef(testAccountName).WithFinalizer(finalizer).Container,
-			},
-			want: want{
-				err: errors.Wrapf(newAccountNotFoundError(testAccountName),
-					"failed to retrieve storage account: %s", testAccountName),
-			},
-		},
-		{
-			name: "FailedToGetAccountNotFoundYesDelete",
-			fields: fields{
-				Client: fake.NewClientBuilder().WithObjects(newCont().WithSpecProviderRef(testAccountName).
-					WithFinalizer(finalizer).
-					WithResourceVersion("1").Container).Build(),
-			},
-			args: args{
-				ctx: ctx,
-				c: newCont().WithSpecProviderRef(testAccountName).WithFinalizer(finalizer).
-					WithResourceVersion("1").
-					WithDeleteTimestamp(time.Now()).
-					Container,
-			},
-			want: want{
-				err: errors.Wrapf(newAccountNotFoundError(testAccountName),
-					"failed to retrieve storage account: %s", testAccountName),
-			},
-		},
-		{
-			name: "AccountReferenceSecretNotFound",
-			fields: fields{
-				Client: fake.NewClientBuilder().WithObjects(
-					v1alpha3test.NewMockAccount(testAccountName).
-						WithSpecWriteConnectionSecretToReference(testNamespace, testAccountName).
-						Account,
-					newCont().WithSpecProviderRef(testAccountName).WithFinalizer(finalizer).Container).Build(),
-			},
-			args: args{
-				ctx: ctx,
-				c: newCont().WithSpecProviderRef(testAccountName).WithFinalizer(finalizer).
-					Container,
-			},
+			cr: container(),
 			want: want{
-				err: errors.Wrapf(newSecretNotFoundError(testAccountName),
-					"failed to retrieve storage account secret: %s/%s", testNamespace, testAccountName),
+				cr: container(
+					withImmutabilityPolicy(&v1beta1.ImmutabilityPolicy{
+						ImmutabilityPeriodSinceCreationInDays: 7,
+						AllowProtectedAppendWrites:            to.BoolPtr(false),
+						Locked:                                to.BoolPtr(false),
+					}),
+					withPolicyAnnotation(),
+					withLegalHold("audit"),
+					withObservation(v1beta1.ContainerObservation{
+						HasLegalHold: true,
+						ImmutabilityPolicy: &v1beta1.ImmutabilityPolicyObservation{
+							ImmutabilityPeriodSinceCreationInDays: 7,
+							State:                                 "Unlocked",
+							Etag:                                  "etag",
+						},
+						LegalHoldTags: []string{"audit"},
+					}),
+					withConditions(xpv1.Available()),
+				),
+				o: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true, ResourceLateInitialized: true},
 			},
 		},
-		{
-			name: "AccountReferenceSecretNil",
-			fields: fields{
-				Client: fake.NewClientBuilder().WithObjects(
-					v1alpha3test.NewMockAccount(testAccountName).Account,
-					newCont().WithSpecProviderRef(testAccountName).WithFinalizer(finalizer).Container).Build(),
-			},
-			args: args{
-				ctx: ctx,
-				c: newCont().WithSpecProviderRef(testAccountName).WithFinalizer(finalizer).
-					Container,
+		"PolicyRemoved": {
+			reason: "A container should not be up to date if the immutability policy was removed from the Container",
+			client: &fake.MockBlobContainersClient{
+				MockGet: func(_ context.Context, _, _, _ string) (storage.BlobContainer, error) {
+					return storage.BlobContainer{ContainerProperties: &storage.ContainerProperties{
+						PublicAccess: storage.PublicAccessNone,
+						ImmutabilityPolicy: &storage.ImmutabilityPolicyProperties{
+							Etag: to.StringPtr("etag"),
+							ImmutabilityPolicyProperty: &storage.ImmutabilityPolicyProperty{
+								ImmutabilityPeriodSinceCreationInDays: to.Int32Ptr(7),
+								State:                                 storage.ImmutabilityPolicyStateUnlocked,
+							},
+						},
+					}}, nil
+				},
 			},
+			cr: container(withPolicyAnnotation()),
 			want: want{
-				err: errors.New(errAcctSecretNil),
-			},
-		},
-		{
-			name: "FailedToCreateContainerHandle",
-			fields: fields{
-				Client: fake.NewClientBuilder().WithObjects(
-					newCont().WithSpecProviderRef(testAccountName).WithFinalizer(finalizer).Container,
-					newSecret(testNamespace, testAccountName, map[string][]byte{
-						xpv1.ResourceCredentialsSecretUserKey:     []byte(testAccountName),
-						xpv1.ResourceCredentialsSecretPasswordKey: []byte("test-key"),
+				cr: container(
+					withPolicyAnnotation(),
+					withObservation(v1beta1.ContainerObservation{
+						ImmutabilityPolicy: &v1beta1.ImmutabilityPolicyObservation{
+							ImmutabilityPeriodSinceCreationInDays: 7,
+							State:                                 "Unlocked",
+							Etag:                                  "etag",
+						},
 					}),
-					v1alpha3test.NewMockAccount(testAccountName).
-						WithSpecWriteConnectionSecretToReference(testNamespace, testAccountName).
-						Account).Build(),
-			},
-			args: args{
-				ctx: ctx,
-				c: newCont().WithSpecProviderRef(testAccountName).
-					WithFinalizer(finalizer).
-					Container,
-			},
-			want: want{
-				err: errors.Wrapf(errors.New("illegal base64 data at input byte 4"),
-					"failed to create client handle: %s, storage account: %s", testContainerName, testAccountName),
+					withConditions(xpv1.Available()),
+				),
+				o: managed.ExternalObservation{ResourceExists: true},
 			},
 		},
-		{
-			name: "Success",
-			fields: fields{
-				Client: fake.NewClientBuilder().WithObjects(
-					newCont().WithSpecProviderRef(testAccountName).WithFinalizer(finalizer).Container,
-					newSecret(testNamespace, testAccountName, map[string][]byte{
-						xpv1.ResourceCredentialsSecretUserKey:     []byte(testAccountName),
-						xpv1.ResourceCredentialsSecretPasswordKey: []byte("dGVzdC1rZXkK"),
-					}),
-					v1alpha3test.NewMockAccount(testAccountName).
-						WithSpecWriteConnectionSecretToReference(testNamespace, testAccountName).
-						Account).Build(),
-			},
-			args: args{
-				ctx: ctx,
-				c: newCont().WithSpecProviderRef(testAccountName).
-					WithFinalizer(finalizer).
-					Container,
+		"NotUpToDate": {
+			reason: "A container whose metadata differs from the Container should not be up to date",
+			client: &fake.MockBlobContainersClient{
+				MockGet: func(_ context.Context, _, _, _ string) (storage.BlobContainer, error) {
+					return storage.BlobContainer{ContainerProperties: &storage.ContainerProperties{PublicAccess: storage.PublicAccessNone}}, nil
+				},
 			},
+			cr: container(withMetadata(map[string]string{"cool": "very"})),
 			want: want{
-				syndel: &containerSyncdeleter{},
+				cr: container(
+					withMetadata(map[string]string{"cool": "very"}),
+					withConditions(xpv1.Available()),
+				),
+				o: managed.ExternalObservation{ResourceExists: true},
 			},
 		},
 	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			m := &containerSyncdeleterMaker{
-				Client: tt.fields.Client,
-			}
-			got, err := m.newSyncdeleter(tt.args.ctx, tt.args.c, time.Minute)
-			if diff := cmp.Diff(tt.want.err, err, test.EquateErrors()); diff != "" {
-				t.Errorf("containerSyncdeleterMaker.newSyncdeleter(): -got error, +want error: \n%s", diff)
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			e := &external{kube: tc.kube, client: tc.client}
+			o, err := e.Observe(context.Background(), tc.cr)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nObserve(...): -want error, +got error:\n%s", tc.reason, diff)
 			}
-			if tt.want.syndel != nil {
-				tt.want.syndel = &containerSyncdeleter{
-					createupdater: &containerCreateUpdater{
-						ContainerOperations: ch,
-						kube:                tt.fields.Client,
-						container:           tt.args.c,
-					},
-					ContainerOperations: ch,
-					kube:                tt.fields.Client,
-					container:           tt.args.c,
-				}
-				// BUG(negz): This test is broken. It appears to intend to compare
-				// unexported fields, but does not. This behaviour was maintained
-				// when porting the test from https://github.com/go-test/deep to cmp.
-				if diff := cmp.Diff(tt.want.syndel, got,
-					cmpopts.IgnoreUnexported(containerSyncdeleter{}),
-					cmpopts.IgnoreUnexported(azblob.ContainerURL{}),
-				); diff != "" {
-					t.Errorf("containerSyncdeleterMaker.newSyncdeleter(): -want, +got:\n%s", diff)
-				}
+			if diff := cmp.Diff(tc.want.o, o); diff != "" {
+				t.Errorf("\n%s\nObserve(...): -want, +got:\n%s", tc.reason, diff)
 			}
-			if tt.want.cont != nil {
-				cont := &v1alpha3.Container{}
-				if err := tt.fields.Client.Get(tt.args.ctx, key, cont); err != nil {
-					t.Errorf("containerSyncdeleterMaker.newSyncdeleter() error validating continer: %v, expected nil", err)
-				}
-				if diff := cmp.Diff(tt.want.cont, got); diff != "" {
-					t.Errorf("containerSyncdeleterMaker.newSyncdeleter() container: -want, +got:\n%s", diff)
-				}
+			if diff := cmp.Diff(tc.want.cr, tc.cr, test.EquateConditions()); diff != "" {
+				t.Errorf("\n%s\nObserve(...): -want, +got:\n%s", tc.reason, diff)
 			}
 		})
 	}
 }
 
-func Test_containerSyncdeleter_delete(t *testing.T) {
-	ctx := context.TODO()
-	errBoom := errors.New("boom")
-
-	type fields struct {
-		createupdater       createupdater
-		ContainerOperations storage.ContainerOperations
-		kube                client.Client
-		container           *v1alpha3.Container
-	}
-	type args struct {
-		ctx context.Context
-	}
-	type want struct {
-		res  reconcile.Result
-		err  error
-		cont *v1alpha3.Container
-	}
-	tests := []struct {
-		name   string
-		fields fields
-		args   args
-		want   want
+func TestCreate(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		client *fake.MockBlobContainersClient
+		want   error
 	}{
-		{
-			name: "DeletionOrphan",
-			fields: fields{
-				kube: test.NewMockClient(),
-				container: v1alpha3test.NewMockContainer(testContainerName).
-					WithSpecDeletionPolicy(xpv1.DeletionOrphan).
-					WithFinalizer(finalizer).Container,
-			},
-			args: args{ctx: ctx},
-			want: want{
-				res: reconcile.Result{},
-				cont: v1alpha3test.NewMockContainer(testContainerName).
-					WithSpecDeletionPolicy(xpv1.DeletionOrphan).
-					WithFinalizers([]string{}).
-					WithStatusConditions(xpv1.Deleting()).
-					Container,
-			},
-		},
-		{
-			name: "DeleteErrorNotFound",
-			fields: fields{
-				kube: test.NewMockClient(),
-				ContainerOperations: &azurestoragefake.MockContainerOperations{
-					MockDelete: func(ctx context.Context) error {
-						return autorest.DetailedError{StatusCode: http.StatusNotFound}
-					},
+		"Successful": {
+			reason: "The container should be created in the storage account with the public access type of the Container",
+			client: &fake.MockBlobContainersClient{
+				MockCreate: func(_ context.Context, rg, acct, name string, c storage.BlobContainer) (storage.BlobContainer, error) {
+					if rg != resourceGroupName || acct != accountName || name != containerName || c.PublicAccess != storage.PublicAccessContainer {
+						return storage.BlobContainer{}, errBoom
+					}
+					return c, nil
 				},
-				container: v1alpha3test.NewMockContainer(testContainerName).
-					WithSpecDeletionPolicy(xpv1.DeletionDelete).
-					WithFinalizer(finalizer).
-					Container,
-			},
-			args: args{ctx: ctx},
-			want: want{
-				res: reconcile.Result{},
-				cont: v1alpha3test.NewMockContainer(testContainerName).
-					WithSpecDeletionPolicy(xpv1.DeletionDelete).
-					WithFinalizers([]string{}).
-					WithStatusConditions(xpv1.Deleting()).
-					Container,
 			},
 		},
-		{
-			name: "DeleteErrorOther",
-			fields: fields{
-				kube: test.NewMockClient(),
-				ContainerOperations: &azurestoragefake.MockContainerOperations{
-					MockDelete: func(ctx context.Context) error {
-						return errBoom
-					},
+		"Failed": {
+			reason: "Errors creating the container should be returned",
+			client: &fake.MockBlobContainersClient{
+				MockCreate: func(_ context.Context, _, _, _ string, _ storage.BlobContainer) (storage.BlobContainer, error) {
+					return storage.BlobContainer{}, errBoom
 				},
-				container: v1alpha3test.NewMockContainer(testContainerName).
-					WithSpecDeletionPolicy(xpv1.DeletionDelete).
-					WithFinalizer(finalizer).Container,
-			},
-			args: args{ctx: ctx},
-			want: want{
-				res: resultRequeue,
-				cont: v1alpha3test.NewMockContainer(testContainerName).
-					WithSpecDeletionPolicy(xpv1.DeletionDelete).
-					WithFinalizer(finalizer).
-					WithStatusConditions(xpv1.Deleting(), xpv1.ReconcileError(errBoom)).
-					Container,
 			},
+			want: errors.Wrap(errBoom, errCreateFailed),
 		},
 	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			csd := &containerSyncdeleter{
-				createupdater:       tt.fields.createupdater,
-				ContainerOperations: tt.fields.ContainerOperations,
-				kube:                tt.fields.kube,
-				container:           tt.fields.container,
-			}
-			got, err := csd.delete(tt.args.ctx)
-			if diff := cmp.Diff(tt.want.err, err, test.EquateErrors()); diff != "" {
-				t.Errorf("containerSyncdeleter.delete(): -want error, +got error:\n%s", diff)
-			}
-			if diff := cmp.Diff(tt.want.res, got); diff != "" {
-				t.Errorf("containerSyncdeleter.delete(): -want, +got:\n%s", diff)
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cr := container(withPublicAccessType("container"))
+			e := &external{client: tc.client}
+			_, err := e.Create(context.Background(), cr)
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nCreate(...): -want error, +got error:\n%s", tc.reason, diff)
 			}
-			if diff := cmp.Diff(tt.want.cont, tt.fields.container, test.EquateConditions()); diff != "" {
-				t.Errorf("containerSyncdeleter.delete() container: -want, +got:\n%s", diff)
+			if diff := cmp.Diff(container(withPublicAccessType("container"), withConditions(xpv1.Creating())), cr, test.EquateConditions()); diff != "" {
+				t.Errorf("\n%s\nCreate(...): -want, +got:\n%s", tc.reason, diff)
 			}
 		})
 	}
 }
 
-func Test_containerSyncdeleter_sync(t *testing.T) {
-	ctx := context.TODO()
-	errBoom := errors.New("boom")
-
-	type fields struct {
-		createupdater       createupdater
-		ContainerOperations storage.ContainerOperations
-		kube                client.Client
-		container           *v1alpha3.Container
-	}
-	type args struct {
-		ctx context.Context
-	}
-	type want struct {
-		res  reconcile.Result
-		err  error
-		cont *v1alpha3.Container
+func TestUpdate(t *testing.T) {
+	updated := func(_ context.Context, _, _, _ string, c storage.BlobContainer) (storage.BlobContainer, error) {
+		return c, nil
 	}
+	unlocked := &v1beta1.ImmutabilityPolicyObservation{ImmutabilityPeriodSinceCreationInDays: 7, State: "Unlocked", Etag: "etag"}
+	locked := &v1beta1.ImmutabilityPolicyObservation{ImmutabilityPeriodSinceCreationInDays: 7, State: "Locked", Etag: "etag"}
 
-	tests := []struct {
-		name   string
-		fields fields
-		args   args
-		want   want
+	cases := map[string]struct {
+		reason string
+		client *fake.MockBlobContainersClient
+		cr     *v1beta1.Container
+		want   error
 	}{
-		{
-			name: "GetErrorNotFound",
-			fields: fields{
-				createupdater: newMockCreateUpdater(),
-				ContainerOperations: &azurestoragefake.MockContainerOperations{
-					MockGet: func(ctx context.Context) (*azblob.PublicAccessType, azblob.Metadata, error) {
-						return nil, nil, newStorageNotFoundError()
-					},
+		"Successful": {
+			reason: "The container should be updated with the access type and metadata of the Container",
+			client: &fake.MockBlobContainersClient{
+				MockUpdate: func(_ context.Context, _, _, _ string, c storage.BlobContainer) (storage.BlobContainer, error) {
+					if c.PublicAccess != storage.PublicAccessBlob || to.String(c.Metadata["cool"]) != "very" {
+						return storage.BlobContainer{}, errBoom
+					}
+					return c, nil
 				},
 			},
-			args: args{ctx: ctx},
-			want: want{},
+			cr: container(withPublicAccessType("blob"), withMetadata(map[string]string{"cool": "very"})),
 		},
-		{
-			name: "GetErrorOther",
-			fields: fields{
-				createupdater: newMockCreateUpdater(),
-				ContainerOperations: &azurestoragefake.MockContainerOperations{
-					MockGet: func(ctx context.Context) (*azblob.PublicAccessType, azblob.Metadata, error) {
-						return nil, nil, errBoom
-					},
+		"Failed": {
+			reason: "Errors updating the container should be returned",
+			client: &fake.MockBlobContainersClient{
+				MockUpdate: func(_ context.Context, _, _, _ string, _ storage.BlobContainer) (storage.BlobContainer, error) {
+					return storage.BlobContainer{}, errBoom
 				},
-				container: v1alpha3test.NewMockContainer(testContainerName).Container,
-				kube:      test.NewMockClient(),
-			},
-			args: args{ctx: ctx},
-			want: want{
-				res: resultRequeue,
-				cont: v1alpha3test.NewMockContainer(testContainerName).
-					WithStatusConditions(xpv1.ReconcileError(errBoom)).
-					Container,
 			},
+			cr:   container(),
+			want: errors.Wrap(errBoom, errUpdateFailed),
 		},
-		{
-			name: "Create",
-			fields: fields{
-				createupdater: newMockCreateUpdater(),
-				ContainerOperations: &azurestoragefake.MockContainerOperations{
-					MockGet: func(ctx context.Context) (*azblob.PublicAccessType, azblob.Metadata, error) {
-						return nil, nil, nil
-					},
+		"CreateAndLockPolicy": {
+			reason: "A missing immutability policy should be created, and locked with the etag of the created policy",
+			client: &fake.MockBlobContainersClient{
+				MockUpdate: updated,
+				MockCreateOrUpdateImmutabilityPolicy: func(_ context.Context, _, _, _ string, ip *storage.ImmutabilityPolicy, ifMatch string) (storage.ImmutabilityPolicy, error) {
+					if ifMatch != "" || to.Int32(ip.ImmutabilityPeriodSinceCreationInDays) != 7 {
+						return storage.ImmutabilityPolicy{}, errBoom
+					}
+					return storage.ImmutabilityPolicy{Etag: to.StringPtr("created")}, nil
+				},
+				MockLockImmutabilityPolicy: func(_ context.Context, _, _, _ string, ifMatch string) (storage.ImmutabilityPolicy, error) {
+					if ifMatch != "created" {
+						return storage.ImmutabilityPolicy{}, errBoom
+					}
+					return storage.ImmutabilityPolicy{}, nil
 				},
-				container: v1alpha3test.NewMockContainer(testContainerName).Container,
-				kube:      test.NewMockClient(),
-			},
-			args: args{ctx: ctx},
-			want: want{
-				res:  reconcile.Result{},
-				cont: v1alpha3test.NewMockContainer(testContainerName).Container,
 			},
+			cr: container(withImmutabilityPolicy(&v1beta1.ImmutabilityPolicy{ImmutabilityPeriodSinceCreationInDays: 7, Locked: to.BoolPtr(true)})),
 		},
-		{
-			name: "Update",
-			fields: fields{
-				createupdater: newMockCreateUpdater(),
-				ContainerOperations: &azurestoragefake.MockContainerOperations{
-					MockGet: func(ctx context.Context) (*azblob.PublicAccessType, azblob.Metadata, error) {
-						return azurestoragefake.PublicAccessTypePtr(azblob.PublicAccessContainer), nil, nil
-					},
+		"UpdatePolicyFailed": {
+			reason: "Errors updating the immutability policy should be returned",
+			client: &fake.MockBlobContainersClient{
+				MockUpdate: updated,
+				MockCreateOrUpdateImmutabilityPolicy: func(_ context.Context, _, _, _ string, _ *storage.ImmutabilityPolicy, _ string) (storage.ImmutabilityPolicy, error) {
+					return storage.ImmutabilityPolicy{}, errBoom
 				},
-				container: v1alpha3test.NewMockContainer(testContainerName).Container,
-				kube:      test.NewMockClient(),
 			},
-			args: args{ctx: ctx},
-			want: want{
-				res:  reconcile.Result{},
-				cont: v1alpha3test.NewMockContainer(testContainerName).Container,
+			cr: container(
+				withImmutabilityPolicy(&v1beta1.ImmutabilityPolicy{ImmutabilityPeriodSinceCreationInDays: 14}),
+				withObservation(v1beta1.ContainerObservation{ImmutabilityPolicy: unlocked}),
+			),
+			want: errors.Wrap(errBoom, errUpdatePolicyFailed),
+		},
+		"LockPolicy": {
+			reason: "An unlocked immutability policy that is up to date should only be locked",
+			client: &fake.MockBlobContainersClient{
+				MockUpdate: updated,
+				MockLockImmutabilityPolicy: func(_ context.Context, _, _, _ string, ifMatch string) (storage.ImmutabilityPolicy, error) {
+					if ifMatch != "etag" {
+						return storage.ImmutabilityPolicy{}, errBoom
+					}
+					return storage.ImmutabilityPolicy{}, nil
+				},
 			},
+			cr: container(
+				withImmutabilityPolicy(&v1beta1.ImmutabilityPolicy{ImmutabilityPeriodSinceCreationInDays: 7, Locked: to.BoolPtr(true)}),
+				withObservation(v1beta1.ContainerObservation{ImmutabilityPolicy: unlocked}),
+			),
 		},
-	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			csd := &containerSyncdeleter{
-				createupdater:       tt.fields.createupdater,
-				ContainerOperations: tt.fields.ContainerOperations,
-				kube:                tt.fields.kube,
-				container:           tt.fields.container,
-			}
-			got, err := csd.sync(tt.args.ctx)
-			if diff := cmp.Diff(tt.want.err, err, test.EquateErrors()); diff != "" {
-				t.Errorf("containerSyncdeleter.sync(): -want error, +got error:\n%s", diff)
-			}
-			if diff := cmp.Diff(tt.want.res, got); diff != "" {
-				t.Errorf("containerSyncdeleter.sync(): -want, +got:\n%s", diff)
-			}
-			if diff := cmp.Diff(tt.want.cont, tt.fields.container, test.EquateConditions()); diff != "" {
-				t.Errorf("containerSyncdeleter.sync() container: -want, +got:\n%s", diff)
-			}
-		})
-	}
-}
-
-func Test_containerCreateUpdater_create(t *testing.T) {
-	ctx := context.TODO()
-	errBoom := errors.New("boom")
-
-	type fields struct {
-		ContainerOperations storage.ContainerOperations
-		kube                client.Client
-		container           *v1alpha3.Container
-	}
-	type args struct {
-		ctx context.Context
-	}
-	type want struct {
-		res  reconcile.Result
-		err  error
-		cont *v1alpha3.Container
-	}
-	tests := []struct {
-		name   string
-		fields fields
-		args   args
-		want   want
-	}{
-		{
-			name: "UpdateFinalizerFailed",
-			fields: fields{
-				container: v1alpha3test.NewMockContainer(testContainerName).Container,
-				kube: &test.MockClient{
-					MockUpdate: func(ctx context.Context, obj client.Object, _ ...client.UpdateOption) error {
-						return errors.New("test-update-error")
-					},
+		"ExtendPolicy": {
+			reason: "A locked immutability policy should be extended",
+			client: &fake.MockBlobContainersClient{
+				MockUpdate: updated,
+				MockExtendImmutabilityPolicy: func(_ context.Context, _, _, _ string, ifMatch string, ip *storage.ImmutabilityPolicy) (storage.ImmutabilityPolicy, error) {
+					if ifMatch != "etag" || to.Int32(ip.ImmutabilityPeriodSinceCreationInDays) != 14 {
+						return storage.ImmutabilityPolicy{}, errBoom
+					}
+					return storage.ImmutabilityPolicy{}, nil
 				},
 			},
-			args: args{ctx: ctx},
-			want: want{
-				res: resultRequeue,
-				err: errors.Wrapf(errors.New("test-update-error"), "failed to update container spec"),
-				cont: v1alpha3test.NewMockContainer(testContainerName).
-					WithFinalizer(finalizer).
-					WithStatusConditions(xpv1.Creating()).
-					Container,
+			cr: container(
+				withImmutabilityPolicy(&v1beta1.ImmutabilityPolicy{ImmutabilityPeriodSinceCreationInDays: 14, Locked: to.BoolPtr(true)}),
+				withObservation(v1beta1.ContainerObservation{ImmutabilityPolicy: locked}),
+			),
+		},
+		"UnlockPolicy": {
+			reason: "Unlocking a locked immutability policy should return an error",
+			client: &fake.MockBlobContainersClient{MockUpdate: updated},
+			cr: container(
+				withImmutabilityPolicy(&v1beta1.ImmutabilityPolicy{ImmutabilityPeriodSinceCreationInDays: 7, Locked: to.BoolPtr(false)}),
+				withObservation(v1beta1.ContainerObservation{ImmutabilityPolicy: locked}),
+			),
+			want: errors.New(errPolicyLocked),
+		},
+		"ShortenPolicy": {
+			reason: "Shortening a locked immutability policy should return an error",
+			client: &fake.MockBlobContainersClient{MockUpdate: updated},
+			cr: container(
+				withImmutabilityPolicy(&v1beta1.ImmutabilityPolicy{ImmutabilityPeriodSinceCreationInDays: 1, Locked: to.BoolPtr(true)}),
+				withObservation(v1beta1.ContainerObservation{ImmutabilityPolicy: locked}),
+			),
+			want: errors.New(errPolicyLocked),
+		},
+		"DeleteEmptyPolicy": {
+			reason: "An unlocked immutability policy should be deleted if the Container specifies an empty policy",
+			client: &fake.MockBlobContainersClient{
+				MockUpdate: updated,
+				MockDeleteImmutabilityPolicy: func(_ context.Context, _, _, _ string, ifMatch string) (storage.ImmutabilityPolicy, error) {
+					if ifMatch != "etag" {
+						return storage.ImmutabilityPolicy{}, errBoom
+					}
+					return storage.ImmutabilityPolicy{}, nil
+				},
 			},
+			cr: container(
+				withImmutabilityPolicy(&v1beta1.ImmutabilityPolicy{}),
+				withObservation(v1beta1.ContainerObservation{ImmutabilityPolicy: unlocked}),
+			),
 		},
-		{
-			name: "CreateFailed",
-			fields: fields{
-				container: v1alpha3test.NewMockContainer(testContainerName).Container,
-				ContainerOperations: &azurestoragefake.MockContainerOperations{
-					MockCreate: func(ctx context.Context, pub azblob.PublicAccessType, meta azblob.Metadata) error {
-						return errBoom
-					},
+		"DeleteRemovedPolicy": {
+			reason: "An unlocked immutability policy should be deleted if it was removed from the Container",
+			client: &fake.MockBlobContainersClient{
+				MockUpdate: updated,
+				MockDeleteImmutabilityPolicy: func(_ context.Context, _, _, _ string, _ string) (storage.ImmutabilityPolicy, error) {
+					return storage.ImmutabilityPolicy{}, errBoom
 				},
-				kube: test.NewMockClient(),
 			},
-			args: args{ctx: ctx},
-			want: want{
-				res: resultRequeue,
-				cont: v1alpha3test.NewMockContainer(testContainerName).
-					WithFinalizer(finalizer).
-					WithStatusConditions(xpv1.Creating(), xpv1.ReconcileError(errBoom)).
-					Container,
+			cr: container(
+				withPolicyAnnotation(),
+				withObservation(v1beta1.ContainerObservation{ImmutabilityPolicy: unlocked}),
+			),
+			want: errors.Wrap(errBoom, errDeletePolicyFailed),
+		},
+		"DeleteLockedPolicy": {
+			reason: "Deleting a locked immutability policy should return an error",
+			client: &fake.MockBlobContainersClient{MockUpdate: updated},
+			cr: container(
+				withPolicyAnnotation(),
+				withObservation(v1beta1.ContainerObservation{ImmutabilityPolicy: locked}),
+			),
+			want: errors.New(errPolicyLocked),
+		},
+		"LegalHold": {
+			reason: "Legal hold tags that are not observed should be set, and those that are not specified cleared",
+			client: &fake.MockBlobContainersClient{
+				MockUpdate: updated,
+				MockSetLegalHold: func(_ context.Context, _, _, _ string, lh storage.LegalHold) (storage.LegalHold, error) {
+					if diff := cmp.Diff(&[]string{"litigation"}, lh.Tags); diff != "" {
+						return storage.LegalHold{}, errBoom
+					}
+					return lh, nil
+				},
+				MockClearLegalHold: func(_ context.Context, _, _, _ string, lh storage.LegalHold) (storage.LegalHold, error) {
+					if diff := cmp.Diff(&[]string{"sec17a4"}, lh.Tags); diff != "" {
+						return storage.LegalHold{}, errBoom
+					}
+					return lh, nil
+				},
 			},
+			cr: container(
+				withLegalHold("audit", "litigation"),
+				withObservation(v1beta1.ContainerObservation{HasLegalHold: true, LegalHoldTags: []string{"audit", "sec17a4"}}),
+			),
 		},
-		{
-			name: "CreateSuccessful",
-			fields: fields{
-				container:           v1alpha3test.NewMockContainer(testContainerName).Container,
-				ContainerOperations: azurestoragefake.NewMockContainerOperations(),
-				kube:                test.NewMockClient(),
-			},
-			args: args{ctx: ctx},
-			want: want{
-				res: reconcile.Result{},
-				cont: v1alpha3test.NewMockContainer(testContainerName).
-					WithFinalizer(finalizer).
-					WithStatusConditions(xpv1.Available(), xpv1.ReconcileSuccess()).
-					Container,
+		"SetLegalHoldFailed": {
+			reason: "Errors setting legal hold tags should be returned",
+			client: &fake.MockBlobContainersClient{
+				MockUpdate: updated,
+				MockSetLegalHold: func(_ context.Context, _, _, _ string, _ storage.LegalHold) (storage.LegalHold, error) {
+					return storage.LegalHold{}, errBoom
+				},
 			},
+			cr:   container(withLegalHold("audit")),
+			want: errors.Wrap(errBoom, errSetLegalHold),
 		},
 	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			ccu := &containerCreateUpdater{
-				ContainerOperations: tt.fields.ContainerOperations,
-				kube:                tt.fields.kube,
-				container:           tt.fields.container,
-			}
-			got, err := ccu.create(tt.args.ctx)
-			if diff := cmp.Diff(tt.want.err, err, test.EquateErrors()); diff != "" {
-				t.Errorf("containerCreateUpdater.create(): -want error, +got error:\n%s", diff)
-			}
-			if diff := cmp.Diff(tt.want.res, got); diff != "" {
-				t.Errorf("containerCreateUpdater.create(): -want, +got:\n%s", diff)
-			}
-			if diff := cmp.Diff(tt.want.cont, tt.fields.container); diff != "" {
-				t.Errorf("containerCreateUpdater.create() container: -want, +got:\n%s", diff)
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			e := &external{client: tc.client}
+			_, err := e.Update(context.Background(), tc.cr)
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nUpdate(...): -want error, +got error:\n%s", tc.reason, diff)
 			}
 		})
 	}
 }
 
-func Test_containerCreateUpdater_update(t *testing.T) {
-	ctx := context.TODO()
-	errBoom := errors.New("boom")
-
-	type fields struct {
-		ContainerOperations storage.ContainerOperations
-		kube                client.Client
-		container           *v1alpha3.Container
-		poll                time.Duration
-	}
-	type args struct {
-		ctx        context.Context
-		accessType *azblob.PublicAccessType
-		meta       azblob.Metadata
-	}
+func TestDelete(t *testing.T) {
 	type want struct {
-		res  reconcile.Result
-		err  error
-		cont *v1alpha3.Container
+		cr  *v1beta1.Container
+		err error
 	}
-	tests := []struct {
-		name   string
-		fields fields
-		args   args
+
+	cases := map[string]struct {
+		reason string
+		client *fake.MockBlobContainersClient
+		cr     *v1beta1.Container
 		want   want
 	}{
-		{
-			name: "NoChange",
-			fields: fields{
-				container: v1alpha3test.NewMockContainer(testContainerName).
-					WithSpecPAC(azblob.PublicAccessContainer).Container,
-				kube: test.NewMockClient(),
-				poll: time.Minute,
-			},
-			args: args{
-				ctx:        ctx,
-				accessType: azurestoragefake.PublicAccessTypePtr(azblob.PublicAccessContainer),
-			},
-			want: want{
-				res: reconcile.Result{RequeueAfter: time.Minute},
-				cont: v1alpha3test.NewMockContainer(testContainerName).
-					WithSpecPAC(azblob.PublicAccessContainer).
-					WithStatusConditions(xpv1.Available(), xpv1.ReconcileSuccess()).
-					Container,
+		"Successful": {
+			reason: "Deleting a container should succeed",
+			client: &fake.MockBlobContainersClient{
+				MockDelete: func(_ context.Context, _, _, _ string) (autorest.Response, error) { return autorest.Response{}, nil },
 			},
+			cr:   container(),
+			want: want{cr: container(withConditions(xpv1.Deleting()))},
 		},
-		{
-			name: "ContainerUpdateFailed",
-			fields: fields{
-				container: v1alpha3test.NewMockContainer(testContainerName).
-					WithSpecPAC(azblob.PublicAccessContainer).
-					WithStatusConditions().
-					Container,
-				ContainerOperations: &azurestoragefake.MockContainerOperations{
-					MockUpdate: func(ctx context.Context, publicAccessType azblob.PublicAccessType, meta azblob.Metadata) error {
-						return errBoom
-					},
+		"AlreadyDeleted": {
+			reason: "Deleting a container that does not exist should succeed",
+			client: &fake.MockBlobContainersClient{
+				MockDelete: func(_ context.Context, _, _, _ string) (autorest.Response, error) {
+					return autorest.Response{}, fake.NotFound()
 				},
-				kube: test.NewMockClient(),
 			},
-			args: args{
-				ctx:        ctx,
-				accessType: azurestoragefake.PublicAccessTypePtr(azblob.PublicAccessContainer),
-				meta: azblob.Metadata{
-					"foo": "bar",
+			cr:   container(),
+			want: want{cr: container(withConditions(xpv1.Deleting()))},
+		},
+		"Failed": {
+			reason: "Errors deleting the container should be returned",
+			client: &fake.MockBlobContainersClient{
+				MockDelete: func(_ context.Context, _, _, _ string) (autorest.Response, error) {
+					return autorest.Response{}, errBoom
 				},
 			},
+			cr: container(),
 			want: want{
-				res: resultRequeue,
-				cont: v1alpha3test.NewMockContainer(testContainerName).
-					WithSpecPAC(azblob.PublicAccessContainer).
-					WithStatusConditions(xpv1.ReconcileError(errBoom)).
-					Container,
+				cr:  container(withConditions(xpv1.Deleting())),
+				err: errors.Wrap(errBoom, errDeleteFailed),
 			},
 		},
-		{
-			name: "ContainerUpdateSuccessful",
-			fields: fields{
-				container: v1alpha3test.NewMockContainer(testContainerName).
-					WithSpecPAC(azblob.PublicAccessContainer).
-					Container,
-				ContainerOperations: azurestoragefake.NewMockContainerOperations(),
-				kube:                test.NewMockClient(),
-				poll:                time.Minute,
-			},
-			args: args{
-				ctx:        ctx,
-				accessType: azurestoragefake.PublicAccessTypePtr(azblob.PublicAccessContainer),
-				meta: azblob.Metadata{
-					"foo": "bar",
-				},
+		"PolicyLocked": {
+			reason: "A container with a locked immutability policy should not be deleted",
+			cr:     container(withObservation(v1beta1.ContainerObservation{ImmutabilityPolicy: &v1beta1.ImmutabilityPolicyObservation{State: "Locked"}})),
+			want: want{
+				cr:  container(withObservation(v1beta1.ContainerObservation{ImmutabilityPolicy: &v1beta1.ImmutabilityPolicyObservation{State: "Locked"}})),
+				err: azure.RefuseDeletion(errors.New(errDeletePolicyLocked)),
 			},
+		},
+		"LegalHold": {
+			reason: "A container with a legal hold should not be deleted",
+			cr:     container(withObservation(v1beta1.ContainerObservation{HasLegalHold: true})),
 			want: want{
-				res: reconcile.Result{RequeueAfter: time.Minute},
-				cont: v1alpha3test.NewMockContainer(testContainerName).
-					WithSpecPAC(azblob.PublicAccessContainer).
-					WithStatusConditions(xpv1.Available(), xpv1.ReconcileSuccess()).
-					Container,
+				cr:  container(withObservation(v1beta1.ContainerObservation{HasLegalHold: true})),
+				err: azure.RefuseDeletion(errors.New(errDeleteLegalHold)),
 			},
 		},
 	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			ccu := &containerCreateUpdater{
-				ContainerOperations: tt.fields.ContainerOperations,
-				kube:                tt.fields.kube,
-				container:           tt.fields.container,
-				poll:                tt.fields.poll,
-			}
-			got, err := ccu.update(tt.args.ctx, tt.args.accessType, tt.args.meta)
-			if diff := cmp.Diff(tt.want.err, err, test.EquateErrors()); diff != "" {
-				t.Errorf("containerCreateUpdater.update(): -want error, +got error:\n%s", diff)
-			}
-			if diff := cmp.Diff(tt.want.res, got); diff != "" {
-				t.Errorf("containerCreateUpdater.update(): -want, +got:\n%s", diff)
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			e := &external{client: tc.client}
+			err := e.Delete(context.Background(), tc.cr)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nDelete(...): -want error, +got error:\n%s", tc.reason, diff)
 			}
-			if diff := cmp.Diff(tt.want.cont, tt.fields.container, test.EquateConditions()); diff != "" {
-				t.Errorf("containerCreateUpdater.update() container: -want, +got:\n%s", diff)
+			if diff := cmp.Diff(tc.want.cr, tc.cr, test.EquateConditions()); diff != "" {
+				t.Errorf("\n%s\nDelete(...): -want, +got:\n%s", tc.reason, diff)
 			}
 		})
 	}
 }
+
+func TestDryRun(t *testing.T) {
+	s := armtest.NewServer()
+	defer s.Close()
+	cr := container()
+
+	got := s.PlanDryRun(t, &connector{kube: s.Kube(nil)}, cr)
+	if diff := cmp.Diff(azure.ReasonPlannedCreate, got.Reason); diff != "" {
+		t.Errorf("PlanDryRun(...): -want Planned reason, +got:\n%s", diff)
+	}
+}
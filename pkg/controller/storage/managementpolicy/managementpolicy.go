@@ -0,0 +1,170 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package managementpolicy
+
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/services/storage/mgmt/2021-04-01/storage"
+	"github.com/Azure/azure-sdk-for-go/services/storage/mgmt/2021-04-01/storage/storageapi"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/connection"
+	"github.com/crossplane/crossplane-runtime/pkg/controller"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	storagev1alpha1 "github.com/crossplane-contrib/provider-azure/apis/storage/v1alpha1"
+	"github.com/crossplane-contrib/provider-azure/apis/v1alpha1"
+	azure "github.com/crossplane-contrib/provider-azure/pkg/clients"
+	azurestorage "github.com/crossplane-contrib/provider-azure/pkg/clients/storage"
+	"github.com/crossplane-contrib/provider-azure/pkg/features"
+)
+
+// Error strings.
+const (
+	errNotManagementPolicy = "managed resource is not a ManagementPolicy"
+	errConnectFailed       = "cannot connect to Azure API"
+	errNoAccount           = "neither spec.forProvider.accountName nor spec.forProvider.resourceGroupName may be empty"
+	errGetFailed           = "cannot get management policy"
+	errCreateFailed        = "cannot create management policy"
+	errUpdateFailed        = "cannot update management policy"
+	errDeleteFailed        = "cannot delete management policy"
+)
+
+// Setup adds a controller that reconciles ManagementPolicies.
+func Setup(mgr ctrl.Manager, o controller.Options) error {
+	name := managed.ControllerName(storagev1alpha1.ManagementPolicyGroupKind)
+
+	cps := []managed.ConnectionPublisher{managed.NewAPISecretPublisher(mgr.GetClient(), mgr.GetScheme())}
+	if o.Features.Enabled(features.EnableAlphaExternalSecretStores) {
+		cps = append(cps, connection.NewDetailsManager(mgr.GetClient(), v1alpha1.StoreConfigGroupVersionKind))
+	}
+
+	recorder := event.NewAPIRecorder(mgr.GetEventRecorderFor(name))
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(name).
+		WithOptions(o.ForControllerRuntime()).
+		For(&storagev1alpha1.ManagementPolicy{}).
+		Complete(azure.NewReconciler(mgr, name, storagev1alpha1.ManagementPolicyGroupVersionKind,
+			managed.WithExternalConnecter(azure.NewConnecter(&connector{kube: mgr.GetClient()}, recorder)),
+			managed.WithReferenceResolver(managed.NewAPISimpleReferenceResolver(mgr.GetClient())),
+			managed.WithPollInterval(o.PollInterval),
+			managed.WithLogger(o.Logger.WithValues("controller", name)),
+			managed.WithRecorder(recorder),
+			managed.WithConnectionPublishers(cps...)))
+}
+
+type connector struct {
+	kube client.Client
+}
+
+func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
+	cr, ok := mg.(*storagev1alpha1.ManagementPolicy)
+	if !ok {
+		return nil, errors.New(errNotManagementPolicy)
+	}
+	creds, auth, err := azure.GetAuthInfo(ctx, c.kube, cr)
+	if err != nil {
+		return nil, errors.Wrap(err, errConnectFailed)
+	}
+	cl := storage.NewManagementPoliciesClientWithBaseURI(creds[azure.CredentialsKeyResourceManagerEndpointURL], creds[azure.CredentialsKeySubscriptionID])
+	azure.ConfigureClient(&cl.Client, auth)
+	return &external{kube: c.kube, client: cl}, nil
+}
+
+// A storage account has at most one management policy, which Azure always
+// names "default". The policy is therefore addressed by its account alone and
+// the external name of a ManagementPolicy is not used.
+type external struct {
+	kube   client.Client
+	client storageapi.ManagementPoliciesClientAPI
+}
+
+func (e *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	cr, ok := mg.(*storagev1alpha1.ManagementPolicy)
+	if !ok {
+		return managed.ExternalObservation{}, errors.New(errNotManagementPolicy)
+	}
+	p := cr.Spec.ForProvider
+	if p.ResourceGroupName == "" || p.AccountName == "" {
+		return managed.ExternalObservation{}, errors.New(errNoAccount)
+	}
+	mp, err := e.client.Get(ctx, p.ResourceGroupName, p.AccountName)
+	if azure.IsNotFound(err) {
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+	if err != nil {
+		return managed.ExternalObservation{}, errors.Wrap(err, errGetFailed)
+	}
+
+	owners := len(cr.GetOwnerReferences())
+	if err := azurestorage.AddAccountOwner(ctx, e.kube, cr, cr.Spec.ForProvider.AccountNameRef); err != nil {
+		return managed.ExternalObservation{}, err
+	}
+
+	current := cr.Spec.ForProvider.DeepCopy()
+	azurestorage.LateInitializeManagementPolicy(&cr.Spec.ForProvider, mp)
+	cr.Status.AtProvider = azurestorage.GenerateManagementPolicyObservation(mp)
+	cr.Status.SetConditions(xpv1.Available())
+
+	diff := azurestorage.IsManagementPolicyUpToDate(cr.Spec.ForProvider, mp)
+	azure.ReportDiff(ctx, diff)
+	return managed.ExternalObservation{
+		ResourceExists:          true,
+		ResourceUpToDate:        diff.UpToDate(),
+		ResourceLateInitialized: len(cr.GetOwnerReferences()) != owners || !cmp.Equal(current, &cr.Spec.ForProvider),
+	}, nil
+}
+
+func (e *external) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
+	cr, ok := mg.(*storagev1alpha1.ManagementPolicy)
+	if !ok {
+		return managed.ExternalCreation{}, errors.New(errNotManagementPolicy)
+	}
+	cr.Status.SetConditions(xpv1.Creating())
+	p := cr.Spec.ForProvider
+	_, err := e.client.CreateOrUpdate(ctx, p.ResourceGroupName, p.AccountName, azurestorage.NewManagementPolicy(p))
+	return managed.ExternalCreation{}, errors.Wrap(err, errCreateFailed)
+}
+
+func (e *external) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
+	cr, ok := mg.(*storagev1alpha1.ManagementPolicy)
+	if !ok {
+		return managed.ExternalUpdate{}, errors.New(errNotManagementPolicy)
+	}
+	p := cr.Spec.ForProvider
+	_, err := e.client.CreateOrUpdate(ctx, p.ResourceGroupName, p.AccountName, azurestorage.NewManagementPolicy(p))
+	return managed.ExternalUpdate{}, errors.Wrap(err, errUpdateFailed)
+}
+
+func (e *external) Delete(ctx context.Context, mg resource.Managed) error {
+	cr, ok := mg.(*storagev1alpha1.ManagementPolicy)
+	if !ok {
+		return errors.New(errNotManagementPolicy)
+	}
+	cr.Status.SetConditions(xpv1.Deleting())
+	p := cr.Spec.ForProvider
+	_, err := e.client.Delete(ctx, p.ResourceGroupName, p.AccountName)
+	return errors.Wrap(resource.Ignore(azure.IsNotFound, err), errDeleteFailed)
+}
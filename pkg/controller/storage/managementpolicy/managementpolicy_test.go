@@ -0,0 +1,391 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package managementpolicy
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/services/storage/mgmt/2021-04-01/storage"
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/date"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane-contrib/provider-azure/apis/storage/v1alpha1"
+	"github.com/crossplane-contrib/provider-azure/apis/storage/v1beta1"
+	azure "github.com/crossplane-contrib/provider-azure/pkg/clients"
+	"github.com/crossplane-contrib/provider-azure/pkg/clients/armtest"
+	azurestorage "github.com/crossplane-contrib/provider-azure/pkg/clients/storage"
+	"github.com/crossplane-contrib/provider-azure/pkg/clients/storage/fake"
+)
+
+const (
+	policyName        = "cool-policy"
+	accountName       = "coolaccount"
+	resourceGroupName = "cool-rg"
+	ruleName          = "cool-rule"
+)
+
+var errBoom = errors.New("boom")
+
+var _ managed.ExternalClient = &external{}
+var _ managed.ExternalConnecter = &connector{}
+
+type policyModifier func(*v1alpha1.ManagementPolicy)
+
+func withConditions(c ...xpv1.Condition) policyModifier {
+	return func(cr *v1alpha1.ManagementPolicy) { cr.Status.ConditionedStatus.Conditions = c }
+}
+
+func withAccountName(n string) policyModifier {
+	return func(cr *v1alpha1.ManagementPolicy) { cr.Spec.ForProvider.AccountName = n }
+}
+
+func withAccountNameRef(n string) policyModifier {
+	return func(cr *v1alpha1.ManagementPolicy) { cr.Spec.ForProvider.AccountNameRef = &xpv1.Reference{Name: n} }
+}
+
+func withEnabled(e *bool) policyModifier {
+	return func(cr *v1alpha1.ManagementPolicy) { cr.Spec.ForProvider.Rules[0].Enabled = e }
+}
+
+func withCoolDays(d int) policyModifier {
+	return func(cr *v1alpha1.ManagementPolicy) {
+		cr.Spec.ForProvider.Rules[0].Definition.Actions.BaseBlob.TierToCool.DaysAfterModificationGreaterThan = &d
+	}
+}
+
+func withOwner(a *v1beta1.Account) policyModifier {
+	return func(cr *v1alpha1.ManagementPolicy) {
+		meta.AddOwnerReference(cr, azurestorage.AccountOwnerReference(a))
+	}
+}
+
+func withObservation(o v1alpha1.ManagementPolicyObservation) policyModifier {
+	return func(cr *v1alpha1.ManagementPolicy) { cr.Status.AtProvider = o }
+}
+
+func managementPolicy(m ...policyModifier) *v1alpha1.ManagementPolicy {
+	cr := &v1alpha1.ManagementPolicy{ObjectMeta: metav1.ObjectMeta{Name: policyName}}
+	cr.Spec.ForProvider.ResourceGroupName = resourceGroupName
+	cr.Spec.ForProvider.AccountName = accountName
+	cr.Spec.ForProvider.Rules = []v1alpha1.ManagementPolicyRule{{
+		Name: ruleName,
+		Definition: v1alpha1.ManagementPolicyDefinition{
+			Actions: v1alpha1.ManagementPolicyActions{
+				BaseBlob: &v1alpha1.ManagementPolicyBaseBlob{
+					TierToCool: &v1alpha1.DateAfterModification{DaysAfterModificationGreaterThan: to.IntPtr(30)},
+				},
+			},
+		},
+	}}
+	for _, f := range m {
+		f(cr)
+	}
+	return cr
+}
+
+// policy returns the Azure management policy of an unmodified
+// ManagementPolicy.
+func policy(enabled bool) storage.ManagementPolicy {
+	return storage.ManagementPolicy{
+		ID: to.StringPtr("id"),
+		ManagementPolicyProperties: &storage.ManagementPolicyProperties{
+			Policy: &storage.ManagementPolicySchema{Rules: &[]storage.ManagementPolicyRule{{
+				Name:    to.StringPtr(ruleName),
+				Enabled: to.BoolPtr(enabled),
+				Type:    to.StringPtr("Lifecycle"),
+				Definition: &storage.ManagementPolicyDefinition{
+					Actions: &storage.ManagementPolicyAction{
+						BaseBlob: &storage.ManagementPolicyBaseBlob{
+							TierToCool: &storage.DateAfterModification{DaysAfterModificationGreaterThan: to.Float64Ptr(30)},
+						},
+					},
+				},
+			}}},
+		},
+	}
+}
+
+func TestObserve(t *testing.T) {
+	modified := time.Date(2022, 4, 1, 0, 0, 0, 0, time.UTC)
+
+	type want struct {
+		cr  *v1alpha1.ManagementPolicy
+		o   managed.ExternalObservation
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		kube   client.Client
+		client *fake.MockManagementPoliciesClient
+		cr     *v1alpha1.ManagementPolicy
+		want   want
+	}{
+		"NoAccount": {
+			reason: "A ManagementPolicy whose storage account is not resolved should return an error",
+			cr:     managementPolicy(withAccountName("")),
+			want: want{
+				cr:  managementPolicy(withAccountName("")),
+				err: errors.New(errNoAccount),
+			},
+		},
+		"NotFound": {
+			reason: "A storage account without a management policy should be reported as such",
+			client: &fake.MockManagementPoliciesClient{
+				MockGet: func(_ context.Context, _, _ string) (storage.ManagementPolicy, error) {
+					return storage.ManagementPolicy{}, fake.NotFound()
+				},
+			},
+			cr:   managementPolicy(),
+			want: want{cr: managementPolicy()},
+		},
+		"GetFailed": {
+			reason: "Errors getting the management policy should be returned",
+			client: &fake.MockManagementPoliciesClient{
+				MockGet: func(_ context.Context, _, _ string) (storage.ManagementPolicy, error) {
+					return storage.ManagementPolicy{}, errBoom
+				},
+			},
+			cr: managementPolicy(),
+			want: want{
+				cr:  managementPolicy(),
+				err: errors.Wrap(errBoom, errGetFailed),
+			},
+		},
+		"OwnerAddedAndLateInitialized": {
+			reason: "A management policy should be owned by its storage account, and its rules late-initialized",
+			kube:   &test.MockClient{MockGet: fake.GetAccount(fake.Account(accountName))},
+			client: &fake.MockManagementPoliciesClient{
+				MockGet: func(_ context.Context, rg, acct string) (storage.ManagementPolicy, error) {
+					if rg != resourceGroupName || acct != accountName {
+						return storage.ManagementPolicy{}, errBoom
+					}
+					mp := policy(true)
+					mp.LastModifiedTime = &date.Time{Time: modified}
+					return mp, nil
+				},
+			},
+			cr: managementPolicy(withAccountNameRef(accountName)),
+			want: want{
+				cr: managementPolicy(
+					withAccountNameRef(accountName),
+					withOwner(fake.Account(accountName)),
+					withEnabled(to.BoolPtr(true)),
+					withObservation(v1alpha1.ManagementPolicyObservation{ID: "id", LastModifiedTime: &metav1.Time{Time: modified}}),
+					withConditions(xpv1.Available()),
+				),
+				o: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true, ResourceLateInitialized: true},
+			},
+		},
+		"NotUpToDate": {
+			reason: "A management policy whose rules differ from the ManagementPolicy should not be up to date",
+			client: &fake.MockManagementPoliciesClient{
+				MockGet: func(_ context.Context, _, _ string) (storage.ManagementPolicy, error) {
+					return policy(false), nil
+				},
+			},
+			cr: managementPolicy(withEnabled(to.BoolPtr(false)), withCoolDays(60)),
+			want: want{
+				cr: managementPolicy(
+					withEnabled(to.BoolPtr(false)),
+					withCoolDays(60),
+					withObservation(v1alpha1.ManagementPolicyObservation{ID: "id"}),
+					withConditions(xpv1.Available()),
+				),
+				o: managed.ExternalObservation{ResourceExists: true},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			e := &external{kube: tc.kube, client: tc.client}
+			o, err := e.Observe(context.Background(), tc.cr)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nObserve(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.o, o); diff != "" {
+				t.Errorf("\n%s\nObserve(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.cr, tc.cr, test.EquateConditions()); diff != "" {
+				t.Errorf("\n%s\nObserve(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestCreate(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		client *fake.MockManagementPoliciesClient
+		want   error
+	}{
+		"Successful": {
+			reason: "The management policy should be created with the rules of the ManagementPolicy",
+			client: &fake.MockManagementPoliciesClient{
+				MockCreateOrUpdate: func(_ context.Context, rg, acct string, mp storage.ManagementPolicy) (storage.ManagementPolicy, error) {
+					if rg != resourceGroupName || acct != accountName {
+						return storage.ManagementPolicy{}, errBoom
+					}
+					want := policy(true)
+					want.ID = nil
+					if diff := cmp.Diff(want, mp); diff != "" {
+						t.Errorf("CreateOrUpdate(...): -want, +got:\n%s", diff)
+					}
+					return mp, nil
+				},
+			},
+		},
+		"Failed": {
+			reason: "Errors creating the management policy should be returned",
+			client: &fake.MockManagementPoliciesClient{
+				MockCreateOrUpdate: func(_ context.Context, _, _ string, _ storage.ManagementPolicy) (storage.ManagementPolicy, error) {
+					return storage.ManagementPolicy{}, errBoom
+				},
+			},
+			want: errors.Wrap(errBoom, errCreateFailed),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cr := managementPolicy()
+			e := &external{client: tc.client}
+			_, err := e.Create(context.Background(), cr)
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nCreate(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(managementPolicy(withConditions(xpv1.Creating())), cr, test.EquateConditions()); diff != "" {
+				t.Errorf("\n%s\nCreate(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		client *fake.MockManagementPoliciesClient
+		want   error
+	}{
+		"Successful": {
+			reason: "The rules of the management policy should be replaced with those of the ManagementPolicy",
+			client: &fake.MockManagementPoliciesClient{
+				MockCreateOrUpdate: func(_ context.Context, _, _ string, mp storage.ManagementPolicy) (storage.ManagementPolicy, error) {
+					rules := *mp.Policy.Rules
+					if to.Bool(rules[0].Enabled) || to.Float64(rules[0].Definition.Actions.BaseBlob.TierToCool.DaysAfterModificationGreaterThan) != 60 {
+						return storage.ManagementPolicy{}, errBoom
+					}
+					return mp, nil
+				},
+			},
+		},
+		"Failed": {
+			reason: "Errors updating the management policy should be returned",
+			client: &fake.MockManagementPoliciesClient{
+				MockCreateOrUpdate: func(_ context.Context, _, _ string, _ storage.ManagementPolicy) (storage.ManagementPolicy, error) {
+					return storage.ManagementPolicy{}, errBoom
+				},
+			},
+			want: errors.Wrap(errBoom, errUpdateFailed),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			e := &external{client: tc.client}
+			_, err := e.Update(context.Background(), managementPolicy(withEnabled(to.BoolPtr(false)), withCoolDays(60)))
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nUpdate(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestDelete(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		client *fake.MockManagementPoliciesClient
+		want   error
+	}{
+		"Successful": {
+			reason: "Deleting a management policy should succeed",
+			client: &fake.MockManagementPoliciesClient{
+				MockDelete: func(_ context.Context, rg, acct string) (autorest.Response, error) {
+					if rg != resourceGroupName || acct != accountName {
+						return autorest.Response{}, errBoom
+					}
+					return autorest.Response{}, nil
+				},
+			},
+		},
+		"AlreadyDeleted": {
+			reason: "Deleting a management policy that does not exist should succeed",
+			client: &fake.MockManagementPoliciesClient{
+				MockDelete: func(_ context.Context, _, _ string) (autorest.Response, error) {
+					return autorest.Response{}, fake.NotFound()
+				},
+			},
+		},
+		"Failed": {
+			reason: "Errors deleting the management policy should be returned",
+			client: &fake.MockManagementPoliciesClient{
+				MockDelete: func(_ context.Context, _, _ string) (autorest.Response, error) {
+					return autorest.Response{}, errBoom
+				},
+			},
+			want: errors.Wrap(errBoom, errDeleteFailed),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cr := managementPolicy()
+			e := &external{client: tc.client}
+			err := e.Delete(context.Background(), cr)
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nDelete(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(managementPolicy(withConditions(xpv1.Deleting())), cr, test.EquateConditions()); diff != "" {
+				t.Errorf("\n%s\nDelete(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestDryRun(t *testing.T) {
+	s := armtest.NewServer()
+	defer s.Close()
+	cr := managementPolicy()
+
+	got := s.PlanDryRun(t, &connector{kube: s.Kube(nil)}, cr)
+	if diff := cmp.Diff(azure.ReasonPlannedCreate, got.Reason); diff != "" {
+		t.Errorf("PlanDryRun(...): -want Planned reason, +got:\n%s", diff)
+	}
+}
@@ -0,0 +1,131 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package tracing exports OpenTelemetry traces of the reconciles of managed
+// resources and the Azure requests they make.
+package tracing
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+	"go.opentelemetry.io/otel/trace"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	// ServiceName is the name under which the provider reports its traces.
+	ServiceName = "provider-azure"
+
+	// InstrumentationName is the name of the tracer used by the provider.
+	InstrumentationName = "github.com/crossplane-contrib/provider-azure"
+
+	errCreateExporter = "cannot create OTLP trace exporter"
+)
+
+// Options configure the export of traces.
+type Options struct {
+	// Endpoint is the host and port of the OTLP/HTTP collector traces are
+	// exported to, e.g. localhost:4318.
+	Endpoint string
+
+	// Insecure exports traces over plain HTTP rather than HTTPS.
+	Insecure bool
+
+	// SampleRatio is the fraction of reconciles that are traced.
+	SampleRatio float64
+}
+
+// Setup registers a global tracer provider that exports traces to the OTLP
+// collector described by the supplied options. It returns a function that
+// flushes any pending traces and stops exporting them.
+func Setup(ctx context.Context, o Options) (func(context.Context) error, error) {
+	opts := []otlptracehttp.Option{otlptracehttp.WithEndpoint(o.Endpoint)}
+	if o.Insecure {
+		opts = append(opts, otlptracehttp.WithInsecure())
+	}
+	exp, err := otlptracehttp.New(ctx, opts...)
+	if err != nil {
+		return nil, errors.Wrap(err, errCreateExporter)
+	}
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exp),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(o.SampleRatio))),
+		sdktrace.WithResource(resource.NewSchemaless(semconv.ServiceNameKey.String(ServiceName))),
+	)
+	otel.SetTracerProvider(tp)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	return tp.Shutdown, nil
+}
+
+// Tracer returns the tracer used by the provider. Spans it starts are not
+// recorded unless Setup was called.
+func Tracer() trace.Tracer {
+	return otel.Tracer(InstrumentationName)
+}
+
+// NewReconciler wraps the supplied Reconciler such that each reconcile is
+// covered by a span. Spans started during the reconcile, including those of
+// any Azure requests, are its children.
+func NewReconciler(name string, r reconcile.Reconciler) reconcile.Reconciler {
+	return &reconciler{name: name, wrapped: r}
+}
+
+type reconciler struct {
+	name    string
+	wrapped reconcile.Reconciler
+}
+
+func (r *reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	ctx, span := Tracer().Start(ctx, "Reconcile "+r.name, trace.WithAttributes(
+		attribute.String("controller", r.name),
+		attribute.String("name", req.Name),
+	))
+	defer span.End()
+
+	result, err := r.wrapped.Reconcile(ctx, req)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.SetAttributes(
+		attribute.Bool("requeue", result.Requeue),
+		attribute.String("requeueAfter", result.RequeueAfter.String()),
+	)
+	return result, err
+}
+
+// StartSpan starts a span with the supplied name as a child of any span in
+// the supplied context. The returned function ends the span, recording the
+// error it is passed, if any.
+func StartSpan(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, func(error)) {
+	ctx, span := Tracer().Start(ctx, name, trace.WithAttributes(attrs...))
+	return ctx, func(err error) {
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+	}
+}
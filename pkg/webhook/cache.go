@@ -0,0 +1,40 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	"github.com/crossplane-contrib/provider-azure/apis/cache/v1beta1"
+)
+
+// +kubebuilder:webhook:verbs=create;update,path=/validate-cache-azure-crossplane-io-v1beta1-redis,mutating=false,failurePolicy=fail,groups=cache.azure.crossplane.io,resources=redis,versions=v1beta1,name=redis.cache.azure.crossplane.io,sideEffects=None,admissionReviewVersions=v1
+
+func redis() *validator {
+	return &validator{
+		object: &v1beta1.Redis{},
+		kind:   v1beta1.RedisGroupVersionKind.GroupKind(),
+		validateUpdate: func(old, mg resource.Managed) field.ErrorList {
+			o, r := old.(*v1beta1.Redis).Spec.ForProvider, mg.(*v1beta1.Redis).Spec.ForProvider
+			p := field.NewPath("spec", "forProvider")
+			errs := immutable(p.Child("resourceGroupName"), o.ResourceGroupName, r.ResourceGroupName)
+			return append(errs, immutable(p.Child("location"), o.Location, r.Location)...)
+		},
+	}
+}
@@ -0,0 +1,40 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	"github.com/crossplane-contrib/provider-azure/apis/compute/v1alpha3"
+)
+
+// +kubebuilder:webhook:verbs=create;update,path=/validate-compute-azure-crossplane-io-v1alpha3-akscluster,mutating=false,failurePolicy=fail,groups=compute.azure.crossplane.io,resources=aksclusters,versions=v1alpha3,name=aksclusters.compute.azure.crossplane.io,sideEffects=None,admissionReviewVersions=v1
+
+func aksCluster() *validator {
+	return &validator{
+		object: &v1alpha3.AKSCluster{},
+		kind:   v1alpha3.AKSClusterGroupVersionKind.GroupKind(),
+		validateUpdate: func(old, mg resource.Managed) field.ErrorList {
+			o, c := old.(*v1alpha3.AKSCluster).Spec, mg.(*v1alpha3.AKSCluster).Spec
+			p := field.NewPath("spec")
+			errs := immutable(p.Child("resourceGroupName"), o.ResourceGroupName, c.ResourceGroupName)
+			return append(errs, immutable(p.Child("location"), o.Location, c.Location)...)
+		},
+	}
+}
@@ -0,0 +1,173 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	"github.com/crossplane-contrib/provider-azure/apis/database/v1alpha3"
+	"github.com/crossplane-contrib/provider-azure/apis/database/v1beta1"
+	"github.com/crossplane-contrib/provider-azure/pkg/clients/database"
+)
+
+// +kubebuilder:webhook:verbs=create;update,path=/validate-database-azure-crossplane-io-v1beta1-mysqlserver,mutating=false,failurePolicy=fail,groups=database.azure.crossplane.io,resources=mysqlservers,versions=v1beta1,name=mysqlservers.database.azure.crossplane.io,sideEffects=None,admissionReviewVersions=v1
+
+func mySQLServer() *validator {
+	return &validator{
+		object: &v1beta1.MySQLServer{},
+		kind:   v1beta1.MySQLServerGroupVersionKind.GroupKind(),
+		validate: func(mg resource.Managed) field.ErrorList {
+			return database.ValidateSQLServerParameters(&mg.(*v1beta1.MySQLServer).Spec.ForProvider, field.NewPath("spec", "forProvider"))
+		},
+		validateUpdate: func(old, mg resource.Managed) field.ErrorList {
+			return validateSQLServerUpdate(old.(*v1beta1.MySQLServer).Spec.ForProvider, mg.(*v1beta1.MySQLServer).Spec.ForProvider)
+		},
+	}
+}
+
+// +kubebuilder:webhook:verbs=create;update,path=/validate-database-azure-crossplane-io-v1beta1-postgresqlserver,mutating=false,failurePolicy=fail,groups=database.azure.crossplane.io,resources=postgresqlservers,versions=v1beta1,name=postgresqlservers.database.azure.crossplane.io,sideEffects=None,admissionReviewVersions=v1
+
+func postgreSQLServer() *validator {
+	return &validator{
+		object: &v1beta1.PostgreSQLServer{},
+		kind:   v1beta1.PostgreSQLServerGroupVersionKind.GroupKind(),
+		validate: func(mg resource.Managed) field.ErrorList {
+			return database.ValidateSQLServerParameters(&mg.(*v1beta1.PostgreSQLServer).Spec.ForProvider, field.NewPath("spec", "forProvider"))
+		},
+		validateUpdate: func(old, mg resource.Managed) field.ErrorList {
+			return validateSQLServerUpdate(old.(*v1beta1.PostgreSQLServer).Spec.ForProvider, mg.(*v1beta1.PostgreSQLServer).Spec.ForProvider)
+		},
+	}
+}
+
+func validateSQLServerUpdate(o, s v1beta1.SQLServerParameters) field.ErrorList {
+	p := field.NewPath("spec", "forProvider")
+	errs := immutable(p.Child("resourceGroupName"), o.ResourceGroupName, s.ResourceGroupName)
+	errs = append(errs, immutable(p.Child("location"), o.Location, s.Location)...)
+	return append(errs, immutable(p.Child("administratorLogin"), o.AdministratorLogin, s.AdministratorLogin)...)
+}
+
+// +kubebuilder:webhook:verbs=create;update,path=/validate-database-azure-crossplane-io-v1beta1-mysqlserverconfiguration,mutating=false,failurePolicy=fail,groups=database.azure.crossplane.io,resources=mysqlserverconfigurations,versions=v1beta1,name=mysqlserverconfigurations.database.azure.crossplane.io,sideEffects=None,admissionReviewVersions=v1
+
+func mySQLServerConfiguration() *validator {
+	return &validator{
+		object: &v1beta1.MySQLServerConfiguration{},
+		kind:   v1beta1.MySQLServerConfigurationGroupVersionKind.GroupKind(),
+		validateUpdate: func(old, mg resource.Managed) field.ErrorList {
+			return validateSQLServerConfigurationUpdate(old.(*v1beta1.MySQLServerConfiguration).Spec.ForProvider, mg.(*v1beta1.MySQLServerConfiguration).Spec.ForProvider)
+		},
+	}
+}
+
+// +kubebuilder:webhook:verbs=create;update,path=/validate-database-azure-crossplane-io-v1beta1-postgresqlserverconfiguration,mutating=false,failurePolicy=fail,groups=database.azure.crossplane.io,resources=postgresqlserverconfigurations,versions=v1beta1,name=postgresqlserverconfigurations.database.azure.crossplane.io,sideEffects=None,admissionReviewVersions=v1
+
+func postgreSQLServerConfiguration() *validator {
+	return &validator{
+		object: &v1beta1.PostgreSQLServerConfiguration{},
+		kind:   v1beta1.PostgreSQLServerConfigurationGroupVersionKind.GroupKind(),
+		validateUpdate: func(old, mg resource.Managed) field.ErrorList {
+			return validateSQLServerConfigurationUpdate(old.(*v1beta1.PostgreSQLServerConfiguration).Spec.ForProvider, mg.(*v1beta1.PostgreSQLServerConfiguration).Spec.ForProvider)
+		},
+	}
+}
+
+func validateSQLServerConfigurationUpdate(o, c v1beta1.SQLServerConfigurationParameters) field.ErrorList {
+	p := field.NewPath("spec", "forProvider")
+	errs := immutable(p.Child("resourceGroupName"), o.ResourceGroupName, c.ResourceGroupName)
+	errs = append(errs, immutable(p.Child("serverName"), o.ServerName, c.ServerName)...)
+	return append(errs, immutable(p.Child("name"), o.Name, c.Name)...)
+}
+
+// +kubebuilder:webhook:verbs=create;update,path=/validate-database-azure-crossplane-io-v1alpha3-mysqlserverfirewallrule,mutating=false,failurePolicy=fail,groups=database.azure.crossplane.io,resources=mysqlserverfirewallrules,versions=v1alpha3,name=mysqlserverfirewallrules.database.azure.crossplane.io,sideEffects=None,admissionReviewVersions=v1
+
+func mySQLServerFirewallRule() *validator {
+	return &validator{
+		object: &v1alpha3.MySQLServerFirewallRule{},
+		kind:   v1alpha3.MySQLServerFirewallRuleGroupVersionKind.GroupKind(),
+		validateUpdate: func(old, mg resource.Managed) field.ErrorList {
+			return validateFirewallRuleUpdate(old.(*v1alpha3.MySQLServerFirewallRule).Spec.ForProvider, mg.(*v1alpha3.MySQLServerFirewallRule).Spec.ForProvider)
+		},
+	}
+}
+
+// +kubebuilder:webhook:verbs=create;update,path=/validate-database-azure-crossplane-io-v1alpha3-postgresqlserverfirewallrule,mutating=false,failurePolicy=fail,groups=database.azure.crossplane.io,resources=postgresqlserverfirewallrules,versions=v1alpha3,name=postgresqlserverfirewallrules.database.azure.crossplane.io,sideEffects=None,admissionReviewVersions=v1
+
+func postgreSQLServerFirewallRule() *validator {
+	return &validator{
+		object: &v1alpha3.PostgreSQLServerFirewallRule{},
+		kind:   v1alpha3.PostgreSQLServerFirewallRuleGroupVersionKind.GroupKind(),
+		validateUpdate: func(old, mg resource.Managed) field.ErrorList {
+			return validateFirewallRuleUpdate(old.(*v1alpha3.PostgreSQLServerFirewallRule).Spec.ForProvider, mg.(*v1alpha3.PostgreSQLServerFirewallRule).Spec.ForProvider)
+		},
+	}
+}
+
+func validateFirewallRuleUpdate(o, r v1alpha3.FirewallRuleParameters) field.ErrorList {
+	p := field.NewPath("spec", "forProvider")
+	errs := immutable(p.Child("resourceGroupName"), o.ResourceGroupName, r.ResourceGroupName)
+	return append(errs, immutable(p.Child("serverName"), o.ServerName, r.ServerName)...)
+}
+
+// +kubebuilder:webhook:verbs=create;update,path=/validate-database-azure-crossplane-io-v1alpha3-mysqlservervirtualnetworkrule,mutating=false,failurePolicy=fail,groups=database.azure.crossplane.io,resources=mysqlservervirtualnetworkrules,versions=v1alpha3,name=mysqlservervirtualnetworkrules.database.azure.crossplane.io,sideEffects=None,admissionReviewVersions=v1
+
+func mySQLServerVirtualNetworkRule() *validator {
+	return &validator{
+		object: &v1alpha3.MySQLServerVirtualNetworkRule{},
+		kind:   v1alpha3.MySQLServerVirtualNetworkRuleGroupVersionKind.GroupKind(),
+		validateUpdate: func(old, mg resource.Managed) field.ErrorList {
+			o, r := old.(*v1alpha3.MySQLServerVirtualNetworkRule).Spec, mg.(*v1alpha3.MySQLServerVirtualNetworkRule).Spec
+			return validateVirtualNetworkRuleUpdate(o.ResourceGroupName, r.ResourceGroupName, o.ServerName, r.ServerName)
+		},
+	}
+}
+
+// +kubebuilder:webhook:verbs=create;update,path=/validate-database-azure-crossplane-io-v1alpha3-postgresqlservervirtualnetworkrule,mutating=false,failurePolicy=fail,groups=database.azure.crossplane.io,resources=postgresqlservervirtualnetworkrules,versions=v1alpha3,name=postgresqlservervirtualnetworkrules.database.azure.crossplane.io,sideEffects=None,admissionReviewVersions=v1
+
+func postgreSQLServerVirtualNetworkRule() *validator {
+	return &validator{
+		object: &v1alpha3.PostgreSQLServerVirtualNetworkRule{},
+		kind:   v1alpha3.PostgreSQLServerVirtualNetworkRuleGroupVersionKind.GroupKind(),
+		validateUpdate: func(old, mg resource.Managed) field.ErrorList {
+			o, r := old.(*v1alpha3.PostgreSQLServerVirtualNetworkRule).Spec, mg.(*v1alpha3.PostgreSQLServerVirtualNetworkRule).Spec
+			return validateVirtualNetworkRuleUpdate(o.ResourceGroupName, r.ResourceGroupName, o.ServerName, r.ServerName)
+		},
+	}
+}
+
+func validateVirtualNetworkRuleUpdate(oldResourceGroupName, resourceGroupName, oldServerName, serverName string) field.ErrorList {
+	p := field.NewPath("spec")
+	errs := immutable(p.Child("resourceGroupName"), oldResourceGroupName, resourceGroupName)
+	return append(errs, immutable(p.Child("serverName"), oldServerName, serverName)...)
+}
+
+// +kubebuilder:webhook:verbs=create;update,path=/validate-database-azure-crossplane-io-v1alpha3-cosmosdbaccount,mutating=false,failurePolicy=fail,groups=database.azure.crossplane.io,resources=cosmosdbaccounts,versions=v1alpha3,name=cosmosdbaccounts.database.azure.crossplane.io,sideEffects=None,admissionReviewVersions=v1
+
+func cosmosDBAccount() *validator {
+	return &validator{
+		object: &v1alpha3.CosmosDBAccount{},
+		kind:   v1alpha3.CosmosDBAccountGroupVersionKind.GroupKind(),
+		validateUpdate: func(old, mg resource.Managed) field.ErrorList {
+			o, a := old.(*v1alpha3.CosmosDBAccount).Spec.ForProvider, mg.(*v1alpha3.CosmosDBAccount).Spec.ForProvider
+			p := field.NewPath("spec", "forProvider")
+			errs := immutable(p.Child("resourceGroupName"), o.ResourceGroupName, a.ResourceGroupName)
+			errs = append(errs, immutable(p.Child("location"), o.Location, a.Location)...)
+			return append(errs, immutable(p.Child("kind"), string(o.Kind), string(a.Kind))...)
+		},
+	}
+}
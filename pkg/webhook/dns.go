@@ -0,0 +1,62 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	"github.com/crossplane-contrib/provider-azure/apis/dns/v1alpha1"
+	azure "github.com/crossplane-contrib/provider-azure/pkg/clients"
+	"github.com/crossplane-contrib/provider-azure/pkg/clients/dns"
+)
+
+// +kubebuilder:webhook:verbs=create;update,path=/validate-dns-azure-crossplane-io-v1alpha1-zone,mutating=false,failurePolicy=fail,groups=dns.azure.crossplane.io,resources=zones,versions=v1alpha1,name=zones.dns.azure.crossplane.io,sideEffects=None,admissionReviewVersions=v1
+
+func zone() *validator {
+	return &validator{
+		object: &v1alpha1.Zone{},
+		kind:   v1alpha1.ZoneGroupVersionKind.GroupKind(),
+		validateUpdate: func(old, mg resource.Managed) field.ErrorList {
+			o, z := old.(*v1alpha1.Zone).Spec.ForProvider, mg.(*v1alpha1.Zone).Spec.ForProvider
+			p := field.NewPath("spec", "forProvider")
+			errs := immutable(p.Child("resourceGroupName"), o.ResourceGroupName, z.ResourceGroupName)
+			errs = append(errs, immutable(p.Child("location"), o.Location, z.Location)...)
+			return append(errs, immutable(p.Child("zoneType"), azure.ToString(o.ZoneType), azure.ToString(z.ZoneType))...)
+		},
+	}
+}
+
+// +kubebuilder:webhook:verbs=create;update,path=/validate-dns-azure-crossplane-io-v1alpha1-recordset,mutating=false,failurePolicy=fail,groups=dns.azure.crossplane.io,resources=recordsets,versions=v1alpha1,name=recordsets.dns.azure.crossplane.io,sideEffects=None,admissionReviewVersions=v1
+
+func recordSet() *validator {
+	return &validator{
+		object: &v1alpha1.RecordSet{},
+		kind:   v1alpha1.RecordSetGroupVersionKind.GroupKind(),
+		validate: func(mg resource.Managed) field.ErrorList {
+			return dns.ValidateRecordSetParameters(&mg.(*v1alpha1.RecordSet).Spec.ForProvider, field.NewPath("spec", "forProvider"))
+		},
+		validateUpdate: func(old, mg resource.Managed) field.ErrorList {
+			o, r := old.(*v1alpha1.RecordSet).Spec.ForProvider, mg.(*v1alpha1.RecordSet).Spec.ForProvider
+			p := field.NewPath("spec", "forProvider")
+			errs := immutable(p.Child("resourceGroupName"), o.ResourceGroupName, r.ResourceGroupName)
+			errs = append(errs, immutable(p.Child("zoneName"), o.ZoneName, r.ZoneName)...)
+			return append(errs, immutable(p.Child("recordType"), string(o.RecordType), string(r.RecordType))...)
+		},
+	}
+}
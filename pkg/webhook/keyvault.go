@@ -0,0 +1,40 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	"github.com/crossplane-contrib/provider-azure/apis/keyvault/v1alpha1"
+)
+
+// +kubebuilder:webhook:verbs=create;update,path=/validate-keyvault-azure-crossplane-io-v1alpha1-keyvaultsecret,mutating=false,failurePolicy=fail,groups=keyvault.azure.crossplane.io,resources=keyvaultsecrets,versions=v1alpha1,name=keyvaultsecrets.keyvault.azure.crossplane.io,sideEffects=None,admissionReviewVersions=v1
+
+func keyVaultSecret() *validator {
+	return &validator{
+		object: &v1alpha1.KeyVaultSecret{},
+		kind:   v1alpha1.KeyVaultSecretGroupVersionKind.GroupKind(),
+		validateUpdate: func(old, mg resource.Managed) field.ErrorList {
+			o, s := old.(*v1alpha1.KeyVaultSecret).Spec.ForProvider, mg.(*v1alpha1.KeyVaultSecret).Spec.ForProvider
+			p := field.NewPath("spec", "forProvider")
+			errs := immutable(p.Child("vaultBaseUrl"), o.VaultBaseURL, s.VaultBaseURL)
+			return append(errs, immutable(p.Child("name"), o.Name, s.Name)...)
+		},
+	}
+}
@@ -0,0 +1,72 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	"github.com/crossplane-contrib/provider-azure/apis/network/v1alpha3"
+)
+
+// +kubebuilder:webhook:verbs=create;update,path=/validate-network-azure-crossplane-io-v1alpha3-publicipaddress,mutating=false,failurePolicy=fail,groups=network.azure.crossplane.io,resources=publicipaddresses,versions=v1alpha3,name=publicipaddresses.network.azure.crossplane.io,sideEffects=None,admissionReviewVersions=v1
+
+func publicIPAddress() *validator {
+	return &validator{
+		object: &v1alpha3.PublicIPAddress{},
+		kind:   v1alpha3.PublicIPAddressGroupVersionKind.GroupKind(),
+		validateUpdate: func(old, mg resource.Managed) field.ErrorList {
+			o, a := old.(*v1alpha3.PublicIPAddress).Spec.ForProvider, mg.(*v1alpha3.PublicIPAddress).Spec.ForProvider
+			p := field.NewPath("spec", "forProvider")
+			errs := immutable(p.Child("resourceGroupName"), o.ResourceGroupName, a.ResourceGroupName)
+			errs = append(errs, immutable(p.Child("location"), o.Location, a.Location)...)
+			errs = append(errs, immutable(p.Child("allocationMethod"), o.PublicIPAllocationMethod, a.PublicIPAllocationMethod)...)
+			return append(errs, immutable(p.Child("version"), o.PublicIPAddressVersion, a.PublicIPAddressVersion)...)
+		},
+	}
+}
+
+// +kubebuilder:webhook:verbs=create;update,path=/validate-network-azure-crossplane-io-v1alpha3-virtualnetwork,mutating=false,failurePolicy=fail,groups=network.azure.crossplane.io,resources=virtualnetworks,versions=v1alpha3,name=virtualnetworks.network.azure.crossplane.io,sideEffects=None,admissionReviewVersions=v1
+
+func virtualNetwork() *validator {
+	return &validator{
+		object: &v1alpha3.VirtualNetwork{},
+		kind:   v1alpha3.VirtualNetworkGroupVersionKind.GroupKind(),
+		validateUpdate: func(old, mg resource.Managed) field.ErrorList {
+			o, v := old.(*v1alpha3.VirtualNetwork).Spec, mg.(*v1alpha3.VirtualNetwork).Spec
+			p := field.NewPath("spec")
+			errs := immutable(p.Child("resourceGroupName"), o.ResourceGroupName, v.ResourceGroupName)
+			return append(errs, immutable(p.Child("location"), o.Location, v.Location)...)
+		},
+	}
+}
+
+// +kubebuilder:webhook:verbs=create;update,path=/validate-network-azure-crossplane-io-v1alpha3-subnet,mutating=false,failurePolicy=fail,groups=network.azure.crossplane.io,resources=subnets,versions=v1alpha3,name=subnets.network.azure.crossplane.io,sideEffects=None,admissionReviewVersions=v1
+
+func subnet() *validator {
+	return &validator{
+		object: &v1alpha3.Subnet{},
+		kind:   v1alpha3.SubnetGroupVersionKind.GroupKind(),
+		validateUpdate: func(old, mg resource.Managed) field.ErrorList {
+			o, s := old.(*v1alpha3.Subnet).Spec, mg.(*v1alpha3.Subnet).Spec
+			p := field.NewPath("spec")
+			errs := immutable(p.Child("resourceGroupName"), o.ResourceGroupName, s.ResourceGroupName)
+			return append(errs, immutable(p.Child("virtualNetworkName"), o.VirtualNetworkName, s.VirtualNetworkName)...)
+		},
+	}
+}
@@ -0,0 +1,37 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	"github.com/crossplane-contrib/provider-azure/apis/v1alpha3"
+)
+
+// +kubebuilder:webhook:verbs=create;update,path=/validate-azure-crossplane-io-v1alpha3-resourcegroup,mutating=false,failurePolicy=fail,groups=azure.crossplane.io,resources=resourcegroups,versions=v1alpha3,name=resourcegroups.azure.crossplane.io,sideEffects=None,admissionReviewVersions=v1
+
+func resourceGroup() *validator {
+	return &validator{
+		object: &v1alpha3.ResourceGroup{},
+		kind:   v1alpha3.ResourceGroupGroupVersionKind.GroupKind(),
+		validateUpdate: func(old, mg resource.Managed) field.ErrorList {
+			return immutable(field.NewPath("spec", "location"), old.(*v1alpha3.ResourceGroup).Spec.Location, mg.(*v1alpha3.ResourceGroup).Spec.Location)
+		},
+	}
+}
@@ -0,0 +1,79 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	"github.com/crossplane-contrib/provider-azure/apis/storage/v1alpha1"
+	"github.com/crossplane-contrib/provider-azure/apis/storage/v1beta1"
+	"github.com/crossplane-contrib/provider-azure/pkg/clients/storage"
+)
+
+// +kubebuilder:webhook:verbs=create;update,path=/validate-storage-azure-crossplane-io-v1beta1-account,mutating=false,failurePolicy=fail,groups=storage.azure.crossplane.io,resources=accounts,versions=v1beta1,name=accounts.storage.azure.crossplane.io,sideEffects=None,admissionReviewVersions=v1
+
+func account() *validator {
+	return &validator{
+		object: &v1beta1.Account{},
+		kind:   v1beta1.AccountGroupVersionKind.GroupKind(),
+		validate: func(mg resource.Managed) field.ErrorList {
+			return storage.ValidateAccountName(externalName(mg))
+		},
+		validateUpdate: func(old, mg resource.Managed) field.ErrorList {
+			o, a := old.(*v1beta1.Account).Spec.ForProvider, mg.(*v1beta1.Account).Spec.ForProvider
+			p := field.NewPath("spec", "forProvider")
+			errs := immutable(p.Child("resourceGroupName"), o.ResourceGroupName, a.ResourceGroupName)
+			return append(errs, immutable(p.Child("location"), o.Location, a.Location)...)
+		},
+	}
+}
+
+// +kubebuilder:webhook:verbs=create;update,path=/validate-storage-azure-crossplane-io-v1beta1-container,mutating=false,failurePolicy=fail,groups=storage.azure.crossplane.io,resources=containers,versions=v1beta1,name=containers.storage.azure.crossplane.io,sideEffects=None,admissionReviewVersions=v1
+
+func container() *validator {
+	return &validator{
+		object: &v1beta1.Container{},
+		kind:   v1beta1.ContainerGroupVersionKind.GroupKind(),
+		validate: func(mg resource.Managed) field.ErrorList {
+			errs := storage.ValidateContainerName(externalName(mg))
+			return append(errs, storage.ValidateLegalHold(mg.(*v1beta1.Container).Spec.ForProvider.LegalHold, field.NewPath("spec", "forProvider", "legalHold"))...)
+		},
+		validateUpdate: func(old, mg resource.Managed) field.ErrorList {
+			o, c := old.(*v1beta1.Container).Spec.ForProvider, mg.(*v1beta1.Container).Spec.ForProvider
+			p := field.NewPath("spec", "forProvider")
+			errs := immutable(p.Child("resourceGroupName"), o.ResourceGroupName, c.ResourceGroupName)
+			return append(errs, immutable(p.Child("accountName"), o.AccountName, c.AccountName)...)
+		},
+	}
+}
+
+// +kubebuilder:webhook:verbs=create;update,path=/validate-storage-azure-crossplane-io-v1alpha1-managementpolicy,mutating=false,failurePolicy=fail,groups=storage.azure.crossplane.io,resources=managementpolicies,versions=v1alpha1,name=managementpolicies.storage.azure.crossplane.io,sideEffects=None,admissionReviewVersions=v1
+
+func managementPolicy() *validator {
+	return &validator{
+		object: &v1alpha1.ManagementPolicy{},
+		kind:   v1alpha1.ManagementPolicyGroupVersionKind.GroupKind(),
+		validateUpdate: func(old, mg resource.Managed) field.ErrorList {
+			o, m := old.(*v1alpha1.ManagementPolicy).Spec.ForProvider, mg.(*v1alpha1.ManagementPolicy).Spec.ForProvider
+			p := field.NewPath("spec", "forProvider")
+			errs := immutable(p.Child("resourceGroupName"), o.ResourceGroupName, m.ResourceGroupName)
+			return append(errs, immutable(p.Child("accountName"), o.AccountName, m.AccountName)...)
+		},
+	}
+}
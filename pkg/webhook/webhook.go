@@ -0,0 +1,181 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package webhook contains the validating admission webhooks of Azure managed
+// resources. They reject mistakes that Azure would otherwise only report once
+// the managed resource is reconciled: changes to fields that identify the
+// external resource, such as its resource group, parent or location, and
+// fields that are inconsistent with each other. Managed resources that are
+// served at more than one version are converted between versions by the
+// conversion webhook that is set up along with their validating webhook.
+package webhook
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	apivalidation "k8s.io/apimachinery/pkg/api/validation"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+)
+
+const (
+	errNotManaged = "object is not a managed resource"
+)
+
+// Setup the validating webhooks of all Azure managed resources, and the
+// conversion webhook of those that implement conversion.Hub.
+func Setup(mgr ctrl.Manager) error {
+	for _, v := range []*validator{
+		redis(),
+		aksCluster(),
+		mySQLServer(),
+		mySQLServerConfiguration(),
+		mySQLServerFirewallRule(),
+		mySQLServerVirtualNetworkRule(),
+		postgreSQLServer(),
+		postgreSQLServerConfiguration(),
+		postgreSQLServerFirewallRule(),
+		postgreSQLServerVirtualNetworkRule(),
+		cosmosDBAccount(),
+		zone(),
+		recordSet(),
+		keyVaultSecret(),
+		publicIPAddress(),
+		virtualNetwork(),
+		subnet(),
+		resourceGroup(),
+		account(),
+		container(),
+		managementPolicy(),
+	} {
+		if err := ctrl.NewWebhookManagedBy(mgr).For(v.object).WithValidator(v).Complete(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// A validator validates the managed resources of one kind.
+type validator struct {
+	object resource.Managed
+	kind   schema.GroupKind
+
+	// validate returns the errors of a managed resource that is created or
+	// updated.
+	validate func(mg resource.Managed) field.ErrorList
+
+	// validateUpdate returns the errors of an update to a managed resource,
+	// such as changes to immutable fields.
+	validateUpdate func(old, mg resource.Managed) field.ErrorList
+}
+
+// ValidateCreate validates a managed resource that is created.
+func (v *validator) ValidateCreate(_ context.Context, obj runtime.Object) error {
+	mg, ok := obj.(resource.Managed)
+	if !ok {
+		return errors.New(errNotManaged)
+	}
+	if v.validate == nil {
+		return nil
+	}
+	return v.invalid(mg, v.validate(mg))
+}
+
+// ValidateUpdate validates an update to a managed resource. Errors that the
+// managed resource already had before the update are not reported, so that
+// managed resources created before a validation rule was introduced can still
+// be updated. Managed resources that are being deleted are not validated, so
+// that their finalizers can be removed.
+func (v *validator) ValidateUpdate(_ context.Context, oldObj, newObj runtime.Object) error {
+	old, ok := oldObj.(resource.Managed)
+	if !ok {
+		return errors.New(errNotManaged)
+	}
+	mg, ok := newObj.(resource.Managed)
+	if !ok {
+		return errors.New(errNotManaged)
+	}
+	if meta.WasDeleted(mg) {
+		return nil
+	}
+
+	var errs field.ErrorList
+	if v.validate != nil {
+		errs = newErrors(v.validate(old), v.validate(mg))
+	}
+	if v.validateUpdate != nil {
+		errs = append(errs, v.validateUpdate(old, mg)...)
+	}
+	return v.invalid(mg, errs)
+}
+
+// ValidateDelete does not validate managed resources that are deleted.
+func (v *validator) ValidateDelete(_ context.Context, _ runtime.Object) error {
+	return nil
+}
+
+func (v *validator) invalid(mg resource.Managed, errs field.ErrorList) error {
+	if len(errs) == 0 {
+		return nil
+	}
+	return kerrors.NewInvalid(v.kind, mg.GetName(), errs)
+}
+
+// newErrors returns the errors that are not among the supplied existing
+// errors.
+func newErrors(existing, errs field.ErrorList) field.ErrorList {
+	var n field.ErrorList
+	for _, err := range errs {
+		found := false
+		for _, e := range existing {
+			if e.Type == err.Type && e.Field == err.Field {
+				found = true
+				break
+			}
+		}
+		if !found {
+			n = append(n, err)
+		}
+	}
+	return n
+}
+
+// immutable returns an error if the supplied field has changed since it was
+// set. Fields that may be resolved from a reference are empty until they are
+// resolved, so they may be set once after the managed resource is created.
+func immutable(path *field.Path, old, new string) field.ErrorList {
+	if old == "" || old == new {
+		return nil
+	}
+	return field.ErrorList{field.Invalid(path, new, apivalidation.FieldImmutableErrorMsg)}
+}
+
+// externalName returns the name of the external resource of the supplied
+// managed resource, and the path of the field it is read from. It is the
+// managed resource's name until its external name annotation is set.
+func externalName(mg resource.Managed) (string, *field.Path) {
+	if n := meta.GetExternalName(mg); n != "" {
+		return n, field.NewPath("metadata", "annotations").Key(meta.AnnotationKeyExternalName)
+	}
+	return mg.GetName(), field.NewPath("metadata", "name")
+}
@@ -0,0 +1,224 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	apivalidation "k8s.io/apimachinery/pkg/api/validation"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane-contrib/provider-azure/apis/database/v1beta1"
+	storagev1alpha1 "github.com/crossplane-contrib/provider-azure/apis/storage/v1alpha1"
+	storagev1beta1 "github.com/crossplane-contrib/provider-azure/apis/storage/v1beta1"
+)
+
+type mySQLServerModifier func(*v1beta1.MySQLServer)
+
+func withCreateMode(m v1beta1.CreateMode) mySQLServerModifier {
+	return func(s *v1beta1.MySQLServer) { s.Spec.ForProvider.CreateMode = &m }
+}
+
+func withLocation(l string) mySQLServerModifier {
+	return func(s *v1beta1.MySQLServer) { s.Spec.ForProvider.Location = l }
+}
+
+func withResourceGroupName(n string) mySQLServerModifier {
+	return func(s *v1beta1.MySQLServer) { s.Spec.ForProvider.ResourceGroupName = n }
+}
+
+func withDeletionTimestamp() mySQLServerModifier {
+	return func(s *v1beta1.MySQLServer) {
+		now := metav1.Now()
+		s.SetDeletionTimestamp(&now)
+	}
+}
+
+func mySQL(m ...mySQLServerModifier) *v1beta1.MySQLServer {
+	s := &v1beta1.MySQLServer{
+		ObjectMeta: metav1.ObjectMeta{Name: "cool-server"},
+		Spec: v1beta1.SQLServerSpec{
+			ForProvider: v1beta1.SQLServerParameters{
+				ResourceGroupName: "cool-rg",
+				Location:          "westus",
+			},
+		},
+	}
+	for _, f := range m {
+		f(s)
+	}
+	return s
+}
+
+func TestValidateCreate(t *testing.T) {
+	forProvider := field.NewPath("spec", "forProvider")
+	mySQLKind := v1beta1.MySQLServerGroupVersionKind.GroupKind()
+
+	cases := map[string]struct {
+		reason string
+		v      *validator
+		mg     resource.Managed
+		want   error
+	}{
+		"Valid": {
+			reason: "A consistent managed resource should be admitted",
+			v:      mySQLServer(),
+			mg:     mySQL(),
+		},
+		"Inconsistent": {
+			reason: "A replica without a source server should be rejected",
+			v:      mySQLServer(),
+			mg:     mySQL(withCreateMode(v1beta1.CreateModeReplica)),
+			want: kerrors.NewInvalid(mySQLKind, "cool-server", field.ErrorList{
+				field.Required(forProvider.Child("sourceServerID"), "must be set when createMode is Replica"),
+			}),
+		},
+		"InvalidName": {
+			reason: "A storage account whose name Azure would reject should be rejected",
+			v:      account(),
+			mg:     &storagev1beta1.Account{ObjectMeta: metav1.ObjectMeta{Name: "cool-account"}},
+			want: kerrors.NewInvalid(storagev1beta1.AccountGroupVersionKind.GroupKind(), "cool-account", field.ErrorList{
+				field.Invalid(field.NewPath("metadata", "name"), "cool-account", "must be between 3 and 24 characters long and contain only lowercase letters and numbers"),
+			}),
+		},
+		"ValidExternalName": {
+			reason: "The external name of a storage account should be validated rather than its name",
+			v:      account(),
+			mg: func() resource.Managed {
+				a := &storagev1beta1.Account{ObjectMeta: metav1.ObjectMeta{Name: "cool-account"}}
+				meta.SetExternalName(a, "coolaccount")
+				return a
+			}(),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := tc.v.ValidateCreate(context.Background(), tc.mg)
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nValidateCreate(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestValidateUpdate(t *testing.T) {
+	forProvider := field.NewPath("spec", "forProvider")
+	mySQLKind := v1beta1.MySQLServerGroupVersionKind.GroupKind()
+
+	cases := map[string]struct {
+		reason string
+		v      *validator
+		old    resource.Managed
+		mg     resource.Managed
+		want   error
+	}{
+		"Unchanged": {
+			reason: "An update that doesn't change immutable fields should be admitted",
+			v:      mySQLServer(),
+			old:    mySQL(),
+			mg:     mySQL(),
+		},
+		"ImmutableFieldChanged": {
+			reason: "An update that changes the location should be rejected",
+			v:      mySQLServer(),
+			old:    mySQL(),
+			mg:     mySQL(withLocation("eastus")),
+			want: kerrors.NewInvalid(mySQLKind, "cool-server", field.ErrorList{
+				field.Invalid(forProvider.Child("location"), "eastus", apivalidation.FieldImmutableErrorMsg),
+			}),
+		},
+		"ImmutableFieldResolved": {
+			reason: "An update that sets a resource group name resolved from a reference should be admitted",
+			v:      mySQLServer(),
+			old:    mySQL(withResourceGroupName("")),
+			mg:     mySQL(),
+		},
+		"NewError": {
+			reason: "An update that makes a managed resource inconsistent should be rejected",
+			v:      mySQLServer(),
+			old:    mySQL(),
+			mg:     mySQL(withCreateMode(v1beta1.CreateModeReplica)),
+			want: kerrors.NewInvalid(mySQLKind, "cool-server", field.ErrorList{
+				field.Required(forProvider.Child("sourceServerID"), "must be set when createMode is Replica"),
+			}),
+		},
+		"ExistingError": {
+			reason: "An update to a managed resource that was already inconsistent should be admitted",
+			v:      mySQLServer(),
+			old:    mySQL(withCreateMode(v1beta1.CreateModeReplica)),
+			mg:     mySQL(withCreateMode(v1beta1.CreateModeReplica)),
+		},
+		"Deleted": {
+			reason: "An update to a managed resource that is being deleted should be admitted",
+			v:      mySQLServer(),
+			old:    mySQL(),
+			mg:     mySQL(withLocation("eastus"), withDeletionTimestamp()),
+		},
+		"ContainerMoved": {
+			reason: "An update that moves a container to another storage account should be rejected",
+			v:      container(),
+			old: func() resource.Managed {
+				c := &storagev1beta1.Container{ObjectMeta: metav1.ObjectMeta{Name: "cool-container"}}
+				c.Spec.ForProvider.AccountName = "coolaccount"
+				return c
+			}(),
+			mg: func() resource.Managed {
+				c := &storagev1beta1.Container{ObjectMeta: metav1.ObjectMeta{Name: "cool-container"}}
+				c.Spec.ForProvider.AccountName = "otheraccount"
+				return c
+			}(),
+			want: kerrors.NewInvalid(storagev1beta1.ContainerGroupVersionKind.GroupKind(), "cool-container", field.ErrorList{
+				field.Invalid(field.NewPath("spec", "forProvider", "accountName"), "otheraccount", apivalidation.FieldImmutableErrorMsg),
+			}),
+		},
+		"ManagementPolicyMoved": {
+			reason: "An update that moves a management policy to another resource group should be rejected",
+			v:      managementPolicy(),
+			old: func() resource.Managed {
+				mp := &storagev1alpha1.ManagementPolicy{ObjectMeta: metav1.ObjectMeta{Name: "cool-policy"}}
+				mp.Spec.ForProvider.ResourceGroupName = "cool-rg"
+				return mp
+			}(),
+			mg: func() resource.Managed {
+				mp := &storagev1alpha1.ManagementPolicy{ObjectMeta: metav1.ObjectMeta{Name: "cool-policy"}}
+				mp.Spec.ForProvider.ResourceGroupName = "other-rg"
+				return mp
+			}(),
+			want: kerrors.NewInvalid(storagev1alpha1.ManagementPolicyGroupVersionKind.GroupKind(), "cool-policy", field.ErrorList{
+				field.Invalid(field.NewPath("spec", "forProvider", "resourceGroupName"), "other-rg", apivalidation.FieldImmutableErrorMsg),
+			}),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := tc.v.ValidateUpdate(context.Background(), tc.old, tc.mg)
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nValidateUpdate(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}